@@ -0,0 +1,315 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/command"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+)
+
+// EnvCompLine is the environmental variable that bash sets to the
+// command line being completed when it runs Terraform as a completion
+// command, and EnvCompPoint is the cursor position within that line.
+const (
+	EnvCompLine  = "COMP_LINE"
+	EnvCompPoint = "COMP_POINT"
+)
+
+// globalFlags are the flags that are valid before a command.
+var globalFlags = []string{
+	"-autocomplete-install",
+	"-autocomplete-uninstall",
+	"-chdir=",
+	"-no-color",
+	"-version",
+}
+
+// addressCommands are the commands, along with their subcommand if
+// they have one, whose arguments are resource addresses from the state.
+var addressCommands = map[string]struct{}{
+	"state mv": struct{}{},
+}
+
+var (
+	helpFlagRe       = regexp.MustCompile(`(?m)^  (-[a-zA-Z0-9-]+)(=?)`)
+	helpSubcommandRe = regexp.MustCompile(`(?m)^  ([a-z][a-z0-9-]*)  `)
+)
+
+// autocompleteMain prints the completions for the command line in the
+// environment, one per line, in the format bash expects from a
+// completion command.
+func autocompleteMain() int {
+	line := os.Getenv(EnvCompLine)
+	if p, err := strconv.Atoi(os.Getenv(EnvCompPoint)); err == nil {
+		if p >= 0 && p < len(line) {
+			line = line[:p]
+		}
+	}
+
+	for _, c := range autocomplete(line, Commands) {
+		fmt.Println(c)
+	}
+
+	return 0
+}
+
+// autocomplete returns the completions for the last word of the given
+// command line. The line should end at the cursor.
+func autocomplete(line string, commands map[string]cli.CommandFactory) []string {
+	words := strings.Fields(line)
+	if len(words) == 0 {
+		return nil
+	}
+	if strings.HasSuffix(line, " ") {
+		words = append(words, "")
+	}
+
+	// Drop the program name and any global flags to find the command
+	var dir string
+	args := words[1:]
+	for len(args) > 1 && strings.HasPrefix(args[0], "-") {
+		if strings.HasPrefix(args[0], "-chdir=") {
+			dir = strings.TrimPrefix(args[0], "-chdir=")
+		}
+
+		args = args[1:]
+	}
+
+	cur := args[len(args)-1]
+	if len(args) == 1 {
+		if strings.HasPrefix(cur, "-") {
+			return filterPrefix(globalFlags, cur)
+		}
+
+		names := make([]string, 0, len(commands))
+		for k, _ := range commands {
+			names = append(names, k)
+		}
+
+		return filterPrefix(names, cur)
+	}
+
+	name := args[0]
+	f, ok := commands[name]
+	if !ok {
+		return nil
+	}
+	cmd, err := f()
+	if err != nil {
+		return nil
+	}
+	args = args[1:]
+
+	if strings.HasPrefix(cur, "-") {
+		var flags []string
+		for _, m := range helpFlagRe.FindAllStringSubmatch(cmd.Help(), -1) {
+			flags = append(flags, m[1]+m[2])
+		}
+
+		return filterPrefix(flags, cur)
+	}
+
+	full := name
+	if len(args) > 1 {
+		full = name + " " + args[0]
+	}
+	if _, ok := addressCommands[full]; ok {
+		return filterPrefix(stateAddresses(dir, args), cur)
+	}
+
+	// The first argument of a command with subcommands is the name
+	// of the subcommand.
+	if len(args) == 1 {
+		help := cmd.Help()
+		if idx := strings.Index(help, "\nSubcommands:\n"); idx >= 0 {
+			var subs []string
+			for _, m := range helpSubcommandRe.FindAllStringSubmatch(help[idx:], -1) {
+				subs = append(subs, m[1])
+			}
+
+			return filterPrefix(subs, cur)
+		}
+	}
+
+	return nil
+}
+
+// stateAddresses returns the addresses of the resources in the state
+// file that the given command arguments refer to. Relative paths are
+// relative to dir.
+func stateAddresses(dir string, args []string) []string {
+	path := command.DefaultStateFilename
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-state=") {
+			path = strings.TrimPrefix(arg, "-state=")
+		}
+	}
+	if dir != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	state, err := terraform.ReadState(f)
+	if err != nil {
+		return nil
+	}
+
+	result := make([]string, 0, len(state.Resources))
+	for k, _ := range state.Resources {
+		result = append(result, k)
+	}
+
+	return result
+}
+
+// filterPrefix returns the sorted values that start with prefix.
+func filterPrefix(vs []string, prefix string) []string {
+	var result []string
+	for _, v := range vs {
+		if strings.HasPrefix(v, prefix) {
+			result = append(result, v)
+		}
+	}
+
+	sort.Strings(result)
+	return result
+}
+
+// autocompleteInstall adds completion for Terraform to the rc files
+// of the shells the user has, or removes it if uninstall is true.
+func autocompleteInstall(uninstall bool) error {
+	home, err := configDir()
+	if err != nil {
+		return err
+	}
+
+	exePath, err := executablePath()
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, sh := range autocompleteShells(exePath) {
+		path := filepath.Join(home, sh.Rc)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		found = true
+
+		if uninstall {
+			err = rcRemove(path, sh.Lines)
+		} else {
+			err = rcAppend(path, sh.Lines)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("No .bashrc or .zshrc found in %s", home)
+	}
+
+	return nil
+}
+
+type autocompleteShell struct {
+	Rc    string
+	Lines []string
+}
+
+func autocompleteShells(exePath string) []autocompleteShell {
+	complete := fmt.Sprintf(
+		"complete -o nospace -C %s terraform", shellQuote(exePath))
+	return []autocompleteShell{
+		autocompleteShell{
+			Rc:    ".bashrc",
+			Lines: []string{complete},
+		},
+		autocompleteShell{
+			Rc: ".zshrc",
+			Lines: []string{
+				"autoload -U +X bashcompinit && bashcompinit",
+				complete,
+			},
+		},
+	}
+}
+
+// shellQuote quotes s as a single word for bash and zsh, so that a path
+// with spaces or other special characters is used as is.
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// rcAppend appends the lines that aren't already in the file at path.
+func rcAppend(path string, lines []string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[string]struct{})
+	for _, l := range strings.Split(string(data), "\n") {
+		existing[l] = struct{}{}
+	}
+
+	var buf bytes.Buffer
+	buf.Write(data)
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		buf.WriteString("\n")
+	}
+	for _, l := range lines {
+		if _, ok := existing[l]; !ok {
+			buf.WriteString(l + "\n")
+		}
+	}
+
+	return ioutil.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// rcRemove removes the given lines from the file at path. The last line
+// is the one that enables completion. The lines before it are setup that
+// the user may have for other reasons, so they're only removed if they
+// directly precede it.
+func rcRemove(path string, lines []string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	last := lines[len(lines)-1]
+	setup := make(map[string]struct{})
+	for _, l := range lines[:len(lines)-1] {
+		setup[l] = struct{}{}
+	}
+
+	src := strings.Split(string(data), "\n")
+	result := make([]string, 0, len(src))
+	for i, l := range src {
+		if l == last {
+			continue
+		}
+		if _, ok := setup[l]; ok && i+1 < len(src) && src[i+1] == last {
+			continue
+		}
+
+		result = append(result, l)
+	}
+
+	return ioutil.WriteFile(path, []byte(strings.Join(result, "\n")), 0644)
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAutocomplete(t *testing.T) {
+	cases := []struct {
+		Line     string
+		Expected []string
+	}{
+		{"terraform pl", []string{"plan"}},
+		{"terraform -v", []string{"-version"}},
+		{"terraform -no-color ref", []string{"refresh"}},
+		{"terraform apply -state", []string{"-state-out=", "-state="}},
+		{"terraform apply -var", []string{"-var", "-var-file="}},
+		{"terraform state ", []string{"compact", "history", "migrate", "mv", "pull", "push"}},
+		{"terraform nope -", nil},
+		{"terraform version foo", nil},
+	}
+
+	for _, tc := range cases {
+		actual := autocomplete(tc.Line, Commands)
+		if !reflect.DeepEqual(actual, tc.Expected) {
+			t.Fatalf("bad: %q\n\n%#v", tc.Line, actual)
+		}
+	}
+}
+
+func TestAutocomplete_commands(t *testing.T) {
+	actual := autocomplete("terraform ", Commands)
+	if len(actual) != len(Commands) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestAutocomplete_stateMv(t *testing.T) {
+	td, err := ioutil.TempDir("", "tf")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(td)
+
+	statePath := filepath.Join(td, "state.tfstate")
+	f, err := os.Create(statePath)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	err = terraform.WriteState(&terraform.State{
+		Resources: map[string]*terraform.ResourceState{
+			"aws_instance.web":         &terraform.ResourceState{ID: "foo"},
+			"aws_instance.db":          &terraform.ResourceState{ID: "bar"},
+			"aws_security_group.allow": &terraform.ResourceState{ID: "baz"},
+		},
+	}, f)
+	f.Close()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	line := "terraform state mv -state=" + statePath + " aws_i"
+	actual := autocomplete(line, Commands)
+	expected := []string{"aws_instance.db", "aws_instance.web"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	line = "terraform state mv -state=" + statePath + " aws_instance.db aws_s"
+	actual = autocomplete(line, Commands)
+	expected = []string{"aws_security_group.allow"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	// Other commands don't take addresses
+	line = "terraform plan -state=" + statePath + " aws_i"
+	if actual := autocomplete(line, Commands); actual != nil {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestRcAppendRemove(t *testing.T) {
+	tf, err := ioutil.TempFile("", "tf")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	tf.WriteString("export FOO=bar")
+	tf.Close()
+	defer os.Remove(tf.Name())
+
+	lines := autocompleteShells("/bin/terraform")[1].Lines
+
+	// Appending twice should only add the lines once
+	for i := 0; i < 2; i++ {
+		if err := rcAppend(tf.Name(), lines); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	data, err := ioutil.ReadFile(tf.Name())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := "export FOO=bar\n" +
+		"autoload -U +X bashcompinit && bashcompinit\n" +
+		"complete -o nospace -C '/bin/terraform' terraform\n"
+	if string(data) != expected {
+		t.Fatalf("bad: %q", data)
+	}
+
+	if err := rcRemove(tf.Name(), lines); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	data, err = ioutil.ReadFile(tf.Name())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if string(data) != "export FOO=bar\n" {
+		t.Fatalf("bad: %q", data)
+	}
+}
+
+func TestAutocompleteShells_quote(t *testing.T) {
+	lines := autocompleteShells("/home/me/my bin/it's/terraform")[0].Lines
+	expected := `complete -o nospace -C '/home/me/my bin/it'\''s/terraform' terraform`
+	if lines[0] != expected {
+		t.Fatalf("bad: %s", lines[0])
+	}
+}
@@ -6,5 +6,7 @@ import (
 )
 
 func main() {
-	plugin.Serve(new(aws.ResourceProvider))
+	plugin.ServeFactory(func() interface{} {
+		return new(aws.ResourceProvider)
+	})
 }
@@ -6,5 +6,7 @@ import (
 )
 
 func main() {
-	plugin.Serve(new(cloudflare.ResourceProvider))
+	plugin.ServeFactory(func() interface{} {
+		return new(cloudflare.ResourceProvider)
+	})
 }
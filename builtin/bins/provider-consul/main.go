@@ -6,5 +6,7 @@ import (
 )
 
 func main() {
-	plugin.Serve(new(consul.ResourceProvider))
+	plugin.ServeFactory(func() interface{} {
+		return new(consul.ResourceProvider)
+	})
 }
@@ -6,5 +6,7 @@ import (
 )
 
 func main() {
-	plugin.Serve(new(digitalocean.ResourceProvider))
+	plugin.ServeFactory(func() interface{} {
+		return new(digitalocean.ResourceProvider)
+	})
 }
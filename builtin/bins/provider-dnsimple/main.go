@@ -6,5 +6,7 @@ import (
 )
 
 func main() {
-	plugin.Serve(new(dnsimple.ResourceProvider))
+	plugin.ServeFactory(func() interface{} {
+		return new(dnsimple.ResourceProvider)
+	})
 }
@@ -6,5 +6,7 @@ import (
 )
 
 func main() {
-	plugin.Serve(google.Provider())
+	plugin.ServeFactory(func() interface{} {
+		return google.Provider()
+	})
 }
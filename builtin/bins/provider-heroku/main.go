@@ -6,5 +6,7 @@ import (
 )
 
 func main() {
-	plugin.Serve(heroku.Provider())
+	plugin.ServeFactory(func() interface{} {
+		return heroku.Provider()
+	})
 }
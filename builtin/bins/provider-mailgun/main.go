@@ -6,5 +6,7 @@ import (
 )
 
 func main() {
-	plugin.Serve(mailgun.Provider())
+	plugin.ServeFactory(func() interface{} {
+		return mailgun.Provider()
+	})
 }
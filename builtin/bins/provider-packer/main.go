@@ -0,0 +1,12 @@
+package main
+
+import (
+	"github.com/hashicorp/terraform/builtin/providers/packer"
+	"github.com/hashicorp/terraform/plugin"
+)
+
+func main() {
+	plugin.ServeFactory(func() interface{} {
+		return packer.Provider()
+	})
+}
@@ -6,5 +6,7 @@ import (
 )
 
 func main() {
-	plugin.Serve(new(file.ResourceProvisioner))
+	plugin.ServeFactory(func() interface{} {
+		return new(file.ResourceProvisioner)
+	})
 }
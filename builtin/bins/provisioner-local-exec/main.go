@@ -6,5 +6,7 @@ import (
 )
 
 func main() {
-	plugin.Serve(new(localexec.ResourceProvisioner))
+	plugin.ServeFactory(func() interface{} {
+		return new(localexec.ResourceProvisioner)
+	})
 }
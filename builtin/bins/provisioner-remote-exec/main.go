@@ -6,5 +6,7 @@ import (
 )
 
 func main() {
-	plugin.Serve(new(remoteexec.ResourceProvisioner))
+	plugin.ServeFactory(func() interface{} {
+		return new(remoteexec.ResourceProvisioner)
+	})
 }
@@ -2,7 +2,6 @@ package aws
 
 import (
 	"fmt"
-	"os"
 	"strings"
 	"unicode"
 
@@ -56,10 +55,6 @@ func (c *Config) AWSRegion() (aws.Region, error) {
 		}
 	}
 
-	if v := os.Getenv("AWS_REGION"); v != "" {
-		return aws.Regions[v], nil
-	}
-
 	md, err := aws.GetMetaData("placement/availability-zone")
 	if err != nil {
 		return aws.Region{}, err
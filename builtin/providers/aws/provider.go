@@ -16,9 +16,16 @@ func Provider() *schema.Provider {
 
 	return &schema.Provider{
 		ResourcesMap: map[string]*schema.Resource{
-			"aws_eip":            resourceAwsEip(),
-			"aws_instance":       resourceAwsInstance(),
-			"aws_security_group": resourceAwsSecurityGroup(),
+			"aws_db_parameter_group":  resourceAwsDbParameterGroup(),
+			"aws_db_subnet_group":     resourceAwsDbSubnetGroup(),
+			"aws_eip":                 resourceAwsEip(),
+			"aws_iam_policy":          resourceAwsIamPolicy(),
+			"aws_iam_role":            resourceAwsIamRole(),
+			"aws_iam_user":            resourceAwsIamUser(),
+			"aws_instance":            resourceAwsInstance(),
+			"aws_security_group":      resourceAwsSecurityGroup(),
+			"aws_ses_domain_dkim":     resourceAwsSesDomainDkim(),
+			"aws_ses_domain_identity": resourceAwsSesDomainIdentity(),
 		},
 	}
 }
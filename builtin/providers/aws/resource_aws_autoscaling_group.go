@@ -119,6 +119,31 @@ func resource_aws_autoscaling_group_update(
 		opts.SetMaxSize = true
 	}
 
+	if _, ok := d.Attributes["desired_capacity"]; ok {
+		opts.DesiredCapacity, err = strconv.Atoi(rs.Attributes["desired_capacity"])
+		opts.SetDesiredCapacity = true
+	}
+
+	if _, ok := d.Attributes["default_cooldown"]; ok {
+		opts.DefaultCooldown, err = strconv.Atoi(rs.Attributes["default_cooldown"])
+		opts.SetDefaultCooldown = true
+	}
+
+	if _, ok := d.Attributes["health_check_grace_period"]; ok {
+		opts.HealthCheckGracePeriod, err = strconv.Atoi(rs.Attributes["health_check_grace_period"])
+		opts.SetHealthCheckGracePeriod = true
+	}
+
+	if _, ok := d.Attributes["health_check_type"]; ok {
+		opts.HealthCheckType = rs.Attributes["health_check_type"]
+	}
+
+	// Switching the launch configuration only affects the instances
+	// launched from then on, so the group doesn't have to be replaced
+	if _, ok := d.Attributes["launch_configuration"]; ok {
+		opts.LaunchConfigurationName = rs.Attributes["launch_configuration"]
+	}
+
 	if err != nil {
 		return s, fmt.Errorf("Error parsing configuration: %s", err)
 	}
@@ -194,19 +219,19 @@ func resource_aws_autoscaling_group_diff(
 
 	b := &diff.ResourceBuilder{
 		Attrs: map[string]diff.AttrType{
-			"availability_zone":         diff.AttrTypeCreate,
-			"default_cooldown":          diff.AttrTypeCreate,
-			"desired_capacity":          diff.AttrTypeCreate,
-			"force_delete":              diff.AttrTypeCreate,
-			"health_check_grace_period": diff.AttrTypeCreate,
-			"health_check_type":         diff.AttrTypeCreate,
-			"launch_configuration":      diff.AttrTypeCreate,
-			"load_balancers":            diff.AttrTypeCreate,
-			"name":                      diff.AttrTypeCreate,
-			"vpc_zone_identifier":       diff.AttrTypeCreate,
-
-			"max_size": diff.AttrTypeUpdate,
-			"min_size": diff.AttrTypeUpdate,
+			"availability_zone":   diff.AttrTypeCreate,
+			"force_delete":        diff.AttrTypeCreate,
+			"load_balancers":      diff.AttrTypeCreate,
+			"name":                diff.AttrTypeCreate,
+			"vpc_zone_identifier": diff.AttrTypeCreate,
+
+			"default_cooldown":          diff.AttrTypeUpdate,
+			"desired_capacity":          diff.AttrTypeUpdate,
+			"health_check_grace_period": diff.AttrTypeUpdate,
+			"health_check_type":         diff.AttrTypeUpdate,
+			"launch_configuration":      diff.AttrTypeUpdate,
+			"max_size":                  diff.AttrTypeUpdate,
+			"min_size":                  diff.AttrTypeUpdate,
 		},
 
 		ComputedAttrs: []string{
@@ -95,6 +95,8 @@ func resource_aws_db_instance_create(
 	opts.MasterUserPassword = rs.Attributes["password"]
 	opts.EngineVersion = rs.Attributes["engine_version"]
 	opts.Engine = rs.Attributes["engine"]
+	opts.DBSubnetGroupName = rs.Attributes["db_subnet_group_name"]
+	opts.DBParameterGroupName = rs.Attributes["parameter_group_name"]
 
 	// Don't keep the password around in the state
 	delete(rs.Attributes, "password")
@@ -109,37 +111,70 @@ func resource_aws_db_instance_create(
 
 	log.Printf("[INFO] DB Instance ID: %s", rs.ID)
 
-	log.Println(
-		"[INFO] Waiting for DB Instance to be available")
+	// Creating a DB Instance often takes more than ten minutes, and
+	// Multi-AZ ones far longer, so by default we wait a long time.
+	return resource_aws_db_instance_wait(
+		rs, conn, p.StopCh(), 30*time.Second,
+		resource.Timeout(d, "create", 40*time.Minute))
+}
+
+func resource_aws_db_instance_update(
+	s *terraform.ResourceState,
+	d *terraform.ResourceDiff,
+	meta interface{}) (*terraform.ResourceState, error) {
+	p := meta.(*ResourceProvider)
+	conn := p.rdsconn
+
+	rs := s.MergeDiff(d)
+
+	// None of the attributes that can be updated change the DB Instance
+	// itself: the password isn't kept in the state so it always shows
+	// up here, and the snapshot ones are only used when destroying it.
+	delete(rs.Attributes, "password")
+
+	// The DB Instance may still be modifying from an earlier change or
+	// its maintenance window, so wait for it to settle before refreshing.
+	return resource_aws_db_instance_wait(
+		rs, conn, p.StopCh(), 0, resource.Timeout(d, "update", 20*time.Minute))
+}
+
+// resource_aws_db_instance_wait waits for the DB Instance to become
+// available, starting after the given delay, then updates the state
+// with it. The wait ends early when stopCh is closed.
+func resource_aws_db_instance_wait(
+	s *terraform.ResourceState,
+	conn *rds.Rds,
+	stopCh <-chan struct{},
+	delay time.Duration,
+	timeout time.Duration) (*terraform.ResourceState, error) {
+	log.Printf(
+		"[INFO] Waiting up to %s for DB Instance to be available", timeout)
 
 	stateConf := &resource.StateChangeConf{
-		Pending:    []string{"creating", "backing-up", "modifying"},
+		Pending: []string{"creating", "backing-up", "modifying",
+			"rebooting", "resetting-master-credentials"},
 		Target:     "available",
-		Refresh:    DBInstanceStateRefreshFunc(rs.ID, conn),
-		Timeout:    10 * time.Minute,
+		Refresh:    DBInstanceStateRefreshFunc(s.ID, conn),
+		Timeout:    timeout,
 		MinTimeout: 10 * time.Second,
-		Delay:      30 * time.Second, // Wait 30 secs before starting
+		Delay:      delay,
+		StopCh:     stopCh,
 	}
 
 	// Wait, catching any errors
-	_, err = stateConf.WaitForState()
+	_, err := stateConf.WaitForState()
 	if err != nil {
-		return rs, err
+		return s, fmt.Errorf(
+			"Error waiting for DB Instance (%s) to be available: %s",
+			s.ID, err)
 	}
 
-	v, err := resource_aws_db_instance_retrieve(rs.ID, conn)
+	v, err := resource_aws_db_instance_retrieve(s.ID, conn)
 	if err != nil {
-		return rs, err
+		return s, err
 	}
 
-	return resource_aws_db_instance_update_state(rs, v)
-}
-
-func resource_aws_db_instance_update(
-	s *terraform.ResourceState,
-	d *terraform.ResourceDiff,
-	meta interface{}) (*terraform.ResourceState, error) {
-	panic("Cannot update DB")
+	return resource_aws_db_instance_update_state(s, v)
 }
 
 func resource_aws_db_instance_destroy(
@@ -159,23 +194,31 @@ func resource_aws_db_instance_destroy(
 	}
 
 	log.Printf("[DEBUG] DB Instance destroy configuration: %v", opts)
-	_, err := conn.DeleteDBInstance(&opts)
+	if _, err := conn.DeleteDBInstance(&opts); err != nil {
+		newerr, ok := err.(*rds.Error)
+		if !ok || newerr.Code != "DBInstanceNotFound" {
+			return fmt.Errorf("Error deleting DB Instance: %s", err)
+		}
+	}
 
 	log.Println(
 		"[INFO] Waiting for DB Instance to be destroyed")
 
+	// Taking the final snapshot can take as long as creating the
+	// DB Instance did.
 	stateConf := &resource.StateChangeConf{
 		Pending: []string{"creating", "backing-up",
 			"modifying", "deleting", "available"},
 		Target:     "",
 		Refresh:    DBInstanceStateRefreshFunc(s.ID, conn),
-		Timeout:    10 * time.Minute,
+		Timeout:    40 * time.Minute,
 		MinTimeout: 10 * time.Second,
 		Delay:      30 * time.Second, // Wait 30 secs before starting
+		StopCh:     p.StopCh(),
 	}
 
 	// Wait, catching any errors
-	_, err = stateConf.WaitForState()
+	_, err := stateConf.WaitForState()
 	if err != nil {
 		return err
 	}
@@ -209,6 +252,7 @@ func resource_aws_db_instance_diff(
 			"availability_zone":         diff.AttrTypeCreate,
 			"backup_retention_period":   diff.AttrTypeCreate,
 			"backup_window":             diff.AttrTypeCreate,
+			"db_subnet_group_name":      diff.AttrTypeCreate,
 			"engine":                    diff.AttrTypeCreate,
 			"engine_version":            diff.AttrTypeCreate,
 			"identifier":                diff.AttrTypeCreate,
@@ -217,6 +261,7 @@ func resource_aws_db_instance_diff(
 			"maintenance_window":        diff.AttrTypeCreate,
 			"multi_az":                  diff.AttrTypeCreate,
 			"name":                      diff.AttrTypeCreate,
+			"parameter_group_name":      diff.AttrTypeCreate,
 			"password":                  diff.AttrTypeUpdate,
 			"port":                      diff.AttrTypeCreate,
 			"publicly_accessible":       diff.AttrTypeCreate,
@@ -330,9 +375,12 @@ func resource_aws_db_instance_validation() *config.Validator {
 			"availability_zone",
 			"backup_retention_period",
 			"backup_window",
+			"db_subnet_group_name",
+			"final_snapshot_identifier",
 			"iops",
 			"maintenance_window",
 			"multi_az",
+			"parameter_group_name",
 			"port",
 			"publicly_accessible",
 			"vpc_security_group_ids.*",
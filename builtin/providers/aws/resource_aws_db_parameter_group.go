@@ -0,0 +1,260 @@
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mitchellh/goamz/rds"
+)
+
+func resourceAwsDbParameterGroup() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsDbParameterGroupCreate,
+		Read:   resourceAwsDbParameterGroupRead,
+		Update: resourceAwsDbParameterGroupUpdate,
+		Delete: resourceAwsDbParameterGroupDelete,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"family": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"parameter": &schema.Schema{
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+
+						"value": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+
+						"apply_method": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+						},
+					},
+				},
+				Set: resourceAwsDbParameterHash,
+			},
+		},
+	}
+}
+
+func resourceAwsDbParameterHash(v interface{}) int {
+	var buf bytes.Buffer
+	m := v.(map[string]interface{})
+	buf.WriteString(fmt.Sprintf("%s-", m["name"].(string)))
+	buf.WriteString(fmt.Sprintf("%s-", m["value"].(string)))
+
+	return hashcode.String(buf.String())
+}
+
+func resourceAwsDbParameterGroupCreate(d *schema.ResourceData, meta interface{}) error {
+	p := meta.(*ResourceProvider)
+	rdsconn := p.rdsconn
+
+	opts := rds.CreateDBParameterGroup{
+		DBParameterGroupName:   d.Get("name").(string),
+		DBParameterGroupFamily: d.Get("family").(string),
+		Description:            d.Get("description").(string),
+	}
+
+	log.Printf("[DEBUG] DB Parameter Group create configuration: %#v", opts)
+	if _, err := rdsconn.CreateDBParameterGroup(&opts); err != nil {
+		return fmt.Errorf("Error creating DB Parameter Group: %s", err)
+	}
+
+	d.SetId(opts.DBParameterGroupName)
+	log.Printf("[INFO] DB Parameter Group ID: %s", d.Id())
+
+	return resourceAwsDbParameterGroupUpdate(d, meta)
+}
+
+func resourceAwsDbParameterGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+	p := meta.(*ResourceProvider)
+	rdsconn := p.rdsconn
+
+	if d.HasChange("parameter") {
+		o, n := d.GetChange("parameter")
+		if o == nil {
+			o = new(schema.Set)
+		}
+		if n == nil {
+			n = new(schema.Set)
+		}
+
+		os := o.(*schema.Set)
+		ns := n.(*schema.Set)
+
+		// Parameters that are no longer configured go back to the
+		// default of the family, unless they're only changing value.
+		modify := expandParameters(ns.Difference(os).List())
+		reset := expandParameters(os.Difference(ns).List())
+		modified := make(map[string]struct{}, len(modify))
+		for _, param := range modify {
+			modified[param.ParameterName] = struct{}{}
+		}
+		for i := 0; i < len(reset); i++ {
+			if _, ok := modified[reset[i].ParameterName]; ok {
+				reset = append(reset[:i], reset[i+1:]...)
+				i--
+			}
+		}
+
+		if len(reset) > 0 {
+			opts := rds.ResetDBParameterGroup{
+				DBParameterGroupName: d.Id(),
+				Parameters:           reset,
+			}
+
+			log.Printf("[DEBUG] DB Parameter Group reset: %#v", opts)
+			if _, err := rdsconn.ResetDBParameterGroup(&opts); err != nil {
+				return fmt.Errorf("Error resetting DB Parameter Group: %s", err)
+			}
+		}
+
+		// The API only takes 20 parameters at a time
+		for len(modify) > 0 {
+			var batch []rds.Parameter
+			if len(modify) > 20 {
+				batch, modify = modify[:20], modify[20:]
+			} else {
+				batch, modify = modify, nil
+			}
+
+			opts := rds.ModifyDBParameterGroup{
+				DBParameterGroupName: d.Id(),
+				Parameters:           batch,
+			}
+
+			log.Printf("[DEBUG] DB Parameter Group modify: %#v", opts)
+			if _, err := rdsconn.ModifyDBParameterGroup(&opts); err != nil {
+				return fmt.Errorf("Error modifying DB Parameter Group: %s", err)
+			}
+		}
+	}
+
+	return resourceAwsDbParameterGroupRead(d, meta)
+}
+
+func resourceAwsDbParameterGroupDelete(d *schema.ResourceData, meta interface{}) error {
+	p := meta.(*ResourceProvider)
+	rdsconn := p.rdsconn
+
+	// Like subnet groups, the group stays in use until every DB Instance
+	// that used it is entirely gone.
+	timeout := d.Timeout("delete")
+	if timeout == 0 {
+		timeout = 10 * time.Minute
+	}
+
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"pending"},
+		Target:     "destroyed",
+		Refresh:    resourceAwsDbParameterGroupDeleteRefreshFunc(rdsconn, d.Id()),
+		Timeout:    timeout,
+		MinTimeout: 5 * time.Second,
+		StopCh:     p.StopCh(),
+	}
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf(
+			"Error deleting DB Parameter Group (%s): %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+func resourceAwsDbParameterGroupRead(d *schema.ResourceData, meta interface{}) error {
+	p := meta.(*ResourceProvider)
+	rdsconn := p.rdsconn
+
+	describeOpts := rds.DescribeDBParameterGroups{
+		DBParameterGroupName: d.Id(),
+	}
+	describeResp, err := rdsconn.DescribeDBParameterGroups(&describeOpts)
+	if err != nil {
+		if rdserr, ok := err.(*rds.Error); ok &&
+			rdserr.Code == "DBParameterGroupNotFound" {
+			d.SetId("")
+			return nil
+		}
+
+		return fmt.Errorf("Error retrieving DB Parameter Group: %s", err)
+	}
+	if len(describeResp.DBParameterGroups) != 1 ||
+		describeResp.DBParameterGroups[0].DBParameterGroupName != d.Id() {
+		return fmt.Errorf(
+			"Unable to find DB Parameter Group: %#v",
+			describeResp.DBParameterGroups)
+	}
+
+	group := describeResp.DBParameterGroups[0]
+
+	// Only the parameters we've set differ from the defaults
+	paramsOpts := rds.DescribeDBParameters{
+		DBParameterGroupName: d.Id(),
+		Source:               "user",
+	}
+	paramsResp, err := rdsconn.DescribeDBParameters(&paramsOpts)
+	if err != nil {
+		return fmt.Errorf("Error retrieving DB Parameters: %s", err)
+	}
+
+	d.Set("name", group.DBParameterGroupName)
+	d.Set("family", group.DBParameterGroupFamily)
+	d.Set("description", group.Description)
+	d.Set("parameter", flattenParameters(paramsResp.Parameters))
+
+	return nil
+}
+
+func resourceAwsDbParameterGroupDeleteRefreshFunc(
+	conn *rds.Rds, name string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		log.Printf("[DEBUG] DB Parameter Group destroy: %s", name)
+
+		opts := rds.DeleteDBParameterGroup{DBParameterGroupName: name}
+		if _, err := conn.DeleteDBParameterGroup(&opts); err != nil {
+			rdserr, ok := err.(*rds.Error)
+			if !ok {
+				return nil, "", err
+			}
+
+			switch rdserr.Code {
+			case "DBParameterGroupNotFound":
+			case "InvalidDBParameterGroupState":
+				return name, "pending", nil
+			default:
+				return nil, "", err
+			}
+		}
+
+		return name, "destroyed", nil
+	}
+}
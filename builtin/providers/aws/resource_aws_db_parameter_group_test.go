@@ -0,0 +1,164 @@
+package aws
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/goamz/rds"
+)
+
+func TestAccAWSDBParameterGroup(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSDBParameterGroupDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSDBParameterGroupConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSDBParameterGroupParameters(
+						"aws_db_parameter_group.bar", map[string]string{
+							"character_set_server": "utf8",
+							"character_set_client": "utf8",
+						}),
+					resource.TestCheckResourceAttr(
+						"aws_db_parameter_group.bar", "name", "parameter-group-terraform"),
+					resource.TestCheckResourceAttr(
+						"aws_db_parameter_group.bar", "family", "mysql5.6"),
+					resource.TestCheckResourceAttr(
+						"aws_db_parameter_group.bar", "description", "Test parameter group for terraform"),
+					resource.TestCheckResourceAttr(
+						"aws_db_parameter_group.bar", "parameter.#", "2"),
+				),
+			},
+
+			// One parameter changes value, one goes back to its default
+			// and one is added
+			resource.TestStep{
+				Config: testAccAWSDBParameterGroupConfigUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSDBParameterGroupParameters(
+						"aws_db_parameter_group.bar", map[string]string{
+							"character_set_server": "ascii",
+							"collation_server":     "ascii_general_ci",
+						}),
+					resource.TestCheckResourceAttr(
+						"aws_db_parameter_group.bar", "parameter.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckAWSDBParameterGroupDestroy(s *terraform.State) error {
+	conn := testAccProvider.rdsconn
+
+	for _, rs := range s.Resources {
+		if rs.Type != "aws_db_parameter_group" {
+			continue
+		}
+
+		// Try to find the Group
+		resp, err := conn.DescribeDBParameterGroups(
+			&rds.DescribeDBParameterGroups{
+				DBParameterGroupName: rs.ID,
+			})
+
+		if err == nil {
+			if len(resp.DBParameterGroups) != 0 &&
+				resp.DBParameterGroups[0].DBParameterGroupName == rs.ID {
+				return fmt.Errorf("DB Parameter Group still exists")
+			}
+		}
+
+		// Verify the error
+		newerr, ok := err.(*rds.Error)
+		if !ok {
+			return err
+		}
+		if newerr.Code != "DBParameterGroupNotFound" {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// testAccCheckAWSDBParameterGroupParameters checks that the group exists
+// and that exactly the given parameters differ from the defaults.
+func testAccCheckAWSDBParameterGroupParameters(
+	n string, expected map[string]string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.ID == "" {
+			return fmt.Errorf("No DB Parameter Group ID is set")
+		}
+
+		conn := testAccProvider.rdsconn
+
+		resp, err := conn.DescribeDBParameters(&rds.DescribeDBParameters{
+			DBParameterGroupName: rs.ID,
+			Source:               "user",
+		})
+		if err != nil {
+			return err
+		}
+
+		actual := make(map[string]string)
+		for _, p := range resp.Parameters {
+			actual[p.ParameterName] = p.ParameterValue
+		}
+		if len(actual) != len(expected) {
+			return fmt.Errorf("bad parameters: %#v", actual)
+		}
+		for k, v := range expected {
+			if actual[k] != v {
+				return fmt.Errorf("bad parameter %s: %#v", k, actual[k])
+			}
+		}
+
+		return nil
+	}
+}
+
+const testAccAWSDBParameterGroupConfig = `
+resource "aws_db_parameter_group" "bar" {
+	name = "parameter-group-terraform"
+	family = "mysql5.6"
+	description = "Test parameter group for terraform"
+
+	parameter {
+		name = "character_set_server"
+		value = "utf8"
+	}
+
+	parameter {
+		name = "character_set_client"
+		value = "utf8"
+	}
+}
+`
+
+const testAccAWSDBParameterGroupConfigUpdate = `
+resource "aws_db_parameter_group" "bar" {
+	name = "parameter-group-terraform"
+	family = "mysql5.6"
+	description = "Test parameter group for terraform"
+
+	parameter {
+		name = "character_set_server"
+		value = "ascii"
+	}
+
+	parameter {
+		name = "collation_server"
+		value = "ascii_general_ci"
+	}
+}
+`
@@ -73,6 +73,7 @@ func resource_aws_db_security_group_create(
 		Target:  "authorized",
 		Refresh: DBSecurityGroupStateRefreshFunc(rs.ID, conn),
 		Timeout: 10 * time.Minute,
+		StopCh:  p.StopCh(),
 	}
 
 	// Wait, catching any errors
@@ -0,0 +1,168 @@
+package aws
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/goamz/rds"
+)
+
+func resourceAwsDbSubnetGroup() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsDbSubnetGroupCreate,
+		Read:   resourceAwsDbSubnetGroupRead,
+		Delete: resourceAwsDbSubnetGroupDelete,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"subnet_ids": &schema.Schema{
+				Type:     schema.TypeList,
+				Required: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceAwsDbSubnetGroupCreate(d *schema.ResourceData, meta interface{}) error {
+	p := meta.(*ResourceProvider)
+	rdsconn := p.rdsconn
+
+	opts := rds.CreateDBSubnetGroup{
+		DBSubnetGroupName:        d.Get("name").(string),
+		DBSubnetGroupDescription: d.Get("description").(string),
+		SubnetIds:                expandStringList(d.Get("subnet_ids").([]interface{})),
+	}
+
+	log.Printf("[DEBUG] DB Subnet Group create configuration: %#v", opts)
+	if _, err := rdsconn.CreateDBSubnetGroup(&opts); err != nil {
+		return fmt.Errorf("Error creating DB Subnet Group: %s", err)
+	}
+
+	d.SetId(opts.DBSubnetGroupName)
+	log.Printf("[INFO] DB Subnet Group ID: %s", d.Id())
+
+	return resourceAwsDbSubnetGroupRead(d, meta)
+}
+
+func resourceAwsDbSubnetGroupRead(d *schema.ResourceData, meta interface{}) error {
+	p := meta.(*ResourceProvider)
+	rdsconn := p.rdsconn
+
+	v, _, err := DBSubnetGroupStateRefreshFunc(rdsconn, d.Id())()
+	if err != nil {
+		return err
+	}
+	if v == nil {
+		d.SetId("")
+		return nil
+	}
+
+	group := v.(*rds.DBSubnetGroup)
+
+	var deps []terraform.ResourceDependency
+	for _, id := range group.SubnetIds {
+		deps = append(deps, terraform.ResourceDependency{ID: id})
+	}
+
+	d.Set("name", group.Name)
+	d.Set("description", group.Description)
+	d.Set("subnet_ids", group.SubnetIds)
+	d.SetDependencies(deps)
+
+	return nil
+}
+
+func resourceAwsDbSubnetGroupDelete(d *schema.ResourceData, meta interface{}) error {
+	p := meta.(*ResourceProvider)
+	rdsconn := p.rdsconn
+
+	// A DB Instance that used the group keeps it in use until it's
+	// entirely gone, which can be a while after it starts deleting, so
+	// keep trying until the group can be deleted.
+	timeout := d.Timeout("delete")
+	if timeout == 0 {
+		timeout = 10 * time.Minute
+	}
+
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"pending"},
+		Target:     "destroyed",
+		Refresh:    resourceAwsDbSubnetGroupDeleteRefreshFunc(rdsconn, d.Id()),
+		Timeout:    timeout,
+		MinTimeout: 5 * time.Second,
+		StopCh:     p.StopCh(),
+	}
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf(
+			"Error deleting DB Subnet Group (%s): %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+func resourceAwsDbSubnetGroupDeleteRefreshFunc(
+	conn *rds.Rds, name string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		log.Printf("[DEBUG] DB Subnet Group destroy: %s", name)
+
+		opts := rds.DeleteDBSubnetGroup{DBSubnetGroupName: name}
+		if _, err := conn.DeleteDBSubnetGroup(&opts); err != nil {
+			rdserr, ok := err.(*rds.Error)
+			if !ok {
+				return nil, "", err
+			}
+
+			switch rdserr.Code {
+			case "DBSubnetGroupNotFoundFault":
+			case "InvalidDBSubnetGroupStateFault":
+				return name, "pending", nil
+			default:
+				return nil, "", err
+			}
+		}
+
+		return name, "destroyed", nil
+	}
+}
+
+// DBSubnetGroupStateRefreshFunc returns a resource.StateRefreshFunc that
+// is used to watch a DB Subnet Group.
+func DBSubnetGroupStateRefreshFunc(conn *rds.Rds, name string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		opts := rds.DescribeDBSubnetGroups{DBSubnetGroupName: name}
+		resp, err := conn.DescribeDBSubnetGroups(&opts)
+		if err != nil {
+			if rdserr, ok := err.(*rds.Error); ok &&
+				rdserr.Code == "DBSubnetGroupNotFoundFault" {
+				return nil, "", nil
+			}
+
+			log.Printf("Error on DBSubnetGroupStateRefresh: %s", err)
+			return nil, "", err
+		}
+
+		if len(resp.DBSubnetGroups) == 0 {
+			return nil, "", nil
+		}
+
+		group := &resp.DBSubnetGroups[0]
+		return group, "exists", nil
+	}
+}
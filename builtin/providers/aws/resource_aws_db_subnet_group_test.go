@@ -0,0 +1,109 @@
+package aws
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/goamz/rds"
+)
+
+func TestAccAWSDBSubnetGroup(t *testing.T) {
+	var v rds.DBSubnetGroup
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSDBSubnetGroupDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSDBSubnetGroupConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSDBSubnetGroupExists("aws_db_subnet_group.bar", &v),
+					resource.TestCheckResourceAttr(
+						"aws_db_subnet_group.bar", "name", "subnet-group-terraform"),
+					resource.TestCheckResourceAttr(
+						"aws_db_subnet_group.bar", "description", "just cuz"),
+					resource.TestCheckResourceAttr(
+						"aws_db_subnet_group.bar", "subnet_ids.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckAWSDBSubnetGroupDestroy(s *terraform.State) error {
+	conn := testAccProvider.rdsconn
+
+	for _, rs := range s.Resources {
+		if rs.Type != "aws_db_subnet_group" {
+			continue
+		}
+
+		// Try to find the Group
+		v, _, err := DBSubnetGroupStateRefreshFunc(conn, rs.ID)()
+		if err != nil {
+			return err
+		}
+		if v != nil {
+			return fmt.Errorf("DB Subnet Group still exists")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckAWSDBSubnetGroupExists(n string, v *rds.DBSubnetGroup) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.ID == "" {
+			return fmt.Errorf("No DB Subnet Group ID is set")
+		}
+
+		conn := testAccProvider.rdsconn
+
+		group, _, err := DBSubnetGroupStateRefreshFunc(conn, rs.ID)()
+		if err != nil {
+			return err
+		}
+		if group == nil {
+			return fmt.Errorf("DB Subnet Group not found")
+		}
+
+		*v = *group.(*rds.DBSubnetGroup)
+		if len(v.SubnetIds) != 2 {
+			return fmt.Errorf("bad subnets: %#v", v.SubnetIds)
+		}
+
+		return nil
+	}
+}
+
+const testAccAWSDBSubnetGroupConfig = `
+resource "aws_vpc" "foo" {
+	cidr_block = "10.1.0.0/16"
+}
+
+resource "aws_subnet" "foo" {
+	cidr_block = "10.1.1.0/24"
+	availability_zone = "us-west-2a"
+	vpc_id = "${aws_vpc.foo.id}"
+}
+
+resource "aws_subnet" "bar" {
+	cidr_block = "10.1.2.0/24"
+	availability_zone = "us-west-2b"
+	vpc_id = "${aws_vpc.foo.id}"
+}
+
+resource "aws_db_subnet_group" "bar" {
+	name = "subnet-group-terraform"
+	description = "just cuz"
+	subnet_ids = ["${aws_subnet.foo.id}", "${aws_subnet.bar.id}"]
+}
+`
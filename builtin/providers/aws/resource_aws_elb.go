@@ -3,7 +3,7 @@ package aws
 import (
 	"fmt"
 	"log"
-	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform/flatmap"
 	"github.com/hashicorp/terraform/helper/config"
@@ -87,31 +87,8 @@ func resource_aws_elb_create(
 	}
 
 	if _, ok := rs.Attributes["health_check.#"]; ok {
-		v := flatmap.Expand(rs.Attributes, "health_check").([]interface{})
-		health_check := v[0].(map[string]interface{})
-		healthyThreshold, err := strconv.ParseInt(health_check["healthy_threshold"].(string), 0, 0)
-		unhealthyThreshold, err := strconv.ParseInt(health_check["unhealthy_threshold"].(string), 0, 0)
-		interval, err := strconv.ParseInt(health_check["interval"].(string), 0, 0)
-		timeout, err := strconv.ParseInt(health_check["timeout"].(string), 0, 0)
-
-		if err != nil {
-			return nil, err
-		}
-
-		configureHealthCheckOpts := elb.ConfigureHealthCheck{
-			LoadBalancerName: elbName,
-			Check: elb.HealthCheck{
-				HealthyThreshold:   healthyThreshold,
-				UnhealthyThreshold: unhealthyThreshold,
-				Interval:           interval,
-				Target:             health_check["target"].(string),
-				Timeout:            timeout,
-			},
-		}
-
-		_, err = elbconn.ConfigureHealthCheck(&configureHealthCheckOpts)
-		if err != nil {
-			return rs, fmt.Errorf("Failure configuring health check: %s", err)
+		if err := resource_aws_elb_configure_health_check(rs, elbconn); err != nil {
+			return rs, err
 		}
 	}
 
@@ -132,50 +109,12 @@ func resource_aws_elb_update(
 
 	rs := s.MergeDiff(d)
 
-	// If we currently have instances, or did have instances,
-	// we want to figure out what to add and remove from the load
-	// balancer
-	if attr, ok := d.Attributes["instances.#"]; ok && attr.Old != "" {
-		// The new state of instances merged with the diff
-		mergedInstances := expandStringList(flatmap.Expand(
-			rs.Attributes, "instances").([]interface{}))
-
-		// The state before the diff merge
-		previousInstances := expandStringList(flatmap.Expand(
-			s.Attributes, "instances").([]interface{}))
-
-		// keep track of what instances we are removing, and which
-		// we are adding
-		var toRemove []string
-		var toAdd []string
-
-		for _, instanceId := range mergedInstances {
-			for _, prevId := range previousInstances {
-				// If the merged instance ID existed
-				// previously, we don't have to do anything
-				if instanceId == prevId {
-					continue
-					// Otherwise, we need to add it to the load balancer
-				} else {
-					toAdd = append(toAdd, instanceId)
-				}
-			}
-		}
-
-		for i, instanceId := range toAdd {
-			for _, prevId := range previousInstances {
-				// If the instance ID we are adding existed
-				// previously, we want to not add it, but rather remove
-				// it
-				if instanceId == prevId {
-					toRemove = append(toRemove, instanceId)
-					toAdd = append(toAdd[:i], toAdd[i+1:]...)
-					// Otherwise, we continue adding it to the ELB
-				} else {
-					continue
-				}
-			}
-		}
+	// If the instances changed, register the new ones with the load
+	// balancer and deregister the ones that were removed
+	if _, ok := d.Attributes["instances.#"]; ok {
+		toAdd, toRemove := diffStringLists(
+			resource_aws_elb_instances(s.Attributes),
+			resource_aws_elb_instances(rs.Attributes))
 
 		if len(toAdd) > 0 {
 			registerInstancesOpts := elb.RegisterInstancesWithLoadBalancer{
@@ -204,6 +143,16 @@ func resource_aws_elb_update(
 		}
 	}
 
+	// The health check can be changed in place
+	for k, _ := range d.Attributes {
+		if strings.HasPrefix(k, "health_check.") {
+			if err := resource_aws_elb_configure_health_check(rs, elbconn); err != nil {
+				return s, err
+			}
+			break
+		}
+	}
+
 	loadBalancer, err := resource_aws_elb_retrieve_balancer(rs.ID, elbconn)
 
 	if err != nil {
@@ -261,7 +210,7 @@ func resource_aws_elb_diff(
 			"subnets":           diff.AttrTypeCreate, // TODO could be AttrTypeUpdate
 			"listener":          diff.AttrTypeCreate,
 			"instances":         diff.AttrTypeUpdate,
-			"health_check":      diff.AttrTypeCreate,
+			"health_check":      diff.AttrTypeUpdate,
 		},
 
 		ComputedAttrs: []string{
@@ -307,6 +256,38 @@ func resource_aws_elb_update_state(
 	return s, nil
 }
 
+// Returns the IDs of the instances of the ELB in its attributes
+func resource_aws_elb_instances(attrs map[string]string) []string {
+	if _, ok := attrs["instances.#"]; !ok {
+		return nil
+	}
+
+	return expandStringList(flatmap.Expand(attrs, "instances").([]interface{}))
+}
+
+// Configures the health check of the ELB from its attributes
+func resource_aws_elb_configure_health_check(
+	rs *terraform.ResourceState,
+	elbconn *elb.ELB) error {
+	v := flatmap.Expand(rs.Attributes, "health_check").([]interface{})
+	check, err := expandHealthCheck(v[0].(map[string]interface{}))
+	if err != nil {
+		return err
+	}
+
+	configureHealthCheckOpts := elb.ConfigureHealthCheck{
+		LoadBalancerName: rs.ID,
+		Check:            check,
+	}
+
+	_, err = elbconn.ConfigureHealthCheck(&configureHealthCheckOpts)
+	if err != nil {
+		return fmt.Errorf("Failure configuring health check: %s", err)
+	}
+
+	return nil
+}
+
 // retrieves an ELB by its ID
 func resource_aws_elb_retrieve_balancer(id string, elbconn *elb.ELB) (*elb.LoadBalancer, error) {
 	describeElbOpts := &elb.DescribeLoadBalancer{
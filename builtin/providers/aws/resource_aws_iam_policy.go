@@ -0,0 +1,168 @@
+package aws
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mitchellh/goamz/iam"
+)
+
+func resourceAwsIamPolicy() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsIamPolicyPut,
+		Read:   resourceAwsIamPolicyRead,
+		Update: resourceAwsIamPolicyPut,
+		Delete: resourceAwsIamPolicyDelete,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"policy": &schema.Schema{
+				Type:      schema.TypeString,
+				Required:  true,
+				StateFunc: normalizePolicyState,
+			},
+
+			"user": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"role": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+// resourceAwsIamPolicyOwner returns whether the policy is of a user or a
+// role, and its name.
+func resourceAwsIamPolicyOwner(d *schema.ResourceData) (string, string, error) {
+	var user, role string
+	if v := d.Get("user"); v != nil {
+		user = v.(string)
+	}
+	if v := d.Get("role"); v != nil {
+		role = v.(string)
+	}
+
+	switch {
+	case user != "" && role != "":
+		return "", "", fmt.Errorf("IAM policy can't have both a user and a role")
+	case user != "":
+		return "user", user, nil
+	case role != "":
+		return "role", role, nil
+	default:
+		return "", "", fmt.Errorf("IAM policy must have a user or a role")
+	}
+}
+
+func resourceAwsIamPolicyPut(d *schema.ResourceData, meta interface{}) error {
+	p := meta.(*ResourceProvider)
+	iamconn := p.iamconn
+
+	kind, owner, err := resourceAwsIamPolicyOwner(d)
+	if err != nil {
+		return err
+	}
+
+	name := d.Get("name").(string)
+	policy := d.Get("policy").(string)
+
+	log.Printf("[DEBUG] IAM policy put: %s (%s %s): %s", name, kind, owner, policy)
+	if kind == "user" {
+		_, err = iamconn.PutUserPolicy(owner, name, policy)
+	} else {
+		_, err = iamconn.PutRolePolicy(owner, name, policy)
+	}
+	if err != nil {
+		return fmt.Errorf("Error putting IAM policy: %s", err)
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", owner, name))
+
+	return resourceAwsIamPolicyRead(d, meta)
+}
+
+func resourceAwsIamPolicyRead(d *schema.ResourceData, meta interface{}) error {
+	p := meta.(*ResourceProvider)
+	iamconn := p.iamconn
+
+	kind, owner, err := resourceAwsIamPolicyOwner(d)
+	if err != nil {
+		return err
+	}
+
+	name := d.Get("name").(string)
+
+	var document string
+	if kind == "user" {
+		var resp *iam.GetUserPolicyResp
+		resp, err = iamconn.GetUserPolicy(owner, name)
+		if err == nil {
+			document = resp.Policy.Document
+		}
+	} else {
+		var resp *iam.GetRolePolicyResp
+		resp, err = iamconn.GetRolePolicy(owner, name)
+		if err == nil {
+			document = resp.Policy.Document
+		}
+	}
+	if err != nil {
+		if iamerr, ok := err.(*iam.Error); ok && iamerr.Code == "NoSuchEntity" {
+			d.SetId("")
+			return nil
+		}
+
+		return fmt.Errorf("Error reading IAM policy: %s", err)
+	}
+
+	// IAM returns policy documents URL encoded
+	policy, err := url.QueryUnescape(document)
+	if err != nil {
+		return fmt.Errorf("Error reading IAM policy: %s", err)
+	}
+
+	d.Set("policy", normalizePolicy(policy))
+
+	return nil
+}
+
+func resourceAwsIamPolicyDelete(d *schema.ResourceData, meta interface{}) error {
+	p := meta.(*ResourceProvider)
+	iamconn := p.iamconn
+
+	kind, owner, err := resourceAwsIamPolicyOwner(d)
+	if err != nil {
+		return err
+	}
+
+	name := d.Get("name").(string)
+
+	log.Printf("[DEBUG] IAM policy destroy: %s", d.Id())
+	if kind == "user" {
+		_, err = iamconn.DeleteUserPolicy(owner, name)
+	} else {
+		_, err = iamconn.DeleteRolePolicy(owner, name)
+	}
+	if err != nil {
+		if iamerr, ok := err.(*iam.Error); ok && iamerr.Code == "NoSuchEntity" {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting IAM policy: %s", err)
+	}
+
+	return nil
+}
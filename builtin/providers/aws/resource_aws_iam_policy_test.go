@@ -0,0 +1,172 @@
+package aws
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/goamz/iam"
+)
+
+func TestAccAWSIAMPolicy_user(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSIAMPolicyDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSIAMPolicyUserConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSIAMPolicyAction(
+						"aws_iam_policy.bar", "s3:ListAllMyBuckets"),
+					resource.TestCheckResourceAttr(
+						"aws_iam_policy.bar", "user", "terraform-acc-policy"),
+				),
+			},
+
+			// The policy document is updated in place
+			resource.TestStep{
+				Config: testAccAWSIAMPolicyUserConfigUpdate,
+				Check: testAccCheckAWSIAMPolicyAction(
+					"aws_iam_policy.bar", "s3:GetBucketLocation"),
+			},
+		},
+	})
+}
+
+func TestAccAWSIAMPolicy_role(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSIAMPolicyDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSIAMPolicyRoleConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSIAMPolicyAction(
+						"aws_iam_policy.bar", "s3:ListAllMyBuckets"),
+					resource.TestCheckResourceAttr(
+						"aws_iam_policy.bar", "role", "terraform-acc-policy"),
+				),
+			},
+		},
+	})
+}
+
+// testAccAWSIAMPolicyDocument returns the document of the policy in the
+// state.
+func testAccAWSIAMPolicyDocument(rs *terraform.ResourceState) (string, error) {
+	conn := testAccProvider.iamconn
+	name := rs.Attributes["name"]
+
+	var document string
+	var err error
+	if user := rs.Attributes["user"]; user != "" {
+		var resp *iam.GetUserPolicyResp
+		resp, err = conn.GetUserPolicy(user, name)
+		if err == nil {
+			document = resp.Policy.Document
+		}
+	} else {
+		var resp *iam.GetRolePolicyResp
+		resp, err = conn.GetRolePolicy(rs.Attributes["role"], name)
+		if err == nil {
+			document = resp.Policy.Document
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return url.QueryUnescape(document)
+}
+
+func testAccCheckAWSIAMPolicyDestroy(s *terraform.State) error {
+	for _, rs := range s.Resources {
+		if rs.Type != "aws_iam_policy" {
+			continue
+		}
+
+		// Try to find the policy
+		_, err := testAccAWSIAMPolicyDocument(rs)
+		if err == nil {
+			return fmt.Errorf("IAM policy still exists")
+		}
+
+		// Verify the error
+		iamerr, ok := err.(*iam.Error)
+		if !ok {
+			return err
+		}
+		if iamerr.Code != "NoSuchEntity" {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// testAccCheckAWSIAMPolicyAction checks that the policy exists and that
+// its document allows the given action.
+func testAccCheckAWSIAMPolicyAction(n, action string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.ID == "" {
+			return fmt.Errorf("No IAM policy ID is set")
+		}
+
+		policy, err := testAccAWSIAMPolicyDocument(rs)
+		if err != nil {
+			return err
+		}
+		if !strings.Contains(policy, action) {
+			return fmt.Errorf("bad policy: %s", policy)
+		}
+
+		return nil
+	}
+}
+
+const testAccAWSIAMPolicyUserConfig = `
+resource "aws_iam_user" "foo" {
+	name = "terraform-acc-policy"
+}
+
+resource "aws_iam_policy" "bar" {
+	name = "terraform-acc-policy"
+	user = "${aws_iam_user.foo.name}"
+	policy = "${file("test-fixtures/iam-policy-list-all-my-buckets.json")}"
+}
+`
+
+const testAccAWSIAMPolicyUserConfigUpdate = `
+resource "aws_iam_user" "foo" {
+	name = "terraform-acc-policy"
+}
+
+resource "aws_iam_policy" "bar" {
+	name = "terraform-acc-policy"
+	user = "${aws_iam_user.foo.name}"
+	policy = "${file("test-fixtures/iam-policy-get-bucket-location.json")}"
+}
+`
+
+const testAccAWSIAMPolicyRoleConfig = `
+resource "aws_iam_role" "foo" {
+	name = "terraform-acc-policy"
+	assume_role_policy = "${file("test-fixtures/iam-assume-role-ec2.json")}"
+}
+
+resource "aws_iam_policy" "bar" {
+	name = "terraform-acc-policy"
+	role = "${aws_iam_role.foo.name}"
+	policy = "${file("test-fixtures/iam-policy-list-all-my-buckets.json")}"
+}
+`
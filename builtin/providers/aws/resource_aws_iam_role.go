@@ -0,0 +1,134 @@
+package aws
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mitchellh/goamz/iam"
+)
+
+func resourceAwsIamRole() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsIamRoleCreate,
+		Read:   resourceAwsIamRoleRead,
+		Update: resourceAwsIamRoleUpdate,
+		Delete: resourceAwsIamRoleDelete,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"path": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"assume_role_policy": &schema.Schema{
+				Type:      schema.TypeString,
+				Required:  true,
+				StateFunc: normalizePolicyState,
+			},
+
+			"arn": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"unique_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceAwsIamRoleCreate(d *schema.ResourceData, meta interface{}) error {
+	p := meta.(*ResourceProvider)
+	iamconn := p.iamconn
+
+	name := d.Get("name").(string)
+	path := "/"
+	if v := d.Get("path"); v != nil && v.(string) != "" {
+		path = v.(string)
+	}
+	policy := d.Get("assume_role_policy").(string)
+
+	log.Printf("[DEBUG] IAM role create: %s (path: %s)", name, path)
+	resp, err := iamconn.CreateRole(name, path, policy)
+	if err != nil {
+		return fmt.Errorf("Error creating IAM role: %s", err)
+	}
+
+	d.SetId(resp.Role.Name)
+	log.Printf("[INFO] IAM role ID: %s", d.Id())
+
+	return resourceAwsIamRoleRead(d, meta)
+}
+
+func resourceAwsIamRoleUpdate(d *schema.ResourceData, meta interface{}) error {
+	p := meta.(*ResourceProvider)
+	iamconn := p.iamconn
+
+	if d.HasChange("assume_role_policy") {
+		policy := d.Get("assume_role_policy").(string)
+
+		log.Printf("[DEBUG] IAM role %s assume role policy: %s", d.Id(), policy)
+		if _, err := iamconn.UpdateAssumeRolePolicy(d.Id(), policy); err != nil {
+			return fmt.Errorf("Error updating IAM role: %s", err)
+		}
+	}
+
+	return resourceAwsIamRoleRead(d, meta)
+}
+
+func resourceAwsIamRoleRead(d *schema.ResourceData, meta interface{}) error {
+	p := meta.(*ResourceProvider)
+	iamconn := p.iamconn
+
+	resp, err := iamconn.GetRole(d.Id())
+	if err != nil {
+		if iamerr, ok := err.(*iam.Error); ok && iamerr.Code == "NoSuchEntity" {
+			d.SetId("")
+			return nil
+		}
+
+		return fmt.Errorf("Error reading IAM role: %s", err)
+	}
+
+	// IAM returns policy documents URL encoded
+	policy, err := url.QueryUnescape(resp.Role.AssumeRolePolicyDocument)
+	if err != nil {
+		return fmt.Errorf("Error reading IAM role policy: %s", err)
+	}
+
+	d.Set("name", resp.Role.Name)
+	d.Set("path", resp.Role.Path)
+	d.Set("assume_role_policy", normalizePolicy(policy))
+	d.Set("arn", resp.Role.Arn)
+	d.Set("unique_id", resp.Role.Id)
+
+	return nil
+}
+
+func resourceAwsIamRoleDelete(d *schema.ResourceData, meta interface{}) error {
+	p := meta.(*ResourceProvider)
+	iamconn := p.iamconn
+
+	log.Printf("[DEBUG] IAM role destroy: %s", d.Id())
+	if _, err := iamconn.DeleteRole(d.Id()); err != nil {
+		if iamerr, ok := err.(*iam.Error); ok && iamerr.Code == "NoSuchEntity" {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting IAM role: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package aws
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/goamz/iam"
+)
+
+func TestAccAWSIAMRole(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSIAMRoleDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSIAMRoleConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSIAMRolePolicy("aws_iam_role.bar", "ec2.amazonaws.com"),
+					resource.TestCheckResourceAttr(
+						"aws_iam_role.bar", "name", "terraform-acc-role"),
+					resource.TestCheckResourceAttr(
+						"aws_iam_role.bar", "path", "/"),
+				),
+			},
+
+			// The assume role policy is updated in place
+			resource.TestStep{
+				Config: testAccAWSIAMRoleConfigUpdate,
+				Check: testAccCheckAWSIAMRolePolicy(
+					"aws_iam_role.bar", "lambda.amazonaws.com"),
+			},
+		},
+	})
+}
+
+func testAccCheckAWSIAMRoleDestroy(s *terraform.State) error {
+	conn := testAccProvider.iamconn
+
+	for _, rs := range s.Resources {
+		if rs.Type != "aws_iam_role" {
+			continue
+		}
+
+		// Try to find the role
+		_, err := conn.GetRole(rs.ID)
+		if err == nil {
+			return fmt.Errorf("IAM role still exists")
+		}
+
+		// Verify the error
+		iamerr, ok := err.(*iam.Error)
+		if !ok {
+			return err
+		}
+		if iamerr.Code != "NoSuchEntity" {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// testAccCheckAWSIAMRolePolicy checks that the role exists and that its
+// assume role policy is for the given service.
+func testAccCheckAWSIAMRolePolicy(n, service string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.ID == "" {
+			return fmt.Errorf("No IAM role ID is set")
+		}
+
+		conn := testAccProvider.iamconn
+
+		resp, err := conn.GetRole(rs.ID)
+		if err != nil {
+			return err
+		}
+
+		policy, err := url.QueryUnescape(resp.Role.AssumeRolePolicyDocument)
+		if err != nil {
+			return err
+		}
+		if !strings.Contains(policy, service) {
+			return fmt.Errorf("bad assume role policy: %s", policy)
+		}
+
+		return nil
+	}
+}
+
+const testAccAWSIAMRoleConfig = `
+resource "aws_iam_role" "bar" {
+	name = "terraform-acc-role"
+	assume_role_policy = "${file("test-fixtures/iam-assume-role-ec2.json")}"
+}
+`
+
+const testAccAWSIAMRoleConfigUpdate = `
+resource "aws_iam_role" "bar" {
+	name = "terraform-acc-role"
+	assume_role_policy = "${file("test-fixtures/iam-assume-role-lambda.json")}"
+}
+`
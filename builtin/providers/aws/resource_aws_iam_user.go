@@ -0,0 +1,102 @@
+package aws
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mitchellh/goamz/iam"
+)
+
+func resourceAwsIamUser() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsIamUserCreate,
+		Read:   resourceAwsIamUserRead,
+		Delete: resourceAwsIamUserDelete,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"path": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"arn": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"unique_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceAwsIamUserCreate(d *schema.ResourceData, meta interface{}) error {
+	p := meta.(*ResourceProvider)
+	iamconn := p.iamconn
+
+	name := d.Get("name").(string)
+	path := "/"
+	if v := d.Get("path"); v != nil && v.(string) != "" {
+		path = v.(string)
+	}
+
+	log.Printf("[DEBUG] IAM user create: %s (path: %s)", name, path)
+	resp, err := iamconn.CreateUser(name, path)
+	if err != nil {
+		return fmt.Errorf("Error creating IAM user: %s", err)
+	}
+
+	d.SetId(resp.User.Name)
+	log.Printf("[INFO] IAM user ID: %s", d.Id())
+
+	return resourceAwsIamUserRead(d, meta)
+}
+
+func resourceAwsIamUserRead(d *schema.ResourceData, meta interface{}) error {
+	p := meta.(*ResourceProvider)
+	iamconn := p.iamconn
+
+	resp, err := iamconn.GetUser(d.Id())
+	if err != nil {
+		if iamerr, ok := err.(*iam.Error); ok && iamerr.Code == "NoSuchEntity" {
+			d.SetId("")
+			return nil
+		}
+
+		return fmt.Errorf("Error reading IAM user: %s", err)
+	}
+
+	d.Set("name", resp.User.Name)
+	d.Set("path", resp.User.Path)
+	d.Set("arn", resp.User.Arn)
+	d.Set("unique_id", resp.User.Id)
+
+	return nil
+}
+
+func resourceAwsIamUserDelete(d *schema.ResourceData, meta interface{}) error {
+	p := meta.(*ResourceProvider)
+	iamconn := p.iamconn
+
+	log.Printf("[DEBUG] IAM user destroy: %s", d.Id())
+	if _, err := iamconn.DeleteUser(d.Id()); err != nil {
+		if iamerr, ok := err.(*iam.Error); ok && iamerr.Code == "NoSuchEntity" {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting IAM user: %s", err)
+	}
+
+	return nil
+}
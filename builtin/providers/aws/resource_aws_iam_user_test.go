@@ -0,0 +1,93 @@
+package aws
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/goamz/iam"
+)
+
+func TestAccAWSIAMUser(t *testing.T) {
+	var v iam.User
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSIAMUserDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSIAMUserConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSIAMUserExists("aws_iam_user.bar", &v),
+					resource.TestCheckResourceAttr(
+						"aws_iam_user.bar", "name", "terraform-acc-user"),
+					resource.TestCheckResourceAttr(
+						"aws_iam_user.bar", "path", "/terraform/"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckAWSIAMUserDestroy(s *terraform.State) error {
+	conn := testAccProvider.iamconn
+
+	for _, rs := range s.Resources {
+		if rs.Type != "aws_iam_user" {
+			continue
+		}
+
+		// Try to find the user
+		_, err := conn.GetUser(rs.ID)
+		if err == nil {
+			return fmt.Errorf("IAM user still exists")
+		}
+
+		// Verify the error
+		iamerr, ok := err.(*iam.Error)
+		if !ok {
+			return err
+		}
+		if iamerr.Code != "NoSuchEntity" {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckAWSIAMUserExists(n string, v *iam.User) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.ID == "" {
+			return fmt.Errorf("No IAM user ID is set")
+		}
+
+		conn := testAccProvider.iamconn
+
+		resp, err := conn.GetUser(rs.ID)
+		if err != nil {
+			return err
+		}
+		if rs.Attributes["arn"] != resp.User.Arn {
+			return fmt.Errorf("bad arn: %s", rs.Attributes["arn"])
+		}
+
+		*v = resp.User
+
+		return nil
+	}
+}
+
+const testAccAWSIAMUserConfig = `
+resource "aws_iam_user" "bar" {
+	name = "terraform-acc-user"
+	path = "/terraform/"
+}
+`
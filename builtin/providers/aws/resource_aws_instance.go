@@ -21,6 +21,7 @@ func resourceAwsInstance() *schema.Resource {
 		Read:   resourceAwsInstanceRead,
 		Update: resourceAwsInstanceUpdate,
 		Delete: resourceAwsInstanceDelete,
+		List:   resourceAwsInstanceList,
 
 		Schema: map[string]*schema.Schema{
 			"ami": &schema.Schema{
@@ -184,6 +185,7 @@ func resourceAwsInstanceCreate(d *schema.ResourceData, meta interface{}) error {
 		Timeout:    10 * time.Minute,
 		Delay:      10 * time.Second,
 		MinTimeout: 3 * time.Second,
+		StopCh:     p.StopCh(),
 	}
 
 	instanceRaw, err := stateConf.WaitForState()
@@ -256,6 +258,7 @@ func resourceAwsInstanceDelete(d *schema.ResourceData, meta interface{}) error {
 		Timeout:    10 * time.Minute,
 		Delay:      10 * time.Second,
 		MinTimeout: 3 * time.Second,
+		StopCh:     p.StopCh(),
 	}
 
 	_, err := stateConf.WaitForState()
@@ -269,6 +272,37 @@ func resourceAwsInstanceDelete(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
+func resourceAwsInstanceList(
+	filters map[string]string, meta interface{}) ([]string, error) {
+	p := meta.(*ResourceProvider)
+	ec2conn := p.ec2conn
+
+	// The filters are passed to EC2 as-is, such as "tag:Name" or
+	// "instance-state-name".
+	filter := ec2.NewFilter()
+	for k, v := range filters {
+		filter.Add(k, v)
+	}
+
+	resp, err := ec2conn.Instances(nil, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for _, r := range resp.Reservations {
+		for _, instance := range r.Instances {
+			if instance.State.Name == "terminated" {
+				continue
+			}
+
+			result = append(result, instance.InstanceId)
+		}
+	}
+
+	return result, nil
+}
+
 func resourceAwsInstanceRead(d *schema.ResourceData, meta interface{}) error {
 	p := meta.(*ResourceProvider)
 	ec2conn := p.ec2conn
@@ -97,6 +97,7 @@ func resource_aws_internet_gateway_destroy(
 		Target:  "",
 		Refresh: IGStateRefreshFunc(ec2conn, s.ID),
 		Timeout: 10 * time.Minute,
+		StopCh:  p.StopCh(),
 	}
 	if _, err := stateConf.WaitForState(); err != nil {
 		return fmt.Errorf(
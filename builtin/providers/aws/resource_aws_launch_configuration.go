@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"log"
 
+	tfconfig "github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/flatmap"
 	"github.com/hashicorp/terraform/helper/config"
 	"github.com/hashicorp/terraform/helper/diff"
+	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/mitchellh/goamz/autoscaling"
 )
@@ -54,6 +56,12 @@ func resource_aws_launch_configuration_create(
 		createLaunchConfigurationOpts.UserData = rs.Attributes["user_data"]
 	}
 
+	// Launch configurations can't be updated, and one that is in use by
+	// an autoscaling group can't be deleted, so a generated name lets the
+	// replacement be created while the old one still exists.
+	if v := rs.Attributes["name"]; v == "" || v == tfconfig.UnknownVariableValue {
+		rs.Attributes["name"] = resource.UniqueId()
+	}
 	createLaunchConfigurationOpts.Name = rs.Attributes["name"]
 
 	log.Printf("[DEBUG] autoscaling create launch configuration: %#v", createLaunchConfigurationOpts)
@@ -135,6 +143,7 @@ func resource_aws_launch_configuration_diff(
 
 		ComputedAttrs: []string{
 			"key_name",
+			"name",
 		},
 	}
 
@@ -194,12 +203,12 @@ func resource_aws_launch_configuration_retrieve(id string, autoscalingconn *auto
 func resource_aws_launch_configuration_validation() *config.Validator {
 	return &config.Validator{
 		Required: []string{
-			"name",
 			"image_id",
 			"instance_type",
 		},
 		Optional: []string{
 			"key_name",
+			"name",
 			"security_groups.*",
 			"user_data",
 		},
@@ -78,6 +78,7 @@ func resource_aws_r53_record_create(
 
 			return resp.ChangeInfo, "accepted", nil
 		},
+		StopCh: p.StopCh(),
 	}
 	respRaw, err := wait.WaitForState()
 	if err != nil {
@@ -101,6 +102,7 @@ func resource_aws_r53_record_create(
 		Refresh: func() (result interface{}, state string, err error) {
 			return resource_aws_r53_wait(conn, changeInfo.ID)
 		},
+		StopCh: p.StopCh(),
 	}
 	_, err = wait.WaitForState()
 	if err != nil {
@@ -56,6 +56,7 @@ func resource_aws_r53_zone_create(
 		Refresh: func() (result interface{}, state string, err error) {
 			return resource_aws_r53_wait(r53, resp.ChangeInfo.ID)
 		},
+		StopCh: p.StopCh(),
 	}
 	_, err = wait.WaitForState()
 	if err != nil {
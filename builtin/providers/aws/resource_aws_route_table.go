@@ -44,6 +44,7 @@ func resource_aws_route_table_create(
 		Target:  "ready",
 		Refresh: RouteTableStateRefreshFunc(ec2conn, s.ID),
 		Timeout: 1 * time.Minute,
+		StopCh:  p.StopCh(),
 	}
 	if _, err := stateConf.WaitForState(); err != nil {
 		return s, fmt.Errorf(
@@ -177,6 +178,7 @@ func resource_aws_route_table_destroy(
 		Target:  "",
 		Refresh: RouteTableStateRefreshFunc(ec2conn, s.ID),
 		Timeout: 1 * time.Minute,
+		StopCh:  p.StopCh(),
 	}
 	if _, err := stateConf.WaitForState(); err != nil {
 		return fmt.Errorf(
@@ -3,7 +3,10 @@ package aws
 import (
 	"fmt"
 	"log"
+	"net/url"
+	"strings"
 
+	"github.com/hashicorp/terraform/flatmap"
 	"github.com/hashicorp/terraform/helper/config"
 	"github.com/hashicorp/terraform/helper/diff"
 	"github.com/hashicorp/terraform/terraform"
@@ -17,6 +20,10 @@ func resource_aws_s3_bucket_validation() *config.Validator {
 		},
 		Optional: []string{
 			"acl",
+			"policy",
+			"versioning",
+			"website.*.index_document",
+			"website.*.error_document",
 		},
 	}
 }
@@ -48,7 +55,98 @@ func resource_aws_s3_bucket_create(
 
 	// Assign the bucket name as the resource ID
 	rs.ID = bucket
-	return rs, nil
+
+	if err := resource_aws_s3_bucket_configure(s3Bucket, rs, d); err != nil {
+		return rs, err
+	}
+
+	return resource_aws_s3_bucket_update_state(rs, s3conn, s3Bucket)
+}
+
+func resource_aws_s3_bucket_update(
+	s *terraform.ResourceState,
+	d *terraform.ResourceDiff,
+	meta interface{}) (*terraform.ResourceState, error) {
+	p := meta.(*ResourceProvider)
+	s3conn := p.s3conn
+
+	rs := s.MergeDiff(d)
+	s3Bucket := s3conn.Bucket(rs.ID)
+
+	if err := resource_aws_s3_bucket_configure(s3Bucket, rs, d); err != nil {
+		return s, err
+	}
+
+	return resource_aws_s3_bucket_update_state(rs, s3conn, s3Bucket)
+}
+
+// resource_aws_s3_bucket_configure sets the policy, versioning and
+// website configuration of the bucket that changed in the diff.
+func resource_aws_s3_bucket_configure(
+	bucket *s3.Bucket,
+	rs *terraform.ResourceState,
+	d *terraform.ResourceDiff) error {
+	if _, ok := d.Attributes["policy"]; ok {
+		if v := rs.Attributes["policy"]; v != "" {
+			log.Printf("[DEBUG] S3 bucket %s put policy: %s", bucket.Name, v)
+			if err := bucket.PutPolicy([]byte(v)); err != nil {
+				return fmt.Errorf("Error putting S3 bucket policy: %s", err)
+			}
+		} else {
+			log.Printf("[DEBUG] S3 bucket %s delete policy", bucket.Name)
+			if err := bucket.DeletePolicy(); err != nil {
+				return fmt.Errorf("Error deleting S3 bucket policy: %s", err)
+			}
+		}
+	}
+
+	if _, ok := d.Attributes["versioning"]; ok {
+		// Versioning can't be turned off once it's on, only suspended
+		status := "Suspended"
+		if rs.Attributes["versioning"] == "true" {
+			status = "Enabled"
+		}
+
+		log.Printf("[DEBUG] S3 bucket %s versioning: %s", bucket.Name, status)
+		if err := bucket.PutVersioning(status); err != nil {
+			return fmt.Errorf("Error putting S3 bucket versioning: %s", err)
+		}
+	}
+
+	websiteChanged := false
+	for k, _ := range d.Attributes {
+		if strings.HasPrefix(k, "website.") {
+			websiteChanged = true
+			break
+		}
+	}
+	if websiteChanged {
+		if n := rs.Attributes["website.#"]; n != "" && n != "0" {
+			v := flatmap.Expand(rs.Attributes, "website").([]interface{})
+			m := v[0].(map[string]interface{})
+
+			website := s3.WebsiteConfiguration{
+				IndexDocument: &s3.IndexDocument{
+					Suffix: m["index_document"].(string),
+				},
+			}
+			if v, ok := m["error_document"]; ok {
+				website.ErrorDocument = &s3.ErrorDocument{Key: v.(string)}
+			}
+
+			log.Printf("[DEBUG] S3 bucket %s put website: %#v", bucket.Name, website)
+			if err := bucket.PutWebsite(website); err != nil {
+				return fmt.Errorf("Error putting S3 bucket website: %s", err)
+			}
+		} else {
+			log.Printf("[DEBUG] S3 bucket %s delete website", bucket.Name)
+			if err := bucket.DeleteWebsite(); err != nil {
+				return fmt.Errorf("Error deleting S3 bucket website: %s", err)
+			}
+		}
+	}
+
+	return nil
 }
 
 func resource_aws_s3_bucket_destroy(
@@ -76,7 +174,8 @@ func resource_aws_s3_bucket_refresh(
 		return s, err
 	}
 	defer resp.Body.Close()
-	return s, nil
+
+	return resource_aws_s3_bucket_update_state(s, s3conn, bucket)
 }
 
 func resource_aws_s3_bucket_diff(
@@ -86,8 +185,84 @@ func resource_aws_s3_bucket_diff(
 
 	b := &diff.ResourceBuilder{
 		Attrs: map[string]diff.AttrType{
-			"bucket": diff.AttrTypeCreate,
+			"bucket":     diff.AttrTypeCreate,
+			"policy":     diff.AttrTypeUpdate,
+			"versioning": diff.AttrTypeUpdate,
+			"website":    diff.AttrTypeUpdate,
+		},
+
+		ComputedAttrs: []string{
+			"website_endpoint",
+		},
+
+		// Policies that only differ in their formatting are the same
+		PreProcess: map[string]diff.PreProcessFunc{
+			"policy": normalizePolicy,
 		},
 	}
 	return b.Diff(s, c)
 }
+
+func resource_aws_s3_bucket_update_state(
+	s *terraform.ResourceState,
+	conn *s3.S3,
+	bucket *s3.Bucket) (*terraform.ResourceState, error) {
+	policy, err := bucket.GetPolicy()
+	if err != nil {
+		if s3err, ok := err.(*s3.Error); !ok || s3err.Code != "NoSuchBucketPolicy" {
+			return s, fmt.Errorf("Error reading S3 bucket policy: %s", err)
+		}
+
+		delete(s.Attributes, "policy")
+	} else {
+		// The policy may come back escaped
+		v, err := url.QueryUnescape(string(policy))
+		if err != nil {
+			v = string(policy)
+		}
+
+		s.Attributes["policy"] = normalizePolicy(v)
+	}
+
+	status, err := bucket.GetVersioning()
+	if err != nil {
+		return s, fmt.Errorf("Error reading S3 bucket versioning: %s", err)
+	}
+	if status == "Enabled" {
+		s.Attributes["versioning"] = "true"
+	} else if _, ok := s.Attributes["versioning"]; ok {
+		s.Attributes["versioning"] = "false"
+	}
+
+	for k, _ := range s.Attributes {
+		if k == "website_endpoint" || strings.HasPrefix(k, "website.") {
+			delete(s.Attributes, k)
+		}
+	}
+	website, err := bucket.GetWebsite()
+	if err != nil {
+		if s3err, ok := err.(*s3.Error); !ok || s3err.Code != "NoSuchWebsiteConfiguration" {
+			return s, fmt.Errorf("Error reading S3 bucket website: %s", err)
+		}
+	} else {
+		m := make(map[string]interface{})
+		if website.IndexDocument != nil {
+			m["index_document"] = website.IndexDocument.Suffix
+		}
+		if website.ErrorDocument != nil {
+			m["error_document"] = website.ErrorDocument.Key
+		}
+
+		toFlatten := map[string]interface{}{
+			"website": []map[string]interface{}{m},
+		}
+		for k, v := range flatmap.Flatten(toFlatten) {
+			s.Attributes[k] = v
+		}
+
+		s.Attributes["website_endpoint"] = fmt.Sprintf(
+			"%s.s3-website-%s.amazonaws.com", bucket.Name, conn.Region.Name)
+	}
+
+	return s, nil
+}
@@ -157,6 +157,7 @@ func resourceAwsSecurityGroupCreate(d *schema.ResourceData, meta interface{}) er
 		Target:  "exists",
 		Refresh: SGStateRefreshFunc(ec2conn, d.Id()),
 		Timeout: 1 * time.Minute,
+		StopCh:  p.StopCh(),
 	}
 	if _, err := stateConf.WaitForState(); err != nil {
 		return fmt.Errorf(
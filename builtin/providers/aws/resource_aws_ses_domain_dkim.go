@@ -0,0 +1,72 @@
+package aws
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceAwsSesDomainDkim() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsSesDomainDkimCreate,
+		Read:   resourceAwsSesDomainDkimRead,
+		Delete: resourceAwsSesDomainDkimDelete,
+
+		Schema: map[string]*schema.Schema{
+			"domain": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"dkim_tokens": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceAwsSesDomainDkimCreate(d *schema.ResourceData, meta interface{}) error {
+	p := meta.(*ResourceProvider)
+	sesconn := p.sesconn
+
+	domain := d.Get("domain").(string)
+
+	log.Printf("[DEBUG] SES domain DKIM create: %s", domain)
+	if _, err := sesconn.VerifyDomainDkim(domain); err != nil {
+		return fmt.Errorf("Error creating SES domain DKIM: %s", err)
+	}
+
+	d.SetId(domain)
+
+	return resourceAwsSesDomainDkimRead(d, meta)
+}
+
+func resourceAwsSesDomainDkimRead(d *schema.ResourceData, meta interface{}) error {
+	p := meta.(*ResourceProvider)
+	sesconn := p.sesconn
+
+	dkim, err := sesconn.IdentityDkim(d.Id())
+	if err != nil {
+		return fmt.Errorf("Error reading SES domain DKIM: %s", err)
+	}
+	if dkim == nil || len(dkim.DkimTokens) == 0 {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("dkim_tokens", dkim.DkimTokens)
+
+	return nil
+}
+
+func resourceAwsSesDomainDkimDelete(d *schema.ResourceData, meta interface{}) error {
+	// SES has no call to remove the DKIM tokens of a domain, they are
+	// deleted along with the domain identity.
+	log.Printf("[DEBUG] SES domain DKIM destroy: %s", d.Id())
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package aws
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccAWSSESDomainDkim(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSSESDomainIdentityDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSSESDomainDkimConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSSESDomainDkimExists("aws_ses_domain_dkim.foo"),
+					resource.TestCheckResourceAttr(
+						"aws_ses_domain_dkim.foo", "domain", "terraform-dkim-test.example.com"),
+					resource.TestCheckResourceAttr(
+						"aws_ses_domain_dkim.foo", "dkim_tokens.#", "3"),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckAWSSESDomainDkimExists checks that the domain has DKIM
+// tokens, and that they're the ones in the state.
+func testAccCheckAWSSESDomainDkimExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		dkim, err := testAccProvider.sesconn.IdentityDkim(rs.ID)
+		if err != nil {
+			return err
+		}
+		if dkim == nil || len(dkim.DkimTokens) == 0 {
+			return fmt.Errorf("SES domain DKIM not found: %s", rs.ID)
+		}
+
+		for i, token := range dkim.DkimTokens {
+			key := "dkim_tokens." + strconv.Itoa(i)
+			if rs.Attributes[key] != token {
+				return fmt.Errorf("Bad %s: %s", key, rs.Attributes[key])
+			}
+		}
+
+		return nil
+	}
+}
+
+const testAccAWSSESDomainDkimConfig = `
+resource "aws_ses_domain_identity" "foo" {
+	domain = "terraform-dkim-test.example.com"
+}
+
+resource "aws_ses_domain_dkim" "foo" {
+	domain = "${aws_ses_domain_identity.foo.domain}"
+}
+`
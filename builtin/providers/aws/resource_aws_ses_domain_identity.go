@@ -0,0 +1,75 @@
+package aws
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceAwsSesDomainIdentity() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsSesDomainIdentityCreate,
+		Read:   resourceAwsSesDomainIdentityRead,
+		Delete: resourceAwsSesDomainIdentityDelete,
+
+		Schema: map[string]*schema.Schema{
+			"domain": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"verification_token": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceAwsSesDomainIdentityCreate(d *schema.ResourceData, meta interface{}) error {
+	p := meta.(*ResourceProvider)
+	sesconn := p.sesconn
+
+	domain := d.Get("domain").(string)
+
+	log.Printf("[DEBUG] SES domain identity create: %s", domain)
+	if _, err := sesconn.VerifyDomainIdentity(domain); err != nil {
+		return fmt.Errorf("Error creating SES domain identity: %s", err)
+	}
+
+	d.SetId(domain)
+
+	return resourceAwsSesDomainIdentityRead(d, meta)
+}
+
+func resourceAwsSesDomainIdentityRead(d *schema.ResourceData, meta interface{}) error {
+	p := meta.(*ResourceProvider)
+	sesconn := p.sesconn
+
+	v, err := sesconn.IdentityVerification(d.Id())
+	if err != nil {
+		return fmt.Errorf("Error reading SES domain identity: %s", err)
+	}
+	if v == nil {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("verification_token", v.VerificationToken)
+
+	return nil
+}
+
+func resourceAwsSesDomainIdentityDelete(d *schema.ResourceData, meta interface{}) error {
+	p := meta.(*ResourceProvider)
+	sesconn := p.sesconn
+
+	log.Printf("[DEBUG] SES domain identity destroy: %s", d.Id())
+	if err := sesconn.DeleteIdentity(d.Id()); err != nil {
+		return fmt.Errorf("Error deleting SES domain identity: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package aws
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccAWSSESDomainIdentity(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSSESDomainIdentityDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSSESDomainIdentityConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSSESDomainIdentityExists("aws_ses_domain_identity.foo"),
+					testAccCheckAWSSESDomainDkimExists("aws_ses_domain_dkim.foo"),
+					resource.TestCheckResourceAttr(
+						"aws_ses_domain_dkim.foo", "dkim_tokens.#", "3"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckAWSSESDomainIdentityDestroy(s *terraform.State) error {
+	conn := testAccProvider.sesconn
+
+	for _, rs := range s.Resources {
+		if rs.Type != "aws_ses_domain_identity" {
+			continue
+		}
+
+		v, err := conn.IdentityVerification(rs.ID)
+		if err != nil {
+			return err
+		}
+		if v != nil {
+			return fmt.Errorf("SES domain identity still exists: %s", rs.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckAWSSESDomainIdentityExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Attributes["verification_token"] == "" {
+			return fmt.Errorf("No verification token is set")
+		}
+
+		v, err := testAccProvider.sesconn.IdentityVerification(rs.ID)
+		if err != nil {
+			return err
+		}
+		if v == nil {
+			return fmt.Errorf("SES domain identity not found: %s", rs.ID)
+		}
+		if v.VerificationToken != rs.Attributes["verification_token"] {
+			return fmt.Errorf("Bad verification token: %s", v.VerificationToken)
+		}
+
+		return nil
+	}
+}
+
+const testAccAWSSESDomainIdentityConfig = `
+resource "aws_ses_domain_identity" "foo" {
+	domain = "terraform-test.example.com"
+}
+
+resource "aws_ses_domain_dkim" "foo" {
+	domain = "${aws_ses_domain_identity.foo.domain}"
+}
+`
@@ -47,6 +47,7 @@ func resource_aws_subnet_create(
 		Target:  "available",
 		Refresh: SubnetStateRefreshFunc(ec2conn, s.ID),
 		Timeout: 10 * time.Minute,
+		StopCh:  p.StopCh(),
 	}
 	subnetRaw, err := stateConf.WaitForState()
 	if err != nil {
@@ -91,6 +92,7 @@ func resource_aws_subnet_destroy(
 		Target:  "",
 		Refresh: SubnetStateRefreshFunc(ec2conn, s.ID),
 		Timeout: 10 * time.Minute,
+		StopCh:  p.StopCh(),
 	}
 	if _, err := stateConf.WaitForState(); err != nil {
 		return fmt.Errorf(
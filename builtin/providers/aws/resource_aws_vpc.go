@@ -46,6 +46,7 @@ func resource_aws_vpc_create(
 		Target:  "available",
 		Refresh: VPCStateRefreshFunc(ec2conn, s.ID),
 		Timeout: 10 * time.Minute,
+		StopCh:  p.StopCh(),
 	}
 	vpcRaw, err := stateConf.WaitForState()
 	if err != nil {
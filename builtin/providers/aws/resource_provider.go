@@ -1,8 +1,9 @@
 package aws
 
 import (
+	"fmt"
 	"log"
-	"os"
+	"sync"
 
 	"github.com/hashicorp/terraform/helper/config"
 	"github.com/hashicorp/terraform/helper/multierror"
@@ -11,6 +12,7 @@ import (
 	"github.com/mitchellh/goamz/autoscaling"
 	"github.com/mitchellh/goamz/ec2"
 	"github.com/mitchellh/goamz/elb"
+	"github.com/mitchellh/goamz/iam"
 	"github.com/mitchellh/goamz/rds"
 	"github.com/mitchellh/goamz/route53"
 	"github.com/mitchellh/goamz/s3"
@@ -22,9 +24,16 @@ type ResourceProvider struct {
 	ec2conn         *ec2.EC2
 	elbconn         *elb.ELB
 	autoscalingconn *autoscaling.AutoScaling
+	iamconn         *iam.IAM
 	s3conn          *s3.S3
 	rdsconn         *rds.Rds
 	route53         *route53.Route53
+	sesconn         *SES
+
+	// stopCh is closed when the provider is stopped, which ends the
+	// waits of its resources for AWS to finish a change.
+	stopCh   chan struct{}
+	stopLock sync.Mutex
 
 	// This is the schema.Provider. Eventually this will replace much
 	// of this structure. For now it is an element of it for compatiblity.
@@ -32,31 +41,7 @@ type ResourceProvider struct {
 }
 
 func (p *ResourceProvider) Validate(c *terraform.ResourceConfig) ([]string, []error) {
-	type param struct {
-		env string
-		key string
-	}
-	params := []param{
-		{"AWS_REGION", "region"},
-		{"AWS_ACCESS_KEY", "access_key"},
-		{"AWS_SECRET_KEY", "secret_key"},
-	}
-
-	var optional []string
-	var required []string
-	for _, p := range params {
-		if v := os.Getenv(p.env); v != "" {
-			optional = append(optional, p.key)
-		} else {
-			required = append(required, p.key)
-		}
-	}
-
-	v := &config.Validator{
-		Required: required,
-		Optional: optional,
-	}
-	return v.Validate(c)
+	return config.NewConfigSchemaValidator(p.ConfigSchema()).Validate(c)
 }
 
 func (p *ResourceProvider) ValidateResource(
@@ -96,12 +81,21 @@ func (p *ResourceProvider) Configure(c *terraform.ResourceConfig) error {
 		p.elbconn = elb.New(auth, region)
 		log.Println("[INFO] Initializing AutoScaling connection")
 		p.autoscalingconn = autoscaling.New(auth, region)
+		log.Println("[INFO] Initializing IAM connection")
+		p.iamconn = iam.New(auth, region)
 		log.Println("[INFO] Initializing S3 connection")
 		p.s3conn = s3.New(auth, region)
 		log.Println("[INFO] Initializing RDS connection")
 		p.rdsconn = rds.New(auth, region)
 		log.Println("[INFO] Initializing Route53 connection")
 		p.route53 = route53.New(auth, region)
+		log.Println("[INFO] Initializing SES connection")
+		p.sesconn = &SES{
+			Endpoint:  SESEndpoint(region.Name),
+			AccessKey: auth.AccessKey,
+			SecretKey: auth.SecretKey,
+			Token:     auth.Token,
+		}
 	}
 
 	if len(errs) > 0 {
@@ -115,6 +109,85 @@ func (p *ResourceProvider) Configure(c *terraform.ResourceConfig) error {
 	return nil
 }
 
+func (p *ResourceProvider) ValidateCredentials() error {
+	// Looking up an instance that can't exist is cheap, and fails if the
+	// credentials are wrong or expired.
+	filter := ec2.NewFilter()
+	filter.Add("instance-id", "i-00000000")
+	if _, err := p.ec2conn.Instances(nil, filter); err != nil {
+		return fmt.Errorf("Error calling the EC2 API: %s", err)
+	}
+
+	return nil
+}
+
+func (p *ResourceProvider) WriteOnlyConfig() []string {
+	// The keys are read from the environment when a saved plan is
+	// applied, which Validate allows.
+	return []string{"access_key", "secret_key"}
+}
+
+func (p *ResourceProvider) ConfigSchema() []terraform.ConfigAttribute {
+	return []terraform.ConfigAttribute{
+		terraform.ConfigAttribute{
+			Name:      "access_key",
+			Required:  true,
+			WriteOnly: true,
+			EnvVars:   []string{"AWS_ACCESS_KEY_ID", "AWS_ACCESS_KEY"},
+		},
+		terraform.ConfigAttribute{
+			Name:     "region",
+			Required: true,
+			EnvVars:  []string{"AWS_REGION"},
+		},
+		terraform.ConfigAttribute{
+			Name:      "secret_key",
+			Required:  true,
+			WriteOnly: true,
+			EnvVars:   []string{"AWS_SECRET_ACCESS_KEY", "AWS_SECRET_KEY"},
+		},
+	}
+}
+
+func (p *ResourceProvider) Stop() error {
+	// The API calls themselves can't be canceled, but they're short.
+	// It's the waits for changes to finish that take long.
+	p.stopLock.Lock()
+	defer p.stopLock.Unlock()
+
+	if p.stopCh == nil {
+		p.stopCh = make(chan struct{})
+	}
+	select {
+	case <-p.stopCh:
+	default:
+		close(p.stopCh)
+	}
+
+	return nil
+}
+
+// StopCh returns the channel that is closed when the provider is
+// stopped, for the StopCh of the StateChangeConfs of its resources.
+func (p *ResourceProvider) StopCh() <-chan struct{} {
+	p.stopLock.Lock()
+	defer p.stopLock.Unlock()
+
+	if p.stopCh == nil {
+		p.stopCh = make(chan struct{})
+	}
+
+	return p.stopCh
+}
+
+func (p *ResourceProvider) Capabilities() terraform.ResourceProviderCapabilities {
+	return terraform.ResourceProviderCapabilities{
+		ListResources:       Provider().Capabilities().ListResources,
+		ValidateCredentials: true,
+		Stop:                true,
+	}
+}
+
 func (p *ResourceProvider) Apply(
 	s *terraform.ResourceState,
 	d *terraform.ResourceDiff) (*terraform.ResourceState, error) {
@@ -144,6 +217,15 @@ func (p *ResourceProvider) Refresh(
 	return resourceMap.Refresh(s, p)
 }
 
+func (p *ResourceProvider) ListResources(
+	t string, filters map[string]string) ([]*terraform.ResourceState, error) {
+	if _, ok := p.p.ResourcesMap[t]; ok {
+		return p.p.ListResources(t, filters)
+	}
+
+	return nil, terraform.ErrListNotSupported
+}
+
 func (p *ResourceProvider) Resources() []terraform.ResourceType {
 	result := resourceMap.Resources()
 	result = append(result, Provider().Resources()...)
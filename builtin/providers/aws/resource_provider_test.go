@@ -81,6 +81,33 @@ func TestResourceProvider_ConfigureBadRegion(t *testing.T) {
 	}
 }
 
+func TestResourceProvider_Stop(t *testing.T) {
+	rp := new(ResourceProvider)
+	if !rp.Capabilities().Stop {
+		t.Fatal("should support stop")
+	}
+
+	stopCh := rp.StopCh()
+	select {
+	case <-stopCh:
+		t.Fatal("should not be stopped")
+	default:
+	}
+
+	// Stopping twice is fine
+	for i := 0; i < 2; i++ {
+		if err := rp.Stop(); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	select {
+	case <-stopCh:
+	default:
+		t.Fatal("should be stopped")
+	}
+}
+
 func testAccPreCheck(t *testing.T) {
 	if v := os.Getenv("AWS_ACCESS_KEY"); v == "" {
 		t.Fatal("AWS_ACCESS_KEY must be set for acceptance tests")
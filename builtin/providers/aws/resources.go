@@ -118,6 +118,7 @@ func init() {
 				Destroy:         resource_aws_s3_bucket_destroy,
 				Diff:            resource_aws_s3_bucket_diff,
 				Refresh:         resource_aws_s3_bucket_refresh,
+				Update:          resource_aws_s3_bucket_update,
 			},
 
 			"aws_subnet": resource.Resource{
@@ -0,0 +1,200 @@
+package aws
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SES is a client for the parts of the Simple Email Service API that
+// domain identities are managed with. goamz has no SES support.
+type SES struct {
+	Endpoint  string
+	AccessKey string
+	SecretKey string
+	Token     string
+
+	HTTPClient *http.Client
+}
+
+// SESError is an error returned by the SES API.
+type SESError struct {
+	StatusCode int
+	Code       string `xml:"Error>Code"`
+	Message    string `xml:"Error>Message"`
+}
+
+func (e *SESError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// SESEndpoint returns the SES endpoint of the region.
+func SESEndpoint(region string) string {
+	return fmt.Sprintf("https://email.%s.amazonaws.com", region)
+}
+
+// SESIdentityVerification is the verification state of an identity.
+type SESIdentityVerification struct {
+	VerificationStatus string
+	VerificationToken  string
+}
+
+// SESIdentityDkim is the DKIM state of an identity.
+type SESIdentityDkim struct {
+	DkimEnabled            bool
+	DkimVerificationStatus string
+	DkimTokens             []string `xml:"DkimTokens>member"`
+}
+
+// VerifyDomainIdentity starts the verification of the domain and returns
+// the token to publish in a TXT record.
+func (c *SES) VerifyDomainIdentity(domain string) (string, error) {
+	var resp struct {
+		Token string `xml:"VerifyDomainIdentityResult>VerificationToken"`
+	}
+
+	params := url.Values{}
+	params.Set("Domain", domain)
+	if err := c.query("VerifyDomainIdentity", params, &resp); err != nil {
+		return "", err
+	}
+
+	return resp.Token, nil
+}
+
+// IdentityVerification returns the verification state of the identity,
+// or nil if SES doesn't know the identity.
+func (c *SES) IdentityVerification(identity string) (*SESIdentityVerification, error) {
+	var resp struct {
+		Entries []struct {
+			Key   string                  `xml:"key"`
+			Value SESIdentityVerification `xml:"value"`
+		} `xml:"GetIdentityVerificationAttributesResult>VerificationAttributes>entry"`
+	}
+
+	params := url.Values{}
+	params.Set("Identities.member.1", identity)
+	err := c.query("GetIdentityVerificationAttributes", params, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range resp.Entries {
+		if e.Key == identity {
+			return &e.Value, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// VerifyDomainDkim generates DKIM tokens for the domain and returns them.
+// A CNAME record of each token has to be published to verify them.
+func (c *SES) VerifyDomainDkim(domain string) ([]string, error) {
+	var resp struct {
+		Tokens []string `xml:"VerifyDomainDkimResult>DkimTokens>member"`
+	}
+
+	params := url.Values{}
+	params.Set("Domain", domain)
+	if err := c.query("VerifyDomainDkim", params, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Tokens, nil
+}
+
+// IdentityDkim returns the DKIM state of the identity, or nil if SES
+// doesn't know the identity.
+func (c *SES) IdentityDkim(identity string) (*SESIdentityDkim, error) {
+	var resp struct {
+		Entries []struct {
+			Key   string          `xml:"key"`
+			Value SESIdentityDkim `xml:"value"`
+		} `xml:"GetIdentityDkimAttributesResult>DkimAttributes>entry"`
+	}
+
+	params := url.Values{}
+	params.Set("Identities.member.1", identity)
+	if err := c.query("GetIdentityDkimAttributes", params, &resp); err != nil {
+		return nil, err
+	}
+
+	for _, e := range resp.Entries {
+		if e.Key == identity {
+			return &e.Value, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// DeleteIdentity deletes the identity, along with its DKIM tokens.
+func (c *SES) DeleteIdentity(identity string) error {
+	params := url.Values{}
+	params.Set("Identity", identity)
+
+	return c.query("DeleteIdentity", params, nil)
+}
+
+func (c *SES) query(action string, params url.Values, result interface{}) error {
+	params.Set("Action", action)
+
+	req, err := http.NewRequest(
+		"POST", c.Endpoint+"/", strings.NewReader(params.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	c.sign(req, time.Now())
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		sesErr := &SESError{StatusCode: resp.StatusCode}
+		if err := xml.NewDecoder(resp.Body).Decode(sesErr); err != nil {
+			sesErr.Code = resp.Status
+		}
+
+		return sesErr
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return xml.NewDecoder(resp.Body).Decode(result)
+}
+
+// sign signs the request with the AWS3-HTTPS scheme SES uses, which is
+// an HMAC of the Date header.
+func (c *SES) sign(req *http.Request, t time.Time) {
+	date := t.UTC().Format(http.TimeFormat)
+
+	h := hmac.New(sha256.New, []byte(c.SecretKey))
+	h.Write([]byte(date))
+	signature := base64.StdEncoding.EncodeToString(h.Sum(nil))
+
+	req.Header.Set("Date", date)
+	req.Header.Set("X-Amzn-Authorization", fmt.Sprintf(
+		"AWS3-HTTPS AWSAccessKeyId=%s, Algorithm=HmacSHA256, Signature=%s",
+		c.AccessKey, signature))
+	if c.Token != "" {
+		req.Header.Set("X-Amz-Security-Token", c.Token)
+	}
+}
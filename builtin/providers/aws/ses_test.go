@@ -0,0 +1,229 @@
+package aws
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSES(t *testing.T) {
+	var actions []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amzn-Authorization") == "" {
+			w.WriteHeader(403)
+			fmt.Fprint(w, testSESErrorResponse)
+			return
+		}
+
+		r.ParseForm()
+		actions = append(actions, r.Form.Get("Action"))
+		switch r.Form.Get("Action") {
+		case "VerifyDomainIdentity":
+			if r.Form.Get("Domain") != "example.com" {
+				t.Errorf("bad: %#v", r.Form)
+			}
+			fmt.Fprint(w, testSESVerifyDomainIdentityResponse)
+		case "GetIdentityVerificationAttributes":
+			if r.Form.Get("Identities.member.1") == "example.com" {
+				fmt.Fprint(w, testSESVerificationAttributesResponse)
+			} else {
+				fmt.Fprint(w, testSESEmptyVerificationAttributesResponse)
+			}
+		case "VerifyDomainDkim":
+			fmt.Fprint(w, testSESVerifyDomainDkimResponse)
+		case "GetIdentityDkimAttributes":
+			fmt.Fprint(w, testSESDkimAttributesResponse)
+		case "DeleteIdentity":
+			if r.Form.Get("Identity") != "example.com" {
+				t.Errorf("bad: %#v", r.Form)
+			}
+			fmt.Fprint(w, `<DeleteIdentityResponse/>`)
+		}
+	}))
+	defer ts.Close()
+
+	c := &SES{Endpoint: ts.URL, AccessKey: "foo", SecretKey: "bar"}
+
+	token, err := c.VerifyDomainIdentity("example.com")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if token != "QTKknzFg2J4ygwa+XvHAxUl1hyHoY0gVfZdfjIedHZ0=" {
+		t.Fatalf("bad: %s", token)
+	}
+
+	v, err := c.IdentityVerification("example.com")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := &SESIdentityVerification{
+		VerificationStatus: "Pending",
+		VerificationToken:  "QTKknzFg2J4ygwa+XvHAxUl1hyHoY0gVfZdfjIedHZ0=",
+	}
+	if !reflect.DeepEqual(v, expected) {
+		t.Fatalf("bad: %#v", v)
+	}
+
+	v, err = c.IdentityVerification("example.org")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if v != nil {
+		t.Fatalf("bad: %#v", v)
+	}
+
+	tokens, err := c.VerifyDomainDkim("example.com")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !reflect.DeepEqual(tokens, []string{"abc", "def", "ghi"}) {
+		t.Fatalf("bad: %#v", tokens)
+	}
+
+	dkim, err := c.IdentityDkim("example.com")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !dkim.DkimEnabled ||
+		dkim.DkimVerificationStatus != "Pending" ||
+		!reflect.DeepEqual(dkim.DkimTokens, []string{"abc", "def", "ghi"}) {
+		t.Fatalf("bad: %#v", dkim)
+	}
+
+	if err := c.DeleteIdentity("example.com"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expectedActions := []string{
+		"VerifyDomainIdentity",
+		"GetIdentityVerificationAttributes",
+		"GetIdentityVerificationAttributes",
+		"VerifyDomainDkim",
+		"GetIdentityDkimAttributes",
+		"DeleteIdentity",
+	}
+	if !reflect.DeepEqual(actions, expectedActions) {
+		t.Fatalf("bad: %#v", actions)
+	}
+}
+
+func TestSES_error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(403)
+		fmt.Fprint(w, testSESErrorResponse)
+	}))
+	defer ts.Close()
+
+	c := &SES{Endpoint: ts.URL}
+
+	err := c.DeleteIdentity("example.com")
+	sesErr, ok := err.(*SESError)
+	if !ok {
+		t.Fatalf("bad: %#v", err)
+	}
+	if sesErr.StatusCode != 403 || sesErr.Code != "InvalidClientTokenId" {
+		t.Fatalf("bad: %#v", sesErr)
+	}
+}
+
+func TestSES_sign(t *testing.T) {
+	c := &SES{AccessKey: "foo", SecretKey: "bar", Token: "baz"}
+
+	req, _ := http.NewRequest("POST", "https://email.us-east-1.amazonaws.com/", nil)
+	c.sign(req, time.Date(2014, 10, 1, 12, 0, 0, 0, time.UTC))
+
+	if v := req.Header.Get("Date"); v != "Wed, 01 Oct 2014 12:00:00 GMT" {
+		t.Fatalf("bad: %s", v)
+	}
+	if v := req.Header.Get("X-Amz-Security-Token"); v != "baz" {
+		t.Fatalf("bad: %s", v)
+	}
+
+	expected := "AWS3-HTTPS AWSAccessKeyId=foo, Algorithm=HmacSHA256, " +
+		"Signature=QggwrSk/j/ZcLbXsHaBzDqCn/t9f4RGW/X35pwYcsUU="
+	if v := req.Header.Get("X-Amzn-Authorization"); v != expected {
+		t.Fatalf("bad: %s", v)
+	}
+}
+
+const testSESVerifyDomainIdentityResponse = `
+<VerifyDomainIdentityResponse xmlns="http://ses.amazonaws.com/doc/2010-12-01/">
+  <VerifyDomainIdentityResult>
+    <VerificationToken>QTKknzFg2J4ygwa+XvHAxUl1hyHoY0gVfZdfjIedHZ0=</VerificationToken>
+  </VerifyDomainIdentityResult>
+  <ResponseMetadata>
+    <RequestId>fbb1f1d8-7b87-11e1-8c3e-4f6b1d8a6a1c</RequestId>
+  </ResponseMetadata>
+</VerifyDomainIdentityResponse>
+`
+
+const testSESVerificationAttributesResponse = `
+<GetIdentityVerificationAttributesResponse xmlns="http://ses.amazonaws.com/doc/2010-12-01/">
+  <GetIdentityVerificationAttributesResult>
+    <VerificationAttributes>
+      <entry>
+        <key>example.com</key>
+        <value>
+          <VerificationStatus>Pending</VerificationStatus>
+          <VerificationToken>QTKknzFg2J4ygwa+XvHAxUl1hyHoY0gVfZdfjIedHZ0=</VerificationToken>
+        </value>
+      </entry>
+    </VerificationAttributes>
+  </GetIdentityVerificationAttributesResult>
+</GetIdentityVerificationAttributesResponse>
+`
+
+const testSESEmptyVerificationAttributesResponse = `
+<GetIdentityVerificationAttributesResponse xmlns="http://ses.amazonaws.com/doc/2010-12-01/">
+  <GetIdentityVerificationAttributesResult>
+    <VerificationAttributes/>
+  </GetIdentityVerificationAttributesResult>
+</GetIdentityVerificationAttributesResponse>
+`
+
+const testSESVerifyDomainDkimResponse = `
+<VerifyDomainDkimResponse xmlns="http://ses.amazonaws.com/doc/2010-12-01/">
+  <VerifyDomainDkimResult>
+    <DkimTokens>
+      <member>abc</member>
+      <member>def</member>
+      <member>ghi</member>
+    </DkimTokens>
+  </VerifyDomainDkimResult>
+</VerifyDomainDkimResponse>
+`
+
+const testSESDkimAttributesResponse = `
+<GetIdentityDkimAttributesResponse xmlns="http://ses.amazonaws.com/doc/2010-12-01/">
+  <GetIdentityDkimAttributesResult>
+    <DkimAttributes>
+      <entry>
+        <key>example.com</key>
+        <value>
+          <DkimEnabled>true</DkimEnabled>
+          <DkimVerificationStatus>Pending</DkimVerificationStatus>
+          <DkimTokens>
+            <member>abc</member>
+            <member>def</member>
+            <member>ghi</member>
+          </DkimTokens>
+        </value>
+      </entry>
+    </DkimAttributes>
+  </GetIdentityDkimAttributesResult>
+</GetIdentityDkimAttributesResponse>
+`
+
+const testSESErrorResponse = `
+<ErrorResponse xmlns="http://ses.amazonaws.com/doc/2010-12-01/">
+  <Error>
+    <Type>Sender</Type>
+    <Code>InvalidClientTokenId</Code>
+    <Message>The security token included in the request is invalid.</Message>
+  </Error>
+  <RequestId>42d59b56-7407-4c4a-be0f-4c88daeea257</RequestId>
+</ErrorResponse>
+`
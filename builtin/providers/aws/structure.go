@@ -1,12 +1,15 @@
 package aws
 
 import (
+	"encoding/json"
+	"fmt"
 	"strconv"
 	"strings"
 
 	"github.com/mitchellh/goamz/autoscaling"
 	"github.com/mitchellh/goamz/ec2"
 	"github.com/mitchellh/goamz/elb"
+	"github.com/mitchellh/goamz/rds"
 )
 
 // Takes the result of flatmap.Expand for an array of listeners and
@@ -39,6 +42,30 @@ func expandListeners(configured []interface{}) ([]elb.Listener, error) {
 	return listeners, nil
 }
 
+// Takes the result of flatmap.Expand for a health check and returns
+// an ELB API compatible object
+func expandHealthCheck(configured map[string]interface{}) (elb.HealthCheck, error) {
+	var check elb.HealthCheck
+	ints := map[string]*int64{
+		"healthy_threshold":   &check.HealthyThreshold,
+		"unhealthy_threshold": &check.UnhealthyThreshold,
+		"interval":            &check.Interval,
+		"timeout":             &check.Timeout,
+	}
+	for k, v := range ints {
+		n, err := strconv.ParseInt(configured[k].(string), 0, 0)
+		if err != nil {
+			return check, fmt.Errorf("health_check.%s: %s", k, err)
+		}
+
+		*v = n
+	}
+
+	check.Target = configured["target"].(string)
+
+	return check, nil
+}
+
 // Takes the result of flatmap.Expand for an array of ingress/egress
 // security group rules and returns EC2 API compatible objects
 func expandIPPerms(configured []interface{}) []ec2.IPPerm {
@@ -82,6 +109,29 @@ func expandIPPerms(configured []interface{}) []ec2.IPPerm {
 	return perms
 }
 
+// Takes the result of flatmap.Expand for an array of parameters and
+// returns RDS API compatible objects
+func expandParameters(configured []interface{}) []rds.Parameter {
+	parameters := make([]rds.Parameter, 0, len(configured))
+
+	for _, pRaw := range configured {
+		data := pRaw.(map[string]interface{})
+
+		p := rds.Parameter{
+			ApplyMethod:    "immediate",
+			ParameterName:  data["name"].(string),
+			ParameterValue: data["value"].(string),
+		}
+		if v, ok := data["apply_method"]; ok && v.(string) != "" {
+			p.ApplyMethod = v.(string)
+		}
+
+		parameters = append(parameters, p)
+	}
+
+	return parameters
+}
+
 // Flattens an array of ipPerms into a list of primitives that
 // flatmap.Flatten() can handle
 func flattenIPPerms(list []ec2.IPPerm) []map[string]interface{} {
@@ -124,6 +174,20 @@ func flattenHealthCheck(check elb.HealthCheck) []map[string]interface{} {
 	return result
 }
 
+// Flattens an array of Parameters into a list of primitives that
+// flatmap.Flatten() can handle
+func flattenParameters(list []rds.Parameter) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(list))
+	for _, p := range list {
+		result = append(result, map[string]interface{}{
+			"name":         p.ParameterName,
+			"value":        p.ParameterValue,
+			"apply_method": p.ApplyMethod,
+		})
+	}
+	return result
+}
+
 // Flattens an array of UserSecurityGroups into a []string
 func flattenSecurityGroups(list []ec2.UserSecurityGroup) []string {
 	result := make([]string, 0, len(list))
@@ -188,3 +252,77 @@ func expandStringList(configured []interface{}) []string {
 	}
 	return vs
 }
+
+// Returns the values to add to and remove from the list o to get the
+// list n, such as the instances to register with a load balancer
+func diffStringLists(o, n []string) (add []string, remove []string) {
+	inO := make(map[string]struct{}, len(o))
+	for _, v := range o {
+		inO[v] = struct{}{}
+	}
+	inN := make(map[string]struct{}, len(n))
+	for _, v := range n {
+		inN[v] = struct{}{}
+	}
+
+	for _, v := range n {
+		if _, ok := inO[v]; !ok {
+			add = append(add, v)
+		}
+	}
+	for _, v := range o {
+		if _, ok := inN[v]; !ok {
+			remove = append(remove, v)
+		}
+	}
+
+	return add, remove
+}
+
+// Returns a policy document in a normal form, so that documents that only
+// differ in formatting, the order of their keys, or lists of one element
+// written as the element itself, such as AWS may return them, compare
+// equal. Documents that aren't valid JSON are returned as is.
+func normalizePolicy(policy string) string {
+	var v interface{}
+	if err := json.Unmarshal([]byte(policy), &v); err != nil {
+		return policy
+	}
+
+	result, err := json.Marshal(normalizePolicyValue(v))
+	if err != nil {
+		return policy
+	}
+
+	return string(result)
+}
+
+// Normalizes a policy document for the state, as the StateFunc of the
+// policy fields of schema resources
+func normalizePolicyState(v interface{}) string {
+	switch v.(type) {
+	case string:
+		return normalizePolicy(v.(string))
+	default:
+		return ""
+	}
+}
+
+func normalizePolicyValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case []interface{}:
+		if len(t) == 1 {
+			return normalizePolicyValue(t[0])
+		}
+
+		for i, e := range t {
+			t[i] = normalizePolicyValue(e)
+		}
+	case map[string]interface{}:
+		for k, e := range t {
+			t[k] = normalizePolicyValue(e)
+		}
+	}
+
+	return v
+}
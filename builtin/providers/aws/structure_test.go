@@ -7,6 +7,7 @@ import (
 	"github.com/hashicorp/terraform/flatmap"
 	"github.com/mitchellh/goamz/ec2"
 	"github.com/mitchellh/goamz/elb"
+	"github.com/mitchellh/goamz/rds"
 )
 
 // Returns test configuration
@@ -174,6 +175,44 @@ func Test_expandListeners(t *testing.T) {
 
 }
 
+func Test_expandHealthCheck(t *testing.T) {
+	check, err := expandHealthCheck(map[string]interface{}{
+		"healthy_threshold":   "10",
+		"unhealthy_threshold": "2",
+		"interval":            "30",
+		"timeout":             "5",
+		"target":              "HTTP:80/",
+	})
+	if err != nil {
+		t.Fatalf("bad: %#v", err)
+	}
+
+	expected := elb.HealthCheck{
+		HealthyThreshold:   10,
+		UnhealthyThreshold: 2,
+		Interval:           30,
+		Timeout:            5,
+		Target:             "HTTP:80/",
+	}
+	if !reflect.DeepEqual(check, expected) {
+		t.Fatalf(
+			"Got:\n\n%#v\n\nExpected:\n\n%#v\n",
+			check,
+			expected)
+	}
+
+	_, err = expandHealthCheck(map[string]interface{}{
+		"healthy_threshold":   "10",
+		"unhealthy_threshold": "foo",
+		"interval":            "30",
+		"timeout":             "5",
+		"target":              "HTTP:80/",
+	})
+	if err == nil {
+		t.Fatal("should error")
+	}
+}
+
 func Test_flattenHealthCheck(t *testing.T) {
 	cases := []struct {
 		Input  elb.HealthCheck
@@ -207,6 +246,72 @@ func Test_flattenHealthCheck(t *testing.T) {
 	}
 }
 
+func Test_expandParameters(t *testing.T) {
+	expanded := []interface{}{
+		map[string]interface{}{
+			"name":  "character_set_client",
+			"value": "utf8",
+		},
+		map[string]interface{}{
+			"name":         "max_connections",
+			"value":        "500",
+			"apply_method": "pending-reboot",
+		},
+	}
+	parameters := expandParameters(expanded)
+
+	expected := []rds.Parameter{
+		rds.Parameter{
+			ApplyMethod:    "immediate",
+			ParameterName:  "character_set_client",
+			ParameterValue: "utf8",
+		},
+		rds.Parameter{
+			ApplyMethod:    "pending-reboot",
+			ParameterName:  "max_connections",
+			ParameterValue: "500",
+		},
+	}
+
+	if !reflect.DeepEqual(parameters, expected) {
+		t.Fatalf(
+			"Got:\n\n%#v\n\nExpected:\n\n%#v\n",
+			parameters,
+			expected)
+	}
+}
+
+func Test_flattenParameters(t *testing.T) {
+	cases := []struct {
+		Input  []rds.Parameter
+		Output []map[string]interface{}
+	}{
+		{
+			Input: []rds.Parameter{
+				rds.Parameter{
+					ApplyMethod:    "immediate",
+					ParameterName:  "character_set_client",
+					ParameterValue: "utf8",
+				},
+			},
+			Output: []map[string]interface{}{
+				map[string]interface{}{
+					"name":         "character_set_client",
+					"value":        "utf8",
+					"apply_method": "immediate",
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		output := flattenParameters(tc.Input)
+		if !reflect.DeepEqual(output, tc.Output) {
+			t.Fatalf("Got:\n\n%#v\n\nExpected:\n\n%#v", output, tc.Output)
+		}
+	}
+}
+
 func Test_expandStringList(t *testing.T) {
 	expanded := flatmap.Expand(testConf(), "availability_zones").([]interface{})
 	stringList := expandStringList(expanded)
@@ -239,3 +344,75 @@ func Test_expandStringListWildcard(t *testing.T) {
 	}
 
 }
+
+func Test_diffStringLists(t *testing.T) {
+	cases := []struct {
+		Old    []string
+		New    []string
+		Add    []string
+		Remove []string
+	}{
+		{
+			Old:    nil,
+			New:    []string{"i-1", "i-2"},
+			Add:    []string{"i-1", "i-2"},
+			Remove: nil,
+		},
+		{
+			Old:    []string{"i-1", "i-2"},
+			New:    []string{"i-2", "i-3"},
+			Add:    []string{"i-3"},
+			Remove: []string{"i-1"},
+		},
+		{
+			Old:    []string{"i-1", "i-2"},
+			New:    nil,
+			Add:    nil,
+			Remove: []string{"i-1", "i-2"},
+		},
+	}
+
+	for i, tc := range cases {
+		add, remove := diffStringLists(tc.Old, tc.New)
+		if !reflect.DeepEqual(add, tc.Add) {
+			t.Fatalf("%d: add: %#v", i, add)
+		}
+		if !reflect.DeepEqual(remove, tc.Remove) {
+			t.Fatalf("%d: remove: %#v", i, remove)
+		}
+	}
+}
+
+func Test_normalizePolicy(t *testing.T) {
+	cases := []struct {
+		Input  string
+		Output string
+	}{
+		{
+			`{"Version": "2012-10-17", "Statement": []}`,
+			`{"Statement":[],"Version":"2012-10-17"}`,
+		},
+		{
+			`{
+				"Statement": [{
+					"Effect": "Allow",
+					"Action": ["s3:GetObject"],
+					"Resource": ["arn:aws:s3:::foo/*", "arn:aws:s3:::bar/*"]
+				}]
+			}`,
+			`{"Statement":{"Action":"s3:GetObject","Effect":"Allow",` +
+				`"Resource":["arn:aws:s3:::foo/*","arn:aws:s3:::bar/*"]}}`,
+		},
+		{
+			`not json`,
+			`not json`,
+		},
+	}
+
+	for i, tc := range cases {
+		actual := normalizePolicy(tc.Input)
+		if actual != tc.Output {
+			t.Fatalf("%d: bad: %s", i, actual)
+		}
+	}
+}
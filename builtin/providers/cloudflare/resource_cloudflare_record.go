@@ -116,6 +116,42 @@ func resource_cloudflare_record_refresh(
 	return resource_cloudflare_record_update_state(s, rec)
 }
 
+func resource_cloudflare_record_list(
+	filters map[string]string,
+	meta interface{}) ([]*terraform.ResourceState, error) {
+	p := meta.(*ResourceProvider)
+	client := p.client
+
+	// Records can only be listed by domain, which can have hundreds of
+	// them, so they're all read at once instead of refreshed one by one.
+	domain := filters["domain"]
+	if domain == "" {
+		return nil, fmt.Errorf("Records can only be listed with a domain filter")
+	}
+
+	records, err := client.RetrieveRecords(domain)
+	if err != nil {
+		return nil, fmt.Errorf("Error retrieving records: %s", err)
+	}
+
+	result := make([]*terraform.ResourceState, 0, len(records))
+	for i, _ := range records {
+		s := &terraform.ResourceState{
+			ID: records[i].Id,
+			Attributes: map[string]string{
+				"domain": domain,
+			},
+		}
+		if _, err := resource_cloudflare_record_update_state(s, &records[i]); err != nil {
+			return nil, err
+		}
+
+		result = append(result, s)
+	}
+
+	return result, nil
+}
+
 func resource_cloudflare_record_diff(
 	s *terraform.ResourceState,
 	c *terraform.ResourceConfig,
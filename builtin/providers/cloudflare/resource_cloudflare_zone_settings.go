@@ -0,0 +1,155 @@
+package cloudflare
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/config"
+	"github.com/hashicorp/terraform/helper/diff"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// zoneSettings are the settings of a zone that can be configured, and
+// whether their values are numbers.
+var zoneSettings = map[string]bool{
+	"browser_cache_ttl": true,
+	"cache_level":       false,
+	"ssl":               false,
+}
+
+func resource_cloudflare_zone_settings_create(
+	s *terraform.ResourceState,
+	d *terraform.ResourceDiff,
+	meta interface{}) (*terraform.ResourceState, error) {
+	p := meta.(*ResourceProvider)
+	client := p.zoneSettings
+
+	rs := s.MergeDiff(d)
+
+	// The zone already exists, it's only its settings that we manage
+	id, err := client.ZoneId(rs.Attributes["domain"])
+	if err != nil {
+		return nil, fmt.Errorf("Error finding zone: %s", err)
+	}
+
+	rs.ID = id
+	log.Printf("[INFO] Zone ID: %s", rs.ID)
+
+	if err := resource_cloudflare_zone_settings_apply(rs, d, client); err != nil {
+		return rs, err
+	}
+
+	return resource_cloudflare_zone_settings_refresh(rs, meta)
+}
+
+func resource_cloudflare_zone_settings_update(
+	s *terraform.ResourceState,
+	d *terraform.ResourceDiff,
+	meta interface{}) (*terraform.ResourceState, error) {
+	p := meta.(*ResourceProvider)
+	rs := s.MergeDiff(d)
+
+	if err := resource_cloudflare_zone_settings_apply(rs, d, p.zoneSettings); err != nil {
+		return rs, err
+	}
+
+	return resource_cloudflare_zone_settings_refresh(rs, meta)
+}
+
+// resource_cloudflare_zone_settings_apply changes the settings that
+// changed in the diff.
+func resource_cloudflare_zone_settings_apply(
+	rs *terraform.ResourceState,
+	d *terraform.ResourceDiff,
+	client *ZoneSettingsClient) error {
+	for name, number := range zoneSettings {
+		attr, ok := d.Attributes[name]
+		if !ok || attr.NewComputed || attr.New == "" {
+			continue
+		}
+
+		var value interface{} = attr.New
+		if number {
+			n, err := strconv.Atoi(attr.New)
+			if err != nil {
+				return fmt.Errorf("%s: %s", name, err)
+			}
+
+			value = n
+		}
+
+		log.Printf("[DEBUG] Zone %s setting %s: %v", rs.ID, name, value)
+		if err := client.UpdateSetting(rs.ID, name, value); err != nil {
+			return fmt.Errorf("Error updating zone setting %s: %s", name, err)
+		}
+	}
+
+	return nil
+}
+
+func resource_cloudflare_zone_settings_destroy(
+	s *terraform.ResourceState,
+	meta interface{}) error {
+	// Zones always have settings, so they're left as they are
+	log.Printf(
+		"[INFO] Leaving the settings of zone %s as they are", s.ID)
+
+	return nil
+}
+
+func resource_cloudflare_zone_settings_refresh(
+	s *terraform.ResourceState,
+	meta interface{}) (*terraform.ResourceState, error) {
+	p := meta.(*ResourceProvider)
+	client := p.zoneSettings
+
+	settings, err := client.Settings(s.ID)
+	if err != nil {
+		return s, fmt.Errorf("Error retrieving zone settings: %s", err)
+	}
+
+	for name, _ := range zoneSettings {
+		if v, ok := settings[name]; ok {
+			s.Attributes[name] = v
+		}
+	}
+
+	return s, nil
+}
+
+func resource_cloudflare_zone_settings_diff(
+	s *terraform.ResourceState,
+	c *terraform.ResourceConfig,
+	meta interface{}) (*terraform.ResourceDiff, error) {
+
+	b := &diff.ResourceBuilder{
+		Attrs: map[string]diff.AttrType{
+			"domain":            diff.AttrTypeCreate,
+			"browser_cache_ttl": diff.AttrTypeUpdate,
+			"cache_level":       diff.AttrTypeUpdate,
+			"ssl":               diff.AttrTypeUpdate,
+		},
+
+		ComputedAttrs: []string{
+			"browser_cache_ttl",
+			"cache_level",
+			"ssl",
+		},
+	}
+
+	return b.Diff(s, c)
+}
+
+func resource_cloudflare_zone_settings_validation() *config.Validator {
+	return &config.Validator{
+		Required: []string{
+			"domain",
+		},
+		Optional: []string{
+			"browser_cache_ttl",
+			"cache_level",
+			"ssl",
+		},
+	}
+}
@@ -0,0 +1,96 @@
+package cloudflare
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccCloudflareZoneSettings_Updated(t *testing.T) {
+	domain := os.Getenv("CLOUDFLARE_DOMAIN")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+
+		// Destroying the resource leaves the settings of the zone as they
+		// are, so there is no CheckDestroy.
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: fmt.Sprintf(testAccCheckCloudflareZoneSettingsConfig_basic, domain),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudflareZoneSettings("cloudflare_zone_settings.foobar", map[string]string{
+						"browser_cache_ttl": "14400",
+						"cache_level":       "aggressive",
+					}),
+					resource.TestCheckResourceAttr(
+						"cloudflare_zone_settings.foobar", "domain", domain),
+					resource.TestCheckResourceAttr(
+						"cloudflare_zone_settings.foobar", "cache_level", "aggressive"),
+				),
+			},
+			resource.TestStep{
+				Config: fmt.Sprintf(testAccCheckCloudflareZoneSettingsConfig_new_value, domain),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudflareZoneSettings("cloudflare_zone_settings.foobar", map[string]string{
+						"browser_cache_ttl": "7200",
+						"cache_level":       "simplified",
+					}),
+					resource.TestCheckResourceAttr(
+						"cloudflare_zone_settings.foobar", "browser_cache_ttl", "7200"),
+					resource.TestCheckResourceAttr(
+						"cloudflare_zone_settings.foobar", "cache_level", "simplified"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckCloudflareZoneSettings(n string, expected map[string]string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.Resources[n]
+
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.ID == "" {
+			return fmt.Errorf("No Zone ID is set")
+		}
+
+		client := testAccProvider.zoneSettings
+
+		settings, err := client.Settings(rs.ID)
+
+		if err != nil {
+			return err
+		}
+
+		for k, v := range expected {
+			if settings[k] != v {
+				return fmt.Errorf("Bad %s: %s", k, settings[k])
+			}
+		}
+
+		return nil
+	}
+}
+
+const testAccCheckCloudflareZoneSettingsConfig_basic = `
+resource "cloudflare_zone_settings" "foobar" {
+	domain = "%s"
+
+	browser_cache_ttl = 14400
+	cache_level = "aggressive"
+}`
+
+const testAccCheckCloudflareZoneSettingsConfig_new_value = `
+resource "cloudflare_zone_settings" "foobar" {
+	domain = "%s"
+
+	browser_cache_ttl = 7200
+	cache_level = "simplified"
+}`
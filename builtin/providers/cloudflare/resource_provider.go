@@ -11,18 +11,12 @@ import (
 type ResourceProvider struct {
 	Config Config
 
-	client *cloudflare.Client
+	client       *cloudflare.Client
+	zoneSettings *ZoneSettingsClient
 }
 
 func (p *ResourceProvider) Validate(c *terraform.ResourceConfig) ([]string, []error) {
-	v := &config.Validator{
-		Required: []string{
-			"token",
-			"email",
-		},
-	}
-
-	return v.Validate(c)
+	return config.NewConfigSchemaValidator(p.ConfigSchema()).Validate(c)
 }
 
 func (p *ResourceProvider) ValidateResource(
@@ -43,9 +37,52 @@ func (p *ResourceProvider) Configure(c *terraform.ResourceConfig) error {
 		return err
 	}
 
+	p.zoneSettings = &ZoneSettingsClient{
+		Email: p.Config.Email,
+		Token: p.Config.Token,
+	}
+
+	return nil
+}
+
+func (p *ResourceProvider) ValidateCredentials() error {
+	// The CloudFlare client has no call that is cheap enough to check the
+	// credentials with, so they're checked by the first real request.
 	return nil
 }
 
+func (p *ResourceProvider) WriteOnlyConfig() []string {
+	return []string{"token"}
+}
+
+func (p *ResourceProvider) ConfigSchema() []terraform.ConfigAttribute {
+	return []terraform.ConfigAttribute{
+		terraform.ConfigAttribute{
+			Name:     "email",
+			Required: true,
+			EnvVars:  []string{"CLOUDFLARE_EMAIL"},
+		},
+		terraform.ConfigAttribute{
+			Name:      "token",
+			Required:  true,
+			WriteOnly: true,
+			EnvVars:   []string{"CLOUDFLARE_TOKEN"},
+		},
+	}
+}
+
+func (p *ResourceProvider) Stop() error {
+	// The API calls of the provider can't be canceled, so they're left
+	// to finish.
+	return nil
+}
+
+func (p *ResourceProvider) Capabilities() terraform.ResourceProviderCapabilities {
+	return terraform.ResourceProviderCapabilities{
+		ListResources: resourceMap.Listable(),
+	}
+}
+
 func (p *ResourceProvider) Apply(
 	s *terraform.ResourceState,
 	d *terraform.ResourceDiff) (*terraform.ResourceState, error) {
@@ -63,6 +100,11 @@ func (p *ResourceProvider) Refresh(
 	return resourceMap.Refresh(s, p)
 }
 
+func (p *ResourceProvider) ListResources(
+	t string, filters map[string]string) ([]*terraform.ResourceState, error) {
+	return resourceMap.List(t, filters, p)
+}
+
 func (p *ResourceProvider) Resources() []terraform.ResourceType {
 	return resourceMap.Resources()
 }
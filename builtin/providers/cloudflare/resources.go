@@ -16,9 +16,19 @@ func init() {
 				Create:          resource_cloudflare_record_create,
 				Destroy:         resource_cloudflare_record_destroy,
 				Diff:            resource_cloudflare_record_diff,
+				List:            resource_cloudflare_record_list,
 				Update:          resource_cloudflare_record_update,
 				Refresh:         resource_cloudflare_record_refresh,
 			},
+
+			"cloudflare_zone_settings": resource.Resource{
+				ConfigValidator: resource_cloudflare_zone_settings_validation(),
+				Create:          resource_cloudflare_zone_settings_create,
+				Destroy:         resource_cloudflare_zone_settings_destroy,
+				Diff:            resource_cloudflare_zone_settings_diff,
+				Update:          resource_cloudflare_zone_settings_update,
+				Refresh:         resource_cloudflare_zone_settings_refresh,
+			},
 		},
 	}
 }
@@ -0,0 +1,134 @@
+package cloudflare
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ZoneSettingsURL is the CloudFlare API that zone settings are read and
+// changed with. The client library only covers DNS records.
+const ZoneSettingsURL = "https://api.cloudflare.com/client/v4"
+
+// ZoneSettingsClient reads and changes the settings of CloudFlare zones.
+type ZoneSettingsClient struct {
+	URL   string
+	Email string
+	Token string
+
+	HTTPClient *http.Client
+}
+
+// ZoneId returns the ID of the zone of the given domain.
+func (c *ZoneSettingsClient) ZoneId(domain string) (string, error) {
+	var zones []struct {
+		Id string `json:"id"`
+	}
+	path := "/zones?name=" + url.QueryEscape(domain)
+	if err := c.request("GET", path, nil, &zones); err != nil {
+		return "", err
+	}
+	if len(zones) == 0 {
+		return "", fmt.Errorf("zone not found: %s", domain)
+	}
+
+	return zones[0].Id, nil
+}
+
+// Settings returns all the settings of the zone, with numbers and other
+// values formatted as strings.
+func (c *ZoneSettingsClient) Settings(zoneId string) (map[string]string, error) {
+	var settings []struct {
+		Id    string      `json:"id"`
+		Value interface{} `json:"value"`
+	}
+	if err := c.request("GET", "/zones/"+zoneId+"/settings", nil, &settings); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string, len(settings))
+	for _, s := range settings {
+		result[s.Id] = fmt.Sprintf("%v", s.Value)
+	}
+
+	return result, nil
+}
+
+// UpdateSetting changes a setting of the zone.
+func (c *ZoneSettingsClient) UpdateSetting(
+	zoneId string, name string, value interface{}) error {
+	body := map[string]interface{}{"value": value}
+	return c.request(
+		"PATCH", "/zones/"+zoneId+"/settings/"+name, body, nil)
+}
+
+func (c *ZoneSettingsClient) request(
+	method string, path string, body interface{}, result interface{}) error {
+	var bodyReader *bytes.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+
+		bodyReader = bytes.NewReader(raw)
+	} else {
+		bodyReader = bytes.NewReader(nil)
+	}
+
+	base := c.URL
+	if base == "" {
+		base = ZoneSettingsURL
+	}
+
+	req, err := http.NewRequest(method, base+path, bodyReader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Auth-Email", c.Email)
+	req.Header.Set("X-Auth-Key", c.Token)
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var envelope struct {
+		Success bool `json:"success"`
+		Errors  []struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"errors"`
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf(
+			"Error decoding CloudFlare response (%s): %s", resp.Status, err)
+	}
+
+	if !envelope.Success {
+		messages := make([]string, 0, len(envelope.Errors))
+		for _, e := range envelope.Errors {
+			messages = append(messages, fmt.Sprintf("%s (%d)", e.Message, e.Code))
+		}
+
+		return fmt.Errorf(
+			"CloudFlare error (%s): %s", resp.Status, strings.Join(messages, ", "))
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return json.Unmarshal(envelope.Result, result)
+}
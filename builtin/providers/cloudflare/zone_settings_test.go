@@ -0,0 +1,90 @@
+package cloudflare
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestZoneSettingsClient(t *testing.T) {
+	var patched string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Auth-Email") != "foo@example.com" ||
+			r.Header.Get("X-Auth-Key") != "secret" {
+			w.WriteHeader(403)
+			fmt.Fprint(w, `{"success":false,"errors":[{"code":9103,"message":"Unknown X-Auth-Key"}]}`)
+			return
+		}
+
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/zones":
+			if r.URL.Query().Get("name") != "example.com" {
+				fmt.Fprint(w, `{"success":true,"result":[]}`)
+				return
+			}
+
+			fmt.Fprint(w, `{"success":true,"result":[{"id":"z1"}]}`)
+		case r.Method == "GET" && r.URL.Path == "/zones/z1/settings":
+			fmt.Fprint(w, `{"success":true,"result":[`+
+				`{"id":"ssl","value":"full"},`+
+				`{"id":"browser_cache_ttl","value":14400}]}`)
+		case r.Method == "PATCH" && r.URL.Path == "/zones/z1/settings/ssl":
+			body, _ := ioutil.ReadAll(r.Body)
+			patched = string(body)
+			fmt.Fprint(w, `{"success":true,"result":{}}`)
+		default:
+			w.WriteHeader(404)
+			fmt.Fprint(w, `{"success":false,"errors":[{"code":7003,"message":"Not found"}]}`)
+		}
+	}))
+	defer ts.Close()
+
+	c := &ZoneSettingsClient{
+		URL:   ts.URL,
+		Email: "foo@example.com",
+		Token: "secret",
+	}
+
+	id, err := c.ZoneId("example.com")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if id != "z1" {
+		t.Fatalf("bad: %s", id)
+	}
+
+	if _, err := c.ZoneId("example.org"); err == nil {
+		t.Fatal("should error")
+	}
+
+	settings, err := c.Settings(id)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := map[string]string{
+		"ssl":               "full",
+		"browser_cache_ttl": "14400",
+	}
+	if !reflect.DeepEqual(settings, expected) {
+		t.Fatalf("bad: %#v", settings)
+	}
+
+	if err := c.UpdateSetting(id, "ssl", "strict"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if patched != `{"value":"strict"}` {
+		t.Fatalf("bad: %s", patched)
+	}
+
+	if err := c.UpdateSetting(id, "foo", "bar"); err == nil {
+		t.Fatal("should error")
+	}
+
+	c.Token = "wrong"
+	if _, err := c.Settings(id); err == nil {
+		t.Fatal("should error")
+	}
+}
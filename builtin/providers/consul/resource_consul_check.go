@@ -0,0 +1,138 @@
+package consul
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/armon/consul-api"
+	"github.com/hashicorp/terraform/helper/config"
+	"github.com/hashicorp/terraform/helper/diff"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func resource_consul_check_validation() *config.Validator {
+	return &config.Validator{
+		Required: []string{
+			"name",
+		},
+		Optional: []string{
+			"check_id",
+			"notes",
+			"script",
+			"interval",
+			"ttl",
+		},
+	}
+}
+
+func resource_consul_check_update(
+	s *terraform.ResourceState,
+	d *terraform.ResourceDiff,
+	meta interface{}) (*terraform.ResourceState, error) {
+	// Registering a check again with the same ID replaces it
+	return resource_consul_check_create(s, d, meta)
+}
+
+func resource_consul_check_create(
+	s *terraform.ResourceState,
+	d *terraform.ResourceDiff,
+	meta interface{}) (*terraform.ResourceState, error) {
+	p := meta.(*ResourceProvider)
+
+	// Merge the diff into the state so that we have all the attributes
+	// properly.
+	rs := s.MergeDiff(d)
+
+	// The ID of the check defaults to its name
+	if aDiff, ok := d.Attributes["check_id"]; ok && aDiff.NewComputed {
+		rs.Attributes["check_id"] = rs.Attributes["name"]
+	}
+
+	script := rs.Attributes["script"]
+	ttl := rs.Attributes["ttl"]
+	interval := rs.Attributes["interval"]
+	switch {
+	case script == "" && ttl == "":
+		return rs, fmt.Errorf("One of script and ttl must be set")
+	case script != "" && ttl != "":
+		return rs, fmt.Errorf("Only one of script and ttl can be set")
+	case script != "" && interval == "":
+		return rs, fmt.Errorf("interval must be set with script")
+	}
+
+	reg := &consulapi.AgentCheckRegistration{
+		ID:    rs.Attributes["check_id"],
+		Name:  rs.Attributes["name"],
+		Notes: rs.Attributes["notes"],
+	}
+	reg.Script = script
+	reg.Interval = interval
+	reg.TTL = ttl
+
+	log.Printf("[DEBUG] Registering check '%s'", reg.ID)
+	if err := p.client.Agent().CheckRegister(reg); err != nil {
+		return rs, fmt.Errorf(
+			"Failed to register Consul check '%s': %v", reg.ID, err)
+	}
+
+	rs.ID = reg.ID
+	return rs, nil
+}
+
+func resource_consul_check_destroy(
+	s *terraform.ResourceState,
+	meta interface{}) error {
+	p := meta.(*ResourceProvider)
+
+	log.Printf("[DEBUG] Deregistering check '%s'", s.ID)
+	if err := p.client.Agent().CheckDeregister(s.ID); err != nil {
+		return fmt.Errorf(
+			"Failed to deregister Consul check '%s': %v", s.ID, err)
+	}
+
+	return nil
+}
+
+func resource_consul_check_diff(
+	s *terraform.ResourceState,
+	c *terraform.ResourceConfig,
+	meta interface{}) (*terraform.ResourceDiff, error) {
+	b := &diff.ResourceBuilder{
+		Attrs: map[string]diff.AttrType{
+			"name":     diff.AttrTypeCreate,
+			"check_id": diff.AttrTypeCreate,
+			"notes":    diff.AttrTypeUpdate,
+			"script":   diff.AttrTypeUpdate,
+			"interval": diff.AttrTypeUpdate,
+			"ttl":      diff.AttrTypeUpdate,
+		},
+
+		ComputedAttrs: []string{
+			"check_id",
+		},
+	}
+
+	return b.Diff(s, c)
+}
+
+func resource_consul_check_refresh(
+	s *terraform.ResourceState,
+	meta interface{}) (*terraform.ResourceState, error) {
+	p := meta.(*ResourceProvider)
+
+	checks, err := p.client.Agent().Checks()
+	if err != nil {
+		return s, fmt.Errorf("Failed to get Consul checks: %v", err)
+	}
+
+	check, ok := checks[s.ID]
+	if !ok {
+		// The check was deregistered outside of Terraform
+		s.ID = ""
+		return s, nil
+	}
+
+	s.Attributes["name"] = check.Name
+	s.Attributes["notes"] = check.Notes
+	return s, nil
+}
@@ -0,0 +1,87 @@
+package consul
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccConsulCheck(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() {},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckConsulCheckDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccConsulCheckConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckConsulCheckExists("consul_check.app", "Checked by TTL"),
+					resource.TestCheckResourceAttr(
+						"consul_check.app", "check_id", "terraform-acc"),
+					resource.TestCheckResourceAttr(
+						"consul_check.app", "ttl", "30s"),
+				),
+			},
+
+			resource.TestStep{
+				Config: testAccConsulCheckConfigUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckConsulCheckExists("consul_check.app", "Updated"),
+					resource.TestCheckResourceAttr(
+						"consul_check.app", "notes", "Updated"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckConsulCheckDestroy(s *terraform.State) error {
+	checks, err := testAccProvider.client.Agent().Checks()
+	if err != nil {
+		return err
+	}
+	if _, ok := checks["terraform-acc"]; ok {
+		return fmt.Errorf("Check still exists")
+	}
+	return nil
+}
+
+func testAccCheckConsulCheckExists(n, notes string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		checks, err := testAccProvider.client.Agent().Checks()
+		if err != nil {
+			return err
+		}
+		check, ok := checks[rs.ID]
+		if !ok {
+			return fmt.Errorf("Check '%s' is not registered", rs.ID)
+		}
+		if check.Notes != notes {
+			return fmt.Errorf("Bad notes: %s", check.Notes)
+		}
+		return nil
+	}
+}
+
+const testAccConsulCheckConfig = `
+resource "consul_check" "app" {
+	name = "terraform-acc"
+	notes = "Checked by TTL"
+	ttl = "30s"
+}
+`
+
+const testAccConsulCheckConfigUpdate = `
+resource "consul_check" "app" {
+	name = "terraform-acc"
+	notes = "Updated"
+	ttl = "30s"
+}
+`
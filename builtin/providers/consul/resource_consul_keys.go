@@ -20,6 +20,7 @@ func resource_consul_keys_validation() *config.Validator {
 		},
 		Optional: []string{
 			"datacenter",
+			"session",
 			"key.*.value",
 			"key.*.default",
 			"key.*.delete",
@@ -62,6 +63,7 @@ func resource_consul_keys_create(
 	}
 
 	kv := p.client.KV()
+	session := rs.Attributes["session"]
 	qOpts := consulapi.QueryOptions{Datacenter: dc}
 	wOpts := consulapi.WriteOptions{Datacenter: dc}
 	for idx, raw := range keys {
@@ -78,7 +80,19 @@ func resource_consul_keys_create(
 
 			log.Printf("[DEBUG] Setting key '%s' to '%v' in %s", path, value, dc)
 			pair := consulapi.KVPair{Key: path, Value: []byte(value)}
-			if _, err := kv.Put(&pair, &wOpts); err != nil {
+			if session != "" {
+				// Setting the key through the session locks it, so
+				// that no other session can set it at the same time.
+				pair.Session = session
+				ok, _, err := kv.Acquire(&pair, &wOpts)
+				if err != nil {
+					return rs, fmt.Errorf("Failed to set Consul key '%s': %v", path, err)
+				}
+				if !ok {
+					return rs, fmt.Errorf(
+						"Failed to set Consul key '%s': locked by another session", path)
+				}
+			} else if _, err := kv.Put(&pair, &wOpts); err != nil {
 				return rs, fmt.Errorf("Failed to set Consul key '%s': %v", path, err)
 			}
 			rs.Attributes[fmt.Sprintf("var.%s", key)] = value
@@ -110,6 +124,7 @@ func resource_consul_keys_destroy(
 	}
 
 	dc := s.Attributes["datacenter"]
+	session := s.Attributes["session"]
 	wOpts := consulapi.WriteOptions{Datacenter: dc}
 	for _, raw := range keys {
 		_, path, sub, err := parse_key(raw)
@@ -120,6 +135,16 @@ func resource_consul_keys_destroy(
 		// Ignore if the key is non-managed
 		shouldDelete, ok := sub["delete"].(bool)
 		if !ok || !shouldDelete {
+			// Keys that were locked are released, so that
+			// other sessions can set them.
+			if _, ok := sub["value"]; ok && session != "" {
+				log.Printf("[DEBUG] Releasing key '%s' in %s", path, dc)
+				pair := consulapi.KVPair{Key: path, Session: session}
+				if _, _, err := kv.Release(&pair, &wOpts); err != nil {
+					return fmt.Errorf("Failed to release Consul key '%s': %v", path, err)
+				}
+			}
+
 			continue
 		}
 
@@ -155,6 +180,7 @@ AFTER:
 		Attrs: map[string]diff.AttrType{
 			"datacenter": diff.AttrTypeCreate,
 			"key":        diff.AttrTypeUpdate,
+			"session":    diff.AttrTypeUpdate,
 		},
 		ComputedAttrsUpdate: computed,
 	}
@@ -0,0 +1,192 @@
+package consul
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/armon/consul-api"
+	"github.com/hashicorp/terraform/flatmap"
+	"github.com/hashicorp/terraform/helper/config"
+	"github.com/hashicorp/terraform/helper/diff"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func resource_consul_service_validation() *config.Validator {
+	return &config.Validator{
+		Required: []string{
+			"name",
+		},
+		Optional: []string{
+			"service_id",
+			"tags.*",
+			"port",
+			"check_script",
+			"check_interval",
+			"check_ttl",
+		},
+	}
+}
+
+func resource_consul_service_update(
+	s *terraform.ResourceState,
+	d *terraform.ResourceDiff,
+	meta interface{}) (*terraform.ResourceState, error) {
+	// Registering a service again with the same ID replaces it
+	return resource_consul_service_create(s, d, meta)
+}
+
+func resource_consul_service_create(
+	s *terraform.ResourceState,
+	d *terraform.ResourceDiff,
+	meta interface{}) (*terraform.ResourceState, error) {
+	p := meta.(*ResourceProvider)
+
+	// Merge the diff into the state so that we have all the attributes
+	// properly.
+	rs := s.MergeDiff(d)
+
+	// The ID of the service defaults to its name
+	if aDiff, ok := d.Attributes["service_id"]; ok && aDiff.NewComputed {
+		rs.Attributes["service_id"] = rs.Attributes["name"]
+	}
+
+	reg, err := resource_consul_service_build(rs)
+	if err != nil {
+		return rs, err
+	}
+
+	log.Printf("[DEBUG] Registering service '%s'", reg.ID)
+	if err := p.client.Agent().ServiceRegister(reg); err != nil {
+		return rs, fmt.Errorf(
+			"Failed to register Consul service '%s': %v", reg.ID, err)
+	}
+
+	rs.ID = reg.ID
+	return rs, nil
+}
+
+func resource_consul_service_destroy(
+	s *terraform.ResourceState,
+	meta interface{}) error {
+	p := meta.(*ResourceProvider)
+
+	log.Printf("[DEBUG] Deregistering service '%s'", s.ID)
+	if err := p.client.Agent().ServiceDeregister(s.ID); err != nil {
+		return fmt.Errorf(
+			"Failed to deregister Consul service '%s': %v", s.ID, err)
+	}
+
+	return nil
+}
+
+func resource_consul_service_diff(
+	s *terraform.ResourceState,
+	c *terraform.ResourceConfig,
+	meta interface{}) (*terraform.ResourceDiff, error) {
+	b := &diff.ResourceBuilder{
+		Attrs: map[string]diff.AttrType{
+			"name":           diff.AttrTypeCreate,
+			"service_id":     diff.AttrTypeCreate,
+			"tags":           diff.AttrTypeUpdate,
+			"port":           diff.AttrTypeUpdate,
+			"check_script":   diff.AttrTypeUpdate,
+			"check_interval": diff.AttrTypeUpdate,
+			"check_ttl":      diff.AttrTypeUpdate,
+		},
+
+		ComputedAttrs: []string{
+			"service_id",
+		},
+	}
+
+	return b.Diff(s, c)
+}
+
+func resource_consul_service_refresh(
+	s *terraform.ResourceState,
+	meta interface{}) (*terraform.ResourceState, error) {
+	p := meta.(*ResourceProvider)
+
+	services, err := p.client.Agent().Services()
+	if err != nil {
+		return s, fmt.Errorf("Failed to get Consul services: %v", err)
+	}
+
+	service, ok := services[s.ID]
+	if !ok {
+		// The service was deregistered outside of Terraform
+		s.ID = ""
+		return s, nil
+	}
+
+	// The tags are replaced wholesale
+	for k := range s.Attributes {
+		if strings.HasPrefix(k, "tags.") {
+			delete(s.Attributes, k)
+		}
+	}
+	if len(service.Tags) > 0 {
+		flatTags := flatmap.Flatten(map[string]interface{}{
+			"tags": service.Tags,
+		})
+		for k, v := range flatTags {
+			s.Attributes[k] = v
+		}
+	}
+
+	s.Attributes["name"] = service.Service
+	if service.Port != 0 {
+		s.Attributes["port"] = strconv.FormatInt(int64(service.Port), 10)
+	}
+
+	return s, nil
+}
+
+// resource_consul_service_build turns the state of a service into the
+// registration for the agent.
+func resource_consul_service_build(
+	s *terraform.ResourceState) (*consulapi.AgentServiceRegistration, error) {
+	reg := &consulapi.AgentServiceRegistration{
+		ID:   s.Attributes["service_id"],
+		Name: s.Attributes["name"],
+	}
+
+	if v := s.Attributes["port"]; v != "" {
+		port, err := strconv.ParseInt(v, 10, 0)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid port '%s': %s", v, err)
+		}
+		reg.Port = int(port)
+	}
+
+	if _, ok := s.Attributes["tags.#"]; ok {
+		for _, raw := range flatmap.Expand(s.Attributes, "tags").([]interface{}) {
+			reg.Tags = append(reg.Tags, raw.(string))
+		}
+	}
+
+	script := s.Attributes["check_script"]
+	ttl := s.Attributes["check_ttl"]
+	switch {
+	case script != "" && ttl != "":
+		return nil, fmt.Errorf(
+			"Only one of check_script and check_ttl can be set")
+	case script != "":
+		interval := s.Attributes["check_interval"]
+		if interval == "" {
+			return nil, fmt.Errorf(
+				"check_interval must be set with check_script")
+		}
+
+		reg.Check = &consulapi.AgentServiceCheck{
+			Script:   script,
+			Interval: interval,
+		}
+	case ttl != "":
+		reg.Check = &consulapi.AgentServiceCheck{TTL: ttl}
+	}
+
+	return reg, nil
+}
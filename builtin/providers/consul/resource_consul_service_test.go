@@ -0,0 +1,71 @@
+package consul
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccConsulService(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() {},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckConsulServiceDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccConsulServiceConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckConsulServiceExists("consul_service.app"),
+					resource.TestCheckResourceAttr(
+						"consul_service.app", "service_id", "terraform-acc"),
+					resource.TestCheckResourceAttr(
+						"consul_service.app", "tags.0", "v1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckConsulServiceDestroy(s *terraform.State) error {
+	services, err := testAccProvider.client.Agent().Services()
+	if err != nil {
+		return err
+	}
+	if _, ok := services["terraform-acc"]; ok {
+		return fmt.Errorf("Service still exists")
+	}
+	return nil
+}
+
+func testAccCheckConsulServiceExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		services, err := testAccProvider.client.Agent().Services()
+		if err != nil {
+			return err
+		}
+		service, ok := services[rs.ID]
+		if !ok {
+			return fmt.Errorf("Service '%s' is not registered", rs.ID)
+		}
+		if service.Port != 8080 {
+			return fmt.Errorf("Bad port: %d", service.Port)
+		}
+		return nil
+	}
+}
+
+const testAccConsulServiceConfig = `
+resource "consul_service" "app" {
+	name = "terraform-acc"
+	tags = ["v1"]
+	port = 8080
+	check_ttl = "30s"
+}
+`
@@ -0,0 +1,123 @@
+package consul
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/armon/consul-api"
+	"github.com/hashicorp/terraform/flatmap"
+	"github.com/hashicorp/terraform/helper/config"
+	"github.com/hashicorp/terraform/helper/diff"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func resource_consul_session_validation() *config.Validator {
+	return &config.Validator{
+		Optional: []string{
+			"datacenter",
+			"name",
+			"checks.*",
+		},
+	}
+}
+
+func resource_consul_session_create(
+	s *terraform.ResourceState,
+	d *terraform.ResourceDiff,
+	meta interface{}) (*terraform.ResourceState, error) {
+	p := meta.(*ResourceProvider)
+
+	// Merge the diff into the state so that we have all the attributes
+	// properly.
+	rs := s.MergeDiff(d)
+
+	// Check if the datacenter should be computed
+	dc := rs.Attributes["datacenter"]
+	if aDiff, ok := d.Attributes["datacenter"]; ok && aDiff.NewComputed {
+		var err error
+		dc, err = get_dc(p.client)
+		if err != nil {
+			return rs, fmt.Errorf("Failed to get agent datacenter: %v", err)
+		}
+		rs.Attributes["datacenter"] = dc
+	}
+
+	entry := &consulapi.SessionEntry{
+		Name: rs.Attributes["name"],
+	}
+	if _, ok := rs.Attributes["checks.#"]; ok {
+		for _, raw := range flatmap.Expand(rs.Attributes, "checks").([]interface{}) {
+			entry.Checks = append(entry.Checks, raw.(string))
+		}
+	}
+
+	log.Printf("[DEBUG] Creating session '%s' in %s", entry.Name, dc)
+	id, _, err := p.client.Session().Create(
+		entry, &consulapi.WriteOptions{Datacenter: dc})
+	if err != nil {
+		return rs, fmt.Errorf("Failed to create Consul session: %v", err)
+	}
+
+	rs.ID = id
+	return rs, nil
+}
+
+func resource_consul_session_destroy(
+	s *terraform.ResourceState,
+	meta interface{}) error {
+	p := meta.(*ResourceProvider)
+
+	// Destroying the session releases all the locks it holds
+	dc := s.Attributes["datacenter"]
+	log.Printf("[DEBUG] Destroying session '%s' in %s", s.ID, dc)
+	_, err := p.client.Session().Destroy(
+		s.ID, &consulapi.WriteOptions{Datacenter: dc})
+	if err != nil {
+		return fmt.Errorf(
+			"Failed to destroy Consul session '%s': %v", s.ID, err)
+	}
+
+	return nil
+}
+
+func resource_consul_session_diff(
+	s *terraform.ResourceState,
+	c *terraform.ResourceConfig,
+	meta interface{}) (*terraform.ResourceDiff, error) {
+	// Sessions can't be changed, only replaced
+	b := &diff.ResourceBuilder{
+		Attrs: map[string]diff.AttrType{
+			"datacenter": diff.AttrTypeCreate,
+			"name":       diff.AttrTypeCreate,
+			"checks":     diff.AttrTypeCreate,
+		},
+
+		ComputedAttrs: []string{
+			"datacenter",
+		},
+	}
+
+	return b.Diff(s, c)
+}
+
+func resource_consul_session_refresh(
+	s *terraform.ResourceState,
+	meta interface{}) (*terraform.ResourceState, error) {
+	p := meta.(*ResourceProvider)
+
+	dc := s.Attributes["datacenter"]
+	entry, _, err := p.client.Session().Info(
+		s.ID, &consulapi.QueryOptions{Datacenter: dc})
+	if err != nil {
+		return s, fmt.Errorf(
+			"Failed to get Consul session '%s': %v", s.ID, err)
+	}
+
+	// Sessions are invalidated when one of their checks fails, or when
+	// their node goes away. A new one is created in that case.
+	if entry == nil {
+		s.ID = ""
+	}
+
+	return s, nil
+}
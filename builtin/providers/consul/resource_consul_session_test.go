@@ -0,0 +1,99 @@
+package consul
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/armon/consul-api"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccConsulSession_lock(t *testing.T) {
+	var id string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() {},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckConsulSessionDestroy(&id),
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccConsulSessionConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckConsulSessionExists("consul_session.lock", &id),
+					testAccCheckConsulKeyLocked("test/lock", &id),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckConsulSessionDestroy(id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		opts := &consulapi.QueryOptions{Datacenter: "nyc1"}
+		entry, _, err := testAccProvider.client.Session().Info(*id, opts)
+		if err != nil {
+			return err
+		}
+		if entry != nil {
+			return fmt.Errorf("Session still exists: %#v", entry)
+		}
+		return nil
+	}
+}
+
+func testAccCheckConsulSessionExists(n string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		opts := &consulapi.QueryOptions{Datacenter: "nyc1"}
+		entry, _, err := testAccProvider.client.Session().Info(rs.ID, opts)
+		if err != nil {
+			return err
+		}
+		if entry == nil {
+			return fmt.Errorf("Session '%s' does not exist", rs.ID)
+		}
+
+		*id = rs.ID
+		return nil
+	}
+}
+
+func testAccCheckConsulKeyLocked(path string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		opts := &consulapi.QueryOptions{Datacenter: "nyc1"}
+		pair, _, err := testAccProvider.client.KV().Get(path, opts)
+		if err != nil {
+			return err
+		}
+		if pair == nil {
+			return fmt.Errorf("Key '%s' does not exist", path)
+		}
+		if pair.Session != *id {
+			return fmt.Errorf("Key '%s' is locked by '%s'", path, pair.Session)
+		}
+		return nil
+	}
+}
+
+const testAccConsulSessionConfig = `
+resource "consul_session" "lock" {
+	datacenter = "nyc1"
+	name = "terraform-acc"
+}
+
+resource "consul_keys" "app" {
+	datacenter = "nyc1"
+	session = "${consul_session.lock.id}"
+	key {
+		name = "lock"
+		path = "test/lock"
+		value = "acceptance"
+		delete = true
+	}
+}
+`
@@ -1,6 +1,7 @@
 package consul
 
 import (
+	"fmt"
 	"log"
 
 	"github.com/armon/consul-api"
@@ -14,13 +15,7 @@ type ResourceProvider struct {
 }
 
 func (p *ResourceProvider) Validate(c *terraform.ResourceConfig) ([]string, []error) {
-	v := &config.Validator{
-		Optional: []string{
-			"datacenter",
-			"address",
-		},
-	}
-	return v.Validate(c)
+	return config.NewConfigSchemaValidator(p.ConfigSchema()).Validate(c)
 }
 
 func (p *ResourceProvider) ValidateResource(
@@ -42,6 +37,38 @@ func (p *ResourceProvider) Configure(c *terraform.ResourceConfig) error {
 	return nil
 }
 
+func (p *ResourceProvider) ValidateCredentials() error {
+	if _, err := p.client.Agent().Self(); err != nil {
+		return fmt.Errorf("Error contacting the Consul agent: %s", err)
+	}
+
+	return nil
+}
+
+func (p *ResourceProvider) WriteOnlyConfig() []string {
+	// The Consul provider has no credentials
+	return nil
+}
+
+func (p *ResourceProvider) ConfigSchema() []terraform.ConfigAttribute {
+	return []terraform.ConfigAttribute{
+		terraform.ConfigAttribute{Name: "address"},
+		terraform.ConfigAttribute{Name: "datacenter"},
+	}
+}
+
+func (p *ResourceProvider) Stop() error {
+	// The API calls of the provider can't be canceled, so they're left
+	// to finish.
+	return nil
+}
+
+func (p *ResourceProvider) Capabilities() terraform.ResourceProviderCapabilities {
+	return terraform.ResourceProviderCapabilities{
+		ValidateCredentials: true,
+	}
+}
+
 func (p *ResourceProvider) Apply(
 	s *terraform.ResourceState,
 	d *terraform.ResourceDiff) (*terraform.ResourceState, error) {
@@ -59,6 +86,11 @@ func (p *ResourceProvider) Refresh(
 	return resourceMap.Refresh(s, p)
 }
 
+func (p *ResourceProvider) ListResources(
+	t string, filters map[string]string) ([]*terraform.ResourceState, error) {
+	return nil, terraform.ErrListNotSupported
+}
+
 func (p *ResourceProvider) Resources() []terraform.ResourceType {
 	return resourceMap.Resources()
 }
@@ -19,6 +19,29 @@ func init() {
 				Diff:            resource_consul_keys_diff,
 				Refresh:         resource_consul_keys_refresh,
 			},
+			"consul_check": resource.Resource{
+				ConfigValidator: resource_consul_check_validation(),
+				Create:          resource_consul_check_create,
+				Destroy:         resource_consul_check_destroy,
+				Update:          resource_consul_check_update,
+				Diff:            resource_consul_check_diff,
+				Refresh:         resource_consul_check_refresh,
+			},
+			"consul_service": resource.Resource{
+				ConfigValidator: resource_consul_service_validation(),
+				Create:          resource_consul_service_create,
+				Destroy:         resource_consul_service_destroy,
+				Update:          resource_consul_service_update,
+				Diff:            resource_consul_service_diff,
+				Refresh:         resource_consul_service_refresh,
+			},
+			"consul_session": resource.Resource{
+				ConfigValidator: resource_consul_session_validation(),
+				Create:          resource_consul_session_create,
+				Destroy:         resource_consul_session_destroy,
+				Diff:            resource_consul_session_diff,
+				Refresh:         resource_consul_session_refresh,
+			},
 		},
 	}
 }
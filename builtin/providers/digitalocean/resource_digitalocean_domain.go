@@ -3,6 +3,7 @@ package digitalocean
 import (
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/hashicorp/terraform/helper/config"
 	"github.com/hashicorp/terraform/helper/diff"
@@ -49,6 +50,11 @@ func resource_digitalocean_domain_destroy(
 
 	err := client.DestroyDomain(s.ID)
 
+	// Handle remotely destroyed domains
+	if err != nil && strings.Contains(err.Error(), "404 Not Found") {
+		return nil
+	}
+
 	if err != nil {
 		return fmt.Errorf("Error deleting Domain: %s", err)
 	}
@@ -64,6 +70,11 @@ func resource_digitalocean_domain_refresh(
 
 	domain, err := client.RetrieveDomain(s.ID)
 
+	// Handle remotely destroyed domains
+	if err != nil && strings.Contains(err.Error(), "404 Not Found") {
+		return nil, nil
+	}
+
 	if err != nil {
 		return s, fmt.Errorf("Error retrieving domain: %s", err)
 	}
@@ -71,7 +71,7 @@ func resource_digitalocean_droplet_create(
 
 	log.Printf("[INFO] Droplet ID: %s", id)
 
-	dropletRaw, err := WaitForDropletAttribute(id, "active", []string{"new"}, "status", client)
+	dropletRaw, err := WaitForDropletAttribute(id, "active", []string{"new"}, "status", client, p.StopCh())
 
 	if err != nil {
 		return rs, fmt.Errorf(
@@ -107,12 +107,12 @@ func resource_digitalocean_droplet_update(
 
 		// Wait for power off
 		_, err = WaitForDropletAttribute(
-			rs.ID, "off", []string{"active"}, "status", client)
+			rs.ID, "off", []string{"active"}, "status", client, p.StopCh())
 
 		err = client.Resize(rs.ID, attr.New)
 
 		if err != nil {
-			newErr := power_on_and_wait(rs.ID, client)
+			newErr := power_on_and_wait(rs.ID, client, p.StopCh())
 			if newErr != nil {
 				return rs, newErr
 			}
@@ -121,10 +121,10 @@ func resource_digitalocean_droplet_update(
 
 		// Wait for the size to change
 		_, err = WaitForDropletAttribute(
-			rs.ID, attr.New, []string{"", attr.Old}, "size", client)
+			rs.ID, attr.New, []string{"", attr.Old}, "size", client, p.StopCh())
 
 		if err != nil {
-			newErr := power_on_and_wait(rs.ID, client)
+			newErr := power_on_and_wait(rs.ID, client, p.StopCh())
 			if newErr != nil {
 				return rs, newErr
 			}
@@ -139,7 +139,7 @@ func resource_digitalocean_droplet_update(
 
 		// Wait for power off
 		_, err = WaitForDropletAttribute(
-			rs.ID, "active", []string{"off"}, "status", client)
+			rs.ID, "active", []string{"off"}, "status", client, p.StopCh())
 
 		if err != nil {
 			return s, err
@@ -155,31 +155,46 @@ func resource_digitalocean_droplet_update(
 
 		// Wait for the name to change
 		_, err = WaitForDropletAttribute(
-			rs.ID, attr.New, []string{"", attr.Old}, "name", client)
+			rs.ID, attr.New, []string{"", attr.Old}, "name", client, p.StopCh())
 	}
 
-	if attr, ok := d.Attributes["private_networking"]; ok {
-		err = client.Rename(rs.ID, attr.New)
+	// Private networking and IPv6 can be enabled on a running droplet,
+	// but not disabled again.
+	if attr, ok := d.Attributes["private_networking"]; ok && attr.New == "true" {
+		err = client.EnablePrivateNetworking(rs.ID)
 
 		if err != nil {
 			return s, err
 		}
 
-		// Wait for the private_networking to turn on/off
+		// Wait for private_networking to turn on
 		_, err = WaitForDropletAttribute(
-			rs.ID, attr.New, []string{"", attr.Old}, "private_networking", client)
+			rs.ID, attr.New, []string{"", attr.Old}, "private_networking", client, p.StopCh())
+
+		if err != nil {
+			return s, err
+		}
+	} else if ok {
+		return s, fmt.Errorf(
+			"Private networking can't be disabled on droplet %s", rs.ID)
 	}
 
-	if attr, ok := d.Attributes["ipv6"]; ok {
-		err = client.Rename(rs.ID, attr.New)
+	if attr, ok := d.Attributes["ipv6"]; ok && attr.New == "true" {
+		err = client.EnableIPV6s(rs.ID)
 
 		if err != nil {
 			return s, err
 		}
 
-		// Wait for ipv6 to turn on/off
+		// Wait for ipv6 to turn on
 		_, err = WaitForDropletAttribute(
-			rs.ID, attr.New, []string{"", attr.Old}, "ipv6", client)
+			rs.ID, attr.New, []string{"", attr.Old}, "ipv6", client, p.StopCh())
+
+		if err != nil {
+			return s, err
+		}
+	} else if ok {
+		return s, fmt.Errorf("IPv6 can't be disabled on droplet %s", rs.ID)
 	}
 
 	droplet, err := resource_digitalocean_droplet_retrieve(rs.ID, client)
@@ -241,7 +256,7 @@ func resource_digitalocean_droplet_diff(
 
 	b := &diff.ResourceBuilder{
 		Attrs: map[string]diff.AttrType{
-			"backups":            diff.AttrTypeUpdate,
+			"backups":            diff.AttrTypeCreate,
 			"image":              diff.AttrTypeCreate,
 			"ipv6":               diff.AttrTypeUpdate,
 			"name":               diff.AttrTypeUpdate,
@@ -329,7 +344,7 @@ func resource_digitalocean_droplet_validation() *config.Validator {
 	}
 }
 
-func WaitForDropletAttribute(id string, target string, pending []string, attribute string, client *digitalocean.Client) (interface{}, error) {
+func WaitForDropletAttribute(id string, target string, pending []string, attribute string, client *digitalocean.Client, stopCh <-chan struct{}) (interface{}, error) {
 	// Wait for the droplet so we can get the networking attributes
 	// that show up after a while
 	log.Printf(
@@ -343,6 +358,7 @@ func WaitForDropletAttribute(id string, target string, pending []string, attribu
 		Timeout:    10 * time.Minute,
 		Delay:      10 * time.Second,
 		MinTimeout: 3 * time.Second,
+		StopCh:     stopCh,
 	}
 
 	return stateConf.WaitForState()
@@ -386,7 +402,7 @@ func new_droplet_state_refresh_func(id string, attribute string, client *digital
 }
 
 // Powers on the droplet and waits for it to be active
-func power_on_and_wait(id string, client *digitalocean.Client) error {
+func power_on_and_wait(id string, client *digitalocean.Client, stopCh <-chan struct{}) error {
 	err := client.PowerOn(id)
 
 	if err != nil {
@@ -395,7 +411,7 @@ func power_on_and_wait(id string, client *digitalocean.Client) error {
 
 	// Wait for power on
 	_, err = WaitForDropletAttribute(
-		id, "active", []string{"off"}, "status", client)
+		id, "active", []string{"off"}, "status", client, stopCh)
 
 	if err != nil {
 		return err
@@ -3,6 +3,7 @@ package digitalocean
 import (
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/hashicorp/terraform/helper/config"
 	"github.com/hashicorp/terraform/helper/diff"
@@ -90,6 +91,11 @@ func resource_digitalocean_record_destroy(
 
 	err := client.DestroyRecord(s.Attributes["domain"], s.ID)
 
+	// Handle remotely destroyed records
+	if err != nil && strings.Contains(err.Error(), "404 Not Found") {
+		return nil
+	}
+
 	if err != nil {
 		return fmt.Errorf("Error deleting record: %s", err)
 	}
@@ -104,6 +110,12 @@ func resource_digitalocean_record_refresh(
 	client := p.client
 
 	rec, err := resource_digitalocean_record_retrieve(s.Attributes["domain"], s.ID, client)
+
+	// Handle remotely destroyed records
+	if err != nil && strings.Contains(err.Error(), "404 Not Found") {
+		return nil, nil
+	}
+
 	if err != nil {
 		return nil, err
 	}
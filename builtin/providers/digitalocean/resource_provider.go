@@ -2,6 +2,7 @@ package digitalocean
 
 import (
 	"log"
+	"sync"
 
 	"github.com/hashicorp/terraform/helper/config"
 	"github.com/hashicorp/terraform/terraform"
@@ -12,16 +13,15 @@ type ResourceProvider struct {
 	Config Config
 
 	client *digitalocean.Client
+
+	// stopCh is closed when the provider is stopped, which ends the
+	// waits for droplets to change.
+	stopCh   chan struct{}
+	stopLock sync.Mutex
 }
 
 func (p *ResourceProvider) Validate(c *terraform.ResourceConfig) ([]string, []error) {
-	v := &config.Validator{
-		Required: []string{
-			"token",
-		},
-	}
-
-	return v.Validate(c)
+	return config.NewConfigSchemaValidator(p.ConfigSchema()).Validate(c)
 }
 
 func (p *ResourceProvider) ValidateResource(
@@ -45,6 +45,62 @@ func (p *ResourceProvider) Configure(c *terraform.ResourceConfig) error {
 	return nil
 }
 
+func (p *ResourceProvider) ValidateCredentials() error {
+	// The DigitalOcean client has no call that is cheap enough to check the
+	// credentials with, so they're checked by the first real request.
+	return nil
+}
+
+func (p *ResourceProvider) WriteOnlyConfig() []string {
+	return []string{"token"}
+}
+
+func (p *ResourceProvider) ConfigSchema() []terraform.ConfigAttribute {
+	return []terraform.ConfigAttribute{
+		terraform.ConfigAttribute{
+			Name:      "token",
+			Required:  true,
+			WriteOnly: true,
+			EnvVars:   []string{"DIGITALOCEAN_TOKEN"},
+		},
+	}
+}
+
+func (p *ResourceProvider) Stop() error {
+	// The API calls themselves can't be canceled, but the waits for
+	// droplets to change can.
+	p.stopLock.Lock()
+	defer p.stopLock.Unlock()
+
+	if p.stopCh == nil {
+		p.stopCh = make(chan struct{})
+	}
+	select {
+	case <-p.stopCh:
+	default:
+		close(p.stopCh)
+	}
+
+	return nil
+}
+
+// StopCh returns the channel that is closed when the provider is
+// stopped.
+func (p *ResourceProvider) StopCh() <-chan struct{} {
+	p.stopLock.Lock()
+	defer p.stopLock.Unlock()
+
+	if p.stopCh == nil {
+		p.stopCh = make(chan struct{})
+	}
+
+	return p.stopCh
+}
+
+func (p *ResourceProvider) Capabilities() terraform.ResourceProviderCapabilities {
+	return terraform.ResourceProviderCapabilities{Stop: true}
+}
+
 func (p *ResourceProvider) Apply(
 	s *terraform.ResourceState,
 	d *terraform.ResourceDiff) (*terraform.ResourceState, error) {
@@ -62,6 +118,11 @@ func (p *ResourceProvider) Refresh(
 	return resourceMap.Refresh(s, p)
 }
 
+func (p *ResourceProvider) ListResources(
+	t string, filters map[string]string) ([]*terraform.ResourceState, error) {
+	return nil, terraform.ErrListNotSupported
+}
+
 func (p *ResourceProvider) Resources() []terraform.ResourceType {
 	return resourceMap.Resources()
 }
@@ -49,6 +49,20 @@ func TestResourceProvider_Configure(t *testing.T) {
 	}
 }
 
+func TestResourceProvider_Stop(t *testing.T) {
+	rp := new(ResourceProvider)
+	stopCh := rp.StopCh()
+	if err := rp.Stop(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	select {
+	case <-stopCh:
+	default:
+		t.Fatal("should be stopped")
+	}
+}
+
 func testAccPreCheck(t *testing.T) {
 	if v := os.Getenv("DIGITALOCEAN_TOKEN"); v == "" {
 		t.Fatal("DIGITALOCEAN_TOKEN must be set for acceptance tests")
@@ -0,0 +1,181 @@
+package dnsimple
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// RegistrarURL is the DNSimple API that contacts and domain registrations
+// are managed with. The client library only covers DNS records.
+const RegistrarURL = "https://api.dnsimple.com/v1"
+
+// RegistrarClient manages DNSimple contacts and domain registrations.
+type RegistrarClient struct {
+	URL   string
+	Email string
+	Token string
+
+	HTTPClient *http.Client
+}
+
+// RegistrarError is an error returned by the DNSimple API.
+type RegistrarError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *RegistrarError) Error() string {
+	return fmt.Sprintf("DNSimple error (%d): %s", e.StatusCode, e.Message)
+}
+
+// Contact is a DNSimple contact, the registrant of domains.
+type Contact struct {
+	Id               int    `json:"id,omitempty"`
+	FirstName        string `json:"first_name"`
+	LastName         string `json:"last_name"`
+	OrganizationName string `json:"organization_name,omitempty"`
+	Address1         string `json:"address1"`
+	Address2         string `json:"address2,omitempty"`
+	City             string `json:"city"`
+	StateProvince    string `json:"state_province"`
+	PostalCode       string `json:"postal_code"`
+	Country          string `json:"country"`
+	EmailAddress     string `json:"email_address"`
+	Phone            string `json:"phone"`
+}
+
+// Domain is a domain in a DNSimple account.
+type Domain struct {
+	Id           int    `json:"id,omitempty"`
+	Name         string `json:"name"`
+	RegistrantId int    `json:"registrant_id,omitempty"`
+	State        string `json:"state,omitempty"`
+	ExpiresOn    string `json:"expires_on,omitempty"`
+	AutoRenew    bool   `json:"auto_renew,omitempty"`
+}
+
+type contactWrapper struct {
+	Contact *Contact `json:"contact"`
+}
+
+type domainWrapper struct {
+	Domain *Domain `json:"domain"`
+}
+
+// CreateContact creates a contact and returns it with its ID.
+func (c *RegistrarClient) CreateContact(contact *Contact) (*Contact, error) {
+	var result contactWrapper
+	err := c.request(
+		"POST", "/contacts", &contactWrapper{contact}, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Contact, nil
+}
+
+// Contact returns the contact with the given ID.
+func (c *RegistrarClient) Contact(id string) (*Contact, error) {
+	var result contactWrapper
+	if err := c.request("GET", "/contacts/"+id, nil, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Contact, nil
+}
+
+// UpdateContact changes the contact with the given ID.
+func (c *RegistrarClient) UpdateContact(id string, contact *Contact) error {
+	return c.request("PUT", "/contacts/"+id, &contactWrapper{contact}, nil)
+}
+
+// DeleteContact deletes the contact with the given ID.
+func (c *RegistrarClient) DeleteContact(id string) error {
+	return c.request("DELETE", "/contacts/"+id, nil, nil)
+}
+
+// RegisterDomain registers the domain for the contact with the given ID.
+func (c *RegistrarClient) RegisterDomain(name string, registrantId int) (*Domain, error) {
+	body := &domainWrapper{&Domain{Name: name, RegistrantId: registrantId}}
+
+	var result domainWrapper
+	if err := c.request("POST", "/domain_registrations", body, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Domain, nil
+}
+
+// Domain returns the domain with the given name.
+func (c *RegistrarClient) Domain(name string) (*Domain, error) {
+	var result domainWrapper
+	if err := c.request("GET", "/domains/"+name, nil, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Domain, nil
+}
+
+// SetAutoRenew turns the automatic renewal of the domain on or off.
+func (c *RegistrarClient) SetAutoRenew(name string, on bool) error {
+	method := "DELETE"
+	if on {
+		method = "POST"
+	}
+
+	return c.request(method, "/domains/"+name+"/auto_renewal", nil, nil)
+}
+
+func (c *RegistrarClient) request(
+	method string, path string, body interface{}, result interface{}) error {
+	var raw []byte
+	if body != nil {
+		var err error
+		if raw, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	base := c.URL
+	if base == "" {
+		base = RegistrarURL
+	}
+
+	req, err := http.NewRequest(method, base+path, bytes.NewReader(raw))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-DNSimple-Token", c.Email+":"+c.Token)
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		var e struct {
+			Message string `json:"message"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&e); err != nil || e.Message == "" {
+			e.Message = resp.Status
+		}
+
+		return &RegistrarError{StatusCode: resp.StatusCode, Message: e.Message}
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,108 @@
+package dnsimple
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestRegistrarClient(t *testing.T) {
+	var requests []string
+	var registered Domain
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-DNSimple-Token") != "foo@example.com:secret" {
+			w.WriteHeader(401)
+			fmt.Fprint(w, `{"message":"Authentication failed"}`)
+			return
+		}
+
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method + " " + r.URL.Path {
+		case "POST /contacts":
+			var body contactWrapper
+			json.NewDecoder(r.Body).Decode(&body)
+			body.Contact.Id = 7
+			json.NewEncoder(w).Encode(&body)
+		case "GET /contacts/7":
+			fmt.Fprint(w, `{"contact":{"id":7,"first_name":"Jane"}}`)
+		case "POST /domain_registrations":
+			var body domainWrapper
+			json.NewDecoder(r.Body).Decode(&body)
+			registered = *body.Domain
+			body.Domain.State = "registered"
+			json.NewEncoder(w).Encode(&body)
+		case "POST /domains/example.com/auto_renewal":
+			fmt.Fprint(w, `{"domain":{"name":"example.com","auto_renew":true}}`)
+		default:
+			w.WriteHeader(404)
+			fmt.Fprint(w, `{"message":"Not found"}`)
+		}
+	}))
+	defer ts.Close()
+
+	c := &RegistrarClient{
+		URL:   ts.URL,
+		Email: "foo@example.com",
+		Token: "secret",
+	}
+
+	contact, err := c.CreateContact(&Contact{FirstName: "Jane"})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if contact.Id != 7 || contact.FirstName != "Jane" {
+		t.Fatalf("bad: %#v", contact)
+	}
+
+	contact, err = c.Contact("7")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if contact.FirstName != "Jane" {
+		t.Fatalf("bad: %#v", contact)
+	}
+
+	_, err = c.Contact("8")
+	if e, ok := err.(*RegistrarError); !ok || e.StatusCode != 404 || e.Message != "Not found" {
+		t.Fatalf("bad: %#v", err)
+	}
+
+	domain, err := c.RegisterDomain("example.com", 7)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if domain.State != "registered" {
+		t.Fatalf("bad: %#v", domain)
+	}
+	expected := Domain{Name: "example.com", RegistrantId: 7}
+	if !reflect.DeepEqual(registered, expected) {
+		t.Fatalf("bad: %#v", registered)
+	}
+
+	if err := c.SetAutoRenew("example.com", true); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := c.SetAutoRenew("example.com", false); err == nil {
+		t.Fatal("should error")
+	}
+
+	expectedRequests := []string{
+		"POST /contacts",
+		"GET /contacts/7",
+		"GET /contacts/8",
+		"POST /domain_registrations",
+		"POST /domains/example.com/auto_renewal",
+		"DELETE /domains/example.com/auto_renewal",
+	}
+	if !reflect.DeepEqual(requests, expectedRequests) {
+		t.Fatalf("bad: %#v", requests)
+	}
+
+	c.Token = "wrong"
+	if _, err := c.Contact("7"); err == nil {
+		t.Fatal("should error")
+	}
+}
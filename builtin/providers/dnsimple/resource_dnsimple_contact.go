@@ -0,0 +1,181 @@
+package dnsimple
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/config"
+	"github.com/hashicorp/terraform/helper/diff"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func resource_dnsimple_contact_create(
+	s *terraform.ResourceState,
+	d *terraform.ResourceDiff,
+	meta interface{}) (*terraform.ResourceState, error) {
+	p := meta.(*ResourceProvider)
+	client := p.registrar
+
+	rs := s.MergeDiff(d)
+
+	newContact := resource_dnsimple_contact_expand(rs)
+	log.Printf("[DEBUG] contact create configuration: %#v", newContact)
+
+	contact, err := client.CreateContact(newContact)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create contact: %s", err)
+	}
+
+	rs.ID = strconv.Itoa(contact.Id)
+	log.Printf("[INFO] contact ID: %s", rs.ID)
+
+	return resource_dnsimple_contact_update_state(rs, contact)
+}
+
+func resource_dnsimple_contact_update(
+	s *terraform.ResourceState,
+	d *terraform.ResourceDiff,
+	meta interface{}) (*terraform.ResourceState, error) {
+	p := meta.(*ResourceProvider)
+	client := p.registrar
+	rs := s.MergeDiff(d)
+
+	// Contacts are replaced as a whole, so the merged state is sent
+	updateContact := resource_dnsimple_contact_expand(rs)
+	log.Printf("[DEBUG] contact update configuration: %#v", updateContact)
+
+	if err := client.UpdateContact(rs.ID, updateContact); err != nil {
+		return rs, fmt.Errorf("Failed to update contact: %s", err)
+	}
+
+	return resource_dnsimple_contact_refresh(rs, meta)
+}
+
+func resource_dnsimple_contact_destroy(
+	s *terraform.ResourceState,
+	meta interface{}) error {
+	p := meta.(*ResourceProvider)
+	client := p.registrar
+
+	log.Printf("[INFO] Deleting contact: %s", s.ID)
+
+	err := client.DeleteContact(s.ID)
+	if e, ok := err.(*RegistrarError); ok && e.StatusCode == 404 {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Error deleting contact: %s", err)
+	}
+
+	return nil
+}
+
+func resource_dnsimple_contact_refresh(
+	s *terraform.ResourceState,
+	meta interface{}) (*terraform.ResourceState, error) {
+	p := meta.(*ResourceProvider)
+	client := p.registrar
+
+	contact, err := client.Contact(s.ID)
+
+	// Handle remotely deleted contacts
+	if e, ok := err.(*RegistrarError); ok && e.StatusCode == 404 {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error retrieving contact: %s", err)
+	}
+
+	return resource_dnsimple_contact_update_state(s, contact)
+}
+
+func resource_dnsimple_contact_diff(
+	s *terraform.ResourceState,
+	c *terraform.ResourceConfig,
+	meta interface{}) (*terraform.ResourceDiff, error) {
+
+	b := &diff.ResourceBuilder{
+		Attrs: map[string]diff.AttrType{
+			"first_name":        diff.AttrTypeUpdate,
+			"last_name":         diff.AttrTypeUpdate,
+			"organization_name": diff.AttrTypeUpdate,
+			"address1":          diff.AttrTypeUpdate,
+			"address2":          diff.AttrTypeUpdate,
+			"city":              diff.AttrTypeUpdate,
+			"state_province":    diff.AttrTypeUpdate,
+			"postal_code":       diff.AttrTypeUpdate,
+			"country":           diff.AttrTypeUpdate,
+			"email_address":     diff.AttrTypeUpdate,
+			"phone":             diff.AttrTypeUpdate,
+		},
+	}
+
+	return b.Diff(s, c)
+}
+
+func resource_dnsimple_contact_expand(s *terraform.ResourceState) *Contact {
+	return &Contact{
+		FirstName:        s.Attributes["first_name"],
+		LastName:         s.Attributes["last_name"],
+		OrganizationName: s.Attributes["organization_name"],
+		Address1:         s.Attributes["address1"],
+		Address2:         s.Attributes["address2"],
+		City:             s.Attributes["city"],
+		StateProvince:    s.Attributes["state_province"],
+		PostalCode:       s.Attributes["postal_code"],
+		Country:          s.Attributes["country"],
+		EmailAddress:     s.Attributes["email_address"],
+		Phone:            s.Attributes["phone"],
+	}
+}
+
+func resource_dnsimple_contact_update_state(
+	s *terraform.ResourceState,
+	contact *Contact) (*terraform.ResourceState, error) {
+	s.Attributes["first_name"] = contact.FirstName
+	s.Attributes["last_name"] = contact.LastName
+	s.Attributes["address1"] = contact.Address1
+	s.Attributes["city"] = contact.City
+	s.Attributes["state_province"] = contact.StateProvince
+	s.Attributes["postal_code"] = contact.PostalCode
+	s.Attributes["country"] = contact.Country
+	s.Attributes["email_address"] = contact.EmailAddress
+	s.Attributes["phone"] = contact.Phone
+
+	// The optional ones are only kept if they're set, so that leaving
+	// them out of the configuration isn't a change
+	optional := map[string]string{
+		"organization_name": contact.OrganizationName,
+		"address2":          contact.Address2,
+	}
+	for k, v := range optional {
+		if v != "" {
+			s.Attributes[k] = v
+		} else {
+			delete(s.Attributes, k)
+		}
+	}
+
+	return s, nil
+}
+
+func resource_dnsimple_contact_validation() *config.Validator {
+	return &config.Validator{
+		Required: []string{
+			"first_name",
+			"last_name",
+			"address1",
+			"city",
+			"state_province",
+			"postal_code",
+			"country",
+			"email_address",
+			"phone",
+		},
+		Optional: []string{
+			"organization_name",
+			"address2",
+		},
+	}
+}
@@ -0,0 +1,129 @@
+package dnsimple
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccDNSimpleContact_Updated(t *testing.T) {
+	var contact Contact
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDNSimpleContactDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDNSimpleContactConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDNSimpleContactExists("dnsimple_contact.foobar", &contact),
+					testAccCheckDNSimpleContactAttributes(&contact, "1 Main St"),
+					resource.TestCheckResourceAttr(
+						"dnsimple_contact.foobar", "first_name", "Terraform"),
+					resource.TestCheckResourceAttr(
+						"dnsimple_contact.foobar", "address1", "1 Main St"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccCheckDNSimpleContactConfig_new_value,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDNSimpleContactExists("dnsimple_contact.foobar", &contact),
+					testAccCheckDNSimpleContactAttributes(&contact, "2 Main St"),
+					resource.TestCheckResourceAttr(
+						"dnsimple_contact.foobar", "address1", "2 Main St"),
+					resource.TestCheckResourceAttr(
+						"dnsimple_contact.foobar", "address2", "Suite 100"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDNSimpleContactDestroy(s *terraform.State) error {
+	client := testAccProvider.registrar
+
+	for _, rs := range s.Resources {
+		if rs.Type != "dnsimple_contact" {
+			continue
+		}
+
+		_, err := client.Contact(rs.ID)
+
+		if err == nil {
+			return fmt.Errorf("Contact still exists")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckDNSimpleContactAttributes(contact *Contact, address string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+
+		if contact.Address1 != address {
+			return fmt.Errorf("Bad address1: %s", contact.Address1)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckDNSimpleContactExists(n string, contact *Contact) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.Resources[n]
+
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.ID == "" {
+			return fmt.Errorf("No Contact ID is set")
+		}
+
+		client := testAccProvider.registrar
+
+		foundContact, err := client.Contact(rs.ID)
+
+		if err != nil {
+			return err
+		}
+
+		if fmt.Sprintf("%d", foundContact.Id) != rs.ID {
+			return fmt.Errorf("Contact not found")
+		}
+
+		*contact = *foundContact
+
+		return nil
+	}
+}
+
+const testAccCheckDNSimpleContactConfig_basic = `
+resource "dnsimple_contact" "foobar" {
+	first_name = "Terraform"
+	last_name = "Test"
+	address1 = "1 Main St"
+	city = "San Francisco"
+	state_province = "CA"
+	postal_code = "94107"
+	country = "US"
+	email_address = "terraform@example.com"
+	phone = "+1 415 555 0100"
+}`
+
+const testAccCheckDNSimpleContactConfig_new_value = `
+resource "dnsimple_contact" "foobar" {
+	first_name = "Terraform"
+	last_name = "Test"
+	address1 = "2 Main St"
+	address2 = "Suite 100"
+	city = "San Francisco"
+	state_province = "CA"
+	postal_code = "94107"
+	country = "US"
+	email_address = "terraform@example.com"
+	phone = "+1 415 555 0100"
+}`
@@ -0,0 +1,132 @@
+package dnsimple
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/config"
+	"github.com/hashicorp/terraform/helper/diff"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func resource_dnsimple_domain_registration_create(
+	s *terraform.ResourceState,
+	d *terraform.ResourceDiff,
+	meta interface{}) (*terraform.ResourceState, error) {
+	p := meta.(*ResourceProvider)
+	client := p.registrar
+
+	rs := s.MergeDiff(d)
+
+	name := rs.Attributes["name"]
+	registrantId, err := strconv.Atoi(rs.Attributes["registrant_id"])
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing registrant_id: %s", err)
+	}
+
+	log.Printf("[DEBUG] domain registration: %s, registrant %d", name, registrantId)
+	if _, err := client.RegisterDomain(name, registrantId); err != nil {
+		return nil, fmt.Errorf("Failed to register domain: %s", err)
+	}
+
+	rs.ID = name
+	log.Printf("[INFO] domain ID: %s", rs.ID)
+
+	if rs.Attributes["auto_renew"] == "true" {
+		if err := client.SetAutoRenew(name, true); err != nil {
+			return rs, fmt.Errorf("Failed to enable auto renewal: %s", err)
+		}
+	}
+
+	return resource_dnsimple_domain_registration_refresh(rs, meta)
+}
+
+func resource_dnsimple_domain_registration_update(
+	s *terraform.ResourceState,
+	d *terraform.ResourceDiff,
+	meta interface{}) (*terraform.ResourceState, error) {
+	p := meta.(*ResourceProvider)
+	client := p.registrar
+	rs := s.MergeDiff(d)
+
+	if attr, ok := d.Attributes["auto_renew"]; ok {
+		if err := client.SetAutoRenew(rs.ID, attr.New == "true"); err != nil {
+			return rs, fmt.Errorf("Failed to change auto renewal: %s", err)
+		}
+	}
+
+	return resource_dnsimple_domain_registration_refresh(rs, meta)
+}
+
+func resource_dnsimple_domain_registration_destroy(
+	s *terraform.ResourceState,
+	meta interface{}) error {
+	// Registered domains can't be given back, they only expire
+	log.Printf(
+		"[INFO] Leaving domain %s registered, it expires on %s",
+		s.ID, s.Attributes["expires_on"])
+
+	return nil
+}
+
+func resource_dnsimple_domain_registration_refresh(
+	s *terraform.ResourceState,
+	meta interface{}) (*terraform.ResourceState, error) {
+	p := meta.(*ResourceProvider)
+	client := p.registrar
+
+	domain, err := client.Domain(s.ID)
+
+	// Handle domains that were removed from the account
+	if e, ok := err.(*RegistrarError); ok && e.StatusCode == 404 {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error retrieving domain: %s", err)
+	}
+
+	s.Attributes["name"] = domain.Name
+	s.Attributes["auto_renew"] = strconv.FormatBool(domain.AutoRenew)
+	s.Attributes["state"] = domain.State
+	s.Attributes["expires_on"] = domain.ExpiresOn
+	if domain.RegistrantId != 0 {
+		s.Attributes["registrant_id"] = strconv.Itoa(domain.RegistrantId)
+	}
+
+	return s, nil
+}
+
+func resource_dnsimple_domain_registration_diff(
+	s *terraform.ResourceState,
+	c *terraform.ResourceConfig,
+	meta interface{}) (*terraform.ResourceDiff, error) {
+
+	b := &diff.ResourceBuilder{
+		Attrs: map[string]diff.AttrType{
+			"name":          diff.AttrTypeCreate,
+			"registrant_id": diff.AttrTypeCreate,
+			"auto_renew":    diff.AttrTypeUpdate,
+		},
+
+		ComputedAttrs: []string{
+			"auto_renew",
+			"state",
+			"expires_on",
+		},
+	}
+
+	return b.Diff(s, c)
+}
+
+func resource_dnsimple_domain_registration_validation() *config.Validator {
+	return &config.Validator{
+		Required: []string{
+			"name",
+			"registrant_id",
+		},
+		Optional: []string{
+			"auto_renew",
+		},
+	}
+}
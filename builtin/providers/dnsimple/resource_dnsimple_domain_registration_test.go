@@ -0,0 +1,107 @@
+package dnsimple
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// Registering a domain costs money and can't be undone, so the domain and
+// the contact that registers it are given rather than made by the test.
+// The domain stays registered afterwards.
+func TestAccDNSimpleDomainRegistration_Updated(t *testing.T) {
+	var domain Domain
+	name := os.Getenv("DNSIMPLE_TEST_REGISTER_DOMAIN")
+	registrant := os.Getenv("DNSIMPLE_TEST_REGISTRANT_ID")
+	if name == "" || registrant == "" {
+		t.Skip("DNSIMPLE_TEST_REGISTER_DOMAIN and DNSIMPLE_TEST_REGISTRANT_ID must be set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: fmt.Sprintf(
+					testAccCheckDNSimpleDomainRegistrationConfig, name, registrant, "false"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDNSimpleDomainRegistrationExists(
+						"dnsimple_domain_registration.foobar", &domain),
+					testAccCheckDNSimpleDomainRegistrationAttributes(&domain, false),
+					resource.TestCheckResourceAttr(
+						"dnsimple_domain_registration.foobar", "name", name),
+					resource.TestCheckResourceAttr(
+						"dnsimple_domain_registration.foobar", "registrant_id", registrant),
+					resource.TestCheckResourceAttr(
+						"dnsimple_domain_registration.foobar", "auto_renew", "false"),
+				),
+			},
+			resource.TestStep{
+				Config: fmt.Sprintf(
+					testAccCheckDNSimpleDomainRegistrationConfig, name, registrant, "true"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDNSimpleDomainRegistrationExists(
+						"dnsimple_domain_registration.foobar", &domain),
+					testAccCheckDNSimpleDomainRegistrationAttributes(&domain, true),
+					resource.TestCheckResourceAttr(
+						"dnsimple_domain_registration.foobar", "auto_renew", "true"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDNSimpleDomainRegistrationAttributes(domain *Domain, autoRenew bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+
+		if domain.State != "registered" {
+			return fmt.Errorf("Bad state: %s", domain.State)
+		}
+
+		if domain.AutoRenew != autoRenew {
+			return fmt.Errorf("Bad auto_renew: %t", domain.AutoRenew)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckDNSimpleDomainRegistrationExists(n string, domain *Domain) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.Resources[n]
+
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.ID == "" {
+			return fmt.Errorf("No Domain ID is set")
+		}
+
+		client := testAccProvider.registrar
+
+		foundDomain, err := client.Domain(rs.ID)
+
+		if err != nil {
+			return err
+		}
+
+		if foundDomain.Name != rs.ID {
+			return fmt.Errorf("Domain not found")
+		}
+
+		*domain = *foundDomain
+
+		return nil
+	}
+}
+
+const testAccCheckDNSimpleDomainRegistrationConfig = `
+resource "dnsimple_domain_registration" "foobar" {
+	name = "%s"
+	registrant_id = "%s"
+	auto_renew = "%s"
+}`
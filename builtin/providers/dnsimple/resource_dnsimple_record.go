@@ -36,6 +36,7 @@ func resource_dnsimple_record_create(
 	log.Printf("[DEBUG] record create configuration: %#v", newRecord)
 
 	recId, err := client.CreateRecord(rs.Attributes["domain"], &newRecord)
+	p.forgetRecords(rs.Attributes["domain"])
 
 	if err != nil {
 		return nil, fmt.Errorf("Failed to create record: %s", err)
@@ -81,6 +82,7 @@ func resource_dnsimple_record_update(
 	log.Printf("[DEBUG] record update configuration: %#v", updateRecord)
 
 	_, err := client.UpdateRecord(rs.Attributes["domain"], rs.ID, &updateRecord)
+	p.forgetRecords(rs.Attributes["domain"])
 	if err != nil {
 		return rs, fmt.Errorf("Failed to update record: %s", err)
 	}
@@ -102,6 +104,7 @@ func resource_dnsimple_record_destroy(
 	log.Printf("[INFO] Deleting record: %s, %s", s.Attributes["domain"], s.ID)
 
 	err := client.DestroyRecord(s.Attributes["domain"], s.ID)
+	p.forgetRecords(s.Attributes["domain"])
 
 	if err != nil {
 		return fmt.Errorf("Error deleting record: %s", err)
@@ -114,9 +117,8 @@ func resource_dnsimple_record_refresh(
 	s *terraform.ResourceState,
 	meta interface{}) (*terraform.ResourceState, error) {
 	p := meta.(*ResourceProvider)
-	client := p.client
 
-	rec, err := resource_dnsimple_record_retrieve(s.Attributes["domain"], s.ID, client)
+	rec, err := p.record(s.Attributes["domain"], s.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -181,6 +183,49 @@ func resource_dnsimple_record_retrieve(domain string, id string, client *dnsimpl
 	return record, nil
 }
 
+// record returns the record of the domain with the given ID. All the
+// records of the domain are retrieved at once the first time, so that
+// domains with many records refresh quickly.
+func (p *ResourceProvider) record(domain string, id string) (*dnsimple.Record, error) {
+	p.recordsLock.Lock()
+	defer p.recordsLock.Unlock()
+
+	records, ok := p.records[domain]
+	if !ok {
+		log.Printf("[DEBUG] Retrieving all records of %s", domain)
+		list, err := p.client.GetRecords(domain)
+		if err != nil {
+			return nil, fmt.Errorf("Error retrieving records: %s", err)
+		}
+
+		records = make(map[string]*dnsimple.Record, len(list))
+		for i, _ := range list {
+			records[list[i].StringId()] = &list[i]
+		}
+
+		if p.records == nil {
+			p.records = make(map[string]map[string]*dnsimple.Record)
+		}
+		p.records[domain] = records
+	}
+
+	if rec, ok := records[id]; ok {
+		return rec, nil
+	}
+
+	// The record may be newer than the cache, or gone
+	return resource_dnsimple_record_retrieve(domain, id, p.client)
+}
+
+// forgetRecords drops the cached records of the domain after they were
+// changed.
+func (p *ResourceProvider) forgetRecords(domain string) {
+	p.recordsLock.Lock()
+	defer p.recordsLock.Unlock()
+
+	delete(p.records, domain)
+}
+
 func resource_dnsimple_record_validation() *config.Validator {
 	return &config.Validator{
 		Required: []string{
@@ -2,6 +2,7 @@ package dnsimple
 
 import (
 	"log"
+	"sync"
 
 	"github.com/hashicorp/terraform/helper/config"
 	"github.com/hashicorp/terraform/terraform"
@@ -11,18 +12,17 @@ import (
 type ResourceProvider struct {
 	Config Config
 
-	client *dnsimple.Client
+	client    *dnsimple.Client
+	registrar *RegistrarClient
+
+	// records caches the records of each domain, so that refreshing many
+	// records of a domain takes a single request. See recordsLock.
+	records     map[string]map[string]*dnsimple.Record
+	recordsLock sync.Mutex
 }
 
 func (p *ResourceProvider) Validate(c *terraform.ResourceConfig) ([]string, []error) {
-	v := &config.Validator{
-		Required: []string{
-			"token",
-			"email",
-		},
-	}
-
-	return v.Validate(c)
+	return config.NewConfigSchemaValidator(p.ConfigSchema()).Validate(c)
 }
 
 func (p *ResourceProvider) ValidateResource(
@@ -43,9 +43,50 @@ func (p *ResourceProvider) Configure(c *terraform.ResourceConfig) error {
 		return err
 	}
 
+	p.registrar = &RegistrarClient{
+		Email: p.Config.Email,
+		Token: p.Config.Token,
+	}
+
 	return nil
 }
 
+func (p *ResourceProvider) ValidateCredentials() error {
+	// The DNSimple client has no call that is cheap enough to check the
+	// credentials with, so they're checked by the first real request.
+	return nil
+}
+
+func (p *ResourceProvider) WriteOnlyConfig() []string {
+	return []string{"token"}
+}
+
+func (p *ResourceProvider) ConfigSchema() []terraform.ConfigAttribute {
+	return []terraform.ConfigAttribute{
+		terraform.ConfigAttribute{
+			Name:     "email",
+			Required: true,
+			EnvVars:  []string{"DNSIMPLE_EMAIL"},
+		},
+		terraform.ConfigAttribute{
+			Name:      "token",
+			Required:  true,
+			WriteOnly: true,
+			EnvVars:   []string{"DNSIMPLE_TOKEN"},
+		},
+	}
+}
+
+func (p *ResourceProvider) Stop() error {
+	// The API calls of the provider can't be canceled, so they're left
+	// to finish.
+	return nil
+}
+
+func (p *ResourceProvider) Capabilities() terraform.ResourceProviderCapabilities {
+	return terraform.ResourceProviderCapabilities{}
+}
+
 func (p *ResourceProvider) Apply(
 	s *terraform.ResourceState,
 	d *terraform.ResourceDiff) (*terraform.ResourceState, error) {
@@ -63,6 +104,11 @@ func (p *ResourceProvider) Refresh(
 	return resourceMap.Refresh(s, p)
 }
 
+func (p *ResourceProvider) ListResources(
+	t string, filters map[string]string) ([]*terraform.ResourceState, error) {
+	return nil, terraform.ErrListNotSupported
+}
+
 func (p *ResourceProvider) Resources() []terraform.ResourceType {
 	return resourceMap.Resources()
 }
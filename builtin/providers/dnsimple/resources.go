@@ -11,6 +11,24 @@ var resourceMap *resource.Map
 func init() {
 	resourceMap = &resource.Map{
 		Mapping: map[string]resource.Resource{
+			"dnsimple_contact": resource.Resource{
+				ConfigValidator: resource_dnsimple_contact_validation(),
+				Create:          resource_dnsimple_contact_create,
+				Destroy:         resource_dnsimple_contact_destroy,
+				Diff:            resource_dnsimple_contact_diff,
+				Update:          resource_dnsimple_contact_update,
+				Refresh:         resource_dnsimple_contact_refresh,
+			},
+
+			"dnsimple_domain_registration": resource.Resource{
+				ConfigValidator: resource_dnsimple_domain_registration_validation(),
+				Create:          resource_dnsimple_domain_registration_create,
+				Destroy:         resource_dnsimple_domain_registration_destroy,
+				Diff:            resource_dnsimple_domain_registration_diff,
+				Update:          resource_dnsimple_domain_registration_update,
+				Refresh:         resource_dnsimple_domain_registration_refresh,
+			},
+
 			"dnsimple_record": resource.Resource{
 				ConfigValidator: resource_dnsimple_record_validation(),
 				Create:          resource_dnsimple_record_create,
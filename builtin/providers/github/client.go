@@ -0,0 +1,422 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// DefaultURL is the API of github.com. GitHub Enterprise has its API
+// at /api/v3 of the server.
+const DefaultURL = "https://api.github.com"
+
+// Client manages the repositories and teams of a GitHub organization,
+// or of the user the token belongs to if no organization is set.
+type Client struct {
+	URL          string
+	Token        string
+	Organization string
+
+	HTTPClient *http.Client
+
+	login string
+	lock  sync.Mutex
+}
+
+// GitHubError is an error returned by the GitHub API.
+type GitHubError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *GitHubError) Error() string {
+	return fmt.Sprintf("GitHub error (%d): %s", e.StatusCode, e.Message)
+}
+
+// isNotFound returns whether the error is GitHub reporting that the
+// object doesn't exist.
+func isNotFound(err error) bool {
+	ghErr, ok := err.(*GitHubError)
+	return ok && ghErr.StatusCode == 404
+}
+
+// User is a GitHub user.
+type User struct {
+	Login string `json:"login"`
+}
+
+// Repository is a GitHub repository.
+type Repository struct {
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	Homepage     string `json:"homepage"`
+	Private      bool   `json:"private"`
+	HasIssues    bool   `json:"has_issues"`
+	HasWiki      bool   `json:"has_wiki"`
+	HasDownloads bool   `json:"has_downloads"`
+	AutoInit     bool   `json:"auto_init,omitempty"`
+
+	FullName      string `json:"full_name,omitempty"`
+	DefaultBranch string `json:"default_branch,omitempty"`
+	HTMLURL       string `json:"html_url,omitempty"`
+	SSHURL        string `json:"ssh_url,omitempty"`
+	CloneURL      string `json:"clone_url,omitempty"`
+}
+
+// TeamMembership is the membership of a user in a team. The state is
+// "pending" until the user accepts the invitation to the organization.
+type TeamMembership struct {
+	Role  string `json:"role"`
+	State string `json:"state,omitempty"`
+}
+
+// BranchProtection are the rules that protect a branch.
+type BranchProtection struct {
+	// Contexts are the status checks that have to pass before a branch
+	// is merged, and Strict requires the branch to be up to date first.
+	Contexts []string
+	Strict   bool
+
+	// RequiredReviews is the number of approving reviews pull requests
+	// need, where 0 doesn't require reviews.
+	RequiredReviews     int
+	DismissStaleReviews bool
+
+	// EnforceAdmins applies the rules to administrators too.
+	EnforceAdmins bool
+}
+
+// Hook is a webhook of a repository.
+type Hook struct {
+	Id     int               `json:"id,omitempty"`
+	Name   string            `json:"name"`
+	Active bool              `json:"active"`
+	Events []string          `json:"events"`
+	Config map[string]string `json:"config"`
+}
+
+// User returns the user the token belongs to.
+func (c *Client) User() (*User, error) {
+	var user User
+	if err := c.request("GET", "/user", nil, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// Owner returns the owner of the repositories that are managed, which is
+// the organization or the user the token belongs to.
+func (c *Client) Owner() (string, error) {
+	if c.Organization != "" {
+		return c.Organization, nil
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.login == "" {
+		user, err := c.User()
+		if err != nil {
+			return "", err
+		}
+
+		c.login = user.Login
+	}
+
+	return c.login, nil
+}
+
+// CreateRepository creates a repository of the owner.
+func (c *Client) CreateRepository(repo *Repository) (*Repository, error) {
+	path := "/user/repos"
+	if c.Organization != "" {
+		path = "/orgs/" + c.Organization + "/repos"
+	}
+
+	var result Repository
+	if err := c.request("POST", path, repo, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// Repository returns the repository of the owner with the given name.
+func (c *Client) Repository(name string) (*Repository, error) {
+	path, err := c.repoPath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var result Repository
+	if err := c.request("GET", path, nil, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// EditRepository changes the repository with the given name.
+func (c *Client) EditRepository(name string, repo *Repository) error {
+	path, err := c.repoPath(name)
+	if err != nil {
+		return err
+	}
+
+	return c.request("PATCH", path, repo, nil)
+}
+
+// DeleteRepository deletes the repository with the given name.
+func (c *Client) DeleteRepository(name string) error {
+	path, err := c.repoPath(name)
+	if err != nil {
+		return err
+	}
+
+	return c.request("DELETE", path, nil, nil)
+}
+
+// SetTeamMembership adds the user to the team, or changes their role if
+// they are a member already.
+func (c *Client) SetTeamMembership(team string, username string, role string) error {
+	return c.request(
+		"PUT", teamMembershipPath(team, username), &TeamMembership{Role: role}, nil)
+}
+
+// TeamMembership returns the membership of the user in the team.
+func (c *Client) TeamMembership(team string, username string) (*TeamMembership, error) {
+	var result TeamMembership
+	err := c.request("GET", teamMembershipPath(team, username), nil, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// DeleteTeamMembership removes the user from the team.
+func (c *Client) DeleteTeamMembership(team string, username string) error {
+	return c.request("DELETE", teamMembershipPath(team, username), nil, nil)
+}
+
+// branchProtection is how the API represents the protection of a branch
+// when it's read.
+type branchProtection struct {
+	RequiredStatusChecks *struct {
+		Strict   bool     `json:"strict"`
+		Contexts []string `json:"contexts"`
+	} `json:"required_status_checks"`
+	EnforceAdmins *struct {
+		Enabled bool `json:"enabled"`
+	} `json:"enforce_admins"`
+	RequiredPullRequestReviews *struct {
+		DismissStaleReviews          bool `json:"dismiss_stale_reviews"`
+		RequiredApprovingReviewCount int  `json:"required_approving_review_count"`
+	} `json:"required_pull_request_reviews"`
+}
+
+// BranchProtection returns the protection of the branch of a repository.
+// It's a 404 error if the branch isn't protected.
+func (c *Client) BranchProtection(repo string, branch string) (*BranchProtection, error) {
+	path, err := c.branchProtectionPath(repo, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	var result branchProtection
+	if err := c.request("GET", path, nil, &result); err != nil {
+		return nil, err
+	}
+
+	var p BranchProtection
+	if v := result.RequiredStatusChecks; v != nil {
+		p.Contexts = v.Contexts
+		p.Strict = v.Strict
+	}
+	if v := result.EnforceAdmins; v != nil {
+		p.EnforceAdmins = v.Enabled
+	}
+	if v := result.RequiredPullRequestReviews; v != nil {
+		p.RequiredReviews = v.RequiredApprovingReviewCount
+		p.DismissStaleReviews = v.DismissStaleReviews
+	}
+
+	return &p, nil
+}
+
+// SetBranchProtection protects the branch of a repository, replacing any
+// rules it was protected with.
+func (c *Client) SetBranchProtection(repo string, branch string, p *BranchProtection) error {
+	path, err := c.branchProtectionPath(repo, branch)
+	if err != nil {
+		return err
+	}
+
+	// All of the keys have to be sent, with null turning a rule off
+	body := map[string]interface{}{
+		"required_status_checks":        nil,
+		"enforce_admins":                p.EnforceAdmins,
+		"required_pull_request_reviews": nil,
+		"restrictions":                  nil,
+	}
+	if p.Strict || len(p.Contexts) > 0 {
+		contexts := p.Contexts
+		if contexts == nil {
+			contexts = []string{}
+		}
+
+		body["required_status_checks"] = map[string]interface{}{
+			"strict":   p.Strict,
+			"contexts": contexts,
+		}
+	}
+	if p.RequiredReviews > 0 {
+		body["required_pull_request_reviews"] = map[string]interface{}{
+			"dismiss_stale_reviews":           p.DismissStaleReviews,
+			"required_approving_review_count": p.RequiredReviews,
+		}
+	}
+
+	return c.request("PUT", path, body, nil)
+}
+
+// DeleteBranchProtection removes the protection of the branch.
+func (c *Client) DeleteBranchProtection(repo string, branch string) error {
+	path, err := c.branchProtectionPath(repo, branch)
+	if err != nil {
+		return err
+	}
+
+	return c.request("DELETE", path, nil, nil)
+}
+
+// CreateHook creates a webhook of the repository and returns it with
+// its ID.
+func (c *Client) CreateHook(repo string, hook *Hook) (*Hook, error) {
+	path, err := c.repoPath(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var result Hook
+	if err := c.request("POST", path+"/hooks", hook, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// Hook returns the webhook of the repository with the given ID.
+func (c *Client) Hook(repo string, id string) (*Hook, error) {
+	path, err := c.repoPath(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var result Hook
+	if err := c.request("GET", path+"/hooks/"+id, nil, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// EditHook changes the webhook of the repository with the given ID.
+func (c *Client) EditHook(repo string, id string, hook *Hook) error {
+	path, err := c.repoPath(repo)
+	if err != nil {
+		return err
+	}
+
+	return c.request("PATCH", path+"/hooks/"+id, hook, nil)
+}
+
+// DeleteHook deletes the webhook of the repository with the given ID.
+func (c *Client) DeleteHook(repo string, id string) error {
+	path, err := c.repoPath(repo)
+	if err != nil {
+		return err
+	}
+
+	return c.request("DELETE", path+"/hooks/"+id, nil, nil)
+}
+
+func (c *Client) repoPath(name string) (string, error) {
+	owner, err := c.Owner()
+	if err != nil {
+		return "", err
+	}
+
+	return "/repos/" + owner + "/" + name, nil
+}
+
+func (c *Client) branchProtectionPath(repo string, branch string) (string, error) {
+	path, err := c.repoPath(repo)
+	if err != nil {
+		return "", err
+	}
+
+	return path + "/branches/" + branch + "/protection", nil
+}
+
+func teamMembershipPath(team string, username string) string {
+	return "/teams/" + team + "/memberships/" + username
+}
+
+func (c *Client) request(
+	method string, path string, body interface{}, result interface{}) error {
+	var raw []byte
+	if body != nil {
+		var err error
+		if raw, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	base := strings.TrimSuffix(c.URL, "/")
+	if base == "" {
+		base = DefaultURL
+	}
+
+	req, err := http.NewRequest(method, base+path, bytes.NewReader(raw))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("Authorization", "token "+c.Token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		var e struct {
+			Message string `json:"message"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&e); err != nil || e.Message == "" {
+			e.Message = resp.Status
+		}
+
+		return &GitHubError{StatusCode: resp.StatusCode, Message: e.Message}
+	}
+
+	if result == nil || resp.StatusCode == 204 {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
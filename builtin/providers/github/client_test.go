@@ -0,0 +1,256 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestClient_owner(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Authorization") != "token secret" {
+			w.WriteHeader(401)
+			fmt.Fprint(w, `{"message":"Bad credentials"}`)
+			return
+		}
+
+		fmt.Fprint(w, `{"login":"octocat"}`)
+	}))
+	defer ts.Close()
+
+	c := &Client{URL: ts.URL, Token: "secret"}
+	for i := 0; i < 2; i++ {
+		owner, err := c.Owner()
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if owner != "octocat" {
+			t.Fatalf("bad: %s", owner)
+		}
+	}
+	if requests != 1 {
+		t.Fatalf("bad: %d", requests)
+	}
+
+	c = &Client{URL: ts.URL, Token: "secret", Organization: "hashicorp"}
+	if owner, _ := c.Owner(); owner != "hashicorp" || requests != 1 {
+		t.Fatalf("bad: %s", owner)
+	}
+
+	c = &Client{URL: ts.URL, Token: "wrong"}
+	_, err := c.User()
+	ghErr, ok := err.(*GitHubError)
+	if !ok || ghErr.StatusCode != 401 || ghErr.Message != "Bad credentials" {
+		t.Fatalf("bad: %#v", err)
+	}
+}
+
+func TestClient_repository(t *testing.T) {
+	var requests []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method + " " + r.URL.Path {
+		case "POST /orgs/hashicorp/repos":
+			var repo Repository
+			json.NewDecoder(r.Body).Decode(&repo)
+			if repo.Name != "foo" || !repo.Private || !repo.AutoInit {
+				t.Errorf("bad: %#v", repo)
+			}
+			repo.FullName = "hashicorp/foo"
+			json.NewEncoder(w).Encode(&repo)
+		case "GET /repos/hashicorp/foo":
+			fmt.Fprint(w, `{"name":"foo","full_name":"hashicorp/foo","default_branch":"master"}`)
+		case "DELETE /repos/hashicorp/foo":
+			w.WriteHeader(204)
+		default:
+			w.WriteHeader(404)
+			fmt.Fprint(w, `{"message":"Not Found"}`)
+		}
+	}))
+	defer ts.Close()
+
+	c := &Client{URL: ts.URL, Token: "secret", Organization: "hashicorp"}
+
+	repo, err := c.CreateRepository(&Repository{Name: "foo", Private: true, AutoInit: true})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if repo.FullName != "hashicorp/foo" {
+		t.Fatalf("bad: %#v", repo)
+	}
+
+	repo, err = c.Repository("foo")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if repo.DefaultBranch != "master" {
+		t.Fatalf("bad: %#v", repo)
+	}
+
+	if err := c.DeleteRepository("foo"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if _, err := c.Repository("bar"); !isNotFound(err) {
+		t.Fatalf("bad: %#v", err)
+	}
+
+	expected := []string{
+		"POST /orgs/hashicorp/repos",
+		"GET /repos/hashicorp/foo",
+		"DELETE /repos/hashicorp/foo",
+		"GET /repos/hashicorp/bar",
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Fatalf("bad: %#v", requests)
+	}
+}
+
+func TestClient_branchProtection(t *testing.T) {
+	var body map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/hashicorp/foo/branches/master/protection" {
+			w.WriteHeader(404)
+			fmt.Fprint(w, `{"message":"Branch not protected"}`)
+			return
+		}
+
+		switch r.Method {
+		case "PUT":
+			body = nil
+			json.NewDecoder(r.Body).Decode(&body)
+			fmt.Fprint(w, `{}`)
+		case "GET":
+			fmt.Fprint(w, testBranchProtectionResponse)
+		}
+	}))
+	defer ts.Close()
+
+	c := &Client{URL: ts.URL, Token: "secret", Organization: "hashicorp"}
+
+	err := c.SetBranchProtection("foo", "master", &BranchProtection{
+		Contexts:        []string{"ci/travis"},
+		RequiredReviews: 2,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"required_status_checks": map[string]interface{}{
+			"strict":   false,
+			"contexts": []interface{}{"ci/travis"},
+		},
+		"enforce_admins": false,
+		"required_pull_request_reviews": map[string]interface{}{
+			"dismiss_stale_reviews":           false,
+			"required_approving_review_count": float64(2),
+		},
+		"restrictions": nil,
+	}
+	if !reflect.DeepEqual(body, expected) {
+		t.Fatalf("bad: %#v", body)
+	}
+
+	// Rules that are off are sent as null
+	if err := c.SetBranchProtection("foo", "master", &BranchProtection{}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected = map[string]interface{}{
+		"required_status_checks":        nil,
+		"enforce_admins":                false,
+		"required_pull_request_reviews": nil,
+		"restrictions":                  nil,
+	}
+	if !reflect.DeepEqual(body, expected) {
+		t.Fatalf("bad: %#v", body)
+	}
+
+	p, err := c.BranchProtection("foo", "master")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expectedProtection := &BranchProtection{
+		Contexts:            []string{"ci/travis"},
+		Strict:              true,
+		RequiredReviews:     1,
+		DismissStaleReviews: true,
+		EnforceAdmins:       true,
+	}
+	if !reflect.DeepEqual(p, expectedProtection) {
+		t.Fatalf("bad: %#v", p)
+	}
+
+	if _, err := c.BranchProtection("foo", "develop"); !isNotFound(err) {
+		t.Fatalf("bad: %#v", err)
+	}
+}
+
+func TestClient_hook(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /repos/hashicorp/foo/hooks":
+			var hook Hook
+			json.NewDecoder(r.Body).Decode(&hook)
+			if hook.Name != "web" || hook.Config["secret"] != "bar" {
+				t.Errorf("bad: %#v", hook)
+			}
+			hook.Id = 42
+			hook.Config["secret"] = "********"
+			json.NewEncoder(w).Encode(&hook)
+		case "GET /repos/hashicorp/foo/hooks/42":
+			fmt.Fprint(w, `{"id":42,"name":"web","active":true,"events":["push"],"config":{"url":"https://example.com/","insecure_ssl":"0"}}`)
+		default:
+			w.WriteHeader(404)
+			fmt.Fprint(w, `{"message":"Not Found"}`)
+		}
+	}))
+	defer ts.Close()
+
+	c := &Client{URL: ts.URL, Token: "secret", Organization: "hashicorp"}
+
+	hook, err := c.CreateHook("foo", &Hook{
+		Name:   "web",
+		Events: []string{"push"},
+		Config: map[string]string{"url": "https://example.com/", "secret": "bar"},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if hook.Id != 42 {
+		t.Fatalf("bad: %#v", hook)
+	}
+
+	hook, err = c.Hook("foo", "42")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !hook.Active || hook.Config["url"] != "https://example.com/" {
+		t.Fatalf("bad: %#v", hook)
+	}
+
+	if _, err := c.Hook("foo", "43"); !isNotFound(err) {
+		t.Fatalf("bad: %#v", err)
+	}
+}
+
+const testBranchProtectionResponse = `
+{
+  "required_status_checks": {
+    "strict": true,
+    "contexts": ["ci/travis"]
+  },
+  "enforce_admins": {
+    "enabled": true
+  },
+  "required_pull_request_reviews": {
+    "dismiss_stale_reviews": true,
+    "required_approving_review_count": 1
+  }
+}
+`
@@ -0,0 +1,33 @@
+package github
+
+import (
+	"fmt"
+	"log"
+)
+
+type Config struct {
+	Token        string `mapstructure:"token"`
+	Organization string `mapstructure:"organization"`
+	BaseURL      string `mapstructure:"base_url"`
+}
+
+// Client() returns a new client for the configured GitHub account.
+func (c *Config) Client() (*Client, error) {
+	if c.Token == "" {
+		return nil, fmt.Errorf(
+			"token must be set, or GITHUB_TOKEN in the environment")
+	}
+
+	if c.Organization != "" {
+		log.Printf("[INFO] GitHub Client configured for organization: %s",
+			c.Organization)
+	} else {
+		log.Printf("[INFO] GitHub Client configured for the token's user")
+	}
+
+	return &Client{
+		URL:          c.BaseURL,
+		Token:        c.Token,
+		Organization: c.Organization,
+	}, nil
+}
@@ -0,0 +1,63 @@
+package github
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mitchellh/mapstructure"
+)
+
+// Provider returns a terraform.ResourceProvider.
+func Provider() *schema.Provider {
+	return &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"token": &schema.Schema{
+				Type:      schema.TypeString,
+				Optional:  true,
+				WriteOnly: true,
+				EnvVars:   []string{"GITHUB_TOKEN"},
+			},
+
+			"organization": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				EnvVars:  []string{"GITHUB_ORGANIZATION"},
+			},
+
+			"base_url": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				EnvVars:  []string{"GITHUB_BASE_URL"},
+			},
+		},
+
+		ResourcesMap: map[string]*schema.Resource{
+			"github_branch_protection":  resourceGithubBranchProtection(),
+			"github_repository":         resourceGithubRepository(),
+			"github_repository_webhook": resourceGithubRepositoryWebhook(),
+			"github_team_membership":    resourceGithubTeamMembership(),
+		},
+
+		ConfigureFunc:           providerConfigure,
+		ValidateCredentialsFunc: providerValidateCredentials,
+	}
+}
+
+func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+	var config Config
+	configRaw := d.Get("").(map[string]interface{})
+	if err := mapstructure.Decode(configRaw, &config); err != nil {
+		return nil, err
+	}
+
+	return config.Client()
+}
+
+func providerValidateCredentials(meta interface{}) error {
+	client := meta.(*Client)
+	if _, err := client.User(); err != nil {
+		return fmt.Errorf("Error looking up GitHub user: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package github
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceGithubBranchProtection() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGithubBranchProtectionCreate,
+		Read:   resourceGithubBranchProtectionRead,
+		Update: resourceGithubBranchProtectionUpdate,
+		Delete: resourceGithubBranchProtectionDelete,
+
+		Schema: map[string]*schema.Schema{
+			"repository": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"branch": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"status_checks": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"strict_status_checks": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+
+			"required_reviews": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+
+			"dismiss_stale_reviews": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+
+			"enforce_admins": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+		},
+	}
+}
+
+func resourceGithubBranchProtectionSet(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	p := &BranchProtection{
+		Strict:              d.Get("strict_status_checks").(bool),
+		RequiredReviews:     d.Get("required_reviews").(int),
+		DismissStaleReviews: d.Get("dismiss_stale_reviews").(bool),
+		EnforceAdmins:       d.Get("enforce_admins").(bool),
+	}
+	for _, v := range d.Get("status_checks").([]interface{}) {
+		p.Contexts = append(p.Contexts, v.(string))
+	}
+
+	if p.DismissStaleReviews && p.RequiredReviews == 0 {
+		return fmt.Errorf("dismiss_stale_reviews requires required_reviews")
+	}
+
+	repo := d.Get("repository").(string)
+	branch := d.Get("branch").(string)
+
+	log.Printf("[DEBUG] Branch protection of %s in %s: %#v", branch, repo, p)
+	if err := client.SetBranchProtection(repo, branch, p); err != nil {
+		return fmt.Errorf("Error protecting branch: %s", err)
+	}
+
+	return nil
+}
+
+func resourceGithubBranchProtectionCreate(d *schema.ResourceData, meta interface{}) error {
+	if err := resourceGithubBranchProtectionSet(d, meta); err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", d.Get("repository"), d.Get("branch")))
+
+	return resourceGithubBranchProtectionRead(d, meta)
+}
+
+func resourceGithubBranchProtectionRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	p, err := client.BranchProtection(
+		d.Get("repository").(string), d.Get("branch").(string))
+	if err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+
+		return fmt.Errorf("Error retrieving branch protection: %s", err)
+	}
+
+	d.Set("status_checks", p.Contexts)
+	d.Set("strict_status_checks", p.Strict)
+	d.Set("required_reviews", p.RequiredReviews)
+	d.Set("dismiss_stale_reviews", p.DismissStaleReviews)
+	d.Set("enforce_admins", p.EnforceAdmins)
+
+	return nil
+}
+
+func resourceGithubBranchProtectionUpdate(d *schema.ResourceData, meta interface{}) error {
+	// The rules are replaced as a whole, so that rules removed from the
+	// configuration are turned off.
+	if err := resourceGithubBranchProtectionSet(d, meta); err != nil {
+		return err
+	}
+
+	return resourceGithubBranchProtectionRead(d, meta)
+}
+
+func resourceGithubBranchProtectionDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	log.Printf("[INFO] Deleting Branch protection: %s", d.Id())
+	err := client.DeleteBranchProtection(
+		d.Get("repository").(string), d.Get("branch").(string))
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting branch protection: %s", err)
+	}
+
+	return nil
+}
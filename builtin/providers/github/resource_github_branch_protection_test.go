@@ -0,0 +1,111 @@
+package github
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccGithubBranchProtection_Basic(t *testing.T) {
+	var p BranchProtection
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGithubBranchProtectionDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckGithubBranchProtectionConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGithubBranchProtectionExists(
+						"github_branch_protection.master", &p),
+					resource.TestCheckResourceAttr(
+						"github_branch_protection.master", "status_checks.0", "ci/travis"),
+					resource.TestCheckResourceAttr(
+						"github_branch_protection.master", "required_reviews", "1"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccCheckGithubBranchProtectionConfig_updated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGithubBranchProtectionExists(
+						"github_branch_protection.master", &p),
+					resource.TestCheckResourceAttr(
+						"github_branch_protection.master", "status_checks.#", "0"),
+					resource.TestCheckResourceAttr(
+						"github_branch_protection.master", "enforce_admins", "true"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckGithubBranchProtectionDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.Resources {
+		if rs.Type != "github_branch_protection" {
+			continue
+		}
+
+		_, err := client.BranchProtection(
+			rs.Attributes["repository"], rs.Attributes["branch"])
+		if err == nil {
+			return fmt.Errorf("Branch is still protected")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckGithubBranchProtectionExists(n string, p *BranchProtection) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.ID == "" {
+			return fmt.Errorf("No Branch protection ID is set")
+		}
+
+		client := testAccProvider.Meta().(*Client)
+
+		found, err := client.BranchProtection(
+			rs.Attributes["repository"], rs.Attributes["branch"])
+		if err != nil {
+			return err
+		}
+
+		*p = *found
+
+		return nil
+	}
+}
+
+const testAccCheckGithubBranchProtectionConfig_basic = `
+resource "github_repository" "foobar" {
+    name = "terraform-acc-test-protection"
+    auto_init = true
+}
+
+resource "github_branch_protection" "master" {
+    repository = "${github_repository.foobar.name}"
+    branch = "${github_repository.foobar.default_branch}"
+    status_checks = ["ci/travis"]
+    required_reviews = 1
+}`
+
+const testAccCheckGithubBranchProtectionConfig_updated = `
+resource "github_repository" "foobar" {
+    name = "terraform-acc-test-protection"
+    auto_init = true
+}
+
+resource "github_branch_protection" "master" {
+    repository = "${github_repository.foobar.name}"
+    branch = "${github_repository.foobar.default_branch}"
+    enforce_admins = true
+}`
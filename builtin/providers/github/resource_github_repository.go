@@ -0,0 +1,175 @@
+package github
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceGithubRepository() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGithubRepositoryCreate,
+		Read:   resourceGithubRepositoryRead,
+		Update: resourceGithubRepositoryUpdate,
+		Delete: resourceGithubRepositoryDelete,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"homepage_url": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"private": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+
+			"has_issues": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+
+			"has_wiki": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+
+			"has_downloads": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+
+			// Creates the repository with a README, so that it has a
+			// default branch that can be protected right away.
+			"auto_init": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"full_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"default_branch": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"html_url": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"ssh_clone_url": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"http_clone_url": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceGithubRepositoryOpts(d *schema.ResourceData) *Repository {
+	return &Repository{
+		Name:         d.Get("name").(string),
+		Description:  d.Get("description").(string),
+		Homepage:     d.Get("homepage_url").(string),
+		Private:      d.Get("private").(bool),
+		HasIssues:    d.Get("has_issues").(bool),
+		HasWiki:      d.Get("has_wiki").(bool),
+		HasDownloads: d.Get("has_downloads").(bool),
+	}
+}
+
+func resourceGithubRepositoryCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	opts := resourceGithubRepositoryOpts(d)
+	opts.AutoInit = d.Get("auto_init").(bool)
+
+	log.Printf("[DEBUG] Repository create configuration: %#v", opts)
+	repo, err := client.CreateRepository(opts)
+	if err != nil {
+		return fmt.Errorf("Error creating repository: %s", err)
+	}
+
+	d.SetId(repo.Name)
+	log.Printf("[INFO] Repository ID: %s", d.Id())
+
+	return resourceGithubRepositoryRead(d, meta)
+}
+
+func resourceGithubRepositoryRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	repo, err := client.Repository(d.Id())
+	if err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+
+		return fmt.Errorf("Error retrieving repository: %s", err)
+	}
+
+	d.Set("name", repo.Name)
+	d.Set("description", repo.Description)
+	d.Set("homepage_url", repo.Homepage)
+	d.Set("private", repo.Private)
+	d.Set("has_issues", repo.HasIssues)
+	d.Set("has_wiki", repo.HasWiki)
+	d.Set("has_downloads", repo.HasDownloads)
+	d.Set("full_name", repo.FullName)
+	d.Set("default_branch", repo.DefaultBranch)
+	d.Set("html_url", repo.HTMLURL)
+	d.Set("ssh_clone_url", repo.SSHURL)
+	d.Set("http_clone_url", repo.CloneURL)
+
+	return nil
+}
+
+func resourceGithubRepositoryUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	opts := resourceGithubRepositoryOpts(d)
+	log.Printf("[DEBUG] Repository update configuration: %#v", opts)
+
+	if err := client.EditRepository(d.Id(), opts); err != nil {
+		return fmt.Errorf("Error updating repository: %s", err)
+	}
+
+	return resourceGithubRepositoryRead(d, meta)
+}
+
+func resourceGithubRepositoryDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	log.Printf("[INFO] Deleting Repository: %s", d.Id())
+	if err := client.DeleteRepository(d.Id()); err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting repository: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package github
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccGithubRepository_Basic(t *testing.T) {
+	var repo Repository
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGithubRepositoryDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckGithubRepositoryConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGithubRepositoryExists("github_repository.foobar", &repo),
+					resource.TestCheckResourceAttr(
+						"github_repository.foobar", "name", "terraform-acc-test"),
+					resource.TestCheckResourceAttr(
+						"github_repository.foobar", "has_issues", "true"),
+					resource.TestCheckResourceAttr(
+						"github_repository.foobar", "default_branch", "master"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccCheckGithubRepositoryConfig_updated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGithubRepositoryExists("github_repository.foobar", &repo),
+					resource.TestCheckResourceAttr(
+						"github_repository.foobar", "description", "Updated"),
+					resource.TestCheckResourceAttr(
+						"github_repository.foobar", "has_issues", "false"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckGithubRepositoryDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.Resources {
+		if rs.Type != "github_repository" {
+			continue
+		}
+
+		_, err := client.Repository(rs.ID)
+		if err == nil {
+			return fmt.Errorf("Repository still exists")
+		}
+		if !isNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckGithubRepositoryExists(n string, repo *Repository) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.ID == "" {
+			return fmt.Errorf("No Repository ID is set")
+		}
+
+		client := testAccProvider.Meta().(*Client)
+
+		found, err := client.Repository(rs.ID)
+		if err != nil {
+			return err
+		}
+
+		if found.Name != rs.ID {
+			return fmt.Errorf("Repository not found")
+		}
+
+		*repo = *found
+
+		return nil
+	}
+}
+
+const testAccCheckGithubRepositoryConfig_basic = `
+resource "github_repository" "foobar" {
+    name = "terraform-acc-test"
+    description = "Terraform acceptance test"
+    private = false
+    has_issues = true
+    auto_init = true
+}`
+
+const testAccCheckGithubRepositoryConfig_updated = `
+resource "github_repository" "foobar" {
+    name = "terraform-acc-test"
+    description = "Updated"
+    private = false
+    auto_init = true
+}`
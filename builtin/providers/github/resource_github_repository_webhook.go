@@ -0,0 +1,173 @@
+package github
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceGithubRepositoryWebhook() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGithubRepositoryWebhookCreate,
+		Read:   resourceGithubRepositoryWebhookRead,
+		Update: resourceGithubRepositoryWebhookUpdate,
+		Delete: resourceGithubRepositoryWebhookDelete,
+
+		Schema: map[string]*schema.Schema{
+			"repository": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"url": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			// Defaults to "form"
+			"content_type": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			// GitHub only shows that a secret is set, so changes made to
+			// it outside of Terraform aren't noticed.
+			"secret": &schema.Schema{
+				Type:      schema.TypeString,
+				Optional:  true,
+				WriteOnly: true,
+			},
+
+			"insecure_ssl": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+
+			// Defaults to the push event
+			"events": &schema.Schema{
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set: func(v interface{}) int {
+					return hashcode.String(v.(string))
+				},
+			},
+
+			// Defaults to true
+			"active": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceGithubRepositoryWebhookOpts(d *schema.ResourceData) *Hook {
+	hook := &Hook{
+		Name:   "web",
+		Active: true,
+		Events: []string{"push"},
+		Config: map[string]string{
+			"url":          d.Get("url").(string),
+			"content_type": "form",
+			"insecure_ssl": "0",
+		},
+	}
+
+	if v, ok := d.GetOk("active"); ok {
+		hook.Active = v.(bool)
+	}
+	if v, ok := d.GetOk("events"); ok {
+		hook.Events = nil
+		for _, e := range v.(*schema.Set).List() {
+			hook.Events = append(hook.Events, e.(string))
+		}
+	}
+	if v := d.Get("content_type").(string); v != "" {
+		hook.Config["content_type"] = v
+	}
+	if v := d.Get("secret").(string); v != "" {
+		hook.Config["secret"] = v
+	}
+	if d.Get("insecure_ssl").(bool) {
+		hook.Config["insecure_ssl"] = "1"
+	}
+
+	return hook
+}
+
+func resourceGithubRepositoryWebhookCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	repo := d.Get("repository").(string)
+	opts := resourceGithubRepositoryWebhookOpts(d)
+
+	log.Printf("[DEBUG] Webhook create of %s: %s %v", repo, opts.Config["url"], opts.Events)
+	hook, err := client.CreateHook(repo, opts)
+	if err != nil {
+		return fmt.Errorf("Error creating webhook: %s", err)
+	}
+
+	d.SetId(strconv.Itoa(hook.Id))
+	log.Printf("[INFO] Webhook ID: %s", d.Id())
+
+	return resourceGithubRepositoryWebhookRead(d, meta)
+}
+
+func resourceGithubRepositoryWebhookRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	hook, err := client.Hook(d.Get("repository").(string), d.Id())
+	if err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+
+		return fmt.Errorf("Error retrieving webhook: %s", err)
+	}
+
+	d.Set("url", hook.Config["url"])
+	d.Set("content_type", hook.Config["content_type"])
+	d.Set("insecure_ssl", hook.Config["insecure_ssl"] == "1")
+	d.Set("events", hook.Events)
+	d.Set("active", hook.Active)
+
+	return nil
+}
+
+func resourceGithubRepositoryWebhookUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	// The secret isn't known, so it's sent with every change to keep it
+	opts := resourceGithubRepositoryWebhookOpts(d)
+
+	log.Printf("[DEBUG] Webhook update: %s", d.Id())
+	if err := client.EditHook(d.Get("repository").(string), d.Id(), opts); err != nil {
+		return fmt.Errorf("Error updating webhook: %s", err)
+	}
+
+	return resourceGithubRepositoryWebhookRead(d, meta)
+}
+
+func resourceGithubRepositoryWebhookDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	log.Printf("[INFO] Deleting Webhook: %s", d.Id())
+	if err := client.DeleteHook(d.Get("repository").(string), d.Id()); err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting webhook: %s", err)
+	}
+
+	return nil
+}
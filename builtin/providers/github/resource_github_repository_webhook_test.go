@@ -0,0 +1,100 @@
+package github
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccGithubRepositoryWebhook_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGithubRepositoryWebhookDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckGithubRepositoryWebhookConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGithubRepositoryWebhookExists("github_repository_webhook.foobar"),
+					resource.TestCheckResourceAttr(
+						"github_repository_webhook.foobar", "content_type", "form"),
+					resource.TestCheckResourceAttr(
+						"github_repository_webhook.foobar", "events.#", "1"),
+					resource.TestCheckResourceAttr(
+						"github_repository_webhook.foobar", "active", "true"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccCheckGithubRepositoryWebhookConfig_updated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGithubRepositoryWebhookExists("github_repository_webhook.foobar"),
+					resource.TestCheckResourceAttr(
+						"github_repository_webhook.foobar", "content_type", "json"),
+					resource.TestCheckResourceAttr(
+						"github_repository_webhook.foobar", "events.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckGithubRepositoryWebhookDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.Resources {
+		if rs.Type != "github_repository_webhook" {
+			continue
+		}
+
+		_, err := client.Hook(rs.Attributes["repository"], rs.ID)
+		if err == nil {
+			return fmt.Errorf("Webhook still exists")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckGithubRepositoryWebhookExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.ID == "" {
+			return fmt.Errorf("No Webhook ID is set")
+		}
+
+		client := testAccProvider.Meta().(*Client)
+
+		_, err := client.Hook(rs.Attributes["repository"], rs.ID)
+		return err
+	}
+}
+
+const testAccCheckGithubRepositoryWebhookConfig_basic = `
+resource "github_repository" "foobar" {
+    name = "terraform-acc-test-webhook"
+}
+
+resource "github_repository_webhook" "foobar" {
+    repository = "${github_repository.foobar.name}"
+    url = "https://example.com/hooks/github"
+    secret = "supersecret"
+}`
+
+const testAccCheckGithubRepositoryWebhookConfig_updated = `
+resource "github_repository" "foobar" {
+    name = "terraform-acc-test-webhook"
+}
+
+resource "github_repository_webhook" "foobar" {
+    repository = "${github_repository.foobar.name}"
+    url = "https://example.com/hooks/github"
+    secret = "supersecret"
+    content_type = "json"
+    events = ["push", "pull_request"]
+}`
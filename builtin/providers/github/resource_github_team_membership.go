@@ -0,0 +1,119 @@
+package github
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceGithubTeamMembership() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGithubTeamMembershipCreate,
+		Read:   resourceGithubTeamMembershipRead,
+		Update: resourceGithubTeamMembershipUpdate,
+		Delete: resourceGithubTeamMembershipDelete,
+
+		Schema: map[string]*schema.Schema{
+			"team_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"username": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"role": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"state": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceGithubTeamMembershipSet(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	role := d.Get("role").(string)
+	if role == "" {
+		role = "member"
+	}
+	if role != "member" && role != "maintainer" {
+		return fmt.Errorf("role must be member or maintainer, got: %s", role)
+	}
+
+	team := d.Get("team_id").(string)
+	username := d.Get("username").(string)
+
+	log.Printf("[DEBUG] Team membership set: %s in %s as %s", username, team, role)
+	if err := client.SetTeamMembership(team, username, role); err != nil {
+		return fmt.Errorf("Error setting team membership: %s", err)
+	}
+
+	return nil
+}
+
+func resourceGithubTeamMembershipCreate(d *schema.ResourceData, meta interface{}) error {
+	if err := resourceGithubTeamMembershipSet(d, meta); err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", d.Get("team_id"), d.Get("username")))
+
+	return resourceGithubTeamMembershipRead(d, meta)
+}
+
+func resourceGithubTeamMembershipRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	membership, err := client.TeamMembership(
+		d.Get("team_id").(string), d.Get("username").(string))
+	if err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+
+		return fmt.Errorf("Error retrieving team membership: %s", err)
+	}
+
+	d.Set("role", membership.Role)
+	d.Set("state", membership.State)
+
+	return nil
+}
+
+func resourceGithubTeamMembershipUpdate(d *schema.ResourceData, meta interface{}) error {
+	if err := resourceGithubTeamMembershipSet(d, meta); err != nil {
+		return err
+	}
+
+	return resourceGithubTeamMembershipRead(d, meta)
+}
+
+func resourceGithubTeamMembershipDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	log.Printf("[INFO] Deleting Team membership: %s", d.Id())
+	err := client.DeleteTeamMembership(
+		d.Get("team_id").(string), d.Get("username").(string))
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting team membership: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package github
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccGithubTeamMembership_Basic(t *testing.T) {
+	team := os.Getenv("GITHUB_TEST_TEAM_ID")
+	user := os.Getenv("GITHUB_TEST_USER")
+	if team == "" || user == "" {
+		t.Skip("GITHUB_TEST_TEAM_ID and GITHUB_TEST_USER must be set")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGithubTeamMembershipDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: fmt.Sprintf(testAccCheckGithubTeamMembershipConfig, team, user, "member"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGithubTeamMembershipExists("github_team_membership.foobar"),
+					resource.TestCheckResourceAttr(
+						"github_team_membership.foobar", "role", "member"),
+				),
+			},
+			resource.TestStep{
+				Config: fmt.Sprintf(testAccCheckGithubTeamMembershipConfig, team, user, "maintainer"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGithubTeamMembershipExists("github_team_membership.foobar"),
+					resource.TestCheckResourceAttr(
+						"github_team_membership.foobar", "role", "maintainer"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckGithubTeamMembershipDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.Resources {
+		if rs.Type != "github_team_membership" {
+			continue
+		}
+
+		_, err := client.TeamMembership(
+			rs.Attributes["team_id"], rs.Attributes["username"])
+		if err == nil {
+			return fmt.Errorf("Team membership still exists")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckGithubTeamMembershipExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		client := testAccProvider.Meta().(*Client)
+
+		_, err := client.TeamMembership(
+			rs.Attributes["team_id"], rs.Attributes["username"])
+		return err
+	}
+}
+
+const testAccCheckGithubTeamMembershipConfig = `
+resource "github_team_membership" "foobar" {
+    team_id = "%s"
+    username = "%s"
+    role = "%s"
+}`
@@ -1,6 +1,8 @@
 package google
 
 import (
+	"fmt"
+
 	"github.com/hashicorp/terraform/helper/schema"
 )
 
@@ -11,21 +13,25 @@ func Provider() *schema.Provider {
 			"account_file": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
+				EnvVars:  []string{"GOOGLE_ACCOUNT_FILE"},
 			},
 
 			"client_secrets_file": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
+				EnvVars:  []string{"GOOGLE_CLIENT_FILE"},
 			},
 
 			"project": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
+				EnvVars:  []string{"GOOGLE_PROJECT"},
 			},
 
 			"region": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
+				EnvVars:  []string{"GOOGLE_REGION"},
 			},
 		},
 
@@ -38,7 +44,8 @@ func Provider() *schema.Provider {
 			"google_compute_route":    resourceComputeRoute(),
 		},
 
-		ConfigureFunc: providerConfigure,
+		ConfigureFunc:           providerConfigure,
+		ValidateCredentialsFunc: providerValidateCredentials,
 	}
 }
 
@@ -56,3 +63,12 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 
 	return &config, nil
 }
+
+func providerValidateCredentials(meta interface{}) error {
+	config := meta.(*Config)
+	if _, err := config.clientCompute.Projects.Get(config.Project).Do(); err != nil {
+		return fmt.Errorf("Error looking up project %s: %s", config.Project, err)
+	}
+
+	return nil
+}
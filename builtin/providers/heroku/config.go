@@ -3,7 +3,6 @@ package heroku
 import (
 	"log"
 	"net/http"
-	"os"
 
 	"github.com/cyberdelia/heroku-go/v3"
 )
@@ -17,15 +16,6 @@ type Config struct {
 //
 func (c *Config) Client() (*heroku.Service, error) {
 
-	// If we have env vars set (like in the acc) tests,
-	// we need to override the values passed in here.
-	if v := os.Getenv("HEROKU_EMAIL"); v != "" {
-		c.Email = v
-	}
-	if v := os.Getenv("HEROKU_API_KEY"); v != "" {
-		c.APIKey = v
-	}
-
 	service := heroku.NewService(&http.Client{
 		Transport: &heroku.Transport{
 			Username:  c.Email,
@@ -1,8 +1,10 @@
 package heroku
 
 import (
+	"fmt"
 	"log"
 
+	"github.com/cyberdelia/heroku-go/v3"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/mitchellh/mapstructure"
 )
@@ -14,22 +16,27 @@ func Provider() *schema.Provider {
 			"email": &schema.Schema{
 				Type:     schema.TypeString,
 				Optional: true,
+				EnvVars:  []string{"HEROKU_EMAIL"},
 			},
 
 			"api_key": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:      schema.TypeString,
+				Optional:  true,
+				WriteOnly: true,
+				EnvVars:   []string{"HEROKU_API_KEY"},
 			},
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
-			"heroku_app":    resourceHerokuApp(),
-			"heroku_addon":  resourceHerokuAddon(),
-			"heroku_domain": resourceHerokuDomain(),
-			"heroku_drain":  resourceHerokuDrain(),
+			"heroku_app":              resourceHerokuApp(),
+			"heroku_addon":            resourceHerokuAddon(),
+			"heroku_addon_attachment": resourceHerokuAddonAttachment(),
+			"heroku_domain":           resourceHerokuDomain(),
+			"heroku_drain":            resourceHerokuDrain(),
 		},
 
-		ConfigureFunc: providerConfigure,
+		ConfigureFunc:           providerConfigure,
+		ValidateCredentialsFunc: providerValidateCredentials,
 	}
 }
 
@@ -43,3 +50,12 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	log.Println("[INFO] Initializing Heroku client")
 	return config.Client()
 }
+
+func providerValidateCredentials(meta interface{}) error {
+	client := meta.(*heroku.Service)
+	if _, err := client.AccountInfo(); err != nil {
+		return fmt.Errorf("Error looking up Heroku account: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package heroku
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/cyberdelia/heroku-go/v3"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func resourceHerokuAddonAttachment() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceHerokuAddonAttachmentCreate,
+		Read:   resourceHerokuAddonAttachmentRead,
+		Delete: resourceHerokuAddonAttachmentDelete,
+
+		Schema: map[string]*schema.Schema{
+			"app": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"addon": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+func resourceHerokuAddonAttachmentCreate(d *schema.ResourceData, meta interface{}) error {
+	// Attaching changes the config vars of the app like creating an
+	// addon does, so they share the lock.
+	addonLock.Lock()
+	defer addonLock.Unlock()
+
+	client := meta.(*heroku.Service)
+
+	opts := heroku.AddonAttachmentCreateOpts{
+		Addon: d.Get("addon").(string),
+		App:   d.Get("app").(string),
+	}
+	if v := d.Get("name"); v != nil && v.(string) != "" {
+		vs := v.(string)
+		opts.Name = &vs
+	}
+
+	log.Printf("[DEBUG] Addon attachment create configuration: %#v", opts)
+	a, err := client.AddonAttachmentCreate(opts)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(a.ID)
+	log.Printf("[INFO] Addon attachment ID: %s", d.Id())
+
+	return resourceHerokuAddonAttachmentRead(d, meta)
+}
+
+func resourceHerokuAddonAttachmentRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*heroku.Service)
+
+	a, err := client.AddonAttachmentInfo(d.Id())
+	if err != nil {
+		return fmt.Errorf("Error retrieving addon attachment: %s", err)
+	}
+
+	d.Set("name", a.Name)
+	d.SetDependencies([]terraform.ResourceDependency{
+		terraform.ResourceDependency{ID: d.Get("app").(string)},
+		terraform.ResourceDependency{ID: d.Get("addon").(string)},
+	})
+
+	return nil
+}
+
+func resourceHerokuAddonAttachmentDelete(d *schema.ResourceData, meta interface{}) error {
+	addonLock.Lock()
+	defer addonLock.Unlock()
+
+	client := meta.(*heroku.Service)
+
+	log.Printf("[INFO] Deleting Addon attachment: %s", d.Id())
+	if err := client.AddonAttachmentDelete(d.Id()); err != nil {
+		return fmt.Errorf("Error deleting addon attachment: %s", err)
+	}
+
+	d.SetId("")
+	return nil
+}
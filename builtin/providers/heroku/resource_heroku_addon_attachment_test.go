@@ -0,0 +1,118 @@
+package heroku
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cyberdelia/heroku-go/v3"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccHerokuAddonAttachment_Basic(t *testing.T) {
+	var attachment heroku.AddonAttachment
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckHerokuAddonAttachmentDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckHerokuAddonAttachmentConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckHerokuAddonAttachmentExists(
+						"heroku_addon_attachment.foobar", &attachment),
+					testAccCheckHerokuAddonAttachmentAttributes(
+						&attachment, "SHARED_MEMCACHIER"),
+					resource.TestCheckResourceAttr(
+						"heroku_addon_attachment.foobar", "app", "terraform-test-app-2"),
+					resource.TestCheckResourceAttr(
+						"heroku_addon_attachment.foobar", "name", "SHARED_MEMCACHIER"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckHerokuAddonAttachmentDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*heroku.Service)
+
+	for _, rs := range s.Resources {
+		if rs.Type != "heroku_addon_attachment" {
+			continue
+		}
+
+		_, err := client.AddonAttachmentInfo(rs.ID)
+
+		if err == nil {
+			return fmt.Errorf("Addon attachment still exists")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckHerokuAddonAttachmentAttributes(
+	attachment *heroku.AddonAttachment, n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+
+		if attachment.Name != n {
+			return fmt.Errorf("Bad name: %s", attachment.Name)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckHerokuAddonAttachmentExists(
+	n string, attachment *heroku.AddonAttachment) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.Resources[n]
+
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.ID == "" {
+			return fmt.Errorf("No Addon attachment ID is set")
+		}
+
+		client := testAccProvider.Meta().(*heroku.Service)
+
+		found, err := client.AddonAttachmentInfo(rs.ID)
+
+		if err != nil {
+			return err
+		}
+
+		if found.ID != rs.ID {
+			return fmt.Errorf("Addon attachment not found")
+		}
+
+		*attachment = *found
+
+		return nil
+	}
+}
+
+const testAccCheckHerokuAddonAttachmentConfig_basic = `
+resource "heroku_app" "foobar" {
+    name = "terraform-test-app"
+    region = "us"
+}
+
+resource "heroku_app" "other" {
+    name = "terraform-test-app-2"
+    region = "us"
+}
+
+resource "heroku_addon" "foobar" {
+    app = "${heroku_app.foobar.name}"
+    plan = "memcachier"
+}
+
+resource "heroku_addon_attachment" "foobar" {
+    app = "${heroku_app.other.name}"
+    addon = "${heroku_addon.foobar.id}"
+    name = "SHARED_MEMCACHIER"
+}`
@@ -254,23 +254,55 @@ func retrieve_config_vars(id string, client *heroku.Service) (map[string]string,
 }
 
 // Updates the config vars for from an expanded configuration.
+//
+// Only the vars that changed in the configuration are touched. Addons
+// and other tools set vars on the app too, so a var that was removed from
+// the configuration is only deleted if it still has the value Terraform
+// gave it.
 func update_config_vars(
 	id string,
 	client *heroku.Service,
 	o []interface{},
 	n []interface{}) error {
+	oldVars := expand_config_vars(o)
+	newVars := expand_config_vars(n)
 	vars := make(map[string]*string)
 
-	for _, v := range o {
-		for k, _ := range v.(map[string]interface{}) {
+	var removed []string
+	for k, _ := range oldVars {
+		if _, ok := newVars[k]; !ok {
+			removed = append(removed, k)
+		}
+	}
+	if len(removed) > 0 {
+		current, err := retrieve_config_vars(id, client)
+		if err != nil {
+			return fmt.Errorf("Error retrieving config vars: %s", err)
+		}
+
+		for _, k := range removed {
+			if v, ok := current[k]; ok && v != oldVars[k] {
+				log.Printf(
+					"[INFO] Not deleting config var %s, it was changed "+
+						"outside of Terraform", k)
+				continue
+			}
+
 			vars[k] = nil
 		}
 	}
-	for _, v := range n {
-		for k, v := range v.(map[string]interface{}) {
-			val := v.(string)
-			vars[k] = &val
+
+	for k, v := range newVars {
+		if old, ok := oldVars[k]; ok && old == v {
+			continue
 		}
+
+		val := v
+		vars[k] = &val
+	}
+
+	if len(vars) == 0 {
+		return nil
 	}
 
 	log.Printf("[INFO] Updating config vars: *%#v", vars)
@@ -280,3 +312,15 @@ func update_config_vars(
 
 	return nil
 }
+
+// Merges the expanded config_vars blocks into a single map.
+func expand_config_vars(raw []interface{}) map[string]string {
+	result := make(map[string]string)
+	for _, v := range raw {
+		for k, v := range v.(map[string]interface{}) {
+			result[k] = v.(string)
+		}
+	}
+
+	return result
+}
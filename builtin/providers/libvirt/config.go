@@ -0,0 +1,40 @@
+package libvirt
+
+import (
+	"fmt"
+	"log"
+	"os/exec"
+)
+
+// DefaultURI is the libvirt connection used if none is configured.
+const DefaultURI = "qemu:///system"
+
+type Config struct {
+	URI   string `mapstructure:"uri"`
+	Virsh string `mapstructure:"virsh"`
+}
+
+// Client() returns a new client for managing the objects of libvirt.
+func (c *Config) Client() (*Client, error) {
+	if c.URI == "" {
+		c.URI = DefaultURI
+	}
+
+	virsh := c.Virsh
+	if virsh == "" {
+		virsh = "virsh"
+	}
+
+	path, err := exec.LookPath(virsh)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"virsh is required to manage libvirt, but wasn't found: %s", err)
+	}
+
+	log.Printf("[INFO] libvirt Client configured for: %s", c.URI)
+
+	return &Client{
+		URI:   c.URI,
+		Virsh: path,
+	}, nil
+}
@@ -0,0 +1,53 @@
+package libvirt
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mitchellh/mapstructure"
+)
+
+// Provider returns a terraform.ResourceProvider.
+func Provider() *schema.Provider {
+	return &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			// The URI is read from the same variable as virsh reads it.
+			"uri": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				EnvVars:  []string{"LIBVIRT_DEFAULT_URI"},
+			},
+
+			"virsh": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+
+		ResourcesMap: map[string]*schema.Resource{
+			"libvirt_domain":  resourceLibvirtDomain(),
+			"libvirt_network": resourceLibvirtNetwork(),
+			"libvirt_volume":  resourceLibvirtVolume(),
+		},
+
+		ConfigureFunc:           providerConfigure,
+		ValidateCredentialsFunc: providerValidateCredentials,
+	}
+}
+
+func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+	var config Config
+	configRaw := d.Get("").(map[string]interface{})
+	if err := mapstructure.Decode(configRaw, &config); err != nil {
+		return nil, err
+	}
+
+	return config.Client()
+}
+
+// providerValidateCredentials checks that libvirt can be connected to
+// with the configured URI.
+func providerValidateCredentials(meta interface{}) error {
+	client := meta.(*Client)
+
+	_, err := client.Run("uri")
+	return err
+}
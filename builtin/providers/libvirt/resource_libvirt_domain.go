@@ -0,0 +1,268 @@
+package libvirt
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceLibvirtDomain() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceLibvirtDomainCreate,
+		Read:   resourceLibvirtDomainRead,
+		Update: resourceLibvirtDomainUpdate,
+		Delete: resourceLibvirtDomainDelete,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"memory": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"vcpu": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"disk": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"volume_id": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+					},
+				},
+			},
+
+			"network_interface": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"network_name": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+
+						"mac": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+						},
+					},
+				},
+			},
+
+			"autostart": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+
+			"state": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceLibvirtDomainCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	memory := d.Get("memory").(int)
+	if memory == 0 {
+		memory = 512
+	}
+
+	vcpu := d.Get("vcpu").(int)
+	if vcpu == 0 {
+		vcpu = 1
+	}
+
+	domain := &domainXML{
+		Type: "kvm",
+		Name: d.Get("name").(string),
+		Memory: sizeXML{
+			Unit:  "MiB",
+			Value: uint64(memory),
+		},
+		VCPU: vcpu,
+		OS: domainOSXML{
+			Type: domainOSType{Value: "hvm"},
+			Boot: []domainBoot{domainBoot{Dev: "hd"}},
+		},
+		Devices: domainDevices{
+			Consoles: []domainConsole{domainConsole{Type: "pty"}},
+		},
+	}
+
+	// Build up the disks, which boot from the first one
+	disksCount := d.Get("disk.#").(int)
+	for i := 0; i < disksCount; i++ {
+		key := d.Get(fmt.Sprintf("disk.%d.volume_id", i)).(string)
+		volume, err := client.Volume("", key)
+		if err != nil {
+			return fmt.Errorf("Error loading volume %s: %s", key, err)
+		}
+
+		domain.Devices.Disks = append(domain.Devices.Disks, domainDisk{
+			Type:   "file",
+			Device: "disk",
+			Driver: domainDiskDriver{
+				Name: "qemu",
+				Type: volume.Target.Format.Type,
+			},
+			Source: domainDiskSource{File: volume.Target.Path},
+			Target: domainDiskTarget{Dev: diskTarget(i), Bus: "virtio"},
+		})
+	}
+
+	// Build up the network interfaces
+	interfacesCount := d.Get("network_interface.#").(int)
+	for i := 0; i < interfacesCount; i++ {
+		prefix := fmt.Sprintf("network_interface.%d", i)
+		iface := domainInterface{
+			Type: "network",
+			Source: domainInterfaceSource{
+				Network: d.Get(prefix + ".network_name").(string),
+			},
+			Model: domainInterfaceModel{Type: "virtio"},
+		}
+
+		if v := d.Get(prefix + ".mac").(string); v != "" {
+			iface.MAC = &domainInterfaceMAC{Address: v}
+		}
+
+		domain.Devices.Interfaces = append(domain.Devices.Interfaces, iface)
+	}
+
+	log.Printf("[DEBUG] Domain create configuration: %#v", domain)
+	domain, err := client.DefineDomain(domain)
+	if err != nil {
+		return fmt.Errorf("Error creating domain: %s", err)
+	}
+
+	// Domains are identified by their UUID, which doesn't change
+	d.SetId(domain.UUID)
+	log.Printf("[INFO] Domain ID: %s", d.Id())
+
+	if d.Get("autostart").(bool) {
+		if err := client.SetDomainAutostart(d.Id(), true); err != nil {
+			return fmt.Errorf("Error setting domain autostart: %s", err)
+		}
+	}
+
+	if err := client.StartDomain(d.Id()); err != nil {
+		return fmt.Errorf("Error starting domain: %s", err)
+	}
+
+	return resourceLibvirtDomainRead(d, meta)
+}
+
+func resourceLibvirtDomainRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	domain, err := client.Domain(d.Id())
+	if err != nil {
+		if isNotFound(err) {
+			// The domain doesn't exist anymore
+			d.SetId("")
+
+			return nil
+		}
+
+		return fmt.Errorf("Error reading domain: %s", err)
+	}
+
+	info, err := client.DomainInfo(d.Id())
+	if err != nil {
+		return fmt.Errorf("Error reading domain: %s", err)
+	}
+
+	memory, err := domain.Memory.Bytes("KiB")
+	if err != nil {
+		return fmt.Errorf("Error reading domain memory: %s", err)
+	}
+
+	d.Set("name", domain.Name)
+	d.Set("memory", int(memory>>20))
+	d.Set("vcpu", domain.VCPU)
+	d.Set("autostart", info["Autostart"] == "enable")
+	d.Set("state", info["State"])
+
+	// The disks are read by the path of their file, which is the key of
+	// the volume for the pools that keep volumes in files.
+	disks := make([]map[string]interface{}, 0, len(domain.Devices.Disks))
+	for _, disk := range domain.Devices.Disks {
+		if disk.Device != "disk" || disk.Source.File == "" {
+			continue
+		}
+
+		disks = append(disks, map[string]interface{}{
+			"volume_id": disk.Source.File,
+		})
+	}
+	d.Set("disk", disks)
+
+	interfaces := make(
+		[]map[string]interface{}, 0, len(domain.Devices.Interfaces))
+	for _, iface := range domain.Devices.Interfaces {
+		if iface.Type != "network" {
+			continue
+		}
+
+		var mac string
+		if iface.MAC != nil {
+			mac = iface.MAC.Address
+		}
+
+		interfaces = append(interfaces, map[string]interface{}{
+			"network_name": iface.Source.Network,
+			"mac":          mac,
+		})
+	}
+	d.Set("network_interface", interfaces)
+
+	return nil
+}
+
+func resourceLibvirtDomainUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	if d.HasChange("autostart") {
+		on := d.Get("autostart").(bool)
+		if err := client.SetDomainAutostart(d.Id(), on); err != nil {
+			return fmt.Errorf("Error setting domain autostart: %s", err)
+		}
+	}
+
+	return resourceLibvirtDomainRead(d, meta)
+}
+
+func resourceLibvirtDomainDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	log.Printf("[INFO] Deleting domain: %s", d.Id())
+	if err := client.DeleteDomain(d.Id()); err != nil && !isNotFound(err) {
+		return fmt.Errorf("Error deleting domain: %s", err)
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,165 @@
+package libvirt
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccLibvirtDomain_Basic(t *testing.T) {
+	var domain domainXML
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLibvirtDomainDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccLibvirtDomainConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckLibvirtDomainExists("libvirt_domain.foobar", &domain),
+					testAccCheckLibvirtDomainAttributes(&domain),
+					resource.TestCheckResourceAttr(
+						"libvirt_domain.foobar", "memory", "256"),
+					resource.TestCheckResourceAttr(
+						"libvirt_domain.foobar", "vcpu", "1"),
+					resource.TestCheckResourceAttr(
+						"libvirt_domain.foobar", "state", "running"),
+					resource.TestCheckResourceAttr(
+						"libvirt_domain.foobar", "network_interface.0.network_name", "terraform-test"),
+				),
+			},
+
+			resource.TestStep{
+				Config: testAccLibvirtDomainConfig_autostart,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckLibvirtDomainExists("libvirt_domain.foobar", &domain),
+					resource.TestCheckResourceAttr(
+						"libvirt_domain.foobar", "autostart", "true"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckLibvirtDomainDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.Resources {
+		if rs.Type != "libvirt_domain" {
+			continue
+		}
+
+		_, err := client.Domain(rs.ID)
+		if err == nil {
+			return fmt.Errorf("Domain still exists")
+		}
+		if !isNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckLibvirtDomainAttributes(domain *domainXML) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if domain.Name != "terraform-test" {
+			return fmt.Errorf("Bad name: %s", domain.Name)
+		}
+
+		if len(domain.Devices.Disks) != 1 {
+			return fmt.Errorf("Bad disks: %#v", domain.Devices.Disks)
+		}
+
+		if domain.Devices.Disks[0].Target.Dev != "vda" {
+			return fmt.Errorf("Bad disk: %#v", domain.Devices.Disks[0])
+		}
+
+		if len(domain.Devices.Interfaces) != 1 {
+			return fmt.Errorf("Bad interfaces: %#v", domain.Devices.Interfaces)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckLibvirtDomainExists(n string, domain *domainXML) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.ID == "" {
+			return fmt.Errorf("No domain ID is set")
+		}
+
+		client := testAccProvider.Meta().(*Client)
+		found, err := client.Domain(rs.ID)
+		if err != nil {
+			return err
+		}
+
+		if found.UUID != rs.ID {
+			return fmt.Errorf("Domain not found")
+		}
+
+		*domain = *found
+
+		return nil
+	}
+}
+
+const testAccLibvirtDomainConfig = `
+resource "libvirt_network" "foobar" {
+	name = "terraform-test"
+	address = "10.17.3.0/24"
+}
+
+resource "libvirt_volume" "foobar" {
+	name = "terraform-test.qcow2"
+	size = 1073741824
+}
+
+resource "libvirt_domain" "foobar" {
+	name = "terraform-test"
+	memory = 256
+
+	disk {
+		volume_id = "${libvirt_volume.foobar.id}"
+	}
+
+	network_interface {
+		network_name = "${libvirt_network.foobar.name}"
+	}
+}
+`
+
+const testAccLibvirtDomainConfig_autostart = `
+resource "libvirt_network" "foobar" {
+	name = "terraform-test"
+	address = "10.17.3.0/24"
+}
+
+resource "libvirt_volume" "foobar" {
+	name = "terraform-test.qcow2"
+	size = 1073741824
+}
+
+resource "libvirt_domain" "foobar" {
+	name = "terraform-test"
+	memory = 256
+	autostart = true
+
+	disk {
+		volume_id = "${libvirt_volume.foobar.id}"
+	}
+
+	network_interface {
+		network_name = "${libvirt_network.foobar.name}"
+	}
+}
+`
@@ -0,0 +1,194 @@
+package libvirt
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceLibvirtNetwork() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceLibvirtNetworkCreate,
+		Read:   resourceLibvirtNetworkRead,
+		Update: resourceLibvirtNetworkUpdate,
+		Delete: resourceLibvirtNetworkDelete,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"mode": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"bridge": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"address": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"autostart": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+
+			"gateway": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceLibvirtNetworkCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	network := &networkXML{
+		Name: d.Get("name").(string),
+	}
+
+	mode := d.Get("mode").(string)
+	if mode == "" {
+		mode = "nat"
+	}
+
+	switch mode {
+	case "nat", "route":
+		network.Forward = &networkForward{Mode: mode}
+	case "isolated":
+	case "bridge":
+		// Bridged networks connect domains to an existing bridge of the
+		// host, so libvirt doesn't hand out addresses.
+		if d.Get("bridge").(string) == "" {
+			return fmt.Errorf("bridge is required with mode \"bridge\"")
+		}
+		if d.Get("address").(string) != "" {
+			return fmt.Errorf("address can't be set with mode \"bridge\"")
+		}
+
+		network.Forward = &networkForward{Mode: mode}
+	default:
+		return fmt.Errorf(
+			"mode must be one of nat, route, isolated or bridge, got: %s", mode)
+	}
+
+	if v := d.Get("bridge").(string); v != "" {
+		network.Bridge = &networkBridge{Name: v}
+	}
+
+	if v := d.Get("address").(string); v != "" {
+		ip, err := newNetworkIP(v)
+		if err != nil {
+			return fmt.Errorf("Error parsing address: %s", err)
+		}
+
+		network.IPs = []networkIPXML{*ip}
+	}
+
+	log.Printf("[DEBUG] Network create configuration: %#v", network)
+	network, err := client.DefineNetwork(network)
+	if err != nil {
+		return fmt.Errorf("Error creating network: %s", err)
+	}
+
+	// Networks are identified by their UUID, which doesn't change
+	d.SetId(network.UUID)
+	log.Printf("[INFO] Network ID: %s", d.Id())
+
+	if d.Get("autostart").(bool) {
+		if err := client.SetNetworkAutostart(d.Id(), true); err != nil {
+			return fmt.Errorf("Error setting network autostart: %s", err)
+		}
+	}
+
+	return resourceLibvirtNetworkRead(d, meta)
+}
+
+func resourceLibvirtNetworkRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	network, err := client.Network(d.Id())
+	if err != nil {
+		if isNotFound(err) {
+			// The network doesn't exist anymore
+			d.SetId("")
+
+			return nil
+		}
+
+		return fmt.Errorf("Error reading network: %s", err)
+	}
+
+	info, err := client.NetworkInfo(d.Id())
+	if err != nil {
+		return fmt.Errorf("Error reading network: %s", err)
+	}
+
+	d.Set("name", network.Name)
+	d.Set("autostart", info["Autostart"] == "yes")
+
+	mode := "isolated"
+	if network.Forward != nil {
+		mode = network.Forward.Mode
+		if mode == "" {
+			mode = "nat"
+		}
+	}
+	d.Set("mode", mode)
+
+	if network.Bridge != nil {
+		d.Set("bridge", network.Bridge.Name)
+	}
+
+	if len(network.IPs) > 0 {
+		ip := network.IPs[0]
+		cidr, err := ip.CIDR()
+		if err != nil {
+			return fmt.Errorf("Error reading network address: %s", err)
+		}
+
+		d.Set("address", cidr)
+		d.Set("gateway", ip.Address)
+	}
+
+	return nil
+}
+
+func resourceLibvirtNetworkUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	if d.HasChange("autostart") {
+		on := d.Get("autostart").(bool)
+		if err := client.SetNetworkAutostart(d.Id(), on); err != nil {
+			return fmt.Errorf("Error setting network autostart: %s", err)
+		}
+	}
+
+	return resourceLibvirtNetworkRead(d, meta)
+}
+
+func resourceLibvirtNetworkDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	log.Printf("[INFO] Deleting network: %s", d.Id())
+	if err := client.DeleteNetwork(d.Id()); err != nil && !isNotFound(err) {
+		return fmt.Errorf("Error deleting network: %s", err)
+	}
+
+	d.SetId("")
+	return nil
+}
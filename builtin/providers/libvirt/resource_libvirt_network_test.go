@@ -0,0 +1,125 @@
+package libvirt
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccLibvirtNetwork_Basic(t *testing.T) {
+	var network networkXML
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLibvirtNetworkDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccLibvirtNetworkConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckLibvirtNetworkExists("libvirt_network.foobar", &network),
+					testAccCheckLibvirtNetworkAttributes(&network),
+					resource.TestCheckResourceAttr(
+						"libvirt_network.foobar", "mode", "nat"),
+					resource.TestCheckResourceAttr(
+						"libvirt_network.foobar", "address", "10.17.3.0/24"),
+					resource.TestCheckResourceAttr(
+						"libvirt_network.foobar", "gateway", "10.17.3.1"),
+					resource.TestCheckResourceAttr(
+						"libvirt_network.foobar", "autostart", "false"),
+				),
+			},
+
+			resource.TestStep{
+				Config: testAccLibvirtNetworkConfig_autostart,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckLibvirtNetworkExists("libvirt_network.foobar", &network),
+					resource.TestCheckResourceAttr(
+						"libvirt_network.foobar", "autostart", "true"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckLibvirtNetworkDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.Resources {
+		if rs.Type != "libvirt_network" {
+			continue
+		}
+
+		_, err := client.Network(rs.ID)
+		if err == nil {
+			return fmt.Errorf("Network still exists")
+		}
+		if !isNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckLibvirtNetworkAttributes(network *networkXML) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if network.Name != "terraform-test" {
+			return fmt.Errorf("Bad name: %s", network.Name)
+		}
+
+		if network.Forward == nil || network.Forward.Mode != "nat" {
+			return fmt.Errorf("Bad forward: %#v", network.Forward)
+		}
+
+		if len(network.IPs) != 1 || network.IPs[0].DHCP == nil {
+			return fmt.Errorf("Bad ip: %#v", network.IPs)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckLibvirtNetworkExists(n string, network *networkXML) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.ID == "" {
+			return fmt.Errorf("No network ID is set")
+		}
+
+		client := testAccProvider.Meta().(*Client)
+		found, err := client.Network(rs.ID)
+		if err != nil {
+			return err
+		}
+
+		if found.UUID != rs.ID {
+			return fmt.Errorf("Network not found")
+		}
+
+		*network = *found
+
+		return nil
+	}
+}
+
+const testAccLibvirtNetworkConfig = `
+resource "libvirt_network" "foobar" {
+	name = "terraform-test"
+	address = "10.17.3.0/24"
+}
+`
+
+const testAccLibvirtNetworkConfig_autostart = `
+resource "libvirt_network" "foobar" {
+	name = "terraform-test"
+	address = "10.17.3.0/24"
+	autostart = true
+}
+`
@@ -0,0 +1,156 @@
+package libvirt
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceLibvirtVolume() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceLibvirtVolumeCreate,
+		Read:   resourceLibvirtVolumeRead,
+		Delete: resourceLibvirtVolumeDelete,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"pool": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"size": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"format": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"base_volume_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+func resourceLibvirtVolumeCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	pool := d.Get("pool").(string)
+	if pool == "" {
+		pool = "default"
+	}
+
+	format := d.Get("format").(string)
+	if format == "" {
+		format = "qcow2"
+	}
+
+	volume := &volumeXML{
+		Name: d.Get("name").(string),
+		Capacity: sizeXML{
+			Unit:  "bytes",
+			Value: uint64(d.Get("size").(int)),
+		},
+		Target: volumeTargetXML{
+			Format: volumeFormatXML{Type: format},
+		},
+	}
+
+	// A volume with a base volume is a copy-on-write layer on top of it,
+	// which is as large as the base unless it is given a size.
+	if v := d.Get("base_volume_id").(string); v != "" {
+		base, err := client.Volume("", v)
+		if err != nil {
+			return fmt.Errorf("Error reading base volume %s: %s", v, err)
+		}
+
+		volume.BackingStore = &volumeTargetXML{
+			Path:   base.Target.Path,
+			Format: base.Target.Format,
+		}
+
+		if volume.Capacity.Value == 0 {
+			volume.Capacity = base.Capacity
+		}
+	}
+
+	if volume.Capacity.Value == 0 {
+		return fmt.Errorf("size is required unless base_volume_id is set")
+	}
+
+	log.Printf("[DEBUG] Volume create configuration: %#v", volume)
+	volume, err := client.CreateVolume(pool, volume)
+	if err != nil {
+		return fmt.Errorf("Error creating volume: %s", err)
+	}
+
+	// The key of a volume is unique on the host, which the name is only
+	// within its pool
+	d.SetId(volume.Key)
+	log.Printf("[INFO] Volume ID: %s", d.Id())
+
+	return resourceLibvirtVolumeRead(d, meta)
+}
+
+func resourceLibvirtVolumeRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	volume, err := client.Volume("", d.Id())
+	if err != nil {
+		if isNotFound(err) {
+			// The volume doesn't exist anymore
+			d.SetId("")
+
+			return nil
+		}
+
+		return fmt.Errorf("Error reading volume: %s", err)
+	}
+
+	pool, err := client.VolumePool(d.Id())
+	if err != nil {
+		return fmt.Errorf("Error reading volume pool: %s", err)
+	}
+
+	size, err := volume.Capacity.Bytes("bytes")
+	if err != nil {
+		return fmt.Errorf("Error reading volume size: %s", err)
+	}
+
+	d.Set("name", volume.Name)
+	d.Set("pool", pool)
+	d.Set("size", int(size))
+	d.Set("format", volume.Target.Format.Type)
+
+	return nil
+}
+
+func resourceLibvirtVolumeDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	log.Printf("[INFO] Deleting volume: %s", d.Id())
+	if err := client.DeleteVolume(d.Id()); err != nil && !isNotFound(err) {
+		return fmt.Errorf("Error deleting volume: %s", err)
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,110 @@
+package libvirt
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccLibvirtVolume_Basic(t *testing.T) {
+	var volume, layer volumeXML
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLibvirtVolumeDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccLibvirtVolumeConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckLibvirtVolumeExists("libvirt_volume.base", &volume),
+					testAccCheckLibvirtVolumeExists("libvirt_volume.layer", &layer),
+					testAccCheckLibvirtVolumeBacking(&layer, &volume),
+					resource.TestCheckResourceAttr(
+						"libvirt_volume.base", "pool", "default"),
+					resource.TestCheckResourceAttr(
+						"libvirt_volume.base", "format", "qcow2"),
+					resource.TestCheckResourceAttr(
+						"libvirt_volume.base", "size", "1073741824"),
+					resource.TestCheckResourceAttr(
+						"libvirt_volume.layer", "size", "1073741824"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckLibvirtVolumeDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.Resources {
+		if rs.Type != "libvirt_volume" {
+			continue
+		}
+
+		_, err := client.Volume("", rs.ID)
+		if err == nil {
+			return fmt.Errorf("Volume still exists")
+		}
+		if !isNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckLibvirtVolumeBacking(layer, base *volumeXML) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if layer.BackingStore == nil {
+			return fmt.Errorf("Volume has no backing store")
+		}
+
+		if layer.BackingStore.Path != base.Target.Path {
+			return fmt.Errorf("Bad backing store: %s", layer.BackingStore.Path)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckLibvirtVolumeExists(n string, volume *volumeXML) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.ID == "" {
+			return fmt.Errorf("No volume ID is set")
+		}
+
+		client := testAccProvider.Meta().(*Client)
+		found, err := client.Volume("", rs.ID)
+		if err != nil {
+			return err
+		}
+
+		if found.Key != rs.ID {
+			return fmt.Errorf("Volume not found")
+		}
+
+		*volume = *found
+
+		return nil
+	}
+}
+
+const testAccLibvirtVolumeConfig = `
+resource "libvirt_volume" "base" {
+	name = "terraform-test-base.qcow2"
+	size = 1073741824
+}
+
+resource "libvirt_volume" "layer" {
+	name = "terraform-test-layer.qcow2"
+	base_volume_id = "${libvirt_volume.base.id}"
+}
+`
@@ -0,0 +1,282 @@
+package libvirt
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Client manages the objects of a libvirt connection by running virsh,
+// so that the provider doesn't need the libvirt libraries to build.
+type Client struct {
+	// URI is the libvirt connection URI, such as "qemu:///system".
+	URI string
+
+	// Virsh is the path of the virsh executable. It is looked up in the
+	// PATH if it's empty.
+	Virsh string
+}
+
+// VirshError is the error of a virsh command that failed.
+type VirshError struct {
+	Args   []string
+	Output string
+	Err    error
+}
+
+func (e *VirshError) Error() string {
+	msg := e.Output
+	if msg == "" {
+		msg = e.Err.Error()
+	}
+
+	return fmt.Sprintf("virsh %s: %s", strings.Join(e.Args, " "), msg)
+}
+
+// isNotFound returns true if the error is from a virsh command given a
+// domain, network or volume that doesn't exist.
+func isNotFound(err error) bool {
+	verr, ok := err.(*VirshError)
+	if !ok {
+		return false
+	}
+
+	msg := strings.ToLower(verr.Output)
+	return strings.Contains(msg, "not found") ||
+		strings.Contains(msg, "failed to get")
+}
+
+// Run runs virsh with the given arguments and returns what it printed.
+func (c *Client) Run(args ...string) (string, error) {
+	virsh := c.Virsh
+	if virsh == "" {
+		virsh = "virsh"
+	}
+
+	full := []string{"--quiet"}
+	if c.URI != "" {
+		full = append(full, "--connect", c.URI)
+	}
+	full = append(full, args...)
+
+	log.Printf("[DEBUG] Running virsh %s", strings.Join(args, " "))
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(virsh, full...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", &VirshError{
+			Args: args,
+			Output: strings.TrimSpace(strings.TrimPrefix(
+				strings.TrimSpace(stderr.String()), "error:")),
+			Err: err,
+		}
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// runXML runs virsh with the given arguments followed by the path of a
+// file with the XML of v, for the commands that define objects.
+func (c *Client) runXML(v interface{}, args ...string) (string, error) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	log.Printf("[DEBUG] libvirt XML:\n%s", data)
+	f, err := ioutil.TempFile("", "terraform-libvirt")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.Write(data)
+	f.Close()
+	if err != nil {
+		return "", err
+	}
+
+	return c.Run(append(args, f.Name())...)
+}
+
+// dumpXML runs a virsh command that prints XML and decodes it into v.
+func (c *Client) dumpXML(v interface{}, args ...string) error {
+	out, err := c.Run(args...)
+	if err != nil {
+		return err
+	}
+
+	return xml.Unmarshal([]byte(out), v)
+}
+
+// info runs a virsh command that prints "Key: value" lines.
+func (c *Client) info(args ...string) (map[string]string, error) {
+	out, err := c.Run(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseInfo(out), nil
+}
+
+// autostart returns the arguments of an autostart command that turns it
+// on or off.
+func autostart(cmd string, id string, on bool) []string {
+	if on {
+		return []string{cmd, id}
+	}
+
+	return []string{cmd, id, "--disable"}
+}
+
+// DefineDomain defines a domain, and returns it as libvirt filled it in.
+func (c *Client) DefineDomain(d *domainXML) (*domainXML, error) {
+	if _, err := c.runXML(d, "define"); err != nil {
+		return nil, err
+	}
+
+	return c.Domain(d.Name)
+}
+
+// Domain returns the domain with the given name or UUID.
+func (c *Client) Domain(id string) (*domainXML, error) {
+	var result domainXML
+	if err := c.dumpXML(&result, "dumpxml", id); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// DomainInfo returns the information of a domain, such as its "State" and
+// whether it is started on "Autostart".
+func (c *Client) DomainInfo(id string) (map[string]string, error) {
+	return c.info("dominfo", id)
+}
+
+// StartDomain starts a domain that is shut off.
+func (c *Client) StartDomain(id string) error {
+	_, err := c.Run("start", id)
+	return err
+}
+
+// SetDomainAutostart sets whether the domain is started with the host.
+func (c *Client) SetDomainAutostart(id string, on bool) error {
+	_, err := c.Run(autostart("autostart", id, on)...)
+	return err
+}
+
+// DeleteDomain stops a domain if it's running and undefines it. The
+// volumes of its disks are left.
+func (c *Client) DeleteDomain(id string) error {
+	info, err := c.DomainInfo(id)
+	if err != nil {
+		return err
+	}
+
+	if info["State"] != "shut off" {
+		if _, err := c.Run("destroy", id); err != nil {
+			return err
+		}
+	}
+
+	_, err = c.Run("undefine", id)
+	return err
+}
+
+// DefineNetwork defines a network and starts it, and returns it as
+// libvirt filled it in.
+func (c *Client) DefineNetwork(n *networkXML) (*networkXML, error) {
+	if _, err := c.runXML(n, "net-define"); err != nil {
+		return nil, err
+	}
+
+	if _, err := c.Run("net-start", n.Name); err != nil {
+		return nil, err
+	}
+
+	return c.Network(n.Name)
+}
+
+// Network returns the network with the given name or UUID.
+func (c *Client) Network(id string) (*networkXML, error) {
+	var result networkXML
+	if err := c.dumpXML(&result, "net-dumpxml", id); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// NetworkInfo returns the information of a network, such as whether it's
+// "Active" and started on "Autostart".
+func (c *Client) NetworkInfo(id string) (map[string]string, error) {
+	return c.info("net-info", id)
+}
+
+// SetNetworkAutostart sets whether the network is started with the host.
+func (c *Client) SetNetworkAutostart(id string, on bool) error {
+	_, err := c.Run(autostart("net-autostart", id, on)...)
+	return err
+}
+
+// DeleteNetwork stops a network if it's active and undefines it.
+func (c *Client) DeleteNetwork(id string) error {
+	info, err := c.NetworkInfo(id)
+	if err != nil {
+		return err
+	}
+
+	if info["Active"] == "yes" {
+		if _, err := c.Run("net-destroy", id); err != nil {
+			return err
+		}
+	}
+
+	_, err = c.Run("net-undefine", id)
+	return err
+}
+
+// CreateVolume creates a volume in a storage pool, and returns it as
+// libvirt filled it in.
+func (c *Client) CreateVolume(pool string, v *volumeXML) (*volumeXML, error) {
+	if _, err := c.runXML(v, "vol-create", pool); err != nil {
+		return nil, err
+	}
+
+	return c.Volume(pool, v.Name)
+}
+
+// Volume returns a volume by its name in the given pool, or by its key
+// if the pool is empty.
+func (c *Client) Volume(pool string, id string) (*volumeXML, error) {
+	args := []string{"vol-dumpxml", id}
+	if pool != "" {
+		args = append(args, "--pool", pool)
+	}
+
+	var result volumeXML
+	if err := c.dumpXML(&result, args...); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// VolumePool returns the name of the storage pool of the volume with the
+// given key.
+func (c *Client) VolumePool(key string) (string, error) {
+	return c.Run("vol-pool", key)
+}
+
+// DeleteVolume deletes the volume with the given key.
+func (c *Client) DeleteVolume(key string) error {
+	_, err := c.Run("vol-delete", key)
+	return err
+}
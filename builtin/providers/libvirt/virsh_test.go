@@ -0,0 +1,124 @@
+package libvirt
+
+import (
+	"encoding/xml"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// testVirsh returns a client that runs a fake virsh, which prints the
+// file it is given, or its arguments for commands without a file. It
+// fails like virsh for the ID "missing".
+func testVirsh(t *testing.T) (*Client, func()) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake virsh is a shell script")
+	}
+
+	dir, err := ioutil.TempDir("", "tf")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	path := filepath.Join(dir, "virsh")
+	err = ioutil.WriteFile(path, []byte(testVirshScript), 0755)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("err: %s", err)
+	}
+
+	client := &Client{URI: "test:///default", Virsh: path}
+	return client, func() { os.RemoveAll(dir) }
+}
+
+const testVirshScript = `#!/bin/sh
+for arg in "$@"; do
+	if [ "$arg" = "missing" ]; then
+		echo "error: failed to get domain 'missing'" >&2
+		echo "error: Domain not found: no domain with matching name 'missing'" >&2
+		exit 1
+	fi
+done
+for arg in "$@"; do
+	if [ -f "$arg" ]; then
+		cat "$arg"
+		exit 0
+	fi
+done
+echo "$@"
+`
+
+func TestClientRun(t *testing.T) {
+	client, cleanup := testVirsh(t)
+	defer cleanup()
+
+	out, err := client.Run("dominfo", "web")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := "--quiet --connect test:///default dominfo web"
+	if out != expected {
+		t.Fatalf("bad: %q", out)
+	}
+}
+
+func TestClientRun_error(t *testing.T) {
+	client, cleanup := testVirsh(t)
+	defer cleanup()
+
+	_, err := client.Run("dominfo", "missing")
+	if err == nil {
+		t.Fatal("should error")
+	}
+	if !isNotFound(err) {
+		t.Fatalf("should be not found: %s", err)
+	}
+	if !strings.HasPrefix(err.Error(), "virsh dominfo missing: failed to get") {
+		t.Fatalf("bad: %s", err)
+	}
+}
+
+func TestClientRunXML(t *testing.T) {
+	client, cleanup := testVirsh(t)
+	defer cleanup()
+
+	n := &networkXML{
+		Name:    "staging",
+		Forward: &networkForward{Mode: "nat"},
+	}
+	out, err := client.runXML(n, "net-define")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var actual networkXML
+	if err := xml.Unmarshal([]byte(out), &actual); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if actual.Name != "staging" || actual.Forward.Mode != "nat" {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	cases := []struct {
+		Err    error
+		Result bool
+	}{
+		{&VirshError{Output: "Network not found: no network with matching name 'foo'"}, true},
+		{&VirshError{Output: "failed to get vol '/tmp/foo'"}, true},
+		{&VirshError{Output: "Requested operation is not valid: network is not active"}, false},
+		{errors.New("not found"), false},
+	}
+
+	for i, tc := range cases {
+		if actual := isNotFound(tc.Err); actual != tc.Result {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
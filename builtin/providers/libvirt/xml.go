@@ -0,0 +1,300 @@
+package libvirt
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// The structures below are the parts of the libvirt XML formats for
+// domains, networks and storage volumes that Terraform manages. See
+// http://libvirt.org/format.html for the complete formats.
+
+type domainXML struct {
+	XMLName xml.Name      `xml:"domain"`
+	Type    string        `xml:"type,attr"`
+	Name    string        `xml:"name"`
+	UUID    string        `xml:"uuid,omitempty"`
+	Memory  sizeXML       `xml:"memory"`
+	VCPU    int           `xml:"vcpu"`
+	OS      domainOSXML   `xml:"os"`
+	Devices domainDevices `xml:"devices"`
+}
+
+type domainOSXML struct {
+	Type domainOSType `xml:"type"`
+	Boot []domainBoot `xml:"boot"`
+}
+
+type domainOSType struct {
+	Arch  string `xml:"arch,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+type domainBoot struct {
+	Dev string `xml:"dev,attr"`
+}
+
+type domainDevices struct {
+	Disks      []domainDisk      `xml:"disk"`
+	Interfaces []domainInterface `xml:"interface"`
+	Consoles   []domainConsole   `xml:"console"`
+}
+
+type domainDisk struct {
+	Type   string           `xml:"type,attr"`
+	Device string           `xml:"device,attr"`
+	Driver domainDiskDriver `xml:"driver"`
+	Source domainDiskSource `xml:"source"`
+	Target domainDiskTarget `xml:"target"`
+}
+
+type domainDiskDriver struct {
+	Name string `xml:"name,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type domainDiskSource struct {
+	File string `xml:"file,attr"`
+}
+
+type domainDiskTarget struct {
+	Dev string `xml:"dev,attr"`
+	Bus string `xml:"bus,attr"`
+}
+
+type domainInterface struct {
+	Type   string                `xml:"type,attr"`
+	MAC    *domainInterfaceMAC   `xml:"mac"`
+	Source domainInterfaceSource `xml:"source"`
+	Model  domainInterfaceModel  `xml:"model"`
+}
+
+type domainInterfaceMAC struct {
+	Address string `xml:"address,attr"`
+}
+
+type domainInterfaceSource struct {
+	Network string `xml:"network,attr"`
+}
+
+type domainInterfaceModel struct {
+	Type string `xml:"type,attr"`
+}
+
+type domainConsole struct {
+	Type string `xml:"type,attr"`
+}
+
+type networkXML struct {
+	XMLName xml.Name        `xml:"network"`
+	Name    string          `xml:"name"`
+	UUID    string          `xml:"uuid,omitempty"`
+	Forward *networkForward `xml:"forward"`
+	Bridge  *networkBridge  `xml:"bridge"`
+	IPs     []networkIPXML  `xml:"ip"`
+}
+
+type networkForward struct {
+	Mode string `xml:"mode,attr,omitempty"`
+}
+
+type networkBridge struct {
+	Name string `xml:"name,attr,omitempty"`
+}
+
+type networkIPXML struct {
+	Address string          `xml:"address,attr"`
+	Netmask string          `xml:"netmask,attr,omitempty"`
+	Prefix  string          `xml:"prefix,attr,omitempty"`
+	DHCP    *networkDHCPXML `xml:"dhcp"`
+}
+
+type networkDHCPXML struct {
+	Range networkDHCPRange `xml:"range"`
+}
+
+type networkDHCPRange struct {
+	Start string `xml:"start,attr"`
+	End   string `xml:"end,attr"`
+}
+
+type volumeXML struct {
+	XMLName      xml.Name         `xml:"volume"`
+	Name         string           `xml:"name"`
+	Key          string           `xml:"key,omitempty"`
+	Capacity     sizeXML          `xml:"capacity"`
+	Target       volumeTargetXML  `xml:"target"`
+	BackingStore *volumeTargetXML `xml:"backingStore"`
+}
+
+type volumeTargetXML struct {
+	Path   string          `xml:"path,omitempty"`
+	Format volumeFormatXML `xml:"format"`
+}
+
+type volumeFormatXML struct {
+	Type string `xml:"type,attr"`
+}
+
+// sizeXML is a size with a unit, such as the memory of a domain or the
+// capacity of a volume.
+type sizeXML struct {
+	Unit  string `xml:"unit,attr,omitempty"`
+	Value uint64 `xml:",chardata"`
+}
+
+// sizeUnits are the multipliers of the units libvirt uses for sizes. A
+// missing unit means bytes for volumes, but KiB for memory, so it isn't
+// in here.
+var sizeUnits = map[string]uint64{
+	"b":     1,
+	"bytes": 1,
+	"k":     1 << 10,
+	"KiB":   1 << 10,
+	"M":     1 << 20,
+	"MiB":   1 << 20,
+	"G":     1 << 30,
+	"GiB":   1 << 30,
+	"T":     1 << 40,
+	"TiB":   1 << 40,
+}
+
+// Bytes returns the size in bytes. def is the unit to use if none is
+// given.
+func (s sizeXML) Bytes(def string) (uint64, error) {
+	unit := s.Unit
+	if unit == "" {
+		unit = def
+	}
+
+	m, ok := sizeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit: %s", unit)
+	}
+
+	return s.Value * m, nil
+}
+
+// newNetworkIP returns the IP configuration of a network with the given
+// address range in CIDR notation. The host gets the first address of the
+// range, and the rest of it is given out to domains with DHCP.
+func newNetworkIP(cidr string) (*networkIPXML, error) {
+	ip, ipnet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, err
+	}
+
+	ip = ip.To4()
+	if ip == nil {
+		return nil, fmt.Errorf("%s: only IPv4 ranges are supported", cidr)
+	}
+
+	ones, bits := ipnet.Mask.Size()
+	if bits-ones < 2 {
+		return nil, fmt.Errorf("%s: range is too small", cidr)
+	}
+
+	// The network address is followed by the host, and the broadcast
+	// address is the last one.
+	start := ipnet.IP.To4()
+	end := make(net.IP, len(start))
+	for i := range start {
+		end[i] = start[i] | ^ipnet.Mask[i]
+	}
+
+	host := nextIP(start)
+	return &networkIPXML{
+		Address: host.String(),
+		Prefix:  fmt.Sprintf("%d", ones),
+		DHCP: &networkDHCPXML{
+			Range: networkDHCPRange{
+				Start: nextIP(host).String(),
+				End:   prevIP(end).String(),
+			},
+		},
+	}, nil
+}
+
+// CIDR returns the address range of the network IP configuration in
+// CIDR notation.
+func (n *networkIPXML) CIDR() (string, error) {
+	ip := net.ParseIP(n.Address)
+	if ip == nil {
+		return "", fmt.Errorf("invalid address: %s", n.Address)
+	}
+
+	var mask net.IPMask
+	if n.Netmask != "" {
+		m := net.ParseIP(n.Netmask)
+		if m == nil || m.To4() == nil {
+			return "", fmt.Errorf("invalid netmask: %s", n.Netmask)
+		}
+
+		mask = net.IPMask(m.To4())
+	} else {
+		_, ipnet, err := net.ParseCIDR(n.Address + "/" + n.Prefix)
+		if err != nil {
+			return "", err
+		}
+
+		mask = ipnet.Mask
+	}
+
+	ones, _ := mask.Size()
+	return fmt.Sprintf("%s/%d", ip.Mask(mask), ones), nil
+}
+
+func nextIP(ip net.IP) net.IP {
+	result := make(net.IP, len(ip))
+	copy(result, ip)
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i]++
+		if result[i] != 0 {
+			break
+		}
+	}
+
+	return result
+}
+
+func prevIP(ip net.IP) net.IP {
+	result := make(net.IP, len(ip))
+	copy(result, ip)
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i]--
+		if result[i] != 0xff {
+			break
+		}
+	}
+
+	return result
+}
+
+// diskTarget returns the device name of the disk with the given index,
+// such as "vda" for the first one.
+func diskTarget(i int) string {
+	var suffix string
+	for i++; i > 0; i = (i - 1) / 26 {
+		suffix = string(rune('a'+(i-1)%26)) + suffix
+	}
+
+	return "vd" + suffix
+}
+
+// parseInfo parses the "Key: value" output of the virsh info commands,
+// such as dominfo and net-info.
+func parseInfo(out string) map[string]string {
+	result := make(map[string]string)
+	for _, line := range strings.Split(out, "\n") {
+		idx := strings.Index(line, ":")
+		if idx < 0 {
+			continue
+		}
+
+		result[strings.TrimSpace(line[:idx])] = strings.TrimSpace(line[idx+1:])
+	}
+
+	return result
+}
@@ -0,0 +1,209 @@
+package libvirt
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+)
+
+func TestSizeXMLBytes(t *testing.T) {
+	cases := []struct {
+		Size   sizeXML
+		Def    string
+		Result uint64
+		Err    bool
+	}{
+		{sizeXML{Value: 524288}, "KiB", 512 << 20, false},
+		{sizeXML{Unit: "MiB", Value: 512}, "KiB", 512 << 20, false},
+		{sizeXML{Unit: "bytes", Value: 1024}, "KiB", 1024, false},
+		{sizeXML{Value: 1024}, "bytes", 1024, false},
+		{sizeXML{Unit: "G", Value: 10}, "bytes", 10 << 30, false},
+		{sizeXML{Unit: "furlongs", Value: 1}, "bytes", 0, true},
+	}
+
+	for i, tc := range cases {
+		actual, err := tc.Size.Bytes(tc.Def)
+		if (err != nil) != tc.Err {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+		if actual != tc.Result {
+			t.Fatalf("%d: bad: %d", i, actual)
+		}
+	}
+}
+
+func TestNewNetworkIP(t *testing.T) {
+	cases := []struct {
+		CIDR   string
+		Result *networkIPXML
+		Err    bool
+	}{
+		{
+			"10.17.3.0/24",
+			&networkIPXML{
+				Address: "10.17.3.1",
+				Prefix:  "24",
+				DHCP: &networkDHCPXML{
+					Range: networkDHCPRange{
+						Start: "10.17.3.2",
+						End:   "10.17.3.254",
+					},
+				},
+			},
+			false,
+		},
+
+		{
+			"192.168.0.7/30",
+			&networkIPXML{
+				Address: "192.168.0.5",
+				Prefix:  "30",
+				DHCP: &networkDHCPXML{
+					Range: networkDHCPRange{
+						Start: "192.168.0.6",
+						End:   "192.168.0.6",
+					},
+				},
+			},
+			false,
+		},
+
+		{"10.0.0.0/31", nil, true},
+		{"fd00::/64", nil, true},
+		{"nope", nil, true},
+	}
+
+	for i, tc := range cases {
+		actual, err := newNetworkIP(tc.CIDR)
+		if (err != nil) != tc.Err {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+		if !reflect.DeepEqual(actual, tc.Result) {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
+
+func TestNetworkIPXMLCIDR(t *testing.T) {
+	cases := []struct {
+		IP     networkIPXML
+		Result string
+	}{
+		{networkIPXML{Address: "10.17.3.1", Prefix: "24"}, "10.17.3.0/24"},
+		{
+			networkIPXML{Address: "192.168.122.1", Netmask: "255.255.255.0"},
+			"192.168.122.0/24",
+		},
+	}
+
+	for i, tc := range cases {
+		actual, err := tc.IP.CIDR()
+		if err != nil {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+		if actual != tc.Result {
+			t.Fatalf("%d: bad: %s", i, actual)
+		}
+	}
+}
+
+func TestDiskTarget(t *testing.T) {
+	cases := map[int]string{
+		0:  "vda",
+		1:  "vdb",
+		25: "vdz",
+		26: "vdaa",
+		27: "vdab",
+	}
+
+	for i, expected := range cases {
+		if actual := diskTarget(i); actual != expected {
+			t.Fatalf("%d: bad: %s", i, actual)
+		}
+	}
+}
+
+func TestParseInfo(t *testing.T) {
+	out := "Name:           default\n" +
+		"UUID:           5a6b1b2e-1d3c-4a6d-9a0e-3b7f1c2d4e5f\n" +
+		"Active:         yes\n" +
+		"Autostart:      no\n"
+
+	expected := map[string]string{
+		"Name":      "default",
+		"UUID":      "5a6b1b2e-1d3c-4a6d-9a0e-3b7f1c2d4e5f",
+		"Active":    "yes",
+		"Autostart": "no",
+	}
+
+	if actual := parseInfo(out); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestDomainXML_unmarshal(t *testing.T) {
+	var d domainXML
+	if err := xml.Unmarshal([]byte(testDomainXML), &d); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if d.UUID != "c7a5fdbd-cdaf-9455-926a-d65c16db1809" {
+		t.Fatalf("bad: %#v", d)
+	}
+
+	memory, err := d.Memory.Bytes("KiB")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if memory != 512<<20 {
+		t.Fatalf("bad: %d", memory)
+	}
+
+	if len(d.Devices.Disks) != 2 {
+		t.Fatalf("bad: %#v", d.Devices.Disks)
+	}
+	if d.Devices.Disks[0].Source.File != "/var/lib/libvirt/images/web.qcow2" {
+		t.Fatalf("bad: %#v", d.Devices.Disks[0])
+	}
+
+	if len(d.Devices.Interfaces) != 1 {
+		t.Fatalf("bad: %#v", d.Devices.Interfaces)
+	}
+	iface := d.Devices.Interfaces[0]
+	if iface.Source.Network != "staging" || iface.MAC.Address != "52:54:00:3a:5e:21" {
+		t.Fatalf("bad: %#v", iface)
+	}
+}
+
+const testDomainXML = `
+<domain type='kvm' id='3'>
+  <name>web</name>
+  <uuid>c7a5fdbd-cdaf-9455-926a-d65c16db1809</uuid>
+  <memory unit='KiB'>524288</memory>
+  <currentMemory unit='KiB'>524288</currentMemory>
+  <vcpu placement='static'>1</vcpu>
+  <os>
+    <type arch='x86_64' machine='pc-i440fx-2.1'>hvm</type>
+    <boot dev='hd'/>
+  </os>
+  <devices>
+    <emulator>/usr/bin/qemu-system-x86_64</emulator>
+    <disk type='file' device='disk'>
+      <driver name='qemu' type='qcow2'/>
+      <source file='/var/lib/libvirt/images/web.qcow2'/>
+      <target dev='vda' bus='virtio'/>
+    </disk>
+    <disk type='file' device='cdrom'>
+      <driver name='qemu' type='raw'/>
+      <target dev='hdc' bus='ide'/>
+      <readonly/>
+    </disk>
+    <interface type='network'>
+      <mac address='52:54:00:3a:5e:21'/>
+      <source network='staging' bridge='virbr1'/>
+      <model type='virtio'/>
+    </interface>
+    <console type='pty'/>
+  </devices>
+</domain>
+`
@@ -0,0 +1,201 @@
+package mailgun
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// APIURL is the Mailgun API that routes and SMTP credentials are managed
+// with. The client library only covers domains.
+const APIURL = "https://api.mailgun.net/v2"
+
+// APIClient manages Mailgun routes and SMTP credentials.
+type APIClient struct {
+	URL    string
+	APIKey string
+
+	HTTPClient *http.Client
+}
+
+// APIError is an error returned by the Mailgun API.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("Mailgun error (%d): %s", e.StatusCode, e.Message)
+}
+
+// Route is a Mailgun route, which matches incoming messages with an
+// expression and handles them with its actions.
+type Route struct {
+	Id          string   `json:"id"`
+	Priority    int      `json:"priority"`
+	Description string   `json:"description"`
+	Expression  string   `json:"expression"`
+	Actions     []string `json:"actions"`
+}
+
+// Credential is an SMTP login of a Mailgun domain.
+type Credential struct {
+	Login     string `json:"login"`
+	CreatedAt string `json:"created_at"`
+}
+
+type routeWrapper struct {
+	Route *Route `json:"route"`
+}
+
+// credentialPage is the number of credentials requested at a time.
+const credentialPage = 100
+
+// CreateRoute creates a route and returns it with its ID.
+func (c *APIClient) CreateRoute(route *Route) (*Route, error) {
+	var result routeWrapper
+	if err := c.request("POST", "/routes", routeForm(route), &result); err != nil {
+		return nil, err
+	}
+
+	return result.Route, nil
+}
+
+// Route returns the route with the given ID.
+func (c *APIClient) Route(id string) (*Route, error) {
+	var result routeWrapper
+	if err := c.request("GET", "/routes/"+id, nil, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Route, nil
+}
+
+// UpdateRoute changes the route with the given ID.
+func (c *APIClient) UpdateRoute(id string, route *Route) error {
+	return c.request("PUT", "/routes/"+id, routeForm(route), nil)
+}
+
+// DeleteRoute deletes the route with the given ID.
+func (c *APIClient) DeleteRoute(id string) error {
+	return c.request("DELETE", "/routes/"+id, nil, nil)
+}
+
+// CreateCredential creates an SMTP login for the domain. The login
+// is the full address, including the domain.
+func (c *APIClient) CreateCredential(domain, login, password string) error {
+	form := url.Values{}
+	form.Set("login", login)
+	form.Set("password", password)
+
+	return c.request("POST", credentialsPath(domain), form, nil)
+}
+
+// Credentials returns all the SMTP logins of the domain.
+func (c *APIClient) Credentials(domain string) ([]Credential, error) {
+	var all []Credential
+	for {
+		var result struct {
+			TotalCount int          `json:"total_count"`
+			Items      []Credential `json:"items"`
+		}
+
+		path := fmt.Sprintf(
+			"%s?skip=%d&limit=%d",
+			credentialsPath(domain), len(all), credentialPage)
+		if err := c.request("GET", path, nil, &result); err != nil {
+			return nil, err
+		}
+
+		all = append(all, result.Items...)
+		if len(result.Items) == 0 || len(all) >= result.TotalCount {
+			return all, nil
+		}
+	}
+}
+
+// UpdateCredentialPassword changes the password of an SMTP login.
+func (c *APIClient) UpdateCredentialPassword(domain, login, password string) error {
+	form := url.Values{}
+	form.Set("password", password)
+
+	return c.request("PUT", credentialPath(domain, login), form, nil)
+}
+
+// DeleteCredential deletes an SMTP login of the domain.
+func (c *APIClient) DeleteCredential(domain, login string) error {
+	return c.request("DELETE", credentialPath(domain, login), nil, nil)
+}
+
+func routeForm(route *Route) url.Values {
+	form := url.Values{}
+	form.Set("priority", strconv.Itoa(route.Priority))
+	form.Set("description", route.Description)
+	form.Set("expression", route.Expression)
+	for _, action := range route.Actions {
+		form.Add("action", action)
+	}
+
+	return form
+}
+
+func credentialsPath(domain string) string {
+	return "/domains/" + domain + "/credentials"
+}
+
+// credentialPath is the path of a single login, which the API addresses
+// by its local part only.
+func credentialPath(domain, login string) string {
+	login = strings.TrimSuffix(login, "@"+domain)
+	return credentialsPath(domain) + "/" + url.QueryEscape(login)
+}
+
+func (c *APIClient) request(
+	method string, path string, form url.Values, result interface{}) error {
+	base := c.URL
+	if base == "" {
+		base = APIURL
+	}
+
+	req, err := http.NewRequest(
+		method, base+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth("api", c.APIKey)
+	req.Header.Set("Accept", "application/json")
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		var e struct {
+			Message string `json:"message"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&e); err != nil || e.Message == "" {
+			e.Message = resp.Status
+		}
+
+		return &APIError{StatusCode: resp.StatusCode, Message: e.Message}
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,179 @@
+package mailgun
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestAPIClient_routes(t *testing.T) {
+	var requests []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "api" || pass != "key-foo" {
+			w.WriteHeader(401)
+			fmt.Fprint(w, `{"message":"Forbidden"}`)
+			return
+		}
+
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method + " " + r.URL.Path {
+		case "POST /routes", "PUT /routes/abc":
+			r.ParseForm()
+			if r.Form.Get("expression") != `match_recipient(".*@example.com")` {
+				t.Errorf("bad expression: %#v", r.Form)
+			}
+			actions := r.Form["action"]
+			if !reflect.DeepEqual(actions, []string{`forward("a@example.com")`, "stop()"}) {
+				t.Errorf("bad actions: %#v", actions)
+			}
+			fmt.Fprint(w, `{"message":"Route has been created","route":{"id":"abc"}}`)
+		case "GET /routes/abc":
+			fmt.Fprint(w, `{"route":{"id":"abc","priority":1,"actions":["stop()"]}}`)
+		default:
+			w.WriteHeader(404)
+			fmt.Fprint(w, `{"message":"Route not found"}`)
+		}
+	}))
+	defer ts.Close()
+
+	c := &APIClient{URL: ts.URL, APIKey: "key-foo"}
+
+	opts := &Route{
+		Priority:   1,
+		Expression: `match_recipient(".*@example.com")`,
+		Actions:    []string{`forward("a@example.com")`, "stop()"},
+	}
+	route, err := c.CreateRoute(opts)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if route.Id != "abc" {
+		t.Fatalf("bad: %#v", route)
+	}
+
+	if err := c.UpdateRoute("abc", opts); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	route, err = c.Route("abc")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if route.Priority != 1 || !reflect.DeepEqual(route.Actions, []string{"stop()"}) {
+		t.Fatalf("bad: %#v", route)
+	}
+
+	_, err = c.Route("nope")
+	if apierr, ok := err.(*APIError); !ok || apierr.StatusCode != 404 {
+		t.Fatalf("bad: %#v", err)
+	}
+
+	expected := []string{
+		"POST /routes",
+		"PUT /routes/abc",
+		"GET /routes/abc",
+		"GET /routes/nope",
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Fatalf("bad: %#v", requests)
+	}
+
+	c.APIKey = "wrong"
+	_, err = c.Route("abc")
+	if apierr, ok := err.(*APIError); !ok || apierr.StatusCode != 401 || apierr.Message != "Forbidden" {
+		t.Fatalf("bad: %#v", err)
+	}
+}
+
+func TestAPIClient_credentials(t *testing.T) {
+	var requests []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method + " " + r.URL.Path {
+		case "POST /domains/example.com/credentials":
+			if r.Form.Get("login") != "alice@example.com" || r.Form.Get("password") != "secret" {
+				t.Errorf("bad: %#v", r.Form)
+			}
+			fmt.Fprint(w, `{"message":"Created 1 credentials pair(s)"}`)
+		case "GET /domains/example.com/credentials":
+			// Two pages of a single login each
+			if r.Form.Get("skip") == "0" {
+				fmt.Fprint(w, `{"total_count":2,"items":[{"login":"alice@example.com"}]}`)
+			} else {
+				fmt.Fprint(w, `{"total_count":2,"items":[{"login":"bob@example.com"}]}`)
+			}
+		case "PUT /domains/example.com/credentials/alice":
+			if r.Form.Get("password") != "changed" {
+				t.Errorf("bad: %#v", r.Form)
+			}
+			fmt.Fprint(w, `{"message":"Password changed"}`)
+		case "DELETE /domains/example.com/credentials/alice":
+			fmt.Fprint(w, `{"message":"Credentials have been deleted"}`)
+		default:
+			w.WriteHeader(404)
+			fmt.Fprint(w, `{"message":"Domain not found"}`)
+		}
+	}))
+	defer ts.Close()
+
+	c := &APIClient{URL: ts.URL, APIKey: "key-foo"}
+
+	if err := c.CreateCredential("example.com", "alice@example.com", "secret"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	credentials, err := c.Credentials("example.com")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(credentials) != 2 ||
+		credentials[0].Login != "alice@example.com" ||
+		credentials[1].Login != "bob@example.com" {
+		t.Fatalf("bad: %#v", credentials)
+	}
+
+	err = c.UpdateCredentialPassword("example.com", "alice@example.com", "changed")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := c.DeleteCredential("example.com", "alice@example.com"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	_, err = c.Credentials("example.org")
+	if apierr, ok := err.(*APIError); !ok || apierr.StatusCode != 404 {
+		t.Fatalf("bad: %#v", err)
+	}
+
+	expected := []string{
+		"POST /domains/example.com/credentials",
+		"GET /domains/example.com/credentials",
+		"GET /domains/example.com/credentials",
+		"PUT /domains/example.com/credentials/alice",
+		"DELETE /domains/example.com/credentials/alice",
+		"GET /domains/example.org/credentials",
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Fatalf("bad: %#v", requests)
+	}
+}
+
+func TestCredentialLogin(t *testing.T) {
+	cases := []struct {
+		Login    string
+		Expected string
+	}{
+		{"alice", "alice@example.com"},
+		{"alice@example.com", "alice@example.com"},
+	}
+
+	for _, tc := range cases {
+		if actual := credentialLogin("example.com", tc.Login); actual != tc.Expected {
+			t.Fatalf("bad: %s: %s", tc.Login, actual)
+		}
+	}
+}
@@ -1,8 +1,8 @@
 package mailgun
 
 import (
+	"fmt"
 	"log"
-	"os"
 
 	"github.com/pearkes/mailgun"
 )
@@ -11,14 +11,19 @@ type Config struct {
 	APIKey string `mapstructure:"api_key"`
 }
 
+// Client is the meta of the provider. Domains are managed with the
+// client library and everything else with the API client.
+type Client struct {
+	Domains *mailgun.Client
+	API     *APIClient
+}
+
 // Client() returns a new client for accessing mailgun.
 //
-func (c *Config) Client() (*mailgun.Client, error) {
-
-	// If we have env vars set (like in the acc) tests,
-	// we need to override the values passed in here.
-	if v := os.Getenv("MAILGUN_API_KEY"); v != "" {
-		c.APIKey = v
+func (c *Config) Client() (*Client, error) {
+	if c.APIKey == "" {
+		return nil, fmt.Errorf(
+			"api_key must be set, or MAILGUN_API_KEY in the environment")
 	}
 
 	// We don't set a domain right away
@@ -30,5 +35,8 @@ func (c *Config) Client() (*mailgun.Client, error) {
 
 	log.Printf("[INFO] Mailgun Client configured ")
 
-	return client, nil
+	return &Client{
+		Domains: client,
+		API:     &APIClient{APIKey: c.APIKey},
+	}, nil
 }
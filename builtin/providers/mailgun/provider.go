@@ -12,13 +12,17 @@ func Provider() *schema.Provider {
 	return &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"api_key": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
+				Type:      schema.TypeString,
+				Optional:  true,
+				WriteOnly: true,
+				EnvVars:   []string{"MAILGUN_API_KEY"},
 			},
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
-			"mailgun_domain": resourceMailgunDomain(),
+			"mailgun_credential": resourceMailgunCredential(),
+			"mailgun_domain":     resourceMailgunDomain(),
+			"mailgun_route":      resourceMailgunRoute(),
 		},
 
 		ConfigureFunc: providerConfigure,
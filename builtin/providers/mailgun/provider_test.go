@@ -7,7 +7,6 @@ import (
 	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
-	"github.com/pearkes/mailgun"
 )
 
 var testAccProviders map[string]terraform.ResourceProvider
@@ -54,8 +53,8 @@ func TestProviderConfigure(t *testing.T) {
 		t.Fatalf("err: %s", err)
 	}
 
-	config := rp.Meta().(*mailgun.Client)
-	if config.ApiKey != expectedKey {
+	config := rp.Meta().(*Client)
+	if config.Domains.ApiKey != expectedKey || config.API.APIKey != expectedKey {
 		t.Fatalf("bad: %#v", config)
 	}
 }
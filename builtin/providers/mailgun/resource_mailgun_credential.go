@@ -0,0 +1,122 @@
+package mailgun
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceMailgunCredential() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceMailgunCredentialCreate,
+		Read:   resourceMailgunCredentialRead,
+		Update: resourceMailgunCredentialUpdate,
+		Delete: resourceMailgunCredentialDelete,
+
+		Schema: map[string]*schema.Schema{
+			"domain": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			// The login can be given with or without the domain, the ID
+			// is always the full address.
+			"login": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"password": &schema.Schema{
+				Type:      schema.TypeString,
+				Required:  true,
+				WriteOnly: true,
+			},
+		},
+	}
+}
+
+// credentialLogin returns the full address of a login of the domain.
+func credentialLogin(domain, login string) string {
+	if strings.Contains(login, "@") {
+		return login
+	}
+
+	return login + "@" + domain
+}
+
+func resourceMailgunCredentialCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client).API
+
+	domain := d.Get("domain").(string)
+	login := credentialLogin(domain, d.Get("login").(string))
+
+	log.Printf("[DEBUG] Credential create: %s", login)
+	err := client.CreateCredential(domain, login, d.Get("password").(string))
+	if err != nil {
+		return fmt.Errorf("Error creating credential: %s", err)
+	}
+
+	d.SetId(login)
+	log.Printf("[INFO] Credential ID: %s", d.Id())
+
+	return resourceMailgunCredentialRead(d, meta)
+}
+
+func resourceMailgunCredentialRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client).API
+
+	credentials, err := client.Credentials(d.Get("domain").(string))
+	if err != nil {
+		if apierr, ok := err.(*APIError); ok && apierr.StatusCode == 404 {
+			// The domain is gone, and its logins with it
+			d.SetId("")
+			return nil
+		}
+
+		return fmt.Errorf("Error retrieving credentials: %s", err)
+	}
+
+	for _, c := range credentials {
+		if c.Login == d.Id() {
+			return nil
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourceMailgunCredentialUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client).API
+
+	if d.HasChange("password") {
+		log.Printf("[DEBUG] Credential password update: %s", d.Id())
+		err := client.UpdateCredentialPassword(
+			d.Get("domain").(string), d.Id(), d.Get("password").(string))
+		if err != nil {
+			return fmt.Errorf("Error updating credential: %s", err)
+		}
+	}
+
+	return resourceMailgunCredentialRead(d, meta)
+}
+
+func resourceMailgunCredentialDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client).API
+
+	log.Printf("[INFO] Deleting Credential: %s", d.Id())
+	err := client.DeleteCredential(d.Get("domain").(string), d.Id())
+	if err != nil {
+		if apierr, ok := err.(*APIError); ok && apierr.StatusCode == 404 {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting credential: %s", err)
+	}
+
+	return nil
+}
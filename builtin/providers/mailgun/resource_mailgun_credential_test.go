@@ -0,0 +1,92 @@
+package mailgun
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccMailgunCredential_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMailgunCredentialDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckMailgunCredentialConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMailgunCredentialExists("mailgun_credential.foobar"),
+					resource.TestCheckResourceAttr(
+						"mailgun_credential.foobar", "login", "postmaster"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckMailgunCredentialDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client).API
+
+	for _, rs := range s.Resources {
+		if rs.Type != "mailgun_credential" {
+			continue
+		}
+
+		credentials, err := client.Credentials(rs.Attributes["domain"])
+		if err != nil {
+			// The domain was destroyed along with the login
+			continue
+		}
+
+		for _, c := range credentials {
+			if c.Login == rs.ID {
+				return fmt.Errorf("Credential still exists")
+			}
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckMailgunCredentialExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.ID != "postmaster@terraform.example.com" {
+			return fmt.Errorf("Bad Credential ID: %s", rs.ID)
+		}
+
+		client := testAccProvider.Meta().(*Client).API
+
+		credentials, err := client.Credentials(rs.Attributes["domain"])
+		if err != nil {
+			return err
+		}
+
+		for _, c := range credentials {
+			if c.Login == rs.ID {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("Credential not found")
+	}
+}
+
+const testAccCheckMailgunCredentialConfig_basic = `
+resource "mailgun_domain" "foobar" {
+    name = "terraform.example.com"
+    spam_action = "disabled"
+    smtp_password = "foobar"
+}
+
+resource "mailgun_credential" "foobar" {
+    domain = "${mailgun_domain.foobar.name}"
+    login = "postmaster"
+    password = "supersecret"
+}`
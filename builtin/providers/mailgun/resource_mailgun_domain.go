@@ -29,15 +29,14 @@ func resourceMailgunDomain() *schema.Resource {
 			},
 
 			"smtp_password": &schema.Schema{
-				Type:     schema.TypeString,
-				ForceNew: true,
-				Required: true,
+				Type:      schema.TypeString,
+				Required:  true,
+				WriteOnly: true,
 			},
 
 			"smtp_login": &schema.Schema{
 				Type:     schema.TypeString,
 				Computed: true,
-				Optional: true,
 			},
 
 			"wildcard": &schema.Schema{
@@ -51,7 +50,7 @@ func resourceMailgunDomain() *schema.Resource {
 }
 
 func resourceMailgunDomainCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*mailgun.Client)
+	client := meta.(*Client).Domains
 
 	opts := mailgun.CreateDomain{}
 
@@ -83,7 +82,7 @@ func resourceMailgunDomainCreate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceMailgunDomainDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*mailgun.Client)
+	client := meta.(*Client).Domains
 
 	log.Printf("[INFO] Deleting Domain: %s", d.Id())
 
@@ -97,7 +96,7 @@ func resourceMailgunDomainDelete(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceMailgunDomainRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*mailgun.Client)
+	client := meta.(*Client).Domains
 
 	_, err := resource_mailgin_domain_retrieve(d.Id(), client, d)
 
@@ -116,7 +115,6 @@ func resource_mailgin_domain_retrieve(id string, client *mailgun.Client, d *sche
 	}
 
 	d.Set("name", domain.Name)
-	d.Set("smtp_password", domain.SmtpPassword)
 	d.Set("smtp_login", domain.SmtpLogin)
 	d.Set("wildcard", domain.Wildcard)
 	d.Set("spam_action", domain.SpamAction)
@@ -26,8 +26,6 @@ func TestAccMailgunDomain_Basic(t *testing.T) {
 						"mailgun_domain.foobar", "name", "terraform.example.com"),
 					resource.TestCheckResourceAttr(
 						"mailgun_domain.foobar", "spam_action", "disabled"),
-					resource.TestCheckResourceAttr(
-						"mailgun_domain.foobar", "smtp_password", "foobar"),
 					resource.TestCheckResourceAttr(
 						"mailgun_domain.foobar", "wildcard", "true"),
 				),
@@ -37,7 +35,7 @@ func TestAccMailgunDomain_Basic(t *testing.T) {
 }
 
 func testAccCheckMailgunDomainDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*mailgun.Client)
+	client := testAccProvider.Meta().(*Client).Domains
 
 	for _, rs := range s.Resources {
 		if rs.Type != "mailgun_domain" {
@@ -89,7 +87,7 @@ func testAccCheckMailgunDomainExists(n string, Domain *mailgun.Domain) resource.
 			return fmt.Errorf("No Domain ID is set")
 		}
 
-		client := testAccProvider.Meta().(*mailgun.Client)
+		client := testAccProvider.Meta().(*Client).Domains
 
 		foundDomain, err := client.RetrieveDomain(rs.ID)
 
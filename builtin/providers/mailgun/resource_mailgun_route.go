@@ -0,0 +1,122 @@
+package mailgun
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceMailgunRoute() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceMailgunRouteCreate,
+		Read:   resourceMailgunRouteRead,
+		Update: resourceMailgunRouteUpdate,
+		Delete: resourceMailgunRouteDelete,
+
+		Schema: map[string]*schema.Schema{
+			"priority": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"expression": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"actions": &schema.Schema{
+				Type:     schema.TypeList,
+				Required: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceMailgunRouteCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client).API
+
+	opts := resourceMailgunRouteOpts(d)
+	log.Printf("[DEBUG] Route create configuration: %#v", opts)
+
+	route, err := client.CreateRoute(opts)
+	if err != nil {
+		return fmt.Errorf("Error creating route: %s", err)
+	}
+
+	d.SetId(route.Id)
+	log.Printf("[INFO] Route ID: %s", d.Id())
+
+	return resourceMailgunRouteRead(d, meta)
+}
+
+func resourceMailgunRouteRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client).API
+
+	route, err := client.Route(d.Id())
+	if err != nil {
+		if apierr, ok := err.(*APIError); ok && apierr.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+
+		return fmt.Errorf("Error retrieving route: %s", err)
+	}
+
+	d.Set("priority", route.Priority)
+	d.Set("description", route.Description)
+	d.Set("expression", route.Expression)
+	d.Set("actions", route.Actions)
+
+	return nil
+}
+
+func resourceMailgunRouteUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client).API
+
+	// The API replaces every field of the route, so the whole
+	// configuration is sent even if only one field changed.
+	opts := resourceMailgunRouteOpts(d)
+	log.Printf("[DEBUG] Route update configuration: %#v", opts)
+
+	if err := client.UpdateRoute(d.Id(), opts); err != nil {
+		return fmt.Errorf("Error updating route: %s", err)
+	}
+
+	return resourceMailgunRouteRead(d, meta)
+}
+
+func resourceMailgunRouteDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client).API
+
+	log.Printf("[INFO] Deleting Route: %s", d.Id())
+	if err := client.DeleteRoute(d.Id()); err != nil {
+		if apierr, ok := err.(*APIError); ok && apierr.StatusCode == 404 {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting route: %s", err)
+	}
+
+	return nil
+}
+
+func resourceMailgunRouteOpts(d *schema.ResourceData) *Route {
+	route := &Route{
+		Priority:    d.Get("priority").(int),
+		Description: d.Get("description").(string),
+		Expression:  d.Get("expression").(string),
+	}
+
+	for _, v := range d.Get("actions").([]interface{}) {
+		route.Actions = append(route.Actions, v.(string))
+	}
+
+	return route
+}
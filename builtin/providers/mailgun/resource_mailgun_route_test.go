@@ -0,0 +1,104 @@
+package mailgun
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccMailgunRoute_Basic(t *testing.T) {
+	var route Route
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMailgunRouteDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckMailgunRouteConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMailgunRouteExists("mailgun_route.foobar", &route),
+					resource.TestCheckResourceAttr(
+						"mailgun_route.foobar", "priority", "1"),
+					resource.TestCheckResourceAttr(
+						"mailgun_route.foobar", "actions.#", "2"),
+					resource.TestCheckResourceAttr(
+						"mailgun_route.foobar", "actions.1", "stop()"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccCheckMailgunRouteConfig_updated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMailgunRouteExists("mailgun_route.foobar", &route),
+					resource.TestCheckResourceAttr(
+						"mailgun_route.foobar", "priority", "5"),
+					resource.TestCheckResourceAttr(
+						"mailgun_route.foobar", "actions.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckMailgunRouteDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client).API
+
+	for _, rs := range s.Resources {
+		if rs.Type != "mailgun_route" {
+			continue
+		}
+
+		_, err := client.Route(rs.ID)
+		if err == nil {
+			return fmt.Errorf("Route still exists")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckMailgunRouteExists(n string, route *Route) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.ID == "" {
+			return fmt.Errorf("No Route ID is set")
+		}
+
+		client := testAccProvider.Meta().(*Client).API
+
+		found, err := client.Route(rs.ID)
+		if err != nil {
+			return err
+		}
+
+		if found.Id != rs.ID {
+			return fmt.Errorf("Route not found")
+		}
+
+		*route = *found
+
+		return nil
+	}
+}
+
+const testAccCheckMailgunRouteConfig_basic = `
+resource "mailgun_route" "foobar" {
+    priority = 1
+    description = "terraform acceptance test"
+    expression = "match_recipient('.*@terraform.example.com')"
+    actions = ["forward('http://example.com/messages')", "stop()"]
+}`
+
+const testAccCheckMailgunRouteConfig_updated = `
+resource "mailgun_route" "foobar" {
+    priority = 5
+    description = "terraform acceptance test"
+    expression = "match_recipient('.*@terraform.example.com')"
+    actions = ["stop()"]
+}`
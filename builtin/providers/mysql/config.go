@@ -0,0 +1,114 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	driver "github.com/go-sql-driver/mysql"
+)
+
+type Config struct {
+	Endpoint string `mapstructure:"endpoint"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+}
+
+// Client connects to a MySQL server.
+//
+// The server isn't connected to until a resource needs it, so that the
+// server can be created in the same configuration, with the provider
+// configured from its attributes.
+type Client struct {
+	config Config
+
+	db   *sql.DB
+	lock sync.Mutex
+}
+
+// Client() returns a new client for the configured server.
+func (c *Config) Client() (*Client, error) {
+	if c.Endpoint == "" {
+		c.Endpoint = "localhost:3306"
+	}
+
+	log.Printf("[INFO] MySQL Client configured for: %s@%s",
+		c.Username, c.Endpoint)
+
+	return &Client{config: *c}, nil
+}
+
+// DB returns the connection pool for the server.
+func (c *Client) DB() (*sql.DB, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.db != nil {
+		return c.db, nil
+	}
+
+	// This is checked now rather than when the provider is configured,
+	// since it may come from a server that isn't created yet then.
+	if c.config.Username == "" {
+		return nil, fmt.Errorf(
+			"username must be set, or MYSQL_USERNAME in the environment")
+	}
+
+	db, err := sql.Open("mysql", c.config.dsn())
+	if err != nil {
+		return nil, err
+	}
+
+	c.db = db
+	return db, nil
+}
+
+// dsn returns the data source name of the server for the driver.
+func (c *Config) dsn() string {
+	// An endpoint with a slash is the path of a Unix socket
+	network := "tcp"
+	if strings.HasPrefix(c.Endpoint, "/") {
+		network = "unix"
+	}
+
+	return fmt.Sprintf("%s:%s@%s(%s)/",
+		c.Username, c.Password, network, c.Endpoint)
+}
+
+// quoteIdentifier quotes the name of a database or table for use in a
+// statement.
+func quoteIdentifier(name string) string {
+	return "`" + strings.Replace(name, "`", "``", -1) + "`"
+}
+
+// quoteString quotes a string for use in a statement where parameters
+// can't be used, such as the user names and passwords of CREATE USER.
+func quoteString(v string) string {
+	v = strings.Replace(v, `\`, `\\`, -1)
+	return "'" + strings.Replace(v, "'", `\'`, -1) + "'"
+}
+
+// quoteAccount quotes the user and host of an account, such as
+// 'app'@'%'.
+func quoteAccount(user, host string) string {
+	return quoteString(user) + "@" + quoteString(host)
+}
+
+// isError returns true if the error is a MySQL error with one of the
+// given numbers, such as 1141 when there's no such grant.
+func isError(err error, numbers ...uint16) bool {
+	e, ok := err.(*driver.MySQLError)
+	if !ok {
+		return false
+	}
+
+	for _, n := range numbers {
+		if e.Number == n {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,54 @@
+package mysql
+
+import (
+	"testing"
+)
+
+func TestConfigDSN(t *testing.T) {
+	cases := []struct {
+		Config Config
+		DSN    string
+	}{
+		{
+			Config{Endpoint: "db.example.com:3306", Username: "root", Password: "secret"},
+			"root:secret@tcp(db.example.com:3306)/",
+		},
+		{
+			Config{Endpoint: "/var/run/mysqld/mysqld.sock", Username: "root"},
+			"root:@unix(/var/run/mysqld/mysqld.sock)/",
+		},
+	}
+
+	for i, tc := range cases {
+		if actual := tc.Config.dsn(); actual != tc.DSN {
+			t.Fatalf("%d: bad: %s", i, actual)
+		}
+	}
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	cases := map[string]string{
+		"app":    "`app`",
+		"we`ird": "`we``ird`",
+	}
+
+	for input, expected := range cases {
+		if actual := quoteIdentifier(input); actual != expected {
+			t.Fatalf("%s: bad: %s", input, actual)
+		}
+	}
+}
+
+func TestQuoteAccount(t *testing.T) {
+	actual := quoteAccount(`o'brien`, `%`)
+	expected := `'o\'brien'@'%'`
+	if actual != expected {
+		t.Fatalf("bad: %s", actual)
+	}
+
+	actual = quoteString(`back\slash`)
+	expected = `'back\\slash'`
+	if actual != expected {
+		t.Fatalf("bad: %s", actual)
+	}
+}
@@ -0,0 +1,50 @@
+package mysql
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mitchellh/mapstructure"
+)
+
+// Provider returns a terraform.ResourceProvider.
+func Provider() *schema.Provider {
+	return &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"endpoint": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				EnvVars:  []string{"MYSQL_ENDPOINT"},
+			},
+
+			"username": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				EnvVars:  []string{"MYSQL_USERNAME"},
+			},
+
+			"password": &schema.Schema{
+				Type:      schema.TypeString,
+				Optional:  true,
+				WriteOnly: true,
+				EnvVars:   []string{"MYSQL_PASSWORD"},
+			},
+		},
+
+		ResourcesMap: map[string]*schema.Resource{
+			"mysql_database": resourceMysqlDatabase(),
+			"mysql_grant":    resourceMysqlGrant(),
+			"mysql_user":     resourceMysqlUser(),
+		},
+
+		ConfigureFunc: providerConfigure,
+	}
+}
+
+func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+	var config Config
+	configRaw := d.Get("").(map[string]interface{})
+	if err := mapstructure.Decode(configRaw, &config); err != nil {
+		return nil, err
+	}
+
+	return config.Client()
+}
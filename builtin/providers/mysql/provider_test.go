@@ -0,0 +1,39 @@
+package mysql
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+var testAccProviders map[string]terraform.ResourceProvider
+var testAccProvider *schema.Provider
+
+func init() {
+	testAccProvider = Provider()
+	testAccProviders = map[string]terraform.ResourceProvider{
+		"mysql": testAccProvider,
+	}
+}
+
+func TestProvider(t *testing.T) {
+	if err := Provider().InternalValidate(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestProvider_impl(t *testing.T) {
+	var _ terraform.ResourceProvider = Provider()
+}
+
+func testAccPreCheck(t *testing.T) {
+	if v := os.Getenv("MYSQL_ENDPOINT"); v == "" {
+		t.Fatal("MYSQL_ENDPOINT must be set for acceptance tests")
+	}
+
+	if v := os.Getenv("MYSQL_USERNAME"); v == "" {
+		t.Fatal("MYSQL_USERNAME must be set for acceptance tests")
+	}
+}
@@ -0,0 +1,134 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceMysqlDatabase() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceMysqlDatabaseCreate,
+		Read:   resourceMysqlDatabaseRead,
+		Update: resourceMysqlDatabaseUpdate,
+		Delete: resourceMysqlDatabaseDelete,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"default_character_set": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"default_collation": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// databaseOptions returns the character set and collation options of
+// CREATE DATABASE and ALTER DATABASE.
+func databaseOptions(d *schema.ResourceData) string {
+	var result string
+	if v := d.Get("default_character_set").(string); v != "" {
+		result += " CHARACTER SET " + quoteString(v)
+	}
+	if v := d.Get("default_collation").(string); v != "" {
+		result += " COLLATE " + quoteString(v)
+	}
+
+	return result
+}
+
+func resourceMysqlDatabaseCreate(d *schema.ResourceData, meta interface{}) error {
+	db, err := meta.(*Client).DB()
+	if err != nil {
+		return err
+	}
+
+	name := d.Get("name").(string)
+	stmt := "CREATE DATABASE " + quoteIdentifier(name) + databaseOptions(d)
+
+	log.Printf("[DEBUG] Database create: %s", stmt)
+	if _, err := db.Exec(stmt); err != nil {
+		return fmt.Errorf("Error creating database: %s", err)
+	}
+
+	d.SetId(name)
+	log.Printf("[INFO] Database ID: %s", d.Id())
+
+	return resourceMysqlDatabaseRead(d, meta)
+}
+
+func resourceMysqlDatabaseRead(d *schema.ResourceData, meta interface{}) error {
+	db, err := meta.(*Client).DB()
+	if err != nil {
+		return err
+	}
+
+	var charset, collation string
+	err = db.QueryRow(
+		"SELECT DEFAULT_CHARACTER_SET_NAME, DEFAULT_COLLATION_NAME "+
+			"FROM information_schema.SCHEMATA WHERE SCHEMA_NAME = ?",
+		d.Id()).Scan(&charset, &collation)
+	if err == sql.ErrNoRows {
+		// The database doesn't exist anymore
+		d.SetId("")
+
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Error reading database: %s", err)
+	}
+
+	d.Set("name", d.Id())
+	d.Set("default_character_set", charset)
+	d.Set("default_collation", collation)
+
+	return nil
+}
+
+func resourceMysqlDatabaseUpdate(d *schema.ResourceData, meta interface{}) error {
+	db, err := meta.(*Client).DB()
+	if err != nil {
+		return err
+	}
+
+	if d.HasChange("default_character_set") || d.HasChange("default_collation") {
+		stmt := "ALTER DATABASE " + quoteIdentifier(d.Id()) + databaseOptions(d)
+
+		log.Printf("[DEBUG] Database update: %s", stmt)
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("Error updating database: %s", err)
+		}
+	}
+
+	return resourceMysqlDatabaseRead(d, meta)
+}
+
+func resourceMysqlDatabaseDelete(d *schema.ResourceData, meta interface{}) error {
+	db, err := meta.(*Client).DB()
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[INFO] Deleting database: %s", d.Id())
+	_, err = db.Exec("DROP DATABASE IF EXISTS " + quoteIdentifier(d.Id()))
+	if err != nil {
+		return fmt.Errorf("Error deleting database: %s", err)
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,115 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccMysqlDatabase_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMysqlDatabaseDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccMysqlDatabaseConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMysqlDatabaseExists("mysql_database.foobar"),
+					resource.TestCheckResourceAttr(
+						"mysql_database.foobar", "default_character_set", "utf8"),
+					resource.TestCheckResourceAttr(
+						"mysql_database.foobar", "default_collation", "utf8_general_ci"),
+				),
+			},
+
+			resource.TestStep{
+				Config: testAccMysqlDatabaseConfig_update,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMysqlDatabaseExists("mysql_database.foobar"),
+					resource.TestCheckResourceAttr(
+						"mysql_database.foobar", "default_collation", "utf8_bin"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckMysqlDatabaseDestroy(s *terraform.State) error {
+	for _, rs := range s.Resources {
+		if rs.Type != "mysql_database" {
+			continue
+		}
+
+		exists, err := testAccMysqlDatabaseExists(rs.ID)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			return fmt.Errorf("Database still exists")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckMysqlDatabaseExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.ID == "" {
+			return fmt.Errorf("No database ID is set")
+		}
+
+		exists, err := testAccMysqlDatabaseExists(rs.ID)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			return fmt.Errorf("Database not found")
+		}
+
+		return nil
+	}
+}
+
+func testAccMysqlDatabaseExists(name string) (bool, error) {
+	db, err := testAccProvider.Meta().(*Client).DB()
+	if err != nil {
+		return false, err
+	}
+
+	var found string
+	err = db.QueryRow(
+		"SELECT SCHEMA_NAME FROM information_schema.SCHEMATA "+
+			"WHERE SCHEMA_NAME = ?", name).Scan(&found)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+const testAccMysqlDatabaseConfig = `
+resource "mysql_database" "foobar" {
+	name = "terraform_test"
+	default_character_set = "utf8"
+	default_collation = "utf8_general_ci"
+}
+`
+
+const testAccMysqlDatabaseConfig_update = `
+resource "mysql_database" "foobar" {
+	name = "terraform_test"
+	default_character_set = "utf8"
+	default_collation = "utf8_bin"
+}
+`
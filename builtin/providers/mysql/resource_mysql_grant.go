@@ -0,0 +1,270 @@
+package mysql
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceMysqlGrant() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceMysqlGrantCreate,
+		Read:   resourceMysqlGrantRead,
+		Delete: resourceMysqlGrantDelete,
+
+		Schema: map[string]*schema.Schema{
+			"user": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"host": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"database": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"table": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"privileges": &schema.Schema{
+				Type:     schema.TypeSet,
+				Required: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set: func(v interface{}) int {
+					return hashcode.String(strings.ToUpper(v.(string)))
+				},
+			},
+
+			"grant_option": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+// grantRegexp matches the lines printed by SHOW GRANTS.
+var grantRegexp = regexp.MustCompile(`^GRANT (.+?) ON (.+?) TO ('.*'@'.*?')(.*)$`)
+
+// parsedGrant is a line printed by SHOW GRANTS.
+type parsedGrant struct {
+	Privileges  []string
+	Target      string
+	GrantOption bool
+}
+
+// parseGrant parses a line printed by SHOW GRANTS. "ALL PRIVILEGES" is
+// returned as "ALL", like it can be given in the configuration.
+func parseGrant(line string) (*parsedGrant, bool) {
+	m := grantRegexp.FindStringSubmatch(line)
+	if m == nil {
+		return nil, false
+	}
+
+	result := &parsedGrant{
+		Target:      m[2],
+		GrantOption: strings.Contains(m[4], "WITH GRANT OPTION"),
+	}
+
+	// Privileges on columns list the columns in parentheses, separated
+	// by commas too
+	var depth, start int
+	privileges := m[1] + ","
+	for i, c := range privileges {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth > 0 {
+				continue
+			}
+
+			p := strings.TrimSpace(privileges[start:i])
+			if p == "ALL PRIVILEGES" {
+				p = "ALL"
+			}
+
+			result.Privileges = append(result.Privileges, p)
+			start = i + 1
+		}
+	}
+
+	return result, true
+}
+
+// grantTarget returns the database and table of a grant as they are in
+// GRANT statements.
+func grantTarget(database, table string) string {
+	if table == "" || table == "*" {
+		return quoteIdentifier(database) + ".*"
+	}
+
+	return quoteIdentifier(database) + "." + quoteIdentifier(table)
+}
+
+func resourceMysqlGrantCreate(d *schema.ResourceData, meta interface{}) error {
+	db, err := meta.(*Client).DB()
+	if err != nil {
+		return err
+	}
+
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+	if host == "" {
+		host = "localhost"
+	}
+
+	table := d.Get("table").(string)
+	if table == "" {
+		table = "*"
+	}
+
+	var privileges []string
+	for _, v := range d.Get("privileges").(*schema.Set).List() {
+		privileges = append(privileges, strings.ToUpper(v.(string)))
+	}
+
+	stmt := fmt.Sprintf("GRANT %s ON %s TO %s",
+		strings.Join(privileges, ", "),
+		grantTarget(d.Get("database").(string), table),
+		quoteAccount(user, host))
+	if d.Get("grant_option").(bool) {
+		stmt += " WITH GRANT OPTION"
+	}
+
+	log.Printf("[DEBUG] Grant create: %s", stmt)
+	if _, err := db.Exec(stmt); err != nil {
+		return fmt.Errorf("Error granting privileges: %s", err)
+	}
+
+	d.SetId(fmt.Sprintf(
+		"%s@%s:%s.%s", user, host, d.Get("database").(string), table))
+	log.Printf("[INFO] Grant ID: %s", d.Id())
+
+	d.Set("host", host)
+	d.Set("table", table)
+	return resourceMysqlGrantRead(d, meta)
+}
+
+func resourceMysqlGrantRead(d *schema.ResourceData, meta interface{}) error {
+	db, err := meta.(*Client).DB()
+	if err != nil {
+		return err
+	}
+
+	account := quoteAccount(d.Get("user").(string), d.Get("host").(string))
+	rows, err := db.Query("SHOW GRANTS FOR " + account)
+	if isError(err, 1141) {
+		// The user doesn't exist anymore, and the grant with it
+		d.SetId("")
+
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Error reading grants: %s", err)
+	}
+	defer rows.Close()
+
+	target := grantTarget(
+		d.Get("database").(string), d.Get("table").(string))
+
+	var found *parsedGrant
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return fmt.Errorf("Error reading grants: %s", err)
+		}
+
+		if g, ok := parseGrant(line); ok && g.Target == target {
+			found = g
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("Error reading grants: %s", err)
+	}
+
+	if found == nil {
+		// The privileges were all revoked
+		d.SetId("")
+
+		return nil
+	}
+
+	// Only the privileges that are still granted are kept, so that the
+	// ones revoked outside of Terraform are granted again.
+	var privileges []interface{}
+	for _, v := range d.Get("privileges").(*schema.Set).List() {
+		for _, p := range found.Privileges {
+			if strings.ToUpper(v.(string)) == p {
+				privileges = append(privileges, v)
+				break
+			}
+		}
+	}
+
+	if len(privileges) == 0 {
+		d.SetId("")
+
+		return nil
+	}
+
+	d.Set("privileges", privileges)
+	d.Set("grant_option", found.GrantOption)
+
+	return nil
+}
+
+func resourceMysqlGrantDelete(d *schema.ResourceData, meta interface{}) error {
+	db, err := meta.(*Client).DB()
+	if err != nil {
+		return err
+	}
+
+	var privileges []string
+	for _, v := range d.Get("privileges").(*schema.Set).List() {
+		privileges = append(privileges, strings.ToUpper(v.(string)))
+	}
+	if d.Get("grant_option").(bool) {
+		privileges = append(privileges, "GRANT OPTION")
+	}
+
+	stmt := fmt.Sprintf("REVOKE %s ON %s FROM %s",
+		strings.Join(privileges, ", "),
+		grantTarget(d.Get("database").(string), d.Get("table").(string)),
+		quoteAccount(d.Get("user").(string), d.Get("host").(string)))
+
+	log.Printf("[INFO] Deleting grant: %s", stmt)
+	_, err = db.Exec(stmt)
+	if isError(err, 1141, 1147) {
+		// The grant or its user was already removed
+		err = nil
+	}
+	if err != nil {
+		return fmt.Errorf("Error revoking privileges: %s", err)
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,187 @@
+package mysql
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestParseGrant(t *testing.T) {
+	cases := []struct {
+		Line   string
+		Result *parsedGrant
+	}{
+		{
+			"GRANT USAGE ON *.* TO 'app'@'%' IDENTIFIED BY PASSWORD '*2470C0C06DEE42FD1618BB99005ADCA2EC9D1E19'",
+			&parsedGrant{
+				Privileges: []string{"USAGE"},
+				Target:     "*.*",
+			},
+		},
+
+		{
+			"GRANT SELECT, INSERT, UPDATE ON `app`.* TO 'app'@'%'",
+			&parsedGrant{
+				Privileges: []string{"SELECT", "INSERT", "UPDATE"},
+				Target:     "`app`.*",
+			},
+		},
+
+		{
+			"GRANT ALL PRIVILEGES ON `app`.`users` TO 'admin'@'localhost' WITH GRANT OPTION",
+			&parsedGrant{
+				Privileges:  []string{"ALL"},
+				Target:      "`app`.`users`",
+				GrantOption: true,
+			},
+		},
+
+		{
+			"GRANT SELECT (id, name), UPDATE (name) ON `app`.`users` TO 'app'@'%'",
+			&parsedGrant{
+				Privileges: []string{"SELECT (id, name)", "UPDATE (name)"},
+				Target:     "`app`.`users`",
+			},
+		},
+
+		{
+			"GRANT PROXY ON ''@'' TO 'root'@'localhost' WITH GRANT OPTION",
+			&parsedGrant{
+				Privileges:  []string{"PROXY"},
+				Target:      "''@''",
+				GrantOption: true,
+			},
+		},
+
+		{"REVOKE SELECT ON `app`.* FROM 'app'@'%'", nil},
+	}
+
+	for i, tc := range cases {
+		actual, ok := parseGrant(tc.Line)
+		if ok != (tc.Result != nil) {
+			t.Fatalf("%d: bad: %#v", i, ok)
+		}
+		if !reflect.DeepEqual(actual, tc.Result) {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
+
+func TestGrantTarget(t *testing.T) {
+	cases := []struct {
+		Database string
+		Table    string
+		Result   string
+	}{
+		{"app", "", "`app`.*"},
+		{"app", "*", "`app`.*"},
+		{"app", "users", "`app`.`users`"},
+	}
+
+	for i, tc := range cases {
+		if actual := grantTarget(tc.Database, tc.Table); actual != tc.Result {
+			t.Fatalf("%d: bad: %s", i, actual)
+		}
+	}
+}
+
+func TestAccMysqlGrant_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMysqlGrantDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccMysqlGrantConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMysqlGrantExists("mysql_grant.foobar"),
+					resource.TestCheckResourceAttr(
+						"mysql_grant.foobar", "table", "*"),
+					resource.TestCheckResourceAttr(
+						"mysql_grant.foobar", "privileges.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckMysqlGrantDestroy(s *terraform.State) error {
+	for _, rs := range s.Resources {
+		if rs.Type != "mysql_grant" {
+			continue
+		}
+
+		// The user is destroyed with the grant, and its grants with it
+		exists, err := testAccMysqlUserExists(
+			rs.Attributes["user"], rs.Attributes["host"])
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			return fmt.Errorf("User of the grant still exists")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckMysqlGrantExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.ID == "" {
+			return fmt.Errorf("No grant ID is set")
+		}
+
+		db, err := testAccProvider.Meta().(*Client).DB()
+		if err != nil {
+			return err
+		}
+
+		rows, err := db.Query("SHOW GRANTS FOR " + quoteAccount(
+			rs.Attributes["user"], rs.Attributes["host"]))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		target := grantTarget(rs.Attributes["database"], rs.Attributes["table"])
+		for rows.Next() {
+			var line string
+			if err := rows.Scan(&line); err != nil {
+				return err
+			}
+
+			if g, ok := parseGrant(line); ok && g.Target == target {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("Grant not found")
+	}
+}
+
+const testAccMysqlGrantConfig = `
+resource "mysql_database" "foobar" {
+	name = "terraform_test"
+}
+
+resource "mysql_user" "foobar" {
+	user = "terraform_test"
+	host = "%"
+}
+
+resource "mysql_grant" "foobar" {
+	user = "${mysql_user.foobar.user}"
+	host = "${mysql_user.foobar.host}"
+	database = "${mysql_database.foobar.name}"
+	privileges = ["SELECT", "INSERT"]
+}
+`
@@ -0,0 +1,109 @@
+package mysql
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceMysqlUser() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceMysqlUserCreate,
+		Read:   resourceMysqlUserRead,
+		Delete: resourceMysqlUserDelete,
+
+		Schema: map[string]*schema.Schema{
+			"user": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"host": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"password": &schema.Schema{
+				Type:      schema.TypeString,
+				Optional:  true,
+				WriteOnly: true,
+			},
+		},
+	}
+}
+
+func resourceMysqlUserCreate(d *schema.ResourceData, meta interface{}) error {
+	db, err := meta.(*Client).DB()
+	if err != nil {
+		return err
+	}
+
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+	if host == "" {
+		host = "localhost"
+	}
+
+	stmt := "CREATE USER " + quoteAccount(user, host)
+	log.Printf("[DEBUG] User create: %s", stmt)
+	if v := d.Get("password").(string); v != "" {
+		stmt += " IDENTIFIED BY " + quoteString(v)
+	}
+
+	if _, err := db.Exec(stmt); err != nil {
+		return fmt.Errorf("Error creating user: %s", err)
+	}
+
+	d.SetId(user + "@" + host)
+	log.Printf("[INFO] User ID: %s", d.Id())
+
+	d.Set("host", host)
+	return resourceMysqlUserRead(d, meta)
+}
+
+func resourceMysqlUserRead(d *schema.ResourceData, meta interface{}) error {
+	db, err := meta.(*Client).DB()
+	if err != nil {
+		return err
+	}
+
+	var count int
+	err = db.QueryRow(
+		"SELECT COUNT(*) FROM mysql.user WHERE User = ? AND Host = ?",
+		d.Get("user").(string), d.Get("host").(string)).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("Error reading user: %s", err)
+	}
+
+	if count == 0 {
+		// The user doesn't exist anymore
+		d.SetId("")
+	}
+
+	return nil
+}
+
+func resourceMysqlUserDelete(d *schema.ResourceData, meta interface{}) error {
+	db, err := meta.(*Client).DB()
+	if err != nil {
+		return err
+	}
+
+	account := quoteAccount(d.Get("user").(string), d.Get("host").(string))
+	log.Printf("[INFO] Deleting user: %s", account)
+	_, err = db.Exec("DROP USER " + account)
+	if isError(err, 1396) {
+		// The user was already dropped
+		err = nil
+	}
+	if err != nil {
+		return fmt.Errorf("Error deleting user: %s", err)
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,97 @@
+package mysql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccMysqlUser_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMysqlUserDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccMysqlUserConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMysqlUserExists("mysql_user.foobar"),
+					resource.TestCheckResourceAttr(
+						"mysql_user.foobar", "host", "localhost"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckMysqlUserDestroy(s *terraform.State) error {
+	for _, rs := range s.Resources {
+		if rs.Type != "mysql_user" {
+			continue
+		}
+
+		exists, err := testAccMysqlUserExists(
+			rs.Attributes["user"], rs.Attributes["host"])
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			return fmt.Errorf("User still exists")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckMysqlUserExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.ID == "" {
+			return fmt.Errorf("No user ID is set")
+		}
+
+		if _, ok := rs.Attributes["password"]; ok {
+			return fmt.Errorf("Password is in the state")
+		}
+
+		exists, err := testAccMysqlUserExists(
+			rs.Attributes["user"], rs.Attributes["host"])
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			return fmt.Errorf("User not found")
+		}
+
+		return nil
+	}
+}
+
+func testAccMysqlUserExists(user, host string) (bool, error) {
+	db, err := testAccProvider.Meta().(*Client).DB()
+	if err != nil {
+		return false, err
+	}
+
+	var count int
+	err = db.QueryRow(
+		"SELECT COUNT(*) FROM mysql.user WHERE User = ? AND Host = ?",
+		user, host).Scan(&count)
+
+	return count > 0, err
+}
+
+const testAccMysqlUserConfig = `
+resource "mysql_user" "foobar" {
+	user = "terraform_test"
+	password = "foobarbaz"
+}
+`
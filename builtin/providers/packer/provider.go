@@ -0,0 +1,16 @@
+package packer
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// Provider returns a terraform.ResourceProvider.
+func Provider() *schema.Provider {
+	// The provider has no configuration: manifests are read from the
+	// files and URLs given to each resource.
+	return &schema.Provider{
+		ResourcesMap: map[string]*schema.Resource{
+			"packer_artifact": resourcePackerArtifact(),
+		},
+	}
+}
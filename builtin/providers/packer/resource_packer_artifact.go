@@ -0,0 +1,178 @@
+package packer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// manifest is the build manifest written by the Packer "manifest"
+// post-processor.
+type manifest struct {
+	Builds      []*manifestBuild `json:"builds"`
+	LastRunUUID string           `json:"last_run_uuid"`
+}
+
+type manifestBuild struct {
+	Name        string `json:"name"`
+	BuilderType string `json:"builder_type"`
+	BuildTime   int64  `json:"build_time"`
+	ArtifactID  string `json:"artifact_id"`
+	RunUUID     string `json:"packer_run_uuid"`
+}
+
+func resourcePackerArtifact() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePackerArtifactCreate,
+		Read:   resourcePackerArtifactRead,
+		Delete: resourcePackerArtifactDelete,
+
+		Schema: map[string]*schema.Schema{
+			"manifest": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"build_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"artifact_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"region": &schema.Schema{
+				Type:     schema.TypeMap,
+				Computed: true,
+			},
+
+			"build_time": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"run_uuid": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourcePackerArtifactCreate(d *schema.ResourceData, meta interface{}) error {
+	d.SetId(d.Get("manifest").(string))
+	return resourcePackerArtifactRead(d, meta)
+}
+
+func resourcePackerArtifactRead(d *schema.ResourceData, meta interface{}) error {
+	location := d.Get("manifest").(string)
+	name := d.Get("build_name").(string)
+
+	log.Printf("[DEBUG] Reading Packer manifest: %s", location)
+	m, err := readManifest(location)
+	if err != nil {
+		return fmt.Errorf("Error reading Packer manifest %s: %s", location, err)
+	}
+
+	b := m.latest(name)
+	if b == nil {
+		if name != "" {
+			return fmt.Errorf(
+				"Packer manifest %s has no builds named %q", location, name)
+		}
+
+		return fmt.Errorf("Packer manifest %s has no builds", location)
+	}
+
+	log.Printf("[INFO] Latest Packer artifact: %s", b.ArtifactID)
+	d.Set("artifact_id", b.ArtifactID)
+	d.Set("region", artifactRegions(b.ArtifactID))
+	d.Set("build_time", int(b.BuildTime))
+	d.Set("run_uuid", b.RunUUID)
+
+	return nil
+}
+
+func resourcePackerArtifactDelete(d *schema.ResourceData, meta interface{}) error {
+	// Nothing is created, so there's nothing to delete
+	d.SetId("")
+	return nil
+}
+
+// readManifest reads a manifest from a file, or from an HTTP or HTTPS URL.
+func readManifest(location string) (*manifest, error) {
+	var r io.ReadCloser
+	if strings.HasPrefix(location, "http://") ||
+		strings.HasPrefix(location, "https://") {
+		resp, err := http.Get(location)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != 200 {
+			resp.Body.Close()
+			return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+		}
+
+		r = resp.Body
+	} else {
+		f, err := os.Open(location)
+		if err != nil {
+			return nil, err
+		}
+
+		r = f
+	}
+	defer r.Close()
+
+	var result manifest
+	if err := json.NewDecoder(r).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// latest returns the most recent build with the given name, or of any
+// name if it is empty. Builds of the same time are ordered as they are in
+// the manifest, where Packer appends new builds.
+func (m *manifest) latest(name string) *manifestBuild {
+	var result *manifestBuild
+	for _, b := range m.Builds {
+		if name != "" && b.Name != name {
+			continue
+		}
+
+		if result == nil || b.BuildTime >= result.BuildTime {
+			result = b
+		}
+	}
+
+	return result
+}
+
+// artifactRegions splits an artifact ID of the form used by the Amazon
+// builders, "us-east-1:ami-1234,us-west-2:ami-5678", into the IDs by
+// region. Artifact IDs without a colon give an empty map.
+func artifactRegions(id string) map[string]interface{} {
+	result := make(map[string]interface{})
+	for _, part := range strings.Split(id, ",") {
+		idx := strings.Index(part, ":")
+		if idx <= 0 {
+			continue
+		}
+
+		result[part[:idx]] = part[idx+1:]
+	}
+
+	return result
+}
@@ -0,0 +1,226 @@
+package packer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccPackerArtifact(t *testing.T) {
+	path := testManifestFile(t, testManifest)
+	defer os.Remove(path)
+
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: fmt.Sprintf(testAccPackerArtifactConfig, path),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"packer_artifact.web", "region.us-east-1", "ami-3333"),
+					resource.TestCheckResourceAttr(
+						"packer_artifact.web", "run_uuid", "run-2"),
+				),
+			},
+		},
+	})
+}
+
+func TestResourcePackerArtifact(t *testing.T) {
+	path := testManifestFile(t, testManifest)
+	defer os.Remove(path)
+
+	s := testPackerArtifactApply(t, map[string]interface{}{
+		"manifest": path,
+	})
+
+	expected := map[string]string{
+		"artifact_id":      "us-east-1:ami-3333,us-west-2:ami-4444",
+		"region.us-east-1": "ami-3333",
+		"region.us-west-2": "ami-4444",
+		"build_time":       "200",
+		"run_uuid":         "run-2",
+	}
+	for k, v := range expected {
+		if s.Attributes[k] != v {
+			t.Fatalf("bad %s: %#v", k, s.Attributes)
+		}
+	}
+
+	// A newer build is picked up by a refresh
+	err := ioutil.WriteFile(path, []byte(`{
+		"builds": [{
+			"name": "web",
+			"build_time": 300,
+			"artifact_id": "us-east-1:ami-5555",
+			"packer_run_uuid": "run-3"
+		}]
+	}`), 0644)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	s, err = resourcePackerArtifact().Refresh(s, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if s.Attributes["region.us-east-1"] != "ami-5555" {
+		t.Fatalf("bad: %#v", s.Attributes)
+	}
+}
+
+func TestResourcePackerArtifact_buildName(t *testing.T) {
+	path := testManifestFile(t, testManifest)
+	defer os.Remove(path)
+
+	s := testPackerArtifactApply(t, map[string]interface{}{
+		"manifest":   path,
+		"build_name": "db",
+	})
+	if s.Attributes["artifact_id"] != "us-east-1:ami-2222" {
+		t.Fatalf("bad: %#v", s.Attributes)
+	}
+}
+
+func TestResourcePackerArtifact_url(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(testManifest))
+		}))
+	defer ts.Close()
+
+	s := testPackerArtifactApply(t, map[string]interface{}{
+		"manifest": ts.URL + "/manifest.json",
+	})
+	if s.Attributes["run_uuid"] != "run-2" {
+		t.Fatalf("bad: %#v", s.Attributes)
+	}
+}
+
+func TestResourcePackerArtifact_noBuilds(t *testing.T) {
+	path := testManifestFile(t, testManifest)
+	defer os.Remove(path)
+
+	r := resourcePackerArtifact()
+	c := testResourceConfig(t, map[string]interface{}{
+		"manifest":   path,
+		"build_name": "nope",
+	})
+	d, err := r.Diff(nil, c)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := r.Apply(nil, d, nil); err == nil {
+		t.Fatal("should error")
+	}
+}
+
+func TestArtifactRegions(t *testing.T) {
+	cases := []struct {
+		ID     string
+		Result map[string]interface{}
+	}{
+		{
+			"us-east-1:ami-1,eu-west-1:ami-2",
+			map[string]interface{}{
+				"us-east-1": "ami-1",
+				"eu-west-1": "ami-2",
+			},
+		},
+
+		// Builders other than Amazon's
+		{
+			"image-1234",
+			map[string]interface{}{},
+		},
+	}
+
+	for i, tc := range cases {
+		actual := artifactRegions(tc.ID)
+		if !reflect.DeepEqual(actual, tc.Result) {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
+
+func testPackerArtifactApply(
+	t *testing.T, raw map[string]interface{}) *terraform.ResourceState {
+	r := resourcePackerArtifact()
+	d, err := r.Diff(nil, testResourceConfig(t, raw))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	s, err := r.Apply(nil, d, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return s
+}
+
+func testResourceConfig(
+	t *testing.T, raw map[string]interface{}) *terraform.ResourceConfig {
+	rc, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return terraform.NewResourceConfig(rc)
+}
+
+func testManifestFile(t *testing.T, contents string) string {
+	f, err := ioutil.TempFile("", "tf")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(contents); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return f.Name()
+}
+
+const testManifest = `{
+	"builds": [
+		{
+			"name": "web",
+			"builder_type": "amazon-ebs",
+			"build_time": 100,
+			"artifact_id": "us-east-1:ami-1111",
+			"packer_run_uuid": "run-1"
+		},
+		{
+			"name": "db",
+			"builder_type": "amazon-ebs",
+			"build_time": 150,
+			"artifact_id": "us-east-1:ami-2222",
+			"packer_run_uuid": "run-1"
+		},
+		{
+			"name": "web",
+			"builder_type": "amazon-ebs",
+			"build_time": 200,
+			"artifact_id": "us-east-1:ami-3333,us-west-2:ami-4444",
+			"packer_run_uuid": "run-2"
+		}
+	],
+	"last_run_uuid": "run-2"
+}`
+
+const testAccPackerArtifactConfig = `
+resource "packer_artifact" "web" {
+	manifest = "%s"
+	build_name = "web"
+}
+`
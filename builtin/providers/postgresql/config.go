@@ -0,0 +1,134 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+
+	_ "github.com/lib/pq"
+)
+
+type Config struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	Database string `mapstructure:"database"`
+	SSLMode  string `mapstructure:"ssl_mode"`
+}
+
+// Client connects to the databases of a PostgreSQL server.
+//
+// The server isn't connected to until a resource needs it, so that the
+// server can be created in the same configuration, with the provider
+// configured from its attributes.
+type Client struct {
+	config Config
+
+	dbs  map[string]*sql.DB
+	lock sync.Mutex
+}
+
+// Client() returns a new client for the configured server.
+func (c *Config) Client() (*Client, error) {
+	if c.Port == 0 {
+		c.Port = 5432
+	}
+	if c.Database == "" {
+		c.Database = "postgres"
+	}
+	if c.SSLMode == "" {
+		c.SSLMode = "require"
+	}
+
+	log.Printf("[INFO] PostgreSQL Client configured for: %s@%s:%d",
+		c.Username, c.Host, c.Port)
+
+	return &Client{
+		config: *c,
+		dbs:    make(map[string]*sql.DB),
+	}, nil
+}
+
+// DB returns the connection pool for the given database of the server,
+// or the configured database if it's empty. Grants on the objects in a
+// database have to be made while connected to it.
+func (c *Client) DB(database string) (*sql.DB, error) {
+	if database == "" {
+		database = c.config.Database
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if db, ok := c.dbs[database]; ok {
+		return db, nil
+	}
+
+	// These are checked now rather than when the provider is configured,
+	// since they may come from a server that isn't created yet then.
+	if c.config.Host == "" {
+		return nil, fmt.Errorf(
+			"host must be set, or PGHOST in the environment")
+	}
+	if c.config.Username == "" {
+		return nil, fmt.Errorf(
+			"username must be set, or PGUSER in the environment")
+	}
+
+	db, err := sql.Open("postgres", c.config.connString(database))
+	if err != nil {
+		return nil, err
+	}
+
+	c.dbs[database] = db
+	return db, nil
+}
+
+// connString returns the connection string for the given database.
+func (c *Config) connString(database string) string {
+	params := []struct {
+		Key   string
+		Value string
+	}{
+		{"host", c.Host},
+		{"port", strconv.Itoa(c.Port)},
+		{"user", c.Username},
+		{"password", c.Password},
+		{"dbname", database},
+		{"sslmode", c.SSLMode},
+	}
+
+	parts := make([]string, 0, len(params))
+	for _, p := range params {
+		if p.Value == "" {
+			continue
+		}
+
+		v := strings.Replace(p.Value, `\`, `\\`, -1)
+		v = strings.Replace(v, `'`, `\'`, -1)
+		parts = append(parts, fmt.Sprintf("%s='%s'", p.Key, v))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// quoteIdentifier quotes the name of a database object, such as a role,
+// for use in a statement.
+func quoteIdentifier(name string) string {
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
+
+// quoteLiteral quotes a string for use in a statement where parameters
+// can't be used, such as the password of CREATE ROLE.
+func quoteLiteral(v string) string {
+	v = strings.Replace(v, `'`, `''`, -1)
+	if strings.Contains(v, `\`) {
+		return `E'` + strings.Replace(v, `\`, `\\`, -1) + `'`
+	}
+
+	return `'` + v + `'`
+}
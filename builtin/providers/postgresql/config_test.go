@@ -0,0 +1,88 @@
+package postgresql
+
+import (
+	"os"
+	"testing"
+)
+
+func TestConfigClient(t *testing.T) {
+	for _, k := range []string{"PGHOST", "PGPORT", "PGUSER", "PGSSLMODE"} {
+		defer os.Setenv(k, os.Getenv(k))
+		os.Setenv(k, "")
+	}
+
+	c := &Config{Host: "db.example.com", Username: "admin"}
+	if _, err := c.Client(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if c.Port != 5432 || c.Database != "postgres" || c.SSLMode != "require" {
+		t.Fatalf("bad: %#v", c)
+	}
+
+	// The environment is read when the provider is configured
+	os.Setenv("PGHOST", "db.example.com")
+	os.Setenv("PGPORT", "5433")
+	os.Setenv("PGUSER", "admin")
+	p := Provider()
+	if err := p.Configure(nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if config := p.Meta().(*Client).config; config.Host != "db.example.com" ||
+		config.Port != 5433 || config.Username != "admin" {
+		t.Fatalf("bad: %#v", config)
+	}
+
+	// The host is only needed to connect
+	c = &Config{Username: "admin"}
+	client, err := c.Client()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := client.DB(""); err == nil {
+		t.Fatal("should error without a host")
+	}
+}
+
+func TestConfigConnString(t *testing.T) {
+	c := &Config{
+		Host:     "db.example.com",
+		Port:     5432,
+		Username: "admin",
+		Password: `it's a \secret`,
+		SSLMode:  "disable",
+	}
+
+	expected := `host='db.example.com' port='5432' user='admin' ` +
+		`password='it\'s a \\secret' dbname='app' sslmode='disable'`
+	if actual := c.connString("app"); actual != expected {
+		t.Fatalf("bad: %s", actual)
+	}
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	cases := map[string]string{
+		"app":      `"app"`,
+		`we"ird`:   `"we""ird"`,
+		"My Table": `"My Table"`,
+	}
+
+	for input, expected := range cases {
+		if actual := quoteIdentifier(input); actual != expected {
+			t.Fatalf("%s: bad: %s", input, actual)
+		}
+	}
+}
+
+func TestQuoteLiteral(t *testing.T) {
+	cases := map[string]string{
+		"secret":  `'secret'`,
+		"it's":    `'it''s'`,
+		`back\up`: `E'back\\up'`,
+	}
+
+	for input, expected := range cases {
+		if actual := quoteLiteral(input); actual != expected {
+			t.Fatalf("%s: bad: %s", input, actual)
+		}
+	}
+}
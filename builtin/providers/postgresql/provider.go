@@ -0,0 +1,70 @@
+package postgresql
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mitchellh/mapstructure"
+)
+
+// Provider returns a terraform.ResourceProvider.
+func Provider() *schema.Provider {
+	return &schema.Provider{
+		// All of these can also be set with the environment variables
+		// that psql uses.
+		Schema: map[string]*schema.Schema{
+			"host": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				EnvVars:  []string{"PGHOST"},
+			},
+
+			"port": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				EnvVars:  []string{"PGPORT"},
+			},
+
+			"username": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				EnvVars:  []string{"PGUSER"},
+			},
+
+			"password": &schema.Schema{
+				Type:      schema.TypeString,
+				Optional:  true,
+				WriteOnly: true,
+				EnvVars:   []string{"PGPASSWORD"},
+			},
+
+			"database": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				EnvVars:  []string{"PGDATABASE"},
+			},
+
+			"ssl_mode": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				EnvVars:  []string{"PGSSLMODE"},
+			},
+		},
+
+		ResourcesMap: map[string]*schema.Resource{
+			"postgresql_database": resourcePostgresqlDatabase(),
+			"postgresql_grant":    resourcePostgresqlGrant(),
+			"postgresql_role":     resourcePostgresqlRole(),
+		},
+
+		ConfigureFunc: providerConfigure,
+	}
+}
+
+func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+	var config Config
+	configRaw := d.Get("").(map[string]interface{})
+	if err := mapstructure.Decode(configRaw, &config); err != nil {
+		return nil, err
+	}
+
+	return config.Client()
+}
@@ -0,0 +1,143 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourcePostgresqlDatabase() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePostgresqlDatabaseCreate,
+		Read:   resourcePostgresqlDatabaseRead,
+		Update: resourcePostgresqlDatabaseUpdate,
+		Delete: resourcePostgresqlDatabaseDelete,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"owner": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"template": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"encoding": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+func resourcePostgresqlDatabaseCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	db, err := client.DB("")
+	if err != nil {
+		return err
+	}
+
+	name := d.Get("name").(string)
+	stmt := []string{"CREATE DATABASE", quoteIdentifier(name)}
+	if v := d.Get("owner").(string); v != "" {
+		stmt = append(stmt, "OWNER", quoteIdentifier(v))
+	}
+	if v := d.Get("template").(string); v != "" {
+		stmt = append(stmt, "TEMPLATE", quoteIdentifier(v))
+	}
+	if v := d.Get("encoding").(string); v != "" {
+		stmt = append(stmt, "ENCODING", quoteLiteral(v))
+	}
+
+	log.Printf("[DEBUG] Database create: %s", strings.Join(stmt, " "))
+	if _, err := db.Exec(strings.Join(stmt, " ")); err != nil {
+		return fmt.Errorf("Error creating database: %s", err)
+	}
+
+	d.SetId(name)
+	log.Printf("[INFO] Database ID: %s", d.Id())
+
+	return resourcePostgresqlDatabaseRead(d, meta)
+}
+
+func resourcePostgresqlDatabaseRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	db, err := client.DB("")
+	if err != nil {
+		return err
+	}
+
+	var owner, encoding string
+	err = db.QueryRow(
+		"SELECT pg_catalog.pg_get_userbyid(d.datdba), "+
+			"pg_catalog.pg_encoding_to_char(d.encoding) "+
+			"FROM pg_catalog.pg_database d WHERE d.datname = $1",
+		d.Id()).Scan(&owner, &encoding)
+	if err == sql.ErrNoRows {
+		// The database doesn't exist anymore
+		d.SetId("")
+
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Error reading database: %s", err)
+	}
+
+	d.Set("name", d.Id())
+	d.Set("owner", owner)
+	d.Set("encoding", encoding)
+
+	return nil
+}
+
+func resourcePostgresqlDatabaseUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	db, err := client.DB("")
+	if err != nil {
+		return err
+	}
+
+	if d.HasChange("owner") {
+		_, err := db.Exec(fmt.Sprintf(
+			"ALTER DATABASE %s OWNER TO %s",
+			quoteIdentifier(d.Id()),
+			quoteIdentifier(d.Get("owner").(string))))
+		if err != nil {
+			return fmt.Errorf("Error changing database owner: %s", err)
+		}
+	}
+
+	return resourcePostgresqlDatabaseRead(d, meta)
+}
+
+func resourcePostgresqlDatabaseDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	db, err := client.DB("")
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[INFO] Deleting database: %s", d.Id())
+	_, err = db.Exec("DROP DATABASE IF EXISTS " + quoteIdentifier(d.Id()))
+	if err != nil {
+		return fmt.Errorf("Error deleting database: %s", err)
+	}
+
+	d.SetId("")
+	return nil
+}
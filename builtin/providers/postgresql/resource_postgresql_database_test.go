@@ -0,0 +1,106 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccPostgresqlDatabase_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlDatabaseDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccPostgresqlDatabaseConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlDatabaseExists("postgresql_database.foobar"),
+					resource.TestCheckResourceAttr(
+						"postgresql_database.foobar", "name", "terraform_test"),
+					resource.TestCheckResourceAttr(
+						"postgresql_database.foobar", "owner", "terraform_test_owner"),
+					resource.TestCheckResourceAttr(
+						"postgresql_database.foobar", "encoding", "UTF8"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckPostgresqlDatabaseDestroy(s *terraform.State) error {
+	for _, rs := range s.Resources {
+		if rs.Type != "postgresql_database" {
+			continue
+		}
+
+		exists, err := testAccPostgresqlDatabaseExists(rs.ID)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			return fmt.Errorf("Database still exists")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckPostgresqlDatabaseExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.ID == "" {
+			return fmt.Errorf("No database ID is set")
+		}
+
+		exists, err := testAccPostgresqlDatabaseExists(rs.ID)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			return fmt.Errorf("Database not found")
+		}
+
+		return nil
+	}
+}
+
+func testAccPostgresqlDatabaseExists(name string) (bool, error) {
+	client := testAccProvider.Meta().(*Client)
+	db, err := client.DB("")
+	if err != nil {
+		return false, err
+	}
+
+	var found string
+	err = db.QueryRow(
+		"SELECT datname FROM pg_catalog.pg_database WHERE datname = $1",
+		name).Scan(&found)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+const testAccPostgresqlDatabaseConfig = `
+resource "postgresql_role" "owner" {
+	name = "terraform_test_owner"
+}
+
+resource "postgresql_database" "foobar" {
+	name = "terraform_test"
+	owner = "${postgresql_role.owner.name}"
+	template = "template0"
+	encoding = "UTF8"
+}
+`
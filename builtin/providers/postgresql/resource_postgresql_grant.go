@@ -0,0 +1,323 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/lib/pq"
+)
+
+// grantPrivileges are the privileges that can be granted on each type of
+// object. "ALL" can be given for all of them.
+var grantPrivileges = map[string][]string{
+	"database": []string{"CREATE", "CONNECT", "TEMPORARY"},
+	"schema":   []string{"CREATE", "USAGE"},
+	"table": []string{
+		"SELECT", "INSERT", "UPDATE", "DELETE",
+		"TRUNCATE", "REFERENCES", "TRIGGER",
+	},
+	"sequence": []string{"USAGE", "SELECT", "UPDATE"},
+}
+
+// grantChecks are the queries that check whether a role ($1) has a
+// privilege ($3) on the objects of a type, given the database or schema
+// ($2). Grants on tables and sequences are on all of them in the schema.
+var grantChecks = map[string]string{
+	"database": "SELECT pg_catalog.has_database_privilege($1, $2, $3)",
+	"schema":   "SELECT pg_catalog.has_schema_privilege($1, $2, $3)",
+	"table": "SELECT NOT EXISTS (SELECT 1 FROM pg_catalog.pg_class c " +
+		"JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace " +
+		"WHERE c.relkind IN ('r', 'v') AND n.nspname = $2 " +
+		"AND NOT pg_catalog.has_table_privilege($1, c.oid, $3))",
+	"sequence": "SELECT NOT EXISTS (SELECT 1 FROM pg_catalog.pg_class c " +
+		"JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace " +
+		"WHERE c.relkind = 'S' AND n.nspname = $2 " +
+		"AND NOT pg_catalog.has_sequence_privilege($1, c.oid, $3))",
+}
+
+func resourcePostgresqlGrant() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePostgresqlGrantCreate,
+		Read:   resourcePostgresqlGrantRead,
+		Delete: resourcePostgresqlGrantDelete,
+
+		Schema: map[string]*schema.Schema{
+			"role": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"database": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"schema": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"object_type": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"privileges": &schema.Schema{
+				Type:     schema.TypeSet,
+				Required: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set: func(v interface{}) int {
+					return hashcode.String(strings.ToUpper(v.(string)))
+				},
+			},
+		},
+	}
+}
+
+// grant is a grant of privileges to a role on the objects of a type.
+type grant struct {
+	Role       string
+	Database   string
+	Schema     string
+	ObjectType string
+	Privileges []string
+}
+
+func expandGrant(d *schema.ResourceData) (*grant, error) {
+	return newGrant(
+		d.Get("role").(string),
+		d.Get("database").(string),
+		d.Get("schema").(string),
+		d.Get("object_type").(string),
+		d.Get("privileges").(*schema.Set).List())
+}
+
+// newGrant returns a grant after checking that the privileges can be
+// granted on the type of object. Tables and sequences are in the "public"
+// schema unless another one is given.
+func newGrant(
+	role, database, schema, objectType string,
+	privileges []interface{}) (*grant, error) {
+	g := &grant{
+		Role:       role,
+		Database:   database,
+		Schema:     schema,
+		ObjectType: objectType,
+	}
+
+	valid, ok := grantPrivileges[g.ObjectType]
+	if !ok {
+		return nil, fmt.Errorf(
+			"object_type must be database, schema, table or sequence, got: %s",
+			g.ObjectType)
+	}
+
+	if g.ObjectType != "database" && g.Schema == "" {
+		g.Schema = "public"
+	}
+
+	for _, v := range privileges {
+		p := strings.ToUpper(v.(string))
+		if p != "ALL" && !containsString(valid, p) {
+			return nil, fmt.Errorf(
+				"%s can't be granted on a %s, only: ALL, %s",
+				p, g.ObjectType, strings.Join(valid, ", "))
+		}
+
+		g.Privileges = append(g.Privileges, p)
+	}
+
+	return g, nil
+}
+
+// target returns the objects of the grant in a GRANT or REVOKE statement.
+func (g *grant) target() string {
+	switch g.ObjectType {
+	case "database":
+		return "DATABASE " + quoteIdentifier(g.Database)
+	case "schema":
+		return "SCHEMA " + quoteIdentifier(g.Schema)
+	case "table":
+		return "ALL TABLES IN SCHEMA " + quoteIdentifier(g.Schema)
+	case "sequence":
+		return "ALL SEQUENCES IN SCHEMA " + quoteIdentifier(g.Schema)
+	}
+
+	panic("unknown object type: " + g.ObjectType)
+}
+
+// GrantStatement returns the statement that grants the privileges.
+func (g *grant) GrantStatement() string {
+	return fmt.Sprintf("GRANT %s ON %s TO %s",
+		strings.Join(g.Privileges, ", "), g.target(), quoteIdentifier(g.Role))
+}
+
+// RevokeStatement returns the statement that revokes the privileges.
+func (g *grant) RevokeStatement() string {
+	return fmt.Sprintf("REVOKE %s ON %s FROM %s",
+		strings.Join(g.Privileges, ", "), g.target(), quoteIdentifier(g.Role))
+}
+
+// db returns the connection that the grant is made with. Grants on the
+// objects in a database are made while connected to the database.
+func (g *grant) db(client *Client) (*sql.DB, error) {
+	if g.ObjectType == "database" {
+		return client.DB("")
+	}
+
+	return client.DB(g.Database)
+}
+
+func resourcePostgresqlGrantCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	g, err := expandGrant(d)
+	if err != nil {
+		return err
+	}
+
+	db, err := g.db(client)
+	if err != nil {
+		return err
+	}
+
+	stmt := g.GrantStatement()
+	log.Printf("[DEBUG] Grant create: %s", stmt)
+	if _, err := db.Exec(stmt); err != nil {
+		return fmt.Errorf("Error granting privileges: %s", err)
+	}
+
+	d.SetId(strings.Join(
+		[]string{g.Role, g.Database, g.Schema, g.ObjectType}, ":"))
+	log.Printf("[INFO] Grant ID: %s", d.Id())
+
+	return resourcePostgresqlGrantRead(d, meta)
+}
+
+func resourcePostgresqlGrantRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	g, err := expandGrant(d)
+	if err != nil {
+		return err
+	}
+
+	db, err := g.db(client)
+	if err != nil {
+		return err
+	}
+
+	object := g.Schema
+	if g.ObjectType == "database" {
+		object = g.Database
+	}
+
+	// Only the privileges that are still all granted are kept, so that
+	// the ones revoked outside of Terraform are granted again.
+	var privileges []interface{}
+	for _, p := range g.Privileges {
+		check := []string{p}
+		if p == "ALL" {
+			check = grantPrivileges[g.ObjectType]
+		}
+
+		granted := true
+		for _, c := range check {
+			err := db.QueryRow(
+				grantChecks[g.ObjectType], g.Role, object, c).Scan(&granted)
+			if isNotExist(err) {
+				// The role or the object doesn't exist anymore
+				d.SetId("")
+
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("Error reading grant: %s", err)
+			}
+
+			if !granted {
+				break
+			}
+		}
+
+		if granted {
+			privileges = append(privileges, p)
+		}
+	}
+
+	if len(privileges) == 0 {
+		// The privileges were all revoked
+		d.SetId("")
+
+		return nil
+	}
+
+	d.Set("schema", g.Schema)
+	d.Set("privileges", privileges)
+
+	return nil
+}
+
+func resourcePostgresqlGrantDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	g, err := expandGrant(d)
+	if err != nil {
+		return err
+	}
+
+	db, err := g.db(client)
+	if err != nil {
+		return err
+	}
+
+	stmt := g.RevokeStatement()
+	log.Printf("[INFO] Deleting grant: %s", stmt)
+	_, err = db.Exec(stmt)
+	if isNotExist(err) {
+		// The role or the object is already gone, and the grant with it
+		err = nil
+	}
+	if err != nil {
+		return fmt.Errorf("Error revoking privileges: %s", err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// isNotExist returns true if the error is about a role, database or
+// schema that doesn't exist.
+func isNotExist(err error) bool {
+	e, ok := err.(*pq.Error)
+	if !ok {
+		return false
+	}
+
+	switch e.Code {
+	case "42704", "3D000", "3F000":
+		return true
+	}
+
+	return false
+}
+
+func containsString(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,236 @@
+package postgresql
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestGrantStatement(t *testing.T) {
+	cases := []struct {
+		Grant  grant
+		Grant_ string
+		Revoke string
+	}{
+		{
+			grant{
+				Role:       "app",
+				Database:   "app",
+				ObjectType: "database",
+				Privileges: []string{"CONNECT", "TEMPORARY"},
+			},
+			`GRANT CONNECT, TEMPORARY ON DATABASE "app" TO "app"`,
+			`REVOKE CONNECT, TEMPORARY ON DATABASE "app" FROM "app"`,
+		},
+
+		{
+			grant{
+				Role:       "app",
+				Database:   "app",
+				Schema:     "public",
+				ObjectType: "table",
+				Privileges: []string{"SELECT"},
+			},
+			`GRANT SELECT ON ALL TABLES IN SCHEMA "public" TO "app"`,
+			`REVOKE SELECT ON ALL TABLES IN SCHEMA "public" FROM "app"`,
+		},
+
+		{
+			grant{
+				Role:       "app",
+				Database:   "app",
+				Schema:     "audit",
+				ObjectType: "sequence",
+				Privileges: []string{"ALL"},
+			},
+			`GRANT ALL ON ALL SEQUENCES IN SCHEMA "audit" TO "app"`,
+			`REVOKE ALL ON ALL SEQUENCES IN SCHEMA "audit" FROM "app"`,
+		},
+	}
+
+	for i, tc := range cases {
+		if actual := tc.Grant.GrantStatement(); actual != tc.Grant_ {
+			t.Fatalf("%d: bad: %s", i, actual)
+		}
+		if actual := tc.Grant.RevokeStatement(); actual != tc.Revoke {
+			t.Fatalf("%d: bad: %s", i, actual)
+		}
+	}
+}
+
+func TestNewGrant(t *testing.T) {
+	cases := []struct {
+		Schema     string
+		ObjectType string
+		Privileges []interface{}
+		Result     *grant
+		Err        bool
+	}{
+		{
+			"",
+			"table",
+			[]interface{}{"select", "INSERT"},
+			&grant{
+				Role:       "app",
+				Database:   "app",
+				Schema:     "public",
+				ObjectType: "table",
+				Privileges: []string{"SELECT", "INSERT"},
+			},
+			false,
+		},
+
+		{
+			"",
+			"database",
+			[]interface{}{"all"},
+			&grant{
+				Role:       "app",
+				Database:   "app",
+				ObjectType: "database",
+				Privileges: []string{"ALL"},
+			},
+			false,
+		},
+
+		{"", "database", []interface{}{"SELECT"}, nil, true},
+		{"", "function", []interface{}{"EXECUTE"}, nil, true},
+	}
+
+	for i, tc := range cases {
+		actual, err := newGrant(
+			"app", "app", tc.Schema, tc.ObjectType, tc.Privileges)
+		if (err != nil) != tc.Err {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+		if !reflect.DeepEqual(actual, tc.Result) {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
+
+func TestAccPostgresqlGrant_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlGrantDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccPostgresqlGrantConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlGrantExists("postgresql_grant.connect"),
+					testAccCheckPostgresqlGrantExists("postgresql_grant.tables"),
+					resource.TestCheckResourceAttr(
+						"postgresql_grant.tables", "schema", "public"),
+					resource.TestCheckResourceAttr(
+						"postgresql_grant.tables", "privileges.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckPostgresqlGrantDestroy(s *terraform.State) error {
+	for _, rs := range s.Resources {
+		if rs.Type != "postgresql_grant" {
+			continue
+		}
+
+		// The role and the database are destroyed with the grants, which
+		// can't be checked without them
+		exists, err := testAccPostgresqlRoleExists(rs.Attributes["role"])
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			return fmt.Errorf("Role of the grant still exists")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckPostgresqlGrantExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.ID == "" {
+			return fmt.Errorf("No grant ID is set")
+		}
+
+		object := rs.Attributes["schema"]
+		database := rs.Attributes["database"]
+		if rs.Attributes["object_type"] == "database" {
+			object = database
+			database = ""
+		}
+
+		client := testAccProvider.Meta().(*Client)
+		db, err := client.DB(database)
+		if err != nil {
+			return err
+		}
+
+		objectType := rs.Attributes["object_type"]
+		count, err := strconv.Atoi(rs.Attributes["privileges.#"])
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < count; i++ {
+			check := []string{rs.Attributes[fmt.Sprintf("privileges.%d", i)]}
+			if check[0] == "ALL" {
+				check = grantPrivileges[objectType]
+			}
+
+			for _, p := range check {
+				var granted bool
+				err := db.QueryRow(
+					grantChecks[objectType],
+					rs.Attributes["role"], object, p).Scan(&granted)
+				if err != nil {
+					return err
+				}
+
+				if !granted {
+					return fmt.Errorf("%s isn't granted", p)
+				}
+			}
+		}
+
+		return nil
+	}
+}
+
+const testAccPostgresqlGrantConfig = `
+resource "postgresql_role" "foobar" {
+	name = "terraform_test"
+	login = true
+}
+
+resource "postgresql_database" "foobar" {
+	name = "terraform_test"
+}
+
+resource "postgresql_grant" "connect" {
+	role = "${postgresql_role.foobar.name}"
+	database = "${postgresql_database.foobar.name}"
+	object_type = "database"
+	privileges = ["ALL"]
+}
+
+resource "postgresql_grant" "tables" {
+	role = "${postgresql_role.foobar.name}"
+	database = "${postgresql_database.foobar.name}"
+	object_type = "table"
+	privileges = ["SELECT", "INSERT"]
+}
+`
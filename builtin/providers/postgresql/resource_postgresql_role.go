@@ -0,0 +1,169 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourcePostgresqlRole() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePostgresqlRoleCreate,
+		Read:   resourcePostgresqlRoleRead,
+		Update: resourcePostgresqlRoleUpdate,
+		Delete: resourcePostgresqlRoleDelete,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"password": &schema.Schema{
+				Type:      schema.TypeString,
+				Optional:  true,
+				WriteOnly: true,
+			},
+
+			"login": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+
+			"superuser": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+
+			"create_database": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+
+			"create_role": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+		},
+	}
+}
+
+// roleOptions are the options of CREATE ROLE and ALTER ROLE for the
+// boolean attributes of a role.
+var roleOptions = []struct {
+	Key string
+	On  string
+	Off string
+}{
+	{"login", "LOGIN", "NOLOGIN"},
+	{"superuser", "SUPERUSER", "NOSUPERUSER"},
+	{"create_database", "CREATEDB", "NOCREATEDB"},
+	{"create_role", "CREATEROLE", "NOCREATEROLE"},
+}
+
+// roleStatement returns the options of a role for CREATE ROLE or ALTER
+// ROLE.
+func roleStatement(d *schema.ResourceData) string {
+	var result string
+	for _, o := range roleOptions {
+		if d.Get(o.Key).(bool) {
+			result += " " + o.On
+		} else {
+			result += " " + o.Off
+		}
+	}
+
+	return result
+}
+
+func resourcePostgresqlRoleCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	db, err := client.DB("")
+	if err != nil {
+		return err
+	}
+
+	name := d.Get("name").(string)
+	stmt := "CREATE ROLE " + quoteIdentifier(name) + " WITH" + roleStatement(d)
+
+	log.Printf("[DEBUG] Role create: %s", stmt)
+	if v := d.Get("password").(string); v != "" {
+		stmt += " PASSWORD " + quoteLiteral(v)
+	}
+
+	if _, err := db.Exec(stmt); err != nil {
+		return fmt.Errorf("Error creating role: %s", err)
+	}
+
+	d.SetId(name)
+	log.Printf("[INFO] Role ID: %s", d.Id())
+
+	return resourcePostgresqlRoleRead(d, meta)
+}
+
+func resourcePostgresqlRoleRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	db, err := client.DB("")
+	if err != nil {
+		return err
+	}
+
+	var login, superuser, createDatabase, createRole bool
+	err = db.QueryRow(
+		"SELECT rolcanlogin, rolsuper, rolcreatedb, rolcreaterole "+
+			"FROM pg_catalog.pg_roles WHERE rolname = $1",
+		d.Id()).Scan(&login, &superuser, &createDatabase, &createRole)
+	if err == sql.ErrNoRows {
+		// The role doesn't exist anymore
+		d.SetId("")
+
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Error reading role: %s", err)
+	}
+
+	d.Set("name", d.Id())
+	d.Set("login", login)
+	d.Set("superuser", superuser)
+	d.Set("create_database", createDatabase)
+	d.Set("create_role", createRole)
+
+	return nil
+}
+
+func resourcePostgresqlRoleUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	db, err := client.DB("")
+	if err != nil {
+		return err
+	}
+
+	stmt := "ALTER ROLE " + quoteIdentifier(d.Id()) + " WITH" + roleStatement(d)
+	log.Printf("[DEBUG] Role update: %s", stmt)
+	if _, err := db.Exec(stmt); err != nil {
+		return fmt.Errorf("Error updating role: %s", err)
+	}
+
+	return resourcePostgresqlRoleRead(d, meta)
+}
+
+func resourcePostgresqlRoleDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	db, err := client.DB("")
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[INFO] Deleting role: %s", d.Id())
+	_, err = db.Exec("DROP ROLE IF EXISTS " + quoteIdentifier(d.Id()))
+	if err != nil {
+		return fmt.Errorf("Error deleting role: %s", err)
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,121 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccPostgresqlRole_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlRoleDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccPostgresqlRoleConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlRoleExists("postgresql_role.foobar"),
+					resource.TestCheckResourceAttr(
+						"postgresql_role.foobar", "login", "true"),
+					resource.TestCheckResourceAttr(
+						"postgresql_role.foobar", "create_database", "false"),
+				),
+			},
+
+			resource.TestStep{
+				Config: testAccPostgresqlRoleConfig_update,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlRoleExists("postgresql_role.foobar"),
+					resource.TestCheckResourceAttr(
+						"postgresql_role.foobar", "create_database", "true"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckPostgresqlRoleDestroy(s *terraform.State) error {
+	for _, rs := range s.Resources {
+		if rs.Type != "postgresql_role" {
+			continue
+		}
+
+		exists, err := testAccPostgresqlRoleExists(rs.ID)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			return fmt.Errorf("Role still exists")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckPostgresqlRoleExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.ID == "" {
+			return fmt.Errorf("No role ID is set")
+		}
+
+		if _, ok := rs.Attributes["password"]; ok {
+			return fmt.Errorf("Password is in the state")
+		}
+
+		exists, err := testAccPostgresqlRoleExists(rs.ID)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			return fmt.Errorf("Role not found")
+		}
+
+		return nil
+	}
+}
+
+func testAccPostgresqlRoleExists(name string) (bool, error) {
+	client := testAccProvider.Meta().(*Client)
+	db, err := client.DB("")
+	if err != nil {
+		return false, err
+	}
+
+	var found string
+	err = db.QueryRow(
+		"SELECT rolname FROM pg_catalog.pg_roles WHERE rolname = $1",
+		name).Scan(&found)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+const testAccPostgresqlRoleConfig = `
+resource "postgresql_role" "foobar" {
+	name = "terraform_test"
+	password = "foobarbaz"
+	login = true
+}
+`
+
+const testAccPostgresqlRoleConfig_update = `
+resource "postgresql_role" "foobar" {
+	name = "terraform_test"
+	password = "foobarbaz"
+	login = true
+	create_database = true
+}
+`
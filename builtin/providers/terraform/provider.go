@@ -0,0 +1,16 @@
+package terraform
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// Provider returns a terraform.ResourceProvider.
+func Provider() *schema.Provider {
+	// The provider has no configuration: states are read from the
+	// backends given to each resource.
+	return &schema.Provider{
+		ResourcesMap: map[string]*schema.Resource{
+			"terraform_remote_state": resourceRemoteState(),
+		},
+	}
+}
@@ -0,0 +1,145 @@
+package terraform
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/state"
+)
+
+func resourceRemoteState() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceRemoteStateCreate,
+		Read:   resourceRemoteStateRead,
+		Delete: resourceRemoteStateDelete,
+
+		Schema: map[string]*schema.Schema{
+			"backend": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"path": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"address": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"serial": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"output": &schema.Schema{
+				Type:     schema.TypeMap,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceRemoteStateCreate(d *schema.ResourceData, meta interface{}) error {
+	_, location, err := remoteStateBackend(d)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(location)
+	return resourceRemoteStateRead(d, meta)
+}
+
+func resourceRemoteStateRead(d *schema.ResourceData, meta interface{}) error {
+	s, location, err := remoteStateBackend(d)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Reading remote state: %s", location)
+	if err := s.RefreshState(); err != nil {
+		return fmt.Errorf("Error reading remote state %s: %s", location, err)
+	}
+
+	// A state that doesn't exist yet has no outputs, rather than being
+	// an error, so that both stacks can be created from scratch.
+	outputs := make(map[string]interface{})
+	serial := 0
+	if st := s.State(); st != nil {
+		for k, v := range st.Outputs {
+			outputs[k] = v
+		}
+		serial = int(st.Serial)
+	}
+
+	d.Set("serial", serial)
+	d.Set("output", outputs)
+
+	return nil
+}
+
+func resourceRemoteStateDelete(d *schema.ResourceData, meta interface{}) error {
+	// Nothing is created, so there's nothing to delete
+	d.SetId("")
+	return nil
+}
+
+// remoteState is a state that can be read from a backend.
+type remoteState interface {
+	state.StateReader
+	state.StateRefresher
+}
+
+// remoteStateBackend returns the state of the resource's backend, and
+// the path or address it's read from.
+func remoteStateBackend(d *schema.ResourceData) (remoteState, string, error) {
+	backend := d.Get("backend").(string)
+	path := d.Get("path").(string)
+	address := d.Get("address").(string)
+
+	switch backend {
+	case "", "local":
+		if path == "" {
+			return nil, "", fmt.Errorf("The local backend requires a path")
+		}
+
+		return &state.LocalState{Path: path}, path, nil
+	case "http":
+		if address == "" {
+			return nil, "", fmt.Errorf("The http backend requires an address")
+		}
+
+		return &state.HTTPState{Address: address}, address, nil
+	case "webdav":
+		if address == "" {
+			return nil, "", fmt.Errorf("The webdav backend requires an address")
+		}
+
+		return &state.WebDAVState{Address: address}, address, nil
+	case "etcd":
+		if address == "" || path == "" {
+			return nil, "", fmt.Errorf(
+				"The etcd backend requires an address and a path")
+		}
+
+		return &state.EtcdState{Address: address, Path: path},
+			address + "/" + strings.TrimLeft(path, "/"), nil
+	case "swift":
+		parts := strings.SplitN(path, "/", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, "", fmt.Errorf(
+				"The swift backend requires a path of the form CONTAINER/OBJECT")
+		}
+
+		return &state.SwiftState{Container: parts[0], Object: parts[1]}, path, nil
+	default:
+		return nil, "", fmt.Errorf("Unknown remote state backend: %s", backend)
+	}
+}
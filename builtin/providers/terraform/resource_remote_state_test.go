@@ -0,0 +1,262 @@
+package terraform
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccRemoteState(t *testing.T) {
+	path := testStateFile(t, testState())
+	defer os.Remove(path)
+
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: fmt.Sprintf(testAccRemoteStateConfig, path),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"terraform_remote_state.network", "output.vpc_id", "vpc-1234"),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceRemoteState(t *testing.T) {
+	path := testStateFile(t, testState())
+	defer os.Remove(path)
+
+	s := testRemoteStateApply(t, map[string]interface{}{
+		"path": path,
+	})
+
+	expected := map[string]string{
+		"output.vpc_id":    "vpc-1234",
+		"output.subnet_id": "subnet-5678",
+		"serial":           "3",
+	}
+	for k, v := range expected {
+		if s.Attributes[k] != v {
+			t.Fatalf("bad %s: %#v", k, s.Attributes)
+		}
+	}
+
+	// Changed outputs are picked up by a refresh
+	changed := testState()
+	changed.Outputs["vpc_id"] = "vpc-9999"
+	writeTestState(t, path, changed)
+
+	s, err := resourceRemoteState().Refresh(s, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if s.Attributes["output.vpc_id"] != "vpc-9999" {
+		t.Fatalf("bad: %#v", s.Attributes)
+	}
+}
+
+func TestResourceRemoteState_http(t *testing.T) {
+	var buf bytes.Buffer
+	if err := terraform.WriteState(testState(), &buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write(buf.Bytes())
+		}))
+	defer ts.Close()
+
+	s := testRemoteStateApply(t, map[string]interface{}{
+		"backend": "http",
+		"address": ts.URL + "/network",
+	})
+	if s.Attributes["output.subnet_id"] != "subnet-5678" {
+		t.Fatalf("bad: %#v", s.Attributes)
+	}
+}
+
+func TestResourceRemoteState_webdav(t *testing.T) {
+	var buf bytes.Buffer
+	if err := terraform.WriteState(testState(), &buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if _, password, _ := r.BasicAuth(); password != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			w.Write(buf.Bytes())
+		}))
+	defer ts.Close()
+
+	defer os.Setenv("TF_WEBDAV_USERNAME", os.Getenv("TF_WEBDAV_USERNAME"))
+	defer os.Setenv("TF_WEBDAV_PASSWORD", os.Getenv("TF_WEBDAV_PASSWORD"))
+	os.Setenv("TF_WEBDAV_USERNAME", "alice")
+	os.Setenv("TF_WEBDAV_PASSWORD", "secret")
+
+	s := testRemoteStateApply(t, map[string]interface{}{
+		"backend": "webdav",
+		"address": ts.URL + "/network.tfstate",
+	})
+	if s.Attributes["output.subnet_id"] != "subnet-5678" {
+		t.Fatalf("bad: %#v", s.Attributes)
+	}
+}
+
+func TestResourceRemoteState_etcd(t *testing.T) {
+	var buf bytes.Buffer
+	if err := terraform.WriteState(testState(), &buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v2/keys/network/state" {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"errorCode": 100, "message": "Key not found"}`)
+				return
+			}
+
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"node": map[string]interface{}{"value": buf.String()},
+			})
+		}))
+	defer ts.Close()
+
+	s := testRemoteStateApply(t, map[string]interface{}{
+		"backend": "etcd",
+		"address": ts.URL,
+		"path":    "/network/state",
+	})
+	if s.Attributes["output.subnet_id"] != "subnet-5678" {
+		t.Fatalf("bad: %#v", s.Attributes)
+	}
+}
+
+func TestResourceRemoteState_noState(t *testing.T) {
+	td, err := ioutil.TempDir("", "tf")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(td)
+
+	s := testRemoteStateApply(t, map[string]interface{}{
+		"path": td + "/terraform.tfstate",
+	})
+	if s.Attributes["serial"] != "0" || s.Attributes["output.vpc_id"] != "" {
+		t.Fatalf("bad: %#v", s.Attributes)
+	}
+}
+
+func TestResourceRemoteState_badBackend(t *testing.T) {
+	cases := []map[string]interface{}{
+		// No path
+		map[string]interface{}{"backend": "local"},
+
+		// No address
+		map[string]interface{}{"backend": "http", "path": "foo"},
+
+		// No WebDAV address
+		map[string]interface{}{"backend": "webdav", "path": "foo"},
+
+		// No etcd path
+		map[string]interface{}{"backend": "etcd", "address": "foo"},
+
+		// No Swift object
+		map[string]interface{}{"backend": "swift", "path": "foo"},
+
+		// Unknown
+		map[string]interface{}{"backend": "nope", "path": "foo"},
+	}
+
+	for i, raw := range cases {
+		r := resourceRemoteState()
+		d, err := r.Diff(nil, testResourceConfig(t, raw))
+		if err != nil {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+		if _, err := r.Apply(nil, d, nil); err == nil {
+			t.Fatalf("%d: should error", i)
+		}
+	}
+}
+
+func testRemoteStateApply(
+	t *testing.T, raw map[string]interface{}) *terraform.ResourceState {
+	r := resourceRemoteState()
+	d, err := r.Diff(nil, testResourceConfig(t, raw))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	s, err := r.Apply(nil, d, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return s
+}
+
+func testResourceConfig(
+	t *testing.T, raw map[string]interface{}) *terraform.ResourceConfig {
+	rc, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return terraform.NewResourceConfig(rc)
+}
+
+func testState() *terraform.State {
+	return &terraform.State{
+		Serial: 3,
+		Outputs: map[string]string{
+			"vpc_id":    "vpc-1234",
+			"subnet_id": "subnet-5678",
+		},
+	}
+}
+
+func testStateFile(t *testing.T, s *terraform.State) string {
+	f, err := ioutil.TempFile("", "tf")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	f.Close()
+
+	writeTestState(t, f.Name(), s)
+	return f.Name()
+}
+
+func writeTestState(t *testing.T, path string, s *terraform.State) {
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer f.Close()
+
+	if err := terraform.WriteState(s, f); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+const testAccRemoteStateConfig = `
+resource "terraform_remote_state" "network" {
+    path = "%s"
+}
+`
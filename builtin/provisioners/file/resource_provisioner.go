@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/terraform/helper/config"
@@ -11,7 +12,11 @@ import (
 	"github.com/hashicorp/terraform/terraform"
 )
 
-type ResourceProvisioner struct{}
+type ResourceProvisioner struct {
+	// stopCh is closed by Stop, to stop the applies in progress
+	l      sync.Mutex
+	stopCh chan struct{}
+}
 
 func (p *ResourceProvisioner) Apply(s *terraform.ResourceState,
 	c *terraform.ResourceConfig) error {
@@ -38,7 +43,32 @@ func (p *ResourceProvisioner) Apply(s *terraform.ResourceState,
 	if !ok {
 		return fmt.Errorf("Unsupported 'destination' type! Must be string.")
 	}
-	return p.copyFiles(conf, src, dst)
+	return p.copyFiles(conf, src, dst, p.stopChan())
+}
+
+func (p *ResourceProvisioner) Describe(s *terraform.ResourceState,
+	c *terraform.ResourceConfig) ([]string, error) {
+	// Ensure the connection type is SSH
+	if err := helper.VerifySSH(s); err != nil {
+		return nil, err
+	}
+
+	target, err := helper.Target(s)
+	if err != nil {
+		return nil, err
+	}
+
+	src, dst := "<computed>", "<computed>"
+	if v, ok := c.Config["source"].(string); ok {
+		src = v
+	}
+	if v, ok := c.Config["destination"].(string); ok {
+		dst = v
+	}
+
+	return []string{
+		fmt.Sprintf("upload %s to %s on %s", src, dst, target),
+	}, nil
 }
 
 func (p *ResourceProvisioner) Validate(c *terraform.ResourceConfig) (ws []string, es []error) {
@@ -51,8 +81,9 @@ func (p *ResourceProvisioner) Validate(c *terraform.ResourceConfig) (ws []string
 	return v.Validate(c)
 }
 
-// copyFiles is used to copy the files from a source to a destination
-func (p *ResourceProvisioner) copyFiles(conf *helper.SSHConfig, src, dst string) error {
+// copyFiles is used to copy the files from a source to a destination,
+// until the stop channel is closed
+func (p *ResourceProvisioner) copyFiles(conf *helper.SSHConfig, src, dst string, stopCh <-chan struct{}) error {
 	// Get the SSH client config
 	config, err := helper.PrepareConfig(conf)
 	if err != nil {
@@ -61,7 +92,7 @@ func (p *ResourceProvisioner) copyFiles(conf *helper.SSHConfig, src, dst string)
 
 	// Wait and retry until we establish the SSH connection
 	var comm *helper.SSHCommunicator
-	err = retryFunc(conf.TimeoutVal, func() error {
+	err = retryFunc(conf.TimeoutVal, stopCh, func() error {
 		host := fmt.Sprintf("%s:%d", conf.Host, conf.Port)
 		comm, err = helper.New(host, config)
 		return err
@@ -97,8 +128,35 @@ func (p *ResourceProvisioner) copyFiles(conf *helper.SSHConfig, src, dst string)
 	return err
 }
 
-// retryFunc is used to retry a function for a given duration
-func retryFunc(timeout time.Duration, f func() error) error {
+func (p *ResourceProvisioner) Stop() error {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	// Applies that start later get a new channel
+	if p.stopCh != nil {
+		close(p.stopCh)
+		p.stopCh = nil
+	}
+
+	return nil
+}
+
+// stopChan returns the channel that Stop closes, for an apply that is
+// starting.
+func (p *ResourceProvisioner) stopChan() <-chan struct{} {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	if p.stopCh == nil {
+		p.stopCh = make(chan struct{})
+	}
+
+	return p.stopCh
+}
+
+// retryFunc is used to retry a function for a given duration, or until
+// the stop channel is closed
+func retryFunc(timeout time.Duration, stopCh <-chan struct{}, f func() error) error {
 	finish := time.After(timeout)
 	for {
 		err := f()
@@ -110,6 +168,8 @@ func retryFunc(timeout time.Duration, f func() error) error {
 		select {
 		case <-finish:
 			return err
+		case <-stopCh:
+			return fmt.Errorf("Stopped while retrying: %v", err)
 		case <-time.After(3 * time.Second):
 		}
 	}
@@ -1,6 +1,7 @@
 package file
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/hashicorp/terraform/config"
@@ -40,6 +41,29 @@ func TestResourceProvider_Validate_bad(t *testing.T) {
 	}
 }
 
+func TestResourceProvider_Describe(t *testing.T) {
+	c := testConfig(t, map[string]interface{}{
+		"source":      "/tmp/foo",
+		"destination": "/tmp/bar",
+	})
+	s := &terraform.ResourceState{
+		ConnInfo: map[string]string{
+			"host": "127.0.0.1",
+		},
+	}
+
+	p := new(ResourceProvisioner)
+	actual, err := p.Describe(s, c)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	expected := []string{"upload /tmp/foo to /tmp/bar on root@127.0.0.1:22"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
 func testConfig(
 	t *testing.T,
 	c map[string]interface{}) *terraform.ResourceConfig {
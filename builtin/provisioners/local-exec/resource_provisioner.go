@@ -2,8 +2,10 @@ package localexec
 
 import (
 	"fmt"
+	"log"
 	"os/exec"
 	"runtime"
+	"sync"
 
 	"github.com/armon/circbuf"
 	"github.com/hashicorp/terraform/helper/config"
@@ -17,7 +19,11 @@ const (
 	maxBufSize = 8 * 1024
 )
 
-type ResourceProvisioner struct{}
+type ResourceProvisioner struct {
+	// The commands that are running, which are killed by Stop
+	l    sync.Mutex
+	cmds map[*exec.Cmd]struct{}
+}
 
 func (p *ResourceProvisioner) Apply(
 	s *terraform.ResourceState,
@@ -50,13 +56,60 @@ func (p *ResourceProvisioner) Apply(
 	cmd.Stdout = output
 
 	// Run the command to completion
-	if err := cmd.Run(); err != nil {
+	if err := p.run(cmd); err != nil {
 		return fmt.Errorf("Error running command '%s': %v. Output: %s",
 			command, err, output.Bytes())
 	}
 	return nil
 }
 
+// run runs the command, so that Stop can kill it while it's running.
+func (p *ResourceProvisioner) run(cmd *exec.Cmd) error {
+	p.l.Lock()
+	if err := cmd.Start(); err != nil {
+		p.l.Unlock()
+		return err
+	}
+	if p.cmds == nil {
+		p.cmds = make(map[*exec.Cmd]struct{})
+	}
+	p.cmds[cmd] = struct{}{}
+	p.l.Unlock()
+
+	err := cmd.Wait()
+
+	p.l.Lock()
+	delete(p.cmds, cmd)
+	p.l.Unlock()
+
+	return err
+}
+
+func (p *ResourceProvisioner) Stop() error {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	for cmd := range p.cmds {
+		log.Printf("[INFO] Killing local-exec command: %d", cmd.Process.Pid)
+		if err := cmd.Process.Kill(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *ResourceProvisioner) Describe(
+	s *terraform.ResourceState,
+	c *terraform.ResourceConfig) ([]string, error) {
+	command, ok := c.Config["command"].(string)
+	if !ok {
+		command = "<computed>"
+	}
+
+	return []string{fmt.Sprintf("run locally: %s", command)}, nil
+}
+
 func (p *ResourceProvisioner) Validate(c *terraform.ResourceConfig) ([]string, []error) {
 	validator := config.Validator{
 		Required: []string{"command"},
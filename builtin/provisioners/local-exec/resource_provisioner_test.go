@@ -3,8 +3,11 @@ package localexec
 import (
 	"io/ioutil"
 	"os"
+	"reflect"
+	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/terraform"
@@ -38,6 +41,66 @@ func TestResourceProvider_Apply(t *testing.T) {
 	}
 }
 
+func TestResourceProvider_Stop(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the command is a shell script")
+	}
+
+	c := testConfig(t, map[string]interface{}{
+		"command": "exec sleep 30",
+	})
+
+	p := new(ResourceProvisioner)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- p.Apply(nil, c)
+	}()
+
+	// Wait for the command to start
+	for i := 0; ; i++ {
+		p.l.Lock()
+		running := len(p.cmds)
+		p.l.Unlock()
+		if running > 0 {
+			break
+		}
+		if i > 100 {
+			t.Fatal("command should be running")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := p.Stop(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	select {
+	case err := <-errCh:
+		if err == nil {
+			t.Fatal("should error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("apply should return once stopped")
+	}
+}
+
+func TestResourceProvider_Describe(t *testing.T) {
+	c := testConfig(t, map[string]interface{}{
+		"command": "echo foo > bar",
+	})
+
+	p := new(ResourceProvisioner)
+	actual, err := p.Describe(&terraform.ResourceState{}, c)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	expected := []string{"run locally: echo foo > bar"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
 func TestResourceProvider_Validate_good(t *testing.T) {
 	c := testConfig(t, map[string]interface{}{
 		"command": "echo foo",
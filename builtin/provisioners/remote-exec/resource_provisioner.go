@@ -9,6 +9,7 @@ import (
 	"log"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	helper "github.com/hashicorp/terraform/helper/ssh"
@@ -20,7 +21,11 @@ const (
 	DefaultShebang = "#!/bin/sh"
 )
 
-type ResourceProvisioner struct{}
+type ResourceProvisioner struct {
+	// stopCh is closed by Stop, to stop the applies in progress
+	l      sync.Mutex
+	stopCh chan struct{}
+}
 
 func (p *ResourceProvisioner) Apply(s *terraform.ResourceState,
 	c *terraform.ResourceConfig) error {
@@ -45,12 +50,62 @@ func (p *ResourceProvisioner) Apply(s *terraform.ResourceState,
 	}
 
 	// Copy and execute each script
-	if err := p.runScripts(conf, scripts); err != nil {
+	if err := p.runScripts(conf, scripts, p.stopChan()); err != nil {
 		return err
 	}
 	return nil
 }
 
+func (p *ResourceProvisioner) Describe(s *terraform.ResourceState,
+	c *terraform.ResourceConfig) ([]string, error) {
+	// Ensure the connection type is SSH
+	if err := helper.VerifySSH(s); err != nil {
+		return nil, err
+	}
+
+	target, err := helper.Target(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for _, k := range []string{"inline", "script", "scripts"} {
+		v, ok := c.Config[k]
+		if !ok {
+			if c.IsSet(k) {
+				result = append(result, fmt.Sprintf(
+					"run %s on %s: <computed>", k, target))
+			}
+
+			continue
+		}
+
+		var values []string
+		switch vt := v.(type) {
+		case string:
+			values = append(values, vt)
+		case []interface{}:
+			for _, l := range vt {
+				values = append(values, fmt.Sprintf("%v", l))
+			}
+		default:
+			return nil, fmt.Errorf("Unsupported '%s' type!", k)
+		}
+
+		for _, v := range values {
+			if k == "inline" {
+				result = append(result, fmt.Sprintf(
+					"run on %s: %s", target, v))
+			} else {
+				result = append(result, fmt.Sprintf(
+					"run script %s on %s", v, target))
+			}
+		}
+	}
+
+	return result, nil
+}
+
 func (p *ResourceProvisioner) Validate(c *terraform.ResourceConfig) (ws []string, es []error) {
 	num := 0
 	for name := range c.Raw {
@@ -160,8 +215,9 @@ func (p *ResourceProvisioner) collectScripts(c *terraform.ResourceConfig) ([]io.
 	return fhs, nil
 }
 
-// runScripts is used to copy and execute a set of scripts
-func (p *ResourceProvisioner) runScripts(conf *helper.SSHConfig, scripts []io.ReadCloser) error {
+// runScripts is used to copy and execute a set of scripts, until the stop
+// channel is closed
+func (p *ResourceProvisioner) runScripts(conf *helper.SSHConfig, scripts []io.ReadCloser, stopCh <-chan struct{}) error {
 	// Get the SSH client config
 	config, err := helper.PrepareConfig(conf)
 	if err != nil {
@@ -170,7 +226,7 @@ func (p *ResourceProvisioner) runScripts(conf *helper.SSHConfig, scripts []io.Re
 
 	// Wait and retry until we establish the SSH connection
 	var comm *helper.SSHCommunicator
-	err = retryFunc(conf.TimeoutVal, func() error {
+	err = retryFunc(conf.TimeoutVal, stopCh, func() error {
 		host := fmt.Sprintf("%s:%d", conf.Host, conf.Port)
 		comm, err = helper.New(host, config)
 		return err
@@ -180,8 +236,14 @@ func (p *ResourceProvisioner) runScripts(conf *helper.SSHConfig, scripts []io.Re
 	}
 
 	for _, script := range scripts {
+		select {
+		case <-stopCh:
+			return fmt.Errorf("Stopped before running all the scripts")
+		default:
+		}
+
 		var cmd *helper.RemoteCmd
-		err := retryFunc(conf.TimeoutVal, func() error {
+		err := retryFunc(conf.TimeoutVal, stopCh, func() error {
 			if err := comm.Upload(conf.ScriptPath, script); err != nil {
 				return fmt.Errorf("Failed to upload script: %v", err)
 			}
@@ -223,8 +285,35 @@ func (p *ResourceProvisioner) runScripts(conf *helper.SSHConfig, scripts []io.Re
 	return nil
 }
 
-// retryFunc is used to retry a function for a given duration
-func retryFunc(timeout time.Duration, f func() error) error {
+func (p *ResourceProvisioner) Stop() error {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	// Applies that start later get a new channel
+	if p.stopCh != nil {
+		close(p.stopCh)
+		p.stopCh = nil
+	}
+
+	return nil
+}
+
+// stopChan returns the channel that Stop closes, for an apply that is
+// starting.
+func (p *ResourceProvisioner) stopChan() <-chan struct{} {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	if p.stopCh == nil {
+		p.stopCh = make(chan struct{})
+	}
+
+	return p.stopCh
+}
+
+// retryFunc is used to retry a function for a given duration, or until
+// the stop channel is closed
+func retryFunc(timeout time.Duration, stopCh <-chan struct{}, f func() error) error {
 	finish := time.After(timeout)
 	for {
 		err := f()
@@ -236,6 +325,8 @@ func retryFunc(timeout time.Duration, f func() error) error {
 		select {
 		case <-finish:
 			return err
+		case <-stopCh:
+			return fmt.Errorf("Stopped while retrying: %v", err)
 		case <-time.After(3 * time.Second):
 		}
 	}
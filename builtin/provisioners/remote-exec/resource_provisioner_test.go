@@ -2,8 +2,11 @@ package remoteexec
 
 import (
 	"bytes"
+	"errors"
 	"io"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/terraform"
@@ -41,6 +44,34 @@ func TestResourceProvider_Validate_bad(t *testing.T) {
 	}
 }
 
+func TestResourceProvider_Describe(t *testing.T) {
+	c := testConfig(t, map[string]interface{}{
+		"inline": []interface{}{
+			"cd /tmp",
+			"wget http://foobar",
+		},
+	})
+	s := &terraform.ResourceState{
+		ConnInfo: map[string]string{
+			"user": "ubuntu",
+		},
+	}
+
+	p := new(ResourceProvisioner)
+	actual, err := p.Describe(s, c)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	expected := []string{
+		"run on ubuntu@<computed>:22: cd /tmp",
+		"run on ubuntu@<computed>:22: wget http://foobar",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
 func TestResourceProvider_generateScript(t *testing.T) {
 	p := new(ResourceProvisioner)
 	conf := testConfig(t, map[string]interface{}{
@@ -154,6 +185,38 @@ func TestResourceProvider_CollectScripts_scripts(t *testing.T) {
 	}
 }
 
+func TestResourceProvider_Stop(t *testing.T) {
+	p := new(ResourceProvisioner)
+	stopCh := p.stopChan()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- retryFunc(time.Minute, stopCh, func() error {
+			return errors.New("connection refused")
+		})
+	}()
+
+	if err := p.Stop(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	select {
+	case err := <-errCh:
+		if err == nil {
+			t.Fatal("should error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("retrying should stop")
+	}
+
+	// Applies that start after the stop aren't stopped
+	select {
+	case <-p.stopChan():
+		t.Fatal("new applies should not be stopped")
+	default:
+	}
+}
+
 func testConfig(
 	t *testing.T,
 	c map[string]interface{}) *terraform.ResourceConfig {
@@ -0,0 +1,19 @@
+package env
+
+import (
+	"fmt"
+	"os"
+)
+
+// SecretBackend reads secrets from environment variables. The key of a
+// secret is the name of the variable: "${secret.env.DB_PASSWORD}".
+type SecretBackend struct{}
+
+func (b *SecretBackend) Secret(key string) (string, error) {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return "", fmt.Errorf("environment variable not set: %s", key)
+	}
+
+	return v, nil
+}
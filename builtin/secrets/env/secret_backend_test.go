@@ -0,0 +1,30 @@
+package env
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestSecretBackend_impl(t *testing.T) {
+	var _ terraform.SecretBackend = new(SecretBackend)
+}
+
+func TestSecretBackend(t *testing.T) {
+	os.Setenv("TF_TEST_SECRET", "hunter2")
+	defer os.Unsetenv("TF_TEST_SECRET")
+
+	b := new(SecretBackend)
+	v, err := b.Secret("TF_TEST_SECRET")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if v != "hunter2" {
+		t.Fatalf("bad: %s", v)
+	}
+
+	if _, err := b.Secret("TF_TEST_SECRET_UNSET"); err == nil {
+		t.Fatal("should error")
+	}
+}
@@ -0,0 +1,37 @@
+package file
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// SecretBackend reads secrets from files in a directory, such as a
+// volume of secrets that is only mounted while Terraform runs. The key
+// of a secret is the name of its file: "${secret.file.db_password}".
+// Trailing newlines are removed.
+type SecretBackend struct {
+	Dir string
+}
+
+func (b *SecretBackend) Secret(key string) (string, error) {
+	if b.Dir == "" {
+		return "", fmt.Errorf(
+			"no directory for secrets, set TF_SECRETS_DIR")
+	}
+
+	// Keys can only name files within the directory
+	path := filepath.Join(b.Dir, filepath.FromSlash(key))
+	rel, err := filepath.Rel(b.Dir, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("invalid secret key: %s", key)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
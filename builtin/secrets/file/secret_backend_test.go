@@ -0,0 +1,50 @@
+package file
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestSecretBackend_impl(t *testing.T) {
+	var _ terraform.SecretBackend = new(SecretBackend)
+}
+
+func TestSecretBackend(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tf")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "db_password")
+	if err := ioutil.WriteFile(path, []byte("hunter2\n"), 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	b := &SecretBackend{Dir: dir}
+	v, err := b.Secret("db_password")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if v != "hunter2" {
+		t.Fatalf("bad: %q", v)
+	}
+
+	if _, err := b.Secret("missing"); err == nil {
+		t.Fatal("should error")
+	}
+	if _, err := b.Secret("../db_password"); err == nil {
+		t.Fatal("should error")
+	}
+}
+
+func TestSecretBackend_noDir(t *testing.T) {
+	b := new(SecretBackend)
+	if _, err := b.Secret("db_password"); err == nil {
+		t.Fatal("should error")
+	}
+}
@@ -0,0 +1,90 @@
+package vault
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// SecretBackend reads secrets from HashiCorp Vault. The key of a secret
+// is the path of the secret in Vault and the field to read from it,
+// separated by a period: "${secret.vault.secret/db.password}".
+type SecretBackend struct {
+	Address string
+	Token   string
+
+	// Client is the HTTP client used to talk to Vault. If it isn't set,
+	// http.DefaultClient is used.
+	Client *http.Client
+}
+
+// vaultResponse is the part of a response from Vault that we use.
+type vaultResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []string               `json:"errors"`
+}
+
+func (b *SecretBackend) Secret(key string) (string, error) {
+	if b.Address == "" {
+		return "", fmt.Errorf("no Vault address, set VAULT_ADDR")
+	}
+
+	idx := strings.LastIndex(key, ".")
+	if idx <= 0 || idx == len(key)-1 {
+		return "", fmt.Errorf(
+			"Vault secrets must be a path and field: path.field, got %s", key)
+	}
+	path, field := key[:idx], key[idx+1:]
+
+	req, err := http.NewRequest("GET", fmt.Sprintf(
+		"%s/v1/%s", strings.TrimRight(b.Address, "/"), path), nil)
+	if err != nil {
+		return "", err
+	}
+	if b.Token != "" {
+		req.Header.Set("X-Vault-Token", b.Token)
+	}
+
+	client := b.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error reading %s from Vault: %s", path, err)
+	}
+	defer resp.Body.Close()
+
+	var result vaultResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf(
+			"error reading %s from Vault: status %d: %s",
+			path, resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf(
+			"error reading %s from Vault: status %d: %s",
+			path, resp.StatusCode, strings.Join(result.Errors, ", "))
+	}
+
+	// Version 2 of the key/value backend nests the secret within
+	// another "data" field, alongside its metadata.
+	data := result.Data
+	if inner, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = inner
+		}
+	}
+
+	v, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("%s in Vault has no field %s", path, field)
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("%s in Vault: field %s isn't a string", path, field)
+	}
+
+	return s, nil
+}
@@ -0,0 +1,67 @@
+package vault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestSecretBackend_impl(t *testing.T) {
+	var _ terraform.SecretBackend = new(SecretBackend)
+}
+
+func TestSecretBackend(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Vault-Token") != "token" {
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"errors": ["permission denied"]}`))
+				return
+			}
+
+			switch r.URL.Path {
+			case "/v1/secret/db":
+				w.Write([]byte(`{"data": {"password": "hunter2"}}`))
+			case "/v1/kv/data/db":
+				w.Write([]byte(`{"data": {
+					"data": {"password": "hunter3"},
+					"metadata": {"version": 1}
+				}}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"errors": []}`))
+			}
+		}))
+	defer ts.Close()
+
+	b := &SecretBackend{Address: ts.URL, Token: "token"}
+
+	cases := []struct {
+		Key    string
+		Result string
+		Error  bool
+	}{
+		{"secret/db.password", "hunter2", false},
+		{"kv/data/db.password", "hunter3", false},
+		{"secret/db.username", "", true},
+		{"secret/nope.password", "", true},
+		{"secret/db", "", true},
+	}
+
+	for i, tc := range cases {
+		actual, err := b.Secret(tc.Key)
+		if (err != nil) != tc.Error {
+			t.Fatalf("%d. Error: %s", i, err)
+		}
+		if actual != tc.Result {
+			t.Fatalf("%d bad: %q", i, actual)
+		}
+	}
+
+	b.Token = "bad"
+	if _, err := b.Secret("secret/db.password"); err == nil {
+		t.Fatal("should error")
+	}
+}
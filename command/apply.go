@@ -20,14 +20,34 @@ type ApplyCommand struct {
 }
 
 func (c *ApplyCommand) Run(args []string) int {
-	var refresh bool
-	var statePath, stateOutPath, backupPath string
+	var autoApprove, lock, provisioners, recordGit, refresh, resume, stats bool
+	var statePath, stateOutPath, backupPath, profileDir, override string
+	var approvalPath string
+	var forget FlagStringSlice
 
 	args = c.Meta.process(args, true)
+	auditArgs := args
+	if c.refuseReadOnly("apply") {
+		return 1
+	}
 
 	cmdFlags := c.Meta.flagSet("apply")
+	cmdFlags.StringVar(&approvalPath, "approval", "", "path")
+	cmdFlags.BoolVar(&autoApprove, "auto-approve", false, "auto-approve")
+	cmdFlags.Var(&forget, "forget", "resource")
+	cmdFlags.BoolVar(&lock, "lock", true, "lock")
+	cmdFlags.StringVar(&profileDir, "profile", "", "dir")
+	cmdFlags.IntVar(&c.MaxDestroy, "max-destroy", c.MaxDestroy, "count")
+	cmdFlags.Float64Var(
+		&c.MaxDestroyPercent, "max-destroy-percent", c.MaxDestroyPercent, "percent")
+	cmdFlags.StringVar(&c.message, "message", "", "message")
+	cmdFlags.StringVar(&override, "override", "", "reason")
+	cmdFlags.BoolVar(&provisioners, "provisioners", false, "provisioners")
+	cmdFlags.BoolVar(&recordGit, "record-git", false, "record-git")
 	cmdFlags.BoolVar(&refresh, "refresh", true, "refresh")
+	cmdFlags.BoolVar(&resume, "resume", false, "resume")
 	cmdFlags.StringVar(&statePath, "state", DefaultStateFilename, "path")
+	cmdFlags.BoolVar(&stats, "stats", false, "stats")
 	cmdFlags.StringVar(&stateOutPath, "state-out", "", "path")
 	cmdFlags.StringVar(&backupPath, "backup", "", "path")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
@@ -42,18 +62,54 @@ func (c *ApplyCommand) Run(args []string) int {
 		cmdFlags.Usage()
 		return 1
 	} else if len(args) == 1 {
-		configPath = args[0]
+		configPath = c.path(args[0])
 	} else {
 		var err error
-		configPath, err = os.Getwd()
+		configPath, err = c.wd()
 		if err != nil {
 			c.Ui.Error(fmt.Sprintf("Error getting pwd: %s", err))
 		}
 	}
 
-	// Prepare the extra hooks to count resources
+	// Prepare the extra hooks to count resources, and to record what
+	// was applied in case the apply fails partway
 	countHook := new(CountHook)
-	c.Meta.extraHooks = []terraform.Hook{countHook}
+	resumeHook := new(ResumeHook)
+	c.Meta.extraHooks = []terraform.Hook{countHook, resumeHook}
+
+	// If we're profiling, start it now so the whole run is covered
+	if profileDir != "" {
+		prof, err := startProfile(c.path(profileDir))
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		defer func() {
+			if err := prof.Stop(); err != nil {
+				c.Ui.Error(err.Error())
+			}
+		}()
+
+		c.Meta.extraHooks = append(c.Meta.extraHooks, prof.Hook)
+	}
+
+	// Notify the webhooks when the apply starts and ends
+	if h := c.webhookHook(); h != nil {
+		c.Meta.extraHooks = append(c.Meta.extraHooks, h)
+	}
+
+	// Record the run in the audit log once the state is written
+	audit := c.startAudit("apply", auditArgs)
+
+	// If we're collecting statistics, show them once we're done
+	if stats {
+		defer c.startStats()()
+	}
+
+	// Paths are relative to the working directory
+	statePath = c.path(statePath)
+	stateOutPath = c.path(stateOutPath)
+	backupPath = c.path(backupPath)
 
 	// If we don't specify an output path, default to out normal state
 	// path.
@@ -67,15 +123,88 @@ func (c *ApplyCommand) Run(args []string) int {
 		backupPath = stateOutPath + DefaultBackupExtention
 	}
 
-	// Build the context based on the arguments given
+	// The plan of an apply that fails partway is saved next to the state
+	// it's written to, so that it can be resumed
+	resumePath := stateOutPath + DefaultResumeExtension
+
+	// Lock the state so that no other run changes it at the same time
+	if lock {
+		l, err := c.lockState(stateOutPath)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error locking state: %s", err))
+			return 1
+		}
+		defer l.Unlock()
+	}
+
+	// Build the context based on the arguments given. States and plans
+	// written by a newer version of Terraform are only used if overridden.
+	c.refuseNewerVersion = override == ""
+	var resumePlan *terraform.Plan
+	if resume {
+		p, err := c.readResumePlan(resumePath)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		// The configuration and variables are those of the failed apply,
+		// so that the diffs in its plan still hold. Variables that aren't
+		// saved with it, which are the write-only ones, can still be set.
+		resumePlan = p
+		c.Configuration = p.Config
+		vs := make(map[string]string)
+		for k, v := range c.variables {
+			vs[k] = v
+		}
+		for k, v := range p.Vars {
+			vs[k] = v
+		}
+		c.variables = vs
+	}
 	ctx, planned, err := c.Context(configPath, statePath)
 	if err != nil {
 		c.Ui.Error(err.Error())
 		return 1
 	}
-	if !validateContext(ctx, c.Ui) {
+	if planned && resume {
+		c.Ui.Error(
+			"The -resume flag can't be used with a plan file. Apply the plan\n" +
+				"file again instead.")
+		return 1
+	}
+	if !c.validateContext(ctx) {
 		return 1
 	}
+	if len(c.Approvers) > 0 && !planned {
+		c.Ui.Error(fmt.Sprintf(
+			"Only saved plans that are approved can be applied. Create a plan\n"+
+				"with `terraform plan -out` and have it approved with\n"+
+				"`terraform approve` by one of: %s.", c.approverNames()))
+		return 1
+	}
+	if planned && len(forget) > 0 {
+		c.Ui.Error(
+			"The -forget flag can't be used with a plan file. Use it when\n" +
+				"creating the plan instead.")
+		return 1
+	}
+	if planned {
+		if approvalPath == "" {
+			approvalPath = configPath + ApprovalExtension
+		}
+		approver, err := c.checkApproval(configPath, c.path(approvalPath))
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		audit.SetApprover(approver)
+		audit.SetPlan(c.plan)
+		if !c.enforcePolicies(c.plan, override, audit) {
+			return 1
+		}
+	}
 
 	// Create a backup of the state before updating
 	if backupPath != "-" && c.state != nil {
@@ -91,64 +220,85 @@ func (c *ApplyCommand) Run(args []string) int {
 		}
 	}
 
-	// Plan if we haven't already
+	// Plan if we haven't already. Resuming doesn't refresh, and reuses
+	// the diffs of the failed apply for the resources it didn't change.
+	plan := c.plan
 	if !planned {
-		if refresh {
+		if refresh && resumePlan == nil {
 			if _, err := ctx.Refresh(); err != nil {
 				c.Ui.Error(fmt.Sprintf("Error refreshing state: %s", err))
 				return 1
 			}
 		}
 
-		if _, err := ctx.Plan(nil); err != nil {
+		plan, err = ctx.Plan(&terraform.PlanOpts{
+			Provisioners: provisioners,
+			Forget:       forget,
+			Resume:       resumePlan,
+		})
+		if err != nil {
 			c.Ui.Error(fmt.Sprintf(
 				"Error creating plan: %s", err))
 			return 1
 		}
+		audit.SetPlan(plan)
+		if !c.enforcePolicies(plan, override, audit) {
+			return 1
+		}
+
+		// Since the plan wasn't reviewed ahead of time, show it and
+		// make sure this is really what the user wants to do.
+		if !autoApprove && !plan.Diff.Empty() {
+			c.Ui.Output(FormatPlan(plan, c.Colorize()))
+			c.showCost(plan)
+
+			v, err := c.Ui.Ask(strings.TrimSpace(applyApproveQuery) + " ")
+			if err != nil {
+				c.Ui.Error(fmt.Sprintf("Error asking for approval: %s", err))
+				return 1
+			}
+			if strings.TrimSpace(v) != "yes" {
+				c.Ui.Output("Apply cancelled.")
+				return 1
+			}
+			c.Ui.Output("")
+		}
 	}
 
-	// Start the apply in a goroutine so that we can be interrupted.
+	// Run the apply so that we can be interrupted. A stopped apply still
+	// has a result, which is saved below.
 	var state *terraform.State
 	var applyErr error
-	doneCh := make(chan struct{})
-	go func() {
-		defer close(doneCh)
+	_, finished := c.runInterruptible(ctx, c.ShutdownCh, func() {
 		state, applyErr = ctx.Apply()
-	}()
-
-	// Wait for the apply to finish or for us to be interrupted so
-	// we can handle it properly.
+	})
+	if !finished {
+		return 1
+	}
 	err = nil
-	select {
-	case <-c.ShutdownCh:
-		c.Ui.Output("Interrupt received. Gracefully shutting down...")
-
-		// Stop execution
-		ctx.Stop()
-
-		// Still get the result, since there is still one
-		select {
-		case <-c.ShutdownCh:
-			c.Ui.Error(
-				"Two interrupts received. Exiting immediately. Note that data\n" +
-					"loss may have occurred.")
-			return 1
-		case <-doneCh:
+
+	if state != nil && recordGit {
+		git := ctx.Git()
+		if git == nil {
+			c.Ui.Output(c.Colorize().Color("[yellow]Warning: [reset]") +
+				"the configuration isn't in a git repository, so no git\n" +
+				"revision is recorded in the state.")
 		}
-	case <-doneCh:
+
+		recordGitRevision(state, git)
 	}
 
 	if state != nil {
+		c.recordRun(state)
+
 		// Write state out to the file
-		f, err := os.Create(stateOutPath)
-		if err == nil {
-			err = terraform.WriteState(state, f)
-			f.Close()
-		}
-		if err != nil {
+		if err := c.writeStateFile(stateOutPath, state); err != nil {
+			audit.Finish(stateOutPath, nil, err)
 			c.Ui.Error(fmt.Sprintf("Failed to save state: %s", err))
 			return 1
 		}
+
+		audit.Finish(stateOutPath, state, applyErr)
 	}
 
 	if applyErr != nil {
@@ -160,9 +310,27 @@ func (c *ApplyCommand) Run(args []string) int {
 				"any resources that successfully completed. Please address the error\n"+
 				"above and apply again to incrementally change your infrastructure.",
 			applyErr))
+
+		if state != nil {
+			err := writeResumePlan(resumePath, plan, resumeHook.Completed())
+			if err != nil {
+				c.Ui.Error(fmt.Sprintf("Error saving the plan to resume: %s", err))
+			} else {
+				c.Ui.Error(
+					"\nTo continue from where the apply failed, without refreshing\n" +
+						"or planning the resources that weren't changed again, run\n" +
+						"`terraform apply -resume`.")
+			}
+		}
+
 		return 1
 	}
 
+	// There's nothing left to resume once an apply succeeds
+	if err := os.Remove(resumePath); err != nil && !os.IsNotExist(err) {
+		log.Printf("[WARN] Error removing %s: %s", resumePath, err)
+	}
+
 	c.Ui.Output(c.Colorize().Color(fmt.Sprintf(
 		"[reset][bold][green]\n"+
 			"Apply complete! Resources: %d added, %d changed, %d destroyed.",
@@ -199,6 +367,9 @@ func (c *ApplyCommand) Run(args []string) int {
 
 		for _, k := range keys {
 			v := state.Outputs[k]
+			if _, ok := state.SensitiveOutputs[k]; ok {
+				v = sensitiveRedacted
+			}
 
 			outputBuf.WriteString(fmt.Sprintf(
 				"  %s%s = %s\n",
@@ -221,17 +392,73 @@ Usage: terraform apply [options] [dir]
   Builds or changes infrastructure according to Terraform configuration
   files .
 
+  Unless a plan file is given, the execution plan is shown first and
+  must be approved by typing "yes".
+
+  If approvers are configured in the CLI configuration, only plan files
+  approved by one of them with "terraform approve" can be applied.
+
 Options:
 
+  -approval=path         Path to the approval of the plan file. Defaults to
+                         the path of the plan with the ".approval" extension.
+
+  -auto-approve          Skip the approval of the execution plan before
+                         applying it.
+
   -backup=path           Path to backup the existing state file before
                          modifying. Defaults to the "-state-out" path with
                          ".backup" extension. Set to "-" to disable backup.
 
+  -debug-dump=dir        Write the requests and responses of provider calls to
+                         the given directory, with secrets redacted, to include
+                         in bug reports.
+
+  -forget=resource       Remove a resource that was deleted outside of
+                         Terraform from the state, instead of creating it
+                         again. This flag can be set multiple times. It
+                         can't be used with a plan file.
+
+  -message=text          Why the command is run, recorded in the metadata of
+                         the state and in the audit log.
+
   -no-color              If specified, output won't contain any color.
 
+  -lock=true             Lock the state while applying, so that no other run
+                         of Terraform changes it at the same time.
+
+  -max-destroy=n         Refuse to apply the plan if it destroys more than
+                         n resources, including replaced resources.
+                         Defaults to "max_destroy" in the CLI configuration.
+
+  -max-destroy-percent=n Refuse to apply the plan if it destroys more than
+                         n percent of the resources in the state. Defaults
+                         to "max_destroy_percent" in the CLI configuration.
+
+  -override=reason       Apply the plan even if it fails the policies,
+                         exceeds the cost budget or destroys more than the
+                         limits allow, or if the state or plan was written
+                         by a newer version of Terraform. The reason is
+                         recorded in the audit log.
+
+  -profile=dir           Write CPU and heap profiles and a report of how
+                         long each resource took to the given directory.
+
+  -provisioners          When showing the execution plan for approval, show
+                         what the provisioners of resources that will be
+                         created would do, without running them.
+
+  -record-git            Record the git commit and branch of the configuration
+                         in the metadata of the state.
+
   -refresh=true          Update state prior to checking for differences. This
                          has no effect if a plan file is given to apply.
 
+  -resume                Continue the last apply to the state, which failed
+                         partway. Resources it didn't change keep the diffs of
+                         its plan, and the others are diffed again, without
+                         refreshing.
+
   -state=path            Path to read and save state (unless state-out
                          is specified). Defaults to "terraform.tfstate".
 
@@ -239,6 +466,11 @@ Options:
                          "-state". This can be used to preserve the old
                          state.
 
+  -stats                 Show how many calls were made to each provider and the
+                         slowest resources and operations at the end of the run.
+
+  -strict                Treat warnings about the configuration as errors.
+
   -var 'foo=bar'         Set a variable in the Terraform configuration. This
                          flag can be set multiple times.
 
@@ -251,6 +483,107 @@ Options:
 	return strings.TrimSpace(helpText)
 }
 
+// readResumePlan reads the plan of the apply that failed partway, that
+// was saved to the given path to resume it.
+func (c *ApplyCommand) readResumePlan(path string) (*terraform.Plan, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf(
+			"There is no failed apply to resume: %s doesn't exist.", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error loading the plan to resume: %s", err)
+	}
+	defer f.Close()
+
+	p, err := terraform.ReadPlan(f)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading the plan to resume: %s", err)
+	}
+	if err := c.checkVersion("plan "+path, p.TFVersion); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// writeResumePlan saves the plan of an apply that failed partway to the
+// given path, along with the resources that were applied, to resume it.
+func writeResumePlan(path string, p *terraform.Plan, completed []string) error {
+	log.Printf("[INFO] Writing the plan to resume to: %s", path)
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return terraform.WritePlan(&terraform.Plan{
+		Config:    p.Config,
+		Diff:      p.Diff,
+		State:     p.State,
+		Vars:      p.Vars,
+		Git:       p.Git,
+		Completed: completed,
+	}, f)
+}
+
+// enforcePolicies checks the plan with the policies and the cost budget,
+// and returns whether it can be applied. A plan that fails them is only
+// applied if an override reason is given, which is recorded in the audit
+// log.
+func (c *ApplyCommand) enforcePolicies(
+	p *terraform.Plan, override string, audit *auditRun) bool {
+	failures := c.policyFailures(p)
+	if len(failures) == 0 {
+		return true
+	}
+
+	if override == "" {
+		c.Ui.Error(fmt.Sprintf(
+			"The plan fails the following policies:\n\n  * %s\n\n"+
+				"To apply it anyway, give the reason with -override.",
+			strings.Join(failures, "\n  * ")))
+		return false
+	}
+
+	c.Ui.Output(c.Colorize().Color(fmt.Sprintf(
+		"[yellow]Warning: [reset]the plan fails the following policies, "+
+			"which are overridden:\n\n  * %s\n",
+		strings.Join(failures, "\n  * "))))
+	audit.SetOverride(override, failures)
+	return true
+}
+
+// policyFailures checks the plan with the policies, the destroy limits and
+// the cost budget, and returns how it fails them.
+func (m *Meta) policyFailures(p *terraform.Plan) []string {
+	failures := m.checkPolicies(p)
+	if msg := m.checkDestroyLimit(p); msg != "" {
+		failures = append(failures, "destroy limit: "+msg)
+	}
+	if m.CostBudget > 0 {
+		cost, err := m.estimateCost(p)
+		switch {
+		case err != nil:
+			failures = append(failures, fmt.Sprintf("cost: %s", err))
+		case m.overBudget(cost):
+			failures = append(failures, fmt.Sprintf(
+				"cost: the estimated monthly cost of $%.2f exceeds the "+
+					"budget of $%.2f", cost.After, m.CostBudget))
+		}
+	}
+
+	return failures
+}
+
 func (c *ApplyCommand) Synopsis() string {
 	return "Builds or changes infrastructure"
 }
+
+const applyApproveQuery = `
+Do you want to perform these actions?
+Terraform will perform the actions described above.
+Only 'yes' will be accepted to approve.
+
+Enter a value:
+`
@@ -1,16 +1,23 @@
 package command
 
 import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/state"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/mitchellh/cli"
 )
@@ -28,6 +35,7 @@ func TestApply(t *testing.T) {
 	}
 
 	args := []string{
+		"-auto-approve",
 		"-state", statePath,
 		testFixturePath("apply"),
 	}
@@ -54,6 +62,550 @@ func TestApply(t *testing.T) {
 	}
 }
 
+func TestApply_readOnly(t *testing.T) {
+	statePath := testTempFile(t)
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &ApplyCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			ReadOnly:    true,
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-auto-approve",
+		"-state", statePath,
+		testFixturePath("apply"),
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.OutputWriter.String())
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "read-only mode") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+
+	if p.ApplyCalled {
+		t.Fatal("apply should not be called")
+	}
+	if _, err := os.Stat(statePath); err == nil {
+		t.Fatal("state should not be written")
+	}
+}
+
+func TestApply_auditLog(t *testing.T) {
+	statePath := testTempFile(t)
+	auditPath := testTempFile(t)
+	defer os.Remove(auditPath)
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &ApplyCommand{
+		Meta: Meta{
+			AuditLog:    &AuditLog{Sink: auditPath},
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-auto-approve",
+		"-state", statePath,
+		testFixturePath("apply"),
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	data, err := ioutil.ReadFile(auditPath)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var r AuditRecord
+	if err := json.Unmarshal(data, &r); err != nil {
+		t.Fatalf("err: %s\n\n%s", err, data)
+	}
+	if r.Command != "apply" {
+		t.Fatalf("bad: %#v", r)
+	}
+	if r.StatePath != statePath {
+		t.Fatalf("bad: %#v", r)
+	}
+	if r.Serial != 1 {
+		t.Fatalf("bad: %#v", r)
+	}
+	if r.Plan == nil || r.Plan.Add != 1 {
+		t.Fatalf("bad: %#v", r.Plan)
+	}
+	if len(r.Resources) != 1 || r.Resources[0].Action != "create" {
+		t.Fatalf("bad: %#v", r.Resources)
+	}
+}
+
+func TestApply_message(t *testing.T) {
+	statePath := testTempFile(t)
+	auditPath := testTempFile(t)
+	defer os.Remove(auditPath)
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &ApplyCommand{
+		Meta: Meta{
+			AuditLog:    &AuditLog{Sink: auditPath},
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-auto-approve",
+		"-message", "bump web ASG",
+		"-state", statePath,
+		testFixturePath("apply"),
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	f, err := os.Open(statePath)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer f.Close()
+
+	state, err := terraform.ReadState(f)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if state.Metadata["run_message"] != "bump web ASG" {
+		t.Fatalf("bad: %#v", state.Metadata)
+	}
+	if state.Metadata["run_id"] == "" || state.Metadata["run_user"] == "" {
+		t.Fatalf("bad: %#v", state.Metadata)
+	}
+
+	data, err := ioutil.ReadFile(auditPath)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// The audit log records the same run as the state
+	var r AuditRecord
+	if err := json.Unmarshal(data, &r); err != nil {
+		t.Fatalf("err: %s\n\n%s", err, data)
+	}
+	if r.Message != "bump web ASG" {
+		t.Fatalf("bad: %#v", r)
+	}
+	if r.RunID != state.Metadata["run_id"] {
+		t.Fatalf("bad: %#v", r)
+	}
+}
+
+func TestApply_locked(t *testing.T) {
+	statePath := testTempFile(t)
+	l, err := state.LockLocal(statePath, "alice@host")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer l.Unlock()
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &ApplyCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-auto-approve",
+		"-state", statePath,
+		testFixturePath("apply"),
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d", code)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "alice@host") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+	if p.ApplyCalled {
+		t.Fatal("apply should not be called")
+	}
+
+	// The lock can be skipped
+	ui = new(cli.MockUi)
+	c = &ApplyCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+	args = append([]string{"-lock=false"}, args...)
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+}
+
+func TestApply_policyFail(t *testing.T) {
+	statePath := testTempFile(t)
+	policy := testPolicy(t, "tags", `echo '{"verdict": "fail", "messages": ["no tags"]}'`)
+	defer os.Remove(policy.Command)
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &ApplyCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Policies:    []*Policy{policy},
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-auto-approve",
+		"-state", statePath,
+		testFixturePath("apply"),
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.OutputWriter.String())
+	}
+
+	if p.ApplyCalled {
+		t.Fatal("apply should not be called")
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "tags: no tags") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+	if _, err := os.Stat(statePath); err == nil {
+		t.Fatal("state should not be written")
+	}
+}
+
+func TestApply_destroyLimit(t *testing.T) {
+	// The resources in the state aren't in the configuration, so the
+	// plan destroys them
+	originalState := &terraform.State{
+		Resources: map[string]*terraform.ResourceState{
+			"test_instance.a": &terraform.ResourceState{
+				ID:   "a",
+				Type: "test_instance",
+			},
+			"test_instance.b": &terraform.ResourceState{
+				ID:   "b",
+				Type: "test_instance",
+			},
+		},
+	}
+	statePath := testStateFile(t, originalState)
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &ApplyCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+			MaxDestroy:  5,
+		},
+	}
+
+	args := []string{
+		"-auto-approve",
+		"-max-destroy", "1",
+		"-state", statePath,
+		testFixturePath("apply"),
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.OutputWriter.String())
+	}
+	if p.ApplyCalled {
+		t.Fatal("apply should not be called")
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "destroy limit: the plan destroys 2 resources") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+
+	// Overridden
+	ui = new(cli.MockUi)
+	c.Meta.Ui = ui
+	args = append([]string{"-override", "decommissioning"}, args...)
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if !p.ApplyCalled {
+		t.Fatal("apply should be called")
+	}
+}
+
+func TestApply_policyOverride(t *testing.T) {
+	statePath := testTempFile(t)
+	auditPath := testTempFile(t)
+	defer os.Remove(auditPath)
+	policy := testPolicy(t, "tags", `echo '{"verdict": "fail", "messages": ["no tags"]}'`)
+	defer os.Remove(policy.Command)
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &ApplyCommand{
+		Meta: Meta{
+			AuditLog:    &AuditLog{Sink: auditPath},
+			ContextOpts: testCtxConfig(p),
+			Policies:    []*Policy{policy},
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-auto-approve",
+		"-override", "emergency fix",
+		"-state", statePath,
+		testFixturePath("apply"),
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	if !p.ApplyCalled {
+		t.Fatal("apply should be called")
+	}
+
+	data, err := ioutil.ReadFile(auditPath)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var r AuditRecord
+	if err := json.Unmarshal(data, &r); err != nil {
+		t.Fatalf("err: %s\n\n%s", err, data)
+	}
+	if r.Override != "emergency fix" {
+		t.Fatalf("bad: %#v", r)
+	}
+	if !reflect.DeepEqual(r.PolicyFailures, []string{"tags: no tags"}) {
+		t.Fatalf("bad: %#v", r.PolicyFailures)
+	}
+}
+
+func TestApply_costBudget(t *testing.T) {
+	statePath := testTempFile(t)
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &ApplyCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+			CostEstimators: []CostEstimator{
+				&StaticCostEstimator{
+					Prices: map[string]*CostPrice{
+						"test_instance": &CostPrice{Fixed: 50},
+					},
+				},
+			},
+			CostBudget: 10,
+		},
+	}
+
+	args := []string{
+		"-auto-approve",
+		"-state", statePath,
+		testFixturePath("apply"),
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.OutputWriter.String())
+	}
+	if p.ApplyCalled {
+		t.Fatal("apply should not be called")
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "exceeds the budget") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+
+	// The budget can be overridden like policies
+	ui = new(cli.MockUi)
+	c.Meta.Ui = ui
+	args = append([]string{"-override", "launch"}, args...)
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if !p.ApplyCalled {
+		t.Fatal("apply should be called")
+	}
+}
+
+func TestApply_webhook(t *testing.T) {
+	statePath := testTempFile(t)
+
+	var l sync.Mutex
+	var events []*WebhookEvent
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			var e WebhookEvent
+			if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			l.Lock()
+			defer l.Unlock()
+			events = append(events, &e)
+		}))
+	defer ts.Close()
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &ApplyCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+			Webhooks:    []*Webhook{&Webhook{URL: ts.URL}},
+		},
+	}
+
+	args := []string{
+		"-auto-approve",
+		"-state", statePath,
+		testFixturePath("apply"),
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	l.Lock()
+	defer l.Unlock()
+	if len(events) != 2 {
+		t.Fatalf("bad: %#v", events)
+	}
+	if events[0].Event != "apply_start" || events[1].Event != "apply_complete" {
+		t.Fatalf("bad: %#v %#v", events[0], events[1])
+	}
+	if events[1].Changes == nil || events[1].Changes.Add != 1 {
+		t.Fatalf("bad: %#v", events[1].Changes)
+	}
+}
+
+func TestApply_sensitiveOutput(t *testing.T) {
+	statePath := testTempFile(t)
+
+	p := testProvider()
+	p.ApplyFn = func(
+		s *terraform.ResourceState,
+		d *terraform.ResourceDiff) (*terraform.ResourceState, error) {
+		return &terraform.ResourceState{
+			ID:         "foo",
+			Attributes: map[string]string{"ami": "ami-secret"},
+		}, nil
+	}
+
+	ui := new(cli.MockUi)
+	c := &ApplyCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-auto-approve",
+		"-state", statePath,
+		testFixturePath("apply-sensitive-output"),
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	output := ui.OutputWriter.String()
+	if strings.Contains(output, "ami-secret") {
+		t.Fatalf("sensitive value in output: %s", output)
+	}
+	if !strings.Contains(output, "ami = <sensitive>") {
+		t.Fatalf("bad: %s", output)
+	}
+
+	// The value is still in the state
+	f, err := os.Open(statePath)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer f.Close()
+
+	state, err := terraform.ReadState(f)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if state.Outputs["ami"] != "ami-secret" {
+		t.Fatalf("bad: %#v", state.Outputs)
+	}
+}
+
+func TestApply_approve(t *testing.T) {
+	statePath := testTempFile(t)
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	ui.InputReader = bytes.NewBufferString("yes\n")
+	c := &ApplyCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		testFixturePath("apply"),
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	output := ui.OutputWriter.String()
+	if !strings.Contains(output, "test_instance.foo") {
+		t.Fatalf("plan should be shown: %s", output)
+	}
+	if !strings.Contains(output, "Do you want to perform these actions?") {
+		t.Fatalf("should ask for approval: %s", output)
+	}
+	if !p.ApplyCalled {
+		t.Fatal("apply should be called")
+	}
+	if _, err := os.Stat(statePath); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestApply_approveCancel(t *testing.T) {
+	statePath := testTempFile(t)
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	ui.InputReader = bytes.NewBufferString("no\n")
+	c := &ApplyCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		testFixturePath("apply"),
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	if p.ApplyCalled {
+		t.Fatal("apply should not be called")
+	}
+	if _, err := os.Stat(statePath); err == nil {
+		t.Fatal("state should not be written")
+	}
+}
+
 func TestApply_configInvalid(t *testing.T) {
 	p := testProvider()
 	ui := new(cli.MockUi)
@@ -65,32 +617,350 @@ func TestApply_configInvalid(t *testing.T) {
 	}
 
 	args := []string{
-		"-state", testTempFile(t),
-		testFixturePath("apply-config-invalid"),
+		"-auto-approve",
+		"-state", testTempFile(t),
+		testFixturePath("apply-config-invalid"),
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: \n%s", ui.OutputWriter.String())
+	}
+}
+
+func TestApply_defaultState(t *testing.T) {
+	td, err := ioutil.TempDir("", "tf")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	statePath := filepath.Join(td, DefaultStateFilename)
+
+	// Change to the temporary directory
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := os.Chdir(filepath.Dir(statePath)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.Chdir(cwd)
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &ApplyCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-auto-approve",
+		testFixturePath("apply"),
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	if _, err := os.Stat(statePath); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	f, err := os.Open(statePath)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer f.Close()
+
+	state, err := terraform.ReadState(f)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if state == nil {
+		t.Fatal("state should not be nil")
+	}
+}
+
+func TestApply_error(t *testing.T) {
+	statePath := testTempFile(t)
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &ApplyCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	var lock sync.Mutex
+	errored := false
+	p.ApplyFn = func(
+		s *terraform.ResourceState,
+		d *terraform.ResourceDiff) (*terraform.ResourceState, error) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if !errored {
+			errored = true
+			return nil, fmt.Errorf("error")
+		}
+
+		return &terraform.ResourceState{ID: "foo"}, nil
+	}
+	p.DiffFn = func(
+		*terraform.ResourceState,
+		*terraform.ResourceConfig) (*terraform.ResourceDiff, error) {
+		return &terraform.ResourceDiff{
+			Attributes: map[string]*terraform.ResourceAttrDiff{
+				"ami": &terraform.ResourceAttrDiff{
+					New: "bar",
+				},
+			},
+		}, nil
+	}
+
+	args := []string{
+		"-auto-approve",
+		"-state", statePath,
+		testFixturePath("apply-error"),
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	if _, err := os.Stat(statePath); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	f, err := os.Open(statePath)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer f.Close()
+
+	state, err := terraform.ReadState(f)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if state == nil {
+		t.Fatal("state should not be nil")
+	}
+	if len(state.Resources) == 0 {
+		t.Fatal("no resources in state")
+	}
+}
+
+func TestApply_resume(t *testing.T) {
+	statePath := testTempFile(t)
+	resumePath := statePath + DefaultResumeExtension
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &ApplyCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	var lock sync.Mutex
+	fail := true
+	p.ApplyFn = func(
+		s *terraform.ResourceState,
+		d *terraform.ResourceDiff) (*terraform.ResourceState, error) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if fail && d.Attributes["error"] != nil {
+			return nil, fmt.Errorf("error")
+		}
+
+		result := &terraform.ResourceState{
+			ID:         "foo",
+			Attributes: make(map[string]string),
+		}
+		for k, ad := range d.Attributes {
+			result.Attributes[k] = ad.New
+		}
+
+		return result, nil
+	}
+	p.DiffFn = func(
+		s *terraform.ResourceState,
+		rc *terraform.ResourceConfig) (*terraform.ResourceDiff, error) {
+		result := &terraform.ResourceDiff{
+			Attributes: make(map[string]*terraform.ResourceAttrDiff),
+		}
+		for k, v := range rc.Config {
+			if s.Attributes[k] != v {
+				result.Attributes[k] = &terraform.ResourceAttrDiff{
+					Old: s.Attributes[k],
+					New: v.(string),
+				}
+			}
+		}
+
+		return result, nil
+	}
+
+	args := []string{
+		"-auto-approve",
+		"-state", statePath,
+		testFixturePath("apply-resume"),
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "apply -resume") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+
+	f, err := os.Open(resumePath)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	plan, err := terraform.ReadPlan(f)
+	f.Close()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := []string{"test_instance.foo"}
+	if !reflect.DeepEqual(plan.Completed, expected) {
+		t.Fatalf("bad: %#v", plan.Completed)
+	}
+
+	// Resume without failing, which applies only what failed
+	fail = false
+	p.ApplyCalled = false
+	p.RefreshCalled = false
+	ui = new(cli.MockUi)
+	c = &ApplyCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args = []string{
+		"-auto-approve",
+		"-resume",
+		"-state", statePath,
+		testFixturePath("apply-resume"),
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if p.RefreshCalled {
+		t.Fatal("refresh shouldn't be called")
+	}
+	if !strings.Contains(ui.OutputWriter.String(), "1 added") {
+		t.Fatalf("bad: %s", ui.OutputWriter.String())
+	}
+
+	f, err = os.Open(statePath)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	state, err := terraform.ReadState(f)
+	f.Close()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(state.Resources) != 2 {
+		t.Fatalf("bad: %#v", state.Resources)
+	}
+
+	if _, err := os.Stat(resumePath); !os.IsNotExist(err) {
+		t.Fatalf("resume plan should be removed: %s", err)
+	}
+}
+
+func TestApply_resumeNone(t *testing.T) {
+	statePath := testTempFile(t)
+
+	ui := new(cli.MockUi)
+	c := &ApplyCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-auto-approve",
+		"-resume",
+		"-state", statePath,
+		testFixturePath("apply"),
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d", code)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "no failed apply") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+}
+
+func TestApply_noArgs(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := os.Chdir(testFixturePath("plan")); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.Chdir(cwd)
+
+	statePath := testTempFile(t)
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &ApplyCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-auto-approve",
+		"-state", statePath,
 	}
-	if code := c.Run(args); code != 1 {
-		t.Fatalf("bad: \n%s", ui.OutputWriter.String())
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
 	}
-}
 
-func TestApply_defaultState(t *testing.T) {
-	td, err := ioutil.TempDir("", "tf")
-	if err != nil {
+	if _, err := os.Stat(statePath); err != nil {
 		t.Fatalf("err: %s", err)
 	}
-	statePath := filepath.Join(td, DefaultStateFilename)
 
-	// Change to the temporary directory
-	cwd, err := os.Getwd()
+	f, err := os.Open(statePath)
 	if err != nil {
 		t.Fatalf("err: %s", err)
 	}
-	if err := os.Chdir(filepath.Dir(statePath)); err != nil {
+	defer f.Close()
+
+	state, err := terraform.ReadState(f)
+	if err != nil {
 		t.Fatalf("err: %s", err)
 	}
-	defer os.Chdir(cwd)
+	if state == nil {
+		t.Fatal("state should not be nil")
+	}
+}
+
+func TestApply_missingForget(t *testing.T) {
+	statePath := testStateFile(t, &terraform.State{
+		Resources: map[string]*terraform.ResourceState{
+			"test_instance.foo": &terraform.ResourceState{
+				ID:   "bar",
+				Type: "test_instance",
+			},
+		},
+	})
 
 	p := testProvider()
+	p.RefreshFn = nil
+	p.RefreshReturn = nil
+
 	ui := new(cli.MockUi)
 	c := &ApplyCommand{
 		Meta: Meta{
@@ -100,14 +970,16 @@ func TestApply_defaultState(t *testing.T) {
 	}
 
 	args := []string{
+		"-auto-approve",
+		"-forget", "test_instance.foo",
+		"-state", statePath,
 		testFixturePath("apply"),
 	}
 	if code := c.Run(args); code != 0 {
 		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
 	}
-
-	if _, err := os.Stat(statePath); err != nil {
-		t.Fatalf("err: %s", err)
+	if p.ApplyCalled {
+		t.Fatal("apply should not be called")
 	}
 
 	f, err := os.Open(statePath)
@@ -120,13 +992,15 @@ func TestApply_defaultState(t *testing.T) {
 	if err != nil {
 		t.Fatalf("err: %s", err)
 	}
-	if state == nil {
-		t.Fatal("state should not be nil")
+	if len(state.Resources) > 0 || len(state.Missing) > 0 {
+		t.Fatalf("bad: %#v", state)
 	}
 }
 
-func TestApply_error(t *testing.T) {
-	statePath := testTempFile(t)
+func TestApply_missingForgetPlan(t *testing.T) {
+	planPath := testPlanFile(t, &terraform.Plan{
+		Config: new(config.Config),
+	})
 
 	p := testProvider()
 	ui := new(cli.MockUi)
@@ -137,38 +1011,39 @@ func TestApply_error(t *testing.T) {
 		},
 	}
 
-	var lock sync.Mutex
-	errored := false
-	p.ApplyFn = func(
-		s *terraform.ResourceState,
-		d *terraform.ResourceDiff) (*terraform.ResourceState, error) {
-		lock.Lock()
-		defer lock.Unlock()
+	args := []string{
+		"-forget", "test_instance.foo",
+		"-state", testTempFile(t),
+		planPath,
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d", code)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "-forget") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+}
 
-		if !errored {
-			errored = true
-			return nil, fmt.Errorf("error")
-		}
+func TestApply_plan(t *testing.T) {
+	planPath := testPlanFile(t, &terraform.Plan{
+		Config: new(config.Config),
+	})
+	statePath := testTempFile(t)
 
-		return &terraform.ResourceState{ID: "foo"}, nil
-	}
-	p.DiffFn = func(
-		*terraform.ResourceState,
-		*terraform.ResourceConfig) (*terraform.ResourceDiff, error) {
-		return &terraform.ResourceDiff{
-			Attributes: map[string]*terraform.ResourceAttrDiff{
-				"ami": &terraform.ResourceAttrDiff{
-					New: "bar",
-				},
-			},
-		}, nil
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &ApplyCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
 	}
 
 	args := []string{
 		"-state", statePath,
-		testFixturePath("apply-error"),
+		planPath,
 	}
-	if code := c.Run(args); code != 1 {
+	if code := c.Run(args); code != 0 {
 		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
 	}
 
@@ -189,21 +1064,112 @@ func TestApply_error(t *testing.T) {
 	if state == nil {
 		t.Fatal("state should not be nil")
 	}
-	if len(state.Resources) == 0 {
-		t.Fatal("no resources in state")
+}
+
+func TestApply_approval(t *testing.T) {
+	planPath := testPlanFile(t, &terraform.Plan{
+		Config: new(config.Config),
+	})
+	statePath := testTempFile(t)
+
+	key := testApprovalKey(t)
+	approvers := map[string]*ecdsa.PublicKey{"alice": &key.PublicKey}
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &ApplyCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+			Approvers:   approvers,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		planPath,
+	}
+
+	// Without an approval
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "Error reading approval") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+
+	plan, err := ioutil.ReadFile(planPath)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	a, err := NewApproval("alice", plan, key)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := WriteApproval(planPath+ApprovalExtension, a); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.Remove(planPath + ApprovalExtension)
+
+	ui = new(cli.MockUi)
+	c.Meta.Ui = ui
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if _, err := os.Stat(statePath); err != nil {
+		t.Fatalf("err: %s", err)
 	}
 }
 
-func TestApply_noArgs(t *testing.T) {
-	cwd, err := os.Getwd()
+func TestApply_approvalSelf(t *testing.T) {
+	planPath := testPlanFile(t, &terraform.Plan{
+		Config: new(config.Config),
+	})
+	statePath := testTempFile(t)
+
+	// The user applying the plan can't approve it
+	key := testApprovalKey(t)
+	plan, err := ioutil.ReadFile(planPath)
 	if err != nil {
 		t.Fatalf("err: %s", err)
 	}
-	if err := os.Chdir(testFixturePath("plan")); err != nil {
+	a, err := NewApproval(auditUser(), plan, key)
+	if err != nil {
 		t.Fatalf("err: %s", err)
 	}
-	defer os.Chdir(cwd)
+	if err := WriteApproval(planPath+ApprovalExtension, a); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.Remove(planPath + ApprovalExtension)
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &ApplyCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+			Approvers: map[string]*ecdsa.PublicKey{
+				auditUser(): &key.PublicKey,
+			},
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		planPath,
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "someone\nother than") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+	if _, err := os.Stat(statePath); err == nil {
+		t.Fatal("state should not be written")
+	}
+}
 
+func TestApply_approvalUnplanned(t *testing.T) {
 	statePath := testTempFile(t)
 
 	p := testProvider()
@@ -212,18 +1178,52 @@ func TestApply_noArgs(t *testing.T) {
 		Meta: Meta{
 			ContextOpts: testCtxConfig(p),
 			Ui:          ui,
+			Approvers: map[string]*ecdsa.PublicKey{
+				"alice": &testApprovalKey(t).PublicKey,
+			},
 		},
 	}
 
 	args := []string{
+		"-auto-approve",
 		"-state", statePath,
+		testFixturePath("apply"),
 	}
-	if code := c.Run(args); code != 0 {
+	if code := c.Run(args); code != 1 {
 		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
 	}
+	if p.ApplyCalled {
+		t.Fatal("apply should not be called")
+	}
+}
 
-	if _, err := os.Stat(statePath); err != nil {
-		t.Fatalf("err: %s", err)
+func TestApply_recordGit(t *testing.T) {
+	planPath := testPlanFile(t, &terraform.Plan{
+		Config: new(config.Config),
+		Git: map[string]string{
+			"commit":       "0123456789abcdef",
+			"short_commit": "0123456",
+			"branch":       "master",
+		},
+	})
+	statePath := testTempFile(t)
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &ApplyCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-record-git",
+		"-state", statePath,
+		planPath,
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
 	}
 
 	f, err := os.Open(statePath)
@@ -236,12 +1236,19 @@ func TestApply_noArgs(t *testing.T) {
 	if err != nil {
 		t.Fatalf("err: %s", err)
 	}
-	if state == nil {
-		t.Fatal("state should not be nil")
+
+	expected := map[string]string{
+		"git_commit": "0123456789abcdef",
+		"git_branch": "master",
+	}
+	for k, v := range expected {
+		if state.Metadata[k] != v {
+			t.Fatalf("bad: %#v", state.Metadata)
+		}
 	}
 }
 
-func TestApply_plan(t *testing.T) {
+func TestApply_recordGitNone(t *testing.T) {
 	planPath := testPlanFile(t, &terraform.Plan{
 		Config: new(config.Config),
 	})
@@ -257,15 +1264,15 @@ func TestApply_plan(t *testing.T) {
 	}
 
 	args := []string{
+		"-record-git",
 		"-state", statePath,
 		planPath,
 	}
 	if code := c.Run(args); code != 0 {
 		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
 	}
-
-	if _, err := os.Stat(statePath); err != nil {
-		t.Fatalf("err: %s", err)
+	if !strings.Contains(ui.OutputWriter.String(), "git repository") {
+		t.Fatalf("bad: %s", ui.OutputWriter.String())
 	}
 
 	f, err := os.Open(statePath)
@@ -278,8 +1285,10 @@ func TestApply_plan(t *testing.T) {
 	if err != nil {
 		t.Fatalf("err: %s", err)
 	}
-	if state == nil {
-		t.Fatal("state should not be nil")
+	for k, _ := range state.Metadata {
+		if strings.HasPrefix(k, "git_") {
+			t.Fatalf("bad: %#v", state.Metadata)
+		}
 	}
 }
 
@@ -387,6 +1396,7 @@ func TestApply_refresh(t *testing.T) {
 	}
 
 	args := []string{
+		"-auto-approve",
 		"-state", statePath,
 		testFixturePath("apply"),
 	}
@@ -493,6 +1503,7 @@ func TestApply_shutdown(t *testing.T) {
 	}()
 
 	args := []string{
+		"-auto-approve",
 		"-state", statePath,
 		testFixturePath("apply-shutdown"),
 	}
@@ -555,6 +1566,7 @@ func TestApply_state(t *testing.T) {
 
 	// Run the apply command pointing to our existing state
 	args := []string{
+		"-auto-approve",
 		"-state", statePath,
 		testFixturePath("apply"),
 	}
@@ -622,6 +1634,7 @@ func TestApply_stateNoExist(t *testing.T) {
 	}
 
 	args := []string{
+		"-auto-approve",
 		"idontexist.tfstate",
 		testFixturePath("apply"),
 	}
@@ -654,6 +1667,7 @@ func TestApply_vars(t *testing.T) {
 	}
 
 	args := []string{
+		"-auto-approve",
 		"-var", "foo=bar",
 		"-state", statePath,
 		testFixturePath("apply-vars"),
@@ -696,6 +1710,7 @@ func TestApply_varFile(t *testing.T) {
 	}
 
 	args := []string{
+		"-auto-approve",
 		"-var-file", varFilePath,
 		"-state", statePath,
 		testFixturePath("apply-vars"),
@@ -748,6 +1763,7 @@ func TestApply_varFileDefault(t *testing.T) {
 	}
 
 	args := []string{
+		"-auto-approve",
 		"-state", statePath,
 		testFixturePath("apply-vars"),
 	}
@@ -792,6 +1808,7 @@ func TestApply_backup(t *testing.T) {
 
 	// Run the apply command pointing to our existing state
 	args := []string{
+		"-auto-approve",
 		"-state", statePath,
 		"-backup", backupPath,
 		testFixturePath("apply"),
@@ -870,6 +1887,7 @@ func TestApply_disableBackup(t *testing.T) {
 
 	// Run the apply command pointing to our existing state
 	args := []string{
+		"-auto-approve",
 		"-state", statePath,
 		"-backup", "-",
 		testFixturePath("apply"),
@@ -917,3 +1935,47 @@ func TestApply_disableBackup(t *testing.T) {
 const applyVarFile = `
 foo = "bar"
 `
+
+func TestApply_newerState(t *testing.T) {
+	statePath := testNewerStateFile(t)
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &ApplyCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-auto-approve",
+		"-state", statePath,
+		testFixturePath("apply"),
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d", code)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "newer") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+	if p.ApplyCalled {
+		t.Fatal("apply should not be called")
+	}
+
+	// The check can be overridden
+	ui = new(cli.MockUi)
+	c = &ApplyCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+	args = append([]string{"-override", "reading old outputs"}, args...)
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "Warning") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+}
@@ -0,0 +1,229 @@
+package command
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ApprovalExtension is added to the path of a plan to form the default
+// path of its approval.
+const ApprovalExtension = ".approval"
+
+// Approval is the approval of a saved plan by someone other than the
+// person applying it. When approvers are configured with "approver"
+// blocks in the CLI configuration, only approved plans can be applied.
+//
+// Approvals are signed with the ECDSA private key of the approver, and
+// verified with their public key from the CLI configuration.
+type Approval struct {
+	Approver string    `json:"approver"`
+	Plan     string    `json:"plan"`
+	Time     time.Time `json:"time"`
+
+	// Signature is the ASN.1 encoded ECDSA signature of the approver,
+	// plan and time.
+	Signature []byte `json:"signature"`
+}
+
+// ecdsaSignature is the ASN.1 structure of an ECDSA signature.
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+// NewApproval returns the approval of the plan file with the given
+// contents by the given approver, signed with their private key.
+func NewApproval(
+	approver string, plan []byte, key *ecdsa.PrivateKey) (*Approval, error) {
+	result := &Approval{
+		Approver: approver,
+		Plan:     planHash(plan),
+		Time:     time.Now().UTC(),
+	}
+
+	r, s, err := ecdsa.Sign(rand.Reader, key, result.digest())
+	if err != nil {
+		return nil, err
+	}
+
+	result.Signature, err = asn1.Marshal(ecdsaSignature{r, s})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Verify checks that this is a valid approval of the plan file with the
+// given contents, signed by one of the approvers.
+func (a *Approval) Verify(
+	plan []byte, approvers map[string]*ecdsa.PublicKey) error {
+	key, ok := approvers[a.Approver]
+	if !ok {
+		return fmt.Errorf("%s is not an approver", a.Approver)
+	}
+
+	if a.Plan != planHash(plan) {
+		return errors.New("the approval is for a different plan")
+	}
+
+	var sig ecdsaSignature
+	if _, err := asn1.Unmarshal(a.Signature, &sig); err != nil {
+		return fmt.Errorf("invalid signature: %s", err)
+	}
+	if sig.R == nil || sig.S == nil ||
+		!ecdsa.Verify(key, a.digest(), sig.R, sig.S) {
+		return fmt.Errorf("the signature of %s is invalid", a.Approver)
+	}
+
+	return nil
+}
+
+// digest is the hash of the approval that is signed.
+func (a *Approval) digest() []byte {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n%s\n",
+		a.Approver, a.Plan, a.Time.UTC().Format(time.RFC3339Nano))
+	return h.Sum(nil)
+}
+
+// planHash returns the SHA-256 hash of a plan file, in hex.
+func planHash(plan []byte) string {
+	sum := sha256.Sum256(plan)
+	return hex.EncodeToString(sum[:])
+}
+
+// ReadApproval reads an approval from the given path.
+func ReadApproval(path string) (*Approval, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var result Approval
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("Error decoding approval %s: %s", path, err)
+	}
+
+	return &result, nil
+}
+
+// WriteApproval writes an approval to the given path.
+func WriteApproval(path string, a *Approval) error {
+	data, err := json.MarshalIndent(a, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// LoadApprovalKey loads the public key of an approver from a PEM file,
+// such as one created with "openssl ec -pubout".
+func LoadApprovalKey(path string) (*ecdsa.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing %s: %s", path, err)
+	}
+
+	result, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("Error parsing %s: not an ECDSA key", path)
+	}
+
+	return result, nil
+}
+
+// loadApprovalPrivateKey loads the private key of an approver from a PEM
+// file, such as one created with "openssl ecparam -genkey".
+func loadApprovalPrivateKey(path string) (*ecdsa.PrivateKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing %s: %s", path, err)
+	}
+
+	return key, nil
+}
+
+// readPEM reads the first PEM block of a file that isn't the parameters
+// of an elliptic curve, which openssl writes before private keys.
+func readPEM(path string) (*pem.Block, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading %s: %s", path, err)
+	}
+
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("Error parsing %s: no key found", path)
+		}
+		if block.Type != "EC PARAMETERS" {
+			return block, nil
+		}
+	}
+}
+
+// checkApproval checks that the plan file at the given path can be
+// applied. If there are approvers, it must have been approved by one of
+// them other than the user applying it. It returns the approver, which
+// is empty if there are no approvers.
+func (m *Meta) checkApproval(planPath, approvalPath string) (string, error) {
+	if len(m.Approvers) == 0 {
+		return "", nil
+	}
+
+	plan, err := ioutil.ReadFile(planPath)
+	if err != nil {
+		return "", fmt.Errorf("Error reading plan: %s", err)
+	}
+
+	a, err := ReadApproval(approvalPath)
+	if err != nil {
+		return "", fmt.Errorf("Error reading approval: %s", err)
+	}
+	if err := a.Verify(plan, m.Approvers); err != nil {
+		return "", fmt.Errorf("Invalid approval %s: %s", approvalPath, err)
+	}
+	if a.Approver == auditUser() {
+		return "", fmt.Errorf(
+			"Invalid approval %s: the plan must be approved by someone\n"+
+				"other than the user applying it.", approvalPath)
+	}
+
+	return a.Approver, nil
+}
+
+// approverNames returns the names of the approvers, sorted.
+func (m *Meta) approverNames() string {
+	names := make([]string, 0, len(m.Approvers))
+	for n, _ := range m.Approvers {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	return strings.Join(names, ", ")
+}
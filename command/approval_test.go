@@ -0,0 +1,151 @@
+package command
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestApproval(t *testing.T) {
+	key := testApprovalKey(t)
+	other := testApprovalKey(t)
+	approvers := map[string]*ecdsa.PublicKey{
+		"alice": &key.PublicKey,
+		"bob":   &other.PublicKey,
+	}
+
+	plan := []byte("plan")
+	a, err := NewApproval("alice", plan, key)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := a.Verify(plan, approvers); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// A different plan
+	if err := a.Verify([]byte("other plan"), approvers); err == nil {
+		t.Fatal("should error")
+	}
+
+	// An unknown approver
+	if err := a.Verify(plan, map[string]*ecdsa.PublicKey{}); err == nil {
+		t.Fatal("should error")
+	}
+
+	// Signed by someone else
+	forged := *a
+	forged.Approver = "bob"
+	if err := forged.Verify(plan, approvers); err == nil {
+		t.Fatal("should error")
+	}
+
+	// Tampered with
+	forged = *a
+	forged.Time = forged.Time.Add(1)
+	if err := forged.Verify(plan, approvers); err == nil {
+		t.Fatal("should error")
+	}
+	forged = *a
+	forged.Signature = []byte("bad")
+	if err := forged.Verify(plan, approvers); err == nil {
+		t.Fatal("should error")
+	}
+}
+
+func TestApproval_readWrite(t *testing.T) {
+	key := testApprovalKey(t)
+	a, err := NewApproval("alice", []byte("plan"), key)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	path := testTempFile(t)
+	defer os.Remove(path)
+	if err := WriteApproval(path, a); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual, err := ReadApproval(path)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !reflect.DeepEqual(actual, a) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestLoadApprovalKey(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	key := testApprovalKey(t)
+	privPath, pubPath := testApprovalKeyFiles(t, td, key)
+
+	pub, err := LoadApprovalKey(pubPath)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if pub.X.Cmp(key.X) != 0 || pub.Y.Cmp(key.Y) != 0 {
+		t.Fatalf("bad: %#v", pub)
+	}
+
+	priv, err := loadApprovalPrivateKey(privPath)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if priv.D.Cmp(key.D) != 0 {
+		t.Fatal("bad key")
+	}
+
+	// A private key isn't a public key
+	if _, err := LoadApprovalKey(privPath); err == nil {
+		t.Fatal("should error")
+	}
+}
+
+// testApprovalKey returns a new ECDSA key for approvals.
+func testApprovalKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return key
+}
+
+// testApprovalKeyFiles writes the key to PEM files in the directory, in
+// the formats openssl writes them, and returns their paths.
+func testApprovalKeyFiles(
+	t *testing.T, dir string, key *ecdsa.PrivateKey) (string, string) {
+	priv, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	pub, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	privPath := filepath.Join(dir, "key.pem")
+	pubPath := filepath.Join(dir, "key.pub")
+	privData := append(
+		pem.EncodeToMemory(&pem.Block{Type: "EC PARAMETERS", Bytes: []byte{}}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: priv})...)
+	if err := ioutil.WriteFile(privPath, privData, 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	pubData := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub})
+	if err := ioutil.WriteFile(pubPath, pubData, 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return privPath, pubPath
+}
@@ -0,0 +1,125 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// ApproveCommand is a Command implementation that approves a saved plan
+// so that someone else can apply it.
+type ApproveCommand struct {
+	Meta
+}
+
+func (c *ApproveCommand) Run(args []string) int {
+	var approver, keyPath, outPath string
+
+	args = c.Meta.process(args, false)
+
+	cmdFlags := c.Meta.flagSet("approve")
+	cmdFlags.StringVar(&approver, "approver", auditUser(), "name")
+	cmdFlags.StringVar(&keyPath, "key", "", "path")
+	cmdFlags.StringVar(&outPath, "out", "", "path")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = cmdFlags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("The approve command expects exactly one argument with\n" +
+			"the path to a saved plan.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+	if keyPath == "" {
+		c.Ui.Error("The -key flag with the path to your private key is required.")
+		return 1
+	}
+
+	planPath := c.path(args[0])
+	if outPath == "" {
+		outPath = planPath + ApprovalExtension
+	}
+	outPath = c.path(outPath)
+
+	data, err := ioutil.ReadFile(planPath)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading plan: %s", err))
+		return 1
+	}
+	plan, err := terraform.ReadPlan(bytes.NewReader(data))
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading plan: %s", err))
+		return 1
+	}
+
+	key, err := loadApprovalPrivateKey(c.path(keyPath))
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	// Only the approver's own key can sign for them, which is checked
+	// when the plan is applied. Checking it here fails early.
+	if pub, ok := c.Approvers[approver]; ok {
+		if pub.X.Cmp(key.X) != 0 || pub.Y.Cmp(key.Y) != 0 {
+			c.Ui.Error(fmt.Sprintf(
+				"The key %s isn't the key of approver %s.", keyPath, approver))
+			return 1
+		}
+	}
+
+	a, err := NewApproval(approver, data, key)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error signing approval: %s", err))
+		return 1
+	}
+	if err := WriteApproval(outPath, a); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error writing approval: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(FormatPlan(plan, c.Colorize()))
+	c.Ui.Output(fmt.Sprintf(
+		"The plan above was approved by %s. The approval was written to\n"+
+			"%s, where apply will look for it.", approver, outPath))
+	return 0
+}
+
+func (c *ApproveCommand) Help() string {
+	helpText := `
+Usage: terraform approve [options] PLAN
+
+  Approves a saved plan, so that it can be applied by someone else when
+  approvers are configured with "approver" blocks in the CLI configuration.
+  The approval is signed with your private key, and written next to the
+  plan with the ".approval" extension.
+
+  Approving a plan approves exactly that plan file. If the plan is created
+  again, it must be approved again.
+
+Options:
+
+  -approver=name      Your name, as in the CLI configuration of the person
+                      applying the plan. Defaults to your user name.
+
+  -key=path           Path to your ECDSA private key, in PEM format.
+                      Required.
+
+  -no-color           If specified, output won't contain any color.
+
+  -out=path           Path to write the approval to. Defaults to the path
+                      of the plan with the ".approval" extension.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ApproveCommand) Synopsis() string {
+	return "Approve a saved plan for someone else to apply"
+}
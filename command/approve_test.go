@@ -0,0 +1,89 @@
+package command
+
+import (
+	"crypto/ecdsa"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+)
+
+func TestApprove(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	key := testApprovalKey(t)
+	keyPath, _ := testApprovalKeyFiles(t, td, key)
+	planPath := testPlanFile(t, &terraform.Plan{
+		Config: new(config.Config),
+	})
+	defer os.Remove(planPath)
+	defer os.Remove(planPath + ApprovalExtension)
+
+	ui := new(cli.MockUi)
+	c := &ApproveCommand{
+		Meta: Meta{
+			Ui: ui,
+			Approvers: map[string]*ecdsa.PublicKey{
+				"alice": &key.PublicKey,
+			},
+		},
+	}
+
+	args := []string{
+		"-approver", "alice",
+		"-key", keyPath,
+		planPath,
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	a, err := ReadApproval(planPath + ApprovalExtension)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	plan, err := ioutil.ReadFile(planPath)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := a.Verify(plan, c.Approvers); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestApprove_wrongKey(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	keyPath, _ := testApprovalKeyFiles(t, td, testApprovalKey(t))
+	planPath := testPlanFile(t, &terraform.Plan{
+		Config: new(config.Config),
+	})
+	defer os.Remove(planPath)
+
+	ui := new(cli.MockUi)
+	c := &ApproveCommand{
+		Meta: Meta{
+			Ui: ui,
+			Approvers: map[string]*ecdsa.PublicKey{
+				"alice": &testApprovalKey(t).PublicKey,
+			},
+		},
+	}
+
+	args := []string{
+		"-approver", "alice",
+		"-key", keyPath,
+		planPath,
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if _, err := os.Stat(planPath + ApprovalExtension); err == nil {
+		t.Fatal("approval should not be written")
+	}
+}
@@ -0,0 +1,322 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/user"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// AuditLog records every run of a command that changes the state, for
+// change tracking. It is configured with "audit_log" in the CLI
+// configuration.
+type AuditLog struct {
+	// Sink is where records are written. It can be the path of a file
+	// that records are appended to as JSON lines, "syslog" to send them
+	// to the system log, or an HTTP or HTTPS URL that each record is
+	// POSTed to. If it is empty, nothing is recorded.
+	Sink string
+}
+
+// AuditRecord is a single entry in the audit log.
+type AuditRecord struct {
+	Time      time.Time        `json:"time"`
+	RunID     string           `json:"run_id"`
+	User      string           `json:"user"`
+	Host      string           `json:"host"`
+	Command   string           `json:"command"`
+	Args      []string         `json:"args"`
+	Dir       string           `json:"dir"`
+	Plan      *AuditPlan       `json:"plan,omitempty"`
+	Resources []*AuditResource `json:"resources,omitempty"`
+	StatePath string           `json:"state_path"`
+	Serial    int64            `json:"serial"`
+	Error     string           `json:"error,omitempty"`
+
+	// Message is why the command was run, as given with -message.
+	Message string `json:"message,omitempty"`
+
+	// PolicyFailures are the failures of policies that were overridden
+	// with the reason in Override.
+	PolicyFailures []string `json:"policy_failures,omitempty"`
+	Override       string   `json:"override,omitempty"`
+
+	// Approver is who approved the plan, if approvals are required.
+	Approver string `json:"approver,omitempty"`
+}
+
+// AuditPlan summarizes the plan that was applied.
+type AuditPlan struct {
+	Add     int `json:"add"`
+	Change  int `json:"change"`
+	Destroy int `json:"destroy"`
+}
+
+// AuditResource is what was done to a single resource.
+type AuditResource struct {
+	Resource string `json:"resource"`
+	Action   string `json:"action"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Write writes a record to the sink of the audit log.
+func (l *AuditLog) Write(r *AuditRecord) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case l.Sink == "syslog":
+		return writeSyslog(data)
+	case strings.HasPrefix(l.Sink, "http://"),
+		strings.HasPrefix(l.Sink, "https://"):
+		resp, err := http.Post(l.Sink, "application/json", bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("%s returned status %d", l.Sink, resp.StatusCode)
+		}
+
+		return nil
+	default:
+		f, err := os.OpenFile(
+			l.Sink, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return err
+		}
+
+		_, err = f.Write(append(data, '\n'))
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+
+		return err
+	}
+}
+
+// auditPlan summarizes a plan for the audit log.
+func auditPlan(p *terraform.Plan) *AuditPlan {
+	result := new(AuditPlan)
+	if p.Diff == nil {
+		return result
+	}
+
+	for name, rd := range p.Diff.Resources {
+		var s *terraform.ResourceState
+		if p.State != nil {
+			s = p.State.Resources[name]
+		}
+
+		switch auditAction(s, rd) {
+		case "create":
+			result.Add++
+		case "replace":
+			result.Add++
+			result.Destroy++
+		case "destroy":
+			result.Destroy++
+		default:
+			result.Change++
+		}
+	}
+
+	return result
+}
+
+// auditAction returns the name of what applying a diff does to a
+// resource, as shown in the audit log.
+func auditAction(s *terraform.ResourceState, d *terraform.ResourceDiff) string {
+	switch {
+	case d.Destroy && d.RequiresNew():
+		return "replace"
+	case d.Destroy:
+		return "destroy"
+	case d.RequiresNew() || s == nil || s.ID == "":
+		return "create"
+	default:
+		return "update"
+	}
+}
+
+// auditUser returns the name of the user running Terraform.
+func auditUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+
+	return os.Getenv("USER")
+}
+
+// AuditHook is a hook that records what is done to each resource, for
+// the audit log.
+type AuditHook struct {
+	Resources []*AuditResource
+
+	pending map[string]*AuditResource
+
+	sync.Mutex
+	terraform.NilHook
+}
+
+func (h *AuditHook) PreApply(
+	id string,
+	s *terraform.ResourceState,
+	d *terraform.ResourceDiff) (terraform.HookAction, error) {
+	h.Lock()
+	defer h.Unlock()
+
+	if h.pending == nil {
+		h.pending = make(map[string]*AuditResource)
+	}
+
+	h.pending[id] = &AuditResource{
+		Resource: id,
+		Action:   auditAction(s, d),
+	}
+
+	return terraform.HookActionContinue, nil
+}
+
+func (h *AuditHook) PostApply(
+	id string,
+	s *terraform.ResourceState,
+	e error) (terraform.HookAction, error) {
+	h.Lock()
+	defer h.Unlock()
+
+	if r, ok := h.pending[id]; ok {
+		delete(h.pending, id)
+
+		if e != nil {
+			r.Error = e.Error()
+		}
+		h.Resources = append(h.Resources, r)
+	}
+
+	return terraform.HookActionContinue, nil
+}
+
+func (h *AuditHook) PostRefresh(
+	id string, s *terraform.ResourceState) (terraform.HookAction, error) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.Resources = append(h.Resources, &AuditResource{
+		Resource: id,
+		Action:   "refresh",
+	})
+
+	return terraform.HookActionContinue, nil
+}
+
+// auditRun records a single run of a command for the audit log.
+type auditRun struct {
+	Record *AuditRecord
+	Hook   *AuditHook
+
+	meta *Meta
+}
+
+// startAudit starts recording a run of the command with the given name
+// and arguments for the audit log. It returns nil if there is no audit
+// log, and the methods of auditRun do nothing on nil.
+//
+// This adds a hook, so it must be called after extraHooks is set.
+func (m *Meta) startAudit(name string, args []string) *auditRun {
+	if m.AuditLog == nil || m.AuditLog.Sink == "" {
+		return nil
+	}
+
+	dir, _ := m.wd()
+	host, _ := os.Hostname()
+	a := &auditRun{
+		Record: &AuditRecord{
+			Time:    time.Now().UTC(),
+			RunID:   m.runID,
+			User:    auditUser(),
+			Host:    host,
+			Command: name,
+			Args:    args,
+			Dir:     dir,
+		},
+		Hook: new(AuditHook),
+		meta: m,
+	}
+	m.extraHooks = append(m.extraHooks, a.Hook)
+
+	return a
+}
+
+// SetPlan records the plan that is being applied.
+func (a *auditRun) SetPlan(p *terraform.Plan) {
+	if a == nil || p == nil {
+		return
+	}
+
+	a.Record.Plan = auditPlan(p)
+}
+
+// SetApprover records who approved the plan that is being applied.
+func (a *auditRun) SetApprover(approver string) {
+	if a == nil {
+		return
+	}
+
+	a.Record.Approver = approver
+}
+
+// SetOverride records that the plan was applied despite the failures of
+// policies, for the given reason.
+func (a *auditRun) SetOverride(reason string, failures []string) {
+	if a == nil {
+		return
+	}
+
+	a.Record.Override = reason
+	a.Record.PolicyFailures = failures
+}
+
+// Finish writes the record of the run, with the state it resulted in and
+// the error that it ended with, if any. Failing to write the record is
+// reported, but doesn't fail the command since the state has already
+// been changed.
+func (a *auditRun) Finish(statePath string, s *terraform.State, err error) {
+	if a == nil {
+		return
+	}
+
+	a.Hook.Lock()
+	a.Record.Resources = a.Hook.Resources
+	a.Hook.Unlock()
+
+	a.Record.StatePath = statePath
+	a.Record.Message = a.meta.message
+	if s != nil {
+		a.Record.Serial = s.Serial
+	}
+	if err != nil {
+		a.Record.Error = err.Error()
+	}
+
+	// The arguments can contain the values of sensitive variables
+	args := make([]string, len(a.Record.Args))
+	for i, arg := range a.Record.Args {
+		args[i] = a.meta.redactor.Redact(arg)
+	}
+	a.Record.Args = args
+
+	if err := a.meta.AuditLog.Write(a.Record); err != nil {
+		a.meta.Ui.Error(fmt.Sprintf("Error writing audit log: %s", err))
+	}
+}
@@ -0,0 +1,18 @@
+// +build darwin freebsd linux netbsd openbsd
+
+package command
+
+import (
+	"log/syslog"
+)
+
+// writeSyslog writes an audit record or an event to the system log.
+func writeSyslog(data []byte) error {
+	w, err := syslog.New(syslog.LOG_NOTICE|syslog.LOG_USER, "terraform")
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	return w.Notice(string(data))
+}
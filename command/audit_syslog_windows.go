@@ -0,0 +1,13 @@
+// +build windows
+
+package command
+
+import (
+	"errors"
+)
+
+// writeSyslog writes an audit record or an event to the system log,
+// which Windows doesn't have.
+func writeSyslog(data []byte) error {
+	return errors.New("syslog isn't supported on Windows")
+}
@@ -0,0 +1,128 @@
+package command
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAuditHook_impl(t *testing.T) {
+	var _ terraform.Hook = new(AuditHook)
+}
+
+func TestAuditLogWrite_file(t *testing.T) {
+	path := testTempFile(t)
+	defer os.Remove(path)
+
+	l := &AuditLog{Sink: path}
+	for _, name := range []string{"apply", "refresh"} {
+		if err := l.Write(&AuditRecord{Command: name}); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("bad: %s", data)
+	}
+
+	var r AuditRecord
+	if err := json.Unmarshal([]byte(lines[1]), &r); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if r.Command != "refresh" {
+		t.Fatalf("bad: %#v", r)
+	}
+}
+
+func TestAuditLogWrite_http(t *testing.T) {
+	var r AuditRecord
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			if req.Method != "POST" {
+				t.Fatalf("bad method: %s", req.Method)
+			}
+			if err := json.NewDecoder(req.Body).Decode(&r); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+		}))
+	defer ts.Close()
+
+	l := &AuditLog{Sink: ts.URL}
+	if err := l.Write(&AuditRecord{Command: "apply", Serial: 3}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if r.Command != "apply" || r.Serial != 3 {
+		t.Fatalf("bad: %#v", r)
+	}
+}
+
+func TestAuditLogWrite_httpError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(500)
+		}))
+	defer ts.Close()
+
+	l := &AuditLog{Sink: ts.URL}
+	if err := l.Write(&AuditRecord{Command: "apply"}); err == nil {
+		t.Fatal("should error")
+	}
+}
+
+func TestAuditPlan(t *testing.T) {
+	p := &terraform.Plan{
+		Diff: &terraform.Diff{
+			Resources: map[string]*terraform.ResourceDiff{
+				"aws_instance.new": &terraform.ResourceDiff{
+					Attributes: map[string]*terraform.ResourceAttrDiff{
+						"ami": &terraform.ResourceAttrDiff{New: "foo"},
+					},
+				},
+				"aws_instance.change": &terraform.ResourceDiff{
+					Attributes: map[string]*terraform.ResourceAttrDiff{
+						"ami": &terraform.ResourceAttrDiff{Old: "foo", New: "bar"},
+					},
+				},
+				"aws_instance.replace": &terraform.ResourceDiff{
+					Destroy: true,
+					Attributes: map[string]*terraform.ResourceAttrDiff{
+						"ami": &terraform.ResourceAttrDiff{
+							Old:         "foo",
+							New:         "bar",
+							RequiresNew: true,
+						},
+					},
+				},
+				"aws_instance.destroy": &terraform.ResourceDiff{
+					Destroy: true,
+				},
+			},
+		},
+		State: &terraform.State{
+			Resources: map[string]*terraform.ResourceState{
+				"aws_instance.change":  &terraform.ResourceState{ID: "a"},
+				"aws_instance.replace": &terraform.ResourceState{ID: "b"},
+				"aws_instance.destroy": &terraform.ResourceState{ID: "c"},
+			},
+		},
+	}
+
+	actual := auditPlan(p)
+	expected := &AuditPlan{Add: 2, Change: 1, Destroy: 2}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
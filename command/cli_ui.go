@@ -40,3 +40,26 @@ func (u *ColorizeUi) colorize(message string, color string) string {
 
 	return u.Colorize.Color(fmt.Sprintf("%s%s[reset]", color, message))
 }
+
+// redactUi is a Ui implementation that redacts sensitive values from
+// everything that is output.
+type redactUi struct {
+	Redactor *redactor
+	Ui       cli.Ui
+}
+
+func (u *redactUi) Ask(query string) (string, error) {
+	return u.Ui.Ask(u.Redactor.Redact(query))
+}
+
+func (u *redactUi) Output(message string) {
+	u.Ui.Output(u.Redactor.Redact(message))
+}
+
+func (u *redactUi) Info(message string) {
+	u.Ui.Info(u.Redactor.Redact(message))
+}
+
+func (u *redactUi) Error(message string) {
+	u.Ui.Error(u.Redactor.Redact(message))
+}
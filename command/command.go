@@ -1,47 +1,159 @@
 package command
 
 import (
-	"fmt"
-
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/state"
 	"github.com/hashicorp/terraform/terraform"
-	"github.com/mitchellh/cli"
 )
 
 // DefaultStateFilename is the default filename used for the state file.
 const DefaultStateFilename = "terraform.tfstate"
 
+// DefaultDataDir is the directory, relative to the configuration, where
+// Terraform keeps data about the working directory.
+const DefaultDataDir = ".terraform"
+
 // DefaultVarsFilename is the default filename used for vars
 const DefaultVarsFilename = "terraform.tfvars"
 
 // DefaultBackupExtention is added to the state file to form the path
 const DefaultBackupExtention = ".backup"
 
-func validateContext(ctx *terraform.Context, ui cli.Ui) bool {
-	if ws, es := ctx.Validate(); len(ws) > 0 || len(es) > 0 {
-		ui.Output(
-			"There are warnings and/or errors related to your configuration. Please\n" +
-				"fix these before continuing.\n")
-
-		if len(ws) > 0 {
-			ui.Output("Warnings:\n")
-			for _, w := range ws {
-				ui.Output(fmt.Sprintf("  * %s", w))
-			}
-
-			if len(es) > 0 {
-				ui.Output("")
-			}
-		}
+// DefaultResumeExtension is added to the state file to form the path of
+// the plan that apply -resume continues when an apply fails partway.
+const DefaultResumeExtension = ".resume"
+
+// DefaultSummaryExtension is added to the path of a plan file to form
+// the path of the summary of the plan that plan -out writes beside it.
+const DefaultSummaryExtension = ".summary.json"
+
+// CompressedStateExtension is the extension of state files that are
+// gzip-compressed at rest.
+const CompressedStateExtension = state.CompressedExtension
+
+// statsTopN is the number of slowest resources and operations shown in
+// the statistics from -stats.
+const statsTopN = 5
+
+// validateContext validates the context and the configuration's rules,
+// and outputs any warnings and errors. It returns false if Terraform
+// shouldn't continue, which is when there are errors, or warnings and
+// -strict was given.
+func (m *Meta) validateContext(ctx *terraform.Context) bool {
+	ws, es := m.checkContext(ctx)
+	m.warnings = ws
+	if len(ws) == 0 && len(es) == 0 {
+		return true
+	}
+
+	for _, w := range ws {
+		m.Ui.Output(m.Colorize().Color("[yellow]Warning: [reset]") + w)
+	}
+	if len(ws) > 0 {
+		m.Ui.Output("")
+	}
 
-		if len(es) > 0 {
-			ui.Output("Errors:\n")
-			for _, e := range es {
-				ui.Output(fmt.Sprintf("  * %s", e))
-			}
+	if len(es) > 0 {
+		m.Ui.Output(
+			"There are errors related to your configuration. Please fix\n" +
+				"these before continuing.\n")
+		for _, e := range es {
+			m.Ui.Output(m.Colorize().Color("[red]Error: [reset]") + e.Error())
 		}
 
 		return false
 	}
 
+	if m.strict {
+		m.Ui.Output(
+			"There are warnings related to your configuration. Since -strict\n" +
+				"was given, these must be fixed before continuing.")
+		return false
+	}
+
 	return true
 }
+
+// checkContext validates the configuration of the context and checks it
+// with the rules, returning the warnings and errors.
+func (m *Meta) checkContext(ctx *terraform.Context) ([]string, []error) {
+	var ws []string
+	var es []error
+	for _, d := range m.contextDiagnostics(ctx) {
+		if d.Severity == config.DiagWarning {
+			ws = append(ws, d.Error())
+		} else {
+			es = append(es, d)
+		}
+	}
+
+	return ws, es
+}
+
+// contextDiagnostics is checkContext, with the warnings and errors as
+// diagnostics.
+func (m *Meta) contextDiagnostics(ctx *terraform.Context) []*config.Diagnostic {
+	ds := ctx.Diagnostics()
+	for _, err := range m.checkRules() {
+		ds = append(ds, config.Diagnostics(err, "policy")...)
+	}
+
+	return ds
+}
+
+// runInterruptible runs f, which runs an operation of the context, and
+// stops the context gracefully if an interrupt is received on shutdownCh.
+// Stopping tells the providers and provisioners to cancel what they're
+// doing, and waits for f to return. A second interrupt returns right away,
+// without waiting, in which case finished is false.
+func (m *Meta) runInterruptible(
+	ctx *terraform.Context,
+	shutdownCh <-chan struct{},
+	f func()) (interrupted, finished bool) {
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		f()
+	}()
+
+	select {
+	case <-shutdownCh:
+		m.Ui.Output("Interrupt received. Gracefully shutting down...")
+
+		// Stop blocks until f returns, so it's done in the background to
+		// catch a second interrupt
+		go ctx.Stop()
+
+		select {
+		case <-shutdownCh:
+			m.Ui.Error(
+				"Two interrupts received. Exiting immediately. Note that data\n" +
+					"loss may have occurred.")
+			return true, false
+		case <-doneCh:
+			return true, true
+		}
+	case <-doneCh:
+		return false, true
+	}
+}
+
+// writeStateFile writes the state to the given path, compressing it if
+// the path has the CompressedStateExtension.
+//
+// Every write increments the serial of the state, so that a given
+// version of the state can be identified, such as in the audit log. If
+// StateRetention is set, the state is also kept in the history of the
+// state file, except in read-only mode, where the state is only written
+// to a temporary file.
+func (m *Meta) writeStateFile(path string, s *terraform.State) error {
+	ls := &state.LocalState{Path: path}
+	if !m.ReadOnly {
+		ls.Retention = m.StateRetention
+	}
+	if err := ls.WriteState(s); err != nil {
+		return err
+	}
+
+	return ls.PersistState()
+}
@@ -0,0 +1,148 @@
+package command
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+// CommandHooksDir is the directory, in the working directory, of the
+// executables that are run before and after commands.
+const CommandHooksDir = ".terraform-hooks"
+
+// CommandHookCommand is a cli.Command that runs the executables in the
+// command hooks directory before and after the command, like the hooks of
+// git. The executable named "pre-COMMAND" is run before it, and
+// "post-COMMAND" after it, such as "pre-apply" and "post-apply".
+//
+// The executables are given the run in environment variables: TF_HOOK is
+// the name of the hook, TF_COMMAND the name of the command, TF_ARGS its
+// arguments, TF_WORKING_DIR the working directory and, for post hooks,
+// TF_EXIT_CODE the exit code of the command.
+//
+// If a pre hook exits with an error, the command isn't run. Lines of the
+// form "NAME=value" that a pre hook writes to stdout are set in the
+// environment of the command, such as to fetch credentials for providers.
+// Errors from post hooks are only reported.
+type CommandHookCommand struct {
+	cli.Command
+
+	Name string
+	Ui   cli.Ui
+
+	// Dir is the working directory. If it's empty, the working directory
+	// of the process is used.
+	Dir string
+}
+
+func (c *CommandHookCommand) Run(args []string) int {
+	dir := c.Dir
+	if dir == "" {
+		var err error
+		if dir, err = os.Getwd(); err != nil {
+			c.Ui.Error(fmt.Sprintf("Error getting pwd: %s", err))
+			return 1
+		}
+	}
+
+	env := []string{
+		"TF_COMMAND=" + c.Name,
+		"TF_ARGS=" + strings.Join(args, " "),
+		"TF_WORKING_DIR=" + dir,
+	}
+
+	pre := "pre-" + c.Name
+	output, err := runCommandHook(dir, pre, env)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("The %s hook stopped %s: %s", pre, c.Name, err))
+		return 1
+	}
+	for _, line := range output {
+		if m := commandHookEnvRe.FindStringSubmatch(line); m != nil {
+			log.Printf("[DEBUG] Setting %s from the %s hook", m[1], pre)
+			os.Setenv(m[1], m[2])
+			continue
+		}
+
+		c.Ui.Output(line)
+	}
+
+	code := c.Command.Run(args)
+
+	post := "post-" + c.Name
+	env = append(env, "TF_EXIT_CODE="+strconv.Itoa(code))
+	output, err = runCommandHook(dir, post, env)
+	for _, line := range output {
+		c.Ui.Output(line)
+	}
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error running the %s hook: %s", post, err))
+	}
+
+	return code
+}
+
+// commandHookEnvRe matches the lines of the output of pre hooks that set
+// environment variables.
+var commandHookEnvRe = regexp.MustCompile(`^([a-zA-Z_][a-zA-Z0-9_]*)=(.*)$`)
+
+// commandHookPath returns the path of the executable of the named hook in
+// the working directory, or "" if there isn't one. Executables may have
+// an extension, such as ".exe" or ".bat" on Windows.
+func commandHookPath(dir, name string) string {
+	path := filepath.Join(dir, CommandHooksDir, name)
+	if fi, err := os.Stat(path); err == nil && !fi.IsDir() {
+		return path
+	}
+
+	matches, _ := filepath.Glob(path + ".*")
+	for _, m := range matches {
+		if fi, err := os.Stat(m); err == nil && !fi.IsDir() {
+			return m
+		}
+	}
+
+	return ""
+}
+
+// runCommandHook runs the executable of the named hook, if there is one,
+// and returns the lines it wrote to stdout. Its stderr is passed through.
+// If it fails, the error includes what it wrote to stdout.
+func runCommandHook(dir, name string, env []string) ([]string, error) {
+	path := commandHookPath(dir, name)
+	if path == "" {
+		return nil, nil
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.Command(path)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), append(env, "TF_HOOK="+name)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	log.Printf("[INFO] Running the %s hook: %s", name, path)
+	if err := cmd.Run(); err != nil {
+		if out := strings.TrimSpace(stdout.String()); out != "" {
+			return nil, fmt.Errorf("%s\n%s", err, out)
+		}
+
+		return nil, err
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(&stdout)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	return lines, scanner.Err()
+}
@@ -0,0 +1,151 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+)
+
+func TestCommandHookCommand_impl(t *testing.T) {
+	var _ cli.Command = new(CommandHookCommand)
+}
+
+func TestCommandHookCommand(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	out := filepath.Join(td, "out")
+	testCommandHook(t, td, "pre-apply",
+		`echo "$TF_HOOK $TF_COMMAND $TF_ARGS" >> `+out+`
+echo TF_TEST_HOOK_TOKEN=secret
+echo fetched credentials`)
+	testCommandHook(t, td, "post-apply",
+		`echo "$TF_HOOK $TF_EXIT_CODE" >> `+out)
+	defer os.Unsetenv("TF_TEST_HOOK_TOKEN")
+
+	ui := new(cli.MockUi)
+	c := &CommandHookCommand{
+		Command: &cli.MockCommand{RunResult: 0},
+		Name:    "apply",
+		Ui:      ui,
+		Dir:     td,
+	}
+
+	if code := c.Run([]string{"-refresh=false"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	data, err := ioutil.ReadFile(out)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := "pre-apply apply -refresh=false\npost-apply 0"
+	if actual := strings.TrimSpace(string(data)); actual != expected {
+		t.Fatalf("bad: %q", actual)
+	}
+
+	// Variables set by pre hooks are in the environment of the command
+	if v := os.Getenv("TF_TEST_HOOK_TOKEN"); v != "secret" {
+		t.Fatalf("bad: %q", v)
+	}
+	if actual := ui.OutputWriter.String(); actual != "fetched credentials\n" {
+		t.Fatalf("bad: %q", actual)
+	}
+}
+
+func TestCommandHookCommand_veto(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	testCommandHook(t, td, "pre-plan", "echo not on fridays; exit 1")
+
+	ui := new(cli.MockUi)
+	mock := &cli.MockCommand{RunResult: 0}
+	c := &CommandHookCommand{
+		Command: mock,
+		Name:    "plan",
+		Ui:      ui,
+		Dir:     td,
+	}
+
+	if code := c.Run(nil); code != 1 {
+		t.Fatalf("bad: %d", code)
+	}
+	if mock.RunCalled {
+		t.Fatal("command should not run")
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "not on fridays") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+}
+
+func TestCommandHookCommand_postError(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	testCommandHook(t, td, "post-plan", "exit 1")
+
+	ui := new(cli.MockUi)
+	c := &CommandHookCommand{
+		Command: &cli.MockCommand{RunResult: 0},
+		Name:    "plan",
+		Ui:      ui,
+		Dir:     td,
+	}
+
+	// Failing post hooks are reported, but don't change the exit code
+	if code := c.Run(nil); code != 0 {
+		t.Fatalf("bad: %d", code)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "post-plan") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+}
+
+func TestCommandHookCommand_noHooks(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	ui := new(cli.MockUi)
+	c := &CommandHookCommand{
+		Command: &cli.MockCommand{RunResult: 2},
+		Name:    "plan",
+		Ui:      ui,
+		Dir:     td,
+	}
+
+	if code := c.Run(nil); code != 2 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+}
+
+func TestCommandHookPath(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	dir := filepath.Join(td, CommandHooksDir)
+	testWriteExecutable(t, filepath.Join(dir, "pre-plan.bat"), "")
+
+	if actual := commandHookPath(td, "pre-plan"); actual != filepath.Join(dir, "pre-plan.bat") {
+		t.Fatalf("bad: %s", actual)
+	}
+	if actual := commandHookPath(td, "pre-apply"); actual != "" {
+		t.Fatalf("bad: %s", actual)
+	}
+}
+
+// testCommandHook writes a shell script as the named hook in the command
+// hooks directory of dir.
+func testCommandHook(t *testing.T, dir, name, script string) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts aren't supported on Windows")
+	}
+
+	testWriteExecutable(t, filepath.Join(dir, CommandHooksDir, name),
+		"#!/bin/sh\n"+script+"\n")
+}
@@ -70,6 +70,10 @@ func testReadPlan(t *testing.T, path string) *terraform.Plan {
 func testStateFile(t *testing.T, s *terraform.State) string {
 	path := testTempFile(t)
 
+	// The state is read back with the version that wrote it, so it is
+	// recorded here to compare the state with what is read back.
+	s.TFVersion = terraform.VersionString()
+
 	f, err := os.Create(path)
 	if err != nil {
 		t.Fatalf("err: %s", err)
@@ -83,6 +87,24 @@ func testStateFile(t *testing.T, s *terraform.State) string {
 	return path
 }
 
+// testNewerStateFile writes a state file that was written by a newer
+// version of Terraform, and returns its path.
+func testNewerStateFile(t *testing.T) string {
+	path := testTempFile(t)
+	err := ioutil.WriteFile(path, []byte(`{
+		"version": 1,
+		"terraform_version": "99.0.0",
+		"resources": {
+			"test_instance.foo": {"type": "test_instance", "id": "bar"}
+		}
+	}`), 0644)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return path
+}
+
 func testProvider() *terraform.MockResourceProvider {
 	p := new(terraform.MockResourceProvider)
 	p.DiffReturn = &terraform.ResourceDiff{}
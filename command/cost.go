@@ -0,0 +1,362 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/colorstring"
+)
+
+// hoursPerMonth is how many hours of hourly prices make up a month.
+const hoursPerMonth = 730
+
+// CostEstimator estimates the monthly cost of resources.
+type CostEstimator interface {
+	// EstimateCost returns the monthly cost of each resource before and
+	// after the plan is applied, keyed by the name of the resource.
+	// Resources whose cost isn't known are left out.
+	EstimateCost([]*CostResource) (map[string]*CostEstimate, error)
+}
+
+// CostResource is a resource whose cost is estimated, with its attributes
+// before and after the plan is applied. Before is nil for resources that
+// are created, and After is nil for resources that are destroyed.
+type CostResource struct {
+	Resource string            `json:"resource"`
+	Type     string            `json:"type"`
+	Before   map[string]string `json:"before,omitempty"`
+	After    map[string]string `json:"after,omitempty"`
+}
+
+// CostEstimate is the monthly cost of a resource before and after the
+// plan is applied.
+type CostEstimate struct {
+	Before float64 `json:"before"`
+	After  float64 `json:"after"`
+}
+
+// PlanCost is the estimated monthly cost of the infrastructure before and
+// after a plan is applied.
+type PlanCost struct {
+	Before float64
+	After  float64
+
+	// Resources are the estimates of the resources whose cost changes.
+	Resources map[string]*CostEstimate
+}
+
+// CostPrice is the monthly price of a type of resource.
+type CostPrice struct {
+	// Attribute is the attribute that the price depends on, such as the
+	// instance type, and Prices are the prices for its values. If it's
+	// empty, every resource of the type costs Fixed.
+	Attribute string
+	Prices    map[string]float64
+	Fixed     float64
+}
+
+// StaticCostPrices are the built-in on-demand prices of common resources,
+// in US dollars, keyed by the type of resource.
+var StaticCostPrices = map[string]*CostPrice{
+	"aws_instance": &CostPrice{
+		Attribute: "instance_type",
+		Prices: map[string]float64{
+			"t1.micro":   0.020 * hoursPerMonth,
+			"t2.micro":   0.013 * hoursPerMonth,
+			"t2.small":   0.026 * hoursPerMonth,
+			"t2.medium":  0.052 * hoursPerMonth,
+			"m1.small":   0.044 * hoursPerMonth,
+			"m1.medium":  0.087 * hoursPerMonth,
+			"m1.large":   0.175 * hoursPerMonth,
+			"m1.xlarge":  0.350 * hoursPerMonth,
+			"m3.medium":  0.070 * hoursPerMonth,
+			"m3.large":   0.140 * hoursPerMonth,
+			"m3.xlarge":  0.280 * hoursPerMonth,
+			"m3.2xlarge": 0.560 * hoursPerMonth,
+			"c3.large":   0.105 * hoursPerMonth,
+			"c3.xlarge":  0.210 * hoursPerMonth,
+			"c3.2xlarge": 0.420 * hoursPerMonth,
+			"r3.large":   0.175 * hoursPerMonth,
+			"r3.xlarge":  0.350 * hoursPerMonth,
+		},
+	},
+	"aws_db_instance": &CostPrice{
+		Attribute: "instance_class",
+		Prices: map[string]float64{
+			"db.t1.micro":  0.025 * hoursPerMonth,
+			"db.m1.small":  0.055 * hoursPerMonth,
+			"db.m1.medium": 0.110 * hoursPerMonth,
+			"db.m1.large":  0.220 * hoursPerMonth,
+			"db.m3.medium": 0.090 * hoursPerMonth,
+			"db.m3.large":  0.185 * hoursPerMonth,
+		},
+	},
+	"aws_elb": &CostPrice{
+		Fixed: 0.025 * hoursPerMonth,
+	},
+	"digitalocean_droplet": &CostPrice{
+		Attribute: "size",
+		Prices: map[string]float64{
+			"512mb": 5,
+			"1gb":   10,
+			"2gb":   20,
+			"4gb":   40,
+			"8gb":   80,
+			"16gb":  160,
+		},
+	},
+}
+
+// StaticCostEstimator estimates costs from a table of prices.
+type StaticCostEstimator struct {
+	Prices map[string]*CostPrice
+}
+
+func (e *StaticCostEstimator) EstimateCost(
+	rs []*CostResource) (map[string]*CostEstimate, error) {
+	result := make(map[string]*CostEstimate)
+	for _, r := range rs {
+		p, ok := e.Prices[r.Type]
+		if !ok {
+			continue
+		}
+
+		before, ok := p.price(r.Before)
+		if !ok {
+			continue
+		}
+		after, ok := p.price(r.After)
+		if !ok {
+			continue
+		}
+
+		result[r.Resource] = &CostEstimate{Before: before, After: after}
+	}
+
+	return result, nil
+}
+
+// price returns the price of a resource with the given attributes, and
+// whether it is known. A resource that doesn't exist costs nothing.
+func (p *CostPrice) price(attrs map[string]string) (float64, bool) {
+	if attrs == nil {
+		return 0, true
+	}
+	if p.Attribute == "" {
+		return p.Fixed, true
+	}
+
+	v, ok := p.Prices[attrs[p.Attribute]]
+	return v, ok
+}
+
+// ExecCostEstimator estimates costs by running an executable, configured
+// with "cost_estimator" in the CLI configuration. The executable is given
+// the resources as JSON on stdin, as {"resources": [...]}, and must write
+// the estimates as JSON to stdout, as {"resources": {"name": {...}}}.
+type ExecCostEstimator struct {
+	Command string
+}
+
+func (e *ExecCostEstimator) EstimateCost(
+	rs []*CostResource) (map[string]*CostEstimate, error) {
+	data, err := json.Marshal(map[string]interface{}{"resources": rs})
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(e.Command)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf(
+			"%s: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var result struct {
+		Resources map[string]*CostEstimate `json:"resources"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		return nil, fmt.Errorf("invalid estimate: %s", err)
+	}
+
+	return result.Resources, nil
+}
+
+// costResources returns every resource in the state or the diff of the
+// plan, with its attributes before and after the plan is applied. The
+// values of attributes that aren't known until apply are empty.
+func costResources(p *terraform.Plan) []*CostResource {
+	names := make(map[string]struct{})
+	if p.State != nil {
+		for name, _ := range p.State.Resources {
+			names[name] = struct{}{}
+		}
+	}
+	if p.Diff != nil {
+		for name, _ := range p.Diff.Resources {
+			names[name] = struct{}{}
+		}
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name, _ := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	result := make([]*CostResource, 0, len(sorted))
+	for _, name := range sorted {
+		var s *terraform.ResourceState
+		if p.State != nil {
+			s = p.State.Resources[name]
+		}
+		var d *terraform.ResourceDiff
+		if p.Diff != nil {
+			d = p.Diff.Resources[name]
+		}
+
+		r := &CostResource{
+			Resource: name,
+			Type:     strings.SplitN(name, ".", 2)[0],
+		}
+		if s != nil && s.ID != "" {
+			r.Before = s.Attributes
+			if r.Before == nil {
+				r.Before = make(map[string]string)
+			}
+		}
+
+		switch {
+		case d == nil || d.Empty():
+			r.After = r.Before
+		case d.Destroy && !d.RequiresNew():
+		default:
+			// A resource that is replaced starts over from the diff
+			if d.RequiresNew() {
+				s = nil
+			}
+
+			r.After = s.MergeDiff(d).Attributes
+			for k, v := range r.After {
+				if v == config.UnknownVariableValue {
+					r.After[k] = ""
+				}
+			}
+		}
+
+		result = append(result, r)
+	}
+
+	return result
+}
+
+// estimateCost estimates the monthly cost of the infrastructure before and
+// after the plan is applied. The first estimator to estimate a resource is
+// used. It returns nil if there are no estimators.
+func (m *Meta) estimateCost(p *terraform.Plan) (*PlanCost, error) {
+	if len(m.CostEstimators) == 0 {
+		return nil, nil
+	}
+
+	rs := costResources(p)
+	estimates := make(map[string]*CostEstimate)
+	for _, e := range m.CostEstimators {
+		result, err := e.EstimateCost(rs)
+		if err != nil {
+			return nil, fmt.Errorf("Error estimating cost: %s", err)
+		}
+
+		for k, v := range result {
+			if _, ok := estimates[k]; !ok && v != nil {
+				estimates[k] = v
+			}
+		}
+	}
+
+	result := &PlanCost{Resources: make(map[string]*CostEstimate)}
+	for k, v := range estimates {
+		result.Before += v.Before
+		result.After += v.After
+		if v.Before != v.After {
+			result.Resources[k] = v
+		}
+	}
+
+	return result, nil
+}
+
+// overBudget returns whether applying the plan would take the estimated
+// monthly cost over the budget.
+func (m *Meta) overBudget(cost *PlanCost) bool {
+	return m.CostBudget > 0 && cost != nil &&
+		cost.After > m.CostBudget && cost.After > cost.Before
+}
+
+// showCost estimates the cost of the plan and shows how it changes, if
+// it does. It returns the estimate, which is nil if the cost couldn't
+// be estimated.
+func (m *Meta) showCost(p *terraform.Plan) *PlanCost {
+	cost, err := m.estimateCost(p)
+	if err != nil {
+		m.Ui.Output(m.Colorize().Color("[yellow]Warning: [reset]" + err.Error()))
+		return nil
+	}
+
+	if cost != nil && len(cost.Resources) > 0 {
+		m.Ui.Output(FormatCost(cost, m.Colorize()))
+	}
+
+	return cost
+}
+
+// FormatCost returns a human-readable summary of the estimated cost of a
+// plan and of each resource whose cost changes.
+func FormatCost(cost *PlanCost, c *colorstring.Colorize) string {
+	if c == nil {
+		c = &colorstring.Colorize{
+			Colors: colorstring.DefaultColors,
+			Reset:  false,
+		}
+	}
+
+	names := make([]string, 0, len(cost.Resources))
+	for name, _ := range cost.Resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	buf.WriteString(c.Color(fmt.Sprintf(
+		"[reset][bold]Estimated monthly cost: $%.2f -> $%.2f (%s)\n",
+		cost.Before, cost.After, formatCostDelta(cost.After-cost.Before))))
+	for _, name := range names {
+		e := cost.Resources[name]
+		color := "green"
+		if e.After > e.Before {
+			color = "yellow"
+		}
+
+		buf.WriteString(c.Color(fmt.Sprintf(
+			"  [%s]%s: %s\n", color, name, formatCostDelta(e.After-e.Before))))
+	}
+
+	return buf.String()
+}
+
+// formatCostDelta formats a change in cost with its sign.
+func formatCostDelta(v float64) string {
+	if v < 0 {
+		return fmt.Sprintf("-$%.2f", -v)
+	}
+
+	return fmt.Sprintf("+$%.2f", v)
+}
@@ -0,0 +1,324 @@
+package command
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/colorstring"
+)
+
+func TestStaticCostEstimator_impl(t *testing.T) {
+	var _ CostEstimator = new(StaticCostEstimator)
+}
+
+func TestExecCostEstimator_impl(t *testing.T) {
+	var _ CostEstimator = new(ExecCostEstimator)
+}
+
+func TestStaticCostEstimator(t *testing.T) {
+	e := &StaticCostEstimator{
+		Prices: map[string]*CostPrice{
+			"aws_instance": &CostPrice{
+				Attribute: "instance_type",
+				Prices: map[string]float64{
+					"m1.small": 10,
+					"m1.large": 40,
+				},
+			},
+			"aws_elb": &CostPrice{Fixed: 20},
+		},
+	}
+
+	rs := []*CostResource{
+		&CostResource{
+			Resource: "aws_instance.create",
+			Type:     "aws_instance",
+			After:    map[string]string{"instance_type": "m1.small"},
+		},
+		&CostResource{
+			Resource: "aws_instance.resize",
+			Type:     "aws_instance",
+			Before:   map[string]string{"instance_type": "m1.small"},
+			After:    map[string]string{"instance_type": "m1.large"},
+		},
+		&CostResource{
+			Resource: "aws_instance.unknown",
+			Type:     "aws_instance",
+			After:    map[string]string{"instance_type": ""},
+		},
+		&CostResource{
+			Resource: "aws_elb.destroy",
+			Type:     "aws_elb",
+			Before:   map[string]string{},
+		},
+		&CostResource{
+			Resource: "aws_eip.free",
+			Type:     "aws_eip",
+			After:    map[string]string{},
+		},
+	}
+
+	actual, err := e.EstimateCost(rs)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]*CostEstimate{
+		"aws_instance.create": &CostEstimate{Before: 0, After: 10},
+		"aws_instance.resize": &CostEstimate{Before: 10, After: 40},
+		"aws_elb.destroy":     &CostEstimate{Before: 20, After: 0},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestExecCostEstimator(t *testing.T) {
+	e := &ExecCostEstimator{Command: testScript(t, `
+grep -q '"resource":"aws_instance.foo"' || exit 1
+echo '{"resources": {"aws_instance.foo": {"before": 1, "after": 2.5}}}'
+`)}
+	defer os.Remove(e.Command)
+
+	actual, err := e.EstimateCost([]*CostResource{
+		&CostResource{Resource: "aws_instance.foo"},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]*CostEstimate{
+		"aws_instance.foo": &CostEstimate{Before: 1, After: 2.5},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestExecCostEstimator_error(t *testing.T) {
+	e := &ExecCostEstimator{Command: testScript(t, "echo 'not json'")}
+	defer os.Remove(e.Command)
+
+	if _, err := e.EstimateCost(nil); err == nil {
+		t.Fatal("should error")
+	}
+}
+
+func TestCostResources(t *testing.T) {
+	plan := &terraform.Plan{
+		Diff: &terraform.Diff{
+			Resources: map[string]*terraform.ResourceDiff{
+				"aws_instance.create": &terraform.ResourceDiff{
+					Attributes: map[string]*terraform.ResourceAttrDiff{
+						"instance_type": &terraform.ResourceAttrDiff{
+							New: "m1.small",
+						},
+						"private_ip": &terraform.ResourceAttrDiff{
+							NewComputed: true,
+						},
+					},
+				},
+				"aws_instance.update": &terraform.ResourceDiff{
+					Attributes: map[string]*terraform.ResourceAttrDiff{
+						"instance_type": &terraform.ResourceAttrDiff{
+							Old: "m1.small",
+							New: "m1.large",
+						},
+					},
+				},
+				"aws_instance.replace": &terraform.ResourceDiff{
+					Destroy: true,
+					Attributes: map[string]*terraform.ResourceAttrDiff{
+						"ami": &terraform.ResourceAttrDiff{
+							Old:         "ami-1",
+							New:         "ami-2",
+							RequiresNew: true,
+						},
+					},
+				},
+				"aws_instance.destroy": &terraform.ResourceDiff{
+					Destroy: true,
+				},
+			},
+		},
+		State: &terraform.State{
+			Resources: map[string]*terraform.ResourceState{
+				"aws_instance.update": &terraform.ResourceState{
+					ID: "i-1",
+					Attributes: map[string]string{
+						"instance_type": "m1.small",
+					},
+				},
+				"aws_instance.replace": &terraform.ResourceState{
+					ID: "i-2",
+					Attributes: map[string]string{
+						"ami":           "ami-1",
+						"instance_type": "m1.small",
+					},
+				},
+				"aws_instance.destroy": &terraform.ResourceState{
+					ID: "i-3",
+					Attributes: map[string]string{
+						"instance_type": "m1.small",
+					},
+				},
+				"aws_instance.same": &terraform.ResourceState{
+					ID: "i-4",
+					Attributes: map[string]string{
+						"instance_type": "m1.small",
+					},
+				},
+			},
+		},
+	}
+
+	actual := costResources(plan)
+	small := map[string]string{"instance_type": "m1.small"}
+	expected := []*CostResource{
+		&CostResource{
+			Resource: "aws_instance.create",
+			Type:     "aws_instance",
+			After: map[string]string{
+				"instance_type": "m1.small",
+				"private_ip":    "",
+			},
+		},
+		&CostResource{
+			Resource: "aws_instance.destroy",
+			Type:     "aws_instance",
+			Before:   small,
+		},
+		&CostResource{
+			Resource: "aws_instance.replace",
+			Type:     "aws_instance",
+			Before: map[string]string{
+				"ami":           "ami-1",
+				"instance_type": "m1.small",
+			},
+			After: map[string]string{"ami": "ami-2"},
+		},
+		&CostResource{
+			Resource: "aws_instance.same",
+			Type:     "aws_instance",
+			Before:   small,
+			After:    small,
+		},
+		&CostResource{
+			Resource: "aws_instance.update",
+			Type:     "aws_instance",
+			Before:   small,
+			After:    map[string]string{"instance_type": "m1.large"},
+		},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	// Unknown values must never be priced
+	for _, r := range actual {
+		for _, v := range r.After {
+			if v == config.UnknownVariableValue {
+				t.Fatalf("bad: %#v", r)
+			}
+		}
+	}
+}
+
+func TestMetaEstimateCost(t *testing.T) {
+	m := &Meta{
+		CostEstimators: []CostEstimator{
+			&StaticCostEstimator{
+				Prices: map[string]*CostPrice{
+					"aws_elb": &CostPrice{Fixed: 20},
+				},
+			},
+			&StaticCostEstimator{
+				Prices: map[string]*CostPrice{
+					"aws_elb":      &CostPrice{Fixed: 100},
+					"aws_instance": &CostPrice{Fixed: 10},
+				},
+			},
+		},
+	}
+
+	plan := &terraform.Plan{
+		Diff: &terraform.Diff{
+			Resources: map[string]*terraform.ResourceDiff{
+				"aws_elb.new": &terraform.ResourceDiff{
+					Attributes: map[string]*terraform.ResourceAttrDiff{
+						"name": &terraform.ResourceAttrDiff{New: "web"},
+					},
+				},
+			},
+		},
+		State: &terraform.State{
+			Resources: map[string]*terraform.ResourceState{
+				"aws_instance.web": &terraform.ResourceState{ID: "i-1"},
+			},
+		},
+	}
+
+	actual, err := m.estimateCost(plan)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := &PlanCost{
+		Before: 10,
+		After:  30,
+		Resources: map[string]*CostEstimate{
+			"aws_elb.new": &CostEstimate{Before: 0, After: 20},
+		},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	m.CostBudget = 25
+	if !m.overBudget(actual) {
+		t.Fatal("should be over budget")
+	}
+	m.CostBudget = 30
+	if m.overBudget(actual) {
+		t.Fatal("should not be over budget")
+	}
+}
+
+func TestMetaEstimateCost_none(t *testing.T) {
+	m := new(Meta)
+	cost, err := m.estimateCost(new(terraform.Plan))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if cost != nil {
+		t.Fatalf("bad: %#v", cost)
+	}
+}
+
+func TestFormatCost(t *testing.T) {
+	cost := &PlanCost{
+		Before: 30,
+		After:  25.5,
+		Resources: map[string]*CostEstimate{
+			"aws_instance.foo": &CostEstimate{Before: 0, After: 10.5},
+			"aws_instance.bar": &CostEstimate{Before: 15, After: 0},
+		},
+	}
+
+	actual := strings.TrimSpace(FormatCost(cost, &colorstring.Colorize{
+		Colors:  colorstring.DefaultColors,
+		Disable: true,
+	}))
+	expected := strings.TrimSpace(`
+Estimated monthly cost: $30.00 -> $25.50 (-$4.50)
+  aws_instance.bar: -$15.00
+  aws_instance.foo: +$10.50
+`)
+	if actual != expected {
+		t.Fatalf("bad:\n%s", actual)
+	}
+}
@@ -0,0 +1,340 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+)
+
+// DefaultDaemonInterval is how often the daemon checks its queue for
+// plans to apply.
+const DefaultDaemonInterval = 10 * time.Second
+
+// daemonHistoryLength is how many of the latest runs the status API
+// reports.
+const daemonHistoryLength = 50
+
+// DaemonCommand is a cli.Command implementation that applies the plan
+// files put in a queue directory, one at a time, so that a team can run
+// applies from one place. Plans are applied in the order of their names,
+// and then moved to the "applied" or "failed" directory of the queue,
+// along with their approvals and the output of the apply.
+type DaemonCommand struct {
+	Meta
+
+	ShutdownCh <-chan struct{}
+
+	status daemonStatus
+}
+
+// DaemonRun is a plan that the daemon applied, or is applying.
+type DaemonRun struct {
+	Plan     string    `json:"plan"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+
+	// Result is "applying", "applied" or "failed". Log is the path of
+	// the output of the apply.
+	Result string `json:"result"`
+	Log    string `json:"log,omitempty"`
+}
+
+// DaemonStatus is what the status API of the daemon returns.
+type DaemonStatus struct {
+	Queue string `json:"queue"`
+
+	// Current is the run of the plan that is being applied, if any.
+	// Queued are the plans waiting to be applied, in order, and History
+	// are the latest runs, newest first.
+	Current *DaemonRun   `json:"current,omitempty"`
+	Queued  []string     `json:"queued"`
+	History []*DaemonRun `json:"history"`
+}
+
+// daemonStatus is the status of the daemon, shared with the status API.
+type daemonStatus struct {
+	DaemonStatus
+	sync.Mutex
+}
+
+func (c *DaemonCommand) Run(args []string) int {
+	var interval time.Duration
+	var listen, statePath string
+	var once bool
+
+	// Each apply gets its own copy of the options, before they're
+	// processed for this command
+	applyMeta := c.Meta
+
+	args = c.Meta.process(args, false)
+	if c.refuseReadOnly("daemon") {
+		return 1
+	}
+
+	cmdFlags := c.Meta.flagSet("daemon")
+	cmdFlags.DurationVar(&interval, "interval", DefaultDaemonInterval, "interval")
+	cmdFlags.StringVar(&listen, "listen", "", "address")
+	cmdFlags.BoolVar(&once, "once", false, "once")
+	cmdFlags.StringVar(&statePath, "state", DefaultStateFilename, "path")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = cmdFlags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("The daemon command expects the queue directory.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+
+	queue := c.path(args[0])
+	if fi, err := os.Stat(queue); err != nil || !fi.IsDir() {
+		c.Ui.Error(fmt.Sprintf("The queue directory %s doesn't exist.", queue))
+		return 1
+	}
+	for _, d := range []string{"applied", "failed"} {
+		if err := os.MkdirAll(filepath.Join(queue, d), 0755); err != nil {
+			c.Ui.Error(fmt.Sprintf("Error creating queue directory: %s", err))
+			return 1
+		}
+	}
+	c.status.Queue = queue
+
+	if listen != "" {
+		ln, err := net.Listen("tcp", listen)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error starting status API: %s", err))
+			return 1
+		}
+		defer ln.Close()
+
+		go http.Serve(ln, http.HandlerFunc(c.serveStatus))
+		c.Ui.Output(fmt.Sprintf("Serving status on http://%s/status", ln.Addr()))
+	}
+
+	c.Ui.Output(fmt.Sprintf("Applying the plans in %s", queue))
+	for {
+		plans, err := daemonQueue(queue)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error reading queue: %s", err))
+			return 1
+		}
+		c.status.Lock()
+		c.status.Queued = plans
+		c.status.Unlock()
+
+		for _, plan := range plans {
+			select {
+			case <-c.ShutdownCh:
+				return 0
+			default:
+			}
+
+			c.apply(applyMeta, queue, plan, c.path(statePath))
+		}
+
+		if once {
+			return 0
+		}
+
+		select {
+		case <-c.ShutdownCh:
+			return 0
+		case <-time.After(interval):
+		}
+	}
+}
+
+// apply applies a single plan from the queue with the apply command, and
+// moves it out of the queue.
+func (c *DaemonCommand) apply(meta Meta, queue, plan, statePath string) {
+	run := &DaemonRun{
+		Plan:    plan,
+		Started: time.Now().UTC(),
+		Result:  "applying",
+	}
+	c.status.Lock()
+	c.status.Current = run
+	if len(c.status.Queued) > 0 {
+		c.status.Queued = c.status.Queued[1:]
+	}
+	c.status.Unlock()
+
+	c.Ui.Output(fmt.Sprintf("Applying %s...", plan))
+	path := filepath.Join(queue, plan)
+	code := 1
+	output, err := ioutil.TempFile(queue, ".daemon")
+	if err == nil {
+		ui := &cli.BasicUi{Writer: output, ErrorWriter: output}
+		if !daemonIsPlan(path) {
+			ui.Error(fmt.Sprintf("%s isn't a plan file.", plan))
+		} else {
+			// Applies aren't interrupted, so that the daemon stops once
+			// the current apply is done
+			meta.Ui = ui
+			apply := &ApplyCommand{Meta: meta}
+			code = apply.Run([]string{"-no-color", "-state", statePath, path})
+
+			// The daemon runs for a long time, so the plugins of each
+			// apply are stopped rather than left until it exits.
+			apply.closePlugins()
+		}
+		output.Close()
+	} else {
+		c.Ui.Error(fmt.Sprintf("Error creating log of %s: %s", plan, err))
+	}
+
+	result := "applied"
+	if code != 0 {
+		result = "failed"
+	}
+
+	// The plan, its approval, its summary and the output of the apply
+	// are moved out of the queue together
+	dir := filepath.Join(queue, result)
+	for _, ext := range []string{"", ApprovalExtension, DefaultSummaryExtension} {
+		if err := os.Rename(path+ext, filepath.Join(dir, plan+ext)); err != nil &&
+			!os.IsNotExist(err) {
+			c.Ui.Error(fmt.Sprintf("Error moving %s: %s", plan+ext, err))
+		}
+	}
+	var logPath string
+	if output != nil {
+		logPath = filepath.Join(dir, plan+".log")
+		if err := os.Rename(output.Name(), logPath); err != nil {
+			c.Ui.Error(fmt.Sprintf("Error moving log of %s: %s", plan, err))
+			logPath = ""
+		}
+	}
+
+	switch {
+	case code == 0:
+		c.Ui.Output(c.Colorize().Color(fmt.Sprintf(
+			"[reset][green]Applied %s.", plan)))
+	case logPath != "":
+		c.Ui.Error(fmt.Sprintf("Failed to apply %s. See %s.", plan, logPath))
+	default:
+		c.Ui.Error(fmt.Sprintf("Failed to apply %s.", plan))
+	}
+
+	c.status.Lock()
+	defer c.status.Unlock()
+	run.Finished = time.Now().UTC()
+	run.Result = result
+	run.Log = logPath
+	c.status.Current = nil
+	c.status.History = append([]*DaemonRun{run}, c.status.History...)
+	if len(c.status.History) > daemonHistoryLength {
+		c.status.History = c.status.History[:daemonHistoryLength]
+	}
+}
+
+// serveStatus serves the status of the daemon as JSON at /status.
+func (c *DaemonCommand) serveStatus(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/status" {
+		http.NotFound(w, r)
+		return
+	}
+
+	c.status.Lock()
+	data, err := json.Marshal(&c.status.DaemonStatus)
+	c.status.Unlock()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// daemonQueue returns the names of the plans in the queue directory, in
+// the order they're applied. Approvals, plan summaries and hidden files
+// aren't plans.
+func daemonQueue(dir string) ([]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(infos))
+	for _, fi := range infos {
+		name := fi.Name()
+		if fi.IsDir() || strings.HasPrefix(name, ".") ||
+			strings.HasSuffix(name, ApprovalExtension) ||
+			strings.HasSuffix(name, DefaultSummaryExtension) {
+			continue
+		}
+
+		result = append(result, name)
+	}
+	sort.Strings(result)
+
+	return result, nil
+}
+
+// daemonIsPlan returns whether the file at the path is a plan.
+func daemonIsPlan(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	if _, err := terraform.ReadPlan(f); err != nil {
+		log.Printf("[DEBUG] %s isn't a plan: %s", path, err)
+		return false
+	}
+
+	return true
+}
+
+func (c *DaemonCommand) Help() string {
+	helpText := `
+Usage: terraform daemon [options] DIR
+
+  Applies the plan files put in the queue directory DIR, one at a time,
+  with the state locked. Plans are applied in the order of their names,
+  and then moved to the "applied" or "failed" directory of the queue,
+  along with their approvals, summaries and a ".log" file of the output
+  of the apply.
+
+  If approvers are set in the CLI configuration, plans are only applied
+  if they're approved, as with "terraform apply". An interrupt stops the
+  daemon once the current apply is done.
+
+Options:
+
+  -interval=10s       How often to check the queue for plans.
+
+  -listen=address     Serve the status of the daemon as JSON at /status on
+                      the given address, such as "127.0.0.1:8080".
+
+  -no-color           If specified, output won't contain any color.
+
+  -once               Apply the plans in the queue, and then exit instead
+                      of waiting for more.
+
+  -state=path         Path to the state file to apply the plans to.
+                      Defaults to "terraform.tfstate".
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *DaemonCommand) Synopsis() string {
+	return "Apply the plans put in a queue directory"
+}
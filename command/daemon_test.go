@@ -0,0 +1,286 @@
+package command
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/state"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+)
+
+func TestDaemon_once(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	queue := filepath.Join(td, "queue")
+	statePath := filepath.Join(td, "terraform.tfstate")
+	testDaemonPlan(t, filepath.Join(queue, "001-web"))
+	testWriteExecutable(t, filepath.Join(queue, "001-web"+DefaultSummaryExtension), "{}")
+	testWriteExecutable(t, filepath.Join(queue, "002-bad"), "not a plan")
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &DaemonCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-once",
+		"-state", statePath,
+		queue,
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	for _, path := range []string{
+		"applied/001-web",
+		"applied/001-web.log",
+		"applied/001-web" + DefaultSummaryExtension,
+		"failed/002-bad",
+		"failed/002-bad.log",
+	} {
+		if _, err := os.Stat(filepath.Join(queue, path)); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+	if plans, _ := daemonQueue(queue); len(plans) != 0 {
+		t.Fatalf("queue should be empty: %#v", plans)
+	}
+	if _, err := os.Stat(statePath); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// The state is unlocked once the applies are done
+	if _, err := os.Stat(statePath + state.LockExtension); !os.IsNotExist(err) {
+		t.Fatalf("state should be unlocked: %s", err)
+	}
+
+	history := c.status.History
+	if len(history) != 2 {
+		t.Fatalf("bad: %#v", history)
+	}
+	if history[0].Plan != "002-bad" || history[0].Result != "failed" {
+		t.Fatalf("bad: %#v", history[0])
+	}
+	if history[1].Plan != "001-web" || history[1].Result != "applied" {
+		t.Fatalf("bad: %#v", history[1])
+	}
+}
+
+func TestDaemon_locked(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	queue := filepath.Join(td, "queue")
+	statePath := filepath.Join(td, "terraform.tfstate")
+	testDaemonPlan(t, filepath.Join(queue, "001-web"))
+
+	l, err := state.LockLocal(statePath, "alice@host")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer l.Unlock()
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &DaemonCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-once",
+		"-state", statePath,
+		queue,
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if _, err := os.Stat(statePath); !os.IsNotExist(err) {
+		t.Fatalf("state should not be written: %s", err)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(queue, "failed", "001-web.log"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !strings.Contains(string(data), "alice@host") {
+		t.Fatalf("bad: %s", data)
+	}
+}
+
+func TestDaemon_closesPlugins(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	conf, err := config.LoadDir(testFixturePath("apply"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	queue := filepath.Join(td, "queue")
+	statePath := filepath.Join(td, "terraform.tfstate")
+	for _, name := range []string{"001-web", "002-web"} {
+		f, err := os.Create(testDaemonPath(t, filepath.Join(queue, name)))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		err = terraform.WritePlan(&terraform.Plan{
+			Config: conf,
+			Diff: &terraform.Diff{
+				Resources: map[string]*terraform.ResourceDiff{
+					"test_instance.foo": &terraform.ResourceDiff{
+						Attributes: map[string]*terraform.ResourceAttrDiff{
+							"ami": &terraform.ResourceAttrDiff{New: "bar"},
+						},
+					},
+				},
+			},
+		}, f)
+		f.Close()
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	p := testProvider()
+	p.ApplyReturn = &terraform.ResourceState{ID: "foo"}
+	ps, counts := testCloseProviders(p)
+	ui := new(cli.MockUi)
+	c := &DaemonCommand{
+		Meta: Meta{
+			ContextOpts: &terraform.ContextOpts{Providers: ps},
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-once",
+		"-state", statePath,
+		queue,
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	created, closed := counts()
+	if created == 0 || created != closed {
+		t.Fatalf("bad: created %d, closed %d", created, closed)
+	}
+}
+
+func TestDaemon_noQueue(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := &DaemonCommand{
+		Meta: Meta{Ui: ui},
+	}
+
+	if code := c.Run([]string{"-once", "/nope"}); code != 1 {
+		t.Fatalf("bad: %d", code)
+	}
+}
+
+func TestDaemonServeStatus(t *testing.T) {
+	c := new(DaemonCommand)
+	c.status.Queue = "queue"
+	c.status.Current = &DaemonRun{Plan: "002-db", Result: "applying"}
+	c.status.Queued = []string{"003-dns"}
+	c.status.History = []*DaemonRun{
+		&DaemonRun{Plan: "001-web", Result: "applied"},
+	}
+
+	req, err := http.NewRequest("GET", "/status", nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	w := httptest.NewRecorder()
+	c.serveStatus(w, req)
+	if w.Code != 200 {
+		t.Fatalf("bad: %d", w.Code)
+	}
+
+	var actual DaemonStatus
+	if err := json.Unmarshal(w.Body.Bytes(), &actual); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if actual.Current.Plan != "002-db" || len(actual.History) != 1 {
+		t.Fatalf("bad: %#v", actual)
+	}
+	if !reflect.DeepEqual(actual.Queued, []string{"003-dns"}) {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	req, err = http.NewRequest("GET", "/nope", nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	w = httptest.NewRecorder()
+	c.serveStatus(w, req)
+	if w.Code != 404 {
+		t.Fatalf("bad: %d", w.Code)
+	}
+}
+
+func TestDaemonQueue(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	for _, name := range []string{
+		"002-db",
+		"001-web",
+		"001-web" + ApprovalExtension,
+		"001-web" + DefaultSummaryExtension,
+		".daemon123",
+		"applied/000-old",
+	} {
+		testWriteExecutable(t, filepath.Join(td, name), "")
+	}
+
+	actual, err := daemonQueue(td)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := []string{"001-web", "002-db"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+// testDaemonPath creates the directory of the path and returns it.
+func testDaemonPath(t *testing.T, path string) string {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return path
+}
+
+// testDaemonPlan writes an empty plan to the path.
+func testDaemonPlan(t *testing.T, path string) {
+	f, err := os.Create(testDaemonPath(t, path))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer f.Close()
+
+	err = terraform.WritePlan(&terraform.Plan{Config: new(config.Config)}, f)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
@@ -0,0 +1,197 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// debugDumpRedacted replaces the values of sensitive keys in debug dumps.
+const debugDumpRedacted = "<redacted>"
+
+// debugDumpSensitive are the substrings of keys whose values are
+// redacted from debug dumps.
+var debugDumpSensitive = []string{
+	"access_key",
+	"api_key",
+	"password",
+	"private_key",
+	"secret",
+	"token",
+}
+
+// debugDump writes the calls made to providers into a directory, one
+// JSON file per call, numbered in the order the calls were made.
+type debugDump struct {
+	Dir string
+
+	// Redactor, if set, also redacts the sensitive values it knows of
+	// wherever they appear.
+	Redactor *redactor
+
+	l sync.Mutex
+	n int
+}
+
+// DebugDumpCall is the contents of a single file in a debug dump.
+type DebugDumpCall struct {
+	Provider string
+	Method   string
+	Request  interface{}
+	Response interface{}
+	Error    string `json:",omitempty"`
+}
+
+// Providers returns the given provider factories wrapped so that their
+// Diff, Apply and Refresh calls are written to the dump.
+func (d *debugDump) Providers(
+	ps map[string]terraform.ResourceProviderFactory) map[string]terraform.ResourceProviderFactory {
+	result := make(map[string]terraform.ResourceProviderFactory)
+	for k, f := range ps {
+		name := k
+		factory := f
+		result[k] = func() (terraform.ResourceProvider, error) {
+			p, err := factory()
+			if err != nil {
+				return nil, err
+			}
+
+			return &debugDumpProvider{
+				ResourceProvider: p,
+				Name:             name,
+				Dump:             d,
+			}, nil
+		}
+	}
+
+	return result
+}
+
+func (d *debugDump) write(
+	provider, method string, req, resp interface{}, err error) error {
+	call := &DebugDumpCall{
+		Provider: provider,
+		Method:   method,
+		Request:  req,
+		Response: resp,
+	}
+	if err != nil {
+		call.Error = err.Error()
+	}
+
+	// Round-trip through JSON to get a generic structure that we can
+	// redact without modifying the real values.
+	data, err := json.Marshal(call)
+	if err != nil {
+		return err
+	}
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	data, err = json.MarshalIndent(redact(raw, d.Redactor), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	if err := os.MkdirAll(d.Dir, 0755); err != nil {
+		return err
+	}
+
+	d.n++
+	path := filepath.Join(
+		d.Dir, fmt.Sprintf("%04d-%s-%s.json", d.n, provider, method))
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// redact replaces the values of sensitive keys anywhere within v, as
+// well as the sensitive values that r knows of.
+func redact(v interface{}, r *redactor) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, v := range t {
+			if isSensitiveKey(k) {
+				t[k] = debugDumpRedacted
+				continue
+			}
+
+			t[k] = redact(v, r)
+		}
+	case []interface{}:
+		for i, v := range t {
+			t[i] = redact(v, r)
+		}
+	case string:
+		return r.Redact(t)
+	}
+
+	return v
+}
+
+func isSensitiveKey(k string) bool {
+	k = strings.ToLower(k)
+	for _, s := range debugDumpSensitive {
+		if strings.Contains(k, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// debugDumpProvider is a ResourceProvider that writes the calls to the
+// provider it wraps into a debug dump.
+type debugDumpProvider struct {
+	terraform.ResourceProvider
+
+	Name string
+	Dump *debugDump
+}
+
+func (p *debugDumpProvider) Apply(
+	s *terraform.ResourceState,
+	d *terraform.ResourceDiff) (*terraform.ResourceState, error) {
+	result, err := p.ResourceProvider.Apply(s, d)
+	p.write("Apply", map[string]interface{}{
+		"State": s,
+		"Diff":  d,
+	}, result, err)
+	return result, err
+}
+
+func (p *debugDumpProvider) Diff(
+	s *terraform.ResourceState,
+	c *terraform.ResourceConfig) (*terraform.ResourceDiff, error) {
+	result, err := p.ResourceProvider.Diff(s, c)
+	p.write("Diff", map[string]interface{}{
+		"State":  s,
+		"Config": c,
+	}, result, err)
+	return result, err
+}
+
+func (p *debugDumpProvider) Refresh(
+	s *terraform.ResourceState) (*terraform.ResourceState, error) {
+	result, err := p.ResourceProvider.Refresh(s)
+	p.write("Refresh", map[string]interface{}{
+		"State": s,
+	}, result, err)
+	return result, err
+}
+
+func (p *debugDumpProvider) write(
+	method string, req, resp interface{}, err error) {
+	if werr := p.Dump.write(p.Name, method, req, resp, err); werr != nil {
+		log.Printf("[ERROR] Error writing debug dump: %s", werr)
+	}
+}
@@ -0,0 +1,459 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// DescribeCommand is a Command implementation that describes a resource,
+// or a type of resource, by combining the schema of its provider with
+// its configuration and state, for editors and other tools.
+type DescribeCommand struct {
+	Meta
+}
+
+// DescribeResult is what the describe command outputs with -json.
+// Address, Config and Instances are only set when a resource is
+// described, rather than a type.
+type DescribeResult struct {
+	Type     string `json:"type"`
+	Provider string `json:"provider"`
+	Address  string `json:"address,omitempty"`
+
+	// Attributes are the attributes of the schema of the resource, with
+	// the values they have in the configuration.
+	Attributes []*DescribeAttribute `json:"attributes"`
+
+	Config    *DescribeConfig     `json:"config,omitempty"`
+	Instances []*DescribeInstance `json:"instances,omitempty"`
+
+	// ConfigError is why the configuration couldn't be loaded, if it
+	// couldn't. The schema and state are still described.
+	ConfigError string `json:"config_error,omitempty"`
+}
+
+// DescribeAttribute is an attribute of a resource in the output of the
+// describe command with -json. Config is the value of the attribute in
+// the configuration, uninterpolated. Attributes that are set in the
+// configuration but aren't in the schema are Unknown.
+type DescribeAttribute struct {
+	Name       string               `json:"name"`
+	Type       string               `json:"type,omitempty"`
+	Required   bool                 `json:"required,omitempty"`
+	Optional   bool                 `json:"optional,omitempty"`
+	Computed   bool                 `json:"computed,omitempty"`
+	ForceNew   bool                 `json:"force_new,omitempty"`
+	WriteOnly  bool                 `json:"write_only,omitempty"`
+	Deprecated string               `json:"deprecated,omitempty"`
+	ElemType   string               `json:"elem_type,omitempty"`
+	Attributes []*DescribeAttribute `json:"attributes,omitempty"`
+	Unknown    bool                 `json:"unknown,omitempty"`
+	Config     interface{}          `json:"config,omitempty"`
+}
+
+// DescribeConfig is where a resource is defined in the configuration.
+type DescribeConfig struct {
+	File      string   `json:"file,omitempty"`
+	Line      int      `json:"line,omitempty"`
+	Count     int      `json:"count"`
+	DependsOn []string `json:"depends_on,omitempty"`
+}
+
+// DescribeInstance is a resource in the state.
+type DescribeInstance struct {
+	Address    string            `json:"address"`
+	ID         string            `json:"id"`
+	Tainted    bool              `json:"tainted,omitempty"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+func (c *DescribeCommand) Run(args []string) int {
+	var statePath string
+	var jsonOutput bool
+
+	args = c.Meta.process(args, false)
+
+	cmdFlags := flag.NewFlagSet("describe", flag.ContinueOnError)
+	cmdFlags.StringVar(&statePath, "state", DefaultStateFilename, "path")
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = cmdFlags.Args()
+	if len(args) < 1 || len(args) > 2 {
+		c.Ui.Error(
+			"The describe command expects a resource address or type, and\n" +
+				"optionally the path to a Terraform configuration.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+
+	var path string
+	if len(args) == 2 {
+		path = c.path(args[1])
+	} else {
+		var err error
+		path, err = c.wd()
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error getting pwd: %s", err))
+			return 1
+		}
+	}
+
+	result, err := c.describe(args[0], path, c.path(statePath))
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error encoding description: %s", err))
+			return 1
+		}
+		c.Ui.Output(string(data))
+		return 0
+	}
+
+	c.Ui.Output(c.Colorize().Color(formatDescribe(result)))
+	return 0
+}
+
+// describe describes the resource at the address, or the type of
+// resource if addr has no name, from the configuration at path and the
+// state at statePath.
+func (c *DescribeCommand) describe(
+	addr, path, statePath string) (*DescribeResult, error) {
+	parts := strings.SplitN(addr, ".", 3)
+	result := &DescribeResult{Type: parts[0]}
+
+	name, rt, err := c.resourceType(result.Type)
+	if err != nil {
+		return nil, err
+	}
+	result.Provider = name
+	result.Attributes = describeAttributes(rt.Attributes)
+
+	if len(parts) == 1 {
+		return result, nil
+	}
+	result.Address = addr
+	id := parts[0] + "." + parts[1]
+
+	conf, err := config.LoadDir(path)
+	if err != nil {
+		result.ConfigError = err.Error()
+	} else {
+		for _, r := range conf.Resources {
+			if r.Id() != id {
+				continue
+			}
+
+			result.Config = &DescribeConfig{
+				File:      r.Pos.Filename,
+				Line:      r.Pos.Line,
+				Count:     r.Count,
+				DependsOn: r.DependsOn,
+			}
+			result.Attributes = describeConfig(
+				result.Attributes, r.RawConfig.Raw, len(rt.Attributes) > 0)
+			break
+		}
+	}
+
+	state, err := c.readState(statePath)
+	if err != nil {
+		return nil, err
+	}
+	if state != nil {
+		result.Instances = describeInstances(state, addr, rt.Attributes)
+	}
+
+	if result.Config == nil && len(result.Instances) == 0 {
+		return nil, fmt.Errorf(
+			"Resource '%s' isn't in the configuration or the state.", addr)
+	}
+
+	return result, nil
+}
+
+// resourceType returns the provider that manages resources of type t,
+// along with its description of the type. The provider with the longest
+// name is used if several match.
+func (c *DescribeCommand) resourceType(
+	t string) (string, *terraform.ResourceType, error) {
+	names := make([]string, 0, 1)
+	for name, _ := range c.ContextOpts.Providers {
+		if strings.HasPrefix(t, name) {
+			names = append(names, name)
+		}
+	}
+	// The names are all prefixes of t, so the longest sort last
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+
+	for _, name := range names {
+		p, err := c.ContextOpts.Providers[name]()
+		if err != nil {
+			return "", nil, fmt.Errorf(
+				"Error loading provider %s: %s", name, err)
+		}
+
+		for _, rt := range p.Resources() {
+			if rt.Name == t {
+				return name, &rt, nil
+			}
+		}
+	}
+
+	return "", nil, fmt.Errorf("No provider manages resources of type '%s'.", t)
+}
+
+// readState reads the state at path, returning nil if there isn't one.
+func (c *DescribeCommand) readState(path string) (*terraform.State, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error loading state: %s", err)
+	}
+	defer f.Close()
+
+	state, err := terraform.ReadState(f)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading state: %s", err)
+	}
+
+	return state, nil
+}
+
+func (c *DescribeCommand) Help() string {
+	helpText := `
+Usage: terraform describe [options] ADDRESS [DIR]
+
+  Describes a resource by combining the schema of its provider with its
+  configuration and state. The address is either the address of a
+  resource, such as "aws_instance.web" or "aws_instance.web.1", or a type
+  of resource, such as "aws_instance", to only describe its schema.
+
+  This is meant for editors and other tools, which can show attributes
+  and their values without reading the configuration themselves.
+
+Options:
+
+  -json               Output the description as JSON.
+
+  -no-color           If specified, output won't contain any color.
+
+  -state=path         Path to the state file to read. Defaults to
+                      "terraform.tfstate".
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *DescribeCommand) Synopsis() string {
+	return "Describe a resource from its schema, config and state"
+}
+
+// describeAttributes returns the attributes of a resource schema for the
+// output of the describe command.
+func describeAttributes(attrs []terraform.ResourceAttribute) []*DescribeAttribute {
+	result := make([]*DescribeAttribute, len(attrs))
+	for i, a := range attrs {
+		result[i] = &DescribeAttribute{
+			Name:       a.Name,
+			Type:       a.Type,
+			Required:   a.Required,
+			Optional:   a.Optional,
+			Computed:   a.Computed,
+			ForceNew:   a.ForceNew,
+			WriteOnly:  a.WriteOnly,
+			Deprecated: a.Deprecated,
+			ElemType:   a.ElemType,
+		}
+		if len(a.Attributes) > 0 {
+			result[i].Attributes = describeAttributes(a.Attributes)
+		}
+	}
+
+	return result
+}
+
+// describeConfig sets the values of the attributes from the raw
+// configuration of a resource. Keys that aren't attributes are added,
+// and are unknown if the provider has a schema to know them by.
+func describeConfig(
+	attrs []*DescribeAttribute,
+	raw map[string]interface{},
+	schema bool) []*DescribeAttribute {
+	byName := make(map[string]*DescribeAttribute, len(attrs))
+	for _, a := range attrs {
+		byName[a.Name] = a
+	}
+
+	keys := make([]string, 0, len(raw))
+	for k, _ := range raw {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		a, ok := byName[k]
+		if !ok {
+			a = &DescribeAttribute{Name: k, Unknown: schema}
+			attrs = append(attrs, a)
+		}
+		a.Config = raw[k]
+	}
+
+	return attrs
+}
+
+// describeInstances returns the resources in the state at the address,
+// which are all the instances of the resource if it has no index.
+// Write-only attributes are never shown.
+func describeInstances(
+	s *terraform.State,
+	addr string,
+	attrs []terraform.ResourceAttribute) []*DescribeInstance {
+	writeOnly := make(map[string]struct{})
+	for _, a := range attrs {
+		if a.WriteOnly {
+			writeOnly[a.Name] = struct{}{}
+		}
+	}
+
+	keys := make([]string, 0, 1)
+	for k, _ := range s.Resources {
+		if k == addr || strings.HasPrefix(k, addr+".") {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	result := make([]*DescribeInstance, 0, len(keys))
+	for _, k := range keys {
+		rs := s.Resources[k]
+		_, tainted := s.Tainted[k]
+
+		inst := &DescribeInstance{
+			Address:    k,
+			ID:         rs.ID,
+			Tainted:    tainted,
+			Attributes: make(map[string]string, len(rs.Attributes)),
+		}
+		for ak, av := range rs.Attributes {
+			if _, ok := writeOnly[strings.SplitN(ak, ".", 2)[0]]; ok {
+				continue
+			}
+			inst.Attributes[ak] = av
+		}
+
+		result = append(result, inst)
+	}
+
+	return result
+}
+
+// formatDescribe returns the description as it's shown to users without
+// -json: the attributes with their flags and configured values, followed
+// by the instances in the state.
+func formatDescribe(r *DescribeResult) string {
+	var buf bytes.Buffer
+
+	title := r.Type
+	if r.Address != "" {
+		title = r.Address
+	}
+	buf.WriteString(fmt.Sprintf("[reset][bold]%s[reset] (provider: %s)\n", title, r.Provider))
+	if r.Config != nil && r.Config.File != "" {
+		buf.WriteString(fmt.Sprintf("  defined at %s:%d\n", r.Config.File, r.Config.Line))
+	}
+	if r.ConfigError != "" {
+		buf.WriteString(fmt.Sprintf(
+			"[yellow]  error loading config: %s[reset]\n", r.ConfigError))
+	}
+
+	buf.WriteString("\n")
+	if len(r.Attributes) == 0 {
+		buf.WriteString("  (no attributes)\n")
+	}
+	for _, a := range r.Attributes {
+		buf.WriteString(fmt.Sprintf("  %s", a.Name))
+		if flags := describeFlags(a); flags != "" {
+			buf.WriteString(fmt.Sprintf(" (%s)", flags))
+		}
+		if a.Config != nil {
+			buf.WriteString(fmt.Sprintf(" = %#v", a.Config))
+		}
+		buf.WriteString("\n")
+	}
+
+	for _, inst := range r.Instances {
+		buf.WriteString(fmt.Sprintf("\n[bold]%s[reset]: %s", inst.Address, inst.ID))
+		if inst.Tainted {
+			buf.WriteString(" (tainted)")
+		}
+		buf.WriteString("\n")
+
+		keys := make([]string, 0, len(inst.Attributes))
+		for k, _ := range inst.Attributes {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			buf.WriteString(fmt.Sprintf("  %s = %s\n", k, inst.Attributes[k]))
+		}
+	}
+
+	return strings.TrimSpace(buf.String())
+}
+
+// describeFlags returns the type and flags of an attribute as they're
+// shown to users.
+func describeFlags(a *DescribeAttribute) string {
+	if a.Unknown {
+		return "unknown"
+	}
+
+	var flags []string
+	if a.Type != "" {
+		t := a.Type
+		if a.ElemType != "" {
+			t = fmt.Sprintf("%s of %s", t, a.ElemType)
+		}
+		flags = append(flags, t)
+	}
+	if a.Required {
+		flags = append(flags, "required")
+	}
+	if a.Optional {
+		flags = append(flags, "optional")
+	}
+	if a.Computed {
+		flags = append(flags, "computed")
+	}
+	if a.ForceNew {
+		flags = append(flags, "forces new resource")
+	}
+	if a.WriteOnly {
+		flags = append(flags, "write-only")
+	}
+	if a.Deprecated != "" {
+		flags = append(flags, "deprecated")
+	}
+
+	return strings.Join(flags, ", ")
+}
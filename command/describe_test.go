@@ -0,0 +1,330 @@
+package command
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+)
+
+func TestDescribeCommand_implements(t *testing.T) {
+	var _ cli.Command = &DescribeCommand{}
+}
+
+func TestDescribe_type(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := &DescribeCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testDescribeProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{"-json", "test_instance", testFixturePath("describe")}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	result := testDescribeResult(t, ui)
+	if result.Type != "test_instance" || result.Provider != "test" {
+		t.Fatalf("bad: %#v", result)
+	}
+	if result.Address != "" || result.Config != nil || result.Instances != nil {
+		t.Fatalf("bad: %#v", result)
+	}
+
+	expected := []*DescribeAttribute{
+		&DescribeAttribute{
+			Name:     "ami",
+			Type:     "string",
+			Required: true,
+			ForceNew: true,
+		},
+		&DescribeAttribute{
+			Name:     "ports",
+			Type:     "list",
+			Optional: true,
+			ElemType: "int",
+		},
+		&DescribeAttribute{
+			Name:      "password",
+			Type:      "string",
+			Optional:  true,
+			WriteOnly: true,
+		},
+	}
+	if !reflect.DeepEqual(result.Attributes, expected) {
+		t.Fatalf("bad: %#v", result.Attributes)
+	}
+}
+
+func TestDescribe_resource(t *testing.T) {
+	statePath := testStateFile(t, &terraform.State{
+		Resources: map[string]*terraform.ResourceState{
+			"test_instance.foo.0": &terraform.ResourceState{
+				ID:   "i-abc",
+				Type: "test_instance",
+				Attributes: map[string]string{
+					"ami":      "ami-123",
+					"password": "secret",
+				},
+			},
+			"test_instance.foo.1": &terraform.ResourceState{
+				ID:   "i-def",
+				Type: "test_instance",
+			},
+			"test_instance.bar": &terraform.ResourceState{
+				ID:   "i-ghi",
+				Type: "test_instance",
+			},
+		},
+		Tainted: map[string]struct{}{
+			"test_instance.foo.1": struct{}{},
+		},
+	})
+
+	ui := new(cli.MockUi)
+	c := &DescribeCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testDescribeProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-json",
+		"-state", statePath,
+		"test_instance.foo",
+		testFixturePath("describe"),
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	result := testDescribeResult(t, ui)
+	if result.Address != "test_instance.foo" {
+		t.Fatalf("bad: %#v", result)
+	}
+
+	if result.Config == nil {
+		t.Fatal("config should be described")
+	}
+	if result.Config.Count != 2 || result.Config.Line != 1 {
+		t.Fatalf("bad: %#v", result.Config)
+	}
+	if !strings.HasSuffix(result.Config.File, "main.tf") {
+		t.Fatalf("bad: %#v", result.Config)
+	}
+
+	configs := make(map[string]interface{})
+	var unknown []string
+	for _, a := range result.Attributes {
+		if a.Config != nil {
+			configs[a.Name] = a.Config
+		}
+		if a.Unknown {
+			unknown = append(unknown, a.Name)
+		}
+	}
+	expectedConfigs := map[string]interface{}{
+		"ami":    "${var.ami}",
+		"flavor": "small",
+	}
+	if !reflect.DeepEqual(configs, expectedConfigs) {
+		t.Fatalf("bad: %#v", configs)
+	}
+	if !reflect.DeepEqual(unknown, []string{"flavor"}) {
+		t.Fatalf("bad: %#v", unknown)
+	}
+
+	expected := []*DescribeInstance{
+		&DescribeInstance{
+			Address: "test_instance.foo.0",
+			ID:      "i-abc",
+			Attributes: map[string]string{
+				"ami": "ami-123",
+			},
+		},
+		&DescribeInstance{
+			Address:    "test_instance.foo.1",
+			ID:         "i-def",
+			Tainted:    true,
+			Attributes: map[string]string{},
+		},
+	}
+	if !reflect.DeepEqual(result.Instances, expected) {
+		t.Fatalf("bad: %#v", result.Instances)
+	}
+}
+
+func TestDescribe_index(t *testing.T) {
+	statePath := testStateFile(t, &terraform.State{
+		Resources: map[string]*terraform.ResourceState{
+			"test_instance.foo.0": &terraform.ResourceState{
+				ID:   "i-abc",
+				Type: "test_instance",
+			},
+			"test_instance.foo.1": &terraform.ResourceState{
+				ID:   "i-def",
+				Type: "test_instance",
+			},
+		},
+	})
+
+	ui := new(cli.MockUi)
+	c := &DescribeCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testDescribeProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-json",
+		"-state", statePath,
+		"test_instance.foo.1",
+		testFixturePath("describe"),
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	result := testDescribeResult(t, ui)
+	if result.Config == nil {
+		t.Fatal("config should be described")
+	}
+	if len(result.Instances) != 1 || result.Instances[0].ID != "i-def" {
+		t.Fatalf("bad: %#v", result.Instances)
+	}
+}
+
+func TestDescribe_configError(t *testing.T) {
+	statePath := testStateFile(t, &terraform.State{
+		Resources: map[string]*terraform.ResourceState{
+			"test_instance.foo": &terraform.ResourceState{
+				ID:   "i-abc",
+				Type: "test_instance",
+			},
+		},
+	})
+
+	ui := new(cli.MockUi)
+	c := &DescribeCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testDescribeProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-json",
+		"-state", statePath,
+		"test_instance.foo",
+		testTempDir(t),
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	result := testDescribeResult(t, ui)
+	if result.ConfigError == "" {
+		t.Fatalf("bad: %#v", result)
+	}
+	if len(result.Instances) != 1 {
+		t.Fatalf("bad: %#v", result.Instances)
+	}
+}
+
+func TestDescribe_missing(t *testing.T) {
+	cases := []string{
+		"test_instance.bar",
+		"other_instance",
+	}
+
+	for _, addr := range cases {
+		ui := new(cli.MockUi)
+		c := &DescribeCommand{
+			Meta: Meta{
+				ContextOpts: testCtxConfig(testDescribeProvider()),
+				Ui:          ui,
+			},
+		}
+
+		args := []string{addr, testFixturePath("describe")}
+		if code := c.Run(args); code != 1 {
+			t.Fatalf("%s: bad: %d", addr, code)
+		}
+	}
+}
+
+func TestDescribe_human(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := &DescribeCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testDescribeProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{"-no-color", "test_instance", testFixturePath("describe")}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	actual := strings.TrimSpace(ui.OutputWriter.String())
+	expected := strings.TrimSpace(testDescribeHumanStr)
+	if actual != expected {
+		t.Fatalf("bad:\n\n%s", actual)
+	}
+}
+
+func testDescribeProvider() *terraform.MockResourceProvider {
+	p := testProvider()
+	p.ResourcesReturn = []terraform.ResourceType{
+		terraform.ResourceType{
+			Name: "test_instance",
+			Attributes: []terraform.ResourceAttribute{
+				terraform.ResourceAttribute{
+					Name:     "ami",
+					Type:     "string",
+					Required: true,
+					ForceNew: true,
+				},
+				terraform.ResourceAttribute{
+					Name:     "ports",
+					Type:     "list",
+					Optional: true,
+					ElemType: "int",
+				},
+				terraform.ResourceAttribute{
+					Name:      "password",
+					Type:      "string",
+					Optional:  true,
+					WriteOnly: true,
+				},
+			},
+		},
+	}
+
+	return p
+}
+
+func testDescribeResult(t *testing.T, ui *cli.MockUi) *DescribeResult {
+	var result DescribeResult
+	if err := json.Unmarshal(ui.OutputWriter.Bytes(), &result); err != nil {
+		t.Fatalf("err: %s\n\n%s", err, ui.OutputWriter.String())
+	}
+
+	return &result
+}
+
+const testDescribeHumanStr = `
+test_instance (provider: test)
+
+  ami (string, required, forces new resource)
+  ports (list of int, optional)
+  password (string, optional, write-only)
+`
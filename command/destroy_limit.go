@@ -0,0 +1,58 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// planDestroys returns how many resources the plan destroys, including
+// the resources it replaces, and how many resources are in its state.
+func planDestroys(p *terraform.Plan) (int, int) {
+	var destroys, total int
+	if p.Diff != nil {
+		for _, rd := range p.Diff.Resources {
+			if rd.Destroy {
+				destroys++
+			}
+		}
+	}
+	if p.State != nil {
+		for _, rs := range p.State.Resources {
+			if rs.ID != "" {
+				total++
+			}
+		}
+	}
+
+	return destroys, total
+}
+
+// checkDestroyLimit returns why the plan destroys more resources than
+// MaxDestroy or MaxDestroyPercent allow, or an empty string if it
+// doesn't. This protects against plans that destroy much more than
+// intended, such as when a typo in a variable changes every resource.
+func (m *Meta) checkDestroyLimit(p *terraform.Plan) string {
+	destroys, total := planDestroys(p)
+	if destroys == 0 {
+		return ""
+	}
+
+	if m.MaxDestroy > 0 && destroys > m.MaxDestroy {
+		return fmt.Sprintf(
+			"the plan destroys %d resources, more than the limit of %d",
+			destroys, m.MaxDestroy)
+	}
+
+	if m.MaxDestroyPercent > 0 && total > 0 {
+		percent := float64(destroys) * 100 / float64(total)
+		if percent > m.MaxDestroyPercent {
+			return fmt.Sprintf(
+				"the plan destroys %d of %d resources (%.0f%%), more than "+
+					"the limit of %.0f%%",
+				destroys, total, percent, m.MaxDestroyPercent)
+		}
+	}
+
+	return ""
+}
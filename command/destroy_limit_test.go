@@ -0,0 +1,68 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestMetaCheckDestroyLimit(t *testing.T) {
+	plan := &terraform.Plan{
+		Diff: &terraform.Diff{
+			Resources: map[string]*terraform.ResourceDiff{
+				"aws_instance.a": &terraform.ResourceDiff{Destroy: true},
+				"aws_instance.b": &terraform.ResourceDiff{
+					Destroy: true,
+					Attributes: map[string]*terraform.ResourceAttrDiff{
+						"ami": &terraform.ResourceAttrDiff{
+							Old:         "ami-1",
+							New:         "ami-2",
+							RequiresNew: true,
+						},
+					},
+				},
+				"aws_instance.c": &terraform.ResourceDiff{
+					Attributes: map[string]*terraform.ResourceAttrDiff{
+						"tags": &terraform.ResourceAttrDiff{Old: "a", New: "b"},
+					},
+				},
+			},
+		},
+		State: &terraform.State{
+			Resources: map[string]*terraform.ResourceState{
+				"aws_instance.a": &terraform.ResourceState{ID: "a"},
+				"aws_instance.b": &terraform.ResourceState{ID: "b"},
+				"aws_instance.c": &terraform.ResourceState{ID: "c"},
+				"aws_instance.d": &terraform.ResourceState{ID: "d"},
+			},
+		},
+	}
+
+	cases := []struct {
+		Max      int
+		Percent  float64
+		Expected string
+	}{
+		{0, 0, ""},
+		{2, 0, ""},
+		{1, 0, "the plan destroys 2 resources, more than the limit of 1"},
+		{0, 50, ""},
+		{
+			0, 25,
+			"the plan destroys 2 of 4 resources (50%), more than the limit of 25%",
+		},
+	}
+
+	for i, tc := range cases {
+		m := &Meta{MaxDestroy: tc.Max, MaxDestroyPercent: tc.Percent}
+		if actual := m.checkDestroyLimit(plan); actual != tc.Expected {
+			t.Fatalf("%d: bad: %q", i, actual)
+		}
+	}
+
+	// Nothing is destroyed
+	m := &Meta{MaxDestroy: 1, MaxDestroyPercent: 1}
+	if actual := m.checkDestroyLimit(new(terraform.Plan)); actual != "" {
+		t.Fatalf("bad: %q", actual)
+	}
+}
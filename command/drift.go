@@ -0,0 +1,192 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// DriftCommand is a Command implementation that refreshes the state and
+// reports the resources that were changed outside of Terraform, without
+// comparing them to the configuration.
+type DriftCommand struct {
+	Meta
+}
+
+// ResourceDrift is how a resource changed outside of Terraform since the
+// state was last written.
+type ResourceDrift struct {
+	// Deleted is true if the resource no longer exists.
+	Deleted bool
+
+	// Attributes are the attributes that changed, keyed by name.
+	Attributes map[string]*AttrDrift
+}
+
+// AttrDrift is how a single attribute changed. Removed is true if the
+// attribute no longer has a value.
+type AttrDrift struct {
+	Old     string
+	New     string
+	Removed bool
+}
+
+// StateDrift compares a state to the same state after it was refreshed,
+// and returns the resources that changed, keyed by name.
+func StateDrift(before, after *terraform.State) map[string]*ResourceDrift {
+	result := make(map[string]*ResourceDrift)
+	if before == nil {
+		return result
+	}
+
+	for name, rs := range before.Resources {
+		if rs.ID == "" {
+			continue
+		}
+
+		var refreshed *terraform.ResourceState
+		if after != nil {
+			refreshed = after.Resources[name]
+		}
+		if refreshed == nil || refreshed.ID == "" {
+			result[name] = &ResourceDrift{Deleted: true}
+			continue
+		}
+
+		attrs := make(map[string]*AttrDrift)
+		for k, v := range rs.Attributes {
+			nv, ok := refreshed.Attributes[k]
+			if !ok {
+				attrs[k] = &AttrDrift{Old: v, Removed: true}
+			} else if nv != v {
+				attrs[k] = &AttrDrift{Old: v, New: nv}
+			}
+		}
+		for k, v := range refreshed.Attributes {
+			if _, ok := rs.Attributes[k]; !ok {
+				attrs[k] = &AttrDrift{New: v}
+			}
+		}
+		if refreshed.ID != rs.ID {
+			attrs["id"] = &AttrDrift{Old: rs.ID, New: refreshed.ID}
+		}
+
+		if len(attrs) > 0 {
+			result[name] = &ResourceDrift{Attributes: attrs}
+		}
+	}
+
+	return result
+}
+
+func (c *DriftCommand) Run(args []string) int {
+	var statePath string
+
+	args = c.Meta.process(args, true)
+
+	cmdFlags := c.Meta.flagSet("drift")
+	cmdFlags.StringVar(&statePath, "state", DefaultStateFilename, "path")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		return 1
+	}
+
+	var path string
+	args = cmdFlags.Args()
+	if len(args) > 1 {
+		c.Ui.Error(
+			"The drift command expects at most one argument with the path\n" +
+				"to a Terraform configuration.\n")
+		cmdFlags.Usage()
+		return 1
+	} else if len(args) == 1 {
+		path = c.path(args[0])
+	} else {
+		var err error
+		path, err = c.wd()
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error getting pwd: %s", err))
+		}
+	}
+
+	// Without a state there is nothing that could have drifted
+	statePath = c.path(statePath)
+	if _, err := os.Stat(statePath); err != nil {
+		c.Ui.Error(fmt.Sprintf(
+			"The state file %s can't be read, so there is nothing to\n"+
+				"check for drift: %s", statePath, err))
+		return 1
+	}
+
+	ctx, planned, err := c.Context(path, statePath)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	if planned {
+		c.Ui.Error("The drift command expects a configuration, not a plan.")
+		return 1
+	}
+	if !c.validateContext(ctx) {
+		return 1
+	}
+
+	// The refreshed state is only kept in memory, so that the drift is
+	// reported again until it's dealt with.
+	refreshed, err := ctx.Refresh()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error refreshing state: %s", err))
+		return 1
+	}
+
+	drift := StateDrift(c.state, refreshed)
+	if len(drift) == 0 {
+		c.Ui.Output(c.Colorize().Color(
+			"[reset][green]No drift detected. The infrastructure matches the state."))
+		return 0
+	}
+
+	c.Ui.Output(FormatDrift(drift, c.Colorize()))
+	c.Ui.Output(c.Colorize().Color(fmt.Sprintf(
+		"\n[reset][bold]Drift detected: %d resource(s) changed outside of Terraform.",
+		len(drift))))
+	return 2
+}
+
+func (c *DriftCommand) Help() string {
+	helpText := `
+Usage: terraform drift [options] [dir]
+
+  Refreshes the state and reports the resources, and the attributes of
+  resources, that were changed outside of Terraform since the state was
+  last written. Unlike plan, the configuration isn't compared to the
+  infrastructure, so only changes made outside of Terraform are shown.
+
+  The state file isn't changed.
+
+  The exit code is 0 if nothing drifted, 1 on errors, and 2 if resources
+  drifted, so that it can be checked by scheduled jobs.
+
+Options:
+
+  -no-color           If specified, output won't contain any color.
+
+  -state=path         Path to read the state from. Defaults to
+                      "terraform.tfstate".
+
+  -var 'foo=bar'      Set a variable in the Terraform configuration. This
+                      flag can be set multiple times.
+
+  -var-file=foo       Set variables in the Terraform configuration from
+                      a file. If "terraform.tfvars" is present, it will be
+                      automatically loaded if this flag is not specified.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *DriftCommand) Synopsis() string {
+	return "Report resources changed outside of Terraform"
+}
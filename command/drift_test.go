@@ -0,0 +1,209 @@
+package command
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+	"github.com/mitchellh/colorstring"
+)
+
+func TestStateDrift(t *testing.T) {
+	before := &terraform.State{
+		Resources: map[string]*terraform.ResourceState{
+			"aws_instance.same": &terraform.ResourceState{
+				ID:         "i-1",
+				Attributes: map[string]string{"ami": "ami-1"},
+			},
+			"aws_instance.changed": &terraform.ResourceState{
+				ID: "i-2",
+				Attributes: map[string]string{
+					"ami":           "ami-1",
+					"instance_type": "m1.small",
+					"key_name":      "deploy",
+				},
+			},
+			"aws_instance.deleted": &terraform.ResourceState{ID: "i-3"},
+			"aws_instance.tainted": &terraform.ResourceState{},
+		},
+	}
+	after := &terraform.State{
+		Resources: map[string]*terraform.ResourceState{
+			"aws_instance.same": &terraform.ResourceState{
+				ID:         "i-1",
+				Attributes: map[string]string{"ami": "ami-1"},
+			},
+			"aws_instance.changed": &terraform.ResourceState{
+				ID: "i-2",
+				Attributes: map[string]string{
+					"ami":               "ami-1",
+					"instance_type":     "m1.large",
+					"security_groups.#": "1",
+				},
+			},
+			"aws_instance.deleted": &terraform.ResourceState{},
+		},
+	}
+
+	actual := StateDrift(before, after)
+	expected := map[string]*ResourceDrift{
+		"aws_instance.changed": &ResourceDrift{
+			Attributes: map[string]*AttrDrift{
+				"instance_type":     &AttrDrift{Old: "m1.small", New: "m1.large"},
+				"key_name":          &AttrDrift{Old: "deploy", Removed: true},
+				"security_groups.#": &AttrDrift{New: "1"},
+			},
+		},
+		"aws_instance.deleted": &ResourceDrift{Deleted: true},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestFormatDrift(t *testing.T) {
+	drift := map[string]*ResourceDrift{
+		"aws_instance.foo": &ResourceDrift{
+			Attributes: map[string]*AttrDrift{
+				"instance_type": &AttrDrift{Old: "m1.small", New: "m1.large"},
+				"key_name":      &AttrDrift{Old: "deploy", Removed: true},
+			},
+		},
+		"aws_instance.bar": &ResourceDrift{Deleted: true},
+	}
+
+	actual := FormatDrift(drift, &colorstring.Colorize{
+		Colors:  colorstring.DefaultColors,
+		Disable: true,
+	})
+	expected := strings.TrimSpace(`
+- aws_instance.bar (deleted outside of Terraform)
+
+~ aws_instance.foo
+    instance_type: "m1.small" => "m1.large"
+    key_name:      "deploy" => <removed>
+`)
+	if actual != expected {
+		t.Fatalf("bad:\n%s", actual)
+	}
+}
+
+func TestDrift(t *testing.T) {
+	state := &terraform.State{
+		Resources: map[string]*terraform.ResourceState{
+			"test_instance.foo": &terraform.ResourceState{
+				ID:         "bar",
+				Type:       "test_instance",
+				Attributes: map[string]string{"ami": "bar"},
+			},
+		},
+	}
+	statePath := testStateFile(t, state)
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &DriftCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	p.RefreshFn = nil
+	p.RefreshReturn = &terraform.ResourceState{
+		ID:         "bar",
+		Type:       "test_instance",
+		Attributes: map[string]string{"ami": "baz"},
+	}
+
+	args := []string{
+		"-state", statePath,
+		testFixturePath("refresh"),
+	}
+	if code := c.Run(args); code != 2 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if !p.RefreshCalled {
+		t.Fatal("refresh should be called")
+	}
+	if p.DiffCalled {
+		t.Fatal("diff should not be called")
+	}
+
+	output := ui.OutputWriter.String()
+	if !strings.Contains(output, `ami: "bar" => "baz"`) {
+		t.Fatalf("bad: %s", output)
+	}
+
+	// The state isn't changed
+	f, err := os.Open(statePath)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer f.Close()
+	newState, err := terraform.ReadState(f)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	actual := newState.Resources["test_instance.foo"].Attributes["ami"]
+	if actual != "bar" {
+		t.Fatalf("bad: %s", actual)
+	}
+}
+
+func TestDrift_none(t *testing.T) {
+	state := &terraform.State{
+		Resources: map[string]*terraform.ResourceState{
+			"test_instance.foo": &terraform.ResourceState{
+				ID:   "bar",
+				Type: "test_instance",
+			},
+		},
+	}
+	statePath := testStateFile(t, state)
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &DriftCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		testFixturePath("refresh"),
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if !strings.Contains(ui.OutputWriter.String(), "No drift detected") {
+		t.Fatalf("bad: %s", ui.OutputWriter.String())
+	}
+}
+
+func TestDrift_noState(t *testing.T) {
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &DriftCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-state", testTempFile(t),
+		testFixturePath("refresh"),
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if p.RefreshCalled {
+		t.Fatal("refresh should not be called")
+	}
+}
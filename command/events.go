@@ -0,0 +1,298 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+)
+
+// DefaultStatsdPrefix is the prefix of the names of the metrics sent to
+// statsd, if no other prefix is given.
+const DefaultStatsdPrefix = "terraform"
+
+// EventBus publishes the lifecycle events of runs of Terraform to sinks,
+// such as metrics and logging systems. Sinks are configured with
+// "event_sink" blocks in the CLI configuration.
+type EventBus struct {
+	Sinks []EventSink
+}
+
+// Event is a lifecycle event of a run of Terraform.
+type Event struct {
+	// Type is "run_start", "run_complete" or "run_failed" for a run of a
+	// command, and "resource_complete" or "resource_failed" once a
+	// resource has been applied or refreshed.
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+
+	// Command is the name of the command, for the events of runs.
+	Command string `json:"command,omitempty"`
+
+	// Resource is the name of the resource and Action is what was done
+	// to it, such as "create" or "refresh", for the events of resources.
+	Resource string `json:"resource,omitempty"`
+	Action   string `json:"action,omitempty"`
+
+	// Duration is how long the run or the resource took, in
+	// milliseconds, for the events that end them.
+	Duration int64 `json:"duration_ms,omitempty"`
+
+	// ExitCode is the exit code of a command that failed.
+	ExitCode int `json:"exit_code,omitempty"`
+}
+
+// EventSink receives the events published to an event bus.
+type EventSink interface {
+	Send(*Event) error
+}
+
+// Publish sends the event to every sink. Failing to send an event is
+// logged, but doesn't stop anything.
+func (b *EventBus) Publish(e *Event) {
+	if b == nil {
+		return
+	}
+
+	for _, s := range b.Sinks {
+		if err := s.Send(e); err != nil {
+			log.Printf("[ERROR] Error sending %s event to %T: %s", e.Type, s, err)
+		}
+	}
+}
+
+// FileEventSink appends events to a file as JSON lines.
+type FileEventSink struct {
+	Path string
+
+	l sync.Mutex
+}
+
+func (s *FileEventSink) Send(e *Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	f, err := os.OpenFile(s.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(append(data, '\n'))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	return err
+}
+
+// SyslogEventSink sends events to the system log as JSON.
+type SyslogEventSink struct{}
+
+func (s *SyslogEventSink) Send(e *Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	return writeSyslog(data)
+}
+
+// StatsdEventSink sends metrics about events to statsd over UDP. Runs are
+// counted by PREFIX.COMMAND.start, .complete and .failed, and timed by
+// PREFIX.COMMAND.duration. Resources are counted by
+// PREFIX.resource.ACTION.complete and .failed, and timed by
+// PREFIX.resource.ACTION.duration.
+type StatsdEventSink struct {
+	// Address is the host and port of statsd, such as "127.0.0.1:8125".
+	Address string
+
+	// Prefix is the prefix of the names of the metrics. It defaults to
+	// DefaultStatsdPrefix.
+	Prefix string
+}
+
+func (s *StatsdEventSink) Send(e *Event) error {
+	metrics := statsdMetrics(s.Prefix, e)
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	conn, err := net.Dial("udp", s.Address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(strings.Join(metrics, "\n")))
+	return err
+}
+
+// statsdNameRe matches what can't be in a part of a statsd metric name.
+var statsdNameRe = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// statsdMetrics returns the statsd metrics for an event.
+func statsdMetrics(prefix string, e *Event) []string {
+	if prefix == "" {
+		prefix = DefaultStatsdPrefix
+	}
+
+	var name, result string
+	switch e.Type {
+	case "run_start", "run_complete", "run_failed":
+		name = statsdNameRe.ReplaceAllString(e.Command, "_")
+		result = strings.TrimPrefix(e.Type, "run_")
+	case "resource_complete", "resource_failed":
+		name = "resource." + statsdNameRe.ReplaceAllString(e.Action, "_")
+		result = strings.TrimPrefix(e.Type, "resource_")
+	default:
+		return nil
+	}
+
+	metrics := []string{fmt.Sprintf("%s.%s.%s:1|c", prefix, name, result)}
+	if result != "start" {
+		metrics = append(metrics, fmt.Sprintf(
+			"%s.%s.duration:%d|ms", prefix, name, e.Duration))
+	}
+
+	return metrics
+}
+
+// EventCommand is a cli.Command that publishes the start and end of its
+// runs to an event bus.
+type EventCommand struct {
+	cli.Command
+
+	Name string
+	Bus  *EventBus
+}
+
+func (c *EventCommand) Run(args []string) int {
+	start := time.Now()
+	c.Bus.Publish(&Event{
+		Type:    "run_start",
+		Time:    start.UTC(),
+		Command: c.Name,
+	})
+
+	code := c.Command.Run(args)
+
+	// Some commands exit with 2 to say that there are changes, which
+	// isn't a failure.
+	e := &Event{
+		Type:     "run_complete",
+		Time:     time.Now().UTC(),
+		Command:  c.Name,
+		Duration: durationMs(time.Since(start)),
+	}
+	if code == 1 {
+		e.Type = "run_failed"
+		e.ExitCode = code
+	}
+	c.Bus.Publish(e)
+
+	return code
+}
+
+// EventHook is a hook that publishes the events of resources as they
+// are applied and refreshed.
+type EventHook struct {
+	Bus *EventBus
+
+	pending map[string]eventHookResource
+	l       sync.Mutex
+	terraform.NilHook
+}
+
+type eventHookResource struct {
+	Action string
+	Start  time.Time
+}
+
+func (h *EventHook) PreApply(
+	id string,
+	s *terraform.ResourceState,
+	d *terraform.ResourceDiff) (terraform.HookAction, error) {
+	h.start(id, auditAction(s, d))
+	return terraform.HookActionContinue, nil
+}
+
+func (h *EventHook) PostApply(
+	id string,
+	s *terraform.ResourceState,
+	err error) (terraform.HookAction, error) {
+	h.finish(id, err)
+	return terraform.HookActionContinue, nil
+}
+
+func (h *EventHook) PreRefresh(
+	id string, s *terraform.ResourceState) (terraform.HookAction, error) {
+	h.start(id, "refresh")
+	return terraform.HookActionContinue, nil
+}
+
+func (h *EventHook) PostRefresh(
+	id string, s *terraform.ResourceState) (terraform.HookAction, error) {
+	h.finish(id, nil)
+	return terraform.HookActionContinue, nil
+}
+
+func (h *EventHook) start(id, action string) {
+	h.l.Lock()
+	defer h.l.Unlock()
+
+	if h.pending == nil {
+		h.pending = make(map[string]eventHookResource)
+	}
+
+	h.pending[id] = eventHookResource{Action: action, Start: time.Now()}
+}
+
+func (h *EventHook) finish(id string, err error) {
+	h.l.Lock()
+	r, ok := h.pending[id]
+	delete(h.pending, id)
+	h.l.Unlock()
+	if !ok {
+		return
+	}
+
+	e := &Event{
+		Type:     "resource_complete",
+		Time:     time.Now().UTC(),
+		Resource: id,
+		Action:   r.Action,
+		Duration: durationMs(time.Since(r.Start)),
+	}
+	if err != nil {
+		e.Type = "resource_failed"
+	}
+
+	h.Bus.Publish(e)
+}
+
+// eventHook returns the hook that publishes the events of resources, or
+// nil if there are no event sinks.
+func (m *Meta) eventHook() *EventHook {
+	if m.Events == nil || len(m.Events.Sinks) == 0 {
+		return nil
+	}
+
+	return &EventHook{Bus: m.Events}
+}
+
+func durationMs(d time.Duration) int64 {
+	return int64(d / time.Millisecond)
+}
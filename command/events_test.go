@@ -0,0 +1,223 @@
+package command
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+)
+
+func TestEventCommand_impl(t *testing.T) {
+	var _ cli.Command = new(EventCommand)
+}
+
+func TestEventHook_impl(t *testing.T) {
+	var _ terraform.Hook = new(EventHook)
+}
+
+func TestEventBusPublish_nil(t *testing.T) {
+	var b *EventBus
+	b.Publish(&Event{Type: "run_start"})
+}
+
+func TestEventCommand(t *testing.T) {
+	sink := new(testEventSink)
+	c := &EventCommand{
+		Command: &cli.MockCommand{RunResult: 2},
+		Name:    "plan",
+		Bus:     &EventBus{Sinks: []EventSink{sink}},
+	}
+
+	if code := c.Run(nil); code != 2 {
+		t.Fatalf("bad: %d", code)
+	}
+
+	// Exiting with 2 isn't a failure
+	if actual := sink.Types(); !reflect.DeepEqual(
+		actual, []string{"run_start", "run_complete"}) {
+		t.Fatalf("bad: %#v", actual)
+	}
+	if sink.Events[1].Command != "plan" {
+		t.Fatalf("bad: %#v", sink.Events[1])
+	}
+}
+
+func TestEventCommand_failed(t *testing.T) {
+	sink := new(testEventSink)
+	c := &EventCommand{
+		Command: &cli.MockCommand{RunResult: 1},
+		Name:    "apply",
+		Bus:     &EventBus{Sinks: []EventSink{sink}},
+	}
+
+	if code := c.Run(nil); code != 1 {
+		t.Fatalf("bad: %d", code)
+	}
+
+	if actual := sink.Types(); !reflect.DeepEqual(
+		actual, []string{"run_start", "run_failed"}) {
+		t.Fatalf("bad: %#v", actual)
+	}
+	if sink.Events[1].ExitCode != 1 {
+		t.Fatalf("bad: %#v", sink.Events[1])
+	}
+}
+
+func TestEventHook(t *testing.T) {
+	sink := new(testEventSink)
+	h := &EventHook{Bus: &EventBus{Sinks: []EventSink{sink}}}
+
+	s := &terraform.ResourceState{ID: "foo"}
+	d := &terraform.ResourceDiff{Destroy: true}
+	if _, err := h.PreApply("aws_instance.foo", s, d); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := h.PostApply("aws_instance.foo", s, errors.New("bad")); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := h.PreRefresh("aws_instance.bar", s); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := h.PostRefresh("aws_instance.bar", s); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if actual := sink.Types(); !reflect.DeepEqual(
+		actual, []string{"resource_failed", "resource_complete"}) {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	e := sink.Events[0]
+	if e.Resource != "aws_instance.foo" || e.Action != "destroy" {
+		t.Fatalf("bad: %#v", e)
+	}
+	e = sink.Events[1]
+	if e.Resource != "aws_instance.bar" || e.Action != "refresh" {
+		t.Fatalf("bad: %#v", e)
+	}
+}
+
+func TestFileEventSink(t *testing.T) {
+	path := testTempFile(t)
+	defer os.Remove(path)
+
+	s := &FileEventSink{Path: path}
+	for _, typ := range []string{"run_start", "run_complete"} {
+		if err := s.Send(&Event{Type: typ, Command: "apply"}); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("bad: %s", data)
+	}
+
+	var e Event
+	if err := json.Unmarshal([]byte(lines[1]), &e); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if e.Type != "run_complete" || e.Command != "apply" {
+		t.Fatalf("bad: %#v", e)
+	}
+}
+
+func TestStatsdEventSink(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer conn.Close()
+
+	s := &StatsdEventSink{Address: conn.LocalAddr().String(), Prefix: "tf"}
+	err = s.Send(&Event{Type: "run_failed", Command: "apply", Duration: 42})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := "tf.apply.failed:1|c\ntf.apply.duration:42|ms"
+	if actual := string(buf[:n]); actual != expected {
+		t.Fatalf("bad: %q", actual)
+	}
+}
+
+func TestStatsdMetrics(t *testing.T) {
+	cases := []struct {
+		Prefix   string
+		Event    *Event
+		Expected []string
+	}{
+		{
+			"",
+			&Event{Type: "run_start", Command: "apply"},
+			[]string{"terraform.apply.start:1|c"},
+		},
+		{
+			"tf",
+			&Event{Type: "run_complete", Command: "self-update", Duration: 10},
+			[]string{
+				"tf.self-update.complete:1|c",
+				"tf.self-update.duration:10|ms",
+			},
+		},
+		{
+			"",
+			&Event{Type: "resource_failed", Action: "destroy/create", Duration: 5},
+			[]string{
+				"terraform.resource.destroy_create.failed:1|c",
+				"terraform.resource.destroy_create.duration:5|ms",
+			},
+		},
+		{
+			"",
+			&Event{Type: "nope"},
+			nil,
+		},
+	}
+
+	for i, tc := range cases {
+		actual := statsdMetrics(tc.Prefix, tc.Event)
+		if !reflect.DeepEqual(actual, tc.Expected) {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
+
+// testEventSink is an EventSink that records the events it is sent.
+type testEventSink struct {
+	Events []*Event
+}
+
+func (s *testEventSink) Send(e *Event) error {
+	s.Events = append(s.Events, e)
+	return nil
+}
+
+func (s *testEventSink) Types() []string {
+	result := make([]string, len(s.Events))
+	for i, e := range s.Events {
+		result[i] = e.Type
+	}
+
+	return result
+}
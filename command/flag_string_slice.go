@@ -0,0 +1,18 @@
+package command
+
+import (
+	"strings"
+)
+
+// FlagStringSlice is a flag.Value implementation for flags that can be
+// set multiple times, such as '-forget aws_instance.foo'.
+type FlagStringSlice []string
+
+func (v *FlagStringSlice) String() string {
+	return strings.Join(*v, ",")
+}
+
+func (v *FlagStringSlice) Set(raw string) error {
+	*v = append(*v, raw)
+	return nil
+}
@@ -0,0 +1,303 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/colorstring"
+)
+
+// FormatPlanDiff returns a human-readable comparison of two plans, such as
+// those of two runs of CI, showing the resources whose planned actions or
+// attributes differ in the new plan.
+func FormatPlanDiff(oldPlan, newPlan *terraform.Plan, c *colorstring.Colorize) string {
+	if c == nil {
+		c = &colorstring.Colorize{
+			Colors: colorstring.DefaultColors,
+			Reset:  false,
+		}
+	}
+
+	oldDiffs := planResourceDiffs(oldPlan)
+	newDiffs := planResourceDiffs(newPlan)
+
+	names := make([]string, 0, len(oldDiffs)+len(newDiffs))
+	for name, _ := range oldDiffs {
+		names = append(names, name)
+	}
+	for name, _ := range newDiffs {
+		if _, ok := oldDiffs[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	buf := new(bytes.Buffer)
+	for _, name := range names {
+		od, nd := oldDiffs[name], newDiffs[name]
+		switch {
+		case od == nil:
+			buf.WriteString(c.Color(fmt.Sprintf(
+				"[green]+ %s: %s (not in the old plan)\n",
+				name, planResourceAction(newPlan, name, nd))))
+			formatAttrDiffs(buf, nd, nil)
+		case nd == nil:
+			buf.WriteString(c.Color(fmt.Sprintf(
+				"[red]- %s: %s (not in the new plan)\n",
+				name, planResourceAction(oldPlan, name, od))))
+		default:
+			oldAction := planResourceAction(oldPlan, name, od)
+			newAction := planResourceAction(newPlan, name, nd)
+
+			var attrs bytes.Buffer
+			formatAttrDiffs(&attrs, nd, od)
+			if oldAction == newAction && attrs.Len() == 0 {
+				continue
+			}
+
+			header := fmt.Sprintf("[yellow]~ %s: %s", name, newAction)
+			if oldAction != newAction {
+				header += fmt.Sprintf(" (was %s)", oldAction)
+			}
+			buf.WriteString(c.Color(header + "\n"))
+			buf.Write(attrs.Bytes())
+		}
+
+		buf.WriteString(c.Color("[reset]\n"))
+	}
+
+	if buf.Len() == 0 {
+		return "The plans are the same."
+	}
+
+	return strings.TrimSpace(buf.String())
+}
+
+// FormatStateDiff returns a human-readable comparison of two states,
+// showing the resources that were added, removed or changed in the new
+// state, and the outputs that changed.
+func FormatStateDiff(oldState, newState *terraform.State, c *colorstring.Colorize) string {
+	if c == nil {
+		c = &colorstring.Colorize{
+			Colors: colorstring.DefaultColors,
+			Reset:  false,
+		}
+	}
+
+	// The resources that were removed or changed are the drift from the
+	// old state to the new one
+	drift := StateDrift(oldState, newState)
+	for name, rs := range newState.Resources {
+		if rs.ID == "" {
+			continue
+		}
+		if ors, ok := oldState.Resources[name]; !ok || ors.ID == "" {
+			drift[name] = nil
+		}
+	}
+
+	names := make([]string, 0, len(drift))
+	for name, _ := range drift {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buf := new(bytes.Buffer)
+	for _, name := range names {
+		rd := drift[name]
+		switch {
+		case rd == nil:
+			buf.WriteString(c.Color(fmt.Sprintf(
+				"[green]+ %s (ID: %s)\n", name, newState.Resources[name].ID)))
+		case rd.Deleted:
+			buf.WriteString(c.Color(fmt.Sprintf(
+				"[red]- %s (ID: %s)\n", name, oldState.Resources[name].ID)))
+		default:
+			buf.WriteString(c.Color(fmt.Sprintf("[yellow]~ %s\n", name)))
+
+			keys := make([]string, 0, len(rd.Attributes))
+			for k, _ := range rd.Attributes {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+
+			keyLen := maxLen(keys)
+			for _, k := range keys {
+				ad := rd.Attributes[k]
+				v := fmt.Sprintf("%#v", ad.New)
+				if ad.Removed {
+					v = "<removed>"
+				}
+
+				buf.WriteString(fmt.Sprintf(
+					"    %s:%s %#v => %s\n",
+					k,
+					strings.Repeat(" ", keyLen-len(k)),
+					ad.Old,
+					v))
+			}
+		}
+
+		buf.WriteString(c.Color("[reset]\n"))
+	}
+
+	// The values of outputs that are sensitive in either state aren't shown
+	outputs := make([]string, 0)
+	for k, v := range newState.Outputs {
+		if ov, ok := oldState.Outputs[k]; !ok || ov != v {
+			outputs = append(outputs, k)
+		}
+	}
+	for k, _ := range oldState.Outputs {
+		if _, ok := newState.Outputs[k]; !ok {
+			outputs = append(outputs, k)
+		}
+	}
+	sort.Strings(outputs)
+
+	if len(outputs) > 0 {
+		buf.WriteString(c.Color("[reset][bold]Outputs:\n"))
+		keyLen := maxLen(outputs)
+		for _, k := range outputs {
+			ov, oldOk := oldState.Outputs[k]
+			nv, newOk := newState.Outputs[k]
+
+			var v string
+			switch {
+			case sensitiveOutput(oldState, k) || sensitiveOutput(newState, k):
+				v = sensitiveRedacted
+			case !oldOk:
+				v = fmt.Sprintf("%#v (added)", nv)
+			case !newOk:
+				v = fmt.Sprintf("%#v => <removed>", ov)
+			default:
+				v = fmt.Sprintf("%#v => %#v", ov, nv)
+			}
+
+			buf.WriteString(fmt.Sprintf(
+				"    %s:%s %s\n",
+				k,
+				strings.Repeat(" ", keyLen-len(k)),
+				v))
+		}
+	}
+
+	if buf.Len() == 0 {
+		return "The states are the same."
+	}
+
+	return strings.TrimSpace(buf.String())
+}
+
+// planResourceDiffs returns the diffs of the resources that the plan
+// changes, keyed by name.
+func planResourceDiffs(p *terraform.Plan) map[string]*terraform.ResourceDiff {
+	result := make(map[string]*terraform.ResourceDiff)
+	if p.Diff == nil {
+		return result
+	}
+
+	for name, rd := range p.Diff.Resources {
+		if !rd.Empty() {
+			result[name] = rd
+		}
+	}
+
+	return result
+}
+
+// planResourceAction returns what the plan does to the resource, as in
+// the audit log.
+func planResourceAction(
+	p *terraform.Plan, name string, rd *terraform.ResourceDiff) string {
+	var s *terraform.ResourceState
+	if p.State != nil {
+		s = p.State.Resources[name]
+	}
+
+	return auditAction(s, rd)
+}
+
+// formatAttrDiffs writes the attributes of the new diff that differ from
+// the old diff, or all of them if there's no old diff.
+func formatAttrDiffs(buf *bytes.Buffer, nd, od *terraform.ResourceDiff) {
+	keys := make([]string, 0, len(nd.Attributes))
+	for k, ad := range nd.Attributes {
+		if k == "id" {
+			continue
+		}
+		if od != nil {
+			if oad, ok := od.Attributes[k]; ok && formatAttrDiff(oad) == formatAttrDiff(ad) {
+				continue
+			}
+		}
+
+		keys = append(keys, k)
+	}
+	if od != nil {
+		for k, _ := range od.Attributes {
+			if _, ok := nd.Attributes[k]; !ok && k != "id" {
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	keyLen := maxLen(keys)
+	for _, k := range keys {
+		v := "(no longer changed)"
+		if ad, ok := nd.Attributes[k]; ok {
+			v = formatAttrDiff(ad)
+		}
+		if od != nil {
+			if oad, ok := od.Attributes[k]; ok {
+				v += fmt.Sprintf(" (was %s)", formatAttrDiff(oad))
+			} else {
+				v += " (not in the old plan)"
+			}
+		}
+
+		buf.WriteString(fmt.Sprintf(
+			"    %s:%s %s\n",
+			k,
+			strings.Repeat(" ", keyLen-len(k)),
+			v))
+	}
+}
+
+// formatAttrDiff formats the change of an attribute as FormatPlan does.
+func formatAttrDiff(ad *terraform.ResourceAttrDiff) string {
+	v := fmt.Sprintf("%#v", ad.New)
+	if ad.NewComputed {
+		v = "<computed>"
+	}
+
+	result := fmt.Sprintf("%#v => %s", ad.Old, v)
+	if ad.RequiresNew {
+		result += " (forces new resource)"
+	}
+
+	return result
+}
+
+// sensitiveOutput returns whether the output is marked sensitive in the
+// state.
+func sensitiveOutput(s *terraform.State, k string) bool {
+	_, ok := s.SensitiveOutputs[k]
+	return ok
+}
+
+// maxLen returns the length of the longest string, for aligning them.
+func maxLen(vs []string) int {
+	result := 0
+	for _, v := range vs {
+		if len(v) > result {
+			result = len(v)
+		}
+	}
+
+	return result
+}
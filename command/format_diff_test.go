@@ -0,0 +1,186 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/colorstring"
+)
+
+func TestFormatPlanDiff(t *testing.T) {
+	state := &terraform.State{
+		Resources: map[string]*terraform.ResourceState{
+			"aws_instance.foo":  &terraform.ResourceState{ID: "foo"},
+			"aws_instance.qux":  &terraform.ResourceState{ID: "qux"},
+			"aws_instance.same": &terraform.ResourceState{ID: "same"},
+		},
+	}
+
+	oldPlan := &terraform.Plan{
+		State: state,
+		Diff: &terraform.Diff{
+			Resources: map[string]*terraform.ResourceDiff{
+				"aws_instance.baz": &terraform.ResourceDiff{Destroy: true},
+				"aws_instance.foo": &terraform.ResourceDiff{
+					Attributes: map[string]*terraform.ResourceAttrDiff{
+						"ami":  &terraform.ResourceAttrDiff{Old: "ami-1", New: "ami-2"},
+						"tags": &terraform.ResourceAttrDiff{Old: "1", New: "2"},
+					},
+				},
+				"aws_instance.qux": &terraform.ResourceDiff{
+					Attributes: map[string]*terraform.ResourceAttrDiff{
+						"ami": &terraform.ResourceAttrDiff{Old: "a", New: "b"},
+					},
+				},
+				"aws_instance.same": &terraform.ResourceDiff{
+					Attributes: map[string]*terraform.ResourceAttrDiff{
+						"ami": &terraform.ResourceAttrDiff{Old: "a", New: "b"},
+					},
+				},
+			},
+		},
+	}
+
+	newPlan := &terraform.Plan{
+		State: state,
+		Diff: &terraform.Diff{
+			Resources: map[string]*terraform.ResourceDiff{
+				"aws_instance.bar": &terraform.ResourceDiff{
+					Attributes: map[string]*terraform.ResourceAttrDiff{
+						"ami": &terraform.ResourceAttrDiff{New: "ami-3"},
+					},
+				},
+				"aws_instance.foo": &terraform.ResourceDiff{
+					Attributes: map[string]*terraform.ResourceAttrDiff{
+						"ami":  &terraform.ResourceAttrDiff{Old: "ami-1", New: "ami-3"},
+						"size": &terraform.ResourceAttrDiff{NewComputed: true},
+					},
+				},
+				"aws_instance.qux": &terraform.ResourceDiff{
+					Attributes: map[string]*terraform.ResourceAttrDiff{
+						"ami": &terraform.ResourceAttrDiff{
+							Old:         "a",
+							New:         "b",
+							RequiresNew: true,
+						},
+					},
+				},
+				"aws_instance.same": &terraform.ResourceDiff{
+					Attributes: map[string]*terraform.ResourceAttrDiff{
+						"ami": &terraform.ResourceAttrDiff{Old: "a", New: "b"},
+					},
+				},
+			},
+		},
+	}
+
+	actual := FormatPlanDiff(oldPlan, newPlan, &colorstring.Colorize{
+		Colors:  colorstring.DefaultColors,
+		Disable: true,
+	})
+	expected := strings.TrimSpace(`
++ aws_instance.bar: create (not in the old plan)
+    ami: "" => "ami-3"
+
+- aws_instance.baz: destroy (not in the new plan)
+
+~ aws_instance.foo: update
+    ami:  "ami-1" => "ami-3" (was "ami-1" => "ami-2")
+    size: "" => <computed> (not in the old plan)
+    tags: (no longer changed) (was "1" => "2")
+
+~ aws_instance.qux: create (was update)
+    ami: "a" => "b" (forces new resource) (was "a" => "b")
+`)
+	if actual != expected {
+		t.Fatalf("bad:\n%s", actual)
+	}
+}
+
+func TestFormatPlanDiff_same(t *testing.T) {
+	plan := &terraform.Plan{
+		Diff: &terraform.Diff{
+			Resources: map[string]*terraform.ResourceDiff{
+				"aws_instance.foo": &terraform.ResourceDiff{Destroy: true},
+			},
+		},
+	}
+
+	actual := FormatPlanDiff(plan, plan, nil)
+	if actual != "The plans are the same." {
+		t.Fatalf("bad:\n%s", actual)
+	}
+}
+
+func TestFormatStateDiff(t *testing.T) {
+	oldState := &terraform.State{
+		Resources: map[string]*terraform.ResourceState{
+			"aws_instance.bar": &terraform.ResourceState{ID: "2"},
+			"aws_instance.foo": &terraform.ResourceState{
+				ID:         "1",
+				Attributes: map[string]string{"ami": "a"},
+			},
+		},
+		Outputs: map[string]string{
+			"addr":   "1.1",
+			"gone":   "y",
+			"secret": "x",
+			"same":   "s",
+		},
+		SensitiveOutputs: map[string]struct{}{"secret": struct{}{}},
+	}
+
+	newState := &terraform.State{
+		Resources: map[string]*terraform.ResourceState{
+			"aws_instance.baz": &terraform.ResourceState{ID: "3"},
+			"aws_instance.foo": &terraform.ResourceState{
+				ID:         "1",
+				Attributes: map[string]string{"ami": "b"},
+			},
+		},
+		Outputs: map[string]string{
+			"added":  "w",
+			"addr":   "1.2",
+			"secret": "z",
+			"same":   "s",
+		},
+		SensitiveOutputs: map[string]struct{}{"secret": struct{}{}},
+	}
+
+	actual := FormatStateDiff(oldState, newState, &colorstring.Colorize{
+		Colors:  colorstring.DefaultColors,
+		Disable: true,
+	})
+	expected := strings.TrimSpace(`
+- aws_instance.bar (ID: 2)
+
++ aws_instance.baz (ID: 3)
+
+~ aws_instance.foo
+    ami: "a" => "b"
+
+Outputs:
+    added:  "w" (added)
+    addr:   "1.1" => "1.2"
+    gone:   "y" => <removed>
+    secret: <sensitive>
+`)
+	if actual != expected {
+		t.Fatalf("bad:\n%s", actual)
+	}
+}
+
+func TestFormatStateDiff_same(t *testing.T) {
+	state := &terraform.State{
+		Resources: map[string]*terraform.ResourceState{
+			"aws_instance.foo": &terraform.ResourceState{ID: "1"},
+		},
+		Outputs: map[string]string{"addr": "1.1"},
+	}
+
+	actual := FormatStateDiff(state, state, nil)
+	if actual != "The states are the same." {
+		t.Fatalf("bad:\n%s", actual)
+	}
+}
@@ -0,0 +1,69 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mitchellh/colorstring"
+)
+
+// FormatDrift returns a human-readable report of the resources that
+// changed outside of Terraform, as returned by StateDrift.
+func FormatDrift(drift map[string]*ResourceDrift, c *colorstring.Colorize) string {
+	if c == nil {
+		c = &colorstring.Colorize{
+			Colors: colorstring.DefaultColors,
+			Reset:  false,
+		}
+	}
+
+	names := make([]string, 0, len(drift))
+	for name, _ := range drift {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buf := new(bytes.Buffer)
+	for _, name := range names {
+		rd := drift[name]
+		if rd.Deleted {
+			buf.WriteString(c.Color(fmt.Sprintf(
+				"[red]- %s (deleted outside of Terraform)\n", name)))
+			buf.WriteString(c.Color("[reset]\n"))
+			continue
+		}
+
+		buf.WriteString(c.Color(fmt.Sprintf("[yellow]~ %s\n", name)))
+
+		keyLen := 0
+		keys := make([]string, 0, len(rd.Attributes))
+		for key, _ := range rd.Attributes {
+			keys = append(keys, key)
+			if len(key) > keyLen {
+				keyLen = len(key)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			ad := rd.Attributes[k]
+			v := fmt.Sprintf("%#v", ad.New)
+			if ad.Removed {
+				v = "<removed>"
+			}
+
+			buf.WriteString(fmt.Sprintf(
+				"    %s:%s %#v => %s\n",
+				k,
+				strings.Repeat(" ", keyLen-len(k)),
+				ad.Old,
+				v))
+		}
+
+		buf.WriteString(c.Color("[reset]\n"))
+	}
+
+	return strings.TrimSpace(buf.String())
+}
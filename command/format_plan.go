@@ -37,6 +37,17 @@ func FormatPlan(p *terraform.Plan, c *colorstring.Colorize) string {
 	for _, name := range names {
 		rdiff := p.Diff.Resources[name]
 
+		// Resources that were deleted outside of Terraform are explained,
+		// since otherwise they'd silently be created again.
+		if rdiff.Missing && rdiff.Empty() {
+			buf.WriteString(c.Color(fmt.Sprintf(
+				"[yellow]- %s (exists in state but not in provider, "+
+					"will be removed from state)\n",
+				name)))
+			buf.WriteString(c.Color("[reset]\n"))
+			continue
+		}
+
 		// Determine the color for the text (green for adding, yellow
 		// for change, red for delete), and symbol, and output the
 		// resource header.
@@ -52,9 +63,13 @@ func FormatPlan(p *terraform.Plan, c *colorstring.Colorize) string {
 			color = "red"
 			symbol = "-"
 		}
+		missing := ""
+		if rdiff.Missing {
+			missing = " (exists in state but not in provider, will be recreated)"
+		}
 		buf.WriteString(c.Color(fmt.Sprintf(
-			"[%s]%s %s\n",
-			color, symbol, name)))
+			"[%s]%s %s%s\n",
+			color, symbol, name, missing)))
 
 		// Get all the attributes that are changing, and sort them. Also
 		// determine the longest key so that we can align them all.
@@ -96,6 +111,14 @@ func FormatPlan(p *terraform.Plan, c *colorstring.Colorize) string {
 				newResource))
 		}
 
+		// Output what the provisioners will do, if we know
+		for _, prov := range rdiff.Provisioners {
+			buf.WriteString(fmt.Sprintf("    provisioner %q:\n", prov.Type))
+			for _, action := range prov.Actions {
+				buf.WriteString(fmt.Sprintf("      %s\n", action))
+			}
+		}
+
 		// Write the reset color so we don't overload the user's terminal
 		buf.WriteString(c.Color("[reset]\n"))
 	}
@@ -81,6 +81,9 @@ func FormatState(s *terraform.State, c *colorstring.Colorize) string {
 		// Output each output k/v pair
 		for _, k := range ks {
 			v := s.Outputs[k]
+			if _, ok := s.SensitiveOutputs[k]; ok {
+				v = sensitiveRedacted
+			}
 			buf.WriteString(fmt.Sprintf("%s = %s\n", k, v))
 		}
 	}
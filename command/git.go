@@ -0,0 +1,73 @@
+package command
+
+import (
+	"bytes"
+	"log"
+	"os/exec"
+	"strings"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// gitRevision returns the git revision of the configuration in dir,
+// keyed by the fields in config.GitFields. It returns nil if dir isn't
+// in a git repository, or if git isn't installed.
+func gitRevision(dir string) map[string]string {
+	commit, err := gitOutput(dir, "rev-parse", "HEAD")
+	if err != nil {
+		log.Printf("[DEBUG] Not reading git revision of %s: %s", dir, err)
+		return nil
+	}
+
+	// A detached HEAD has no branch
+	branch, err := gitOutput(dir, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil || branch == "HEAD" {
+		branch = ""
+	}
+
+	short := commit
+	if len(short) > 7 {
+		short = short[:7]
+	}
+
+	return map[string]string{
+		"commit":       commit,
+		"short_commit": short,
+		"branch":       branch,
+	}
+}
+
+// gitOutput runs git in dir and returns its trimmed output.
+func gitOutput(dir string, args ...string) (string, error) {
+	var stdout bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// recordGitRevision stores the git revision in the metadata of the state
+// as "git_commit" and "git_branch". The metadata of a previous revision
+// is removed if git is nil.
+func recordGitRevision(s *terraform.State, git map[string]string) {
+	if s.Metadata == nil {
+		s.Metadata = make(map[string]string)
+	}
+
+	for _, f := range []string{"commit", "branch"} {
+		k := "git_" + f
+		if v := git[f]; v != "" {
+			s.Metadata[k] = v
+		} else {
+			delete(s.Metadata, k)
+		}
+	}
+
+	if len(s.Metadata) == 0 {
+		s.Metadata = nil
+	}
+}
@@ -0,0 +1,87 @@
+package command
+
+import (
+	"os"
+	"os/exec"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestGitRevision(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	if v := gitRevision(td); v != nil {
+		t.Fatalf("should be nil outside a repository: %#v", v)
+	}
+
+	testGit(t, td, "init", "-q")
+	testGit(t, td, "checkout", "-q", "-b", "deploy")
+	testGit(t, td, "commit", "-q", "--allow-empty", "-m", "initial")
+	commit, err := gitOutput(td, "rev-parse", "HEAD")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := gitRevision(td)
+	expected := map[string]string{
+		"commit":       commit,
+		"short_commit": commit[:7],
+		"branch":       "deploy",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	// A detached HEAD has no branch
+	testGit(t, td, "checkout", "-q", commit)
+	if v := gitRevision(td)["branch"]; v != "" {
+		t.Fatalf("bad: %#v", v)
+	}
+}
+
+func TestRecordGitRevision(t *testing.T) {
+	s := &terraform.State{
+		Metadata: map[string]string{
+			"git_commit": "old",
+			"git_branch": "master",
+			"other":      "value",
+		},
+	}
+
+	recordGitRevision(s, map[string]string{
+		"commit":       "0123456789",
+		"short_commit": "0123456",
+	})
+	expected := map[string]string{
+		"git_commit": "0123456789",
+		"other":      "value",
+	}
+	if !reflect.DeepEqual(s.Metadata, expected) {
+		t.Fatalf("bad: %#v", s.Metadata)
+	}
+
+	s = new(terraform.State)
+	recordGitRevision(s, nil)
+	if s.Metadata != nil {
+		t.Fatalf("bad: %#v", s.Metadata)
+	}
+}
+
+func testGit(t *testing.T, dir string, args ...string) {
+	args = append([]string{
+		"-c", "user.name=test",
+		"-c", "user.email=test@example.com",
+	}, args...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %s\n\n%s", args, err, out)
+	}
+}
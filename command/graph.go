@@ -3,7 +3,8 @@ package command
 import (
 	"flag"
 	"fmt"
-	"os"
+	"net"
+	"net/http"
 	"strings"
 
 	"github.com/hashicorp/terraform/terraform"
@@ -13,12 +14,19 @@ import (
 // configuration and outputs the dependency tree in graphical form.
 type GraphCommand struct {
 	Meta
+
+	ShutdownCh <-chan struct{}
 }
 
 func (c *GraphCommand) Run(args []string) int {
+	var serve bool
+	var listen string
+
 	args = c.Meta.process(args, false)
 
 	cmdFlags := flag.NewFlagSet("graph", flag.ContinueOnError)
+	cmdFlags.BoolVar(&serve, "serve", false, "serve")
+	cmdFlags.StringVar(&listen, "listen", "127.0.0.1:0", "address")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		return 1
@@ -31,10 +39,10 @@ func (c *GraphCommand) Run(args []string) int {
 		cmdFlags.Usage()
 		return 1
 	} else if len(args) == 1 {
-		path = args[0]
+		path = c.path(args[0])
 	} else {
 		var err error
-		path, err = os.Getwd()
+		path, err = c.wd()
 		if err != nil {
 			c.Ui.Error(fmt.Sprintf("Error getting pwd: %s", err))
 		}
@@ -52,11 +60,32 @@ func (c *GraphCommand) Run(args []string) int {
 		return 1
 	}
 
+	if serve {
+		return c.serve(listen, graphData(g, c.redactor))
+	}
+
 	c.Ui.Output(terraform.GraphDot(g))
 
 	return 0
 }
 
+// serve serves the web UI of the graph until an interrupt is received.
+func (c *GraphCommand) serve(listen string, data *GraphData) int {
+	ln, err := net.Listen("tcp", listen)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error starting server: %s", err))
+		return 1
+	}
+	defer ln.Close()
+
+	go http.Serve(ln, graphHandler(data))
+	c.Ui.Output(fmt.Sprintf(
+		"Serving the graph on http://%s/\nPress Ctrl-C to stop.", ln.Addr()))
+
+	<-c.ShutdownCh
+	return 0
+}
+
 func (c *GraphCommand) Help() string {
 	helpText := `
 Usage: terraform graph [options] PATH
@@ -70,6 +99,16 @@ Usage: terraform graph [options] PATH
   read this format is GraphViz, but many web services are also available
   to read this format.
 
+Options:
+
+  -listen=address     Address to serve the graph on with -serve. Defaults
+                      to a free port on 127.0.0.1.
+
+  -serve              Serve an interactive graph on a local web server
+                      instead of outputting it, until interrupted. Nodes
+                      can be filtered by name, provider and type, and
+                      show their planned changes when clicked.
+
 `
 	return strings.TrimSpace(helpText)
 }
@@ -0,0 +1,416 @@
+package command
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/hashicorp/terraform/depgraph"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// GraphData is the dependency graph that "terraform graph -serve" renders,
+// as served at /graph.json.
+type GraphData struct {
+	Nodes []*GraphDataNode `json:"nodes"`
+	Edges []*GraphDataEdge `json:"edges"`
+}
+
+// GraphDataNode is a resource or a provider in the graph.
+type GraphDataNode struct {
+	ID string `json:"id"`
+
+	// Kind is "resource", "meta" for the resources that are counted, or
+	// "provider".
+	Kind     string `json:"kind"`
+	Type     string `json:"type,omitempty"`
+	Provider string `json:"provider,omitempty"`
+
+	// Action is what the plan does to the resource, as in the audit log,
+	// and is empty if it does nothing or the graph isn't of a plan.
+	Action     string                      `json:"action,omitempty"`
+	Attributes map[string]*PolicyAttribute `json:"attributes,omitempty"`
+}
+
+// GraphDataEdge is a dependency of the node From on the node To.
+type GraphDataEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// graphData returns the graph to serve, with the values of sensitive
+// variables in the planned changes redacted.
+func graphData(g *depgraph.Graph, r *redactor) *GraphData {
+	result := &GraphData{
+		Nodes: make([]*GraphDataNode, 0, len(g.Nouns)),
+		Edges: make([]*GraphDataEdge, 0),
+	}
+
+	for _, n := range g.Nouns {
+		if n.Name == terraform.GraphRootNode {
+			continue
+		}
+
+		node := &GraphDataNode{ID: n.Name}
+		switch m := n.Meta.(type) {
+		case *terraform.GraphNodeResource:
+			node.Kind = "resource"
+			node.Type = m.Type
+			node.Provider = m.ResourceProviderID
+
+			rd := m.Resource.Diff
+			if rd != nil && !rd.Empty() {
+				node.Action = auditAction(m.Resource.State, rd)
+				for k, ad := range rd.Attributes {
+					if node.Attributes == nil {
+						node.Attributes = make(map[string]*PolicyAttribute)
+					}
+
+					node.Attributes[k] = &PolicyAttribute{
+						Old:         r.Redact(ad.Old),
+						New:         r.Redact(ad.New),
+						Computed:    ad.NewComputed,
+						RequiresNew: ad.RequiresNew,
+					}
+				}
+			}
+		case *terraform.GraphNodeResourceMeta:
+			node.Kind = "meta"
+			node.Type = m.Type
+		case *terraform.GraphNodeResourceProvider:
+			node.Kind = "provider"
+			node.Provider = m.ID
+		default:
+			continue
+		}
+		result.Nodes = append(result.Nodes, node)
+
+		for _, dep := range n.Deps {
+			if dep.Target.Name == terraform.GraphRootNode {
+				continue
+			}
+
+			result.Edges = append(result.Edges, &GraphDataEdge{
+				From: n.Name,
+				To:   dep.Target.Name,
+			})
+		}
+	}
+
+	sort.Sort(graphDataNodeSort(result.Nodes))
+	sort.Sort(graphDataEdgeSort(result.Edges))
+
+	return result
+}
+
+// graphHandler serves the page that renders the graph at /, and the graph
+// itself at /graph.json.
+func graphHandler(data *GraphData) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(graphPage))
+	})
+	mux.HandleFunc("/graph.json", func(w http.ResponseWriter, r *http.Request) {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(raw)
+	})
+
+	return mux
+}
+
+type graphDataNodeSort []*GraphDataNode
+
+func (s graphDataNodeSort) Len() int           { return len(s) }
+func (s graphDataNodeSort) Less(i, j int) bool { return s[i].ID < s[j].ID }
+func (s graphDataNodeSort) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+type graphDataEdgeSort []*GraphDataEdge
+
+func (s graphDataEdgeSort) Len() int { return len(s) }
+func (s graphDataEdgeSort) Less(i, j int) bool {
+	if s[i].From != s[j].From {
+		return s[i].From < s[j].From
+	}
+
+	return s[i].To < s[j].To
+}
+func (s graphDataEdgeSort) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+// graphPage renders the graph from /graph.json. Resources are laid out in
+// columns by how deep their dependencies go, so that everything a node
+// depends on is to its left. It has no dependencies, so that it works
+// offline.
+const graphPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Terraform Graph</title>
+<style>
+body { margin: 0; font-family: sans-serif; font-size: 13px; overflow: hidden; }
+#bar { padding: 8px; background: #eee; border-bottom: 1px solid #ccc; }
+#bar > * { margin-right: 8px; }
+#graph { display: block; width: 100vw; height: calc(100vh - 42px); cursor: move; }
+#details { position: absolute; top: 50px; right: 8px; width: 400px;
+  max-height: calc(100vh - 70px); overflow: auto; background: #fff;
+  border: 1px solid #ccc; padding: 8px; }
+#details table { border-collapse: collapse; width: 100%; }
+#details td, #details th { border-bottom: 1px solid #eee; padding: 2px 4px;
+  text-align: left; vertical-align: top; word-break: break-all; }
+.node rect { stroke: #666; fill: #fff; }
+.node.provider rect { fill: #ddd; }
+.node.meta rect { stroke-dasharray: 4 2; }
+.node.create rect { fill: #9eff9e; stroke: #00c000; }
+.node.update rect { fill: #ffff94; stroke: #c0c000; }
+.node.replace rect { fill: #ffc994; stroke: #e08000; }
+.node.destroy rect { fill: #ff9494; stroke: #e00000; }
+.node.selected rect { stroke: #00f; stroke-width: 3; }
+.node text { pointer-events: none; }
+.node { cursor: pointer; }
+.edge { stroke: #999; fill: none; marker-end: url(#arrow); }
+.edge.selected { stroke: #00f; stroke-width: 2; }
+</style>
+</head>
+<body>
+<div id="bar">
+  <input id="search" placeholder="Filter by name">
+  <select id="provider"><option value="">All providers</option></select>
+  <select id="type"><option value="">All types</option></select>
+  <label><input type="checkbox" id="changes"> Changes only</label>
+  <span id="count"></span>
+</div>
+<svg id="graph">
+  <defs>
+    <marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5"
+      markerWidth="6" markerHeight="6" orient="auto">
+      <path d="M 0 0 L 10 5 L 0 10 z" fill="#999"></path>
+    </marker>
+  </defs>
+  <g id="view"></g>
+</svg>
+<div id="details" hidden></div>
+<script>
+(function() {
+  var W = 220, H = 30, GAPX = 80, GAPY = 14;
+  var svg = document.getElementById("graph");
+  var view = document.getElementById("view");
+  var details = document.getElementById("details");
+  var filters = {
+    search: document.getElementById("search"),
+    provider: document.getElementById("provider"),
+    type: document.getElementById("type"),
+    changes: document.getElementById("changes")
+  };
+  var data, byId = {}, depth = {}, selected = null;
+
+  // Zooming with the wheel and panning by dragging
+  var scale = 1, tx = 20, ty = 20, drag = null;
+  function transform() {
+    view.setAttribute("transform",
+      "translate(" + tx + "," + ty + ") scale(" + scale + ")");
+  }
+  svg.addEventListener("wheel", function(e) {
+    e.preventDefault();
+    var f = e.deltaY < 0 ? 1.1 : 1 / 1.1;
+    var r = svg.getBoundingClientRect();
+    var x = e.clientX - r.left, y = e.clientY - r.top;
+    tx = x - (x - tx) * f;
+    ty = y - (y - ty) * f;
+    scale *= f;
+    transform();
+  });
+  svg.addEventListener("mousedown", function(e) {
+    drag = {x: e.clientX - tx, y: e.clientY - ty, moved: false};
+  });
+  window.addEventListener("mousemove", function(e) {
+    if (!drag) return;
+    drag.moved = true;
+    tx = e.clientX - drag.x;
+    ty = e.clientY - drag.y;
+    transform();
+  });
+  window.addEventListener("mouseup", function() {
+    setTimeout(function() { drag = null; }, 0);
+  });
+
+  function el(name, attrs, parent) {
+    var e = document.createElementNS("http://www.w3.org/2000/svg", name);
+    for (var k in attrs) e.setAttribute(k, attrs[k]);
+    if (parent) parent.appendChild(e);
+    return e;
+  }
+
+  function add(parent, tag, text) {
+    var e = document.createElement(tag);
+    if (text !== undefined) e.textContent = text;
+    parent.appendChild(e);
+    return e;
+  }
+
+  // The depth of a node is the length of its longest chain of
+  // dependencies.
+  function depthOf(id, seen) {
+    if (depth[id] !== undefined) return depth[id];
+    if (seen[id]) return 0;
+    seen[id] = true;
+    var d = 0;
+    byId[id].deps.forEach(function(dep) {
+      d = Math.max(d, depthOf(dep, seen) + 1);
+    });
+    return depth[id] = d;
+  }
+
+  function visible(n) {
+    var s = filters.search.value.toLowerCase();
+    if (s && n.id.toLowerCase().indexOf(s) < 0) return false;
+    if (filters.provider.value && n.provider !== filters.provider.value) return false;
+    if (filters.type.value && n.type !== filters.type.value) return false;
+    if (filters.changes.checked && !n.action) return false;
+    return true;
+  }
+
+  function draw() {
+    while (view.firstChild) view.removeChild(view.firstChild);
+
+    var pos = {}, rows = {}, count = 0;
+    data.nodes.forEach(function(n) {
+      if (!visible(n)) return;
+      var col = depth[n.id];
+      rows[col] = (rows[col] || 0) + 1;
+      pos[n.id] = {
+        x: col * (W + GAPX),
+        y: (rows[col] - 1) * (H + GAPY)
+      };
+      count++;
+    });
+    document.getElementById("count").textContent =
+      count + " of " + data.nodes.length + " nodes";
+
+    data.edges.forEach(function(e) {
+      var from = pos[e.from], to = pos[e.to];
+      if (!from || !to) return;
+      var x1 = from.x, y1 = from.y + H / 2;
+      var x2 = to.x + W, y2 = to.y + H / 2;
+      var mid = (x1 + x2) / 2;
+      var path = el("path", {
+        "class": "edge",
+        d: "M " + x1 + " " + y1 + " C " + mid + " " + y1 + ", " +
+          mid + " " + y2 + ", " + x2 + " " + y2
+      }, view);
+      if (selected && (e.from === selected || e.to === selected)) {
+        path.setAttribute("class", "edge selected");
+      }
+    });
+
+    data.nodes.forEach(function(n) {
+      var p = pos[n.id];
+      if (!p) return;
+      var cls = "node " + n.kind + (n.action ? " " + n.action : "");
+      if (n.id === selected) cls += " selected";
+      var g = el("g", {
+        "class": cls,
+        transform: "translate(" + p.x + "," + p.y + ")"
+      }, view);
+      el("rect", {width: W, height: H, rx: 4}, g);
+      var label = n.id.length > 32 ? n.id.substr(0, 31) + "…" : n.id;
+      el("text", {x: 8, y: H / 2 + 4}, g).textContent = label;
+      el("title", {}, g).textContent = n.id;
+      g.addEventListener("click", function() {
+        if (drag && drag.moved) return;
+        select(n.id);
+      });
+    });
+  }
+
+  function select(id) {
+    selected = id;
+    draw();
+
+    var n = byId[id];
+    while (details.firstChild) details.removeChild(details.firstChild);
+    details.hidden = false;
+
+    var close = add(details, "button", "Close");
+    close.style.float = "right";
+    close.addEventListener("click", function() {
+      details.hidden = true;
+      selected = null;
+      draw();
+    });
+
+    add(details, "h3", n.id);
+    var info = add(details, "table");
+    [["Kind", n.kind], ["Type", n.type], ["Provider", n.provider],
+     ["Change", n.action || "none"],
+     ["Depends on", n.deps.join(", ")],
+     ["Required by", n.rdeps.join(", ")]].forEach(function(row) {
+      if (!row[1]) return;
+      var tr = add(info, "tr");
+      add(tr, "th", row[0]);
+      add(tr, "td", row[1]);
+    });
+
+    if (n.attributes) {
+      add(details, "h4", "Planned changes");
+      var attrs = add(details, "table");
+      var tr = add(attrs, "tr");
+      ["Attribute", "Old", "New"].forEach(function(h) { add(tr, "th", h); });
+      Object.keys(n.attributes).sort().forEach(function(k) {
+        var a = n.attributes[k];
+        var tr = add(attrs, "tr");
+        add(tr, "td", k + (a.requires_new ? " (forces new resource)" : ""));
+        add(tr, "td", a.old);
+        add(tr, "td", a.computed ? "<computed>" : a.new);
+      });
+    }
+  }
+
+  function options(select, values) {
+    Object.keys(values).sort().forEach(function(v) {
+      add(select, "option", v).value = v;
+    });
+  }
+
+  var req = new XMLHttpRequest();
+  req.open("GET", "graph.json");
+  req.onload = function() {
+    data = JSON.parse(req.responseText);
+    var providers = {}, types = {};
+    data.nodes.forEach(function(n) {
+      n.deps = [];
+      n.rdeps = [];
+      byId[n.id] = n;
+      if (n.provider) providers[n.provider] = true;
+      if (n.type) types[n.type] = true;
+    });
+    data.edges.forEach(function(e) {
+      byId[e.from].deps.push(e.to);
+      byId[e.to].rdeps.push(e.from);
+    });
+    data.nodes.forEach(function(n) { depthOf(n.id, {}); });
+
+    options(filters.provider, providers);
+    options(filters.type, types);
+    for (var k in filters) filters[k].addEventListener("input", draw);
+    filters.changes.addEventListener("change", draw);
+
+    transform();
+    draw();
+  };
+  req.send();
+})();
+</script>
+</body>
+</html>
+`
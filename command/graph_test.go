@@ -1,7 +1,11 @@
 package command
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -104,3 +108,139 @@ func TestGraph_plan(t *testing.T) {
 		t.Fatalf("doesn't look like digraph: %s", output)
 	}
 }
+
+func TestGraph_serve(t *testing.T) {
+	// The interrupt is already received, so the server stops right away
+	shutdownCh := make(chan struct{}, 1)
+	shutdownCh <- struct{}{}
+
+	ui := new(cli.MockUi)
+	c := &GraphCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+		ShutdownCh: shutdownCh,
+	}
+
+	args := []string{
+		"-serve",
+		"-listen", "127.0.0.1:0",
+		testFixturePath("graph"),
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	output := ui.OutputWriter.String()
+	if !strings.Contains(output, "http://127.0.0.1:") {
+		t.Fatalf("bad: %s", output)
+	}
+	if strings.Contains(output, "digraph {") {
+		t.Fatalf("should not output the digraph: %s", output)
+	}
+}
+
+func TestGraphData(t *testing.T) {
+	p := testProvider()
+	p.DiffReturn = &terraform.ResourceDiff{
+		Attributes: map[string]*terraform.ResourceAttrDiff{
+			"ami": &terraform.ResourceAttrDiff{New: "bar"},
+		},
+	}
+
+	conf, err := config.LoadDir(testFixturePath("graph"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	opts := testCtxConfig(p)
+	opts.Config = conf
+	plan, err := terraform.NewContext(opts).Plan(nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	g, err := plan.Context(testCtxConfig(p)).Graph()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	r := new(redactor)
+	r.Add("bar")
+	data := graphData(g, r)
+
+	var resource, provider *GraphDataNode
+	for _, n := range data.Nodes {
+		switch n.ID {
+		case "test_instance.foo":
+			resource = n
+		case "provider.test":
+			provider = n
+		}
+	}
+	if resource == nil || provider == nil {
+		t.Fatalf("bad: %#v", data.Nodes)
+	}
+	if resource.Kind != "resource" || resource.Action != "create" ||
+		resource.Provider != "test" || resource.Type != "test_instance" {
+		t.Fatalf("bad: %#v", resource)
+	}
+	if resource.Attributes["ami"].New != sensitiveRedacted {
+		t.Fatalf("bad: %#v", resource.Attributes["ami"])
+	}
+	if provider.Kind != "provider" {
+		t.Fatalf("bad: %#v", provider)
+	}
+
+	expected := &GraphDataEdge{From: "test_instance.foo", To: "provider.test"}
+	found := false
+	for _, e := range data.Edges {
+		if reflect.DeepEqual(e, expected) {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("bad: %#v", data.Edges)
+	}
+}
+
+func TestGraphHandler(t *testing.T) {
+	data := &GraphData{
+		Nodes: []*GraphDataNode{
+			&GraphDataNode{ID: "test_instance.foo", Kind: "resource"},
+		},
+		Edges: []*GraphDataEdge{},
+	}
+	h := graphHandler(data)
+
+	for path, code := range map[string]int{
+		"/":           200,
+		"/graph.json": 200,
+		"/nope":       404,
+	} {
+		req, err := http.NewRequest("GET", path, nil)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != code {
+			t.Fatalf("%s: bad: %d", path, w.Code)
+		}
+
+		switch path {
+		case "/":
+			if !strings.Contains(w.Body.String(), "graph.json") {
+				t.Fatalf("bad: %s", w.Body.String())
+			}
+		case "/graph.json":
+			var actual GraphData
+			if err := json.Unmarshal(w.Body.Bytes(), &actual); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if !reflect.DeepEqual(&actual, data) {
+				t.Fatalf("bad: %#v", actual)
+			}
+		}
+	}
+}
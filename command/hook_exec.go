@@ -0,0 +1,259 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os/exec"
+	"strings"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+)
+
+// HookEvents are the names of the events that hook executables can be
+// run for, one for each function of terraform.Hook.
+var HookEvents = []string{
+	"pre_apply_all", "post_apply_all",
+	"pre_apply", "post_apply",
+	"pre_diff", "post_diff",
+	"pre_provision_resource", "post_provision_resource",
+	"pre_provision", "post_provision",
+	"pre_refresh", "post_refresh",
+}
+
+// ExecHook is an executable that is run when Terraform reaches hook
+// points, such as to record metrics, send notifications, or validate
+// changes. Hooks are configured with "hook" blocks in the CLI
+// configuration.
+//
+// The executable is given the HookEvent as JSON on stdin. If it exits
+// with an error for an event that starts with "pre_", what Terraform was
+// about to do fails with the output of the executable. Errors for other
+// events are only reported.
+type ExecHook struct {
+	Name    string
+	Command string
+
+	// Events are the names of the events to run the executable for, from
+	// HookEvents. If it's empty, it's run for every event.
+	Events []string
+}
+
+// HookEvent is what a hook executable is given.
+type HookEvent struct {
+	Event string `json:"event"`
+
+	// Resource is the name of the resource that the event is for, and ID
+	// is its ID, if it has one. Action is what applying the diff does to
+	// the resource, for pre_apply.
+	Resource string `json:"resource,omitempty"`
+	ID       string `json:"id,omitempty"`
+	Action   string `json:"action,omitempty"`
+
+	// Provisioner is the type of the provisioner, for pre_provision and
+	// post_provision.
+	Provisioner string `json:"provisioner,omitempty"`
+
+	// Changes summarizes the plan, for pre_apply_all.
+	Changes *AuditPlan `json:"changes,omitempty"`
+
+	// Error is why applying failed, for post_apply and post_apply_all.
+	Error string `json:"error,omitempty"`
+}
+
+// Run runs the executable for the event, if it's one of its events. An
+// error is returned if the executable couldn't be run or failed.
+func (h *ExecHook) Run(e *HookEvent) error {
+	if len(h.Events) > 0 {
+		found := false
+		for _, v := range h.Events {
+			if v == e.Event {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil
+		}
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	var output bytes.Buffer
+	cmd := exec.Command(h.Command)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	log.Printf("[DEBUG] Running hook %s for %s", h.Name, e.Event)
+	if err := cmd.Run(); err != nil {
+		if out := strings.TrimSpace(output.String()); out != "" {
+			return fmt.Errorf("%s\n%s", err, out)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// ExecHookHook is a hook that runs the hook executables. Errors from
+// executables run for events that start with "pre_" are returned, so
+// that they fail what Terraform was about to do. Others are reported,
+// but don't stop anything.
+type ExecHookHook struct {
+	Hooks []*ExecHook
+	Ui    cli.Ui
+
+	// Redactor hides sensitive values in the errors that are sent.
+	Redactor *redactor
+
+	terraform.NilHook
+}
+
+func (h *ExecHookHook) PreApplyAll(
+	s *terraform.State, d *terraform.Diff) (terraform.HookAction, error) {
+	return h.run(&HookEvent{
+		Event:   "pre_apply_all",
+		Changes: auditPlan(&terraform.Plan{Diff: d, State: s}),
+	})
+}
+
+func (h *ExecHookHook) PostApplyAll(
+	s *terraform.State, err error) (terraform.HookAction, error) {
+	return h.run(&HookEvent{Event: "post_apply_all", Error: h.errString(err)})
+}
+
+func (h *ExecHookHook) PreApply(
+	id string,
+	s *terraform.ResourceState,
+	d *terraform.ResourceDiff) (terraform.HookAction, error) {
+	return h.run(&HookEvent{
+		Event:    "pre_apply",
+		Resource: id,
+		ID:       s.ID,
+		Action:   auditAction(s, d),
+	})
+}
+
+func (h *ExecHookHook) PostApply(
+	id string,
+	s *terraform.ResourceState,
+	err error) (terraform.HookAction, error) {
+	return h.run(&HookEvent{
+		Event:    "post_apply",
+		Resource: id,
+		ID:       s.ID,
+		Error:    h.errString(err),
+	})
+}
+
+func (h *ExecHookHook) PreDiff(
+	id string, s *terraform.ResourceState) (terraform.HookAction, error) {
+	return h.run(&HookEvent{Event: "pre_diff", Resource: id, ID: s.ID})
+}
+
+func (h *ExecHookHook) PostDiff(
+	id string, d *terraform.ResourceDiff) (terraform.HookAction, error) {
+	return h.run(&HookEvent{Event: "post_diff", Resource: id})
+}
+
+func (h *ExecHookHook) PreProvisionResource(
+	id string, s *terraform.ResourceState) (terraform.HookAction, error) {
+	return h.run(&HookEvent{
+		Event:    "pre_provision_resource",
+		Resource: id,
+		ID:       s.ID,
+	})
+}
+
+func (h *ExecHookHook) PostProvisionResource(
+	id string, s *terraform.ResourceState) (terraform.HookAction, error) {
+	return h.run(&HookEvent{
+		Event:    "post_provision_resource",
+		Resource: id,
+		ID:       s.ID,
+	})
+}
+
+func (h *ExecHookHook) PreProvision(
+	id, provId string) (terraform.HookAction, error) {
+	return h.run(&HookEvent{
+		Event:       "pre_provision",
+		Resource:    id,
+		Provisioner: provId,
+	})
+}
+
+func (h *ExecHookHook) PostProvision(
+	id, provId string) (terraform.HookAction, error) {
+	return h.run(&HookEvent{
+		Event:       "post_provision",
+		Resource:    id,
+		Provisioner: provId,
+	})
+}
+
+func (h *ExecHookHook) PreRefresh(
+	id string, s *terraform.ResourceState) (terraform.HookAction, error) {
+	return h.run(&HookEvent{Event: "pre_refresh", Resource: id, ID: s.ID})
+}
+
+func (h *ExecHookHook) PostRefresh(
+	id string, s *terraform.ResourceState) (terraform.HookAction, error) {
+	e := &HookEvent{Event: "post_refresh", Resource: id}
+	if s != nil {
+		e.ID = s.ID
+	}
+
+	return h.run(e)
+}
+
+// run runs every hook executable for the event, in order. The first
+// error for a "pre_" event stops the others from running.
+func (h *ExecHookHook) run(e *HookEvent) (terraform.HookAction, error) {
+	for _, hook := range h.Hooks {
+		err := hook.Run(e)
+		if err == nil {
+			continue
+		}
+
+		err = fmt.Errorf("hook %s: %s", hook.Name, h.Redactor.Redact(err.Error()))
+		if strings.HasPrefix(e.Event, "pre_") {
+			return terraform.HookActionContinue, err
+		}
+
+		log.Printf("[ERROR] Error running %s", err)
+		if h.Ui != nil {
+			h.Ui.Error(fmt.Sprintf("Error running %s", err))
+		}
+	}
+
+	return terraform.HookActionContinue, nil
+}
+
+func (h *ExecHookHook) errString(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return h.Redactor.Redact(err.Error())
+}
+
+// execHookHook returns the hook that runs the hook executables, or nil
+// if there are none.
+func (m *Meta) execHookHook() *ExecHookHook {
+	if len(m.ExecHooks) == 0 {
+		return nil
+	}
+
+	return &ExecHookHook{
+		Hooks:    m.ExecHooks,
+		Ui:       m.Ui,
+		Redactor: m.redactor,
+	}
+}
@@ -0,0 +1,90 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+)
+
+func TestExecHookHook_impl(t *testing.T) {
+	var _ terraform.Hook = new(ExecHookHook)
+}
+
+func TestExecHookRun(t *testing.T) {
+	out := testTempFile(t)
+	defer os.Remove(out)
+
+	h := &ExecHook{
+		Name:    "record",
+		Command: testScript(t, "cat >> "+out+"; echo >> "+out),
+		Events:  []string{"pre_apply"},
+	}
+	defer os.Remove(h.Command)
+
+	if err := h.Run(&HookEvent{Event: "post_apply"}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	err := h.Run(&HookEvent{
+		Event:    "pre_apply",
+		Resource: "aws_instance.foo",
+		Action:   "create",
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	data, err := ioutil.ReadFile(out)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Only the events of the hook are given to it
+	expected := `{"event":"pre_apply","resource":"aws_instance.foo","action":"create"}`
+	if strings.TrimSpace(string(data)) != expected {
+		t.Fatalf("bad: %s", data)
+	}
+}
+
+func TestExecHookHook_error(t *testing.T) {
+	r := new(redactor)
+	r.Add("hunter2")
+
+	ui := new(cli.MockUi)
+	h := &ExecHookHook{
+		Hooks: []*ExecHook{
+			&ExecHook{
+				Name:    "deny",
+				Command: testScript(t, "echo denied hunter2; exit 1"),
+			},
+		},
+		Ui:       ui,
+		Redactor: r,
+	}
+	defer os.Remove(h.Hooks[0].Command)
+
+	// Errors before something is done stop it
+	_, err := h.PreApply(
+		"aws_instance.foo",
+		new(terraform.ResourceState),
+		new(terraform.ResourceDiff))
+	if err == nil {
+		t.Fatal("should error")
+	}
+	if !strings.Contains(err.Error(), "hook deny") ||
+		!strings.Contains(err.Error(), "denied <sensitive>") {
+		t.Fatalf("bad: %s", err)
+	}
+
+	// Errors after something is done are only reported
+	_, err = h.PostApply("aws_instance.foo", new(terraform.ResourceState), nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "hook deny") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+}
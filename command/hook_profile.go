@@ -0,0 +1,268 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// ProfileHook is a hook that records how long each provider and
+// provisioner call takes for every resource, so that a timing report
+// can be written at the end of a run.
+type ProfileHook struct {
+	Timings []ProfileTiming
+
+	pending map[string]time.Time
+
+	sync.Mutex
+	terraform.NilHook
+}
+
+// ProfileTiming is the duration of a single operation on a resource.
+type ProfileTiming struct {
+	Id        string
+	Operation string
+	Duration  time.Duration
+}
+
+func (h *ProfileHook) PreApply(
+	id string,
+	s *terraform.ResourceState,
+	d *terraform.ResourceDiff) (terraform.HookAction, error) {
+	h.start(id, "apply")
+	return terraform.HookActionContinue, nil
+}
+
+func (h *ProfileHook) PostApply(
+	id string,
+	s *terraform.ResourceState,
+	e error) (terraform.HookAction, error) {
+	h.stop(id, "apply")
+	return terraform.HookActionContinue, nil
+}
+
+func (h *ProfileHook) PreDiff(
+	id string, s *terraform.ResourceState) (terraform.HookAction, error) {
+	h.start(id, "diff")
+	return terraform.HookActionContinue, nil
+}
+
+func (h *ProfileHook) PostDiff(
+	id string, d *terraform.ResourceDiff) (terraform.HookAction, error) {
+	h.stop(id, "diff")
+	return terraform.HookActionContinue, nil
+}
+
+func (h *ProfileHook) PreProvision(
+	id string, provId string) (terraform.HookAction, error) {
+	h.start(id, "provision "+provId)
+	return terraform.HookActionContinue, nil
+}
+
+func (h *ProfileHook) PostProvision(
+	id string, provId string) (terraform.HookAction, error) {
+	h.stop(id, "provision "+provId)
+	return terraform.HookActionContinue, nil
+}
+
+func (h *ProfileHook) PreRefresh(
+	id string, s *terraform.ResourceState) (terraform.HookAction, error) {
+	h.start(id, "refresh")
+	return terraform.HookActionContinue, nil
+}
+
+func (h *ProfileHook) PostRefresh(
+	id string, s *terraform.ResourceState) (terraform.HookAction, error) {
+	h.stop(id, "refresh")
+	return terraform.HookActionContinue, nil
+}
+
+// WriteReport writes a human readable report of the recorded timings,
+// slowest first, followed by the total time spent in each operation.
+func (h *ProfileHook) WriteReport(w io.Writer) error {
+	h.Lock()
+	defer h.Unlock()
+
+	timings := make([]ProfileTiming, len(h.Timings))
+	copy(timings, h.Timings)
+	sort.Sort(profileTimingSort(timings))
+
+	totals := make(map[string]time.Duration)
+	counts := make(map[string]int)
+	for _, t := range timings {
+		totals[t.Operation] += t.Duration
+		counts[t.Operation] += 1
+	}
+
+	ops := make([]string, 0, len(totals))
+	for op, _ := range totals {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+
+	if _, err := fmt.Fprintf(w, "Timings (slowest first):\n\n"); err != nil {
+		return err
+	}
+	for _, t := range timings {
+		_, err := fmt.Fprintf(
+			w, "  %-12s %-20s %s\n", t.Duration, t.Operation, t.Id)
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "\nTotals:\n\n"); err != nil {
+		return err
+	}
+	for _, op := range ops {
+		_, err := fmt.Fprintf(
+			w, "  %-20s %s (%d calls)\n", op, totals[op], counts[op])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteSummary writes a short summary of the recorded timings, meant to
+// be shown at the end of a run: the number of calls made to each
+// provider, and the n slowest resources and operations.
+func (h *ProfileHook) WriteSummary(w io.Writer, n int) error {
+	h.Lock()
+	defer h.Unlock()
+
+	timings := make([]ProfileTiming, len(h.Timings))
+	copy(timings, h.Timings)
+	sort.Sort(profileTimingSort(timings))
+
+	// Count the calls to each provider by operation. Provisioners aren't
+	// provider calls, but they do count towards the time of a resource.
+	calls := make(map[string]map[string]int)
+	resources := make(map[string]time.Duration)
+	for _, t := range timings {
+		resources[t.Id] += t.Duration
+		if strings.HasPrefix(t.Operation, "provision ") {
+			continue
+		}
+
+		p := resourceProviderName(t.Id)
+		if calls[p] == nil {
+			calls[p] = make(map[string]int)
+		}
+		calls[p][t.Operation] += 1
+	}
+
+	providers := make([]string, 0, len(calls))
+	for p, _ := range calls {
+		providers = append(providers, p)
+	}
+	sort.Strings(providers)
+
+	resourceTimings := make([]ProfileTiming, 0, len(resources))
+	for id, d := range resources {
+		resourceTimings = append(resourceTimings, ProfileTiming{
+			Id:       id,
+			Duration: d,
+		})
+	}
+	sort.Sort(profileTimingSort(resourceTimings))
+
+	if len(timings) > n {
+		timings = timings[:n]
+	}
+	if len(resourceTimings) > n {
+		resourceTimings = resourceTimings[:n]
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteString("Provider calls:\n\n")
+	for _, p := range providers {
+		ops := make([]string, 0, len(calls[p]))
+		total := 0
+		for op, count := range calls[p] {
+			ops = append(ops, fmt.Sprintf("%s: %d", op, count))
+			total += count
+		}
+		sort.Strings(ops)
+
+		buf.WriteString(fmt.Sprintf(
+			"  %-20s %d (%s)\n", p, total, strings.Join(ops, ", ")))
+	}
+
+	buf.WriteString("\nSlowest resources:\n\n")
+	for _, t := range resourceTimings {
+		buf.WriteString(fmt.Sprintf(
+			"  %-12s %s\n", roundDuration(t.Duration), t.Id))
+	}
+
+	buf.WriteString("\nSlowest operations:\n\n")
+	for _, t := range timings {
+		buf.WriteString(fmt.Sprintf(
+			"  %-12s %-20s %s\n",
+			roundDuration(t.Duration), t.Operation, t.Id))
+	}
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+func (h *ProfileHook) start(id, op string) {
+	h.Lock()
+	defer h.Unlock()
+
+	if h.pending == nil {
+		h.pending = make(map[string]time.Time)
+	}
+
+	h.pending[op+"\x00"+id] = time.Now()
+}
+
+func (h *ProfileHook) stop(id, op string) {
+	h.Lock()
+	defer h.Unlock()
+
+	key := op + "\x00" + id
+	start, ok := h.pending[key]
+	if !ok {
+		return
+	}
+	delete(h.pending, key)
+
+	h.Timings = append(h.Timings, ProfileTiming{
+		Id:        id,
+		Operation: op,
+		Duration:  time.Now().Sub(start),
+	})
+}
+
+// resourceProviderName returns the name of the provider of a resource
+// by the prefix of its type, such as "aws" for "aws_instance.foo".
+func resourceProviderName(id string) string {
+	t := strings.SplitN(id, ".", 2)[0]
+	return strings.SplitN(t, "_", 2)[0]
+}
+
+// roundDuration rounds a duration to milliseconds so it's easy to read.
+func roundDuration(d time.Duration) time.Duration {
+	return d - d%time.Millisecond
+}
+
+// profileTimingSort sorts timings by duration, longest first.
+type profileTimingSort []ProfileTiming
+
+func (s profileTimingSort) Len() int      { return len(s) }
+func (s profileTimingSort) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s profileTimingSort) Less(i, j int) bool {
+	if s[i].Duration == s[j].Duration {
+		return s[i].Id < s[j].Id
+	}
+
+	return s[i].Duration > s[j].Duration
+}
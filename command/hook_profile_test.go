@@ -0,0 +1,79 @@
+package command
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestProfileHook_impl(t *testing.T) {
+	var _ terraform.Hook = new(ProfileHook)
+}
+
+func TestProfileHook(t *testing.T) {
+	h := new(ProfileHook)
+
+	h.PreDiff("foo", nil)
+	h.PostDiff("foo", nil)
+	h.PreApply("foo", nil, nil)
+	h.PreProvision("foo", "shell")
+	h.PostProvision("foo", "shell")
+	h.PostApply("foo", nil, nil)
+
+	// A post without a pre shouldn't be recorded
+	h.PostRefresh("bar", nil)
+
+	if len(h.Timings) != 3 {
+		t.Fatalf("bad: %#v", h.Timings)
+	}
+
+	var buf bytes.Buffer
+	if err := h.WriteReport(&buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := buf.String()
+	for _, op := range []string{"diff", "apply", "provision shell"} {
+		if !strings.Contains(actual, op) {
+			t.Fatalf("missing %q: %s", op, actual)
+		}
+	}
+	if strings.Contains(actual, "refresh") {
+		t.Fatalf("bad: %s", actual)
+	}
+}
+
+func TestProfileHook_summary(t *testing.T) {
+	h := new(ProfileHook)
+
+	h.PreRefresh("aws_instance.foo", nil)
+	h.PostRefresh("aws_instance.foo", nil)
+	h.PreDiff("aws_instance.foo", nil)
+	h.PostDiff("aws_instance.foo", nil)
+	h.PreDiff("do_droplet.bar", nil)
+	h.PostDiff("do_droplet.bar", nil)
+	h.PreProvision("do_droplet.bar", "shell")
+	h.PostProvision("do_droplet.bar", "shell")
+
+	var buf bytes.Buffer
+	if err := h.WriteSummary(&buf, 1); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := buf.String()
+	for _, s := range []string{
+		"aws                  2 (diff: 1, refresh: 1)",
+		"do                   1 (diff: 1)",
+	} {
+		if !strings.Contains(actual, s) {
+			t.Fatalf("missing %q: %s", s, actual)
+		}
+	}
+
+	// Only the slowest resource and operation are shown
+	if n := strings.Count(actual, "\n  "); n != 4 {
+		t.Fatalf("bad: %d\n\n%s", n, actual)
+	}
+}
@@ -0,0 +1,51 @@
+package command
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// ResumeHook is a hook that records the resources that were applied, so
+// that an apply that fails partway can be resumed without applying them
+// again.
+type ResumeHook struct {
+	completed map[string]struct{}
+
+	sync.Mutex
+	terraform.NilHook
+}
+
+func (h *ResumeHook) PostApply(
+	id string,
+	s *terraform.ResourceState,
+	e error) (terraform.HookAction, error) {
+	if e != nil {
+		return terraform.HookActionContinue, nil
+	}
+
+	h.Lock()
+	defer h.Unlock()
+
+	if h.completed == nil {
+		h.completed = make(map[string]struct{})
+	}
+	h.completed[id] = struct{}{}
+
+	return terraform.HookActionContinue, nil
+}
+
+// Completed returns the resources that were applied, sorted.
+func (h *ResumeHook) Completed() []string {
+	h.Lock()
+	defer h.Unlock()
+
+	result := make([]string, 0, len(h.completed))
+	for k, _ := range h.completed {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+
+	return result
+}
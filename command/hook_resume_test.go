@@ -0,0 +1,27 @@
+package command
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestResumeHook_impl(t *testing.T) {
+	var _ terraform.Hook = new(ResumeHook)
+}
+
+func TestResumeHook(t *testing.T) {
+	h := new(ResumeHook)
+	s := new(terraform.ResourceState)
+
+	h.PostApply("foo", s, nil)
+	h.PostApply("bar", s, fmt.Errorf("error"))
+	h.PostApply("baz", s, nil)
+
+	expected := []string{"baz", "foo"}
+	if actual := h.Completed(); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
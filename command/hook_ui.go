@@ -6,24 +6,41 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/mitchellh/cli"
 	"github.com/mitchellh/colorstring"
 )
 
+// defaultPeriodicUiTimer is how often to output that a resource is
+// still being applied, if PeriodicUiTimer isn't set.
+const defaultPeriodicUiTimer = 10 * time.Second
+
 type UiHook struct {
 	terraform.NilHook
 
 	Colorize *colorstring.Colorize
 	Ui       cli.Ui
 
+	// PeriodicUiTimer is how often to output a message that a resource
+	// is still being applied, so that users can tell a slow operation
+	// from one that is stuck.
+	PeriodicUiTimer time.Duration
+
 	l         sync.Mutex
 	once      sync.Once
-	resources map[string]uiResourceOp
+	resources map[string]uiResourceState
 	ui        cli.Ui
 }
 
+// uiResourceState tracks a resource that is currently being applied.
+type uiResourceState struct {
+	Op     uiResourceOp
+	Start  time.Time
+	DoneCh chan struct{}
+}
+
 type uiResourceOp byte
 
 const (
@@ -46,8 +63,14 @@ func (h *UiHook) PreApply(
 		op = uiResourceCreate
 	}
 
+	state := uiResourceState{
+		Op:     op,
+		Start:  time.Now(),
+		DoneCh: make(chan struct{}),
+	}
+
 	h.l.Lock()
-	h.resources[id] = op
+	h.resources[id] = state
 	h.l.Unlock()
 
 	var operation string
@@ -109,20 +132,54 @@ func (h *UiHook) PreApply(
 		operation,
 		attrString)))
 
+	// Periodically output that we're still working on this resource
+	go h.stillApplying(id, state)
+
 	return terraform.HookActionContinue, nil
 }
 
+func (h *UiHook) stillApplying(id string, state uiResourceState) {
+	var msg string
+	switch state.Op {
+	case uiResourceModify:
+		msg = "Still modifying..."
+	case uiResourceDestroy:
+		msg = "Still destroying..."
+	case uiResourceCreate:
+		msg = "Still creating..."
+	}
+
+	for {
+		select {
+		case <-state.DoneCh:
+			return
+		case <-time.After(h.PeriodicUiTimer):
+			// Timer up, show status
+		}
+
+		h.ui.Output(h.Colorize.Color(fmt.Sprintf(
+			"[reset][bold]%s: %s (%s elapsed)[reset_bold]",
+			id,
+			msg,
+			time.Now().Sub(state.Start)/time.Second*time.Second)))
+	}
+}
+
 func (h *UiHook) PostApply(
 	id string,
 	s *terraform.ResourceState,
 	applyerr error) (terraform.HookAction, error) {
 	h.l.Lock()
-	op := h.resources[id]
+	state, ok := h.resources[id]
 	delete(h.resources, id)
 	h.l.Unlock()
 
+	if ok {
+		close(state.DoneCh)
+	}
+
 	var msg string
-	switch op {
+	switch state.Op {
 	case uiResourceModify:
 		msg = "Modifications complete"
 	case uiResourceDestroy:
@@ -166,12 +223,28 @@ func (h *UiHook) PreRefresh(
 	return terraform.HookActionContinue, nil
 }
 
+func (h *UiHook) PostRefresh(
+	id string, s *terraform.ResourceState) (terraform.HookAction, error) {
+	h.once.Do(h.init)
+
+	if s == nil || s.ID == "" {
+		h.ui.Output(h.Colorize.Color(fmt.Sprintf(
+			"[reset][bold][yellow]%s: Not found, it was deleted outside of Terraform",
+			id)))
+	}
+
+	return terraform.HookActionContinue, nil
+}
+
 func (h *UiHook) init() {
 	if h.Colorize == nil {
 		panic("colorize not given")
 	}
 
-	h.resources = make(map[string]uiResourceOp)
+	h.resources = make(map[string]uiResourceState)
+	if h.PeriodicUiTimer == 0 {
+		h.PeriodicUiTimer = defaultPeriodicUiTimer
+	}
 
 	// Wrap the ui so that it is safe for concurrency regardless of the
 	// underlying reader/writer that is in place.
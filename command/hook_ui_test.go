@@ -0,0 +1,52 @@
+package command
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+	"github.com/mitchellh/colorstring"
+)
+
+func TestUiHook_impl(t *testing.T) {
+	var _ terraform.Hook = new(UiHook)
+}
+
+func TestUiHook_stillApplying(t *testing.T) {
+	ui := new(cli.MockUi)
+	h := &UiHook{
+		Colorize: &colorstring.Colorize{
+			Colors:  colorstring.DefaultColors,
+			Disable: true,
+		},
+		Ui:              ui,
+		PeriodicUiTimer: 10 * time.Millisecond,
+	}
+
+	s := new(terraform.ResourceState)
+	d := new(terraform.ResourceDiff)
+	if _, err := h.PreApply("foo", s, d); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if _, err := h.PostApply("foo", s, nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Nothing should be output once the resource is complete
+	before := ui.OutputWriter.String()
+	time.Sleep(50 * time.Millisecond)
+	actual := ui.OutputWriter.String()
+	if actual != before {
+		t.Fatalf("output after complete: %s", actual)
+	}
+
+	if !strings.Contains(actual, "foo: Still creating...") {
+		t.Fatalf("bad: %s", actual)
+	}
+	if !strings.Contains(actual, "foo: Creation complete") {
+		t.Fatalf("bad: %s", actual)
+	}
+}
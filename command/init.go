@@ -0,0 +1,334 @@
+package command
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// InitCommand is a Command implementation that prepares a directory
+// for running Terraform, optionally copying a starter configuration
+// into it first.
+type InitCommand struct {
+	Meta
+}
+
+func (c *InitCommand) Run(args []string) int {
+	var source string
+	var verifyPlugins bool
+
+	args = c.Meta.process(args, false)
+
+	cmdFlags := flag.NewFlagSet("init", flag.ContinueOnError)
+	cmdFlags.StringVar(&source, "from", "", "source")
+	cmdFlags.StringVar(&source, "from-module", "", "source")
+	cmdFlags.BoolVar(&verifyPlugins, "verify-plugins", true, "verify-plugins")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		return 1
+	}
+
+	var dir string
+	args = cmdFlags.Args()
+	if len(args) > 1 {
+		c.Ui.Error(
+			"The init command expects at most one argument with the path\n" +
+				"to the directory to initialize.\n")
+		cmdFlags.Usage()
+		return 1
+	} else if len(args) == 1 {
+		dir = c.path(args[0])
+	} else {
+		var err error
+		dir, err = c.wd()
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error getting pwd: %s", err))
+			return 1
+		}
+	}
+
+	hasConfig, err := dirHasConfig(dir)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading directory: %s", err))
+		return 1
+	}
+
+	if source != "" {
+		if hasConfig {
+			c.Ui.Error(fmt.Sprintf(
+				"The directory %s already contains a Terraform configuration.\n"+
+					"A starter configuration can only be copied into a directory\n"+
+					"without one.", dir))
+			return 1
+		}
+
+		c.Ui.Output(fmt.Sprintf("Copying configuration from %s...", source))
+		if err := copyConfig(source, dir); err != nil {
+			c.Ui.Error(fmt.Sprintf(
+				"Error copying configuration from %s: %s", source, err))
+			return 1
+		}
+
+		hasConfig = true
+	}
+
+	dataDir := filepath.Join(dir, DefaultDataDir)
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error creating %s: %s", dataDir, err))
+		return 1
+	}
+
+	if hasConfig && verifyPlugins {
+		c.Ui.Output("Verifying plugins for the configuration...")
+
+		ctx, _, err := c.Context(dir, "")
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		// Building the graph starts every provider the configuration
+		// uses, which fails if a plugin is missing or broken.
+		if _, err := ctx.Graph(); err != nil {
+			c.Ui.Error(fmt.Sprintf("Error verifying plugins: %s", err))
+			return 1
+		}
+	}
+
+	c.Ui.Output(c.Colorize().Color(
+		"\n[reset][bold][green]Terraform has been successfully initialized!"))
+	if !hasConfig {
+		c.Ui.Output(
+			"\nThe directory has no Terraform configuration files yet. Add\n" +
+				"some, or use the -from-module flag to start from an existing one.")
+	}
+
+	return 0
+}
+
+func (c *InitCommand) Help() string {
+	helpText := `
+Usage: terraform init [options] [DIR]
+
+  Prepare a directory for running Terraform. This creates the
+  directory Terraform keeps its working data in and verifies that
+  the plugins for every provider used in the configuration can be
+  started.
+
+  This is always safe to run multiple times. If DIR isn't given, the
+  current directory is initialized.
+
+Options:
+
+  -from-module=source    Copy a starter configuration into the directory
+                         before initializing it. The source can be a path
+                         to a directory, an HTTP URL of a single
+                         configuration file, or a git repository such as
+                         "git::https://example.com/skeleton.git//dir?ref=v1".
+                         The directory must not have a configuration
+                         already. -from is an older name for this flag.
+
+  -no-color              If specified, output won't contain any color.
+
+  -verify-plugins=true   Start the plugins for the providers in the
+                         configuration to check that they work.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *InitCommand) Synopsis() string {
+	return "Prepare a new or existing directory for Terraform"
+}
+
+// dirHasConfig returns true if the directory has Terraform configuration
+// files in it. A directory that doesn't exist has no configuration.
+func dirHasConfig(dir string) (bool, error) {
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	for _, fi := range fis {
+		if fi.IsDir() {
+			continue
+		}
+
+		name := fi.Name()
+		if strings.HasSuffix(name, ".tf") || strings.HasSuffix(name, ".tf.json") {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// copyConfig copies the configuration at source into the directory dst,
+// creating dst if it doesn't exist.
+func copyConfig(source, dst string) error {
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+
+	if strings.HasPrefix(source, "git::") {
+		return copyConfigGit(strings.TrimPrefix(source, "git::"), dst)
+	}
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		return copyConfigHTTP(source, dst)
+	}
+
+	fis, err := ioutil.ReadDir(source)
+	if err != nil {
+		return err
+	}
+
+	// Only the top level is copied, since that is all that is loaded
+	// as configuration. Hidden files are skipped so that things like
+	// another directory's working data aren't copied. Symlinks are
+	// skipped so that a cloned source can't copy files from elsewhere
+	// on the machine.
+	for _, fi := range fis {
+		if fi.IsDir() || strings.HasPrefix(fi.Name(), ".") {
+			continue
+		}
+		if fi.Mode()&os.ModeSymlink != 0 {
+			log.Printf("[WARN] Not copying symlink: %s", fi.Name())
+			continue
+		}
+
+		if err := copyFile(
+			filepath.Join(source, fi.Name()),
+			filepath.Join(dst, fi.Name())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// copyConfigGit clones the git repository at source and copies the
+// configuration in it. Like module sources, a "//" after the repository
+// URL selects a subdirectory and "?ref=" a branch or tag.
+func copyConfigGit(source, dst string) error {
+	var ref string
+	if idx := strings.Index(source, "?"); idx >= 0 {
+		query := source[idx+1:]
+		source = source[:idx]
+		if !strings.HasPrefix(query, "ref=") {
+			return fmt.Errorf("unknown parameter in git source: %s", query)
+		}
+
+		ref = strings.TrimPrefix(query, "ref=")
+	}
+
+	repo, subdir := source, ""
+	start := 0
+	if idx := strings.Index(source, "://"); idx >= 0 {
+		start = idx + 3
+	}
+	if idx := strings.Index(source[start:], "//"); idx >= 0 {
+		repo = source[:start+idx]
+		subdir = source[start+idx+2:]
+	}
+
+	td, err := ioutil.TempDir("", "tf-init")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(td)
+
+	args := []string{"clone", "--depth", "1"}
+	if ref != "" {
+		args = append(args, "--branch", ref)
+	}
+	// The "--" keeps a repository that starts with "-" from being read
+	// as an option of git.
+	args = append(args, "--", repo, td)
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf(
+			"error cloning %s: %s\n\n%s",
+			repo, err, strings.TrimSpace(stderr.String()))
+	}
+
+	// The subdirectory must be in the clone, so that a source can't copy
+	// files from elsewhere on the machine with ".." or a symlink.
+	root, err := filepath.EvalSymlinks(td)
+	if err != nil {
+		return err
+	}
+	dir, err := filepath.EvalSymlinks(filepath.Join(td, filepath.FromSlash(subdir)))
+	if err != nil {
+		return fmt.Errorf("error finding %q in %s: %s", subdir, repo, err)
+	}
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || rel == ".." ||
+		strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("subdirectory %q is outside of %s", subdir, repo)
+	}
+
+	return copyConfig(dir, dst)
+}
+
+func copyConfigHTTP(source, dst string) error {
+	name := path.Base(strings.SplitN(source, "?", 2)[0])
+	if !strings.HasSuffix(name, ".tf") && !strings.HasSuffix(name, ".tf.json") {
+		return fmt.Errorf(
+			"URL must be of a .tf or .tf.json file, got: %s", name)
+	}
+
+	resp, err := http.Get(source)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("bad response code: %d", resp.StatusCode)
+	}
+
+	f, err := os.Create(filepath.Join(dst, name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, resp.Body)
+	return err
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
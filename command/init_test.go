@@ -0,0 +1,351 @@
+package command
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/mitchellh/cli"
+)
+
+func TestInit(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	ui := new(cli.MockUi)
+	c := &InitCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	if code := c.Run([]string{td}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	if _, err := os.Stat(filepath.Join(td, DefaultDataDir)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestInit_from(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	dir := filepath.Join(td, "foo")
+
+	ui := new(cli.MockUi)
+	c := &InitCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-from", testFixturePath("init"),
+		dir,
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "main.tf")); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, DefaultDataDir)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestInit_fromExisting(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	err := ioutil.WriteFile(filepath.Join(td, "main.tf"), []byte(""), 0644)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	ui := new(cli.MockUi)
+	c := &InitCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-from", testFixturePath("init"),
+		td,
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.OutputWriter.String())
+	}
+}
+
+func TestInit_fromHTTP(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `resource "test_instance" "foo" {}`)
+		}))
+	defer ts.Close()
+
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	ui := new(cli.MockUi)
+	c := &InitCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-from", ts.URL + "/starter.tf",
+		td,
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(td, "starter.tf"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if string(data) != `resource "test_instance" "foo" {}` {
+		t.Fatalf("bad: %s", data)
+	}
+}
+
+func TestInit_fromModuleGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed")
+	}
+
+	repo := testTempDir(t)
+	defer os.RemoveAll(repo)
+
+	sub := filepath.Join(repo, "skeleton")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	err := ioutil.WriteFile(
+		filepath.Join(sub, "main.tf"),
+		[]byte(`resource "test_instance" "foo" {}`), 0644)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	testGit(t, repo, "init", "-q")
+	testGit(t, repo, "add", ".")
+	testGit(t, repo, "commit", "-q", "-m", "skeleton")
+	testGit(t, repo, "tag", "v1")
+
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	ui := new(cli.MockUi)
+	c := &InitCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-from-module", "git::file://" + filepath.ToSlash(repo) + "//skeleton?ref=v1",
+		td,
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(td, "main.tf"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if string(data) != `resource "test_instance" "foo" {}` {
+		t.Fatalf("bad: %s", data)
+	}
+	if _, err := os.Stat(filepath.Join(td, ".git")); err == nil {
+		t.Fatal("the repository shouldn't be copied")
+	}
+}
+
+func TestInit_fromModuleGitBadRef(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed")
+	}
+
+	repo := testTempDir(t)
+	defer os.RemoveAll(repo)
+
+	err := ioutil.WriteFile(filepath.Join(repo, "main.tf"), []byte(""), 0644)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	testGit(t, repo, "init", "-q")
+	testGit(t, repo, "add", ".")
+	testGit(t, repo, "commit", "-q", "-m", "skeleton")
+
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	ui := new(cli.MockUi)
+	c := &InitCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-from-module", "git::file://" + filepath.ToSlash(repo) + "?ref=nope",
+		td,
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.OutputWriter.String())
+	}
+	if _, err := os.Stat(filepath.Join(td, "main.tf")); err == nil {
+		t.Fatal("nothing should be copied")
+	}
+}
+
+func TestInit_fromModuleGitOutside(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed")
+	}
+
+	outside := testTempDir(t)
+	defer os.RemoveAll(outside)
+	err := ioutil.WriteFile(filepath.Join(outside, "main.tf"), []byte(""), 0644)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	repo := testTempDir(t)
+	defer os.RemoveAll(repo)
+
+	if err := os.Symlink(outside, filepath.Join(repo, "link")); err != nil {
+		t.Skipf("symlinks aren't supported: %s", err)
+	}
+	testGit(t, repo, "init", "-q")
+	testGit(t, repo, "add", ".")
+	testGit(t, repo, "commit", "-q", "-m", "skeleton")
+
+	for _, subdir := range []string{"link", "../" + filepath.Base(outside)} {
+		td := testTempDir(t)
+		defer os.RemoveAll(td)
+
+		ui := new(cli.MockUi)
+		c := &InitCommand{
+			Meta: Meta{
+				ContextOpts: testCtxConfig(testProvider()),
+				Ui:          ui,
+			},
+		}
+
+		args := []string{
+			"-from-module", "git::file://" + filepath.ToSlash(repo) + "//" + subdir,
+			td,
+		}
+		if code := c.Run(args); code != 1 {
+			t.Fatalf("%s: bad: %d\n\n%s", subdir, code, ui.OutputWriter.String())
+		}
+		if _, err := os.Stat(filepath.Join(td, "main.tf")); err == nil {
+			t.Fatalf("%s: nothing should be copied", subdir)
+		}
+	}
+}
+
+func TestInit_fromModuleGitSymlink(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed")
+	}
+
+	outside := testTempDir(t)
+	defer os.RemoveAll(outside)
+	secret := filepath.Join(outside, "secret.tf")
+	if err := ioutil.WriteFile(secret, []byte(""), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	repo := testTempDir(t)
+	defer os.RemoveAll(repo)
+	err := ioutil.WriteFile(filepath.Join(repo, "main.tf"), []byte(""), 0644)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := os.Symlink(secret, filepath.Join(repo, "link.tf")); err != nil {
+		t.Skipf("symlinks aren't supported: %s", err)
+	}
+	testGit(t, repo, "init", "-q")
+	testGit(t, repo, "add", ".")
+	testGit(t, repo, "commit", "-q", "-m", "skeleton")
+
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	ui := new(cli.MockUi)
+	c := &InitCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-from-module", "git::file://" + filepath.ToSlash(repo),
+		td,
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if _, err := os.Stat(filepath.Join(td, "main.tf")); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := os.Lstat(filepath.Join(td, "link.tf")); err == nil {
+		t.Fatal("the symlink should not be copied")
+	}
+}
+
+func TestInit_missingProvider(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	ui := new(cli.MockUi)
+	c := &InitCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-from", testFixturePath("init-missing-provider"),
+		td,
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.OutputWriter.String())
+	}
+
+	// Skipping verification should succeed
+	ui = new(cli.MockUi)
+	c = &InitCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+	args = []string{"-verify-plugins=false", td}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+}
@@ -1,12 +1,20 @@
 package command
 
 import (
+	"bytes"
+	"crypto/ecdsa"
 	"flag"
 	"fmt"
+	"log"
 	"math/rand"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/state"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/mitchellh/cli"
 	"github.com/mitchellh/colorstring"
@@ -18,20 +26,128 @@ type Meta struct {
 	ContextOpts *terraform.ContextOpts
 	Ui          cli.Ui
 
+	// AuditLog, if set, records every run of a command that changes
+	// the state.
+	AuditLog *AuditLog
+
+	// Webhooks are notified when an apply starts, completes and fails.
+	Webhooks []*Webhook
+
+	// Events publishes the lifecycle events of runs to the event sinks.
+	Events *EventBus
+
+	// ExecHooks are executables that are run at the hook points of
+	// every operation, such as before and after each resource is applied.
+	ExecHooks []*ExecHook
+
+	// Policies check plans before they are applied.
+	Policies []*Policy
+
+	// Approvers are the people who can approve saved plans, with their
+	// public keys. If there are any, only saved plans that one of them
+	// approved, other than the user applying them, can be applied.
+	Approvers map[string]*ecdsa.PublicKey
+
+	// Rules are conventions that the resources in the configuration
+	// must follow, checked along with the configuration.
+	Rules []*Rule
+
+	// CostEstimators estimate the monthly cost of plans, which is shown
+	// with the plan. If CostBudget is set, plans that would take the cost
+	// over it aren't applied.
+	CostEstimators []CostEstimator
+	CostBudget     float64
+
+	// MaxDestroy and MaxDestroyPercent limit how many resources, and
+	// what percentage of the resources in the state, an apply can
+	// destroy, including the resources it replaces. Zero means there is
+	// no limit. Plans over the limit aren't applied.
+	MaxDestroy        int
+	MaxDestroyPercent float64
+
+	// StateRetention, if set, keeps old versions of local state files
+	// in the directory next to them, and is how many are kept.
+	StateRetention *state.Retention
+
+	// ReadOnly guarantees that no command changes infrastructure or the
+	// state. Commands that would change them refuse to run, and refresh
+	// writes the refreshed state to a temporary file instead.
+	ReadOnly bool
+
+	// WorkingDir is the directory that relative paths given to the
+	// command are relative to, including the default paths. If it is
+	// empty, the process working directory is used. Commands never change
+	// the process working directory, so this is what allows running
+	// commands on several directories from one process.
+	WorkingDir string
+
+	// ProviderResolver, if set, finds the providers of the types of
+	// resources that none of the providers manage, inferred from the
+	// types, instead of the types being reported as unknown.
+	ProviderResolver ProviderResolver
+
+	// Configuration, if set, is used by Context instead of loading the
+	// configuration from the directory it's given, for programs that
+	// build the configuration themselves. Rules are still loaded from the
+	// directory.
+	Configuration *config.Config
+
 	// State read when calling `Context`. This is available after calling
 	// `Context`.
 	state *terraform.State
 
+	// Plan read when calling `Context`, if it was given a plan file.
+	plan *terraform.Plan
+
+	// Configuration read when calling `Context`, if it wasn't given a
+	// plan file, along with the rules in the same directory.
+	config      *config.Config
+	configRules []*Rule
+
+	// The providers inferred by Context, keyed by name.
+	inferredProviders map[string]terraform.ResourceProviderFactory
+
+	// The providers and provisioners created for contexts, which are
+	// stopped with closePlugins.
+	plugins *pluginSet
+
+	// The warnings about the configuration shown by validateContext.
+	warnings []string
+
+	// refuseNewerVersion makes Context refuse states and plans that were
+	// written by a newer version of Terraform, instead of warning about
+	// them. Commands that write the state set it unless -override is
+	// given, since writing the state would drop what this version
+	// doesn't understand.
+	refuseNewerVersion bool
+
 	// This can be set by the command itself to provide extra hooks.
 	extraHooks []terraform.Hook
 
+	// redactor hides sensitive values from the output of the command
+	// and the log. Values are added with addSensitive.
+	redactor  *redactor
+	redactLog bool
+
 	// Variables for the context (private)
 	autoKey       string
 	autoVariables map[string]string
 	variables     map[string]string
 
-	color bool
-	oldUi cli.Ui
+	color     bool
+	debugDump string
+	oldUi     cli.Ui
+	strict    bool
+
+	// runID identifies the run of the command, and message is why it was
+	// run, as given with -message. They're recorded in the state and the
+	// audit log.
+	runID   string
+	message string
+
+	// quiet hides the progress of operations, such as each resource
+	// being refreshed.
+	quiet bool
 }
 
 // Colorize returns the colorization structure for a command.
@@ -46,8 +162,6 @@ func (m *Meta) Colorize() *colorstring.Colorize {
 // Context returns a Terraform Context taking into account the context
 // options used to initialize this meta configuration.
 func (m *Meta) Context(path, statePath string) (*terraform.Context, bool, error) {
-	opts := m.contextOpts()
-
 	// First try to just read the plan directly from the path given.
 	f, err := os.Open(path)
 	if err == nil {
@@ -62,55 +176,151 @@ func (m *Meta) Context(path, statePath string) (*terraform.Context, bool, error)
 						"variable values, create a new plan file.")
 			}
 
-			return plan.Context(opts), true, nil
+			if err := m.checkVersion("plan "+path, plan.TFVersion); err != nil {
+				return nil, false, err
+			}
+
+			m.plan = plan
+			m.inferProviders(plan.Config, plan.State)
+			ctx := plan.Context(m.contextOpts())
+			m.addSensitive(ctx.SensitiveValues())
+			return ctx, true, nil
 		}
 	}
 
-	// Load up the state
-	var state *terraform.State
+	// Load up the state. If the state file doesn't exist, it is okay,
+	// since it is probably a new infrastructure.
+	ls := &state.LocalState{Path: statePath}
 	if statePath != "" {
-		f, err := os.Open(statePath)
-		if err != nil && os.IsNotExist(err) {
-			// If the state file doesn't exist, it is okay, since it
-			// is probably a new infrastructure.
-			err = nil
-		} else if err == nil {
-			state, err = terraform.ReadState(f)
-			f.Close()
-		}
-
-		if err != nil {
+		if err := ls.RefreshState(); err != nil {
 			return nil, false, fmt.Errorf("Error loading state: %s", err)
 		}
 	}
+	state := ls.State()
+	if state != nil {
+		if err := m.checkVersion("state file "+statePath, state.TFVersion); err != nil {
+			return nil, false, err
+		}
+	}
 
 	// Store the loaded state
 	m.state = state
 
-	config, err := config.LoadDir(path)
-	if err != nil {
-		return nil, false, fmt.Errorf("Error loading config: %s", err)
+	conf := m.Configuration
+	if conf == nil {
+		conf, err = config.LoadDir(path)
+		if err != nil {
+			return nil, false, fmt.Errorf("Error loading config: %s", err)
+		}
+	}
+	if err := terraform.CheckRequiredVersion(conf); err != nil {
+		return nil, false, err
 	}
-	if err := config.Validate(); err != nil {
+	if err := conf.Validate(); err != nil {
 		return nil, false, fmt.Errorf("Error validating config: %s", err)
 	}
+	rules, err := loadRules(path)
+	if err != nil {
+		return nil, false, fmt.Errorf("Error loading policies: %s", err)
+	}
+
+	m.config = conf
+	m.configRules = rules
+	m.inferProviders(conf, state)
 
-	opts.Config = config
+	opts := m.contextOpts()
+	opts.Config = conf
 	opts.State = state
+	opts.Git = gitRevision(path)
 	ctx := terraform.NewContext(opts)
+	m.addSensitive(ctx.SensitiveValues())
 	return ctx, false, nil
 }
 
+// checkVersion checks the version of Terraform that wrote a state or
+// plan, described by what. If it was written by a newer version, it's an
+// error if refuseNewerVersion is set, and a warning otherwise.
+func (m *Meta) checkVersion(what, version string) error {
+	err := terraform.CheckVersion(version)
+	if err == nil {
+		return nil
+	}
+
+	if m.refuseNewerVersion {
+		return fmt.Errorf(
+			"The %s can't be used: %s.\n"+
+				"This version could lose what it doesn't understand when writing\n"+
+				"the state. Upgrade Terraform, or give the reason for using it\n"+
+				"anyway with -override.", what, err)
+	}
+
+	// The warning goes to the error output so that it doesn't get mixed
+	// into output that is parsed, such as the value of an output.
+	m.Ui.Error(fmt.Sprintf(
+		"[yellow]Warning: [reset]the %s may not be read correctly: %s.",
+		what, err))
+	return nil
+}
+
+// refuseReadOnly reports an error and returns true if this is read-only
+// mode. It is called by commands that can't run in read-only mode.
+func (m *Meta) refuseReadOnly(name string) bool {
+	if !m.ReadOnly {
+		return false
+	}
+
+	m.Ui.Error(fmt.Sprintf(
+		"The %s command can't be run in read-only mode, since it changes\n"+
+			"infrastructure or the state. Read-only mode is enabled by the\n"+
+			"-read-only flag or \"read_only\" in the CLI configuration.",
+		name))
+	return true
+}
+
+// addSensitive adds values that must be hidden from the output of the
+// command and the log.
+func (m *Meta) addSensitive(vs []string) {
+	if len(vs) == 0 {
+		return
+	}
+
+	if m.redactor == nil {
+		m.redactor = new(redactor)
+	}
+
+	// The first time there is something to hide, start hiding it from
+	// the log as well. Output from plugins is logged by this process,
+	// so this covers them too.
+	if !m.redactLog {
+		m.redactLog = true
+		log.SetOutput(&redactWriter{
+			Redactor: m.redactor,
+			Writer:   os.Stderr,
+		})
+	}
+
+	m.redactor.Add(vs...)
+}
+
 // contextOpts returns the options to use to initialize a Terraform
 // context with the settings from this Meta.
 func (m *Meta) contextOpts() *terraform.ContextOpts {
 	var opts terraform.ContextOpts = *m.ContextOpts
 	opts.Hooks = make(
 		[]terraform.Hook,
-		len(m.ContextOpts.Hooks)+len(m.extraHooks)+1)
-	opts.Hooks[0] = m.uiHook()
-	copy(opts.Hooks[1:], m.ContextOpts.Hooks)
-	copy(opts.Hooks[len(m.ContextOpts.Hooks)+1:], m.extraHooks)
+		0,
+		len(m.ContextOpts.Hooks)+len(m.extraHooks)+3)
+	if !m.quiet {
+		opts.Hooks = append(opts.Hooks, m.uiHook())
+	}
+	opts.Hooks = append(opts.Hooks, m.ContextOpts.Hooks...)
+	opts.Hooks = append(opts.Hooks, m.extraHooks...)
+	if h := m.execHookHook(); h != nil {
+		opts.Hooks = append(opts.Hooks, h)
+	}
+	if h := m.eventHook(); h != nil {
+		opts.Hooks = append(opts.Hooks, h)
+	}
 
 	vs := make(map[string]string)
 	for k, v := range opts.Variables {
@@ -124,6 +334,44 @@ func (m *Meta) contextOpts() *terraform.ContextOpts {
 	}
 	opts.Variables = vs
 
+	if len(opts.Secrets) > 0 {
+		var l sync.Mutex
+		secrets := make(map[string]terraform.SecretBackend)
+		for k, b := range opts.Secrets {
+			secrets[k] = &redactSecretBackend{
+				SecretBackend: b,
+				Meta:          m,
+				L:             &l,
+			}
+		}
+		opts.Secrets = secrets
+	}
+
+	if len(m.inferredProviders) > 0 {
+		ps := make(map[string]terraform.ResourceProviderFactory)
+		for k, f := range opts.Providers {
+			ps[k] = f
+		}
+		for k, f := range m.inferredProviders {
+			ps[k] = f
+		}
+		opts.Providers = ps
+	}
+
+	if m.plugins == nil {
+		m.plugins = new(pluginSet)
+	}
+	opts.Providers = m.plugins.Providers(opts.Providers)
+	opts.Provisioners = m.plugins.Provisioners(opts.Provisioners)
+
+	if m.debugDump != "" {
+		d := &debugDump{
+			Dir:      m.path(m.debugDump),
+			Redactor: m.redactor,
+		}
+		opts.Providers = d.Providers(opts.Providers)
+	}
+
 	return &opts
 }
 
@@ -131,15 +379,32 @@ func (m *Meta) contextOpts() *terraform.ContextOpts {
 func (m *Meta) flagSet(n string) *flag.FlagSet {
 	f := flag.NewFlagSet(n, flag.ContinueOnError)
 	f.Var((*FlagVar)(&m.variables), "var", "variables")
-	f.Var((*FlagVarFile)(&m.variables), "var-file", "variable file")
+	f.Var(&metaFlagVarFile{m, &m.variables}, "var-file", "variable file")
+	f.BoolVar(&m.strict, "strict", false, "strict")
+	f.StringVar(&m.debugDump, "debug-dump", "", "path")
 
 	if m.autoKey != "" {
-		f.Var((*FlagVarFile)(&m.autoVariables), m.autoKey, "variable file")
+		f.Var(&metaFlagVarFile{m, &m.autoVariables}, m.autoKey, "variable file")
 	}
 
 	return f
 }
 
+// metaFlagVarFile is a FlagVarFile that reads files relative to the
+// working directory of the Meta.
+type metaFlagVarFile struct {
+	meta *Meta
+	vs   *map[string]string
+}
+
+func (v *metaFlagVarFile) String() string {
+	return ""
+}
+
+func (v *metaFlagVarFile) Set(raw string) error {
+	return (*FlagVarFile)(v.vs).Set(v.meta.path(raw))
+}
+
 // process will process the meta-parameters out of the arguments. This
 // will potentially modify the args in-place. It will return the resulting
 // slice.
@@ -151,7 +416,6 @@ func (m *Meta) process(args []string, vars bool) []string {
 	}
 
 	// Set colorization
-	m.color = m.Color
 	for i, v := range args {
 		if v == "-no-color" {
 			m.Color = false
@@ -159,14 +423,20 @@ func (m *Meta) process(args []string, vars bool) []string {
 			break
 		}
 	}
+	m.color = m.Color
 
 	// Set the UI
 	m.oldUi = m.Ui
+	m.redactor = new(redactor)
+	m.runID = newRunID()
 	m.Ui = &cli.ConcurrentUi{
-		Ui: &ColorizeUi{
-			Colorize:   m.Colorize(),
-			ErrorColor: "[red]",
-			Ui:         m.oldUi,
+		Ui: &redactUi{
+			Redactor: m.redactor,
+			Ui: &ColorizeUi{
+				Colorize:   m.Colorize(),
+				ErrorColor: "[red]",
+				Ui:         m.oldUi,
+			},
 		},
 	}
 
@@ -174,7 +444,7 @@ func (m *Meta) process(args []string, vars bool) []string {
 	// the args...
 	m.autoKey = ""
 	if vars {
-		if _, err := os.Stat(DefaultVarsFilename); err == nil {
+		if _, err := os.Stat(m.path(DefaultVarsFilename)); err == nil {
 			m.autoKey = fmt.Sprintf("var-file-%d", rand.Int())
 			args = append(args, "", "")
 			copy(args[2:], args[0:])
@@ -186,6 +456,48 @@ func (m *Meta) process(args []string, vars bool) []string {
 	return args
 }
 
+// path returns the given path relative to the working directory. Absolute
+// paths, empty paths, and "-" (which disables some outputs) are returned
+// unchanged.
+func (m *Meta) path(p string) string {
+	if m.WorkingDir == "" || p == "" || p == "-" || filepath.IsAbs(p) {
+		return p
+	}
+
+	return filepath.Join(m.WorkingDir, p)
+}
+
+// wd returns the working directory of the command as an absolute path.
+func (m *Meta) wd() (string, error) {
+	if m.WorkingDir == "" {
+		return os.Getwd()
+	}
+
+	return filepath.Abs(m.WorkingDir)
+}
+
+// startStats starts recording statistics about the run with a hook, and
+// returns a function that outputs a summary of them. The function should
+// be called at the end of the run.
+func (m *Meta) startStats() func() {
+	h := new(ProfileHook)
+	m.extraHooks = append(m.extraHooks, h)
+
+	start := time.Now()
+	return func() {
+		buf := new(bytes.Buffer)
+		if err := h.WriteSummary(buf, statsTopN); err != nil {
+			m.Ui.Error(fmt.Sprintf("Error writing statistics: %s", err))
+			return
+		}
+
+		m.Ui.Output(fmt.Sprintf(
+			"\nRun statistics (%s total):\n\n%s",
+			roundDuration(time.Now().Sub(start)),
+			strings.TrimSpace(buf.String())))
+	}
+}
+
 // uiHook returns the UiHook to use with the context.
 func (m *Meta) uiHook() *UiHook {
 	return &UiHook{
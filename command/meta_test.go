@@ -1,6 +1,8 @@
 package command
 
 import (
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 )
@@ -45,4 +47,37 @@ func TestMetaColorize(t *testing.T) {
 	if !m.Colorize().Disable {
 		t.Fatal("should be disabled")
 	}
+
+	// Test disable #2
+	m = new(Meta)
+	m.Color = true
+	args = []string{"foo", "-no-color", "bar"}
+	args2 = []string{"foo", "bar"}
+	args = m.process(args, false)
+	if !reflect.DeepEqual(args, args2) {
+		t.Fatalf("bad: %#v", args)
+	}
+	if !m.Colorize().Disable {
+		t.Fatal("should be disabled")
+	}
+}
+
+func TestMetaPath(t *testing.T) {
+	m := new(Meta)
+	if actual := m.path("foo"); actual != "foo" {
+		t.Fatalf("bad: %s", actual)
+	}
+
+	m.WorkingDir = "bar"
+	cases := map[string]string{
+		"":    "",
+		"-":   "-",
+		"foo": filepath.Join("bar", "foo"),
+		filepath.Join(string(os.PathSeparator), "foo"): filepath.Join(string(os.PathSeparator), "foo"),
+	}
+	for input, expected := range cases {
+		if actual := m.path(input); actual != expected {
+			t.Fatalf("bad: %q %q", input, actual)
+		}
+	}
 }
@@ -3,31 +3,60 @@ package command
 import (
 	"flag"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
 	"strings"
 
 	"github.com/hashicorp/terraform/terraform"
 )
 
+// EnvOutputToken is the environmental variable that holds the token that
+// requests to the outputs served with -serve must have, if -token isn't
+// given.
+const EnvOutputToken = "TF_OUTPUT_TOKEN"
+
 // OutputCommand is a Command implementation that reads an output
 // from a Terraform state and prints it.
 type OutputCommand struct {
 	Meta
+
+	ShutdownCh <-chan struct{}
 }
 
 func (c *OutputCommand) Run(args []string) int {
-	var statePath string
+	var statePath, listen, token string
+	var serve bool
 
 	args = c.Meta.process(args, false)
 
 	cmdFlags := flag.NewFlagSet("output", flag.ContinueOnError)
 	cmdFlags.StringVar(&statePath, "state", DefaultStateFilename, "path")
+	cmdFlags.BoolVar(&serve, "serve", false, "serve")
+	cmdFlags.StringVar(&listen, "listen", "127.0.0.1:0", "address")
+	cmdFlags.StringVar(&token, "token", os.Getenv(EnvOutputToken), "token")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		return 1
 	}
 
 	args = cmdFlags.Args()
+	if serve {
+		if len(args) > 0 {
+			c.Ui.Error("The output command expects no arguments with -serve.")
+			cmdFlags.Usage()
+			return 1
+		}
+		if token == "" {
+			c.Ui.Error(fmt.Sprintf(
+				"A token is required to serve outputs. Set it with -token or\n"+
+					"%s in the environment.", EnvOutputToken))
+			return 1
+		}
+
+		return c.serve(listen, c.path(statePath), token)
+	}
+
 	if len(args) != 1 || args[0] == "" {
 		c.Ui.Error(
 			"The output command expects exactly one argument with the name\n" +
@@ -37,7 +66,7 @@ func (c *OutputCommand) Run(args []string) int {
 	}
 	name := args[0]
 
-	f, err := os.Open(statePath)
+	f, err := os.Open(c.path(statePath))
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error loading file: %s", err))
 		return 1
@@ -50,6 +79,10 @@ func (c *OutputCommand) Run(args []string) int {
 		return 1
 	}
 
+	// Reading an output doesn't change the state, so a newer version only
+	// gives a warning
+	c.checkVersion("state file "+statePath, state.TFVersion)
+
 	if len(state.Outputs) == 0 {
 		c.Ui.Error(fmt.Sprintf(
 			"The state file has no outputs defined. Define an output\n" +
@@ -71,18 +104,51 @@ func (c *OutputCommand) Run(args []string) int {
 	return 0
 }
 
+// serve serves the outputs of the state file until an interrupt is
+// received.
+func (c *OutputCommand) serve(listen, statePath, token string) int {
+	ln, err := net.Listen("tcp", listen)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error starting server: %s", err))
+		return 1
+	}
+	defer ln.Close()
+
+	go http.Serve(ln, outputHandler(statePath, token))
+	c.Ui.Output(fmt.Sprintf(
+		"Serving outputs on http://%s/outputs\nPress Ctrl-C to stop.",
+		ln.Addr()))
+
+	<-c.ShutdownCh
+	return 0
+}
+
 func (c *OutputCommand) Help() string {
 	helpText := `
 Usage: terraform output [options] NAME
+       terraform output -serve [options]
 
   Reads an output variable from a Terraform state file and prints
   the value.
 
+  With -serve, the outputs are served as JSON over HTTP instead, until
+  interrupted: all of them at /outputs, and each at /outputs/NAME.
+  Requests must have an "Authorization: Bearer TOKEN" header. The state
+  file is read for every request, and sensitive outputs aren't served.
+
 Options:
 
+  -listen=address  Address to serve the outputs on with -serve. Defaults
+                   to a free port on 127.0.0.1.
+
+  -serve           Serve the outputs over HTTP.
+
   -state=path      Path to the state file to read. Defaults to
                    "terraform.tfstate".
 
+  -token=token     The token requests must have with -serve. Defaults to
+                   the TF_OUTPUT_TOKEN environmental variable.
+
 `
 	return strings.TrimSpace(helpText)
 }
@@ -0,0 +1,102 @@
+package command
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// outputHandler serves the outputs of the state file at statePath as
+// JSON to requests with the token: all of them as an object at /outputs,
+// and each as an object with its name and value at /outputs/NAME.
+//
+// The state file is read for every request, so that outputs changed by
+// an apply are served right away. Sensitive outputs are never served.
+func outputHandler(statePath, token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if subtle.ConstantTimeCompare([]byte(auth), []byte("Bearer "+token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		if r.Method != "GET" {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var name string
+		switch {
+		case r.URL.Path == "/outputs":
+		case strings.HasPrefix(r.URL.Path, "/outputs/"):
+			name = strings.TrimPrefix(r.URL.Path, "/outputs/")
+		default:
+			http.NotFound(w, r)
+			return
+		}
+
+		outputs, err := servedOutputs(statePath)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		var result interface{} = outputs
+		if name != "" {
+			v, ok := outputs[name]
+			if !ok {
+				http.Error(w, fmt.Sprintf(
+					"output not found: %s", name), http.StatusNotFound)
+				return
+			}
+
+			result = map[string]string{"name": name, "value": v}
+		}
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	})
+}
+
+// servedOutputs reads the outputs of the state file, without the
+// sensitive ones. A state file that doesn't exist yet has no outputs.
+func servedOutputs(statePath string) (map[string]string, error) {
+	result := make(map[string]string)
+
+	f, err := os.Open(statePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return result, nil
+		}
+
+		return nil, fmt.Errorf("error loading state: %s", err)
+	}
+
+	state, err := terraform.ReadState(f)
+	f.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error reading state: %s", err)
+	}
+
+	for k, v := range state.Outputs {
+		if _, ok := state.SensitiveOutputs[k]; ok {
+			continue
+		}
+
+		result[k] = v
+	}
+
+	return result, nil
+}
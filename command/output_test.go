@@ -1,9 +1,13 @@
 package command
 
 import (
+	"encoding/json"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -206,3 +210,104 @@ func TestOutput_stateDefault(t *testing.T) {
 		t.Fatalf("bad: %#v", actual)
 	}
 }
+
+func TestOutput_serveNoToken(t *testing.T) {
+	defer os.Setenv(EnvOutputToken, os.Getenv(EnvOutputToken))
+	os.Setenv(EnvOutputToken, "")
+
+	ui := new(cli.MockUi)
+	c := &OutputCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{"-serve"}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: \n%s", ui.OutputWriter.String())
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "token is required") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+}
+
+func TestOutputHandler(t *testing.T) {
+	statePath := testStateFile(t, &terraform.State{
+		Outputs: map[string]string{
+			"hostname": "lb.example.com",
+			"password": "secret",
+		},
+		SensitiveOutputs: map[string]struct{}{
+			"password": struct{}{},
+		},
+	})
+	h := outputHandler(statePath, "token")
+
+	get := func(path, token string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("GET", path, nil)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, token := range []string{"", "nope"} {
+		if w := get("/outputs", token); w.Code != 401 {
+			t.Fatalf("%q: bad: %d", token, w.Code)
+		}
+	}
+
+	w := get("/outputs", "token")
+	if w.Code != 200 {
+		t.Fatalf("bad: %d", w.Code)
+	}
+	var all map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &all); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !reflect.DeepEqual(all, map[string]string{"hostname": "lb.example.com"}) {
+		t.Fatalf("bad: %#v", all)
+	}
+
+	w = get("/outputs/hostname", "token")
+	if w.Code != 200 {
+		t.Fatalf("bad: %d", w.Code)
+	}
+	var one map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &one); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if one["name"] != "hostname" || one["value"] != "lb.example.com" {
+		t.Fatalf("bad: %#v", one)
+	}
+
+	for _, path := range []string{"/outputs/password", "/outputs/nope", "/nope"} {
+		if w := get(path, "token"); w.Code != 404 {
+			t.Fatalf("%s: bad: %d", path, w.Code)
+		}
+	}
+
+	// Outputs changed by an apply are served right away
+	f, err := os.Create(statePath)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	err = terraform.WriteState(&terraform.State{
+		Outputs: map[string]string{"hostname": "lb2.example.com"},
+	}, f)
+	f.Close()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if w := get("/outputs/hostname", "token"); !strings.Contains(
+		w.Body.String(), "lb2.example.com") {
+		t.Fatalf("bad: %s", w.Body.String())
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform/terraform"
 )
@@ -13,24 +14,37 @@ import (
 // configuration to an actual infrastructure and shows the differences.
 type PlanCommand struct {
 	Meta
+
+	ShutdownCh <-chan struct{}
 }
 
 func (c *PlanCommand) Run(args []string) int {
-	var destroy, refresh bool
-	var outPath, statePath, backupPath string
+	var destroy, provisioners, refresh, stats bool
+	var outPath, statePath, backupPath, profileDir string
+	var watch time.Duration
+	var forget FlagStringSlice
 
 	args = c.Meta.process(args, true)
 
 	cmdFlags := c.Meta.flagSet("plan")
 	cmdFlags.BoolVar(&destroy, "destroy", false, "destroy")
+	cmdFlags.Var(&forget, "forget", "resource")
 	cmdFlags.BoolVar(&refresh, "refresh", true, "refresh")
 	cmdFlags.StringVar(&outPath, "out", "", "path")
+	cmdFlags.StringVar(&profileDir, "profile", "", "dir")
+	cmdFlags.BoolVar(&provisioners, "provisioners", false, "provisioners")
 	cmdFlags.StringVar(&statePath, "state", DefaultStateFilename, "path")
+	cmdFlags.BoolVar(&stats, "stats", false, "stats")
 	cmdFlags.StringVar(&backupPath, "backup", "", "path")
+	cmdFlags.DurationVar(&watch, "watch", 0, "interval")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		return 1
 	}
+	if watch > 0 && outPath != "" {
+		c.Ui.Error("The -watch and -out flags can't be used together.")
+		return 1
+	}
 
 	var path string
 	args = cmdFlags.Args()
@@ -41,36 +55,74 @@ func (c *PlanCommand) Run(args []string) int {
 		cmdFlags.Usage()
 		return 1
 	} else if len(args) == 1 {
-		path = args[0]
+		path = c.path(args[0])
 	} else {
 		var err error
-		path, err = os.Getwd()
+		path, err = c.wd()
 		if err != nil {
 			c.Ui.Error(fmt.Sprintf("Error getting pwd: %s", err))
 		}
 	}
 
+	// If we're profiling, start it now so the whole run is covered
+	if profileDir != "" {
+		prof, err := startProfile(c.path(profileDir))
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		defer func() {
+			if err := prof.Stop(); err != nil {
+				c.Ui.Error(err.Error())
+			}
+		}()
+
+		c.Meta.extraHooks = append(c.Meta.extraHooks, prof.Hook)
+	}
+
+	// If we're collecting statistics, show them once we're done
+	if stats {
+		defer c.startStats()()
+	}
+
 	// If the default state path doesn't exist, ignore it.
 	if statePath != "" {
-		if _, err := os.Stat(statePath); err != nil {
+		if _, err := os.Stat(c.path(statePath)); err != nil {
 			if os.IsNotExist(err) && statePath == DefaultStateFilename {
 				statePath = ""
 			}
 		}
 	}
 
+	// Paths are relative to the working directory
+	statePath = c.path(statePath)
+	backupPath = c.path(backupPath)
+	outPath = c.path(outPath)
+
 	// If we don't specify a backup path, default to state out with
 	// the extension
 	if backupPath == "" {
 		backupPath = statePath + DefaultBackupExtention
 	}
 
+	planOpts := &terraform.PlanOpts{
+		Destroy:      destroy,
+		Provisioners: provisioners,
+		Forget:       forget,
+	}
+
+	// The state is only refreshed in memory when watching, so there
+	// is nothing to back up.
+	if watch > 0 {
+		return c.watch(path, statePath, refresh, planOpts, watch)
+	}
+
 	ctx, _, err := c.Context(path, statePath)
 	if err != nil {
 		c.Ui.Error(err.Error())
 		return 1
 	}
-	if !validateContext(ctx, c.Ui) {
+	if !c.validateContext(ctx) {
 		return 1
 	}
 
@@ -90,14 +142,27 @@ func (c *PlanCommand) Run(args []string) int {
 		}
 
 		c.Ui.Output("Refreshing Terraform state prior to plan...\n")
-		if _, err := ctx.Refresh(); err != nil {
-			c.Ui.Error(fmt.Sprintf("Error refreshing state: %s", err))
+		var refreshErr error
+		interrupted, _ := c.runInterruptible(ctx, c.ShutdownCh, func() {
+			_, refreshErr = ctx.Refresh()
+		})
+		if interrupted {
+			return 1
+		}
+		if refreshErr != nil {
+			c.Ui.Error(fmt.Sprintf("Error refreshing state: %s", refreshErr))
 			return 1
 		}
 		c.Ui.Output("")
 	}
 
-	plan, err := ctx.Plan(&terraform.PlanOpts{Destroy: destroy})
+	var plan *terraform.Plan
+	interrupted, _ := c.runInterruptible(ctx, c.ShutdownCh, func() {
+		plan, err = ctx.Plan(planOpts)
+	})
+	if interrupted {
+		return 1
+	}
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error running plan: %s", err))
 		return 1
@@ -109,6 +174,19 @@ func (c *PlanCommand) Run(args []string) int {
 				"could not detect any differences between your configuration and\n" +
 				"the real physical resources that exist. As a result, Terraform\n" +
 				"doesn't need to do anything.")
+
+		// The summary is still written, and a plan left by an earlier
+		// plan is removed, so that neither is mistaken for this one.
+		if outPath != "" {
+			log.Printf("[INFO] Removing earlier plan file: %s", outPath)
+			if err := os.Remove(outPath); err != nil && !os.IsNotExist(err) {
+				c.Ui.Error(fmt.Sprintf("Error removing plan file: %s", err))
+				return 1
+			}
+			if !c.writeSummary(outPath, plan, c.warnings) {
+				return 1
+			}
+		}
 		return 0
 	}
 
@@ -135,9 +213,44 @@ func (c *PlanCommand) Run(args []string) int {
 
 	c.Ui.Output(FormatPlan(plan, c.Colorize()))
 
+	warnings := c.warnings
+	if cost := c.showCost(plan); c.overBudget(cost) {
+		msg := fmt.Sprintf(
+			"the estimated monthly cost exceeds the budget of $%.2f, so the\n"+
+				"plan won't be applied unless -override is given.", c.CostBudget)
+		c.Ui.Output(c.Colorize().Color("[yellow]Warning: [reset]" + msg))
+		warnings = append(warnings, msg)
+	}
+	if msg := c.checkDestroyLimit(plan); msg != "" {
+		msg = fmt.Sprintf(
+			"%s, so it won't be\napplied unless -override is given.", msg)
+		c.Ui.Output(c.Colorize().Color("[yellow]Warning: [reset]" + msg))
+		warnings = append(warnings, msg)
+	}
+
+	if outPath != "" {
+		if !c.writeSummary(outPath, plan, warnings) {
+			return 1
+		}
+	}
+
 	return 0
 }
 
+// writeSummary writes the summary of the plan beside the plan file at
+// the given path, returning false if it couldn't be written.
+func (c *PlanCommand) writeSummary(
+	outPath string, plan *terraform.Plan, warnings []string) bool {
+	path := outPath + DefaultSummaryExtension
+	log.Printf("[INFO] Writing plan summary to: %s", path)
+	if err := writePlanSummary(path, summarizePlan(plan, warnings)); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error writing plan summary: %s", err))
+		return false
+	}
+
+	return true
+}
+
 func (c *PlanCommand) Help() string {
 	helpText := `
 Usage: terraform plan [options] [dir]
@@ -155,13 +268,30 @@ Options:
                       modifying. Defaults to the "-state-out" path with
                       ".backup" extension. Set to "-" to disable backup.
 
+  -debug-dump=dir     Write the requests and responses of provider calls to
+                      the given directory, with secrets redacted, to include
+                      in bug reports.
+
   -destroy            If set, a plan will be generated to destroy all resources
                       managed by the given configuration and state.
 
+  -forget=resource    Remove a resource that was deleted outside of Terraform
+                      from the state, instead of creating it again. This
+                      flag can be set multiple times.
+
   -no-color           If specified, output won't contain any color.
 
   -out=path           Write a plan file to the given path. This can be used as
-                      input to the "apply" command.
+                      input to the "apply" command. A summary of the plan
+                      is also written as JSON to the path with the
+                      ".summary.json" extension.
+
+  -profile=dir        Write CPU and heap profiles and a report of how long
+                      each resource took to the given directory.
+
+  -provisioners       Show what the provisioners of resources that will be
+                      created would do, such as the commands they'd run and
+                      the hosts they'd connect to, without running them.
 
   -refresh=true       Update state prior to checking for differences.
 
@@ -169,6 +299,11 @@ Options:
                       up Terraform-managed resources. By default it will
                       use the state "terraform.tfstate" if it exists.
 
+  -stats              Show how many calls were made to each provider and the
+                      slowest resources and operations at the end of the run.
+
+  -strict             Treat warnings about the configuration as errors.
+
   -var 'foo=bar'      Set a variable in the Terraform configuration. This
                       flag can be set multiple times.
 
@@ -176,6 +311,10 @@ Options:
                       a file. If "terraform.tfvars" is present, it will be
                       automatically loaded if this flag is not specified.
 
+  -watch=interval     Refresh and plan again every interval, such as "30s",
+                      until interrupted, showing the plan only when it
+                      changes. This can't be used with -out.
+
 `
 	return strings.TrimSpace(helpText)
 }
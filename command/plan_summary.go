@@ -0,0 +1,95 @@
+package command
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// PlanSummaryVersion is the version of the format of plan summaries. It
+// is only incremented when a change would break readers of the summary.
+const PlanSummaryVersion = 1
+
+// PlanSummary is a small summary of a plan that plan -out writes beside
+// the plan file, so that CI systems can annotate changes with what they
+// do without reading the plan format.
+type PlanSummary struct {
+	Version int `json:"version"`
+
+	// The counts are the same as those shown at the end of apply, so a
+	// resource that is replaced is counted as added and destroyed.
+	Add     int `json:"add"`
+	Change  int `json:"change"`
+	Destroy int `json:"destroy"`
+
+	// Resources are the sorted names of the resources for each action:
+	// "create", "update", "replace" and "destroy".
+	Resources map[string][]string `json:"resources"`
+
+	// Warnings are the warnings shown about the configuration and the
+	// plan.
+	Warnings []string `json:"warnings"`
+}
+
+// summarizePlan returns the summary of the plan with the warnings.
+func summarizePlan(p *terraform.Plan, warnings []string) *PlanSummary {
+	result := &PlanSummary{
+		Version: PlanSummaryVersion,
+		Resources: map[string][]string{
+			"create":  []string{},
+			"update":  []string{},
+			"replace": []string{},
+			"destroy": []string{},
+		},
+		Warnings: warnings,
+	}
+	if result.Warnings == nil {
+		result.Warnings = []string{}
+	}
+	if p.Diff == nil {
+		return result
+	}
+
+	for name, rd := range p.Diff.Resources {
+		if rd.Empty() {
+			continue
+		}
+
+		var s *terraform.ResourceState
+		if p.State != nil {
+			s = p.State.Resources[name]
+		}
+
+		action := auditAction(s, rd)
+		result.Resources[action] = append(result.Resources[action], name)
+
+		switch action {
+		case "create":
+			result.Add++
+		case "replace":
+			result.Add++
+			result.Destroy++
+		case "destroy":
+			result.Destroy++
+		default:
+			result.Change++
+		}
+	}
+	for _, names := range result.Resources {
+		sort.Strings(names)
+	}
+
+	return result
+}
+
+// writePlanSummary writes the summary to the given path.
+func writePlanSummary(path string, s *PlanSummary) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
@@ -0,0 +1,105 @@
+package command
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestSummarizePlan(t *testing.T) {
+	p := &terraform.Plan{
+		Diff: &terraform.Diff{
+			Resources: map[string]*terraform.ResourceDiff{
+				"aws_instance.new": &terraform.ResourceDiff{
+					Attributes: map[string]*terraform.ResourceAttrDiff{
+						"ami": &terraform.ResourceAttrDiff{New: "foo"},
+					},
+				},
+				"aws_instance.another": &terraform.ResourceDiff{
+					Attributes: map[string]*terraform.ResourceAttrDiff{
+						"ami": &terraform.ResourceAttrDiff{New: "foo"},
+					},
+				},
+				"aws_instance.change": &terraform.ResourceDiff{
+					Attributes: map[string]*terraform.ResourceAttrDiff{
+						"ami": &terraform.ResourceAttrDiff{Old: "foo", New: "bar"},
+					},
+				},
+				"aws_instance.replace": &terraform.ResourceDiff{
+					Destroy: true,
+					Attributes: map[string]*terraform.ResourceAttrDiff{
+						"ami": &terraform.ResourceAttrDiff{
+							Old:         "foo",
+							New:         "bar",
+							RequiresNew: true,
+						},
+					},
+				},
+			},
+		},
+		State: &terraform.State{
+			Resources: map[string]*terraform.ResourceState{
+				"aws_instance.change":  &terraform.ResourceState{ID: "a"},
+				"aws_instance.replace": &terraform.ResourceState{ID: "b"},
+			},
+		},
+	}
+
+	actual := summarizePlan(p, []string{"foo"})
+	expected := &PlanSummary{
+		Version: PlanSummaryVersion,
+		Add:     3,
+		Change:  1,
+		Destroy: 1,
+		Resources: map[string][]string{
+			"create":  []string{"aws_instance.another", "aws_instance.new"},
+			"update":  []string{"aws_instance.change"},
+			"replace": []string{"aws_instance.replace"},
+			"destroy": []string{},
+		},
+		Warnings: []string{"foo"},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestWritePlanSummary_empty(t *testing.T) {
+	path := filepath.Join(testTempDir(t), "plan.summary.json")
+	s := summarizePlan(&terraform.Plan{Diff: new(terraform.Diff)}, nil)
+	if err := writePlanSummary(path, s); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Every key is present even when there's nothing in it, so readers
+	// don't have to check for them.
+	var actual map[string]interface{}
+	if err := json.Unmarshal(data, &actual); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := map[string]interface{}{
+		"version": float64(PlanSummaryVersion),
+		"add":     float64(0),
+		"change":  float64(0),
+		"destroy": float64(0),
+		"resources": map[string]interface{}{
+			"create":  []interface{}{},
+			"update":  []interface{}{},
+			"replace": []interface{}{},
+			"destroy": []interface{}{},
+		},
+		"warnings": []interface{}{},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %s", data)
+	}
+}
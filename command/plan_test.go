@@ -1,11 +1,15 @@
 package command
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/mitchellh/cli"
@@ -36,6 +40,77 @@ func TestPlan(t *testing.T) {
 	}
 }
 
+func TestPlan_rules(t *testing.T) {
+	rule, err := NewRule("owner", "", "", []string{"Owner"})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &PlanCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+			Rules:       []*Rule{rule},
+		},
+	}
+
+	args := []string{testFixturePath("plan")}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if p.DiffCalled {
+		t.Fatal("diff should not be called")
+	}
+	if !strings.Contains(ui.OutputWriter.String(), `missing required tag "Owner"`) {
+		t.Fatalf("bad: %s", ui.OutputWriter.String())
+	}
+}
+
+func TestPlan_chdir(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	if err := copyConfig(testFixturePath("plan"), td); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	err := ioutil.WriteFile(
+		filepath.Join(td, DefaultVarsFilename), []byte(`foo = "bar"`), 0644)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &PlanCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+			WorkingDir:  td,
+		},
+	}
+
+	args := []string{"-out", "foo.tfplan"}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	f, err := os.Open(filepath.Join(td, "foo.tfplan"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer f.Close()
+
+	plan, err := terraform.ReadPlan(f)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if plan.Vars["foo"] != "bar" {
+		t.Fatalf("bad: %#v", plan.Vars)
+	}
+}
+
 func TestPlan_destroy(t *testing.T) {
 	originalState := &terraform.State{
 		Resources: map[string]*terraform.ResourceState{
@@ -125,6 +200,153 @@ func TestPlan_noState(t *testing.T) {
 	}
 }
 
+func TestPlan_cost(t *testing.T) {
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &PlanCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+			CostEstimators: []CostEstimator{
+				&StaticCostEstimator{
+					Prices: map[string]*CostPrice{
+						"test_instance": &CostPrice{Fixed: 50},
+					},
+				},
+			},
+			CostBudget: 10,
+		},
+	}
+
+	args := []string{testFixturePath("plan")}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	output := ui.OutputWriter.String()
+	if !strings.Contains(output, "Estimated monthly cost: $0.00 -> $50.00 (+$50.00)") {
+		t.Fatalf("bad: %s", output)
+	}
+	if !strings.Contains(output, "exceeds the budget of $10.00") {
+		t.Fatalf("bad: %s", output)
+	}
+}
+
+func TestPlan_destroyLimit(t *testing.T) {
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &PlanCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+			MaxDestroy:  1,
+		},
+	}
+
+	args := []string{
+		"-destroy",
+		"-state", testStateFile(t, &terraform.State{
+			Resources: map[string]*terraform.ResourceState{
+				"test_instance.foo": &terraform.ResourceState{
+					ID:   "foo",
+					Type: "test_instance",
+				},
+				"test_instance.bar": &terraform.ResourceState{
+					ID:   "bar",
+					Type: "test_instance",
+				},
+			},
+		}),
+		testFixturePath("plan"),
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	output := ui.OutputWriter.String()
+	if !strings.Contains(output, "the plan destroys 2 resources, more than the limit of 1") {
+		t.Fatalf("bad: %s", output)
+	}
+}
+
+func TestPlan_missing(t *testing.T) {
+	statePath := testStateFile(t, &terraform.State{
+		Resources: map[string]*terraform.ResourceState{
+			"test_instance.foo": &terraform.ResourceState{
+				ID:   "bar",
+				Type: "test_instance",
+			},
+		},
+	})
+
+	p := testProvider()
+	p.RefreshFn = nil
+	p.RefreshReturn = nil
+
+	ui := new(cli.MockUi)
+	c := &PlanCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		testFixturePath("plan"),
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	output := ui.OutputWriter.String()
+	expected := "+ test_instance.foo (exists in state but not in provider, will be recreated)"
+	if !strings.Contains(output, expected) {
+		t.Fatalf("bad: %s", output)
+	}
+}
+
+func TestPlan_missingForget(t *testing.T) {
+	statePath := testStateFile(t, &terraform.State{
+		Resources: map[string]*terraform.ResourceState{
+			"test_instance.foo": &terraform.ResourceState{
+				ID:   "bar",
+				Type: "test_instance",
+			},
+		},
+	})
+
+	p := testProvider()
+	p.RefreshFn = nil
+	p.RefreshReturn = nil
+
+	ui := new(cli.MockUi)
+	c := &PlanCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"-forget", "test_instance.foo",
+		testFixturePath("plan"),
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if p.DiffCalled {
+		t.Fatal("diff should not be called")
+	}
+
+	output := ui.OutputWriter.String()
+	expected := "- test_instance.foo (exists in state but not in provider, will be removed from state)"
+	if !strings.Contains(output, expected) {
+		t.Fatalf("bad: %s", output)
+	}
+}
+
 func TestPlan_outPath(t *testing.T) {
 	tf, err := ioutil.TempFile("", "tf")
 	if err != nil {
@@ -163,6 +385,150 @@ func TestPlan_outPath(t *testing.T) {
 	if _, err := terraform.ReadPlan(f); err != nil {
 		t.Fatalf("err: %s", err)
 	}
+
+	summary := testPlanSummary(t, outPath)
+	if summary.Add != 1 || summary.Change != 0 || summary.Destroy != 1 {
+		t.Fatalf("bad: %#v", summary)
+	}
+	expected := []string{"test_instance.foo"}
+	if !reflect.DeepEqual(summary.Resources["replace"], expected) {
+		t.Fatalf("bad: %#v", summary.Resources)
+	}
+}
+
+func TestPlan_outPathNoChanges(t *testing.T) {
+	outPath := filepath.Join(testTempDir(t), "plan")
+
+	// A summary from an earlier plan is replaced, and its plan removed
+	summaryPath := outPath + DefaultSummaryExtension
+	if err := ioutil.WriteFile(summaryPath, []byte("{}"), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := ioutil.WriteFile(outPath, []byte("old"), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	statePath := testStateFile(t, &terraform.State{
+		Resources: map[string]*terraform.ResourceState{
+			"test_instance.foo": &terraform.ResourceState{
+				ID:   "bar",
+				Type: "test_instance",
+			},
+		},
+	})
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &PlanCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-out", outPath,
+		"-state", statePath,
+		testFixturePath("plan"),
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if !strings.Contains(ui.OutputWriter.String(), "No changes") {
+		t.Fatalf("bad: %s", ui.OutputWriter.String())
+	}
+	if _, err := os.Stat(outPath); !os.IsNotExist(err) {
+		t.Fatalf("the earlier plan should be removed: %s", err)
+	}
+
+	summary := testPlanSummary(t, outPath)
+	if summary.Version != PlanSummaryVersion {
+		t.Fatalf("bad: %#v", summary)
+	}
+	if summary.Add != 0 || summary.Change != 0 || summary.Destroy != 0 {
+		t.Fatalf("bad: %#v", summary)
+	}
+}
+
+func TestPlan_outPathWarnings(t *testing.T) {
+	outPath := filepath.Join(testTempDir(t), "plan")
+
+	p := testProvider()
+	p.DiffReturn = &terraform.ResourceDiff{
+		Attributes: map[string]*terraform.ResourceAttrDiff{
+			"ami": &terraform.ResourceAttrDiff{New: "bar"},
+		},
+	}
+	ui := new(cli.MockUi)
+	c := &PlanCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-out", outPath,
+		testFixturePath("plan-warnings"),
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	summary := testPlanSummary(t, outPath)
+	if len(summary.Warnings) != 1 ||
+		!strings.Contains(summary.Warnings[0], "'unused': declared but never used") {
+		t.Fatalf("bad: %#v", summary.Warnings)
+	}
+}
+
+func testPlanSummary(t *testing.T, outPath string) *PlanSummary {
+	data, err := ioutil.ReadFile(outPath + DefaultSummaryExtension)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var result PlanSummary
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("err: %s\n\n%s", err, data)
+	}
+
+	return &result
+}
+
+func TestPlan_profile(t *testing.T) {
+	profileDir := testTempDir(t)
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &PlanCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-profile", profileDir,
+		testFixturePath("plan"),
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	for _, n := range []string{profileCPUFile, profileHeapFile} {
+		if _, err := os.Stat(filepath.Join(profileDir, n)); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	report, err := ioutil.ReadFile(filepath.Join(profileDir, profileTimingsFile))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !strings.Contains(string(report), "test_instance.foo") {
+		t.Fatalf("bad: %s", report)
+	}
 }
 
 func TestPlan_refresh(t *testing.T) {
@@ -188,6 +554,108 @@ func TestPlan_refresh(t *testing.T) {
 	}
 }
 
+func TestPlan_watch(t *testing.T) {
+	statePath := testStateFile(t, &terraform.State{
+		Resources: map[string]*terraform.ResourceState{
+			"test_instance.foo": &terraform.ResourceState{
+				ID:   "bar",
+				Type: "test_instance",
+			},
+		},
+	})
+	defer os.Remove(statePath)
+
+	// The plan changes from the third diff on
+	var l sync.Mutex
+	var diffs int
+	p := testProvider()
+	p.DiffFn = func(
+		*terraform.ResourceState,
+		*terraform.ResourceConfig) (*terraform.ResourceDiff, error) {
+		l.Lock()
+		defer l.Unlock()
+		diffs++
+
+		ami := "foo"
+		if diffs > 2 {
+			ami = "bar"
+		}
+		return &terraform.ResourceDiff{
+			Attributes: map[string]*terraform.ResourceAttrDiff{
+				"ami": &terraform.ResourceAttrDiff{New: ami},
+			},
+		}, nil
+	}
+
+	shutdownCh := make(chan struct{})
+	ui := new(cli.MockUi)
+	c := &PlanCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+		ShutdownCh: shutdownCh,
+	}
+
+	args := []string{
+		"-state", statePath,
+		"-watch", "10ms",
+		testFixturePath("plan"),
+	}
+	codeCh := make(chan int)
+	go func() {
+		codeCh <- c.Run(args)
+	}()
+
+	for start := time.Now(); ; time.Sleep(10 * time.Millisecond) {
+		l.Lock()
+		n := diffs
+		l.Unlock()
+		if n >= 5 {
+			break
+		}
+		if time.Now().Sub(start) > 5*time.Second {
+			t.Fatalf("not enough diffs: %d", n)
+		}
+	}
+
+	shutdownCh <- struct{}{}
+	if code := <-codeCh; code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	output := ui.OutputWriter.String()
+	if n := strings.Count(output, "The plan changed"); n != 2 {
+		t.Fatalf("bad: %d\n\n%s", n, output)
+	}
+	if strings.Contains(output, "Refreshing state") {
+		t.Fatalf("bad: %s", output)
+	}
+}
+
+func TestPlan_watchOut(t *testing.T) {
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &PlanCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-watch", "1s",
+		"-out", "foo.tfplan",
+		testFixturePath("plan"),
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d", code)
+	}
+	if p.DiffCalled {
+		t.Fatal("diff should not be called")
+	}
+}
+
 func TestPlan_state(t *testing.T) {
 	// Write out some prior state
 	tf, err := ioutil.TempFile("", "tf")
@@ -297,6 +765,235 @@ func TestPlan_stateDefault(t *testing.T) {
 	}
 }
 
+func TestPlan_debugDump(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &PlanCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-debug-dump", td,
+		testFixturePath("plan"),
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	if !p.DiffCalled {
+		t.Fatal("diff should be called")
+	}
+
+	path := filepath.Join(td, "0001-test-Diff.json")
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !strings.Contains(string(data), "network_interface") {
+		t.Fatalf("bad: %s", data)
+	}
+}
+
+func TestPlan_provisioners(t *testing.T) {
+	p := testProvider()
+	pr := new(terraform.MockResourceProvisioner)
+	pr.DescribeReturn = []string{"run locally: echo hello"}
+
+	ui := new(cli.MockUi)
+	c := &PlanCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+	c.ContextOpts.Provisioners = map[string]terraform.ResourceProvisionerFactory{
+		"shell": func() (terraform.ResourceProvisioner, error) {
+			return pr, nil
+		},
+	}
+
+	args := []string{
+		"-provisioners",
+		testFixturePath("plan-provisioners"),
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	if pr.ApplyCalled {
+		t.Fatal("apply should not be called")
+	}
+
+	output := ui.OutputWriter.String()
+	if !strings.Contains(output, "provisioner \"shell\":\n      run locally: echo hello") {
+		t.Fatalf("bad: %s", output)
+	}
+}
+
+func TestPlan_sensitive(t *testing.T) {
+	p := testProvider()
+	p.DiffFn = func(
+		s *terraform.ResourceState,
+		c *terraform.ResourceConfig) (*terraform.ResourceDiff, error) {
+		return &terraform.ResourceDiff{
+			Attributes: map[string]*terraform.ResourceAttrDiff{
+				"ami": &terraform.ResourceAttrDiff{
+					New: c.Config["ami"].(string),
+				},
+			},
+		}, nil
+	}
+
+	ui := new(cli.MockUi)
+	c := &PlanCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-var", "password=hunter2",
+		testFixturePath("plan-sensitive"),
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	output := ui.OutputWriter.String()
+	if strings.Contains(output, "hunter2") {
+		t.Fatalf("sensitive value in output: %s", output)
+	}
+	if !strings.Contains(output, `"" => "<sensitive>"`) {
+		t.Fatalf("bad: %s", output)
+	}
+}
+
+func TestPlan_secret(t *testing.T) {
+	p := testProvider()
+	p.DiffFn = func(
+		s *terraform.ResourceState,
+		c *terraform.ResourceConfig) (*terraform.ResourceDiff, error) {
+		return &terraform.ResourceDiff{
+			Attributes: map[string]*terraform.ResourceAttrDiff{
+				"ami": &terraform.ResourceAttrDiff{
+					New: c.Config["ami"].(string),
+				},
+			},
+		}, nil
+	}
+
+	ctxOpts := testCtxConfig(p)
+	ctxOpts.Secrets = map[string]terraform.SecretBackend{
+		"mock": &terraform.MockSecretBackend{
+			Values: map[string]string{"ami": "hunter2"},
+		},
+	}
+
+	ui := new(cli.MockUi)
+	c := &PlanCommand{
+		Meta: Meta{
+			ContextOpts: ctxOpts,
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		testFixturePath("plan-secret"),
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	output := ui.OutputWriter.String()
+	if strings.Contains(output, "hunter2") {
+		t.Fatalf("secret in output: %s", output)
+	}
+	if !strings.Contains(output, `"" => "<sensitive>"`) {
+		t.Fatalf("bad: %s", output)
+	}
+}
+
+func TestPlan_stats(t *testing.T) {
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &PlanCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-stats",
+		testFixturePath("plan"),
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	output := ui.OutputWriter.String()
+	for _, s := range []string{
+		"Run statistics",
+		"(diff: 1)",
+		"test_instance.foo",
+	} {
+		if !strings.Contains(output, s) {
+			t.Fatalf("missing %q: %s", s, output)
+		}
+	}
+}
+
+func TestPlan_warnings(t *testing.T) {
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &PlanCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{testFixturePath("plan-warnings")}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	output := ui.OutputWriter.String()
+	if !strings.Contains(output, "Variable 'unused': declared but never used") {
+		t.Fatalf("bad: %s", output)
+	}
+}
+
+func TestPlan_warningsStrict(t *testing.T) {
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &PlanCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-strict",
+		testFixturePath("plan-warnings"),
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.OutputWriter.String())
+	}
+
+	if p.DiffCalled {
+		t.Fatal("diff should not be called")
+	}
+}
+
 func TestPlan_vars(t *testing.T) {
 	p := testProvider()
 	ui := new(cli.MockUi)
@@ -488,6 +1185,7 @@ func TestPlan_backup(t *testing.T) {
 		t.Fatalf("err: %s", err)
 	}
 
+	originalState.TFVersion = terraform.VersionString()
 	if !reflect.DeepEqual(backupState, originalState) {
 		t.Fatalf("bad: %#v", backupState)
 	}
@@ -551,3 +1249,50 @@ func TestPlan_disableBackup(t *testing.T) {
 const planVarFile = `
 foo = "bar"
 `
+
+func TestPlan_newerState(t *testing.T) {
+	statePath := testNewerStateFile(t)
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &PlanCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	// Plans don't change the state, so they only warn
+	args := []string{
+		"-state", statePath,
+		testFixturePath("plan"),
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "v99.0.0") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+}
+
+func TestPlan_requiredVersion(t *testing.T) {
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &PlanCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{testFixturePath("plan-required-version")}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d", code)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "requires Terraform >= 99.0.0") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+	if p.DiffCalled {
+		t.Fatal("diff should not be called")
+	}
+}
@@ -0,0 +1,109 @@
+package command
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// watch refreshes and plans every interval until interrupted, showing
+// the plan only when it's different from the last one. The state file
+// is read again each time, so that applies made in the meantime are
+// taken into account. The plugins started for each plan are stopped
+// once it's done.
+func (c *PlanCommand) watch(
+	path, statePath string,
+	refresh bool,
+	opts *terraform.PlanOpts,
+	interval time.Duration) int {
+	// The progress of every refresh would bury the plans
+	c.Meta.quiet = true
+
+	c.Ui.Output(fmt.Sprintf(
+		"Watching for changes every %s. Press Ctrl-C to stop.\n", interval))
+
+	var last string
+	for i := 0; ; i++ {
+		ctx, _, err := c.Context(path, statePath)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		// Problems with the configuration won't fix themselves, so only
+		// check it the first time.
+		if i == 0 && !c.validateContext(ctx) {
+			return 1
+		}
+
+		var plan *terraform.Plan
+		doneCh := make(chan struct{})
+		go func() {
+			defer close(doneCh)
+			plan, err = c.watchPlan(ctx, refresh, opts)
+		}()
+
+		select {
+		case <-c.ShutdownCh:
+			ctx.Stop()
+			<-doneCh
+			c.closePlugins()
+			return 0
+		case <-doneCh:
+		}
+		c.closePlugins()
+
+		// Errors such as failing to reach a provider's API are shown,
+		// but watching continues since they may be temporary.
+		var current string
+		if err != nil {
+			current = err.Error()
+		} else {
+			current = plan.Diff.String()
+		}
+
+		if current != last {
+			last = current
+
+			now := time.Now().Format("15:04:05")
+			switch {
+			case err != nil:
+				c.Ui.Error(fmt.Sprintf("[%s] %s", now, err))
+			case plan.Diff.Empty():
+				c.Ui.Output(c.Colorize().Color(fmt.Sprintf(
+					"[reset][bold]%s: No changes. Infrastructure is up-to-date.\n",
+					now)))
+			default:
+				c.Ui.Output(c.Colorize().Color(fmt.Sprintf(
+					"[reset][bold]%s: The plan changed:\n", now)))
+				c.Ui.Output(FormatPlan(plan, c.Colorize()))
+			}
+		}
+
+		select {
+		case <-c.ShutdownCh:
+			return 0
+		case <-time.After(interval):
+		}
+	}
+}
+
+// watchPlan refreshes the state in memory and plans once.
+func (c *PlanCommand) watchPlan(
+	ctx *terraform.Context,
+	refresh bool,
+	opts *terraform.PlanOpts) (*terraform.Plan, error) {
+	if refresh {
+		if _, err := ctx.Refresh(); err != nil {
+			return nil, fmt.Errorf("Error refreshing state: %s", err)
+		}
+	}
+
+	plan, err := ctx.Plan(opts)
+	if err != nil {
+		return nil, fmt.Errorf("Error running plan: %s", err)
+	}
+
+	return plan, nil
+}
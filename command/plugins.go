@@ -0,0 +1,96 @@
+package command
+
+import (
+	"io"
+	"log"
+	"sync"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// pluginSet records the providers and provisioners that were created for
+// contexts, so that their plugins can be stopped before the command
+// exits. Commands that create contexts over and over, such as plan
+// -watch, would otherwise start new plugins every time and leave the old
+// ones running until Terraform exits.
+//
+// Providers and provisioners are closed if they implement io.Closer,
+// which the ones served by plugins do.
+type pluginSet struct {
+	l       sync.Mutex
+	closers []io.Closer
+}
+
+// Providers returns the given provider factories wrapped so that the
+// providers they create are recorded.
+func (s *pluginSet) Providers(
+	ps map[string]terraform.ResourceProviderFactory) map[string]terraform.ResourceProviderFactory {
+	result := make(map[string]terraform.ResourceProviderFactory)
+	for k, f := range ps {
+		factory := f
+		result[k] = func() (terraform.ResourceProvider, error) {
+			p, err := factory()
+			if err == nil {
+				s.add(p)
+			}
+
+			return p, err
+		}
+	}
+
+	return result
+}
+
+// Provisioners returns the given provisioner factories wrapped so that
+// the provisioners they create are recorded.
+func (s *pluginSet) Provisioners(
+	ps map[string]terraform.ResourceProvisionerFactory) map[string]terraform.ResourceProvisionerFactory {
+	result := make(map[string]terraform.ResourceProvisionerFactory)
+	for k, f := range ps {
+		factory := f
+		result[k] = func() (terraform.ResourceProvisioner, error) {
+			p, err := factory()
+			if err == nil {
+				s.add(p)
+			}
+
+			return p, err
+		}
+	}
+
+	return result
+}
+
+func (s *pluginSet) add(v interface{}) {
+	c, ok := v.(io.Closer)
+	if !ok {
+		return
+	}
+
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.closers = append(s.closers, c)
+}
+
+// Close closes everything that was recorded since the last call.
+func (s *pluginSet) Close() {
+	s.l.Lock()
+	closers := s.closers
+	s.closers = nil
+	s.l.Unlock()
+
+	for _, c := range closers {
+		if err := c.Close(); err != nil {
+			log.Printf("[WARN] Error stopping plugin: %s", err)
+		}
+	}
+}
+
+// closePlugins stops the plugins of the providers and provisioners that
+// were created for the contexts of the command so far. The contexts
+// can't be used afterwards.
+func (m *Meta) closePlugins() {
+	if m.plugins != nil {
+		m.plugins.Close()
+	}
+}
@@ -0,0 +1,117 @@
+package command
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+)
+
+// closeProvider is a provider that counts how many times it's created
+// and closed, like the providers served by plugins.
+type closeProvider struct {
+	*terraform.MockResourceProvider
+
+	l       *sync.Mutex
+	created *int
+	closed  *int
+}
+
+func (p *closeProvider) Close() error {
+	p.l.Lock()
+	defer p.l.Unlock()
+	*p.closed++
+	return nil
+}
+
+func testCloseProviders(p *terraform.MockResourceProvider) (
+	map[string]terraform.ResourceProviderFactory, func() (int, int)) {
+	var l sync.Mutex
+	var created, closed int
+	ps := map[string]terraform.ResourceProviderFactory{
+		"test": func() (terraform.ResourceProvider, error) {
+			l.Lock()
+			defer l.Unlock()
+			created++
+			return &closeProvider{
+				MockResourceProvider: p,
+				l:                    &l,
+				created:              &created,
+				closed:               &closed,
+			}, nil
+		},
+	}
+
+	return ps, func() (int, int) {
+		l.Lock()
+		defer l.Unlock()
+		return created, closed
+	}
+}
+
+func TestPluginSet(t *testing.T) {
+	ps, counts := testCloseProviders(testProvider())
+
+	s := new(pluginSet)
+	ps = s.Providers(ps)
+	for i := 0; i < 2; i++ {
+		if _, err := ps["test"](); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	s.Close()
+	if created, closed := counts(); created != 2 || closed != 2 {
+		t.Fatalf("bad: %d %d", created, closed)
+	}
+
+	// Closing again doesn't close them twice
+	s.Close()
+	if _, closed := counts(); closed != 2 {
+		t.Fatalf("bad: %d", closed)
+	}
+}
+
+func TestPlan_watchClosesPlugins(t *testing.T) {
+	ps, counts := testCloseProviders(testProvider())
+
+	shutdownCh := make(chan struct{})
+	ui := new(cli.MockUi)
+	c := &PlanCommand{
+		Meta: Meta{
+			ContextOpts: &terraform.ContextOpts{Providers: ps},
+			Ui:          ui,
+		},
+		ShutdownCh: shutdownCh,
+	}
+
+	args := []string{
+		"-watch", "10ms",
+		testFixturePath("plan"),
+	}
+	codeCh := make(chan int)
+	go func() {
+		codeCh <- c.Run(args)
+	}()
+
+	// Wait for a few plans, each of which creates providers
+	for start := time.Now(); ; time.Sleep(10 * time.Millisecond) {
+		if created, _ := counts(); created >= 6 {
+			break
+		}
+		if time.Now().Sub(start) > 5*time.Second {
+			t.Fatal("not enough plans")
+		}
+	}
+
+	shutdownCh <- struct{}{}
+	if code := <-codeCh; code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	if created, closed := counts(); created != closed {
+		t.Fatalf("bad: created %d, closed %d", created, closed)
+	}
+}
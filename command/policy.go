@@ -0,0 +1,172 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// Policy is an executable that checks a plan before it is applied, such
+// as to forbid security groups that are open to the world. Policies are
+// configured with "policy" blocks in the CLI configuration.
+//
+// The executable is given the PolicyPlan as JSON on stdin, and must
+// write a PolicyResult as JSON to stdout.
+type Policy struct {
+	Name    string
+	Command string
+}
+
+// PolicyPlan is the plan that policies check.
+type PolicyPlan struct {
+	Resources []*PolicyResource `json:"resources"`
+}
+
+// PolicyResource is what the plan does to a single resource.
+type PolicyResource struct {
+	Resource   string                      `json:"resource"`
+	Type       string                      `json:"type"`
+	Action     string                      `json:"action"`
+	Attributes map[string]*PolicyAttribute `json:"attributes,omitempty"`
+}
+
+// PolicyAttribute is the change of a single attribute of a resource.
+type PolicyAttribute struct {
+	Old         string `json:"old"`
+	New         string `json:"new"`
+	Computed    bool   `json:"computed,omitempty"`
+	RequiresNew bool   `json:"requires_new,omitempty"`
+}
+
+// PolicyResult is the verdict of a policy on a plan.
+type PolicyResult struct {
+	// Verdict is "pass", "warn" or "fail". The apply is refused if
+	// any policy fails.
+	Verdict string `json:"verdict"`
+
+	// Messages explain the verdict.
+	Messages []string `json:"messages,omitempty"`
+}
+
+// Check runs the policy on the plan. An error is returned if the policy
+// couldn't be run, or if it didn't return a valid result.
+func (p *Policy) Check(plan *PolicyPlan) (*PolicyResult, error) {
+	data, err := json.Marshal(plan)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(p.Command)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf(
+			"%s: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var result PolicyResult
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		return nil, fmt.Errorf("invalid result: %s", err)
+	}
+
+	switch result.Verdict {
+	case "pass", "warn", "fail":
+	default:
+		return nil, fmt.Errorf("unknown verdict %q", result.Verdict)
+	}
+
+	return &result, nil
+}
+
+// policyPlan returns the plan for policies to check, with the values of
+// sensitive variables redacted.
+func policyPlan(p *terraform.Plan, r *redactor) *PolicyPlan {
+	result := &PolicyPlan{Resources: make([]*PolicyResource, 0)}
+	if p.Diff == nil {
+		return result
+	}
+
+	names := make([]string, 0, len(p.Diff.Resources))
+	for name, rd := range p.Diff.Resources {
+		if !rd.Empty() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		rd := p.Diff.Resources[name]
+
+		var s *terraform.ResourceState
+		if p.State != nil {
+			s = p.State.Resources[name]
+		}
+
+		pr := &PolicyResource{
+			Resource: name,
+			Type:     strings.SplitN(name, ".", 2)[0],
+			Action:   auditAction(s, rd),
+		}
+		if len(rd.Attributes) > 0 {
+			pr.Attributes = make(map[string]*PolicyAttribute)
+			for k, ad := range rd.Attributes {
+				pr.Attributes[k] = &PolicyAttribute{
+					Old:         r.Redact(ad.Old),
+					New:         r.Redact(ad.New),
+					Computed:    ad.NewComputed,
+					RequiresNew: ad.RequiresNew,
+				}
+			}
+		}
+
+		result.Resources = append(result.Resources, pr)
+	}
+
+	return result
+}
+
+// checkPolicies runs the policies on the plan and shows their verdicts.
+// It returns the messages of the policies that failed, including those
+// that couldn't be run, which should stop the plan from being applied.
+func (m *Meta) checkPolicies(p *terraform.Plan) []string {
+	if len(m.Policies) == 0 {
+		return nil
+	}
+
+	plan := policyPlan(p, m.redactor)
+
+	var failures []string
+	for _, policy := range m.Policies {
+		result, err := policy.Check(plan)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf(
+				"%s: error checking policy: %s", policy.Name, err))
+			continue
+		}
+
+		switch result.Verdict {
+		case "fail":
+			if len(result.Messages) == 0 {
+				result.Messages = []string{"failed"}
+			}
+			for _, msg := range result.Messages {
+				failures = append(failures,
+					fmt.Sprintf("%s: %s", policy.Name, msg))
+			}
+		case "warn":
+			for _, msg := range result.Messages {
+				m.Ui.Output(m.Colorize().Color(fmt.Sprintf(
+					"[yellow]Policy warning: [reset]%s: %s", policy.Name, msg)))
+			}
+		}
+	}
+
+	return failures
+}
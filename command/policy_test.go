@@ -0,0 +1,161 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+)
+
+func TestPolicyCheck(t *testing.T) {
+	p := testPolicy(t, "tags", `
+grep -q '"resource":"aws_instance.foo"' || exit 1
+echo '{"verdict": "fail", "messages": ["aws_instance.foo has no tags"]}'
+`)
+	defer os.Remove(p.Command)
+
+	plan := &PolicyPlan{
+		Resources: []*PolicyResource{
+			&PolicyResource{Resource: "aws_instance.foo"},
+		},
+	}
+	result, err := p.Check(plan)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := &PolicyResult{
+		Verdict:  "fail",
+		Messages: []string{"aws_instance.foo has no tags"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("bad: %#v", result)
+	}
+}
+
+func TestPolicyCheck_error(t *testing.T) {
+	cases := []string{
+		"echo broken >&2; exit 1",
+		"echo 'not json'",
+		`echo '{"verdict": "maybe"}'`,
+	}
+
+	for _, tc := range cases {
+		p := testPolicy(t, "broken", tc)
+		_, err := p.Check(new(PolicyPlan))
+		os.Remove(p.Command)
+		if err == nil {
+			t.Fatalf("should error: %s", tc)
+		}
+	}
+}
+
+func TestPolicyPlan(t *testing.T) {
+	r := new(redactor)
+	r.Add("hunter2")
+
+	plan := &terraform.Plan{
+		Diff: &terraform.Diff{
+			Resources: map[string]*terraform.ResourceDiff{
+				"aws_instance.foo": &terraform.ResourceDiff{
+					Attributes: map[string]*terraform.ResourceAttrDiff{
+						"password": &terraform.ResourceAttrDiff{
+							Old: "",
+							New: "hunter2",
+						},
+						"ip": &terraform.ResourceAttrDiff{
+							NewComputed: true,
+						},
+					},
+				},
+				"aws_instance.bar": &terraform.ResourceDiff{
+					Destroy: true,
+				},
+				"aws_instance.baz": &terraform.ResourceDiff{},
+			},
+		},
+		State: &terraform.State{
+			Resources: map[string]*terraform.ResourceState{
+				"aws_instance.bar": &terraform.ResourceState{ID: "bar"},
+			},
+		},
+	}
+
+	actual := policyPlan(plan, r)
+	expected := &PolicyPlan{
+		Resources: []*PolicyResource{
+			&PolicyResource{
+				Resource: "aws_instance.bar",
+				Type:     "aws_instance",
+				Action:   "destroy",
+			},
+			&PolicyResource{
+				Resource: "aws_instance.foo",
+				Type:     "aws_instance",
+				Action:   "create",
+				Attributes: map[string]*PolicyAttribute{
+					"password": &PolicyAttribute{New: sensitiveRedacted},
+					"ip":       &PolicyAttribute{Computed: true},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestMetaCheckPolicies(t *testing.T) {
+	pass := testPolicy(t, "pass", `echo '{"verdict": "pass"}'`)
+	defer os.Remove(pass.Command)
+	warn := testPolicy(t, "warn", `
+echo '{"verdict": "warn", "messages": ["no description"]}'`)
+	defer os.Remove(warn.Command)
+	fail := testPolicy(t, "fail", `echo '{"verdict": "fail"}'`)
+	defer os.Remove(fail.Command)
+	broken := testPolicy(t, "broken", `exit 1`)
+	defer os.Remove(broken.Command)
+
+	ui := new(cli.MockUi)
+	m := &Meta{
+		Policies: []*Policy{pass, warn, fail, broken},
+		Ui:       ui,
+	}
+
+	failures := m.checkPolicies(new(terraform.Plan))
+	if len(failures) != 2 {
+		t.Fatalf("bad: %#v", failures)
+	}
+	if failures[0] != "fail: failed" {
+		t.Fatalf("bad: %#v", failures)
+	}
+	if ui.OutputWriter.String() != "Policy warning: warn: no description\n" {
+		t.Fatalf("bad: %q", ui.OutputWriter.String())
+	}
+}
+
+// testPolicy returns a policy that runs the given shell script. The
+// caller must remove the script.
+func testPolicy(t *testing.T, name, script string) *Policy {
+	return &Policy{Name: name, Command: testScript(t, script)}
+}
+
+// testScript writes an executable shell script and returns its path. The
+// caller must remove it.
+func testScript(t *testing.T, script string) string {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts aren't supported on Windows")
+	}
+
+	path := testTempFile(t)
+	err := ioutil.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0755)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return path
+}
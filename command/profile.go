@@ -0,0 +1,76 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+)
+
+// The names of the files written to a profile directory.
+const (
+	profileCPUFile     = "cpu.pprof"
+	profileHeapFile    = "heap.pprof"
+	profileTimingsFile = "timings.txt"
+)
+
+// profile collects a CPU profile, heap profile, and resource timings
+// for a single command run and writes them to a directory.
+type profile struct {
+	Dir  string
+	Hook *ProfileHook
+
+	cpuFile *os.File
+}
+
+// startProfile creates the profile directory and starts CPU profiling.
+// The Hook of the result must be added to the context to record timings.
+func startProfile(dir string) (*profile, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("Error creating profile directory: %s", err)
+	}
+
+	f, err := os.Create(filepath.Join(dir, profileCPUFile))
+	if err != nil {
+		return nil, fmt.Errorf("Error creating CPU profile: %s", err)
+	}
+	if err := pprof.StartCPUProfile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("Error starting CPU profile: %s", err)
+	}
+
+	return &profile{
+		Dir:     dir,
+		Hook:    new(ProfileHook),
+		cpuFile: f,
+	}, nil
+}
+
+// Stop stops CPU profiling and writes the heap profile and the timing
+// report into the profile directory.
+func (p *profile) Stop() error {
+	pprof.StopCPUProfile()
+	if err := p.cpuFile.Close(); err != nil {
+		return fmt.Errorf("Error writing CPU profile: %s", err)
+	}
+
+	f, err := os.Create(filepath.Join(p.Dir, profileHeapFile))
+	if err == nil {
+		err = pprof.WriteHeapProfile(f)
+		f.Close()
+	}
+	if err != nil {
+		return fmt.Errorf("Error writing heap profile: %s", err)
+	}
+
+	f, err = os.Create(filepath.Join(p.Dir, profileTimingsFile))
+	if err == nil {
+		err = p.Hook.WriteReport(f)
+		f.Close()
+	}
+	if err != nil {
+		return fmt.Errorf("Error writing timing report: %s", err)
+	}
+
+	return nil
+}
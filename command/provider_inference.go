@@ -0,0 +1,106 @@
+package command
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// ProviderResolver finds the provider with the given name, which was
+// inferred from a type of resource that none of the configured providers
+// manage. It returns a factory for the provider along with a description
+// of where it was found, or an error describing where it looked.
+type ProviderResolver func(name string) (terraform.ResourceProviderFactory, string, error)
+
+// inferProviders infers the providers of the types of resources in the
+// configuration and the state that none of the providers manage, and
+// resolves them with the ProviderResolver. The name of the provider is
+// the type up to the first underscore, so an "aws_instance" is managed
+// by the "aws" provider.
+//
+// The providers that are found are used by contextOpts. The types whose
+// providers aren't found are left for the context to report.
+func (m *Meta) inferProviders(c *config.Config, s *terraform.State) {
+	if m.ProviderResolver == nil {
+		return
+	}
+
+	var types []string
+	if c != nil {
+		for _, r := range c.Resources {
+			types = append(types, r.Type)
+		}
+	}
+	if s != nil {
+		for _, rs := range s.Resources {
+			types = append(types, rs.Type)
+		}
+	}
+
+	inferred := make(map[string]string)
+	for _, t := range types {
+		if m.providerForType(t) {
+			continue
+		}
+
+		idx := strings.Index(t, "_")
+		if idx <= 0 {
+			continue
+		}
+
+		if _, ok := inferred[t[:idx]]; !ok {
+			inferred[t[:idx]] = t
+		}
+	}
+
+	names := make([]string, 0, len(inferred))
+	for name, _ := range inferred {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		t := inferred[name]
+		log.Printf("[INFO] Inferring provider %q from resource type: %s", name, t)
+
+		// The notes go to the error output for the same reason as the
+		// warning in checkVersion.
+		f, where, err := m.ProviderResolver(name)
+		if err != nil {
+			m.Ui.Error(m.Colorize().Color(fmt.Sprintf(
+				"[reset][yellow]No provider is configured for %s, and the inferred\n"+
+					"provider %q wasn't found: %s", t, name, err)))
+			continue
+		}
+
+		if m.inferredProviders == nil {
+			m.inferredProviders = make(map[string]terraform.ResourceProviderFactory)
+		}
+		m.inferredProviders[name] = f
+
+		m.Ui.Error(m.Colorize().Color(fmt.Sprintf(
+			"[reset][bold]No provider is configured for %s. Using the inferred\n"+
+				"provider %q, %s.", t, name, where)))
+	}
+}
+
+// providerForType returns true if a provider, configured or inferred,
+// has a name that is a prefix of the type.
+func (m *Meta) providerForType(t string) bool {
+	for _, ps := range []map[string]terraform.ResourceProviderFactory{
+		m.ContextOpts.Providers,
+		m.inferredProviders,
+	} {
+		for prefix, _ := range ps {
+			if strings.HasPrefix(t, prefix) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,104 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+)
+
+func TestMetaInferProviders(t *testing.T) {
+	p := testProvider()
+	p.ResourcesReturn = []terraform.ResourceType{
+		terraform.ResourceType{Name: "foo_instance"},
+	}
+
+	var resolved []string
+	ui := new(cli.MockUi)
+	c := &PlanCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+			ProviderResolver: func(
+				name string) (terraform.ResourceProviderFactory, string, error) {
+				resolved = append(resolved, name)
+				return terraform.ResourceProviderFactoryFixed(p), "found at /plugins/foo", nil
+			},
+		},
+	}
+
+	args := []string{
+		"-state", testTempFile(t),
+		testFixturePath("plan-inferred-provider"),
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	// Only the type that no provider manages is inferred
+	if len(resolved) != 1 || resolved[0] != "foo" {
+		t.Fatalf("bad: %#v", resolved)
+	}
+	if !p.DiffCalled {
+		t.Fatal("diff should be called on the inferred provider")
+	}
+
+	actual := ui.ErrorWriter.String()
+	if !strings.Contains(actual, `inferred
+provider "foo", found at /plugins/foo`) {
+		t.Fatalf("bad: %s", actual)
+	}
+}
+
+func TestMetaInferProviders_notFound(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := &PlanCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+			ProviderResolver: func(
+				name string) (terraform.ResourceProviderFactory, string, error) {
+				return nil, "", fmt.Errorf("terraform-provider-%s is missing", name)
+			},
+		},
+	}
+
+	args := []string{
+		"-state", testTempFile(t),
+		testFixturePath("plan-inferred-provider"),
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.OutputWriter.String())
+	}
+
+	actual := ui.ErrorWriter.String()
+	if !strings.Contains(actual, "terraform-provider-foo is missing") {
+		t.Fatalf("bad: %s", actual)
+	}
+	if !strings.Contains(ui.OutputWriter.String(), "foo_instance") {
+		t.Fatalf("bad: %s", ui.OutputWriter.String())
+	}
+}
+
+func TestMetaInferProviders_noResolver(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := &PlanCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-state", testTempFile(t),
+		testFixturePath("plan-inferred-provider"),
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.OutputWriter.String())
+	}
+	if strings.Contains(ui.ErrorWriter.String(), "inferred") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+}
@@ -0,0 +1,47 @@
+package command
+
+import (
+	"strings"
+)
+
+// ProvidersCommand is a Command implementation that dispatches to the
+// subcommands used for inspecting the providers Terraform knows about.
+type ProvidersCommand struct {
+	Meta
+}
+
+func (c *ProvidersCommand) Run(args []string) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "capabilities":
+			cmd := &ProvidersCapabilitiesCommand{Meta: c.Meta}
+			return cmd.Run(args[1:])
+		case "schema":
+			cmd := &ProvidersSchemaCommand{Meta: c.Meta}
+			return cmd.Run(args[1:])
+		}
+	}
+
+	c.Ui.Error(c.Help())
+	return 1
+}
+
+func (c *ProvidersCommand) Help() string {
+	helpText := `
+Usage: terraform providers <subcommand> [options]
+
+  Shows information about the providers Terraform knows about, which are
+  the built-in providers and the ones in the Terraform configuration file.
+
+Subcommands:
+
+  capabilities    Show the optional features providers support
+  schema          Show the configuration attributes of providers
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ProvidersCommand) Synopsis() string {
+	return "Show information about providers"
+}
@@ -0,0 +1,117 @@
+package command
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// ProvidersCapabilitiesCommand is a Command implementation that shows
+// which of the optional features of Terraform the providers support.
+type ProvidersCapabilitiesCommand struct {
+	Meta
+}
+
+func (c *ProvidersCapabilitiesCommand) Run(args []string) int {
+	args = c.Meta.process(args, false)
+
+	cmdFlags := flag.NewFlagSet("providers capabilities", flag.ContinueOnError)
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		return 1
+	}
+
+	providers := c.ContextOpts.Providers
+	names := cmdFlags.Args()
+	if len(names) == 0 {
+		for name, _ := range providers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	for i, name := range names {
+		f, ok := providers[name]
+		if !ok {
+			c.Ui.Error(fmt.Sprintf("Unknown provider: %s", name))
+			return 1
+		}
+
+		p, err := f()
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error loading provider %s: %s", name, err))
+			return 1
+		}
+
+		if i > 0 {
+			c.Ui.Output("")
+		}
+		c.Ui.Output(c.Colorize().Color(fmt.Sprintf("[bold]%s", name)))
+		c.Ui.Output(c.formatCapabilities(p.Capabilities()))
+	}
+
+	return 0
+}
+
+func (c *ProvidersCapabilitiesCommand) Help() string {
+	helpText := `
+Usage: terraform providers capabilities [options] [NAME...]
+
+  Shows which optional features the named providers, or all the
+  providers Terraform knows about, support:
+
+    list resources        Existing resources can be found with
+                          "terraform scan".
+    validate credentials  Credentials are checked before planning.
+    cancel calls          Calls in progress are canceled on an
+                          interrupt, rather than left to finish.
+
+  Plugins built with older versions of Terraform can't report their
+  capabilities. They're used as before, but may need to be rebuilt to
+  support newer features.
+
+Options:
+
+  -no-color           If specified, output won't contain any color.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ProvidersCapabilitiesCommand) Synopsis() string {
+	return "Show the optional features providers support"
+}
+
+func (c *ProvidersCapabilitiesCommand) formatCapabilities(
+	caps terraform.ResourceProviderCapabilities) string {
+	if caps.Unknown {
+		return c.Colorize().Color(
+			"  [yellow]unknown[reset]: the plugin was built before providers could\n" +
+				"  report their capabilities. Rebuild it with this version of\n" +
+				"  Terraform to use the features it's missing.")
+	}
+
+	rows := []struct {
+		Name      string
+		Supported bool
+	}{
+		{"list resources", caps.ListResources},
+		{"validate credentials", caps.ValidateCredentials},
+		{"cancel calls", caps.Stop},
+	}
+
+	lines := make([]string, len(rows))
+	for i, r := range rows {
+		v := "no"
+		if r.Supported {
+			v = "yes"
+		}
+
+		lines[i] = fmt.Sprintf("  %-20s  %s", r.Name, v)
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,93 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+)
+
+func TestProvidersCapabilities(t *testing.T) {
+	p := testProvider()
+	p.CapabilitiesReturn = terraform.ResourceProviderCapabilities{
+		ListResources: true,
+		Stop:          true,
+	}
+
+	ui := new(cli.MockUi)
+	c := &ProvidersCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{"capabilities", "-no-color", "test"}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	if !p.CapabilitiesCalled {
+		t.Fatal("Capabilities should be called")
+	}
+
+	actual := strings.TrimSpace(ui.OutputWriter.String())
+	expected := strings.TrimSpace(testProvidersCapabilitiesStr)
+	if actual != expected {
+		t.Fatalf("bad:\n\n%s", actual)
+	}
+}
+
+func TestProvidersCapabilities_unknown(t *testing.T) {
+	p := testProvider()
+	p.CapabilitiesReturn = terraform.ResourceProviderCapabilities{
+		Unknown: true,
+	}
+
+	ui := new(cli.MockUi)
+	c := &ProvidersCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{"capabilities", "-no-color"}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	actual := ui.OutputWriter.String()
+	if !strings.Contains(actual, "unknown: the plugin was built before") {
+		t.Fatalf("bad:\n\n%s", actual)
+	}
+	if strings.Contains(actual, "list resources") {
+		t.Fatalf("bad:\n\n%s", actual)
+	}
+}
+
+func TestProvidersCapabilities_unknownProvider(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := &ProvidersCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{"capabilities", "nope"}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d", code)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "Unknown provider: nope") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+}
+
+const testProvidersCapabilitiesStr = `
+test
+  list resources        yes
+  validate credentials  no
+  cancel calls          yes
+`
@@ -0,0 +1,121 @@
+package command
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// ProvidersSchemaCommand is a Command implementation that shows the
+// attributes a provider is configured with, along with the environment
+// variables that are read when an attribute isn't set.
+type ProvidersSchemaCommand struct {
+	Meta
+}
+
+func (c *ProvidersSchemaCommand) Run(args []string) int {
+	args = c.Meta.process(args, false)
+
+	cmdFlags := flag.NewFlagSet("providers schema", flag.ContinueOnError)
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		return 1
+	}
+
+	providers := c.ContextOpts.Providers
+	names := cmdFlags.Args()
+	if len(names) == 0 {
+		for name, _ := range providers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	for i, name := range names {
+		f, ok := providers[name]
+		if !ok {
+			c.Ui.Error(fmt.Sprintf("Unknown provider: %s", name))
+			return 1
+		}
+
+		p, err := f()
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error loading provider %s: %s", name, err))
+			return 1
+		}
+
+		if i > 0 {
+			c.Ui.Output("")
+		}
+		c.Ui.Output(c.Colorize().Color(fmt.Sprintf("[bold]%s", name)))
+		c.Ui.Output(formatConfigSchema(p.ConfigSchema()))
+	}
+
+	return 0
+}
+
+func (c *ProvidersSchemaCommand) Help() string {
+	helpText := `
+Usage: terraform providers schema [options] [NAME...]
+
+  Shows the attributes that the named providers, or all the providers
+  Terraform knows about, are configured with.
+
+  For each attribute, the environment variables that are read when it
+  isn't set in the configuration are shown, in the order they're tried.
+
+Options:
+
+  -no-color           If specified, output won't contain any color.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ProvidersSchemaCommand) Synopsis() string {
+	return "Show the configuration attributes of providers"
+}
+
+// formatConfigSchema returns the attributes of a provider as aligned
+// columns of their names, flags and environment variables.
+func formatConfigSchema(attrs []terraform.ConfigAttribute) string {
+	if len(attrs) == 0 {
+		return "  (no attributes)"
+	}
+
+	rows := make([][3]string, len(attrs))
+	var nameLen, flagsLen int
+	for i, a := range attrs {
+		flags := []string{"optional"}
+		if a.Required {
+			flags[0] = "required"
+		}
+		if a.WriteOnly {
+			flags = append(flags, "write-only")
+		}
+
+		env := "-"
+		if len(a.EnvVars) > 0 {
+			env = strings.Join(a.EnvVars, ", ")
+		}
+
+		rows[i] = [3]string{a.Name, strings.Join(flags, ", "), env}
+		if len(a.Name) > nameLen {
+			nameLen = len(a.Name)
+		}
+		if len(rows[i][1]) > flagsLen {
+			flagsLen = len(rows[i][1])
+		}
+	}
+
+	lines := make([]string, len(rows))
+	for i, r := range rows {
+		lines[i] = fmt.Sprintf(
+			"  %-*s  %-*s  %s", nameLen, r[0], flagsLen, r[1], r[2])
+	}
+
+	return strings.Join(lines, "\n")
+}
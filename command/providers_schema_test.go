@@ -0,0 +1,95 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+)
+
+func TestProvidersCommand_implements(t *testing.T) {
+	var _ cli.Command = &ProvidersCommand{}
+}
+
+func TestProvidersSchema(t *testing.T) {
+	p := testProvider()
+	p.ConfigSchemaReturn = []terraform.ConfigAttribute{
+		terraform.ConfigAttribute{
+			Name: "region",
+		},
+		terraform.ConfigAttribute{
+			Name:      "token",
+			Required:  true,
+			WriteOnly: true,
+			EnvVars:   []string{"TEST_TOKEN", "TEST_KEY"},
+		},
+	}
+
+	ui := new(cli.MockUi)
+	c := &ProvidersCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{"schema", "-no-color", "test"}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	if !p.ConfigSchemaCalled {
+		t.Fatal("ConfigSchema should be called")
+	}
+
+	actual := strings.TrimSpace(ui.OutputWriter.String())
+	expected := strings.TrimSpace(testProvidersSchemaStr)
+	if actual != expected {
+		t.Fatalf("bad:\n\n%s", actual)
+	}
+}
+
+func TestProvidersSchema_unknown(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := &ProvidersCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{"schema", "nope"}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d", code)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "Unknown provider: nope") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+}
+
+func TestProvidersSchema_noAttributes(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := &ProvidersCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{"schema", "-no-color"}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	actual := strings.TrimSpace(ui.OutputWriter.String())
+	if actual != "test\n  (no attributes)" {
+		t.Fatalf("bad:\n\n%s", actual)
+	}
+}
+
+const testProvidersSchemaStr = `
+test
+  region  optional              -
+  token   required, write-only  TEST_TOKEN, TEST_KEY
+`
@@ -0,0 +1,362 @@
+package command
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// EnvPushToken is the environmental variable that holds the token used
+// to authenticate to the remote run API if -token isn't given.
+const EnvPushToken = "TF_PUSH_TOKEN"
+
+// DefaultPushPollInterval is how often the status of a pushed run is
+// checked when waiting for it to finish.
+const DefaultPushPollInterval = 5 * time.Second
+
+// PushCommand is a cli.Command implementation that submits the
+// configuration to a remote server that runs Terraform.
+type PushCommand struct {
+	Meta
+
+	// Address is the default address of the remote run API, from the
+	// CLI configuration.
+	Address string
+
+	// pollInterval overrides DefaultPushPollInterval.
+	pollInterval time.Duration
+}
+
+// pushRequest is the body of the request that submits a run.
+type pushRequest struct {
+	Name      string            `json:"name"`
+	StateRef  string            `json:"state_ref"`
+	Variables map[string]string `json:"variables"`
+
+	// Configuration is the gzipped tar archive of the configuration
+	// directory.
+	Configuration []byte `json:"configuration"`
+}
+
+// pushRun is the status of a run on the server.
+type pushRun struct {
+	ID      string `json:"id"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// Finished returns whether the run is done, and whether it succeeded.
+func (r *pushRun) Finished() (bool, bool) {
+	switch r.Status {
+	case "finished":
+		return true, true
+	case "errored", "canceled":
+		return true, false
+	default:
+		return false, false
+	}
+}
+
+func (c *PushCommand) Run(args []string) int {
+	var address, name, stateRef, token string
+	var poll bool
+
+	args = c.Meta.process(args, true)
+
+	cmdFlags := c.Meta.flagSet("push")
+	cmdFlags.StringVar(&address, "address", c.Address, "address")
+	cmdFlags.StringVar(&name, "name", "", "name")
+	cmdFlags.StringVar(&stateRef, "state-ref", "", "name")
+	cmdFlags.StringVar(&token, "token", os.Getenv(EnvPushToken), "token")
+	cmdFlags.BoolVar(&poll, "poll", true, "poll")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		return 1
+	}
+
+	var configPath string
+	args = cmdFlags.Args()
+	if len(args) > 1 {
+		c.Ui.Error("The push command expects at most one argument.")
+		cmdFlags.Usage()
+		return 1
+	} else if len(args) == 1 {
+		configPath = c.path(args[0])
+	} else {
+		var err error
+		configPath, err = c.wd()
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error getting pwd: %s", err))
+			return 1
+		}
+	}
+
+	// The remote run changes infrastructure
+	if c.refuseReadOnly("push") {
+		return 1
+	}
+
+	if address == "" {
+		c.Ui.Error(
+			"The address of the remote run API must be given with -address\n" +
+				"or \"push_address\" in the Terraform CLI configuration.")
+		return 1
+	}
+	address = strings.TrimRight(address, "/")
+
+	// Make sure the configuration is valid before sending it
+	conf, err := config.LoadDir(configPath)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error loading config: %s", err))
+		return 1
+	}
+	if err := terraform.CheckRequiredVersion(conf); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	if err := conf.Validate(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error validating config: %s", err))
+		return 1
+	}
+
+	if name == "" {
+		abs, err := filepath.Abs(configPath)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error getting config path: %s", err))
+			return 1
+		}
+		name = filepath.Base(abs)
+	}
+	if stateRef == "" {
+		stateRef = name
+	}
+
+	archive, err := pushArchive(configPath)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error archiving config: %s", err))
+		return 1
+	}
+
+	vs := make(map[string]string)
+	for k, v := range c.autoVariables {
+		vs[k] = v
+	}
+	for k, v := range c.variables {
+		vs[k] = v
+	}
+
+	req := &pushRequest{
+		Name:          name,
+		StateRef:      stateRef,
+		Variables:     vs,
+		Configuration: archive,
+	}
+
+	var run pushRun
+	if err := pushDo(address+"/v1/runs", token, req, &run); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error submitting run: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Run %s submitted: %s", run.ID, run.Status))
+	if !poll {
+		return 0
+	}
+
+	interval := c.pollInterval
+	if interval == 0 {
+		interval = DefaultPushPollInterval
+	}
+
+	status := run.Status
+	for {
+		done, ok := run.Finished()
+		if done {
+			if run.Message != "" {
+				c.Ui.Output(run.Message)
+			}
+			if !ok {
+				c.Ui.Error(fmt.Sprintf("Run %s %s", run.ID, run.Status))
+				return 1
+			}
+
+			return 0
+		}
+
+		time.Sleep(interval)
+
+		url := fmt.Sprintf("%s/v1/runs/%s", address, run.ID)
+		if err := pushDo(url, token, nil, &run); err != nil {
+			c.Ui.Error(fmt.Sprintf("Error getting run status: %s", err))
+			return 1
+		}
+
+		if run.Status != status {
+			status = run.Status
+			c.Ui.Output(fmt.Sprintf("Run %s: %s", run.ID, run.Status))
+		}
+	}
+}
+
+func (c *PushCommand) Help() string {
+	helpText := `
+Usage: terraform push [options] [dir]
+
+  Submit the configuration to a remote server that runs Terraform, and
+  wait for the run to finish.
+
+  The configuration directory is uploaded along with the variables, and
+  a reference to the state that the server should use. Hidden files and
+  state files aren't uploaded.
+
+Options:
+
+  -address=url        Address of the remote run API. Defaults to
+                      "push_address" in the Terraform CLI configuration.
+
+  -name=name          Name of the configuration on the server. Defaults
+                      to the name of the configuration directory.
+
+  -no-color           If specified, output won't contain any color.
+
+  -poll=true          Wait for the run to finish, showing its status.
+                      Set to false to only submit the run.
+
+  -state-ref=name     Reference to the state on the server that the run
+                      uses. Defaults to the name of the configuration.
+
+  -token=token        Token to authenticate to the server. Defaults to
+                      the TF_PUSH_TOKEN environmental variable.
+
+  -var 'foo=bar'      Set a variable in the Terraform configuration. This
+                      flag can be set multiple times.
+
+  -var-file=foo       Set variables in the Terraform configuration from
+                      a file. If "terraform.tfvars" is present, it will be
+                      automatically loaded if this flag is not specified.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *PushCommand) Synopsis() string {
+	return "Submit the configuration to a remote server to run"
+}
+
+// pushArchive returns a gzipped tar archive of the configuration
+// directory, leaving out hidden files and state files.
+func pushArchive(dir string) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+
+		base := info.Name()
+		if strings.HasPrefix(base, ".") {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+		if info.IsDir() || !info.Mode().IsRegular() {
+			return nil
+		}
+		if strings.Contains(base, ".tfstate") {
+			return nil
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// pushDo makes a request to the remote run API and decodes the response
+// into result. If body is nil, it's a GET request, otherwise body is
+// sent as JSON in a POST request.
+func pushDo(url, token string, body interface{}, result interface{}) error {
+	method := "GET"
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+
+		method = "POST"
+		r = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, url, r)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s: %s", method, url, resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,260 @@
+package command
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mitchellh/cli"
+)
+
+func TestPush(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+	testPushDir(t, td)
+
+	var req pushRequest
+	var auth string
+	statuses := []string{"running", "finished"}
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == "POST" && r.URL.Path == "/v1/runs":
+				auth = r.Header.Get("Authorization")
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Fatalf("err: %s", err)
+				}
+				json.NewEncoder(w).Encode(&pushRun{ID: "1", Status: "pending"})
+			case r.Method == "GET" && r.URL.Path == "/v1/runs/1":
+				run := &pushRun{ID: "1", Status: statuses[0]}
+				if len(statuses) > 1 {
+					statuses = statuses[1:]
+				} else {
+					run.Message = "Apply complete!"
+				}
+				json.NewEncoder(w).Encode(run)
+			default:
+				w.WriteHeader(404)
+			}
+		}))
+	defer ts.Close()
+
+	ui := new(cli.MockUi)
+	c := &PushCommand{
+		Meta: Meta{
+			Ui: ui,
+		},
+		Address:      ts.URL,
+		pollInterval: 10 * time.Millisecond,
+	}
+
+	args := []string{
+		"-token", "secret",
+		"-var", "foo=bar",
+		td,
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	if auth != "Bearer secret" {
+		t.Fatalf("bad: %s", auth)
+	}
+	if req.Name != filepath.Base(td) || req.StateRef != req.Name {
+		t.Fatalf("bad: %#v", req)
+	}
+	if !reflect.DeepEqual(req.Variables, map[string]string{"foo": "bar"}) {
+		t.Fatalf("bad: %#v", req.Variables)
+	}
+
+	files := testPushArchiveFiles(t, req.Configuration)
+	expected := []string{"main.tf", "modules/app.tf"}
+	if !reflect.DeepEqual(files, expected) {
+		t.Fatalf("bad: %#v", files)
+	}
+
+	output := ui.OutputWriter.String()
+	if !strings.Contains(output, "Run 1: running") {
+		t.Fatalf("bad: %s", output)
+	}
+	if !strings.Contains(output, "Apply complete!") {
+		t.Fatalf("bad: %s", output)
+	}
+}
+
+func TestPush_errored(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+	testPushDir(t, td)
+
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			status := "pending"
+			if r.Method == "GET" {
+				status = "errored"
+			}
+			json.NewEncoder(w).Encode(&pushRun{ID: "1", Status: status})
+		}))
+	defer ts.Close()
+
+	ui := new(cli.MockUi)
+	c := &PushCommand{
+		Meta: Meta{
+			Ui: ui,
+		},
+		pollInterval: 10 * time.Millisecond,
+	}
+
+	args := []string{
+		"-address", ts.URL,
+		"-var", "foo=bar",
+		td,
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.OutputWriter.String())
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "errored") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+}
+
+func TestPush_noAddress(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := &PushCommand{
+		Meta: Meta{
+			Ui: ui,
+		},
+	}
+
+	args := []string{
+		testFixturePath("apply"),
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d", code)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "push_address") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+}
+
+func TestPush_noPoll(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+	testPushDir(t, td)
+
+	var gets int
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "GET" {
+				gets++
+			}
+			json.NewEncoder(w).Encode(&pushRun{ID: "1", Status: "pending"})
+		}))
+	defer ts.Close()
+
+	ui := new(cli.MockUi)
+	c := &PushCommand{
+		Meta: Meta{
+			Ui: ui,
+		},
+		Address: ts.URL,
+	}
+
+	args := []string{
+		"-poll=false",
+		"-var", "foo=bar",
+		td,
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if gets != 0 {
+		t.Fatalf("should not poll: %d", gets)
+	}
+}
+
+func TestPush_readOnly(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := &PushCommand{
+		Meta: Meta{
+			Ui:       ui,
+			ReadOnly: true,
+		},
+		Address: "http://127.0.0.1:1",
+	}
+
+	args := []string{
+		testFixturePath("apply"),
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d", code)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "read-only") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+}
+
+// testPushDir fills the directory with a configuration, along with a
+// state file and hidden files that shouldn't be pushed.
+func testPushDir(t *testing.T, dir string) {
+	config, err := ioutil.ReadFile(
+		filepath.Join(testFixturePath("apply-vars"), "main.tf"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	files := map[string][]byte{
+		"main.tf":                  config,
+		"modules/app.tf":           []byte("# app\n"),
+		"terraform.tfstate":        []byte("{}"),
+		"terraform.tfstate.backup": []byte("{}"),
+		".terraform/plugins.json":  []byte("{}"),
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if err := ioutil.WriteFile(path, data, 0644); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+}
+
+// testPushArchiveFiles returns the sorted names of the files in a pushed
+// configuration archive.
+func testPushArchiveFiles(t *testing.T, data []byte) []string {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var result []string
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		result = append(result, h.Name)
+	}
+
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,110 @@
+package command
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// sensitiveRedacted replaces sensitive values in output and logs.
+const sensitiveRedacted = "<sensitive>"
+
+// redactor replaces sensitive values in text. Values can be added while
+// it's in use, since they're only known once the configuration is loaded
+// and outputs are computed.
+type redactor struct {
+	values []string
+	l      sync.RWMutex
+}
+
+// Add adds values to redact. Empty values are ignored.
+func (r *redactor) Add(vs ...string) {
+	r.l.Lock()
+	defer r.l.Unlock()
+
+	for _, v := range vs {
+		if v == "" {
+			continue
+		}
+
+		found := false
+		for _, existing := range r.values {
+			if existing == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			r.values = append(r.values, v)
+		}
+	}
+
+	// Longer values are replaced first, so that a value that contains
+	// another is replaced whole.
+	sort.Sort(sort.Reverse(stringLenSort(r.values)))
+}
+
+// Redact returns s with all the sensitive values replaced.
+func (r *redactor) Redact(s string) string {
+	if r == nil {
+		return s
+	}
+
+	r.l.RLock()
+	defer r.l.RUnlock()
+
+	for _, v := range r.values {
+		s = strings.Replace(s, v, sensitiveRedacted, -1)
+	}
+
+	return s
+}
+
+// redactWriter is an io.Writer that redacts sensitive values from what
+// is written to it. Values are only found if they're within a single
+// write, which is the case for log messages.
+type redactWriter struct {
+	Redactor *redactor
+	Writer   io.Writer
+}
+
+func (w *redactWriter) Write(p []byte) (int, error) {
+	if _, err := w.Writer.Write([]byte(w.Redactor.Redact(string(p)))); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// redactSecretBackend is a SecretBackend that hides the secrets it reads
+// from the output of the command and the log.
+type redactSecretBackend struct {
+	terraform.SecretBackend
+
+	Meta *Meta
+	L    *sync.Mutex
+}
+
+func (b *redactSecretBackend) Secret(key string) (string, error) {
+	v, err := b.SecretBackend.Secret(key)
+	if err != nil {
+		return "", err
+	}
+
+	// Secrets are read concurrently, so the Meta has to be locked.
+	b.L.Lock()
+	defer b.L.Unlock()
+	b.Meta.addSensitive([]string{v})
+
+	return v, nil
+}
+
+// stringLenSort sorts strings by length, shortest first.
+type stringLenSort []string
+
+func (s stringLenSort) Len() int           { return len(s) }
+func (s stringLenSort) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s stringLenSort) Less(i, j int) bool { return len(s[i]) < len(s[j]) }
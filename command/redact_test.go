@@ -0,0 +1,37 @@
+package command
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRedactor(t *testing.T) {
+	r := new(redactor)
+	r.Add("foo", "", "foobar", "foo")
+
+	actual := r.Redact("foobar and foo, but not bar")
+	expected := "<sensitive> and <sensitive>, but not bar"
+	if actual != expected {
+		t.Fatalf("bad: %s", actual)
+	}
+}
+
+func TestRedactor_nil(t *testing.T) {
+	var r *redactor
+	if actual := r.Redact("foo"); actual != "foo" {
+		t.Fatalf("bad: %s", actual)
+	}
+}
+
+func TestRedactWriter(t *testing.T) {
+	r := new(redactor)
+	r.Add("hunter2")
+
+	buf := new(bytes.Buffer)
+	w := &redactWriter{Redactor: r, Writer: buf}
+	w.Write([]byte("[DEBUG] password = hunter2\n"))
+
+	if buf.String() != "[DEBUG] password = <sensitive>\n" {
+		t.Fatalf("bad: %q", buf.String())
+	}
+}
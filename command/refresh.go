@@ -2,6 +2,7 @@ package command
 
 import (
 	"fmt"
+	"io/ioutil"
 	"log"
 	"os"
 	"strings"
@@ -13,17 +14,22 @@ import (
 // file.
 type RefreshCommand struct {
 	Meta
+
+	ShutdownCh <-chan struct{}
 }
 
 func (c *RefreshCommand) Run(args []string) int {
-	var statePath, stateOutPath, backupPath string
+	var statePath, stateOutPath, backupPath, override string
 
 	args = c.Meta.process(args, true)
+	audit := c.startAudit("refresh", args)
 
 	cmdFlags := c.Meta.flagSet("refresh")
 	cmdFlags.StringVar(&statePath, "state", DefaultStateFilename, "path")
 	cmdFlags.StringVar(&stateOutPath, "state-out", "", "path")
+	cmdFlags.StringVar(&c.message, "message", "", "message")
 	cmdFlags.StringVar(&backupPath, "backup", "", "path")
+	cmdFlags.StringVar(&override, "override", "", "reason")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		return 1
@@ -36,21 +42,40 @@ func (c *RefreshCommand) Run(args []string) int {
 		cmdFlags.Usage()
 		return 1
 	} else if len(args) == 1 {
-		configPath = args[0]
+		configPath = c.path(args[0])
 	} else {
 		var err error
-		configPath, err = os.Getwd()
+		configPath, err = c.wd()
 		if err != nil {
 			c.Ui.Error(fmt.Sprintf("Error getting pwd: %s", err))
 		}
 	}
 
+	// Paths are relative to the working directory
+	statePath = c.path(statePath)
+	stateOutPath = c.path(stateOutPath)
+	backupPath = c.path(backupPath)
+
 	// If we don't specify an output path, default to out normal state
 	// path.
 	if stateOutPath == "" {
 		stateOutPath = statePath
 	}
 
+	// In read-only mode, the refreshed state is written to a temporary
+	// file so that the state isn't changed.
+	if c.ReadOnly {
+		f, err := ioutil.TempFile("", "terraform-refresh")
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error creating temporary state file: %s", err))
+			return 1
+		}
+		f.Close()
+
+		stateOutPath = f.Name()
+		backupPath = "-"
+	}
+
 	// If we don't specify a backup path, default to state out with
 	// the extension
 	if backupPath == "" {
@@ -83,13 +108,18 @@ func (c *RefreshCommand) Run(args []string) int {
 		return 1
 	}
 
-	// Build the context based on the arguments given
+	// Build the context based on the arguments given. A state written by
+	// a newer version of Terraform is only written if overridden.
+	c.refuseNewerVersion = override == "" && !c.ReadOnly
+	if override != "" {
+		audit.SetOverride(override, nil)
+	}
 	ctx, _, err := c.Context(configPath, statePath)
 	if err != nil {
 		c.Ui.Error(err.Error())
 		return 1
 	}
-	if !validateContext(ctx, c.Ui) {
+	if !c.validateContext(ctx) {
 		return 1
 	}
 
@@ -107,22 +137,35 @@ func (c *RefreshCommand) Run(args []string) int {
 		}
 	}
 
-	state, err := ctx.Refresh()
+	// A stopped refresh still has a result, with the resources that were
+	// refreshed, which is saved below.
+	var state *terraform.State
+	_, finished := c.runInterruptible(ctx, c.ShutdownCh, func() {
+		state, err = ctx.Refresh()
+	})
+	if !finished {
+		return 1
+	}
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error refreshing state: %s", err))
 		return 1
 	}
 
+	c.recordRun(state)
+
 	log.Printf("[INFO] Writing state output to: %s", stateOutPath)
-	f, err := os.Create(stateOutPath)
-	if err == nil {
-		defer f.Close()
-		err = terraform.WriteState(state, f)
-	}
-	if err != nil {
+	if err := c.writeStateFile(stateOutPath, state); err != nil {
+		audit.Finish(stateOutPath, nil, err)
 		c.Ui.Error(fmt.Sprintf("Error writing state file: %s", err))
 		return 1
 	}
+	audit.Finish(stateOutPath, state, nil)
+
+	if c.ReadOnly {
+		c.Ui.Output(fmt.Sprintf(
+			"Read-only mode: the refreshed state was written to %s",
+			stateOutPath))
+	}
 
 	return 0
 }
@@ -138,20 +181,36 @@ Usage: terraform refresh [options] [dir]
   state file to update metadata. This metadata might cause new changes
   to occur when you generate a plan or call apply next.
 
+  In read-only mode, the refreshed state is written to a temporary file
+  instead, and its path is shown.
+
 Options:
 
   -backup=path        Path to backup the existing state file before
                       modifying. Defaults to the "-state-out" path with
                       ".backup" extension. Set to "-" to disable backup.
 
+  -debug-dump=dir     Write the requests and responses of provider calls to
+                      the given directory, with secrets redacted, to include
+                      in bug reports.
+
+  -message=text       Why the command is run, recorded in the metadata of
+                      the state and in the audit log.
+
   -no-color           If specified, output won't contain any color.
 
+  -override=reason    Refresh the state even if it was written by a newer
+                      version of Terraform. The reason is recorded in the
+                      audit log.
+
   -state=path         Path to read and save state (unless state-out
                       is specified). Defaults to "terraform.tfstate".
 
   -state-out=path     Path to write updated state file. By default, the
                       "-state" path will be used.
 
+  -strict             Treat warnings about the configuration as errors.
+
   -var 'foo=bar'      Set a variable in the Terraform configuration. This
                       flag can be set multiple times.
 
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform/terraform"
@@ -64,6 +65,78 @@ func TestRefresh(t *testing.T) {
 	}
 }
 
+func TestRefresh_readOnly(t *testing.T) {
+	state := &terraform.State{
+		Resources: map[string]*terraform.ResourceState{
+			"test_instance.foo": &terraform.ResourceState{
+				ID:   "bar",
+				Type: "test_instance",
+			},
+		},
+	}
+	statePath := testStateFile(t, state)
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &RefreshCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			ReadOnly:    true,
+			Ui:          ui,
+		},
+	}
+
+	p.RefreshFn = nil
+	p.RefreshReturn = &terraform.ResourceState{ID: "yes"}
+
+	args := []string{
+		"-state", statePath,
+		testFixturePath("refresh"),
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	// The state is unchanged
+	f, err := os.Open(statePath)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	oldState, err := terraform.ReadState(f)
+	f.Close()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if oldState.Resources["test_instance.foo"].ID != "bar" {
+		t.Fatalf("bad: %#v", oldState)
+	}
+	if _, err := os.Stat(statePath + DefaultBackupExtention); err == nil {
+		t.Fatal("backup should not exist")
+	}
+
+	// The refreshed state is in the temporary file that is shown
+	output := ui.OutputWriter.String()
+	idx := strings.LastIndex(output, "written to ")
+	if idx < 0 {
+		t.Fatalf("bad: %s", output)
+	}
+	outPath := strings.TrimSpace(output[idx+len("written to "):])
+	defer os.Remove(outPath)
+
+	f, err = os.Open(outPath)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	newState, err := terraform.ReadState(f)
+	f.Close()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if newState.Resources["test_instance.foo"].ID != "yes" {
+		t.Fatalf("bad: %#v", newState)
+	}
+}
+
 func TestRefresh_badState(t *testing.T) {
 	p := testProvider()
 	ui := new(cli.MockUi)
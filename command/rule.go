@@ -0,0 +1,200 @@
+package command
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/terraform/config"
+)
+
+// RuleFileExtension is the extension of files next to the configuration
+// that define rules with "policy" blocks.
+const RuleFileExtension = ".tfpolicy"
+
+// Rule is a convention that the resources in the configuration must
+// follow, such as a naming convention or tags that every resource must
+// have. Rules are checked with the configuration, before anything reaches
+// a provider. They are configured with "policy" blocks in the CLI
+// configuration or in RuleFileExtension files.
+type Rule struct {
+	Name string
+
+	// Type is the type of resource that the rule applies to. If it's
+	// empty, the rule applies to every resource.
+	Type string
+
+	// NamePattern, if set, must match the names of the resources.
+	NamePattern *regexp.Regexp
+
+	// RequiredTags are the keys that must be set in the "tags" of the
+	// resources.
+	RequiredTags []string
+}
+
+// NewRule returns the rule with the given name and settings, as they are
+// written in a "policy" block. An error is returned if the name pattern
+// isn't a valid regular expression.
+func NewRule(name, typ, pattern string, tags []string) (*Rule, error) {
+	result := &Rule{
+		Name:         name,
+		Type:         typ,
+		RequiredTags: tags,
+	}
+
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"policy %s: invalid name_pattern: %s", name, err)
+		}
+
+		result.NamePattern = re
+	}
+
+	return result, nil
+}
+
+// Check returns the violations of the rule by the configuration. The
+// errors are *config.Diagnostic, with the resources that violate the rule.
+func (r *Rule) Check(c *config.Config) []error {
+	var errs []error
+	for _, res := range c.Resources {
+		if r.Type != "" && r.Type != res.Type {
+			continue
+		}
+
+		addErr := func(format string, a ...interface{}) {
+			errs = append(errs, &config.Diagnostic{
+				Severity: config.DiagError,
+				Code:     "policy",
+				Pos:      res.Pos,
+				Resource: res.Id(),
+				Summary: fmt.Sprintf(
+					"policy %s: %s", r.Name, fmt.Sprintf(format, a...)),
+			})
+		}
+
+		if r.NamePattern != nil && !r.NamePattern.MatchString(res.Name) {
+			addErr("name must match %q", r.NamePattern.String())
+		}
+
+		if len(r.RequiredTags) == 0 {
+			continue
+		}
+
+		var raw interface{}
+		if res.RawConfig != nil {
+			raw = res.RawConfig.Raw["tags"]
+		}
+		tags := resourceTags(raw)
+		for _, k := range r.RequiredTags {
+			if _, ok := tags[k]; !ok {
+				addErr("missing required tag %q", k)
+			}
+		}
+	}
+
+	return errs
+}
+
+// resourceTags returns the keys of the raw "tags" of a resource, which
+// are a map, or a list of maps when they're written as blocks.
+func resourceTags(raw interface{}) map[string]struct{} {
+	result := make(map[string]struct{})
+	switch v := raw.(type) {
+	case map[string]interface{}:
+		for k, _ := range v {
+			result[k] = struct{}{}
+		}
+	case []map[string]interface{}:
+		for _, m := range v {
+			for k, _ := range m {
+				result[k] = struct{}{}
+			}
+		}
+	case []interface{}:
+		for _, elem := range v {
+			for k, _ := range resourceTags(elem) {
+				result[k] = struct{}{}
+			}
+		}
+	}
+
+	return result
+}
+
+// ruleFile is the structure of a RuleFileExtension file.
+type ruleFile struct {
+	Policies map[string]*struct {
+		Resource     string   `hcl:"resource"`
+		NamePattern  string   `hcl:"name_pattern"`
+		RequiredTags []string `hcl:"required_tags"`
+	} `hcl:"policy"`
+}
+
+// loadRules loads the rules of the RuleFileExtension files in the given
+// directory, sorted by name.
+func loadRules(dir string) ([]*Rule, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+RuleFileExtension))
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*Rule
+	for _, path := range paths {
+		d, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading %s: %s", path, err)
+		}
+
+		obj, err := hcl.Parse(string(d))
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing %s: %s", path, err)
+		}
+
+		var f ruleFile
+		if err := hcl.DecodeObject(&f, obj); err != nil {
+			return nil, fmt.Errorf("Error decoding %s: %s", path, err)
+		}
+
+		names := make([]string, 0, len(f.Policies))
+		for n, _ := range f.Policies {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+
+		for _, n := range names {
+			p := f.Policies[n]
+			r, err := NewRule(n, p.Resource, p.NamePattern, p.RequiredTags)
+			if err != nil {
+				return nil, fmt.Errorf("Error in %s: %s", path, err)
+			}
+
+			result = append(result, r)
+		}
+	}
+
+	return result, nil
+}
+
+// checkRules returns the violations of the rules by the configuration
+// that was loaded by Context. There are none if a plan was loaded.
+func (m *Meta) checkRules() []error {
+	if m.config == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, r := range m.Rules {
+		errs = append(errs, r.Check(m.config)...)
+	}
+	for _, r := range m.configRules {
+		errs = append(errs, r.Check(m.config)...)
+	}
+
+	return errs
+}
@@ -0,0 +1,137 @@
+package command
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/mitchellh/cli"
+)
+
+func TestNewRule(t *testing.T) {
+	r, err := NewRule("naming", "aws_instance", "^[a-z]+$", nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if r.NamePattern == nil || !r.NamePattern.MatchString("web") {
+		t.Fatalf("bad: %#v", r)
+	}
+
+	if _, err := NewRule("naming", "", "[a-z", nil); err == nil {
+		t.Fatal("should error")
+	}
+}
+
+func TestRuleCheck(t *testing.T) {
+	c, err := config.LoadDir(testFixturePath("validate-rules"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	cases := []struct {
+		Rule     *Rule
+		Expected []string
+	}{
+		{
+			&Rule{Name: "none"},
+			nil,
+		},
+		{
+			&Rule{
+				Name:         "tags",
+				Type:         "test_instance",
+				RequiredTags: []string{"Name", "Owner"},
+			},
+			[]string{
+				`main.tf:1: resource 'test_instance.foo': policy tags: missing required tag "Owner"`,
+			},
+		},
+		{
+			&Rule{
+				Name:         "all",
+				RequiredTags: []string{"Name"},
+			},
+			[]string{
+				`main.tf:18: resource 'test_volume.baz': policy all: missing required tag "Name"`,
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		// The positions have the full path of the files
+		errs := tc.Rule.Check(c)
+		if len(errs) != len(tc.Expected) {
+			t.Fatalf("%d: bad: %#v", i, errs)
+		}
+		for j, err := range errs {
+			if !strings.HasSuffix(err.Error(), "/"+tc.Expected[j]) {
+				t.Fatalf("%d: bad: %s", i, err)
+			}
+		}
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	rules, err := loadRules(testFixturePath("validate-rules"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("bad: %#v", rules)
+	}
+	if rules[0].Name != "naming" || rules[0].NamePattern == nil {
+		t.Fatalf("bad: %#v", rules[0])
+	}
+	if rules[1].Type != "test_instance" ||
+		!reflect.DeepEqual(rules[1].RequiredTags, []string{"Name", "Owner"}) {
+		t.Fatalf("bad: %#v", rules[1])
+	}
+
+	rules, err = loadRules(testFixturePath("plan"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(rules) != 0 {
+		t.Fatalf("bad: %#v", rules)
+	}
+}
+
+func TestMetaCheckRules(t *testing.T) {
+	rule, err := NewRule("naming", "", "^[a-z]+$", nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	m := &Meta{
+		ContextOpts: testCtxConfig(p),
+		Ui:          ui,
+		Rules:       []*Rule{rule},
+	}
+
+	if _, _, err := m.Context(testFixturePath("validate-rules"), ""); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var actual []string
+	for _, err := range m.checkRules() {
+		actual = append(actual, err.Error())
+	}
+	// The rules from the CLI configuration come before the rules next
+	// to the configuration
+	expected := []string{
+		`'test_instance.Bar': policy naming: name must match "^[a-z]+$"`,
+		`'test_instance.Bar': policy naming: name must match "^[a-z][a-z0-9_]*$"`,
+		`'test_instance.foo': policy tags: missing required tag "Owner"`,
+	}
+	if len(actual) != len(expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+	for i, e := range expected {
+		if !strings.HasSuffix(actual[i], e) {
+			t.Fatalf("bad: %#v", actual)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package command
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/terraform/state"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// newRunID returns a random UUID that identifies a run of a command.
+func newRunID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("Error generating run ID: %s", err))
+	}
+
+	// Version 4, variant 10
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// recordRun stores who ran the command, where, and why in the metadata
+// of the state as "run_id", "run_user", "run_host" and "run_message", so
+// that every serial of the state can be attributed. The message of a
+// previous run is removed if this run has none.
+func (m *Meta) recordRun(s *terraform.State) {
+	if s.Metadata == nil {
+		s.Metadata = make(map[string]string)
+	}
+
+	host, _ := os.Hostname()
+	for k, v := range map[string]string{
+		"run_id":      m.runID,
+		"run_user":    auditUser(),
+		"run_host":    host,
+		"run_message": m.message,
+	} {
+		if v != "" {
+			s.Metadata[k] = v
+		} else {
+			delete(s.Metadata, k)
+		}
+	}
+
+	if len(s.Metadata) == 0 {
+		s.Metadata = nil
+	}
+}
+
+// lockState locks the local state file at the given path for this run,
+// recording the user, the host and the ID of the run in the lock.
+func (m *Meta) lockState(path string) (*state.LocalLock, error) {
+	return state.LockLocal(path, m.lockInfo())
+}
+
+// lockInfo describes this run in the locks it takes.
+func (m *Meta) lockInfo() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s@%s, run %s", auditUser(), host, m.runID)
+}
@@ -0,0 +1,49 @@
+package command
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestNewRunID(t *testing.T) {
+	re := regexp.MustCompile(
+		`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	a, b := newRunID(), newRunID()
+	if !re.MatchString(a) {
+		t.Fatalf("bad: %s", a)
+	}
+	if a == b {
+		t.Fatalf("run IDs should be unique: %s", a)
+	}
+}
+
+func TestMetaRecordRun(t *testing.T) {
+	s := &terraform.State{
+		Metadata: map[string]string{
+			"run_message": "old",
+			"git_commit":  "0123456789",
+		},
+	}
+
+	m := &Meta{runID: "id", message: "bump web ASG"}
+	m.recordRun(s)
+	if s.Metadata["run_id"] != "id" || s.Metadata["run_message"] != "bump web ASG" {
+		t.Fatalf("bad: %#v", s.Metadata)
+	}
+	if s.Metadata["git_commit"] != "0123456789" {
+		t.Fatalf("bad: %#v", s.Metadata)
+	}
+
+	// The message of a previous run is removed
+	m = &Meta{runID: "other"}
+	m.recordRun(s)
+	if _, ok := s.Metadata["run_message"]; ok {
+		t.Fatalf("bad: %#v", s.Metadata)
+	}
+	if s.Metadata["run_id"] != "other" {
+		t.Fatalf("bad: %#v", s.Metadata)
+	}
+}
@@ -0,0 +1,296 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// ScanCommand is a Command implementation that lists the existing
+// resources of the given types that Terraform doesn't manage yet, and
+// generates configuration for them.
+type ScanCommand struct {
+	Meta
+}
+
+// invalidNameChars matches the characters that can't be in the names of
+// generated resources.
+var invalidNameChars = regexp.MustCompile("[^a-z0-9_]+")
+
+func (c *ScanCommand) Run(args []string) int {
+	var configPath, outPath, statePath, stateOutPath string
+	var filters FlagVar
+
+	args = c.Meta.process(args, true)
+
+	cmdFlags := c.Meta.flagSet("scan")
+	cmdFlags.StringVar(&configPath, "config", "", "path")
+	cmdFlags.Var(&filters, "filter", "filter")
+	cmdFlags.StringVar(&c.message, "message", "", "message")
+	cmdFlags.StringVar(&outPath, "out", "", "path")
+	cmdFlags.StringVar(&statePath, "state", DefaultStateFilename, "path")
+	cmdFlags.StringVar(&stateOutPath, "state-out", "", "path")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		return 1
+	}
+
+	types := cmdFlags.Args()
+	if len(types) == 0 {
+		c.Ui.Error("The scan command expects the types of resources to scan.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+
+	if stateOutPath != "" && c.refuseReadOnly("scan") {
+		return 1
+	}
+
+	if configPath == "" {
+		var err error
+		configPath, err = c.wd()
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error getting pwd: %s", err))
+		}
+	}
+	configPath = c.path(configPath)
+	statePath = c.path(statePath)
+	stateOutPath = c.path(stateOutPath)
+
+	// The configuration is only used for the provider configurations
+	// that are needed to list the resources.
+	ctx, planned, err := c.Context(configPath, statePath)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	if planned {
+		c.Ui.Error("The scan command expects a configuration, not a plan.")
+		return 1
+	}
+	if !c.validateContext(ctx) {
+		return 1
+	}
+
+	state := c.state
+	if state == nil {
+		state = new(terraform.State)
+	}
+	if state.Resources == nil {
+		state.Resources = make(map[string]*terraform.ResourceState)
+	}
+
+	// Resources that are already in the state are managed by Terraform,
+	// so they're skipped. Names in the state or the configuration can't
+	// be used for the new resources.
+	managed := make(map[string]struct{})
+	names := make(map[string]struct{})
+	for k, rs := range state.Resources {
+		managed[rs.Type+"."+rs.ID] = struct{}{}
+		names[k] = struct{}{}
+	}
+	if c.config != nil {
+		for _, r := range c.config.Resources {
+			names[r.Id()] = struct{}{}
+		}
+	}
+
+	found := make(map[string]*terraform.ResourceState)
+	for _, t := range types {
+		log.Printf("[INFO] Scanning for resources of type: %s", t)
+		result, err := ctx.ListResources(t, filters)
+		if err == terraform.ErrListNotSupported {
+			c.Ui.Error(fmt.Sprintf(
+				"The provider for %s can't list existing resources.", t))
+			return 1
+		}
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error scanning %s: %s", t, err))
+			return 1
+		}
+
+		for _, rs := range result {
+			if _, ok := managed[t+"."+rs.ID]; ok {
+				continue
+			}
+
+			name := scanResourceName(t, rs, names)
+			names[name] = struct{}{}
+			found[name] = rs
+		}
+	}
+
+	if len(found) == 0 {
+		c.Ui.Output(c.Colorize().Color(
+			"[reset][green]No unmanaged resources found."))
+		return 0
+	}
+
+	config := FormatScan(found)
+	if outPath == "" {
+		c.Ui.Output(config)
+	} else {
+		outPath = c.path(outPath)
+		if err := ioutil.WriteFile(outPath, []byte(config+"\n"), 0644); err != nil {
+			c.Ui.Error(fmt.Sprintf("Error writing configuration: %s", err))
+			return 1
+		}
+	}
+
+	if stateOutPath != "" {
+		for k, rs := range found {
+			state.Resources[k] = rs
+		}
+
+		c.recordRun(state)
+
+		log.Printf("[INFO] Writing state output to: %s", stateOutPath)
+		if err := c.writeStateFile(stateOutPath, state); err != nil {
+			c.Ui.Error(fmt.Sprintf("Error writing state file: %s", err))
+			return 1
+		}
+	}
+
+	if outPath != "" {
+		c.Ui.Output(fmt.Sprintf(
+			"Found %d unmanaged resource(s). The configuration was written to %s.",
+			len(found), outPath))
+	}
+
+	return 0
+}
+
+// scanResourceName returns a name for a resource that was found by a
+// scan, based on its name or Name tag if it has one and otherwise its ID.
+// The name isn't in the given set of names.
+func scanResourceName(
+	t string, rs *terraform.ResourceState, names map[string]struct{}) string {
+	name := rs.Attributes["name"]
+	if name == "" {
+		name = rs.Attributes["tags.Name"]
+	}
+	if name == "" {
+		name = rs.ID
+	}
+
+	name = invalidNameChars.ReplaceAllString(strings.ToLower(name), "_")
+	name = strings.Trim(name, "_")
+	if name == "" {
+		name = "resource"
+	}
+
+	result := fmt.Sprintf("%s.%s", t, name)
+	for i := 2; ; i++ {
+		if _, ok := names[result]; !ok {
+			return result
+		}
+
+		result = fmt.Sprintf("%s.%s_%d", t, name, i)
+	}
+}
+
+// FormatScan returns the configuration of the resources that were found
+// by a scan, keyed by their names in the form of "type.name".
+//
+// Only the top-level attributes are written. The configuration has to be
+// reviewed, since attributes that are computed by the provider can't be
+// set in the configuration and nested attributes are left out.
+func FormatScan(resources map[string]*terraform.ResourceState) string {
+	keys := make([]string, 0, len(resources))
+	for k, _ := range resources {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	buf := new(bytes.Buffer)
+	buf.WriteString(
+		"# Generated by \"terraform scan\" from existing resources. Review\n" +
+			"# this configuration before using it: remove the attributes that\n" +
+			"# are computed by the provider and add the nested attributes.\n")
+	for _, k := range keys {
+		rs := resources[k]
+		name := k[strings.Index(k, ".")+1:]
+
+		attrs := make([]string, 0, len(rs.Attributes))
+		for a, _ := range rs.Attributes {
+			if a == "id" || strings.Contains(a, ".") {
+				continue
+			}
+
+			attrs = append(attrs, a)
+		}
+		sort.Strings(attrs)
+
+		buf.WriteString(fmt.Sprintf("\n# ID: %s\n", rs.ID))
+		buf.WriteString(fmt.Sprintf("resource %q %q {\n", rs.Type, name))
+		for _, a := range attrs {
+			buf.WriteString(fmt.Sprintf("    %s = %q\n", a, rs.Attributes[a]))
+		}
+		buf.WriteString("}\n")
+	}
+
+	return strings.TrimSpace(buf.String())
+}
+
+func (c *ScanCommand) Help() string {
+	helpText := `
+Usage: terraform scan [options] TYPE...
+
+  Lists the existing resources of the given types that aren't in the
+  state, and generates configuration for them so that they can be
+  brought under the management of Terraform. Only providers that support
+  listing resources can be scanned.
+
+  This command is experimental. The generated configuration must be
+  reviewed, since it can contain attributes that are computed by the
+  provider, and nested attributes aren't generated.
+
+  The provider configurations are read from the configuration in the
+  current directory, or the "-config" directory.
+
+Options:
+
+  -config=path        Path to the configuration with the provider
+                      configurations. Defaults to the current directory.
+
+  -filter 'foo=bar'   Only list resources matching the filter. The filters
+                      that are supported depend on the type of resource.
+                      This flag can be set multiple times.
+
+  -message=text       Why the command is run, recorded in the metadata of
+                      the state written with -state-out.
+
+  -no-color           If specified, output won't contain any color.
+
+  -out=path           Path to write the configuration to. Defaults to
+                      the output of the command.
+
+  -state=path         Path to read the state from, to skip the resources
+                      that are already managed. Defaults to
+                      "terraform.tfstate".
+
+  -state-out=path     Path to write the state to, including the resources
+                      that were found, so that Terraform manages them
+                      without creating them again. By default, the state
+                      isn't written.
+
+  -var 'foo=bar'      Set a variable in the Terraform configuration. This
+                      flag can be set multiple times.
+
+  -var-file=foo       Set variables in the Terraform configuration from
+                      a file. If "terraform.tfvars" is present, it will be
+                      automatically loaded if this flag is not specified.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ScanCommand) Synopsis() string {
+	return "Generate configuration for existing resources (experimental)"
+}
@@ -0,0 +1,192 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+)
+
+func TestFormatScan(t *testing.T) {
+	resources := map[string]*terraform.ResourceState{
+		"test_instance.web": &terraform.ResourceState{
+			ID:   "i-1",
+			Type: "test_instance",
+			Attributes: map[string]string{
+				"id":                "i-1",
+				"ami":               "ami-1",
+				"security_groups.#": "1",
+				"security_groups.0": "default",
+			},
+		},
+	}
+
+	actual := FormatScan(resources)
+	expected := strings.TrimSpace(`
+# Generated by "terraform scan" from existing resources. Review
+# this configuration before using it: remove the attributes that
+# are computed by the provider and add the nested attributes.
+
+# ID: i-1
+resource "test_instance" "web" {
+    ami = "ami-1"
+}
+`)
+	if actual != expected {
+		t.Fatalf("bad:\n%s", actual)
+	}
+}
+
+func TestScanResourceName(t *testing.T) {
+	names := map[string]struct{}{
+		"test_instance.web": struct{}{},
+	}
+
+	cases := []struct {
+		Attrs    map[string]string
+		ID       string
+		Expected string
+	}{
+		{map[string]string{"name": "db"}, "i-1", "test_instance.db"},
+		{map[string]string{"tags.Name": "Web Server"}, "i-1", "test_instance.web_server"},
+		{nil, "i-1234", "test_instance.i_1234"},
+		{map[string]string{"name": "web"}, "i-1", "test_instance.web_2"},
+		{map[string]string{"name": "--"}, "i-1", "test_instance.resource"},
+	}
+
+	for i, tc := range cases {
+		rs := &terraform.ResourceState{ID: tc.ID, Attributes: tc.Attrs}
+		actual := scanResourceName("test_instance", rs, names)
+		if actual != tc.Expected {
+			t.Fatalf("%d: bad: %s", i, actual)
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	state := &terraform.State{
+		Resources: map[string]*terraform.ResourceState{
+			"test_instance.foo": &terraform.ResourceState{
+				ID:   "bar",
+				Type: "test_instance",
+			},
+		},
+	}
+	statePath := testStateFile(t, state)
+	outPath := testTempFile(t)
+	stateOutPath := testTempFile(t)
+
+	p := testProvider()
+	p.CapabilitiesReturn = terraform.ResourceProviderCapabilities{
+		ListResources: true,
+	}
+	p.ListResourcesReturn = []*terraform.ResourceState{
+		&terraform.ResourceState{ID: "bar"},
+		&terraform.ResourceState{
+			ID:         "baz",
+			Attributes: map[string]string{"name": "foo", "ami": "ami-1"},
+		},
+	}
+
+	ui := new(cli.MockUi)
+	c := &ScanCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-config", testFixturePath("refresh"),
+		"-filter", "ami=ami-1",
+		"-out", outPath,
+		"-state", statePath,
+		"-state-out", stateOutPath,
+		"test_instance",
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if p.ListResourcesFilters["ami"] != "ami-1" {
+		t.Fatalf("bad: %#v", p.ListResourcesFilters)
+	}
+
+	// The managed resource is skipped, and the name of the configured
+	// resource isn't reused.
+	data, err := ioutil.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	config := string(data)
+	if !strings.Contains(config, `resource "test_instance" "foo_2" {`) {
+		t.Fatalf("bad: %s", config)
+	}
+	if !strings.Contains(config, `ami = "ami-1"`) {
+		t.Fatalf("bad: %s", config)
+	}
+	if strings.Contains(config, "ID: bar") {
+		t.Fatalf("bad: %s", config)
+	}
+
+	f, err := os.Open(stateOutPath)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer f.Close()
+	newState, err := terraform.ReadState(f)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(newState.Resources) != 2 {
+		t.Fatalf("bad: %#v", newState.Resources)
+	}
+	rs := newState.Resources["test_instance.foo_2"]
+	if rs == nil || rs.ID != "baz" || rs.Type != "test_instance" {
+		t.Fatalf("bad: %#v", rs)
+	}
+}
+
+func TestScan_notSupported(t *testing.T) {
+	p := testProvider()
+	p.CapabilitiesReturn = terraform.ResourceProviderCapabilities{
+		ListResources: true,
+	}
+	p.ListResourcesReturnError = terraform.ErrListNotSupported
+
+	ui := new(cli.MockUi)
+	c := &ScanCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-config", testFixturePath("refresh"),
+		"-state", testTempFile(t),
+		"test_instance",
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.OutputWriter.String())
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "can't list") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+}
+
+func TestScan_noTypes(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := &ScanCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	if code := c.Run(nil); code != 1 {
+		t.Fatalf("bad: %d", code)
+	}
+}
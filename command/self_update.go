@@ -0,0 +1,416 @@
+package command
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/osext"
+)
+
+// UpdateSignatureExtension is added to the URL of the release index to
+// form the URL of its detached signature.
+const UpdateSignatureExtension = ".sig"
+
+// SelfUpdateCommand is a Command implementation that installs the
+// release of Terraform and its plugins given by a release index, so that
+// every machine runs the same version.
+type SelfUpdateCommand struct {
+	Meta
+
+	// Address is the URL of the release index, and KeyPath is the path
+	// of the public key that releases are signed with. They are from the
+	// CLI configuration.
+	Address string
+	KeyPath string
+
+	Version           string
+	VersionPrerelease string
+
+	// Dir is the directory the executables are installed in. Defaults to
+	// the directory of the Terraform executable.
+	Dir string
+}
+
+// ReleaseIndex is the index of the release that self-update installs.
+type ReleaseIndex struct {
+	Version string `json:"version"`
+
+	// Builds are the executables of Terraform and its plugins, keyed by
+	// file name, for each platform in the form "GOOS_GOARCH".
+	Builds map[string]map[string]*ReleaseBuild `json:"builds"`
+}
+
+// ReleaseBuild is an executable in a release index. The index is signed,
+// so the hash ties the executable to its name and the version.
+type ReleaseBuild struct {
+	URL    string `json:"url"`
+	SHA256 string `json:"sha256"`
+}
+
+func (c *SelfUpdateCommand) Run(args []string) int {
+	var address, keyPath string
+	var check, downgrade bool
+
+	args = c.Meta.process(args, false)
+
+	cmdFlags := c.Meta.flagSet("self-update")
+	cmdFlags.StringVar(&address, "address", c.Address, "address")
+	cmdFlags.BoolVar(&downgrade, "allow-downgrade", false, "allow-downgrade")
+	cmdFlags.BoolVar(&check, "check", false, "check")
+	cmdFlags.StringVar(&keyPath, "key", c.KeyPath, "path")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		return 1
+	}
+
+	if address == "" {
+		c.Ui.Error("The address of the release index is required. Set\n" +
+			"update_address in the CLI configuration, or use -address.")
+		return 1
+	}
+
+	// Without a key, the index can only be read to say whether there is
+	// another version. Nothing is installed from an index that isn't
+	// verified.
+	var key *ecdsa.PublicKey
+	if keyPath != "" {
+		var err error
+		key, err = LoadApprovalKey(c.path(keyPath))
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+	}
+
+	index, err := readReleaseIndex(address, key)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading release index: %s", err))
+		return 1
+	}
+
+	current := c.Version
+	if c.VersionPrerelease != "" {
+		current += "." + c.VersionPrerelease
+	}
+	if index.Version == current {
+		c.Ui.Output(fmt.Sprintf(
+			"Terraform v%s is the version of the release index.", current))
+		return 0
+	}
+	if check {
+		c.Ui.Output(fmt.Sprintf(
+			"Terraform v%s is available. This is Terraform v%s.",
+			index.Version, current))
+		return 2
+	}
+
+	// Releases are only installed if they are signed
+	if key == nil {
+		c.Ui.Error("The release signing key is required. Set update_key in\n" +
+			"the CLI configuration, or use -key.")
+		return 1
+	}
+
+	// An old index is still signed, so it could be served again to roll
+	// back to a release with known problems. Going back is only done
+	// when asked for.
+	if terraform.CompareVersions(index.Version, c.Version) < 0 && !downgrade {
+		c.Ui.Error(fmt.Sprintf(
+			"Terraform v%s is older than this version (v%s). Use\n"+
+				"-allow-downgrade to install it anyway.", index.Version, current))
+		return 1
+	}
+
+	platform := runtime.GOOS + "_" + runtime.GOARCH
+	files := index.Builds[platform]
+	if len(files) == 0 {
+		c.Ui.Error(fmt.Sprintf(
+			"Terraform v%s has no build for %s.", index.Version, platform))
+		return 1
+	}
+
+	dir := c.Dir
+	if dir == "" {
+		exePath, err := osext.Executable()
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error finding the executable: %s", err))
+			return 1
+		}
+
+		dir = filepath.Dir(exePath)
+	}
+
+	names := make([]string, 0, len(files))
+	for name, b := range files {
+		if b == nil {
+			c.Ui.Error(fmt.Sprintf("Missing build in release index: %q", name))
+			return 1
+		}
+		if name != filepath.Base(name) || strings.ContainsAny(name, `/\`) ||
+			name == "." || name == ".." {
+			c.Ui.Error(fmt.Sprintf("Invalid file name in release index: %q", name))
+			return 1
+		}
+
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Every executable is downloaded and verified before any is
+	// replaced, so that a bad release doesn't leave a mix of versions.
+	downloaded := make(map[string]string)
+	defer func() {
+		for _, path := range downloaded {
+			os.Remove(path)
+		}
+	}()
+	for _, name := range names {
+		c.Ui.Output(fmt.Sprintf("Downloading %s...", name))
+		path, err := downloadRelease(dir, files[name])
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error downloading %s: %s", name, err))
+			return 1
+		}
+
+		downloaded[name] = path
+	}
+
+	for _, name := range names {
+		dst := filepath.Join(dir, name)
+		log.Printf("[INFO] Replacing executable: %s", dst)
+		if err := replaceExecutable(downloaded[name], dst); err != nil {
+			c.Ui.Error(fmt.Sprintf("Error installing %s: %s", name, err))
+			return 1
+		}
+
+		delete(downloaded, name)
+	}
+
+	c.Ui.Output(c.Colorize().Color(fmt.Sprintf(
+		"[reset][green]Updated Terraform v%s to v%s, with %d executable(s) in %s.",
+		current, index.Version, len(names), dir)))
+	return 0
+}
+
+// FetchPlugin installs the plugin executable with the given file name,
+// such as "terraform-provider-aws", from the release index at address
+// into dir. The index is verified with the key at keyPath, and the plugin
+// with its hash in the index, like self-update does. The path it was
+// installed at is returned.
+func FetchPlugin(address, keyPath, dir, name string) (string, error) {
+	key, err := LoadApprovalKey(keyPath)
+	if err != nil {
+		return "", err
+	}
+
+	index, err := readReleaseIndex(address, key)
+	if err != nil {
+		return "", fmt.Errorf("Error reading release index: %s", err)
+	}
+
+	platform := runtime.GOOS + "_" + runtime.GOARCH
+	filename := name
+	if runtime.GOOS == "windows" {
+		filename += ".exe"
+	}
+	b := index.Builds[platform][filename]
+	if b == nil {
+		return "", fmt.Errorf(
+			"the release index at %s has no build of %s for %s",
+			address, filename, platform)
+	}
+
+	log.Printf("[INFO] Downloading plugin %s from: %s", filename, b.URL)
+	path, err := downloadRelease(dir, b)
+	if err != nil {
+		return "", fmt.Errorf("Error downloading %s: %s", filename, err)
+	}
+
+	dst := filepath.Join(dir, filename)
+	if err := replaceExecutable(path, dst); err != nil {
+		os.Remove(path)
+		return "", fmt.Errorf("Error installing %s: %s", filename, err)
+	}
+
+	return dst, nil
+}
+
+// readReleaseIndex reads the release index at the given URL, and
+// verifies its detached signature with the key. The signature is the
+// ASN.1 encoded ECDSA signature of the SHA-256 hash of the index, as
+// written by "openssl dgst -sha256 -sign". If the key is nil, the index
+// isn't verified.
+func readReleaseIndex(address string, key *ecdsa.PublicKey) (*ReleaseIndex, error) {
+	data, err := httpGetAll(address)
+	if err != nil {
+		return nil, err
+	}
+
+	if key != nil {
+		sig, err := httpGetAll(address + UpdateSignatureExtension)
+		if err != nil {
+			return nil, fmt.Errorf("Error downloading signature: %s", err)
+		}
+
+		hash := sha256.Sum256(data)
+		if err := verifyReleaseSignature(hash[:], sig, key); err != nil {
+			return nil, err
+		}
+	}
+
+	var result ReleaseIndex
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	if result.Version == "" {
+		return nil, fmt.Errorf("no version in release index")
+	}
+
+	return &result, nil
+}
+
+// downloadRelease downloads an executable to a temporary file in the
+// given directory, and checks it against its hash in the release index.
+func downloadRelease(dir string, b *ReleaseBuild) (string, error) {
+	expected, err := hex.DecodeString(b.SHA256)
+	if err != nil || len(expected) != sha256.Size {
+		return "", fmt.Errorf("invalid SHA-256 hash in release index: %q", b.SHA256)
+	}
+
+	resp, err := http.Get(b.URL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	f, err := ioutil.TempFile(dir, ".terraform-update")
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(f, h), resp.Body)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil && !bytes.Equal(h.Sum(nil), expected) {
+		err = fmt.Errorf("the SHA-256 hash doesn't match the release index")
+	}
+	if err == nil {
+		err = os.Chmod(f.Name(), 0755)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+
+	return f.Name(), nil
+}
+
+// verifyReleaseSignature checks the signature of the given hash.
+func verifyReleaseSignature(hash, sig []byte, key *ecdsa.PublicKey) error {
+	var s ecdsaSignature
+	if _, err := asn1.Unmarshal(sig, &s); err != nil {
+		return fmt.Errorf("invalid signature: %s", err)
+	}
+	if s.R == nil || s.S == nil || !ecdsa.Verify(key, hash, s.R, s.S) {
+		return fmt.Errorf("the signature is invalid")
+	}
+
+	return nil
+}
+
+// replaceExecutable atomically replaces the executable at dst with src.
+// Running executables can't be replaced on Windows, but they can be
+// renamed, so the executable is moved out of the way first if needed.
+func replaceExecutable(src, dst string) error {
+	err := os.Rename(src, dst)
+	if err == nil {
+		return nil
+	}
+
+	old := dst + ".old"
+	os.Remove(old)
+	if rerr := os.Rename(dst, old); rerr != nil {
+		return err
+	}
+	if err := os.Rename(src, dst); err != nil {
+		os.Rename(old, dst)
+		return err
+	}
+
+	return nil
+}
+
+func httpGetAll(url string) ([]byte, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+func (c *SelfUpdateCommand) Help() string {
+	helpText := `
+Usage: terraform self-update [options]
+
+  Installs the version of Terraform and its plugins given by the release
+  index, so that every machine runs the same version. The executables for
+  this platform are downloaded next to the Terraform executable, and each
+  one is checked against its hash in the index before any is replaced.
+
+  The release index is a JSON document with the "version" and, for each
+  platform such as "linux_amd64", the URL and SHA-256 hash of each
+  executable keyed by file name. The signature of the index is at its URL
+  with the ".sig" extension, as written by "openssl dgst -sha256 -sign".
+  Older versions than this one are only installed with -allow-downgrade.
+
+  The address of the release index and the signing key are set with
+  update_address and update_key in the CLI configuration.
+
+Options:
+
+  -address=url        URL of the release index. Overrides update_address.
+
+  -allow-downgrade    Install the version of the index even if it's older
+                      than this one.
+
+  -check              Only check whether another version is available. The
+                      exit code is 2 if it is.
+
+  -key=path           Path to the ECDSA public key that releases are signed
+                      with, in PEM format. Overrides update_key.
+
+  -no-color           If specified, output won't contain any color.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *SelfUpdateCommand) Synopsis() string {
+	return "Install the Terraform release from the release index"
+}
@@ -0,0 +1,487 @@
+package command
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+)
+
+func TestSelfUpdate(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	key := testApprovalKey(t)
+	_, pubPath := testApprovalKeyFiles(t, td, key)
+
+	ts := testReleaseServer(t, key, map[string]string{
+		"terraform":              "new terraform",
+		"terraform-provider-aws": "new aws",
+	})
+	defer ts.Close()
+
+	binDir := filepath.Join(td, "bin")
+	testWriteExecutable(t, filepath.Join(binDir, "terraform"), "old terraform")
+
+	ui := new(cli.MockUi)
+	c := &SelfUpdateCommand{
+		Meta:    Meta{Ui: ui},
+		Address: ts.URL + "/index.json",
+		KeyPath: pubPath,
+		Version: "0.2.0",
+		Dir:     binDir,
+	}
+
+	if code := c.Run(nil); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	for name, expected := range map[string]string{
+		"terraform":              "new terraform",
+		"terraform-provider-aws": "new aws",
+	} {
+		data, err := ioutil.ReadFile(filepath.Join(binDir, name))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if string(data) != expected {
+			t.Fatalf("bad %s: %q", name, data)
+		}
+	}
+
+	// No temporary files are left behind
+	infos, err := ioutil.ReadDir(binDir)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(infos) != 2 {
+		t.Fatalf("bad: %#v", infos)
+	}
+
+	if !strings.Contains(ui.OutputWriter.String(), "v0.2.0 to v0.3.0") {
+		t.Fatalf("bad: %s", ui.OutputWriter.String())
+	}
+}
+
+func TestSelfUpdate_badSignature(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	// The release is signed with a different key
+	_, pubPath := testApprovalKeyFiles(t, td, testApprovalKey(t))
+	ts := testReleaseServer(t, testApprovalKey(t), map[string]string{
+		"terraform": "new terraform",
+	})
+	defer ts.Close()
+
+	binDir := filepath.Join(td, "bin")
+	testWriteExecutable(t, filepath.Join(binDir, "terraform"), "old terraform")
+
+	ui := new(cli.MockUi)
+	c := &SelfUpdateCommand{
+		Meta:    Meta{Ui: ui},
+		Address: ts.URL + "/index.json",
+		KeyPath: pubPath,
+		Version: "0.2.0",
+		Dir:     binDir,
+	}
+
+	if code := c.Run(nil); code != 1 {
+		t.Fatalf("bad: %d", code)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "signature is invalid") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(binDir, "terraform"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if string(data) != "old terraform" {
+		t.Fatalf("bad: %q", data)
+	}
+	infos, err := ioutil.ReadDir(binDir)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(infos) != 1 {
+		t.Fatalf("bad: %#v", infos)
+	}
+}
+
+func TestSelfUpdate_badHash(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	key := testApprovalKey(t)
+	_, pubPath := testApprovalKeyFiles(t, td, key)
+
+	// The executable isn't the one the signed index was made for
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	mux.HandleFunc("/terraform", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("bad terraform"))
+	})
+	hash := sha256.Sum256([]byte("new terraform"))
+	testServeReleaseIndex(t, mux, key, &ReleaseIndex{
+		Version: "0.3.0",
+		Builds: map[string]map[string]*ReleaseBuild{
+			runtime.GOOS + "_" + runtime.GOARCH: map[string]*ReleaseBuild{
+				"terraform": &ReleaseBuild{
+					URL:    ts.URL + "/terraform",
+					SHA256: hex.EncodeToString(hash[:]),
+				},
+			},
+		},
+	})
+
+	binDir := filepath.Join(td, "bin")
+	testWriteExecutable(t, filepath.Join(binDir, "terraform"), "old terraform")
+
+	ui := new(cli.MockUi)
+	c := &SelfUpdateCommand{
+		Meta:    Meta{Ui: ui},
+		Address: ts.URL + "/index.json",
+		KeyPath: pubPath,
+		Version: "0.2.0",
+		Dir:     binDir,
+	}
+
+	if code := c.Run(nil); code != 1 {
+		t.Fatalf("bad: %d", code)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "hash doesn't match") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(binDir, "terraform"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if string(data) != "old terraform" {
+		t.Fatalf("bad: %q", data)
+	}
+}
+
+func TestSelfUpdate_downgrade(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	key := testApprovalKey(t)
+	_, pubPath := testApprovalKeyFiles(t, td, key)
+
+	ts := testReleaseServerVersion(t, key, "0.1.0", map[string]string{
+		"terraform": "old terraform",
+	})
+	defer ts.Close()
+
+	binDir := filepath.Join(td, "bin")
+	testWriteExecutable(t, filepath.Join(binDir, "terraform"), "terraform")
+
+	ui := new(cli.MockUi)
+	c := &SelfUpdateCommand{
+		Meta:    Meta{Ui: ui},
+		Address: ts.URL + "/index.json",
+		KeyPath: pubPath,
+		Version: "0.2.0",
+		Dir:     binDir,
+	}
+
+	if code := c.Run(nil); code != 1 {
+		t.Fatalf("bad: %d", code)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "-allow-downgrade") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+
+	ui = new(cli.MockUi)
+	c.Meta = Meta{Ui: ui}
+	if code := c.Run([]string{"-allow-downgrade"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(binDir, "terraform"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if string(data) != "old terraform" {
+		t.Fatalf("bad: %q", data)
+	}
+}
+
+func TestSelfUpdate_check(t *testing.T) {
+	ts := testReleaseServer(t, testApprovalKey(t), nil)
+	defer ts.Close()
+
+	ui := new(cli.MockUi)
+	c := &SelfUpdateCommand{
+		Meta:    Meta{Ui: ui},
+		Address: ts.URL + "/index.json",
+		Version: "0.2.0",
+	}
+
+	if code := c.Run([]string{"-check"}); code != 2 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if !strings.Contains(ui.OutputWriter.String(), "v0.3.0 is available") {
+		t.Fatalf("bad: %s", ui.OutputWriter.String())
+	}
+}
+
+func TestSelfUpdate_current(t *testing.T) {
+	ts := testReleaseServer(t, testApprovalKey(t), nil)
+	defer ts.Close()
+
+	ui := new(cli.MockUi)
+	c := &SelfUpdateCommand{
+		Meta:    Meta{Ui: ui},
+		Address: ts.URL + "/index.json",
+		Version: "0.3.0",
+	}
+
+	if code := c.Run(nil); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+}
+
+func TestSelfUpdate_noKey(t *testing.T) {
+	ts := testReleaseServer(t, testApprovalKey(t), nil)
+	defer ts.Close()
+
+	ui := new(cli.MockUi)
+	c := &SelfUpdateCommand{
+		Meta:    Meta{Ui: ui},
+		Address: ts.URL + "/index.json",
+		Version: "0.2.0",
+	}
+
+	if code := c.Run(nil); code != 1 {
+		t.Fatalf("bad: %d", code)
+	}
+}
+
+func TestFetchPlugin(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	key := testApprovalKey(t)
+	_, pubPath := testApprovalKeyFiles(t, td, key)
+
+	ts := testReleaseServer(t, key, map[string]string{
+		"terraform":              "terraform",
+		"terraform-provider-foo": "foo",
+	})
+	defer ts.Close()
+
+	path, err := FetchPlugin(ts.URL+"/index.json", pubPath, td, "terraform-provider-foo")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if path != filepath.Join(td, "terraform-provider-foo") {
+		t.Fatalf("bad: %s", path)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if string(data) != "foo" {
+		t.Fatalf("bad: %q", data)
+	}
+
+	// Only the plugin is installed
+	if _, err := os.Stat(filepath.Join(td, "terraform")); err == nil {
+		t.Fatal("terraform should not be installed")
+	}
+}
+
+func TestFetchPlugin_missing(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	key := testApprovalKey(t)
+	_, pubPath := testApprovalKeyFiles(t, td, key)
+
+	ts := testReleaseServer(t, key, map[string]string{
+		"terraform": "terraform",
+	})
+	defer ts.Close()
+
+	_, err := FetchPlugin(ts.URL+"/index.json", pubPath, td, "terraform-provider-foo")
+	if err == nil || !strings.Contains(err.Error(), "has no build of terraform-provider-foo") {
+		t.Fatalf("bad: %v", err)
+	}
+}
+
+func TestFetchPlugin_badSignature(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	// The release is signed with a different key
+	_, pubPath := testApprovalKeyFiles(t, td, testApprovalKey(t))
+	ts := testReleaseServer(t, testApprovalKey(t), map[string]string{
+		"terraform-provider-foo": "foo",
+	})
+	defer ts.Close()
+
+	_, err := FetchPlugin(ts.URL+"/index.json", pubPath, td, "terraform-provider-foo")
+	if err == nil || !strings.Contains(err.Error(), "signature is invalid") {
+		t.Fatalf("bad: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(td, "terraform-provider-foo")); err == nil {
+		t.Fatal("the plugin should not be installed")
+	}
+}
+
+func TestFetchPlugin_badHash(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	key := testApprovalKey(t)
+	_, pubPath := testApprovalKeyFiles(t, td, key)
+
+	// The index names another executable as the plugin
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	mux.HandleFunc("/terraform-provider-foo", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("bar"))
+	})
+	hash := sha256.Sum256([]byte("foo"))
+	testServeReleaseIndex(t, mux, key, &ReleaseIndex{
+		Version: "0.3.0",
+		Builds: map[string]map[string]*ReleaseBuild{
+			runtime.GOOS + "_" + runtime.GOARCH: map[string]*ReleaseBuild{
+				"terraform-provider-foo": &ReleaseBuild{
+					URL:    ts.URL + "/terraform-provider-foo",
+					SHA256: hex.EncodeToString(hash[:]),
+				},
+			},
+		},
+	})
+
+	_, err := FetchPlugin(ts.URL+"/index.json", pubPath, td, "terraform-provider-foo")
+	if err == nil || !strings.Contains(err.Error(), "hash doesn't match") {
+		t.Fatalf("bad: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(td, "terraform-provider-foo")); err == nil {
+		t.Fatal("the plugin should not be installed")
+	}
+}
+
+func TestReplaceExecutable(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	src := filepath.Join(td, "src")
+	dst := filepath.Join(td, "dst")
+	testWriteExecutable(t, src, "new")
+	testWriteExecutable(t, dst, "old")
+
+	if err := replaceExecutable(src, dst); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	data, err := ioutil.ReadFile(dst)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if string(data) != "new" {
+		t.Fatalf("bad: %q", data)
+	}
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Fatalf("src should be gone: %s", err)
+	}
+}
+
+// testReleaseServer serves a release index for version 0.3.0 with the
+// given executables for this platform, signed with the key.
+func testReleaseServer(
+	t *testing.T,
+	key *ecdsa.PrivateKey,
+	files map[string]string) *httptest.Server {
+	return testReleaseServerVersion(t, key, "0.3.0", files)
+}
+
+func testReleaseServerVersion(
+	t *testing.T,
+	key *ecdsa.PrivateKey,
+	version string,
+	files map[string]string) *httptest.Server {
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+
+	builds := make(map[string]*ReleaseBuild)
+	for name, contents := range files {
+		contents := contents
+		path := "/" + runtime.GOOS + "_" + runtime.GOARCH + "/" + name
+		hash := sha256.Sum256([]byte(contents))
+		builds[name] = &ReleaseBuild{
+			URL:    ts.URL + path,
+			SHA256: hex.EncodeToString(hash[:]),
+		}
+
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(contents))
+		})
+	}
+
+	testServeReleaseIndex(t, mux, key, &ReleaseIndex{
+		Version: version,
+		Builds: map[string]map[string]*ReleaseBuild{
+			runtime.GOOS + "_" + runtime.GOARCH: builds,
+		},
+	})
+
+	return ts
+}
+
+// testServeReleaseIndex serves the index at /index.json, with its
+// signature.
+func testServeReleaseIndex(
+	t *testing.T, mux *http.ServeMux, key *ecdsa.PrivateKey, index *ReleaseIndex) {
+	data, err := json.Marshal(index)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	hash := sha256.Sum256(data)
+	r, s, err := ecdsa.Sign(rand.Reader, key, hash[:])
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	sig, err := asn1.Marshal(ecdsaSignature{r, s})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	mux.HandleFunc("/index.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+	})
+	mux.HandleFunc("/index.json"+UpdateSignatureExtension,
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write(sig)
+		})
+}
+
+func testWriteExecutable(t *testing.T, path, contents string) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := ioutil.WriteFile(path, []byte(contents), 0755); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
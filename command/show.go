@@ -16,15 +16,29 @@ type ShowCommand struct {
 }
 
 func (c *ShowCommand) Run(args []string) int {
+	var diff bool
+
 	args = c.Meta.process(args, false)
 
 	cmdFlags := flag.NewFlagSet("show", flag.ContinueOnError)
+	cmdFlags.BoolVar(&diff, "diff", false, "diff")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		return 1
 	}
 
 	args = cmdFlags.Args()
+	if diff {
+		if len(args) != 2 {
+			c.Ui.Error(
+				"The show command expects exactly two arguments with -diff,\n" +
+					"the paths to the old and new plan or state files.\n")
+			cmdFlags.Usage()
+			return 1
+		}
+
+		return c.diff(c.path(args[0]), c.path(args[1]))
+	}
 	if len(args) != 1 {
 		c.Ui.Error(
 			"The show command expects exactly one argument with the path\n" +
@@ -32,23 +46,70 @@ func (c *ShowCommand) Run(args []string) int {
 		cmdFlags.Usage()
 		return 1
 	}
-	path := args[0]
+	path := c.path(args[0])
+
+	plan, state, ok := c.read(path)
+	if !ok {
+		return 1
+	}
+
+	if plan != nil {
+		c.Ui.Output(FormatPlan(plan, c.Colorize()))
+		return 0
+	}
+
+	c.Ui.Output(FormatState(state, c.Colorize()))
+	return 0
+}
+
+// diff outputs how the plan or state at the new path differs from the one
+// at the old path.
+func (c *ShowCommand) diff(oldPath, newPath string) int {
+	oldPlan, oldState, ok := c.read(oldPath)
+	if !ok {
+		return 1
+	}
+	newPlan, newState, ok := c.read(newPath)
+	if !ok {
+		return 1
+	}
+
+	switch {
+	case oldPlan != nil && newPlan != nil:
+		c.Ui.Output(FormatPlanDiff(oldPlan, newPlan, c.Colorize()))
+	case oldState != nil && newState != nil:
+		c.Ui.Output(FormatStateDiff(oldState, newState, c.Colorize()))
+	default:
+		c.Ui.Error(fmt.Sprintf(
+			"%s and %s can't be compared, since one is a plan and the\n"+
+				"other is a state. Both must be plans or both states.",
+			oldPath, newPath))
+		return 1
+	}
+
+	return 0
+}
 
+// read reads the plan or state file at the path, and returns the plan
+// or the state. Errors are output, and false is returned if the file
+// can't be read.
+func (c *ShowCommand) read(path string) (*terraform.Plan, *terraform.State, bool) {
 	var plan *terraform.Plan
 	var state *terraform.State
 
 	f, err := os.Open(path)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error loading file: %s", err))
-		return 1
+		return nil, nil, false
 	}
+	defer f.Close()
 
 	var planErr, stateErr error
 	plan, err = terraform.ReadPlan(f)
 	if err != nil {
 		if _, err := f.Seek(0, 0); err != nil {
 			c.Ui.Error(fmt.Sprintf("Error reading file: %s", err))
-			return 1
+			return nil, nil, false
 		}
 
 		plan = nil
@@ -68,27 +129,37 @@ func (c *ShowCommand) Run(args []string) int {
 				"State read error: %s\n\nPlan read error: %s",
 			stateErr,
 			planErr))
-		return 1
+		return nil, nil, false
 	}
 
+	// Showing a plan or state doesn't change it, so a newer version only
+	// gives a warning
 	if plan != nil {
-		c.Ui.Output(FormatPlan(plan, c.Colorize()))
-		return 0
+		c.checkVersion("plan "+path, plan.TFVersion)
+		c.addSensitive(plan.SensitiveValues())
+	} else {
+		c.checkVersion("state file "+path, state.TFVersion)
 	}
 
-	c.Ui.Output(FormatState(state, c.Colorize()))
-	return 0
+	return plan, state, true
 }
 
 func (c *ShowCommand) Help() string {
 	helpText := `
 Usage: terraform show [options] path
+       terraform show -diff [options] old new
 
   Reads and outputs a Terraform state or plan file in a human-readable
   form.
 
+  With -diff, shows how the new plan differs from the old one, such as
+  resources whose planned actions or attributes changed, or how the new
+  state differs from the old one.
+
 Options:
 
+  -diff         Compare two plans or two states.
+
   -no-color     If specified, output won't contain any color.
 
 `
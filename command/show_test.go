@@ -1,6 +1,7 @@
 package command
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform/config"
@@ -89,3 +90,119 @@ func TestShow_state(t *testing.T) {
 		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
 	}
 }
+
+func TestShow_diffPlans(t *testing.T) {
+	oldPath := testPlanFile(t, &terraform.Plan{
+		Config: new(config.Config),
+		Diff: &terraform.Diff{
+			Resources: map[string]*terraform.ResourceDiff{
+				"test_instance.foo": &terraform.ResourceDiff{Destroy: true},
+			},
+		},
+	})
+	newPath := testPlanFile(t, &terraform.Plan{
+		Config: new(config.Config),
+	})
+
+	ui := new(cli.MockUi)
+	c := &ShowCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-diff",
+		oldPath,
+		newPath,
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	output := ui.OutputWriter.String()
+	if !strings.Contains(output, "test_instance.foo: destroy (not in the new plan)") {
+		t.Fatalf("bad: \n%s", output)
+	}
+}
+
+func TestShow_diffStates(t *testing.T) {
+	oldPath := testStateFile(t, &terraform.State{
+		Resources: map[string]*terraform.ResourceState{
+			"test_instance.foo": &terraform.ResourceState{
+				ID:   "bar",
+				Type: "test_instance",
+			},
+		},
+	})
+	newPath := testStateFile(t, &terraform.State{})
+
+	ui := new(cli.MockUi)
+	c := &ShowCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-diff",
+		oldPath,
+		newPath,
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	output := ui.OutputWriter.String()
+	if !strings.Contains(output, "test_instance.foo (ID: bar)") {
+		t.Fatalf("bad: \n%s", output)
+	}
+}
+
+func TestShow_diffMixed(t *testing.T) {
+	planPath := testPlanFile(t, &terraform.Plan{
+		Config: new(config.Config),
+	})
+	statePath := testStateFile(t, &terraform.State{})
+
+	ui := new(cli.MockUi)
+	c := &ShowCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-diff",
+		planPath,
+		statePath,
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: \n%s", ui.OutputWriter.String())
+	}
+}
+
+func TestShow_diffArgs(t *testing.T) {
+	planPath := testPlanFile(t, &terraform.Plan{
+		Config: new(config.Config),
+	})
+
+	ui := new(cli.MockUi)
+	c := &ShowCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-diff",
+		planPath,
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: \n%s", ui.OutputWriter.String())
+	}
+}
@@ -0,0 +1,64 @@
+package command
+
+import (
+	"strings"
+)
+
+// StateCommand is a Command implementation that dispatches to the
+// subcommands used for maintaining a Terraform state file.
+type StateCommand struct {
+	Meta
+}
+
+func (c *StateCommand) Run(args []string) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "compact":
+			cmd := &StateCompactCommand{Meta: c.Meta}
+			return cmd.Run(args[1:])
+		case "history":
+			cmd := &StateHistoryCommand{Meta: c.Meta}
+			return cmd.Run(args[1:])
+		case "migrate":
+			cmd := &StateMigrateCommand{Meta: c.Meta}
+			return cmd.Run(args[1:])
+		case "mv":
+			cmd := &StateMvCommand{Meta: c.Meta}
+			return cmd.Run(args[1:])
+		case "pull":
+			cmd := &StatePullCommand{Meta: c.Meta}
+			return cmd.Run(args[1:])
+		case "push":
+			cmd := &StatePushCommand{Meta: c.Meta}
+			return cmd.Run(args[1:])
+		}
+	}
+
+	c.Ui.Error(c.Help())
+	return 1
+}
+
+func (c *StateCommand) Help() string {
+	helpText := `
+Usage: terraform state <subcommand> [options]
+
+  Performs maintenance on a Terraform state file. The state file is
+  normally managed entirely by Terraform, and these subcommands should
+  only be needed for cleaning up, migrating or sharing it.
+
+Subcommands:
+
+  compact    Remove unneeded data from a state file and rewrite it
+  history    List and output old versions of the state
+  migrate    Copy the state from one storage to another
+  mv         Rename a resource in the state file
+  pull       Output the state stored at a remote address
+  push       Store a local state file at a remote address
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *StateCommand) Synopsis() string {
+	return "Maintenance commands for the state file"
+}
@@ -0,0 +1,168 @@
+package command
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// StateCompactCommand is a Command implementation that rewrites a
+// state file with everything that doesn't need to be stored removed.
+type StateCompactCommand struct {
+	Meta
+}
+
+func (c *StateCompactCommand) Run(args []string) int {
+	var statePath, stateOutPath, backupPath, override string
+
+	args = c.Meta.process(args, false)
+	if c.refuseReadOnly("state compact") {
+		return 1
+	}
+	audit := c.startAudit("state compact", args)
+
+	cmdFlags := flag.NewFlagSet("state compact", flag.ContinueOnError)
+	cmdFlags.StringVar(&statePath, "state", DefaultStateFilename, "path")
+	cmdFlags.StringVar(&stateOutPath, "state-out", "", "path")
+	cmdFlags.StringVar(&c.message, "message", "", "message")
+	cmdFlags.StringVar(&backupPath, "backup", "", "path")
+	cmdFlags.StringVar(&override, "override", "", "reason")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		return 1
+	}
+
+	if len(cmdFlags.Args()) > 0 {
+		c.Ui.Error("The state compact command expects no arguments.")
+		cmdFlags.Usage()
+		return 1
+	}
+
+	// Paths are relative to the working directory
+	statePath = c.path(statePath)
+	stateOutPath = c.path(stateOutPath)
+	backupPath = c.path(backupPath)
+
+	// If we don't specify an output path, default to out normal state
+	// path.
+	if stateOutPath == "" {
+		stateOutPath = statePath
+	}
+
+	// If we don't specify a backup path, default to state out with
+	// the extension
+	if backupPath == "" {
+		backupPath = stateOutPath + DefaultBackupExtention
+	}
+
+	fi, err := os.Stat(statePath)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error loading state file: %s", err))
+		return 1
+	}
+	before := fi.Size()
+
+	f, err := os.Open(statePath)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error loading state file: %s", err))
+		return 1
+	}
+	state, err := terraform.ReadState(f)
+	f.Close()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading state: %s", err))
+		return 1
+	}
+
+	// A state written by a newer version of Terraform is only written if
+	// overridden.
+	c.refuseNewerVersion = override == ""
+	if override != "" {
+		audit.SetOverride(override, nil)
+	}
+	if err := c.checkVersion("state file "+statePath, state.TFVersion); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	// Create a backup of the state before compacting
+	if backupPath != "-" {
+		log.Printf("[INFO] Writing backup state to: %s", backupPath)
+		f, err := os.Create(backupPath)
+		if err == nil {
+			err = terraform.WriteState(state, f)
+			f.Close()
+		}
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error writing backup state file: %s", err))
+			return 1
+		}
+	}
+
+	state.Compact()
+
+	c.recordRun(state)
+
+	log.Printf("[INFO] Writing compacted state to: %s", stateOutPath)
+	if err := c.writeStateFile(stateOutPath, state); err != nil {
+		audit.Finish(stateOutPath, nil, err)
+		c.Ui.Error(fmt.Sprintf("Error writing state file: %s", err))
+		return 1
+	}
+	audit.Finish(stateOutPath, state, nil)
+
+	fi, err = os.Stat(stateOutPath)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading state file: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf(
+		"State compacted: %d bytes -> %d bytes", before, fi.Size()))
+	return 0
+}
+
+func (c *StateCompactCommand) Help() string {
+	helpText := `
+Usage: terraform state compact [options]
+
+  Rewrites a state file, removing resources that were never created,
+  stale taint markers, and empty fields. State files in older formats
+  are rewritten in the current format.
+
+  If the output path ends in ".gz", the state is compressed. Terraform
+  reads compressed state files transparently and keeps them compressed
+  when they are updated.
+
+Options:
+
+  -backup=path        Path to backup the existing state file before
+                      modifying. Defaults to the "-state-out" path with
+                      ".backup" extension. Set to "-" to disable backup.
+
+  -message=text       Why the command is run, recorded in the metadata of
+                      the state and in the audit log.
+
+  -no-color           If specified, output won't contain any color.
+
+  -override=reason    Change the state even if it was written by a newer
+                      version of Terraform. The reason is recorded in the
+                      audit log.
+
+  -state=path         Path to read and save state (unless state-out
+                      is specified). Defaults to "terraform.tfstate".
+
+  -state-out=path     Path to write the compacted state file. By default,
+                      the "-state" path will be used.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *StateCompactCommand) Synopsis() string {
+	return "Remove unneeded data from a state file"
+}
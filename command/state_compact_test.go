@@ -0,0 +1,225 @@
+package command
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+)
+
+func TestStateCommand_implements(t *testing.T) {
+	var _ cli.Command = &StateCommand{}
+}
+
+func TestStateCompact(t *testing.T) {
+	originalState := &terraform.State{
+		Resources: map[string]*terraform.ResourceState{
+			"test_instance.foo": &terraform.ResourceState{
+				ID:   "bar",
+				Type: "test_instance",
+			},
+			"test_instance.bar": &terraform.ResourceState{
+				Type: "test_instance",
+			},
+		},
+		Tainted: map[string]struct{}{
+			"test_instance.baz": struct{}{},
+		},
+	}
+	statePath := testStateFile(t, originalState)
+
+	ui := new(cli.MockUi)
+	c := &StateCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"compact",
+		"-state", statePath,
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	f, err := os.Open(statePath)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	state, err := terraform.ReadState(f)
+	f.Close()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := &terraform.State{
+		Resources: map[string]*terraform.ResourceState{
+			"test_instance.foo": &terraform.ResourceState{
+				ID:   "bar",
+				Type: "test_instance",
+			},
+		},
+		Serial:    1,
+		TFVersion: terraform.VersionString(),
+	}
+	if state.Metadata["run_id"] == "" {
+		t.Fatalf("bad: %#v", state.Metadata)
+	}
+	state.Metadata = nil
+	if state.Lineage == "" {
+		t.Fatal("state should have a lineage")
+	}
+	state.Lineage = ""
+	if !reflect.DeepEqual(state, expected) {
+		t.Fatalf("bad: %#v", state)
+	}
+
+	// Should have a backup of the original
+	f, err = os.Open(statePath + DefaultBackupExtention)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	backupState, err := terraform.ReadState(f)
+	f.Close()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !reflect.DeepEqual(backupState, originalState) {
+		t.Fatalf("bad: %#v", backupState)
+	}
+}
+
+func TestStateCompact_readOnly(t *testing.T) {
+	statePath := testStateFile(t, &terraform.State{})
+
+	ui := new(cli.MockUi)
+	c := &StateCompactCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			ReadOnly:    true,
+			Ui:          ui,
+		},
+	}
+
+	args := []string{"-state", statePath}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d", code)
+	}
+	if _, err := os.Stat(statePath + DefaultBackupExtention); err == nil {
+		t.Fatal("backup should not exist")
+	}
+}
+
+func TestStateCompact_newerState(t *testing.T) {
+	statePath := testNewerStateFile(t)
+
+	ui := new(cli.MockUi)
+	c := &StateCompactCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{"-state", statePath}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d", code)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "-override") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+	if _, err := os.Stat(statePath + DefaultBackupExtention); err == nil {
+		t.Fatal("backup should not exist")
+	}
+
+	ui = new(cli.MockUi)
+	c = &StateCompactCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+	args = append([]string{"-override", "downgrade"}, args...)
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+}
+
+func TestStateCompact_compressed(t *testing.T) {
+	originalState := &terraform.State{
+		Resources: map[string]*terraform.ResourceState{
+			"test_instance.foo": &terraform.ResourceState{
+				ID:   "bar",
+				Type: "test_instance",
+			},
+		},
+	}
+	statePath := testStateFile(t, originalState)
+	outPath := testTempFile(t) + CompressedStateExtension
+
+	ui := new(cli.MockUi)
+	c := &StateCompactCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"-state-out", outPath,
+		"-backup", "-",
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	raw, err := ioutil.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !bytes.HasPrefix(raw, []byte{0x1f, 0x8b}) {
+		t.Fatalf("state should be compressed: %q", raw)
+	}
+
+	state, err := terraform.ReadState(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	originalState.Serial++
+	if state.Metadata["run_id"] == "" {
+		t.Fatalf("bad: %#v", state.Metadata)
+	}
+	state.Metadata = nil
+	if state.Lineage == "" {
+		t.Fatal("state should have a lineage")
+	}
+	state.Lineage = ""
+	if !reflect.DeepEqual(state, originalState) {
+		t.Fatalf("bad: %#v", state)
+	}
+}
+
+func TestStateCompact_noState(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := &StateCompactCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-state", testTempFile(t),
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: \n%s", ui.OutputWriter.String())
+	}
+}
@@ -0,0 +1,106 @@
+package command
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform/state"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// StateHistoryCommand is a Command implementation that lists the old
+// versions of a state file that are kept, and outputs them.
+type StateHistoryCommand struct {
+	Meta
+}
+
+func (c *StateHistoryCommand) Run(args []string) int {
+	var statePath string
+
+	args = c.Meta.process(args, false)
+
+	cmdFlags := flag.NewFlagSet("state history", flag.ContinueOnError)
+	cmdFlags.StringVar(&statePath, "state", DefaultStateFilename, "path")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = cmdFlags.Args()
+	if len(args) > 1 {
+		c.Ui.Error("The state history command expects at most one argument: the\n" +
+			"ID of a version to output.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+
+	// Paths are relative to the working directory
+	statePath = c.path(statePath)
+	history := &state.LocalState{Path: statePath}
+
+	if len(args) == 1 {
+		s, err := history.Version(args[0])
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error reading version: %s", err))
+			return 1
+		}
+
+		var buf bytes.Buffer
+		if err := terraform.WriteState(s, &buf); err != nil {
+			c.Ui.Error(fmt.Sprintf("Error writing state: %s", err))
+			return 1
+		}
+
+		c.Ui.Output(strings.TrimSpace(buf.String()))
+		return 0
+	}
+
+	versions, err := history.Versions()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading the history: %s", err))
+		return 1
+	}
+	if len(versions) == 0 {
+		c.Ui.Output(fmt.Sprintf(
+			"No versions of %s are kept. Set state_keep_last or\n"+
+				"state_keep_daily_days in the CLI configuration to keep them.",
+			statePath))
+		return 0
+	}
+
+	for _, v := range versions {
+		c.Ui.Output(fmt.Sprintf(
+			"%s  serial %d  %s",
+			v.ID, v.Serial, v.Time.UTC().Format("2006-01-02 15:04:05 MST")))
+	}
+
+	return 0
+}
+
+func (c *StateHistoryCommand) Help() string {
+	helpText := `
+Usage: terraform state history [options] [ID]
+
+  Lists the old versions of a state file that are kept, newest first.
+  Given the ID of a version, outputs that version of the state, such
+  as to compare it with the current state.
+
+  Versions are only kept if state_keep_last or state_keep_daily_days is
+  set in the CLI configuration.
+
+Options:
+
+  -no-color           If specified, output won't contain any color.
+
+  -state=path         Path to the state file. Defaults to
+                      "terraform.tfstate".
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *StateHistoryCommand) Synopsis() string {
+	return "List and output old versions of the state"
+}
@@ -0,0 +1,111 @@
+package command
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/state"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+)
+
+func TestStateHistory(t *testing.T) {
+	statePath := testStateFile(t, &terraform.State{
+		Resources: map[string]*terraform.ResourceState{
+			"test_instance.foo": &terraform.ResourceState{
+				ID:   "bar",
+				Type: "test_instance",
+			},
+		},
+	})
+	defer os.RemoveAll(statePath + state.HistoryExtension)
+
+	// Versions are kept when the state is written
+	for _, args := range [][]string{
+		{"mv", "-state", statePath, "test_instance.foo", "test_instance.bar"},
+		{"mv", "-state", statePath, "test_instance.bar", "test_instance.baz"},
+	} {
+		ui := new(cli.MockUi)
+		c := &StateCommand{
+			Meta: Meta{
+				ContextOpts:    testCtxConfig(testProvider()),
+				Ui:             ui,
+				StateRetention: &state.Retention{KeepLast: 5},
+			},
+		}
+		if code := c.Run(args); code != 0 {
+			t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+		}
+	}
+
+	ui := new(cli.MockUi)
+	c := &StateCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+	if code := c.Run([]string{"history", "-state", statePath}); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(ui.OutputWriter.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("bad: %s", ui.OutputWriter.String())
+	}
+	if !strings.Contains(lines[0], "serial 2") || !strings.Contains(lines[1], "serial 1") {
+		t.Fatalf("bad: %s", ui.OutputWriter.String())
+	}
+
+	// The older version is output by its ID
+	id := strings.Fields(lines[1])[0]
+	ui = new(cli.MockUi)
+	c = &StateCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+	if code := c.Run([]string{"history", "-state", statePath, id}); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	actual, err := terraform.ReadState(strings.NewReader(ui.OutputWriter.String()))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, ok := actual.Resources["test_instance.bar"]; !ok || actual.Serial != 1 {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestStateHistory_none(t *testing.T) {
+	statePath := testStateFile(t, &terraform.State{})
+
+	ui := new(cli.MockUi)
+	c := &StateHistoryCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	if code := c.Run([]string{"-state", statePath}); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+	if !strings.Contains(ui.OutputWriter.String(), "No versions") {
+		t.Fatalf("bad: %s", ui.OutputWriter.String())
+	}
+
+	ui = new(cli.MockUi)
+	c = &StateHistoryCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+	if code := c.Run([]string{"-state", statePath, "20140720T120000Z-1"}); code != 1 {
+		t.Fatalf("bad: %d", code)
+	}
+}
@@ -0,0 +1,257 @@
+package command
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/state"
+)
+
+// StateMigrateCommand is a Command implementation that copies the state
+// from one storage to another, such as from a local file to an HTTP
+// address.
+type StateMigrateCommand struct {
+	Meta
+}
+
+// lockableState is a storage of the state that can be locked.
+type lockableState interface {
+	state.State
+	state.StateLocker
+}
+
+func (c *StateMigrateCommand) Run(args []string) int {
+	var force bool
+
+	args = c.Meta.process(args, false)
+	if c.refuseReadOnly("state migrate") {
+		return 1
+	}
+	audit := c.startAudit("state migrate", args)
+
+	cmdFlags := flag.NewFlagSet("state migrate", flag.ContinueOnError)
+	cmdFlags.BoolVar(&force, "force", false, "force")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = cmdFlags.Args()
+	if len(args) != 2 {
+		c.Ui.Error("The state migrate command expects exactly two arguments: the\n" +
+			"path or address of the state, and where to copy it to.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+
+	srcAddr, src, err := c.stateAt(args[0])
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	dstAddr, dst, err := c.stateAt(args[1])
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	if srcAddr == dstAddr {
+		c.Ui.Error("The state can't be migrated to where it already is.")
+		return 1
+	}
+
+	// Lock both sides, so that nothing changes either state while it's
+	// copied.
+	for _, l := range []struct {
+		Addr  string
+		State lockableState
+	}{
+		{srcAddr, src},
+		{dstAddr, dst},
+	} {
+		err := l.State.Lock(c.lockInfo())
+		if err == state.ErrLockNotSupported {
+			c.Ui.Output(c.Colorize().Color("[yellow]Warning: [reset]") +
+				fmt.Sprintf("the state at %s can't be locked. Make sure\n"+
+					"nothing else changes it during the migration.", l.Addr))
+			continue
+		}
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error locking the state: %s", err))
+			return 1
+		}
+
+		defer func(addr string, s lockableState) {
+			if err := s.Unlock(); err != nil {
+				c.Ui.Error(fmt.Sprintf(
+					"Error unlocking the state at %s: %s", addr, err))
+			}
+		}(l.Addr, l.State)
+	}
+
+	if err := src.RefreshState(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading state from %s: %s", srcAddr, err))
+		return 1
+	}
+	s := src.State()
+	if s == nil {
+		c.Ui.Error(fmt.Sprintf("There is no state at %s.", srcAddr))
+		return 1
+	}
+
+	if err := dst.RefreshState(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading state from %s: %s", dstAddr, err))
+		return 1
+	}
+	if existing := dst.State(); existing != nil {
+		same, err := sameStateContent(s, existing)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error comparing states: %s", err))
+			return 1
+		}
+		if same {
+			c.Ui.Output(fmt.Sprintf(
+				"The state at %s is already the same as at %s.", dstAddr, srcAddr))
+			return 0
+		}
+		if !force {
+			c.Ui.Error(fmt.Sprintf(
+				"There is already a different state at %s. Use -force to\n"+
+					"overwrite it.", dstAddr))
+			return 1
+		}
+	}
+
+	// The lineage is given before the checksum is taken, since it would
+	// otherwise be given when the copy is stored.
+	if err := s.InitLineage(); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	sum, err := stateChecksum(s)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error computing checksum: %s", err))
+		return 1
+	}
+
+	log.Printf("[INFO] Copying state from %s to %s", srcAddr, dstAddr)
+	if err := dst.WriteState(s); err == nil {
+		err = dst.PersistState()
+	}
+	if err != nil {
+		audit.Finish(dstAddr, nil, err)
+		c.Ui.Error(fmt.Sprintf("Error writing state to %s: %s", dstAddr, err))
+		return 1
+	}
+
+	// Read the copy back, to verify that it was stored intact
+	if err := dst.RefreshState(); err != nil {
+		audit.Finish(dstAddr, nil, err)
+		c.Ui.Error(fmt.Sprintf("Error reading state from %s: %s", dstAddr, err))
+		return 1
+	}
+	actual := ""
+	if copied := dst.State(); copied != nil {
+		actual, err = stateChecksum(copied)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error computing checksum: %s", err))
+			return 1
+		}
+	}
+	if actual != sum {
+		err := fmt.Errorf("checksum %s, expected %s", actual, sum)
+		audit.Finish(dstAddr, nil, err)
+		c.Ui.Error(fmt.Sprintf(
+			"The state stored at %s doesn't match the state at %s\n"+
+				"(%s). The state at %s is unchanged.",
+			dstAddr, srcAddr, err, srcAddr))
+		return 1
+	}
+	audit.Finish(dstAddr, s, nil)
+
+	c.Ui.Output(fmt.Sprintf(
+		"Migrated the state from %s to %s.\n\n"+
+			"SHA-256 checksum: %s\n\n"+
+			"The state at %s is unchanged. Remove it once nothing uses it,\n"+
+			"so that it isn't changed by mistake.",
+		srcAddr, dstAddr, sum, srcAddr))
+	return 0
+}
+
+func (c *StateMigrateCommand) Help() string {
+	helpText := `
+Usage: terraform state migrate [options] SOURCE DESTINATION
+
+  Copies the state from one storage to another. The source and
+  destination are each one of:
+
+    PATH                        A local state file.
+    http://HOST/PATH            An HTTP address, like with "state push".
+    webdav://HOST/PATH          A file on a WebDAV server or artifact
+                                store, written with PUT. Use
+                                webdavs:// for HTTPS. The credentials
+                                are read from the TF_WEBDAV_*
+                                environment variables.
+    etcd://HOST:PORT/KEY        A key in etcd, with the v2 API.
+    swift://CONTAINER/OBJECT    An object in OpenStack Swift. The
+                                credentials are read from the OS_*
+                                environment variables.
+
+  Both states are locked while the state is copied, and the copy is read
+  back to verify its checksum. The source state is left unchanged.
+
+Options:
+
+  -force              Overwrite the state at the destination if there
+                      is already a different state there.
+
+  -no-color           If specified, output won't contain any color.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *StateMigrateCommand) Synopsis() string {
+	return "Copy the state from one storage to another"
+}
+
+// stateAt returns the storage of the state at the given path or
+// address, along with the path or address it's at.
+func (c *StateMigrateCommand) stateAt(addr string) (string, lockableState, error) {
+	switch {
+	case strings.HasPrefix(addr, "http://"), strings.HasPrefix(addr, "https://"):
+		return addr, &state.HTTPState{Address: addr}, nil
+	case strings.HasPrefix(addr, "webdav://"), strings.HasPrefix(addr, "webdavs://"):
+		// The scheme is only used to choose the storage, and is otherwise
+		// the same as HTTP or HTTPS
+		u := "http" + strings.TrimPrefix(addr, "webdav")
+		return addr, &state.WebDAVState{Address: u}, nil
+	case strings.HasPrefix(addr, "etcd://"):
+		parts := strings.SplitN(strings.TrimPrefix(addr, "etcd://"), "/", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return "", nil, fmt.Errorf(
+				"%s: etcd addresses are in the form etcd://HOST:PORT/KEY", addr)
+		}
+
+		return addr, &state.EtcdState{
+			Address: "http://" + parts[0],
+			Path:    parts[1],
+		}, nil
+	case strings.HasPrefix(addr, "swift://"):
+		parts := strings.SplitN(strings.TrimPrefix(addr, "swift://"), "/", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return "", nil, fmt.Errorf(
+				"%s: Swift addresses are in the form swift://CONTAINER/OBJECT", addr)
+		}
+
+		return addr, &state.SwiftState{
+			Container: parts[0],
+			Object:    parts[1],
+		}, nil
+	}
+
+	// Paths are relative to the working directory
+	path := c.path(addr)
+	return path, &state.LocalState{Path: path}, nil
+}
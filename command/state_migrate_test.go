@@ -0,0 +1,230 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/state"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+)
+
+func TestStateMigrate(t *testing.T) {
+	statePath := testStateFile(t, &terraform.State{
+		Resources: map[string]*terraform.ResourceState{
+			"test_instance.foo": &terraform.ResourceState{
+				ID:   "bar",
+				Type: "test_instance",
+			},
+		},
+		Serial:  2,
+		Lineage: "abc",
+	})
+
+	ts := testStateServer(t, nil)
+	defer ts.Close()
+
+	ui := new(cli.MockUi)
+	c := &StateCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	if code := c.Run([]string{"migrate", statePath, ts.URL}); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+	if !strings.Contains(ui.OutputWriter.String(), "SHA-256 checksum: ") {
+		t.Fatalf("bad: %s", ui.OutputWriter.String())
+	}
+
+	actual := testStateServerState(t, ts)
+	if actual.Lineage != "abc" {
+		t.Fatalf("bad: %#v", actual)
+	}
+	if rs := actual.Resources["test_instance.foo"]; rs == nil || rs.ID != "bar" {
+		t.Fatalf("bad: %#v", actual.Resources)
+	}
+
+	// Both states are unlocked again
+	if _, err := os.Stat(statePath + state.LockExtension); !os.IsNotExist(err) {
+		t.Fatalf("lock should be removed: %s", err)
+	}
+	remote := &state.HTTPState{Address: ts.URL}
+	if err := remote.Lock("test"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestStateMigrate_toLocal(t *testing.T) {
+	ts := testStateServer(t, &terraform.State{
+		Outputs: map[string]string{"foo": "bar"},
+		Serial:  5,
+		Lineage: "abc",
+	})
+	defer ts.Close()
+
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+	statePath := filepath.Join(td, "terraform.tfstate.gz")
+
+	ui := new(cli.MockUi)
+	c := &StateMigrateCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	if code := c.Run([]string{ts.URL, statePath}); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	f, err := os.Open(statePath)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	actual, err := terraform.ReadState(f)
+	f.Close()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if actual.Lineage != "abc" || actual.Outputs["foo"] != "bar" {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestStateMigrate_existing(t *testing.T) {
+	statePath := testStateFile(t, &terraform.State{
+		Outputs: map[string]string{"foo": "bar"},
+		Lineage: "abc",
+	})
+
+	ts := testStateServer(t, &terraform.State{
+		Outputs: map[string]string{"foo": "baz"},
+		Lineage: "def",
+	})
+	defer ts.Close()
+
+	ui := new(cli.MockUi)
+	c := &StateMigrateCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	if code := c.Run([]string{statePath, ts.URL}); code != 1 {
+		t.Fatalf("bad: %d", code)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "already a different state") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+	if actual := testStateServerState(t, ts); actual.Outputs["foo"] != "baz" {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	// With -force, the state is overwritten
+	ui = new(cli.MockUi)
+	c = &StateMigrateCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	if code := c.Run([]string{"-force", statePath, ts.URL}); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+	if actual := testStateServerState(t, ts); actual.Outputs["foo"] != "bar" {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestStateMigrate_locked(t *testing.T) {
+	statePath := testStateFile(t, &terraform.State{
+		Outputs: map[string]string{"foo": "bar"},
+	})
+
+	ts := testStateServer(t, nil)
+	defer ts.Close()
+
+	remote := &state.HTTPState{Address: ts.URL}
+	if err := remote.Lock("alice@host"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	ui := new(cli.MockUi)
+	c := &StateMigrateCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	if code := c.Run([]string{statePath, ts.URL}); code != 1 {
+		t.Fatalf("bad: %d", code)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "alice@host") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+	if actual := testStateServerState(t, ts); actual != nil {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	// The source state was unlocked
+	if _, err := os.Stat(statePath + state.LockExtension); !os.IsNotExist(err) {
+		t.Fatalf("lock should be removed: %s", err)
+	}
+}
+
+func TestStateMigrate_noState(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	ts := testStateServer(t, nil)
+	defer ts.Close()
+
+	ui := new(cli.MockUi)
+	c := &StateMigrateCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{filepath.Join(td, "terraform.tfstate"), ts.URL}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d", code)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "There is no state") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+}
+
+func TestStateMigrate_badAddress(t *testing.T) {
+	cases := []string{
+		"etcd://127.0.0.1:2379",
+		"swift://terraform",
+	}
+
+	for _, addr := range cases {
+		ui := new(cli.MockUi)
+		c := &StateMigrateCommand{
+			Meta: Meta{
+				ContextOpts: testCtxConfig(testProvider()),
+				Ui:          ui,
+			},
+		}
+
+		if code := c.Run([]string{"terraform.tfstate", addr}); code != 1 {
+			t.Fatalf("%s: bad: %d", addr, code)
+		}
+		if !strings.Contains(ui.ErrorWriter.String(), "in the form") {
+			t.Fatalf("%s: bad: %s", addr, ui.ErrorWriter.String())
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package command
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// StateMvCommand is a Command implementation that renames resources in
+// a state file, so that they aren't destroyed and created again when
+// they're renamed in the configuration.
+type StateMvCommand struct {
+	Meta
+}
+
+func (c *StateMvCommand) Run(args []string) int {
+	var statePath, stateOutPath, backupPath, override string
+
+	args = c.Meta.process(args, false)
+	if c.refuseReadOnly("state mv") {
+		return 1
+	}
+	audit := c.startAudit("state mv", args)
+
+	cmdFlags := flag.NewFlagSet("state mv", flag.ContinueOnError)
+	cmdFlags.StringVar(&statePath, "state", DefaultStateFilename, "path")
+	cmdFlags.StringVar(&stateOutPath, "state-out", "", "path")
+	cmdFlags.StringVar(&c.message, "message", "", "message")
+	cmdFlags.StringVar(&backupPath, "backup", "", "path")
+	cmdFlags.StringVar(&override, "override", "", "reason")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = cmdFlags.Args()
+	if len(args) != 2 {
+		c.Ui.Error("The state mv command expects exactly two arguments: the\n" +
+			"current name of the resource and its new name.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+
+	// Paths are relative to the working directory
+	statePath = c.path(statePath)
+	stateOutPath = c.path(stateOutPath)
+	backupPath = c.path(backupPath)
+
+	// If we don't specify an output path, default to out normal state
+	// path.
+	if stateOutPath == "" {
+		stateOutPath = statePath
+	}
+
+	// If we don't specify a backup path, default to state out with
+	// the extension
+	if backupPath == "" {
+		backupPath = stateOutPath + DefaultBackupExtention
+	}
+
+	f, err := os.Open(statePath)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error loading state file: %s", err))
+		return 1
+	}
+	state, err := terraform.ReadState(f)
+	f.Close()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading state: %s", err))
+		return 1
+	}
+
+	// A state written by a newer version of Terraform is only written if
+	// overridden.
+	c.refuseNewerVersion = override == ""
+	if override != "" {
+		audit.SetOverride(override, nil)
+	}
+	if err := c.checkVersion("state file "+statePath, state.TFVersion); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	// Create a backup of the state before moving anything
+	if backupPath != "-" {
+		log.Printf("[INFO] Writing backup state to: %s", backupPath)
+		f, err := os.Create(backupPath)
+		if err == nil {
+			err = terraform.WriteState(state, f)
+			f.Close()
+		}
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error writing backup state file: %s", err))
+			return 1
+		}
+	}
+
+	moved, err := state.Move(args[0], args[1])
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error moving %s: %s", args[0], err))
+		return 1
+	}
+
+	c.recordRun(state)
+
+	log.Printf("[INFO] Writing state output to: %s", stateOutPath)
+	if err := c.writeStateFile(stateOutPath, state); err != nil {
+		audit.Finish(stateOutPath, nil, err)
+		c.Ui.Error(fmt.Sprintf("Error writing state file: %s", err))
+		return 1
+	}
+	audit.Finish(stateOutPath, state, nil)
+
+	names := make([]string, 0, len(moved))
+	for k, _ := range moved {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	for _, k := range names {
+		c.Ui.Output(fmt.Sprintf("Moved %s to %s", k, moved[k]))
+	}
+	return 0
+}
+
+func (c *StateMvCommand) Help() string {
+	helpText := `
+Usage: terraform state mv [options] SOURCE DESTINATION
+
+  Renames a resource in the state file, so that renaming it in the
+  configuration doesn't destroy it and create it again. Rename the
+  resource in the configuration, and then move it in the state to the
+  new name.
+
+  The names are in the form of TYPE.NAME, such as "aws_instance.web".
+  If SOURCE is a resource with a count, all its instances are moved.
+  A single instance can be moved with TYPE.NAME.INDEX.
+
+Options:
+
+  -backup=path        Path to backup the existing state file before
+                      modifying. Defaults to the "-state-out" path with
+                      ".backup" extension. Set to "-" to disable backup.
+
+  -message=text       Why the command is run, recorded in the metadata of
+                      the state and in the audit log.
+
+  -no-color           If specified, output won't contain any color.
+
+  -override=reason    Change the state even if it was written by a newer
+                      version of Terraform. The reason is recorded in the
+                      audit log.
+
+  -state=path         Path to read and save state (unless state-out
+                      is specified). Defaults to "terraform.tfstate".
+
+  -state-out=path     Path to write the updated state file. By default,
+                      the "-state" path will be used.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *StateMvCommand) Synopsis() string {
+	return "Rename a resource in the state file"
+}
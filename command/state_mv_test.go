@@ -0,0 +1,105 @@
+package command
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+)
+
+func TestStateMv(t *testing.T) {
+	statePath := testStateFile(t, &terraform.State{
+		Resources: map[string]*terraform.ResourceState{
+			"test_instance.foo": &terraform.ResourceState{
+				ID:   "bar",
+				Type: "test_instance",
+			},
+		},
+	})
+
+	ui := new(cli.MockUi)
+	c := &StateCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"mv",
+		"-state", statePath,
+		"test_instance.foo",
+		"test_instance.baz",
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+	if !strings.Contains(ui.OutputWriter.String(), "Moved test_instance.foo to test_instance.baz") {
+		t.Fatalf("bad: %s", ui.OutputWriter.String())
+	}
+
+	f, err := os.Open(statePath)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	state, err := terraform.ReadState(f)
+	f.Close()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, ok := state.Resources["test_instance.foo"]; ok {
+		t.Fatalf("bad: %#v", state.Resources)
+	}
+	if rs := state.Resources["test_instance.baz"]; rs == nil || rs.ID != "bar" {
+		t.Fatalf("bad: %#v", state.Resources)
+	}
+
+	// Should have a backup of the original
+	if _, err := os.Stat(statePath + DefaultBackupExtention); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestStateMv_notFound(t *testing.T) {
+	statePath := testStateFile(t, &terraform.State{})
+
+	ui := new(cli.MockUi)
+	c := &StateMvCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"test_instance.foo",
+		"test_instance.baz",
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d", code)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "not in the state") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+}
+
+func TestStateMv_readOnly(t *testing.T) {
+	statePath := testStateFile(t, &terraform.State{})
+
+	ui := new(cli.MockUi)
+	c := &StateMvCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			ReadOnly:    true,
+			Ui:          ui,
+		},
+	}
+
+	args := []string{"-state", statePath, "test_instance.foo", "test_instance.baz"}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d", code)
+	}
+}
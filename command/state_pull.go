@@ -0,0 +1,69 @@
+package command
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform/state"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// StatePullCommand is a Command implementation that reads the state
+// stored at a remote address and outputs it.
+type StatePullCommand struct {
+	Meta
+}
+
+func (c *StatePullCommand) Run(args []string) int {
+	args = c.Meta.process(args, false)
+
+	cmdFlags := flag.NewFlagSet("state pull", flag.ContinueOnError)
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = cmdFlags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("The state pull command expects exactly one argument: the\n" +
+			"address of the remote state.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+
+	remote := &state.HTTPState{Address: args[0]}
+	if err := remote.RefreshState(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading remote state: %s", err))
+		return 1
+	}
+	if remote.State() == nil {
+		c.Ui.Error(fmt.Sprintf("There is no state at %s.", args[0]))
+		return 1
+	}
+
+	var buf bytes.Buffer
+	if err := terraform.WriteState(remote.State(), &buf); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error writing state: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(strings.TrimSpace(buf.String()))
+	return 0
+}
+
+func (c *StatePullCommand) Help() string {
+	helpText := `
+Usage: terraform state pull ADDRESS
+
+  Reads the state stored at the HTTP address and outputs it, such as to
+  inspect it or to save it as a local state file.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *StatePullCommand) Synopsis() string {
+	return "Output the state stored at a remote address"
+}
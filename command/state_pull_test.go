@@ -0,0 +1,133 @@
+package command
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform/state"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+)
+
+func TestStatePull(t *testing.T) {
+	ts := testStateServer(t, &terraform.State{
+		Resources: map[string]*terraform.ResourceState{
+			"test_instance.foo": &terraform.ResourceState{
+				ID:   "bar",
+				Type: "test_instance",
+			},
+		},
+		Serial:  3,
+		Lineage: "abc",
+	})
+	defer ts.Close()
+
+	ui := new(cli.MockUi)
+	c := &StateCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	if code := c.Run([]string{"pull", ts.URL}); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	actual, err := terraform.ReadState(strings.NewReader(ui.OutputWriter.String()))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if actual.Serial != 3 || actual.Lineage != "abc" {
+		t.Fatalf("bad: %#v", actual)
+	}
+	if rs := actual.Resources["test_instance.foo"]; rs == nil || rs.ID != "bar" {
+		t.Fatalf("bad: %#v", actual.Resources)
+	}
+}
+
+func TestStatePull_noState(t *testing.T) {
+	ts := testStateServer(t, nil)
+	defer ts.Close()
+
+	ui := new(cli.MockUi)
+	c := &StatePullCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	if code := c.Run([]string{ts.URL}); code != 1 {
+		t.Fatalf("bad: %d", code)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "There is no state") {
+		t.Fatalf("bad: %s", ui.ErrorWriter.String())
+	}
+}
+
+// testStateServer starts a server that stores a state like an HTTP
+// backend: GET returns it, or 404 if there is none, and POST replaces
+// it. LOCK and UNLOCK requests lock the state.
+func testStateServer(t *testing.T, s *terraform.State) *httptest.Server {
+	var lock sync.Mutex
+	var data, holder []byte
+	if s != nil {
+		var buf bytes.Buffer
+		if err := terraform.WriteState(s, &buf); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		data = buf.Bytes()
+	}
+
+	return httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			switch r.Method {
+			case "GET":
+				if data == nil {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				w.Write(data)
+			case "POST":
+				body, err := ioutil.ReadAll(r.Body)
+				if err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+
+				data = body
+			case "LOCK":
+				if holder != nil {
+					w.WriteHeader(http.StatusLocked)
+					w.Write(holder)
+					return
+				}
+
+				holder, _ = ioutil.ReadAll(r.Body)
+			case "UNLOCK":
+				holder = nil
+			default:
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		}))
+}
+
+// testStateServerState returns the state stored by a testStateServer.
+func testStateServerState(t *testing.T, ts *httptest.Server) *terraform.State {
+	remote := &state.HTTPState{Address: ts.URL}
+	if err := remote.RefreshState(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return remote.State()
+}
@@ -0,0 +1,210 @@
+package command
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform/state"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// StatePushCommand is a Command implementation that stores a local
+// state file at a remote address, refusing to overwrite a remote state
+// that has diverged from it.
+type StatePushCommand struct {
+	Meta
+}
+
+func (c *StatePushCommand) Run(args []string) int {
+	var statePath string
+	var force bool
+
+	args = c.Meta.process(args, false)
+	if c.refuseReadOnly("state push") {
+		return 1
+	}
+	audit := c.startAudit("state push", args)
+
+	cmdFlags := flag.NewFlagSet("state push", flag.ContinueOnError)
+	cmdFlags.StringVar(&statePath, "state", DefaultStateFilename, "path")
+	cmdFlags.BoolVar(&force, "force", false, "force")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = cmdFlags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("The state push command expects exactly one argument: the\n" +
+			"address to store the state at.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+	address := args[0]
+
+	// Paths are relative to the working directory
+	statePath = c.path(statePath)
+
+	f, err := os.Open(statePath)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error loading state file: %s", err))
+		return 1
+	}
+	local, err := terraform.ReadState(f)
+	f.Close()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading state: %s", err))
+		return 1
+	}
+
+	// A state written before lineages were recorded is given one, and
+	// saved, so that the next push of it can be checked against the
+	// remote state pushed now.
+	if local.Lineage == "" {
+		log.Printf("[INFO] Giving the state in %s a lineage", statePath)
+		if err := c.writeStateFile(statePath, local); err != nil {
+			c.Ui.Error(fmt.Sprintf("Error writing state file: %s", err))
+			return 1
+		}
+	}
+
+	remote := &state.HTTPState{Address: address}
+	if err := remote.RefreshState(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading remote state: %s", err))
+		return 1
+	}
+
+	if current := remote.State(); current != nil {
+		same, err := sameStateContent(local, current)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error comparing states: %s", err))
+			return 1
+		}
+		if same {
+			c.Ui.Output(fmt.Sprintf(
+				"The state at %s is already up to date.", address))
+			return 0
+		}
+
+		if err := checkStatePush(local, current); err != nil {
+			if !force {
+				c.Ui.Error(fmt.Sprintf(
+					"Refusing to push the state to %s: %s\n\n"+
+						"Pushing it would overwrite changes that aren't in the local\n"+
+						"state. Pull the remote state to see them, or use -force to\n"+
+						"overwrite it anyway.", address, err))
+				return 1
+			}
+
+			c.Ui.Output(c.Colorize().Color("[yellow]Warning: [reset]") +
+				fmt.Sprintf("overwriting the state at %s: %s", address, err))
+		}
+	}
+
+	log.Printf("[INFO] Pushing state to: %s", address)
+	if err := remote.WriteState(local); err == nil {
+		err = remote.PersistState()
+	}
+	if err != nil {
+		audit.Finish(address, nil, err)
+		c.Ui.Error(fmt.Sprintf("Error pushing state: %s", err))
+		return 1
+	}
+	audit.Finish(address, local, nil)
+
+	c.Ui.Output(fmt.Sprintf(
+		"Pushed the state to %s (serial %d).", address, local.Serial))
+	return 0
+}
+
+func (c *StatePushCommand) Help() string {
+	helpText := `
+Usage: terraform state push [options] ADDRESS
+
+  Stores a local state file at the HTTP address, so that it can be
+  shared, such as with the terraform_remote_state resource.
+
+  The push is refused if the remote state has diverged from the local
+  state: if it has a different lineage, so it's the state of other
+  infrastructure, or a newer serial, so it has changes that aren't in
+  the local state.
+
+Options:
+
+  -force              Push the state even if the remote state has
+                      diverged from it, overwriting the remote state.
+
+  -no-color           If specified, output won't contain any color.
+
+  -state=path         Path to the state file to push. Defaults to
+                      "terraform.tfstate".
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *StatePushCommand) Synopsis() string {
+	return "Store a local state file at a remote address"
+}
+
+// checkStatePush returns an error if the remote state has diverged from
+// the local state, so that pushing the local state would lose changes.
+// States written before lineages were recorded are only compared by
+// their serial.
+func checkStatePush(local, remote *terraform.State) error {
+	if local.Lineage != "" && remote.Lineage != "" &&
+		local.Lineage != remote.Lineage {
+		return fmt.Errorf(
+			"the remote state has lineage %s, but the local state has\n"+
+				"lineage %s, so they aren't versions of the same state",
+			remote.Lineage, local.Lineage)
+	}
+
+	if remote.Serial > local.Serial {
+		return fmt.Errorf(
+			"the remote state has serial %d, which is newer than the\n"+
+				"local state's serial %d",
+			remote.Serial, local.Serial)
+	}
+
+	return nil
+}
+
+// sameStateContent returns true if the two states are the same but for
+// their serial, such as when a state is pushed again without changes,
+// since storing a state increments its serial.
+func sameStateContent(a, b *terraform.State) (bool, error) {
+	aSum, err := stateChecksum(a)
+	if err != nil {
+		return false, err
+	}
+	bSum, err := stateChecksum(b)
+	if err != nil {
+		return false, err
+	}
+
+	return aSum == bSum, nil
+}
+
+// stateChecksum returns the SHA-256 checksum of the state as it's
+// written, but without its serial, so that copies of a state stored in
+// different places have the same checksum.
+func stateChecksum(s *terraform.State) (string, error) {
+	serial := s.Serial
+	s.Serial = 0
+	defer func() {
+		s.Serial = serial
+	}()
+
+	h := sha256.New()
+	if err := terraform.WriteState(s, h); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
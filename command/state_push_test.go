@@ -0,0 +1,193 @@
+package command
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+)
+
+func TestStatePush(t *testing.T) {
+	statePath := testStateFile(t, &terraform.State{
+		Resources: map[string]*terraform.ResourceState{
+			"test_instance.foo": &terraform.ResourceState{
+				ID:   "bar",
+				Type: "test_instance",
+			},
+		},
+		Serial:  4,
+		Lineage: "abc",
+	})
+
+	ts := testStateServer(t, &terraform.State{
+		Serial:  3,
+		Lineage: "abc",
+	})
+	defer ts.Close()
+
+	ui := new(cli.MockUi)
+	c := &StateCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{"push", "-state", statePath, ts.URL}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	actual := testStateServerState(t, ts)
+	if actual.Serial != 5 || actual.Lineage != "abc" {
+		t.Fatalf("bad: %#v", actual)
+	}
+	if rs := actual.Resources["test_instance.foo"]; rs == nil || rs.ID != "bar" {
+		t.Fatalf("bad: %#v", actual.Resources)
+	}
+
+	// Pushing the same state again has nothing to do
+	ui = new(cli.MockUi)
+	c = &StateCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+	if !strings.Contains(ui.OutputWriter.String(), "already up to date") {
+		t.Fatalf("bad: %s", ui.OutputWriter.String())
+	}
+	if actual := testStateServerState(t, ts); actual.Serial != 5 {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestStatePush_noRemoteState(t *testing.T) {
+	statePath := testStateFile(t, &terraform.State{
+		Serial:  1,
+		Lineage: "abc",
+	})
+
+	ts := testStateServer(t, nil)
+	defer ts.Close()
+
+	ui := new(cli.MockUi)
+	c := &StatePushCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	if code := c.Run([]string{"-state", statePath, ts.URL}); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+	if actual := testStateServerState(t, ts); actual == nil || actual.Lineage != "abc" {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestStatePush_diverged(t *testing.T) {
+	cases := []struct {
+		Name   string
+		Remote *terraform.State
+	}{
+		{
+			"lineage",
+			&terraform.State{Serial: 1, Lineage: "def"},
+		},
+		{
+			"serial",
+			&terraform.State{Serial: 5, Lineage: "abc"},
+		},
+	}
+
+	for _, tc := range cases {
+		statePath := testStateFile(t, &terraform.State{
+			Outputs: map[string]string{"foo": "bar"},
+			Serial:  4,
+			Lineage: "abc",
+		})
+
+		ts := testStateServer(t, tc.Remote)
+
+		ui := new(cli.MockUi)
+		c := &StatePushCommand{
+			Meta: Meta{
+				ContextOpts: testCtxConfig(testProvider()),
+				Ui:          ui,
+			},
+		}
+
+		if code := c.Run([]string{"-state", statePath, ts.URL}); code != 1 {
+			t.Fatalf("%s: bad: %d", tc.Name, code)
+		}
+		if !strings.Contains(ui.ErrorWriter.String(), "Refusing to push") {
+			t.Fatalf("%s: bad: %s", tc.Name, ui.ErrorWriter.String())
+		}
+		if actual := testStateServerState(t, ts); actual.Serial != tc.Remote.Serial {
+			t.Fatalf("%s: remote state changed: %#v", tc.Name, actual)
+		}
+
+		// With -force, the remote state is overwritten
+		ui = new(cli.MockUi)
+		c = &StatePushCommand{
+			Meta: Meta{
+				ContextOpts: testCtxConfig(testProvider()),
+				Ui:          ui,
+			},
+		}
+
+		args := []string{"-force", "-state", statePath, ts.URL}
+		if code := c.Run(args); code != 0 {
+			t.Fatalf("%s: bad: \n%s", tc.Name, ui.ErrorWriter.String())
+		}
+		actual := testStateServerState(t, ts)
+		if actual.Lineage != "abc" || actual.Outputs["foo"] != "bar" {
+			t.Fatalf("%s: bad: %#v", tc.Name, actual)
+		}
+
+		ts.Close()
+	}
+}
+
+func TestStatePush_noLineage(t *testing.T) {
+	statePath := testStateFile(t, &terraform.State{Serial: 2})
+
+	ts := testStateServer(t, nil)
+	defer ts.Close()
+
+	ui := new(cli.MockUi)
+	c := &StatePushCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	if code := c.Run([]string{"-state", statePath, ts.URL}); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	// The local state is given a lineage that the remote state shares
+	f, err := os.Open(statePath)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	local, err := terraform.ReadState(f)
+	f.Close()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if local.Lineage == "" {
+		t.Fatal("local state should have a lineage")
+	}
+	if actual := testStateServerState(t, ts); actual.Lineage != local.Lineage {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
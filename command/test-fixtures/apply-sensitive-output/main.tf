@@ -0,0 +1,8 @@
+resource "test_instance" "foo" {
+    ami = "bar"
+}
+
+output "ami" {
+    value = "${test_instance.foo.ami}"
+    sensitive = true
+}
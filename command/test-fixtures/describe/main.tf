@@ -0,0 +1,5 @@
+resource "test_instance" "foo" {
+    count = 2
+    ami = "${var.ami}"
+    flavor = "small"
+}
@@ -0,0 +1 @@
+resource "nope_instance" "foo" {}
@@ -0,0 +1,7 @@
+resource "test_instance" "foo" {
+    ami = "bar"
+
+    provisioner "shell" {
+        command = "echo hello"
+    }
+}
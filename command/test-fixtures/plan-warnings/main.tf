@@ -0,0 +1,7 @@
+variable "unused" {
+    default = "foo"
+}
+
+resource "test_instance" "foo" {
+    ami = "bar"
+}
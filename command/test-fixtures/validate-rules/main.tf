@@ -0,0 +1,20 @@
+resource "test_instance" "foo" {
+    ami = "bar"
+
+    tags {
+        Name = "foo"
+    }
+}
+
+resource "test_instance" "Bar" {
+    ami = "baz"
+
+    tags {
+        Name = "bar"
+        Owner = "ops"
+    }
+}
+
+resource "test_volume" "baz" {
+    size = 10
+}
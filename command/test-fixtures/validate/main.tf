@@ -0,0 +1,8 @@
+resource "test_instance" "foo" {
+    ami = "bar"
+
+    tags {
+        Name = "foo"
+        Owner = "ops"
+    }
+}
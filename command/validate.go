@@ -0,0 +1,188 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform/config"
+)
+
+// ValidateCommand is a Command implementation that validates a Terraform
+// configuration, along with the rules it must follow, without refreshing
+// or planning anything.
+type ValidateCommand struct {
+	Meta
+}
+
+// ValidateResult is what the validate command outputs with -json.
+type ValidateResult struct {
+	Valid        bool                  `json:"valid"`
+	ErrorCount   int                   `json:"error_count"`
+	WarningCount int                   `json:"warning_count"`
+	Diagnostics  []*ValidateDiagnostic `json:"diagnostics"`
+}
+
+// ValidateDiagnostic is an error or warning in the output of the
+// validate command with -json. File and Line are where in the
+// configuration it is, if known, and Line is zero if only the file is
+// known.
+type ValidateDiagnostic struct {
+	Severity string `json:"severity"`
+	Code     string `json:"code"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Resource string `json:"resource,omitempty"`
+	Summary  string `json:"summary"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+func (c *ValidateCommand) Run(args []string) int {
+	var jsonOutput bool
+
+	args = c.Meta.process(args, true)
+
+	cmdFlags := c.Meta.flagSet("validate")
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		return 1
+	}
+
+	var path string
+	args = cmdFlags.Args()
+	if len(args) > 1 {
+		c.Ui.Error(
+			"The validate command expects at most one argument with the\n" +
+				"path to a Terraform configuration.\n")
+		cmdFlags.Usage()
+		return 1
+	} else if len(args) == 1 {
+		path = c.path(args[0])
+	} else {
+		var err error
+		path, err = c.wd()
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error getting pwd: %s", err))
+		}
+	}
+
+	if jsonOutput {
+		return c.outputJSON(c.diagnostics(path))
+	}
+
+	ctx, planned, err := c.Context(path, "")
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	if planned {
+		c.Ui.Error("The validate command expects a configuration, not a plan.")
+		return 1
+	}
+	if !c.validateContext(ctx) {
+		return 1
+	}
+
+	c.Ui.Output(c.Colorize().Color("[reset][green]The configuration is valid."))
+	return 0
+}
+
+// diagnostics validates the configuration at path like Run, but returns
+// everything it finds as diagnostics.
+func (c *ValidateCommand) diagnostics(path string) []*config.Diagnostic {
+	// The configuration is loaded and validated here first, since the
+	// errors from that are otherwise only returned by Context as text.
+	conf, err := config.LoadDir(path)
+	if err != nil {
+		return config.Diagnostics(err, "load")
+	}
+	if ds := config.Diagnostics(conf.Validate(), "config"); len(ds) > 0 {
+		return ds
+	}
+	c.Configuration = conf
+
+	ctx, _, err := c.Context(path, "")
+	if err != nil {
+		return config.Diagnostics(err, "load")
+	}
+
+	return c.contextDiagnostics(ctx)
+}
+
+// outputJSON outputs the diagnostics as a ValidateResult, returning the
+// exit status of the command.
+func (c *ValidateCommand) outputJSON(ds []*config.Diagnostic) int {
+	result := &ValidateResult{
+		Diagnostics: make([]*ValidateDiagnostic, len(ds)),
+	}
+	for i, d := range ds {
+		if d.Severity == config.DiagWarning {
+			result.WarningCount++
+		} else {
+			result.ErrorCount++
+		}
+
+		result.Diagnostics[i] = &ValidateDiagnostic{
+			Severity: string(d.Severity),
+			Code:     d.Code,
+			File:     d.Pos.Filename,
+			Line:     d.Pos.Line,
+			Resource: d.Resource,
+			Summary:  d.Summary,
+			Detail:   d.Detail,
+		}
+	}
+	result.Valid = result.ErrorCount == 0 &&
+		!(c.strict && result.WarningCount > 0)
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error encoding diagnostics: %s", err))
+		return 1
+	}
+	c.Ui.Output(string(data))
+
+	if !result.Valid {
+		return 1
+	}
+
+	return 0
+}
+
+func (c *ValidateCommand) Help() string {
+	helpText := `
+Usage: terraform validate [options] [dir]
+
+  Validates the Terraform configuration in the given directory, or the
+  current directory, without refreshing or planning anything.
+
+  Along with the configuration itself, the resources are checked against
+  the rules in the "policy" blocks of the CLI configuration and of the
+  ".tfpolicy" files in the directory, such as naming conventions and
+  required tags.
+
+Options:
+
+  -json               Output the errors and warnings as JSON, with where
+                      in the configuration each one is, for editors and
+                      other tools.
+
+  -no-color           If specified, output won't contain any color.
+
+  -strict             Treat warnings about the configuration as errors.
+
+  -var 'foo=bar'      Set a variable in the Terraform configuration. This
+                      flag can be set multiple times.
+
+  -var-file=foo       Set variables in the Terraform configuration from
+                      a file. If "terraform.tfvars" is present, it will be
+                      automatically loaded if this flag is not specified.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ValidateCommand) Synopsis() string {
+	return "Validates the Terraform configuration"
+}
@@ -0,0 +1,220 @@
+package command
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+)
+
+func TestValidate(t *testing.T) {
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &ValidateCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{testFixturePath("validate")}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if !p.ValidateResourceCalled {
+		t.Fatal("validate should be called")
+	}
+	if !strings.Contains(ui.OutputWriter.String(), "is valid") {
+		t.Fatalf("bad: %s", ui.OutputWriter.String())
+	}
+}
+
+func TestValidate_rules(t *testing.T) {
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &ValidateCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{testFixturePath("validate-rules")}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	output := ui.OutputWriter.String()
+	expected := []string{
+		`resource 'test_instance.foo': policy tags: missing required tag "Owner"`,
+		`resource 'test_instance.Bar': policy naming: name must match`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Fatalf("bad: %s", output)
+		}
+	}
+}
+
+func TestValidate_plan(t *testing.T) {
+	planPath := testPlanFile(t, &terraform.Plan{
+		Config: new(config.Config),
+	})
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &ValidateCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{planPath}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+}
+
+func TestValidate_json(t *testing.T) {
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &ValidateCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{"-json", testFixturePath("validate")}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	result := testValidateResult(t, ui)
+	if !result.Valid || result.ErrorCount != 0 || len(result.Diagnostics) != 0 {
+		t.Fatalf("bad: %#v", result)
+	}
+}
+
+func TestValidate_jsonRules(t *testing.T) {
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &ValidateCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{"-json", testFixturePath("validate-rules")}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	result := testValidateResult(t, ui)
+	if result.Valid || result.ErrorCount == 0 {
+		t.Fatalf("bad: %#v", result)
+	}
+
+	lines := map[string]int{
+		"test_instance.foo": 1,
+		"test_instance.Bar": 9,
+	}
+	var count int
+	for _, d := range result.Diagnostics {
+		if d.Code != "policy" {
+			continue
+		}
+
+		count++
+		if d.Severity != "error" {
+			t.Fatalf("bad: %#v", d)
+		}
+		if filepath.Base(d.File) != "main.tf" || d.Line != lines[d.Resource] {
+			t.Fatalf("bad: %#v", d)
+		}
+		if strings.Contains(d.Summary, d.Resource) {
+			t.Fatalf("summary shouldn't repeat the resource: %#v", d)
+		}
+	}
+	if count != 2 {
+		t.Fatalf("bad: %s", ui.OutputWriter.String())
+	}
+}
+
+func TestValidate_jsonConfigInvalid(t *testing.T) {
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &ValidateCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{"-json", testFixturePath("apply-config-invalid")}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	result := testValidateResult(t, ui)
+	if result.Valid || len(result.Diagnostics) != 1 {
+		t.Fatalf("bad: %#v", result)
+	}
+	d := result.Diagnostics[0]
+	if d.Code != "unknown_variable" || d.Line != 1 ||
+		filepath.Base(d.File) != "main.tf" {
+		t.Fatalf("bad: %#v", d)
+	}
+}
+
+func TestValidate_jsonParseError(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	err := ioutil.WriteFile(
+		filepath.Join(td, "main.tf"),
+		[]byte("resource \"test_instance\" \"foo\" {\n    ami = \n"), 0644)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	c := &ValidateCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{"-json", td}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	result := testValidateResult(t, ui)
+	if result.Valid || len(result.Diagnostics) != 1 {
+		t.Fatalf("bad: %#v", result)
+	}
+	d := result.Diagnostics[0]
+	if d.Code != "parse" || d.File != filepath.Join(td, "main.tf") || d.Line == 0 {
+		t.Fatalf("bad: %#v", d)
+	}
+}
+
+func testValidateResult(t *testing.T, ui *cli.MockUi) *ValidateResult {
+	var result ValidateResult
+	if err := json.Unmarshal(ui.OutputWriter.Bytes(), &result); err != nil {
+		t.Fatalf("err: %s\n\n%s", err, ui.OutputWriter.String())
+	}
+
+	return &result
+}
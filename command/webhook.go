@@ -0,0 +1,175 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+)
+
+// Webhook is an HTTP endpoint that is notified when an apply starts,
+// completes and fails. Webhooks are configured with "webhook" blocks in
+// the CLI configuration.
+type Webhook struct {
+	// URL is where notifications are POSTed.
+	URL string
+
+	// Format is "slack" to send messages in the format of Slack incoming
+	// webhooks, or "json" to send the WebhookEvent itself. It defaults
+	// to "json".
+	Format string
+}
+
+// WebhookEvent is a notification sent to webhooks.
+type WebhookEvent struct {
+	// Event is one of "apply_start", "apply_complete" or "apply_failed".
+	Event string `json:"event"`
+
+	Time    time.Time  `json:"time"`
+	User    string     `json:"user"`
+	Host    string     `json:"host"`
+	Dir     string     `json:"dir"`
+	Changes *AuditPlan `json:"changes"`
+	Error   string     `json:"error,omitempty"`
+}
+
+// Text returns a message for humans about the event, as sent to Slack.
+func (e *WebhookEvent) Text() string {
+	var what string
+	switch e.Event {
+	case "apply_start":
+		what = "started"
+	case "apply_complete":
+		what = "complete"
+	case "apply_failed":
+		what = "failed"
+	default:
+		what = e.Event
+	}
+
+	result := fmt.Sprintf(
+		"Terraform apply %s in %s (%s@%s)", what, e.Dir, e.User, e.Host)
+	if e.Changes != nil {
+		result += fmt.Sprintf(
+			": %d to add, %d to change, %d to destroy",
+			e.Changes.Add, e.Changes.Change, e.Changes.Destroy)
+	}
+	if e.Error != "" {
+		result += "\n" + e.Error
+	}
+
+	return result
+}
+
+// Send POSTs the event to the webhook.
+func (w *Webhook) Send(e *WebhookEvent) error {
+	var body interface{} = e
+	if w.Format == "slack" {
+		body = map[string]string{"text": e.Text()}
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.Post(w.URL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// WebhookHook is a hook that notifies webhooks when an apply starts,
+// completes and fails. Failing to notify a webhook is reported, but
+// doesn't stop the apply.
+type WebhookHook struct {
+	Webhooks []*Webhook
+	Dir      string
+	Ui       cli.Ui
+
+	// Redactor hides sensitive values in the errors that are sent.
+	Redactor *redactor
+
+	changes *AuditPlan
+	l       sync.Mutex
+	terraform.NilHook
+}
+
+func (h *WebhookHook) PreApplyAll(
+	s *terraform.State, d *terraform.Diff) (terraform.HookAction, error) {
+	h.l.Lock()
+	h.changes = auditPlan(&terraform.Plan{Diff: d, State: s})
+	h.l.Unlock()
+
+	h.send("apply_start", nil)
+	return terraform.HookActionContinue, nil
+}
+
+func (h *WebhookHook) PostApplyAll(
+	s *terraform.State, err error) (terraform.HookAction, error) {
+	if err != nil {
+		h.send("apply_failed", err)
+	} else {
+		h.send("apply_complete", nil)
+	}
+
+	return terraform.HookActionContinue, nil
+}
+
+func (h *WebhookHook) send(event string, err error) {
+	host, _ := os.Hostname()
+
+	h.l.Lock()
+	e := &WebhookEvent{
+		Event:   event,
+		Time:    time.Now().UTC(),
+		User:    auditUser(),
+		Host:    host,
+		Dir:     h.Dir,
+		Changes: h.changes,
+	}
+	h.l.Unlock()
+
+	if err != nil {
+		e.Error = h.Redactor.Redact(err.Error())
+	}
+
+	for _, w := range h.Webhooks {
+		if err := w.Send(e); err != nil {
+			log.Printf("[ERROR] Error notifying webhook: %s", err)
+			if h.Ui != nil {
+				h.Ui.Error(fmt.Sprintf("Error notifying webhook: %s", err))
+			}
+		}
+	}
+}
+
+// webhookHook returns the hook that notifies the webhooks, or nil if
+// there are none.
+func (m *Meta) webhookHook() *WebhookHook {
+	if len(m.Webhooks) == 0 {
+		return nil
+	}
+
+	dir, _ := m.wd()
+	return &WebhookHook{
+		Webhooks: m.Webhooks,
+		Dir:      dir,
+		Ui:       m.Ui,
+		Redactor: m.redactor,
+	}
+}
@@ -0,0 +1,86 @@
+package command
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestWebhookHook_impl(t *testing.T) {
+	var _ terraform.Hook = new(WebhookHook)
+}
+
+func TestWebhookSend_slack(t *testing.T) {
+	var body map[string]string
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+		}))
+	defer ts.Close()
+
+	w := &Webhook{URL: ts.URL, Format: "slack"}
+	e := &WebhookEvent{
+		Event:   "apply_failed",
+		User:    "alice",
+		Host:    "laptop",
+		Dir:     "/infra",
+		Changes: &AuditPlan{Add: 1, Destroy: 2},
+		Error:   "boom",
+	}
+	if err := w.Send(e); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := "Terraform apply failed in /infra (alice@laptop): " +
+		"1 to add, 0 to change, 2 to destroy\nboom"
+	if body["text"] != expected {
+		t.Fatalf("bad: %#v", body)
+	}
+}
+
+func TestWebhookSend_error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(500)
+		}))
+	defer ts.Close()
+
+	w := &Webhook{URL: ts.URL}
+	if err := w.Send(&WebhookEvent{Event: "apply_start"}); err == nil {
+		t.Fatal("should error")
+	}
+}
+
+func TestWebhookHook_failed(t *testing.T) {
+	var e WebhookEvent
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+		}))
+	defer ts.Close()
+
+	r := new(redactor)
+	r.Add("hunter2")
+
+	h := &WebhookHook{
+		Webhooks: []*Webhook{&Webhook{URL: ts.URL}},
+		Redactor: r,
+	}
+	h.PostApplyAll(nil, errors.New("bad password hunter2"))
+
+	if e.Event != "apply_failed" {
+		t.Fatalf("bad: %#v", e)
+	}
+	if strings.Contains(e.Error, "hunter2") {
+		t.Fatalf("bad: %#v", e)
+	}
+}
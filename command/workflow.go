@@ -0,0 +1,275 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/multierror"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+)
+
+// Workflow runs plans, applies and refreshes from Go, the way the plan,
+// apply and refresh commands do, for programs that embed Terraform.
+// Everything it needs is in the Workflow, so that several can run in the
+// same process, such as for different directories.
+//
+// The options shared with the commands are set in Meta: the providers and
+// provisioners in ContextOpts, the directory of the configuration in
+// WorkingDir, or the configuration itself in Configuration, and the
+// policies, limits and audit log. Plans that fail the policies, the
+// destroy limits or the cost budget aren't applied, unless Override is
+// set. Approvals of plans aren't checked, since they're for plan files.
+type Workflow struct {
+	Meta
+
+	// Output receives what the commands would show, such as the progress
+	// of each resource and warnings about the configuration. If it's nil,
+	// the output is discarded.
+	Output io.Writer
+
+	// StatePath is the path of the state file, relative to WorkingDir.
+	// Defaults to DefaultStateFilename.
+	StatePath string
+
+	// Variables are the values of the variables of the configuration.
+	Variables map[string]string
+
+	// Override is the reason for applying plans that fail the policies,
+	// which is recorded in the audit log.
+	Override string
+
+	// Message is why the workflow is run, as given with -message to the
+	// commands.
+	Message string
+}
+
+// Plan refreshes the state in memory and creates a plan. The state file
+// isn't changed. If the context is canceled, the plan is stopped and the
+// error of the context is returned.
+func (w *Workflow) Plan(
+	ctx context.Context, opts *terraform.PlanOpts) (*terraform.Plan, error) {
+	if err := w.init(); err != nil {
+		return nil, err
+	}
+
+	tfCtx, err := w.context(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var plan *terraform.Plan
+	err = w.run(ctx, tfCtx, func() error {
+		if _, err := tfCtx.Refresh(); err != nil {
+			return fmt.Errorf("Error refreshing state: %s", err)
+		}
+
+		plan, err = tfCtx.Plan(opts)
+		return err
+	})
+	return plan, err
+}
+
+// Apply applies the plan and writes the resulting state to the state
+// file, with the state locked. If the plan is nil, the configuration is
+// planned first. If the context is canceled, the apply is stopped
+// gracefully, and the state is written with what was done.
+func (w *Workflow) Apply(
+	ctx context.Context, plan *terraform.Plan) (*terraform.State, error) {
+	if err := w.init(); err != nil {
+		return nil, err
+	}
+	if w.ReadOnly {
+		return nil, fmt.Errorf("can't apply in read-only mode")
+	}
+
+	statePath := w.statePath()
+	l, err := w.lockState(statePath)
+	if err != nil {
+		return nil, fmt.Errorf("Error locking state: %s", err)
+	}
+	defer l.Unlock()
+
+	var tfCtx *terraform.Context
+	if plan != nil {
+		if err := w.checkVersion("plan", plan.TFVersion); err != nil {
+			return nil, err
+		}
+
+		w.inferProviders(plan.Config, plan.State)
+		tfCtx = plan.Context(w.contextOpts())
+		w.addSensitive(tfCtx.SensitiveValues())
+	} else {
+		if tfCtx, err = w.context(ctx); err != nil {
+			return nil, err
+		}
+
+		err = w.run(ctx, tfCtx, func() error {
+			if _, err := tfCtx.Refresh(); err != nil {
+				return fmt.Errorf("Error refreshing state: %s", err)
+			}
+
+			plan, err = tfCtx.Plan(nil)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	audit := w.startAudit("apply", nil)
+	audit.SetPlan(plan)
+	if failures := w.policyFailures(plan); len(failures) > 0 {
+		if w.Override == "" {
+			return nil, fmt.Errorf(
+				"the plan fails the following policies:\n\n  * %s",
+				strings.Join(failures, "\n  * "))
+		}
+
+		audit.SetOverride(w.Override, failures)
+	}
+
+	var state *terraform.State
+	applyErr := w.run(ctx, tfCtx, func() error {
+		var err error
+		state, err = tfCtx.Apply()
+		return err
+	})
+	if state == nil {
+		return nil, applyErr
+	}
+
+	w.recordRun(state)
+	if err := w.writeStateFile(statePath, state); err != nil {
+		audit.Finish(statePath, nil, err)
+		return state, fmt.Errorf("Failed to save state: %s", err)
+	}
+	audit.Finish(statePath, state, applyErr)
+
+	return state, applyErr
+}
+
+// Refresh refreshes the state and writes it to the state file, with the
+// state locked. If the context is canceled, the refresh is stopped and
+// the state file isn't changed.
+func (w *Workflow) Refresh(ctx context.Context) (*terraform.State, error) {
+	if err := w.init(); err != nil {
+		return nil, err
+	}
+	if w.ReadOnly {
+		return nil, fmt.Errorf("can't refresh in read-only mode")
+	}
+
+	statePath := w.statePath()
+	l, err := w.lockState(statePath)
+	if err != nil {
+		return nil, fmt.Errorf("Error locking state: %s", err)
+	}
+	defer l.Unlock()
+
+	tfCtx, err := w.context(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	audit := w.startAudit("refresh", nil)
+	var state *terraform.State
+	err = w.run(ctx, tfCtx, func() error {
+		var err error
+		state, err = tfCtx.Refresh()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	w.recordRun(state)
+	if err := w.writeStateFile(statePath, state); err != nil {
+		audit.Finish(statePath, nil, err)
+		return nil, fmt.Errorf("Error writing state file: %s", err)
+	}
+	audit.Finish(statePath, state, nil)
+
+	return state, nil
+}
+
+// init prepares the Meta for a run, the way the commands process their
+// arguments.
+func (w *Workflow) init() error {
+	if w.ContextOpts == nil {
+		return fmt.Errorf("ContextOpts must be set")
+	}
+
+	output := w.Output
+	if output == nil {
+		output = ioutil.Discard
+	}
+	w.Meta.Ui = &cli.BasicUi{Writer: output, ErrorWriter: output}
+	w.Meta.oldUi = nil
+	w.Meta.extraHooks = nil
+	w.Meta.process(nil, false)
+
+	w.variables = w.Variables
+	w.message = w.Message
+	w.refuseNewerVersion = w.Override == ""
+	return nil
+}
+
+// context loads the configuration and the state, and validates them.
+func (w *Workflow) context(ctx context.Context) (*terraform.Context, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	dir, err := w.wd()
+	if err != nil {
+		return nil, err
+	}
+
+	tfCtx, _, err := w.Context(dir, w.statePath())
+	if err != nil {
+		return nil, err
+	}
+
+	ws, es := w.checkContext(tfCtx)
+	for _, warning := range ws {
+		w.Ui.Output("Warning: " + warning)
+	}
+	if len(es) > 0 {
+		return nil, multierror.ErrorAppend(nil, es...)
+	}
+
+	return tfCtx, nil
+}
+
+// run runs f, stopping the Terraform context if ctx is canceled first.
+// The error of ctx is returned if it was canceled.
+func (w *Workflow) run(
+	ctx context.Context, tfCtx *terraform.Context, f func() error) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- f()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		log.Printf("[INFO] Stopping: %s", ctx.Err())
+		tfCtx.Stop()
+		<-errCh
+		return ctx.Err()
+	}
+}
+
+func (w *Workflow) statePath() string {
+	if w.StatePath == "" {
+		return w.path(DefaultStateFilename)
+	}
+
+	return w.path(w.StatePath)
+}
@@ -0,0 +1,174 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/state"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestWorkflowApply(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	p := testProvider()
+	out := new(bytes.Buffer)
+	w := &Workflow{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			WorkingDir:  testFixturePath("apply"),
+		},
+		Output:    out,
+		StatePath: filepath.Join(td, "terraform.tfstate"),
+		Message:   "from go",
+	}
+
+	plan, err := w.Plan(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := os.Stat(w.StatePath); !os.IsNotExist(err) {
+		t.Fatalf("plan should not write the state: %s", err)
+	}
+
+	s, err := w.Apply(context.Background(), plan)
+	if err != nil {
+		t.Fatalf("err: %s\n\n%s", err, out.String())
+	}
+	if !p.ApplyCalled {
+		t.Fatal("apply should be called")
+	}
+	if s.Metadata["run_message"] != "from go" {
+		t.Fatalf("bad: %#v", s.Metadata)
+	}
+
+	f, err := os.Open(w.StatePath)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer f.Close()
+	if _, err := terraform.ReadState(f); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// The state is unlocked once the apply is done
+	if _, err := os.Stat(w.StatePath + state.LockExtension); !os.IsNotExist(err) {
+		t.Fatalf("state should be unlocked: %s", err)
+	}
+}
+
+func TestWorkflowApply_noPlan(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	p := testProvider()
+	w := &Workflow{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			WorkingDir:  td,
+		},
+	}
+
+	// The configuration is given directly, so the directory is empty
+	conf, err := config.LoadDir(testFixturePath("apply"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	w.Configuration = conf
+
+	if _, err := w.Apply(context.Background(), nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !p.ApplyCalled {
+		t.Fatal("apply should be called")
+	}
+	if _, err := os.Stat(filepath.Join(td, DefaultStateFilename)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestWorkflowApply_locked(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	statePath := filepath.Join(td, "terraform.tfstate")
+	l, err := state.LockLocal(statePath, "alice@host")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer l.Unlock()
+
+	p := testProvider()
+	w := &Workflow{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			WorkingDir:  testFixturePath("apply"),
+		},
+		StatePath: statePath,
+	}
+
+	if _, err := w.Apply(context.Background(), nil); err == nil {
+		t.Fatal("should error")
+	}
+	if p.ApplyCalled {
+		t.Fatal("apply should not be called")
+	}
+}
+
+func TestWorkflowPlan_canceled(t *testing.T) {
+	p := testProvider()
+	w := &Workflow{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			WorkingDir:  testFixturePath("apply"),
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := w.Plan(ctx, nil); err != context.Canceled {
+		t.Fatalf("bad: %#v", err)
+	}
+	if p.DiffCalled {
+		t.Fatal("diff should not be called")
+	}
+}
+
+func TestWorkflowPlan_noContextOpts(t *testing.T) {
+	w := &Workflow{
+		Meta: Meta{
+			WorkingDir: testFixturePath("apply"),
+		},
+	}
+
+	if _, err := w.Plan(context.Background(), nil); err == nil {
+		t.Fatal("should error")
+	}
+}
+
+func TestWorkflowRefresh(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	p := testProvider()
+	w := &Workflow{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(p),
+			WorkingDir:  testFixturePath("apply"),
+		},
+		StatePath: filepath.Join(td, "terraform.tfstate"),
+	}
+
+	if _, err := w.Refresh(context.Background()); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := os.Stat(w.StatePath); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
@@ -1,10 +1,12 @@
 package main
 
 import (
+	"crypto/ecdsa"
 	"os"
 	"os/signal"
 
 	"github.com/hashicorp/terraform/command"
+	"github.com/hashicorp/terraform/state"
 	"github.com/mitchellh/cli"
 )
 
@@ -14,9 +16,76 @@ var Commands map[string]cli.CommandFactory
 // Ui is the cli.Ui used for communicating to the outside world.
 var Ui cli.Ui
 
+// AuditLog is the audit log the commands use, set up from the CLI
+// configuration.
+var AuditLog command.AuditLog
+
+// ReadOnly is whether the commands run in read-only mode. It is set by
+// the -read-only flag or the CLI configuration before initCommands.
+var ReadOnly bool
+
+// PushAddress is the default address of the remote run API for the push
+// command, from the CLI configuration.
+var PushAddress string
+
+// UpdateAddress and UpdateKey are the release index and the path of the
+// release signing key for the self-update command, from the CLI
+// configuration.
+var UpdateAddress string
+var UpdateKey string
+
+// Webhooks are the webhooks the commands notify, set up from the CLI
+// configuration.
+var Webhooks []*command.Webhook
+
+// ExecHooks are the hook executables that the commands run, set up from
+// the CLI configuration.
+var ExecHooks []*command.ExecHook
+
+// Events publishes the lifecycle events of the commands to the event
+// sinks set up from the CLI configuration.
+var Events command.EventBus
+
+// Policies are the policies that check plans before they are applied,
+// set up from the CLI configuration.
+var Policies []*command.Policy
+
+// Approvers are the people who can approve saved plans, with their
+// public keys, set up from the CLI configuration.
+var Approvers map[string]*ecdsa.PublicKey
+
+// Rules are the conventions that resources must follow, set up from the
+// CLI configuration.
+var Rules []*command.Rule
+
+// CostEstimators estimate the cost of plans, and CostBudget is the
+// monthly cost that applies can't exceed. They are set up from the CLI
+// configuration.
+var CostEstimators = []command.CostEstimator{
+	&command.StaticCostEstimator{Prices: command.StaticCostPrices},
+}
+var CostBudget float64
+
+// MaxDestroy and MaxDestroyPercent limit how much an apply can destroy,
+// set up from the CLI configuration.
+var MaxDestroy int
+var MaxDestroyPercent float64
+
+// ResolveProvider finds the providers inferred from types of resources
+// that no provider manages, set up from the CLI configuration.
+var ResolveProvider command.ProviderResolver
+
+// StateRetention is how many old versions of local state files are
+// kept, set up from the CLI configuration.
+var StateRetention *state.Retention
+
 const ErrorPrefix = "e:"
 const OutputPrefix = "o:"
 
+// EnvNoColor is the environmental variable that, if set, disables
+// colored output. This is set automatically if stdout isn't a terminal.
+const EnvNoColor = "TF_NO_COLOR"
+
 func init() {
 	Ui = &cli.PrefixedUi{
 		AskPrefix:    OutputPrefix,
@@ -26,13 +95,40 @@ func init() {
 		Ui:           &cli.BasicUi{Writer: os.Stdout},
 	}
 
+	initCommands("")
+}
+
+// initCommands initializes Commands so that the commands work in the
+// given directory. If it is empty, they use the process working directory.
+func initCommands(workingDir string) {
 	meta := command.Meta{
-		Color:       true,
-		ContextOpts: &ContextOpts,
-		Ui:          Ui,
+		Color:             os.Getenv(EnvNoColor) == "",
+		ContextOpts:       &ContextOpts,
+		Ui:                Ui,
+		AuditLog:          &AuditLog,
+		ReadOnly:          ReadOnly,
+		Webhooks:          Webhooks,
+		ExecHooks:         ExecHooks,
+		Events:            &Events,
+		Policies:          Policies,
+		Approvers:         Approvers,
+		Rules:             Rules,
+		CostEstimators:    CostEstimators,
+		CostBudget:        CostBudget,
+		MaxDestroy:        MaxDestroy,
+		MaxDestroyPercent: MaxDestroyPercent,
+		StateRetention:    StateRetention,
+		ProviderResolver:  ResolveProvider,
+		WorkingDir:        workingDir,
 	}
 
 	Commands = map[string]cli.CommandFactory{
+		"approve": func() (cli.Command, error) {
+			return &command.ApproveCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"apply": func() (cli.Command, error) {
 			return &command.ApplyCommand{
 				Meta:       meta,
@@ -40,36 +136,106 @@ func init() {
 			}, nil
 		},
 
+		"daemon": func() (cli.Command, error) {
+			return &command.DaemonCommand{
+				Meta:       meta,
+				ShutdownCh: makeShutdownCh(),
+			}, nil
+		},
+
+		"describe": func() (cli.Command, error) {
+			return &command.DescribeCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"drift": func() (cli.Command, error) {
+			return &command.DriftCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"graph": func() (cli.Command, error) {
 			return &command.GraphCommand{
+				Meta:       meta,
+				ShutdownCh: makeShutdownCh(),
+			}, nil
+		},
+
+		"init": func() (cli.Command, error) {
+			return &command.InitCommand{
 				Meta: meta,
 			}, nil
 		},
 
 		"output": func() (cli.Command, error) {
 			return &command.OutputCommand{
-				Meta: meta,
+				Meta:       meta,
+				ShutdownCh: makeShutdownCh(),
 			}, nil
 		},
 
 		"plan": func() (cli.Command, error) {
 			return &command.PlanCommand{
+				Meta:       meta,
+				ShutdownCh: makeShutdownCh(),
+			}, nil
+		},
+
+		"providers": func() (cli.Command, error) {
+			return &command.ProvidersCommand{
 				Meta: meta,
 			}, nil
 		},
 
+		"push": func() (cli.Command, error) {
+			return &command.PushCommand{
+				Meta:    meta,
+				Address: PushAddress,
+			}, nil
+		},
+
 		"refresh": func() (cli.Command, error) {
 			return &command.RefreshCommand{
+				Meta:       meta,
+				ShutdownCh: makeShutdownCh(),
+			}, nil
+		},
+
+		"scan": func() (cli.Command, error) {
+			return &command.ScanCommand{
 				Meta: meta,
 			}, nil
 		},
 
+		"self-update": func() (cli.Command, error) {
+			return &command.SelfUpdateCommand{
+				Meta:              meta,
+				Address:           UpdateAddress,
+				KeyPath:           UpdateKey,
+				Version:           Version,
+				VersionPrerelease: VersionPrerelease,
+			}, nil
+		},
+
 		"show": func() (cli.Command, error) {
 			return &command.ShowCommand{
 				Meta: meta,
 			}, nil
 		},
 
+		"state": func() (cli.Command, error) {
+			return &command.StateCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"validate": func() (cli.Command, error) {
+			return &command.ValidateCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"version": func() (cli.Command, error) {
 			return &command.VersionCommand{
 				Meta:              meta,
@@ -79,6 +245,44 @@ func init() {
 			}, nil
 		},
 	}
+
+	// Plan and apply run the executables in the command hooks directory
+	// of the working directory before and after them
+	for _, name := range []string{"apply", "plan"} {
+		name, f := name, Commands[name]
+		Commands[name] = func() (cli.Command, error) {
+			cmd, err := f()
+			if err != nil {
+				return nil, err
+			}
+
+			return &command.CommandHookCommand{
+				Command: cmd,
+				Name:    name,
+				Ui:      Ui,
+				Dir:     workingDir,
+			}, nil
+		}
+	}
+
+	// The runs of every command are published to the event sinks
+	if len(Events.Sinks) > 0 {
+		for name, f := range Commands {
+			name, f := name, f
+			Commands[name] = func() (cli.Command, error) {
+				cmd, err := f()
+				if err != nil {
+					return nil, err
+				}
+
+				return &command.EventCommand{
+					Command: cmd,
+					Name:    name,
+					Bus:     &Events,
+				}, nil
+			}
+		}
+	}
 }
 
 // makeShutdownCh creates an interrupt listener and returns a channel.
@@ -97,3 +301,13 @@ func makeShutdownCh() <-chan struct{} {
 
 	return resultCh
 }
+
+// isTerminal returns true if the given file is a terminal.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return fi.Mode()&os.ModeCharDevice != 0
+}
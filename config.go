@@ -3,13 +3,17 @@ package main
 import (
 	"fmt"
 	"io/ioutil"
+	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 
 	"github.com/hashicorp/hcl"
-	"github.com/hashicorp/terraform/plugin"
+	"github.com/hashicorp/terraform/builtin/secrets/env"
+	"github.com/hashicorp/terraform/builtin/secrets/file"
+	"github.com/hashicorp/terraform/builtin/secrets/vault"
+	"github.com/hashicorp/terraform/command"
 	"github.com/hashicorp/terraform/rpc"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/mitchellh/osext"
@@ -22,6 +26,146 @@ import (
 type Config struct {
 	Providers    map[string]string
 	Provisioners map[string]string
+
+	// AuditLog is where the audit log of commands that change the state
+	// is written: a file path, "syslog", or an HTTP(S) URL.
+	AuditLog string `hcl:"audit_log"`
+
+	// ReadOnly makes every command run in read-only mode, where
+	// infrastructure and the state are never changed.
+	ReadOnly bool `hcl:"read_only"`
+
+	// PushAddress is the default address of the remote run API that
+	// `terraform push` submits runs to.
+	PushAddress string `hcl:"push_address"`
+
+	// UpdateAddress is the URL of the release index that
+	// `terraform self-update` installs releases from, and UpdateKey is
+	// the path of the ECDSA public key that releases are signed with.
+	UpdateAddress string `hcl:"update_address"`
+	UpdateKey     string `hcl:"update_key"`
+
+	// Webhooks are notified when an apply starts, completes and fails.
+	// They are keyed by name.
+	Webhooks map[string]*WebhookConfig `hcl:"webhook"`
+
+	// Hooks are executables that are run at hook points, such as before
+	// and after each resource is applied. They are keyed by name.
+	Hooks map[string]*HookConfig `hcl:"hook"`
+
+	// EventSinks receive the lifecycle events of runs, such as metrics
+	// for statsd. They are keyed by name.
+	EventSinks map[string]*EventSinkConfig `hcl:"event_sink"`
+
+	// Policies check plans before they are applied, or set rules that
+	// the resources in configurations must follow. They are keyed by
+	// name.
+	Policies map[string]*PolicyConfig `hcl:"policy"`
+
+	// Approvers can approve saved plans, which are then the only plans
+	// that can be applied. They are keyed by name.
+	Approvers map[string]*ApproverConfig `hcl:"approver"`
+
+	// CostEstimator is the path of an executable that estimates the
+	// monthly cost of resources, before the built-in prices are used.
+	CostEstimator string `hcl:"cost_estimator"`
+
+	// CostBudget is the estimated monthly cost, in dollars, that applies
+	// can't take the infrastructure over. Zero means there is no budget.
+	CostBudget int `hcl:"cost_budget"`
+
+	// MaxDestroy and MaxDestroyPercent limit how many resources, and what
+	// percentage of the resources in the state, an apply can destroy.
+	// Zero means there is no limit.
+	MaxDestroy        int `hcl:"max_destroy"`
+	MaxDestroyPercent int `hcl:"max_destroy_percent"`
+
+	// StateKeepLast and StateKeepDailyDays are how many old versions of
+	// local state files are kept: the latest StateKeepLast versions,
+	// and the latest version of each day for StateKeepDailyDays days.
+	// If both are zero, no old versions are kept.
+	StateKeepLast      int `hcl:"state_keep_last"`
+	StateKeepDailyDays int `hcl:"state_keep_daily_days"`
+}
+
+// WebhookConfig is the configuration of a single webhook.
+type WebhookConfig struct {
+	URL    string `hcl:"url"`
+	Format string `hcl:"format"`
+}
+
+// HookConfig is the configuration of a single hook executable.
+type HookConfig struct {
+	Command string   `hcl:"command"`
+	Events  []string `hcl:"events"`
+}
+
+// EventSinkConfig is the configuration of a single event sink.
+type EventSinkConfig struct {
+	// Type is "statsd", "syslog" or "file".
+	Type string `hcl:"type"`
+
+	// Address and Prefix are the address of statsd and the prefix of the
+	// names of the metrics, for the "statsd" type.
+	Address string `hcl:"address"`
+	Prefix  string `hcl:"prefix"`
+
+	// Path is the file that events are appended to, for the "file" type.
+	Path string `hcl:"path"`
+}
+
+// Sink returns the event sink for the configuration.
+func (c *EventSinkConfig) Sink() (command.EventSink, error) {
+	switch c.Type {
+	case "statsd":
+		if c.Address == "" {
+			return nil, fmt.Errorf("address is required for statsd")
+		}
+
+		return &command.StatsdEventSink{
+			Address: c.Address,
+			Prefix:  c.Prefix,
+		}, nil
+	case "syslog":
+		return &command.SyslogEventSink{}, nil
+	case "file":
+		if c.Path == "" {
+			return nil, fmt.Errorf("path is required for file")
+		}
+
+		return &command.FileEventSink{Path: c.Path}, nil
+	default:
+		return nil, fmt.Errorf("unknown type %q", c.Type)
+	}
+}
+
+// PolicyConfig is the configuration of a single policy.
+type PolicyConfig struct {
+	// Command is the path of the executable that checks the plan.
+	Command string `hcl:"command"`
+
+	// Resource is the type of resource that NamePattern and RequiredTags
+	// apply to. If it's empty, they apply to every resource.
+	Resource     string   `hcl:"resource"`
+	NamePattern  string   `hcl:"name_pattern"`
+	RequiredTags []string `hcl:"required_tags"`
+}
+
+// ApproverConfig is the configuration of a single approver.
+type ApproverConfig struct {
+	// PublicKey is the path of the approver's ECDSA public key, in PEM
+	// format.
+	PublicKey string `hcl:"public_key"`
+}
+
+// Rule returns the rule that the policy sets, or nil if it only runs a
+// command.
+func (p *PolicyConfig) Rule(name string) (*command.Rule, error) {
+	if p.NamePattern == "" && len(p.RequiredTags) == 0 {
+		return nil, nil
+	}
+
+	return command.NewRule(name, p.Resource, p.NamePattern, p.RequiredTags)
 }
 
 // BuiltinConfig is the built-in defaults for the configuration. These
@@ -39,6 +183,12 @@ func init() {
 		"dnsimple":     "terraform-provider-dnsimple",
 		"consul":       "terraform-provider-consul",
 		"cloudflare":   "terraform-provider-cloudflare",
+		"github":       "terraform-provider-github",
+		"packer":       "terraform-provider-packer",
+		"libvirt":      "terraform-provider-libvirt",
+		"mysql":        "terraform-provider-mysql",
+		"postgresql":   "terraform-provider-postgresql",
+		"terraform":    "terraform-provider-terraform",
 	}
 	BuiltinConfig.Provisioners = map[string]string{
 		"local-exec":  "terraform-provisioner-local-exec",
@@ -49,7 +199,8 @@ func init() {
 
 // ConfigFile returns the default path to the configuration file.
 //
-// On Unix-like systems this is the ".terraformrc" file in the home directory.
+// On Unix-like systems this is the ".terraformrc" file in the home directory,
+// or "$XDG_CONFIG_HOME/terraform/terraformrc" if only that exists.
 // On Windows, this is the "terraform.rc" file in the application data
 // directory.
 func ConfigFile() (string, error) {
@@ -78,6 +229,78 @@ func LoadConfig(path string) (*Config, error) {
 		return nil, err
 	}
 
+	for n, w := range result.Webhooks {
+		if w.URL == "" {
+			return nil, fmt.Errorf(
+				"Error in %s: webhook %s: url is required", path, n)
+		}
+
+		switch w.Format {
+		case "", "json", "slack":
+		default:
+			return nil, fmt.Errorf(
+				"Error in %s: webhook %s: unknown format %q", path, n, w.Format)
+		}
+	}
+
+	for n, h := range result.Hooks {
+		if h.Command == "" {
+			return nil, fmt.Errorf(
+				"Error in %s: hook %s: command is required", path, n)
+		}
+
+		for _, e := range h.Events {
+			found := false
+			for _, v := range command.HookEvents {
+				if v == e {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf(
+					"Error in %s: hook %s: unknown event %q", path, n, e)
+			}
+		}
+	}
+
+	for n, e := range result.EventSinks {
+		if _, err := e.Sink(); err != nil {
+			return nil, fmt.Errorf(
+				"Error in %s: event_sink %s: %s", path, n, err)
+		}
+	}
+
+	for n, p := range result.Policies {
+		rule, err := p.Rule(n)
+		if err != nil {
+			return nil, fmt.Errorf("Error in %s: %s", path, err)
+		}
+		if p.Command == "" && rule == nil {
+			return nil, fmt.Errorf(
+				"Error in %s: policy %s: command, name_pattern or "+
+					"required_tags is required", path, n)
+		}
+	}
+
+	if result.MaxDestroyPercent < 0 || result.MaxDestroyPercent > 100 {
+		return nil, fmt.Errorf(
+			"Error in %s: max_destroy_percent must be between 0 and 100", path)
+	}
+
+	if result.StateKeepLast < 0 || result.StateKeepDailyDays < 0 {
+		return nil, fmt.Errorf(
+			"Error in %s: state_keep_last and state_keep_daily_days can't "+
+				"be negative", path)
+	}
+
+	for n, a := range result.Approvers {
+		if a.PublicKey == "" {
+			return nil, fmt.Errorf(
+				"Error in %s: approver %s: public_key is required", path, n)
+		}
+	}
+
 	return &result, nil
 }
 
@@ -100,6 +323,109 @@ func (c1 *Config) Merge(c2 *Config) *Config {
 		result.Provisioners[k] = v
 	}
 
+	result.AuditLog = c1.AuditLog
+	if c2.AuditLog != "" {
+		result.AuditLog = c2.AuditLog
+	}
+
+	result.PushAddress = c1.PushAddress
+	if c2.PushAddress != "" {
+		result.PushAddress = c2.PushAddress
+	}
+
+	result.UpdateAddress = c1.UpdateAddress
+	if c2.UpdateAddress != "" {
+		result.UpdateAddress = c2.UpdateAddress
+	}
+
+	result.UpdateKey = c1.UpdateKey
+	if c2.UpdateKey != "" {
+		result.UpdateKey = c2.UpdateKey
+	}
+
+	result.CostEstimator = c1.CostEstimator
+	if c2.CostEstimator != "" {
+		result.CostEstimator = c2.CostEstimator
+	}
+
+	result.CostBudget = c1.CostBudget
+	if c2.CostBudget != 0 {
+		result.CostBudget = c2.CostBudget
+	}
+
+	result.MaxDestroy = c1.MaxDestroy
+	if c2.MaxDestroy != 0 {
+		result.MaxDestroy = c2.MaxDestroy
+	}
+
+	result.MaxDestroyPercent = c1.MaxDestroyPercent
+	if c2.MaxDestroyPercent != 0 {
+		result.MaxDestroyPercent = c2.MaxDestroyPercent
+	}
+
+	result.StateKeepLast = c1.StateKeepLast
+	if c2.StateKeepLast != 0 {
+		result.StateKeepLast = c2.StateKeepLast
+	}
+
+	result.StateKeepDailyDays = c1.StateKeepDailyDays
+	if c2.StateKeepDailyDays != 0 {
+		result.StateKeepDailyDays = c2.StateKeepDailyDays
+	}
+
+	if len(c1.Webhooks)+len(c2.Webhooks) > 0 {
+		result.Webhooks = make(map[string]*WebhookConfig)
+		for k, v := range c1.Webhooks {
+			result.Webhooks[k] = v
+		}
+		for k, v := range c2.Webhooks {
+			result.Webhooks[k] = v
+		}
+	}
+
+	if len(c1.Hooks)+len(c2.Hooks) > 0 {
+		result.Hooks = make(map[string]*HookConfig)
+		for k, v := range c1.Hooks {
+			result.Hooks[k] = v
+		}
+		for k, v := range c2.Hooks {
+			result.Hooks[k] = v
+		}
+	}
+
+	if len(c1.EventSinks)+len(c2.EventSinks) > 0 {
+		result.EventSinks = make(map[string]*EventSinkConfig)
+		for k, v := range c1.EventSinks {
+			result.EventSinks[k] = v
+		}
+		for k, v := range c2.EventSinks {
+			result.EventSinks[k] = v
+		}
+	}
+
+	if len(c1.Policies)+len(c2.Policies) > 0 {
+		result.Policies = make(map[string]*PolicyConfig)
+		for k, v := range c1.Policies {
+			result.Policies[k] = v
+		}
+		for k, v := range c2.Policies {
+			result.Policies[k] = v
+		}
+	}
+
+	if len(c1.Approvers)+len(c2.Approvers) > 0 {
+		result.Approvers = make(map[string]*ApproverConfig)
+		for k, v := range c1.Approvers {
+			result.Approvers[k] = v
+		}
+		for k, v := range c2.Approvers {
+			result.Approvers[k] = v
+		}
+	}
+
+	// Read-only mode can't be turned off by a later configuration
+	result.ReadOnly = c1.ReadOnly || c2.ReadOnly
+
 	return &result
 }
 
@@ -118,10 +444,10 @@ func (c *Config) ProviderFactories() map[string]terraform.ResourceProviderFactor
 func (c *Config) providerFactory(path string) terraform.ResourceProviderFactory {
 	return func() (terraform.ResourceProvider, error) {
 		// Build the plugin client configuration and init the plugin
-		var config plugin.ClientConfig
-		config.Cmd = pluginCmd(path)
-		config.Managed = true
-		client := plugin.NewClient(&config)
+		client, err := pluginClient(path)
+		if err != nil {
+			return nil, err
+		}
 
 		// Request the RPC client and service name from the client
 		// so we can build the actual RPC-implemented provider.
@@ -135,13 +461,66 @@ func (c *Config) providerFactory(path string) terraform.ResourceProviderFactory
 			return nil, err
 		}
 
-		return &rpc.ResourceProvider{
-			Client: rpcClient,
-			Name:   service,
+		return &pluginResourceProvider{
+			ResourceProvider: &rpc.ResourceProvider{
+				Client: rpcClient,
+				Name:   service,
+			},
+			Plugin: client,
 		}, nil
 	}
 }
 
+// ResolveProvider finds the plugin of the provider with the given name,
+// which was inferred from a type of resource. The plugin is looked for
+// like the plugins in the configuration are. If it isn't found and a
+// release index is configured, it is downloaded from the index into the
+// first plugin directory.
+func (c *Config) ResolveProvider(
+	name string) (terraform.ResourceProviderFactory, string, error) {
+	exe := "terraform-provider-" + name
+	path := pluginPath(exe)
+	if strings.ContainsRune(path, os.PathSeparator) {
+		if _, err := os.Stat(path); err == nil {
+			return c.providerFactory(path), "found at " + path, nil
+		}
+	}
+
+	dirs := pluginDirs()
+	if c.UpdateAddress == "" || c.UpdateKey == "" || len(dirs) == 0 {
+		return nil, "", fmt.Errorf(
+			"%s isn't next to the Terraform executable, in the plugin\n"+
+				"directories or on the PATH. Install it, or add it to the providers\n"+
+				"in the CLI configuration", exe)
+	}
+
+	if err := os.MkdirAll(dirs[0], 0755); err != nil {
+		return nil, "", err
+	}
+	path, err := command.FetchPlugin(c.UpdateAddress, c.UpdateKey, dirs[0], exe)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return c.providerFactory(path), fmt.Sprintf(
+		"downloaded to %s from the release index", path), nil
+}
+
+// SecretBackends returns the built-in secret backends, configured from
+// the environment.
+func SecretBackends() map[string]terraform.SecretBackend {
+	return map[string]terraform.SecretBackend{
+		"env": new(env.SecretBackend),
+		"file": &file.SecretBackend{
+			Dir: os.Getenv("TF_SECRETS_DIR"),
+		},
+		"vault": &vault.SecretBackend{
+			Address: os.Getenv("VAULT_ADDR"),
+			Token:   os.Getenv("VAULT_TOKEN"),
+		},
+	}
+}
+
 // ProvisionerFactories returns the mapping of prefixes to
 // ResourceProvisionerFactory that can be used to instantiate a
 // binary-based plugin.
@@ -157,10 +536,10 @@ func (c *Config) ProvisionerFactories() map[string]terraform.ResourceProvisioner
 func (c *Config) provisionerFactory(path string) terraform.ResourceProvisionerFactory {
 	return func() (terraform.ResourceProvisioner, error) {
 		// Build the plugin client configuration and init the plugin
-		var config plugin.ClientConfig
-		config.Cmd = pluginCmd(path)
-		config.Managed = true
-		client := plugin.NewClient(&config)
+		client, err := pluginClient(path)
+		if err != nil {
+			return nil, err
+		}
 
 		// Request the RPC client and service name from the client
 		// so we can build the actual RPC-implemented provider.
@@ -174,43 +553,100 @@ func (c *Config) provisionerFactory(path string) terraform.ResourceProvisionerFa
 			return nil, err
 		}
 
-		return &rpc.ResourceProvisioner{
-			Client: rpcClient,
-			Name:   service,
+		return &pluginResourceProvisioner{
+			ResourceProvisioner: &rpc.ResourceProvisioner{
+				Client: rpcClient,
+				Name:   service,
+			},
+			Plugin: client,
 		}, nil
 	}
 }
 
 func pluginCmd(path string) *exec.Cmd {
+	return exec.Command(pluginPath(path))
+}
+
+// pluginPath returns the path of the executable of the plugin with the
+// given path in the configuration. Paths can use either separator, and
+// don't need the ".exe" extension on Windows.
+//
+// Plugins given by name are looked for in the directory of the Terraform
+// executable, then in the plugin directories returned by pluginDirs, and
+// then on the PATH.
+func pluginPath(path string) string {
+	path = filepath.FromSlash(path)
 	cmdPath := ""
 
 	// If the path doesn't contain a separator, look in the same
 	// directory as the Terraform executable first.
 	if !strings.ContainsRune(path, os.PathSeparator) {
-		exePath, err := osext.Executable()
-		if err == nil {
-			temp := filepath.Join(
-				filepath.Dir(exePath),
-				filepath.Base(path))
+		var dirs []string
+		if exePath, err := executablePath(); err == nil {
+			dirs = append(dirs, filepath.Dir(exePath))
+		}
+		dirs = append(dirs, pluginDirs()...)
 
+		for _, dir := range dirs {
+			temp := pluginExePath(filepath.Join(dir, filepath.Base(path)))
 			if _, err := os.Stat(temp); err == nil {
 				cmdPath = temp
+				break
 			}
 		}
 
 		// If we still haven't found the executable, look for it
 		// in the PATH.
-		if v, err := exec.LookPath(path); err == nil {
-			cmdPath = v
+		if cmdPath == "" {
+			if v, err := exec.LookPath(path); err == nil {
+				cmdPath = v
+			}
 		}
 	}
 
 	// If we still don't have a path, then just set it to the original
 	// given path.
 	if cmdPath == "" {
-		cmdPath = path
+		cmdPath = pluginExePath(path)
+	}
+
+	return cmdPath
+}
+
+// executablePath returns the path of the Terraform executable. osext
+// relies on /proc on some platforms, which small or chrooted systems may
+// not have mounted, so the path is worked out from the command line
+// instead if it fails.
+func executablePath() (string, error) {
+	exePath, err := osext.Executable()
+	if err == nil {
+		return exePath, nil
+	}
+	log.Printf("[DEBUG] Error finding executable with osext: %s", err)
+
+	exePath = os.Args[0]
+	if !strings.ContainsRune(exePath, os.PathSeparator) {
+		if exePath, err = exec.LookPath(exePath); err != nil {
+			return "", err
+		}
+	}
+	if exePath, err = filepath.Abs(exePath); err != nil {
+		return "", err
+	}
+
+	return filepath.EvalSymlinks(exePath)
+}
+
+// pluginExePath adds the extension of executables on this platform to
+// the path if it doesn't exist without it.
+func pluginExePath(path string) string {
+	if pluginExeSuffix == "" ||
+		strings.HasSuffix(strings.ToLower(path), pluginExeSuffix) {
+		return path
+	}
+	if _, err := os.Stat(path); err == nil {
+		return path
 	}
 
-	// Build the command to execute the plugin
-	return exec.Command(cmdPath)
+	return path + pluginExeSuffix
 }
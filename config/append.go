@@ -29,6 +29,13 @@ func Append(c1, c2 *Config) (*Config, error) {
 		}
 	}
 
+	// Only one file is expected to configure Terraform itself, but if
+	// more do, the last one wins.
+	c.Terraform = c1.Terraform
+	if c2.Terraform != nil {
+		c.Terraform = c2.Terraform
+	}
+
 	if len(c1.Outputs) > 0 || len(c2.Outputs) > 0 {
 		c.Outputs = make(
 			[]*Output, 0, len(c1.Outputs)+len(c2.Outputs))
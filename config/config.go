@@ -3,10 +3,16 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform/flatmap"
+	"github.com/hashicorp/terraform/helper/didyoumean"
 	"github.com/hashicorp/terraform/helper/multierror"
 	"github.com/mitchellh/mapstructure"
 	"github.com/mitchellh/reflectwalk"
@@ -15,6 +21,7 @@ import (
 // Config is the configuration that comes from loading a collection
 // of Terraform templates.
 type Config struct {
+	Terraform       *Terraform
 	ProviderConfigs []*ProviderConfig
 	Resources       []*Resource
 	Variables       []*Variable
@@ -25,6 +32,15 @@ type Config struct {
 	unknownKeys []string
 }
 
+// Terraform is the configuration of Terraform itself, from the
+// "terraform" block.
+type Terraform struct {
+	// RequiredVersion is a constraint on the versions of Terraform that
+	// the configuration can be used with, such as ">= 0.3.0". It's
+	// checked before anything else is done with the configuration.
+	RequiredVersion string
+}
+
 // ProviderConfig is the configuration for a resource provider.
 //
 // For example, Terraform needs to set the AWS access keys for the AWS
@@ -32,6 +48,11 @@ type Config struct {
 type ProviderConfig struct {
 	Name      string
 	RawConfig *RawConfig
+
+	// DefaultTags are the tags from the default_tags block of the
+	// provider, which are merged into the tags of every resource of the
+	// provider that has tags. It is nil if there's no default_tags block.
+	DefaultTags *RawConfig
 }
 
 // A resource represents a single Terraform resource in the configuration.
@@ -44,6 +65,40 @@ type Resource struct {
 	RawConfig    *RawConfig
 	Provisioners []*Provisioner
 	DependsOn    []string
+
+	// Enabled is the enabled meta-argument of the resource, with the
+	// single key "enabled". A resource that isn't enabled isn't created,
+	// or is destroyed if it exists. It is nil if the resource is always
+	// enabled.
+	Enabled *RawConfig
+
+	// Timeouts are how long creating, updating and deleting the resource
+	// may take, keyed by "create", "update" and "delete", from the
+	// timeouts block of the resource. Actions without one aren't limited.
+	Timeouts map[string]time.Duration
+
+	// Lifecycle is the lifecycle block of the resource, changing how
+	// Terraform manages the resource.
+	Lifecycle ResourceLifecycle
+
+	// Pos is where this resource was defined, if known. This is used
+	// to give better context in error messages.
+	Pos Pos
+}
+
+// Pos is a position within a configuration file. Line is one-based and
+// is zero if the line isn't known.
+type Pos struct {
+	Filename string
+	Line     int
+}
+
+func (p Pos) String() string {
+	if p.Line == 0 {
+		return p.Filename
+	}
+
+	return fmt.Sprintf("%s:%d", p.Filename, p.Line)
 }
 
 // Provisioner is a configured provisioner step on a resource.
@@ -54,19 +109,72 @@ type Provisioner struct {
 }
 
 // Variable is a variable defined within the configuration.
+//
+// The values of sensitive variables are hidden from the output of
+// Terraform and its logs.
 type Variable struct {
 	Name        string
 	Default     interface{}
 	Description string
+	Sensitive   bool
+
+	// Validations are the rules that the value of the variable must
+	// follow, from the validation blocks of the variable.
+	Validations []*VariableValidation
+}
+
+// VariableValidation is a validation block of a variable. The value of
+// the variable must follow all of the rules that are set in the block.
+type VariableValidation struct {
+	// Regex is a regular expression that the value must match.
+	Regex string
+
+	// Values are the values that are allowed.
+	Values []string
+
+	// Min and Max are the range of numbers the value must be in.
+	Min *float64
+	Max *float64
+
+	// ErrorMessage is the error shown when the value doesn't follow the
+	// rules, instead of one describing the rule that failed.
+	ErrorMessage string
 }
 
 // Output is an output defined within the configuration. An output is
 // resulting data that is highlighted by Terraform when finished.
+//
+// The values of sensitive outputs are stored in the state like any other
+// output, but are hidden from the output of Terraform and its logs.
 type Output struct {
 	Name      string
 	RawConfig *RawConfig
+	Sensitive bool
 }
 
+// ResourceLifecycle is the lifecycle block of a resource.
+type ResourceLifecycle struct {
+	// IgnoreChanges are the attributes whose changes are ignored once
+	// the resource exists, such as an attribute set with uuid() that
+	// would otherwise change on every run.
+	IgnoreChanges []string `hcl:"ignore_changes"`
+}
+
+// rootKeys are the valid keys at the root level of a configuration.
+var rootKeys = []string{
+	"output", "provider", "resource", "terraform", "variable"}
+
+// timeoutKeys are the actions that can be given a timeout in the timeouts
+// block of a resource.
+var timeoutKeys = []string{"create", "delete", "update"}
+
+// lifecycleKeys are the valid keys of the lifecycle block of a resource.
+var lifecycleKeys = []string{"ignore_changes"}
+
+// validationKeys are the valid keys of the validation blocks of a
+// variable.
+var validationKeys = []string{"error_message", "max", "min", "regex", "values"}
+
 // VariableType is the type of value a variable is holding, and returned
 // by the Type() function on variables.
 type VariableType byte
@@ -97,16 +205,65 @@ func (r *Resource) Id() string {
 	return fmt.Sprintf("%s.%s", r.Type, r.Name)
 }
 
-// Validate does some basic semantic checking of the configuration.
+// IsEnabled returns whether the resource is enabled, given the values of
+// the variables keyed like "var.foo".
+func (r *Resource) IsEnabled(vs map[string]string) (bool, error) {
+	if r.Enabled == nil {
+		return true, nil
+	}
+
+	// Interpolate a copy, since the resource may be shared
+	raw, err := NewRawConfig(r.Enabled.Raw)
+	if err != nil {
+		return false, err
+	}
+	if err := raw.Interpolate(vs); err != nil {
+		return false, fmt.Errorf("%s: enabled: %s", r.Id(), err)
+	}
+
+	switch v := raw.Config()["enabled"].(type) {
+	case bool:
+		return v, nil
+	case int:
+		return v != 0, nil
+	case string:
+		result, err := strconv.ParseBool(v)
+		if err != nil {
+			return false, fmt.Errorf(
+				"%s: enabled must be true or false, got %q", r.Id(), v)
+		}
+
+		return result, nil
+	default:
+		return false, fmt.Errorf(
+			"%s: enabled must be true or false, got %#v", r.Id(), v)
+	}
+}
+
+// Validate does some basic semantic checking of the configuration. The
+// errors are all *Diagnostic.
 func (c *Config) Validate() error {
 	var errs []error
+	addErr := func(code string, pos Pos, format string, a ...interface{}) {
+		errs = append(errs, &Diagnostic{
+			Severity: DiagError,
+			Code:     code,
+			Pos:      pos,
+			Summary:  fmt.Sprintf(format, a...),
+		})
+	}
 
 	for _, k := range c.unknownKeys {
-		errs = append(errs, fmt.Errorf(
-			"Unknown root level key: %s", k))
+		msg := fmt.Sprintf("Unknown root level key: %s", k)
+		if s := didyoumean.NameSuggestion(k, rootKeys); s != "" {
+			msg += fmt.Sprintf(" (did you mean %q?)", s)
+		}
+
+		addErr("unknown_key", Pos{}, "%s", msg)
 	}
 
 	vars := c.allVariables()
+	sourcePos := c.sourcePositions()
 	varMap := make(map[string]*Variable)
 	for _, v := range c.Variables {
 		varMap[v.Name] = v
@@ -114,12 +271,18 @@ func (c *Config) Validate() error {
 
 	for _, v := range c.Variables {
 		if v.Type() == VariableTypeUnknown {
-			errs = append(errs, fmt.Errorf(
+			addErr("invalid_variable", Pos{},
 				"Variable '%s': must be string or mapping",
-				v.Name))
+				v.Name)
 			continue
 		}
 
+		if len(v.Validations) > 0 && v.Type() != VariableTypeString {
+			addErr("invalid_variable", Pos{},
+				"Variable '%s': only string variables can have validations",
+				v.Name)
+		}
+
 		interp := false
 		fn := func(i Interpolation) (string, error) {
 			interp = true
@@ -130,9 +293,9 @@ func (c *Config) Validate() error {
 		if v.Default != nil {
 			if err := reflectwalk.Walk(v.Default, w); err == nil {
 				if interp {
-					errs = append(errs, fmt.Errorf(
+					addErr("invalid_variable", Pos{},
 						"Variable '%s': cannot contain interpolations",
-						v.Name))
+						v.Name)
 				}
 			}
 		}
@@ -148,10 +311,10 @@ func (c *Config) Validate() error {
 			}
 
 			if _, ok := varMap[uv.Name]; !ok {
-				errs = append(errs, fmt.Errorf(
+				addErr("unknown_variable", sourcePos[source],
 					"%s: unknown variable referenced: %s",
 					source,
-					uv.Name))
+					uv.Name)
 			}
 		}
 	}
@@ -164,9 +327,9 @@ func (c *Config) Validate() error {
 			if _, ok := dupped[r.Id()]; !ok {
 				dupped[r.Id()] = struct{}{}
 
-				errs = append(errs, fmt.Errorf(
+				addErr("duplicate_resource", r.Pos,
 					"%s: resource repeated multiple times",
-					r.Id()))
+					r.Id())
 			}
 		}
 
@@ -177,16 +340,28 @@ func (c *Config) Validate() error {
 	// Validate resources
 	for n, r := range resources {
 		if r.Count < 1 {
-			errs = append(errs, fmt.Errorf(
+			addErr("invalid_count", r.Pos,
 				"%s: count must be greater than or equal to 1",
-				n))
+				n)
 		}
 
 		for _, d := range r.DependsOn {
 			if _, ok := resources[d]; !ok {
-				errs = append(errs, fmt.Errorf(
+				addErr("unknown_dependency", r.Pos,
 					"%s: resource depends on non-existent resource '%s'",
-					n, d))
+					n, d)
+			}
+		}
+
+		// Whether a resource is enabled is decided before anything is
+		// created, so it can only depend on variables
+		if r.Enabled != nil {
+			for _, v := range r.Enabled.Variables {
+				if _, ok := v.(*UserVariable); !ok {
+					addErr("invalid_enabled", r.Pos,
+						"%s: enabled can only reference variables, not %s",
+						n, v.FullKey())
+				}
 			}
 		}
 	}
@@ -201,28 +376,46 @@ func (c *Config) Validate() error {
 			id := fmt.Sprintf("%s.%s", rv.Type, rv.Name)
 			r, ok := resources[id]
 			if !ok {
-				errs = append(errs, fmt.Errorf(
+				addErr("unknown_resource", sourcePos[source],
 					"%s: unknown resource '%s' referenced in variable %s",
 					source,
 					id,
-					rv.FullKey()))
+					rv.FullKey())
 				continue
 			}
 
 			// If it is a multi reference and resource has a single
 			// count, it is an error.
 			if r.Count > 1 && !rv.Multi {
-				errs = append(errs, fmt.Errorf(
+				addErr("missing_index", sourcePos[source],
 					"%s: variable '%s' must specify index for multi-count "+
 						"resource %s",
 					source,
 					rv.FullKey(),
-					id))
+					id)
 				continue
 			}
 		}
 	}
 
+	// Default tags are merged into every resource of the provider, so
+	// they can't depend on resources themselves
+	for _, pc := range c.ProviderConfigs {
+		if pc.DefaultTags == nil {
+			continue
+		}
+
+		for _, v := range pc.DefaultTags.Variables {
+			if _, ok := v.(*ResourceVariable); ok {
+				addErr("invalid_default_tags", Pos{},
+					"provider config '%s': default tags can't reference "+
+						"resources, but reference %s",
+					pc.Name,
+					v.FullKey())
+			}
+		}
+	}
+
 	// Check that all outputs are valid
 	for _, o := range c.Outputs {
 		invalid := false
@@ -233,8 +426,8 @@ func (c *Config) Validate() error {
 			}
 		}
 		if invalid {
-			errs = append(errs, fmt.Errorf(
-				"%s: output should only have 'value' field", o.Name))
+			addErr("invalid_output", Pos{},
+				"%s: output should only have 'value' field", o.Name)
 		}
 	}
 
@@ -245,6 +438,125 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// Warnings returns problems with the configuration that aren't fatal,
+// but that are likely to be mistakes. These should be shown to the user,
+// but shouldn't stop Terraform from continuing.
+func (c *Config) Warnings() []string {
+	ds := c.WarningDiagnostics()
+	if len(ds) == 0 {
+		return nil
+	}
+
+	ws := make([]string, len(ds))
+	for i, d := range ds {
+		ws[i] = d.Error()
+	}
+
+	return ws
+}
+
+// WarningDiagnostics returns the same problems as Warnings, as warning
+// diagnostics sorted by their message.
+func (c *Config) WarningDiagnostics() []*Diagnostic {
+	var ws []*Diagnostic
+	addWarning := func(code string, pos Pos, format string, a ...interface{}) {
+		ws = append(ws, &Diagnostic{
+			Severity: DiagWarning,
+			Code:     code,
+			Pos:      pos,
+			Summary:  fmt.Sprintf(format, a...),
+		})
+	}
+
+	// Find variables that are never used
+	used := make(map[string]struct{})
+	addUsed := func(raw *RawConfig) {
+		if raw == nil {
+			return
+		}
+
+		for _, v := range raw.Variables {
+			if uv, ok := v.(*UserVariable); ok {
+				used[uv.Name] = struct{}{}
+			}
+		}
+	}
+	for _, pc := range c.ProviderConfigs {
+		addUsed(pc.RawConfig)
+		addUsed(pc.DefaultTags)
+	}
+	for _, r := range c.Resources {
+		addUsed(r.RawConfig)
+		addUsed(r.Enabled)
+		for _, p := range r.Provisioners {
+			addUsed(p.RawConfig)
+			addUsed(p.ConnInfo)
+		}
+	}
+	for _, o := range c.Outputs {
+		addUsed(o.RawConfig)
+	}
+	for _, v := range c.Variables {
+		if _, ok := used[v.Name]; !ok {
+			addWarning("unused_variable", Pos{},
+				"Variable '%s': declared but never used", v.Name)
+		}
+	}
+
+	// If the configuration configures providers, a resource that doesn't
+	// match any of them is using an unconfigured provider, which is
+	// probably not what was intended.
+	if len(c.ProviderConfigs) > 0 {
+		for _, r := range c.Resources {
+			if ProviderConfigName(r.Type, c.ProviderConfigs) == "" {
+				addWarning("unconfigured_provider", r.Pos,
+					"%s: no provider configuration matches this resource, "+
+						"so its provider will be used without configuration",
+					r.Id())
+			}
+		}
+	}
+
+	// Only the provider configurations are kept out of plans. The
+	// attributes of resources are stored in the diffs of plans and in the
+	// state, and outputs in the state, so a secret used in one is stored
+	// in plain text there too.
+	for _, r := range c.Resources {
+		for _, v := range r.RawConfig.Variables {
+			if sv, ok := v.(*SecretVariable); ok {
+				addWarning("secret_in_state", r.Pos,
+					"%s: secret '%s' is used in an attribute, so its value "+
+						"will be stored in plans and the state",
+					r.Id(), sv.FullKey())
+			}
+		}
+	}
+	for _, o := range c.Outputs {
+		for _, v := range o.RawConfig.Variables {
+			if sv, ok := v.(*SecretVariable); ok {
+				addWarning("secret_in_state", Pos{},
+					"output '%s': secret '%s' is used in the value, so it "+
+						"will be stored in the state",
+					o.Name, sv.FullKey())
+			}
+		}
+	}
+
+	sort.Sort(diagnosticSort(ws))
+	return ws
+}
+
+// sourcePositions returns where the sources of variables returned by
+// allVariables are defined, for those whose position is known.
+func (c *Config) sourcePositions() map[string]Pos {
+	result := make(map[string]Pos)
+	for _, r := range c.Resources {
+		result[fmt.Sprintf("resource '%s'", r.Id())] = r.Pos
+	}
+
+	return result
+}
+
 // allVariables is a helper that returns a mapping of all the interpolated
 // variables within the configuration. This is used to verify references
 // are valid in the Validate step.
@@ -255,6 +567,11 @@ func (c *Config) allVariables() map[string][]InterpolatedVariable {
 		for _, v := range pc.RawConfig.Variables {
 			result[source] = append(result[source], v)
 		}
+		if pc.DefaultTags != nil {
+			for _, v := range pc.DefaultTags.Variables {
+				result[source] = append(result[source], v)
+			}
+		}
 	}
 
 	for _, rc := range c.Resources {
@@ -262,6 +579,11 @@ func (c *Config) allVariables() map[string][]InterpolatedVariable {
 		for _, v := range rc.RawConfig.Variables {
 			result[source] = append(result[source], v)
 		}
+		if rc.Enabled != nil {
+			for _, v := range rc.Enabled.Variables {
+				result[source] = append(result[source], v)
+			}
+		}
 	}
 
 	for _, o := range c.Outputs {
@@ -284,6 +606,9 @@ func (o *Output) mergerMerge(m merger) merger {
 	result := *o
 	result.Name = o2.Name
 	result.RawConfig = result.RawConfig.merge(o2.RawConfig)
+	if o2.Sensitive {
+		result.Sensitive = true
+	}
 
 	return &result
 }
@@ -299,6 +624,14 @@ func (c *ProviderConfig) mergerMerge(m merger) merger {
 	result.Name = c2.Name
 	result.RawConfig = result.RawConfig.merge(c2.RawConfig)
 
+	if c2.DefaultTags != nil {
+		if result.DefaultTags == nil {
+			result.DefaultTags = c2.DefaultTags
+		} else {
+			result.DefaultTags = result.DefaultTags.merge(c2.DefaultTags)
+		}
+	}
+
 	return &result
 }
 
@@ -322,6 +655,24 @@ func (r *Resource) mergerMerge(m merger) merger {
 		result.Provisioners = r2.Provisioners
 	}
 
+	if r2.Enabled != nil {
+		result.Enabled = r2.Enabled
+	}
+
+	if len(r2.Timeouts) > 0 {
+		result.Timeouts = make(map[string]time.Duration)
+		for k, v := range r.Timeouts {
+			result.Timeouts[k] = v
+		}
+		for k, v := range r2.Timeouts {
+			result.Timeouts[k] = v
+		}
+	}
+
+	if len(r2.Lifecycle.IgnoreChanges) > 0 {
+		result.Lifecycle.IgnoreChanges = r2.Lifecycle.IgnoreChanges
+	}
+
 	return &result
 }
 
@@ -361,6 +712,12 @@ func (v *Variable) Merge(v2 *Variable) *Variable {
 	if v2.Description != "" {
 		result.Description = v2.Description
 	}
+	if v2.Sensitive {
+		result.Sensitive = true
+	}
+	if len(v2.Validations) > 0 {
+		result.Validations = v2.Validations
+	}
 
 	return &result
 }
@@ -394,6 +751,75 @@ func (v *Variable) mergerMerge(m merger) merger {
 	return v.Merge(m.(*Variable))
 }
 
+// ValidateValue checks the value of the variable against its validation
+// blocks, and returns an error for each block that it doesn't follow.
+func (v *Variable) ValidateValue(value string) []error {
+	var errs []error
+	for _, vv := range v.Validations {
+		err := vv.validate(value)
+		if err == nil {
+			continue
+		}
+
+		if vv.ErrorMessage != "" {
+			err = errors.New(vv.ErrorMessage)
+		}
+		errs = append(errs, fmt.Errorf("var.%s: %s", v.Name, err))
+	}
+
+	return errs
+}
+
+func (vv *VariableValidation) validate(value string) error {
+	if vv.Regex != "" {
+		re, err := regexp.Compile(vv.Regex)
+		if err != nil {
+			return err
+		}
+		if !re.MatchString(value) {
+			return fmt.Errorf("value %q doesn't match %q", value, vv.Regex)
+		}
+	}
+
+	if len(vv.Values) > 0 {
+		found := false
+		for _, allowed := range vv.Values {
+			if value == allowed {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf(
+				"value %q must be one of: %s",
+				value, strings.Join(vv.Values, ", "))
+		}
+	}
+
+	if vv.Min != nil || vv.Max != nil {
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("value %q must be a number", value)
+		}
+		if vv.Min != nil && n < *vv.Min {
+			return fmt.Errorf(
+				"value %s must be at least %s", value, formatFloat(*vv.Min))
+		}
+		if vv.Max != nil && n > *vv.Max {
+			return fmt.Errorf(
+				"value %s must be at most %s", value, formatFloat(*vv.Max))
+		}
+	}
+
+	return nil
+}
+
+// formatFloat formats a number of a validation block the way it is
+// usually written, such as "10" rather than "1e+01".
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
 // Required tests whether a variable is required or not.
 func (v *Variable) Required() bool {
 	return v.Default == nil
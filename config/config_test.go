@@ -3,7 +3,10 @@ package config
 import (
 	"path/filepath"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 )
 
 // This is the directory where our test fixtures are.
@@ -44,6 +47,109 @@ func TestConfigValidate_countZero(t *testing.T) {
 	}
 }
 
+func TestConfigValidate_count(t *testing.T) {
+	cases := []struct {
+		Name   string
+		Config string
+		Err    bool
+	}{
+		{
+			"default count",
+			`resource "aws_instance" "web" {}`,
+			false,
+		},
+
+		{
+			"count",
+			`resource "aws_instance" "web" { count = 3 }`,
+			false,
+		},
+
+		{
+			"zero count",
+			`resource "aws_instance" "web" { count = 0 }`,
+			true,
+		},
+
+		{
+			"negative count",
+			`resource "aws_instance" "web" { count = -1 }`,
+			true,
+		},
+
+		{
+			"splat of a multi resource",
+			`
+resource "aws_instance" "web" { count = 3 }
+resource "aws_elb" "lb" { instances = ["${aws_instance.web.*.id}"] }
+`,
+			false,
+		},
+
+		{
+			"index of a multi resource",
+			`
+resource "aws_instance" "web" { count = 3 }
+resource "aws_eip" "ip" { instance = "${aws_instance.web.1.id}" }
+`,
+			false,
+		},
+
+		{
+			"multi resource without an index",
+			`
+resource "aws_instance" "web" { count = 3 }
+resource "aws_eip" "ip" { instance = "${aws_instance.web.id}" }
+`,
+			true,
+		},
+
+		{
+			"splat of a single resource",
+			`
+resource "aws_instance" "web" {}
+resource "aws_elb" "lb" { instances = ["${aws_instance.web.*.id}"] }
+`,
+			false,
+		},
+
+		{
+			"index of a multi resource in an output",
+			`
+resource "aws_instance" "web" { count = 3 }
+output "ip" { value = "${aws_instance.web.0.private_ip}" }
+`,
+			false,
+		},
+	}
+
+	for _, tc := range cases {
+		c, err := LoadString("main.tf", tc.Config)
+		if err != nil {
+			t.Fatalf("%s: err: %s", tc.Name, err)
+		}
+
+		err = c.Validate()
+		if (err != nil) != tc.Err {
+			t.Fatalf("%s: err: %s", tc.Name, err)
+		}
+	}
+}
+
+func TestConfigValidate_defaultTagsResource(t *testing.T) {
+	c := testConfig(t, "validate-default-tags-resource")
+	if err := c.Validate(); err == nil {
+		t.Fatal("should not be valid")
+	}
+}
+
+func TestConfigValidate_enabledResourceVar(t *testing.T) {
+	c := testConfig(t, "validate-enabled-resource-var")
+	if err := c.Validate(); err == nil {
+		t.Fatal("should not be valid")
+	}
+}
+
 func TestConfigValidate_dupResource(t *testing.T) {
 	c := testConfig(t, "validate-dup-resource")
 	if err := c.Validate(); err == nil {
@@ -58,6 +164,54 @@ func TestConfigValidate_outputBadField(t *testing.T) {
 	}
 }
 
+func TestConfigWarnings(t *testing.T) {
+	c := testConfig(t, "validate-warnings")
+	if err := c.Validate(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := []string{
+		"Variable 'unused': declared but never used",
+		"do_droplet.db: no provider configuration matches this resource, " +
+			"so its provider will be used without configuration",
+		"aws_instance.web: secret 'secret.env.USER_DATA' is used in an " +
+			"attribute, so its value will be stored in plans and the state",
+		"output 'token': secret 'secret.env.TOKEN' is used in the value, " +
+			"so it will be stored in the state",
+	}
+	sort.Strings(expected)
+
+	actual := c.Warnings()
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestConfigWarningDiagnostics(t *testing.T) {
+	c := testConfig(t, "validate-warnings")
+
+	ds := c.WarningDiagnostics()
+	if len(ds) != 4 {
+		t.Fatalf("bad: %#v", ds)
+	}
+	for _, d := range ds {
+		if d.Severity != DiagWarning || d.Code == "" {
+			t.Fatalf("bad: %#v", d)
+		}
+		if d.Code == "secret_in_state" &&
+			strings.HasPrefix(d.Summary, "aws_instance") && d.Pos.Line == 0 {
+			t.Fatalf("bad: %#v", d)
+		}
+	}
+}
+
+func TestConfigWarnings_good(t *testing.T) {
+	c := testConfig(t, "validate-good")
+	if ws := c.Warnings(); len(ws) > 0 {
+		t.Fatalf("bad: %#v", ws)
+	}
+}
+
 func TestConfigValidate_unknownThing(t *testing.T) {
 	c := testConfig(t, "validate-unknownthing")
 	if err := c.Validate(); err == nil {
@@ -65,6 +219,17 @@ func TestConfigValidate_unknownThing(t *testing.T) {
 	}
 }
 
+func TestConfigValidate_unknownThingTypo(t *testing.T) {
+	c := testConfig(t, "validate-unknownthing-typo")
+	err := c.Validate()
+	if err == nil {
+		t.Fatal("should not be valid")
+	}
+	if !strings.Contains(err.Error(), `did you mean "resource"?`) {
+		t.Fatalf("bad: %s", err)
+	}
+}
+
 func TestConfigValidate_unknownResourceVar(t *testing.T) {
 	c := testConfig(t, "validate-unknown-resource-var")
 	if err := c.Validate(); err == nil {
@@ -86,6 +251,21 @@ func TestConfigValidate_unknownVar(t *testing.T) {
 	}
 }
 
+func TestConfigValidate_diagnostics(t *testing.T) {
+	c := testConfig(t, "validate-unknown-resource-var")
+
+	ds := Diagnostics(c.Validate(), "other")
+	if len(ds) != 1 {
+		t.Fatalf("bad: %#v", ds)
+	}
+	if ds[0].Code != "unknown_resource" || ds[0].Severity != DiagError {
+		t.Fatalf("bad: %#v", ds[0])
+	}
+	if filepath.Base(ds[0].Pos.Filename) != "main.tf" || ds[0].Pos.Line != 4 {
+		t.Fatalf("bad: %#v", ds[0])
+	}
+}
+
 func TestConfigValidate_varDefault(t *testing.T) {
 	c := testConfig(t, "validate-var-default")
 	if err := c.Validate(); err != nil {
@@ -121,6 +301,152 @@ func TestProviderConfigName(t *testing.T) {
 	}
 }
 
+func TestResourceIsEnabled(t *testing.T) {
+	cases := []struct {
+		Value  interface{}
+		Result bool
+		Err    bool
+	}{
+		{true, true, false},
+		{false, false, false},
+		{"${var.foo}", true, false},
+		{"${var.bar}", false, false},
+		{"${var.unknown}", false, true},
+		{"maybe", false, true},
+	}
+
+	vs := map[string]string{
+		"var.foo": "true",
+		"var.bar": "0",
+	}
+
+	for i, tc := range cases {
+		raw, err := NewRawConfig(map[string]interface{}{"enabled": tc.Value})
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		r := &Resource{Type: "aws_instance", Name: "foo", Enabled: raw}
+		actual, err := r.IsEnabled(vs)
+		if (err != nil) != tc.Err {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+		if actual != tc.Result {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+
+	r := &Resource{Type: "aws_instance", Name: "foo"}
+	if enabled, err := r.IsEnabled(nil); err != nil || !enabled {
+		t.Fatalf("bad: %#v %s", enabled, err)
+	}
+}
+
+func TestResourceMergerMerge_timeouts(t *testing.T) {
+	raw, err := NewRawConfig(map[string]interface{}{"foo": "bar"})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	r1 := &Resource{
+		Name:      "foo",
+		RawConfig: raw,
+		Timeouts: map[string]time.Duration{
+			"create": time.Minute,
+			"delete": time.Minute,
+		},
+	}
+	r2 := &Resource{
+		Name:      "foo",
+		RawConfig: raw,
+		Timeouts:  map[string]time.Duration{"create": time.Hour},
+	}
+
+	actual := r1.mergerMerge(r2).(*Resource).Timeouts
+	expected := map[string]time.Duration{
+		"create": time.Hour,
+		"delete": time.Minute,
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestVariableValidateValue(t *testing.T) {
+	one, ten := 1.0, 10.5
+	cases := []struct {
+		Validation *VariableValidation
+		Value      string
+		Err        string
+	}{
+		{
+			&VariableValidation{Regex: "^[a-z]+$"},
+			"foo",
+			"",
+		},
+
+		{
+			&VariableValidation{Regex: "^[a-z]+$"},
+			"foo1",
+			`var.foo: value "foo1" doesn't match "^[a-z]+$"`,
+		},
+
+		{
+			&VariableValidation{Values: []string{"dev", "prod"}},
+			"stage",
+			`var.foo: value "stage" must be one of: dev, prod`,
+		},
+
+		{
+			&VariableValidation{Min: &one, Max: &ten},
+			"10.5",
+			"",
+		},
+
+		{
+			&VariableValidation{Min: &one},
+			"0",
+			"var.foo: value 0 must be at least 1",
+		},
+
+		{
+			&VariableValidation{Max: &ten},
+			"11",
+			"var.foo: value 11 must be at most 10.5",
+		},
+
+		{
+			&VariableValidation{Min: &one},
+			"foo",
+			`var.foo: value "foo" must be a number`,
+		},
+
+		{
+			&VariableValidation{
+				Values:       []string{"dev", "prod"},
+				ErrorMessage: "must be dev or prod",
+			},
+			"stage",
+			"var.foo: must be dev or prod",
+		},
+	}
+
+	for i, tc := range cases {
+		v := &Variable{
+			Name:        "foo",
+			Validations: []*VariableValidation{tc.Validation},
+		}
+
+		var actual string
+		if errs := v.ValidateValue(tc.Value); len(errs) > 0 {
+			actual = errs[0].Error()
+		}
+		if actual != tc.Err {
+			t.Fatalf("%d: bad: %s", i, actual)
+		}
+	}
+}
+
 func TestVariableDefaultsMap(t *testing.T) {
 	cases := []struct {
 		Default interface{}
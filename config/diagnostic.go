@@ -0,0 +1,118 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/multierror"
+)
+
+// DiagnosticSeverity is whether a diagnostic is an error or a warning.
+type DiagnosticSeverity string
+
+const (
+	DiagError   DiagnosticSeverity = "error"
+	DiagWarning DiagnosticSeverity = "warning"
+)
+
+// Diagnostic is an error or warning about a configuration. Code names the
+// kind of problem, such as "unknown_variable", and Pos is where in the
+// configuration it is, if known, so that tools such as editors can show
+// it in place.
+//
+// A Diagnostic is an error, so it can be returned wherever errors are.
+type Diagnostic struct {
+	Severity DiagnosticSeverity
+	Code     string
+	Pos      Pos
+
+	// Resource is the ID of the resource the diagnostic is about, if it
+	// is about a single one.
+	Resource string
+
+	Summary string
+	Detail  string
+}
+
+// Error returns the diagnostic as it's shown to users. Diagnostics about
+// a resource lead with the resource and where it's defined, so that they
+// are easy to find in the configuration.
+func (d *Diagnostic) Error() string {
+	msg := d.Summary
+	if d.Detail != "" {
+		msg += ": " + d.Detail
+	}
+
+	if d.Resource == "" {
+		return msg
+	}
+
+	if d.Severity == DiagWarning {
+		msg = "warning: " + msg
+	}
+	msg = fmt.Sprintf("resource '%s': %s", d.Resource, msg)
+	if d.Pos.Filename != "" {
+		msg = fmt.Sprintf("%s: %s", d.Pos, msg)
+	}
+
+	return msg
+}
+
+// Diagnostics returns err as diagnostics. Errors that are diagnostics
+// already are returned as they are, errors with multiple errors are
+// flattened, and any other error becomes an error with the given code.
+func Diagnostics(err error, code string) []*Diagnostic {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *Diagnostic:
+		return []*Diagnostic{e}
+	case *multierror.Error:
+		var result []*Diagnostic
+		for _, err := range e.Errors {
+			result = append(result, Diagnostics(err, code)...)
+		}
+
+		return result
+	default:
+		return []*Diagnostic{&Diagnostic{
+			Severity: DiagError,
+			Code:     code,
+			Summary:  err.Error(),
+		}}
+	}
+}
+
+// parseErrorLine matches the line in the errors of the HCL and JSON
+// parsers, which are either "At 3:5: ..." or "line 3, column 5: ...".
+var parseErrorLine = regexp.MustCompile(`(?i)(?:at (\d+):\d+|line (\d+))`)
+
+// parseDiagnostic returns the error from parsing the file at path as a
+// diagnostic, with the line the parser stopped at if it says.
+func parseDiagnostic(path string, err error) *Diagnostic {
+	d := &Diagnostic{
+		Severity: DiagError,
+		Code:     "parse",
+		Pos:      Pos{Filename: path},
+		Summary:  fmt.Sprintf("Error parsing %s: %s", path, err),
+	}
+
+	if m := parseErrorLine.FindStringSubmatch(err.Error()); m != nil {
+		line := m[1]
+		if line == "" {
+			line = m[2]
+		}
+		d.Pos.Line, _ = strconv.Atoi(line)
+	}
+
+	return d
+}
+
+// diagnosticSort implements sort.Interface and sorts diagnostics by
+// their message.
+type diagnosticSort []*Diagnostic
+
+func (s diagnosticSort) Len() int           { return len(s) }
+func (s diagnosticSort) Less(i, j int) bool { return s[i].Error() < s[j].Error() }
+func (s diagnosticSort) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
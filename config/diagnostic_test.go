@@ -0,0 +1,98 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/multierror"
+)
+
+func TestDiagnosticError(t *testing.T) {
+	cases := []struct {
+		Diagnostic *Diagnostic
+		Expected   string
+	}{
+		{
+			&Diagnostic{Summary: "bad"},
+			"bad",
+		},
+
+		{
+			&Diagnostic{Summary: "bad", Detail: "very"},
+			"bad: very",
+		},
+
+		{
+			&Diagnostic{
+				Pos:     Pos{Filename: "main.tf", Line: 3},
+				Summary: "bad",
+			},
+			"bad",
+		},
+
+		{
+			&Diagnostic{
+				Severity: DiagError,
+				Pos:      Pos{Filename: "main.tf", Line: 3},
+				Resource: "aws_instance.foo",
+				Summary:  "bad",
+			},
+			"main.tf:3: resource 'aws_instance.foo': bad",
+		},
+
+		{
+			&Diagnostic{
+				Severity: DiagWarning,
+				Resource: "aws_instance.foo",
+				Summary:  "bad",
+			},
+			"resource 'aws_instance.foo': warning: bad",
+		},
+	}
+
+	for i, tc := range cases {
+		if actual := tc.Diagnostic.Error(); actual != tc.Expected {
+			t.Fatalf("%d: bad: %s", i, actual)
+		}
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	d := &Diagnostic{Severity: DiagError, Code: "foo", Summary: "foo"}
+	err := &multierror.Error{Errors: []error{d, errors.New("bar")}}
+
+	actual := Diagnostics(err, "other")
+	expected := []*Diagnostic{
+		d,
+		&Diagnostic{Severity: DiagError, Code: "other", Summary: "bar"},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	if actual := Diagnostics(nil, "other"); actual != nil {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestParseDiagnostic(t *testing.T) {
+	cases := []struct {
+		Err  string
+		Line int
+	}{
+		{"At 4:1: object expected closing RBRACE got: EOF", 4},
+		{"line 12, column 3: syntax error", 12},
+		{"unknown config format", 0},
+	}
+
+	for _, tc := range cases {
+		d := parseDiagnostic("main.tf", errors.New(tc.Err))
+		if d.Code != "parse" || d.Pos.Filename != "main.tf" || d.Pos.Line != tc.Line {
+			t.Fatalf("%s: bad: %#v", tc.Err, d)
+		}
+		if d.Error() != "Error parsing main.tf: "+tc.Err {
+			t.Fatalf("%s: bad: %s", tc.Err, d.Error())
+		}
+	}
+}
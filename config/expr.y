@@ -22,7 +22,7 @@ import (
 	args []Interpolation
 }
 
-%type	<args> args
+%type	<args> args exprs
 %type   <expr> expr
 %type   <str> string
 %type   <variable> variable
@@ -62,13 +62,19 @@ args:
 	{
 		$$ = nil
 	}
-|	expr COMMA expr
+|	exprs
 	{
-		$$ = append($$, $1, $3)
+		$$ = $1
+	}
+
+exprs:
+	expr
+	{
+		$$ = []Interpolation{$1}
 	}
-|	expr
+|	exprs COMMA expr
 	{
-		$$ = append($$, $1)
+		$$ = append($1, $3)
 	}
 
 string:
@@ -2,6 +2,7 @@ package config
 
 import (
 	"bytes"
+	"fmt"
 	"log"
 	"unicode"
 	"unicode/utf8"
@@ -61,6 +62,7 @@ func (x *exprLex) lexId(yylval *exprSymType) int {
 			c != '-' &&
 			c != '.' &&
 			c != '*' &&
+			c != '/' &&
 			!unicode.IsLetter(c) &&
 			!unicode.IsNumber(c) {
 			x.backup()
@@ -68,11 +70,17 @@ func (x *exprLex) lexId(yylval *exprSymType) int {
 		}
 
 		if _, err := b.WriteRune(c); err != nil {
-			log.Printf("ERR: %s", err)
+			log.Printf("[ERROR] %s", err)
 			return lexEOF
 		}
 	}
 
+	// Nothing was read, so the next character can't start any token
+	if b.Len() == 0 {
+		x.Error(fmt.Sprintf("unexpected character: %q", x.peek()))
+		return lexEOF
+	}
+
 	yylval.str = b.String()
 	return IDENTIFIER
 }
@@ -82,7 +90,8 @@ func (x *exprLex) lexString(yylval *exprSymType) int {
 	for {
 		c := x.next()
 		if c == lexEOF {
-			break
+			x.Error("unterminated string")
+			return lexEOF
 		}
 
 		// String end
@@ -91,7 +100,7 @@ func (x *exprLex) lexString(yylval *exprSymType) int {
 		}
 
 		if _, err := b.WriteRune(c); err != nil {
-			log.Printf("ERR: %s", err)
+			log.Printf("[ERROR] %s", err)
 			return lexEOF
 		}
 	}
@@ -127,5 +136,5 @@ func (x *exprLex) backup() {
 
 // The parser calls this method on a parse error.
 func (x *exprLex) Error(s string) {
-	log.Printf("parse error: %s", s)
+	exprErrors = append(exprErrors, fmt.Errorf("parse error: %s", s))
 }
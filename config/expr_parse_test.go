@@ -34,6 +34,29 @@ func TestExprParse(t *testing.T) {
 			false,
 		},
 
+		{
+			"secret.vault.secret/db.password",
+			&VariableInterpolation{
+				Variable: &SecretVariable{
+					Backend: "vault",
+					Key:     "secret/db.password",
+					key:     "secret.vault.secret/db.password",
+				},
+			},
+			false,
+		},
+
+		{
+			"git.short_commit",
+			&VariableInterpolation{
+				Variable: &GitVariable{
+					Field: "short_commit",
+					key:   "git.short_commit",
+				},
+			},
+			false,
+		},
+
 		{
 			"lookup(var.foo, var.bar)",
 			&FunctionInterpolation{
@@ -56,6 +79,40 @@ func TestExprParse(t *testing.T) {
 			false,
 		},
 
+		{
+			`concat("a", "b", "c")`,
+			&FunctionInterpolation{
+				Func: nil, // Funcs["concat"]
+				Args: []Interpolation{
+					&LiteralInterpolation{Literal: "a"},
+					&LiteralInterpolation{Literal: "b"},
+					&LiteralInterpolation{Literal: "c"},
+				},
+			},
+			false,
+		},
+
+		{
+			`formatlist("%s:80", aws_instance.web.*.private_ip)`,
+			&FunctionInterpolation{
+				Func: nil, // Funcs["formatlist"]
+				Args: []Interpolation{
+					&LiteralInterpolation{Literal: "%s:80"},
+					&VariableInterpolation{
+						Variable: &ResourceVariable{
+							Type:  "aws_instance",
+							Name:  "web",
+							Field: "private_ip",
+							Multi: true,
+							Index: -1,
+							key:   "aws_instance.web.*.private_ip",
+						},
+					},
+				},
+			},
+			false,
+		},
+
 		{
 			"lookup(var.foo, lookup(var.baz, var.bar))",
 			&FunctionInterpolation{
@@ -88,6 +145,36 @@ func TestExprParse(t *testing.T) {
 			},
 			false,
 		},
+
+		{
+			"(0",
+			nil,
+			true,
+		},
+
+		{
+			`lookup(var.foo`,
+			nil,
+			true,
+		},
+
+		{
+			`"foo`,
+			nil,
+			true,
+		},
+
+		{
+			"var.foo + 1",
+			nil,
+			true,
+		},
+
+		{
+			"",
+			nil,
+			true,
+		},
 	}
 
 	for i, tc := range cases {
@@ -0,0 +1,32 @@
+// +build gofuzz
+
+package config
+
+// Fuzz is the entry point for go-fuzz (github.com/dvyukov/go-fuzz). It
+// loads data as a configuration file and validates it, which should
+// only ever return errors for malformed configurations, never panic:
+//
+//	go-fuzz-build github.com/hashicorp/terraform/config
+//	go-fuzz -bin=config-fuzz.zip -workdir=fuzz/config
+func Fuzz(data []byte) int {
+	c, err := LoadString("fuzz.tf", string(data))
+	if err != nil {
+		return 0
+	}
+
+	c.Validate()
+	c.Warnings()
+	return 1
+}
+
+// FuzzExpr is the entry point for fuzzing the interpolation parser. Build
+// it with "go-fuzz-build -func FuzzExpr".
+func FuzzExpr(data []byte) int {
+	i, err := ExprParse(string(data))
+	if err != nil {
+		return 0
+	}
+
+	i.Variables()
+	return 1
+}
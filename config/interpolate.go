@@ -11,6 +11,10 @@ import (
 var funcRegexp *regexp.Regexp = regexp.MustCompile(
 	`(?i)([a-z0-9_]+)\(\s*(?:([.a-z0-9_]+)\s*,\s*)*([.a-z0-9_]+)\s*\)`)
 
+// InterpSplitDelim is the delimiter between the values of a list, such
+// as the values of a multi-variable like "${aws_instance.foo.*.id}".
+const InterpSplitDelim = ","
+
 // Interpolation is something that can be contained in a "${}" in a
 // configuration value.
 //
@@ -65,6 +69,24 @@ type ResourceVariable struct {
 	key string
 }
 
+// A GitVariable is a variable that is referencing the git revision of
+// the configuration, such as "${git.commit}"
+type GitVariable struct {
+	Field string // Field of the revision, i.e. "commit"
+
+	key string
+}
+
+// A SecretVariable is a variable that is referencing a secret that is
+// read from a secret backend when it is interpolated, such as
+// "${secret.env.DB_PASSWORD}"
+type SecretVariable struct {
+	Backend string // Secret backend, i.e. "env"
+	Key     string // Key of the secret within the backend
+
+	key string
+}
+
 // A UserVariable is a variable that is referencing a user variable
 // that is inputted from outside the configuration. This looks like
 // "${var.foo}"
@@ -76,6 +98,12 @@ type UserVariable struct {
 }
 
 func NewInterpolatedVariable(v string) (InterpolatedVariable, error) {
+	if strings.HasPrefix(v, "secret.") {
+		return NewSecretVariable(v)
+	}
+	if strings.HasPrefix(v, "git.") {
+		return NewGitVariable(v)
+	}
 	if !strings.HasPrefix(v, "var.") {
 		return NewResourceVariable(v)
 	}
@@ -190,6 +218,53 @@ func (v *ResourceVariable) FullKey() string {
 	return v.key
 }
 
+// GitFields are the fields of the git revision that can be interpolated.
+var GitFields = []string{"commit", "short_commit", "branch"}
+
+func NewGitVariable(key string) (*GitVariable, error) {
+	field := key[len("git."):]
+	for _, f := range GitFields {
+		if field == f {
+			return &GitVariable{Field: field, key: key}, nil
+		}
+	}
+
+	return nil, fmt.Errorf(
+		"%s: unknown git field, must be one of: %s",
+		key, strings.Join(GitFields, ", "))
+}
+
+func (v *GitVariable) FullKey() string {
+	return v.key
+}
+
+func (v *GitVariable) GoString() string {
+	return fmt.Sprintf("*%#v", *v)
+}
+
+func NewSecretVariable(key string) (*SecretVariable, error) {
+	parts := strings.SplitN(key, ".", 3)
+	if len(parts) < 3 || parts[1] == "" || parts[2] == "" {
+		return nil, fmt.Errorf(
+			"%s: secret variables must be three parts: secret.backend.key",
+			key)
+	}
+
+	return &SecretVariable{
+		Backend: parts[1],
+		Key:     parts[2],
+		key:     key,
+	}, nil
+}
+
+func (v *SecretVariable) FullKey() string {
+	return v.key
+}
+
+func (v *SecretVariable) GoString() string {
+	return fmt.Sprintf("*%#v", *v)
+}
+
 func NewUserVariable(key string) (*UserVariable, error) {
 	name := key[len("var."):]
 	elem := ""
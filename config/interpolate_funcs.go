@@ -2,20 +2,227 @@ package config
 
 import (
 	"bytes"
+	"crypto/rand"
+	"encoding/base64"
 	"fmt"
 	"io/ioutil"
+	"math/big"
+	"mime/multipart"
+	"net"
+	"net/textproto"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// cloudInitBoundary is the boundary between the parts of the payloads
+// built by the "cloudinit" function. It is fixed so that the same parts
+// always give the same payload, and don't cause a diff.
+const cloudInitBoundary = "==TERRAFORM-CLOUDINIT-BOUNDARY=="
+
+// cloudInitTypes are the content types of the parts of a cloud-init
+// payload, by the first line that cloud-init uses to recognize them.
+var cloudInitTypes = []struct {
+	Prefix string
+	Type   string
+}{
+	{"#!", "text/x-shellscript"},
+	{"#cloud-config", "text/cloud-config"},
+	{"#cloud-boothook", "text/cloud-boothook"},
+	{"#include", "text/x-include-url"},
+	{"#part-handler", "text/part-handler"},
+	{"#upstart-job", "text/upstart-job"},
+}
+
 // Funcs is the mapping of built-in functions for configuration.
 var Funcs map[string]InterpolationFunc
 
 func init() {
 	Funcs = map[string]InterpolationFunc{
-		"concat": interpolationFuncConcat,
-		"file":   interpolationFuncFile,
-		"lookup": interpolationFuncLookup,
+		"base64decode": interpolationFuncBase64Decode,
+		"base64encode": interpolationFuncBase64Encode,
+		"cidrhost":     interpolationFuncCidrHost,
+		"cidrsubnet":   interpolationFuncCidrSubnet,
+		"cloudinit":    interpolationFuncCloudInit,
+		"concat":       interpolationFuncConcat,
+		"file":         interpolationFuncFile,
+		"formatlist":   interpolationFuncFormatList,
+		"join":         interpolationFuncJoin,
+		"lookup":       interpolationFuncLookup,
+		"lower":        interpolationFuncLower,
+		"replace":      interpolationFuncReplace,
+		"substr":       interpolationFuncSubstr,
+		"timestamp":    interpolationFuncTimestamp,
+		"upper":        interpolationFuncUpper,
+		"uuid":         interpolationFuncUUID,
+	}
+}
+
+// interpolationFuncBase64Decode implements the "base64decode" function
+// that decodes a string encoded with standard base64.
+func interpolationFuncBase64Decode(
+	vs map[string]string, args ...string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf(
+			"base64decode expects 1 arguments, got %d", len(args))
+	}
+
+	data, err := base64.StdEncoding.DecodeString(args[0])
+	if err != nil {
+		return "", fmt.Errorf("base64decode: %s", err)
+	}
+
+	return string(data), nil
+}
+
+// interpolationFuncBase64Encode implements the "base64encode" function
+// that encodes a string with standard base64.
+func interpolationFuncBase64Encode(
+	vs map[string]string, args ...string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf(
+			"base64encode expects 1 arguments, got %d", len(args))
 	}
+
+	return base64.StdEncoding.EncodeToString([]byte(args[0])), nil
+}
+
+// interpolationFuncCidrHost implements the "cidrhost" function that
+// computes the IP address of the given host number within a network
+// prefix, such as "10.0.0.5" for the host 5 of "10.0.0.0/24".
+func interpolationFuncCidrHost(
+	vs map[string]string, args ...string) (string, error) {
+	if len(args) != 2 {
+		return "", fmt.Errorf(
+			"cidrhost expects 2 arguments, got %d", len(args))
+	}
+
+	_, network, err := net.ParseCIDR(args[0])
+	if err != nil {
+		return "", fmt.Errorf("cidrhost: invalid prefix: %s", err)
+	}
+	hostnum, err := strconv.Atoi(args[1])
+	if err != nil || hostnum < 0 {
+		return "", fmt.Errorf(
+			"cidrhost: host number must be a non-negative number, got %q",
+			args[1])
+	}
+
+	ones, bits := network.Mask.Size()
+	host := big.NewInt(int64(hostnum))
+	if host.BitLen() > bits-ones {
+		return "", fmt.Errorf(
+			"cidrhost: prefix %s has no host %d", args[0], hostnum)
+	}
+
+	ip := ipToInt(network.IP)
+	ip.Or(ip, host)
+	return intToIP(ip, len(network.IP)).String(), nil
+}
+
+// interpolationFuncCidrSubnet implements the "cidrsubnet" function that
+// computes a subnet of a network prefix. The prefix is extended by the
+// given number of bits, and the subnet number fills the new bits, so
+// the subnet 2 of "10.0.0.0/16" with 8 new bits is "10.0.2.0/24".
+func interpolationFuncCidrSubnet(
+	vs map[string]string, args ...string) (string, error) {
+	if len(args) != 3 {
+		return "", fmt.Errorf(
+			"cidrsubnet expects 3 arguments, got %d", len(args))
+	}
+
+	_, network, err := net.ParseCIDR(args[0])
+	if err != nil {
+		return "", fmt.Errorf("cidrsubnet: invalid prefix: %s", err)
+	}
+	newbits, err := strconv.Atoi(args[1])
+	if err != nil || newbits < 0 {
+		return "", fmt.Errorf(
+			"cidrsubnet: new bits must be a non-negative number, got %q",
+			args[1])
+	}
+	netnum, err := strconv.Atoi(args[2])
+	if err != nil || netnum < 0 {
+		return "", fmt.Errorf(
+			"cidrsubnet: subnet number must be a non-negative number, got %q",
+			args[2])
+	}
+
+	ones, bits := network.Mask.Size()
+	if ones+newbits > bits {
+		return "", fmt.Errorf(
+			"cidrsubnet: prefix %s can't be extended by %d bits",
+			args[0], newbits)
+	}
+
+	num := big.NewInt(int64(netnum))
+	if num.BitLen() > newbits {
+		return "", fmt.Errorf(
+			"cidrsubnet: subnet number %d doesn't fit in %d bits",
+			netnum, newbits)
+	}
+
+	ip := ipToInt(network.IP)
+	ip.Or(ip, num.Lsh(num, uint(bits-ones-newbits)))
+	return fmt.Sprintf(
+		"%s/%d", intToIP(ip, len(network.IP)), ones+newbits), nil
+}
+
+// interpolationFuncCloudInit implements the "cloudinit" function that
+// assembles a multi-part MIME cloud-init payload, such as for user data,
+// with one part for each argument. The content type of each part is
+// detected from its first line, the same way cloud-init does it.
+func interpolationFuncCloudInit(
+	vs map[string]string, args ...string) (string, error) {
+	if len(args) == 0 {
+		return "", fmt.Errorf("cloudinit expects at least 1 argument")
+	}
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	if err := w.SetBoundary(cloudInitBoundary); err != nil {
+		return "", err
+	}
+
+	buf.WriteString(fmt.Sprintf(
+		"Content-Type: multipart/mixed; boundary=\"%s\"\r\n"+
+			"MIME-Version: 1.0\r\n\r\n",
+		cloudInitBoundary))
+
+	for i, a := range args {
+		if strings.Contains(a, cloudInitBoundary) {
+			return "", fmt.Errorf(
+				"cloudinit part %d contains the MIME boundary", i+1)
+		}
+
+		contentType := "text/plain"
+		for _, t := range cloudInitTypes {
+			if strings.HasPrefix(a, t.Prefix) {
+				contentType = t.Type
+				break
+			}
+		}
+
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Type", contentType+"; charset=\"utf-8\"")
+		h.Set("Content-Disposition", fmt.Sprintf(
+			"attachment; filename=\"part-%03d\"", i+1))
+		h.Set("MIME-Version", "1.0")
+
+		p, err := w.CreatePart(h)
+		if err != nil {
+			return "", err
+		}
+		if _, err := p.Write([]byte(a)); err != nil {
+			return "", err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
 }
 
 // interpolationFuncConcat implements the "concat" function that allows
@@ -50,6 +257,66 @@ func interpolationFuncFile(
 	return string(data), nil
 }
 
+// interpolationFuncFormatList implements the "formatlist" function that
+// formats each element of lists, such as the values of a multi-variable,
+// and returns the list of the results. The first argument is the format,
+// as with fmt.Sprintf. The other arguments that are lists must have the
+// same number of elements, and each result is formatted with the
+// elements at its index. Arguments that aren't lists are used for every
+// result.
+func interpolationFuncFormatList(
+	vs map[string]string, args ...string) (string, error) {
+	if len(args) < 1 {
+		return "", fmt.Errorf("formatlist expects at least 1 argument")
+	}
+
+	format := args[0]
+	lists := make([][]string, len(args)-1)
+	n := 1
+	for i, a := range args[1:] {
+		lists[i] = strings.Split(a, InterpSplitDelim)
+		if len(lists[i]) == 1 {
+			continue
+		}
+
+		if n > 1 && len(lists[i]) != n {
+			return "", fmt.Errorf(
+				"formatlist: argument %d has %d elements, expected %d",
+				i+2, len(lists[i]), n)
+		}
+		n = len(lists[i])
+	}
+
+	result := make([]string, n)
+	for i := 0; i < n; i++ {
+		fmtArgs := make([]interface{}, len(lists))
+		for j, l := range lists {
+			if len(l) == 1 {
+				fmtArgs[j] = l[0]
+			} else {
+				fmtArgs[j] = l[i]
+			}
+		}
+
+		result[i] = fmt.Sprintf(format, fmtArgs...)
+	}
+
+	return strings.Join(result, InterpSplitDelim), nil
+}
+
+// interpolationFuncJoin implements the "join" function that joins the
+// elements of a list, such as the values of a multi-variable, with a
+// delimiter.
+func interpolationFuncJoin(
+	vs map[string]string, args ...string) (string, error) {
+	if len(args) != 2 {
+		return "", fmt.Errorf(
+			"join expects 2 arguments, got %d", len(args))
+	}
+
+	return strings.Replace(args[1], InterpSplitDelim, args[0], -1), nil
+}
+
 // interpolationFuncLookup implements the "lookup" function that allows
 // dynamic lookups of map types within a Terraform configuration.
 func interpolationFuncLookup(
@@ -69,3 +336,125 @@ func interpolationFuncLookup(
 
 	return v, nil
 }
+
+// interpolationFuncLower implements the "lower" function that converts
+// a string to lower case.
+func interpolationFuncLower(
+	vs map[string]string, args ...string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf(
+			"lower expects 1 arguments, got %d", len(args))
+	}
+
+	return strings.ToLower(args[0]), nil
+}
+
+// interpolationFuncReplace implements the "replace" function that
+// replaces all the occurrences of a substring in a string.
+func interpolationFuncReplace(
+	vs map[string]string, args ...string) (string, error) {
+	if len(args) != 3 {
+		return "", fmt.Errorf(
+			"replace expects 3 arguments, got %d", len(args))
+	}
+
+	return strings.Replace(args[0], args[1], args[2], -1), nil
+}
+
+// interpolationFuncSubstr implements the "substr" function that
+// extracts the part of a string starting at an offset, with the given
+// length. A negative length extracts up to the end of the string.
+func interpolationFuncSubstr(
+	vs map[string]string, args ...string) (string, error) {
+	if len(args) != 3 {
+		return "", fmt.Errorf(
+			"substr expects 3 arguments, got %d", len(args))
+	}
+
+	offset, err := strconv.Atoi(args[1])
+	if err != nil {
+		return "", fmt.Errorf(
+			"substr: offset must be a number, got %q", args[1])
+	}
+	length, err := strconv.Atoi(args[2])
+	if err != nil {
+		return "", fmt.Errorf(
+			"substr: length must be a number, got %q", args[2])
+	}
+
+	s := []rune(args[0])
+	if offset < 0 || offset > len(s) {
+		return "", fmt.Errorf(
+			"substr: offset %d is out of range for %q", offset, args[0])
+	}
+
+	end := offset + length
+	if length < 0 || end > len(s) {
+		end = len(s)
+	}
+
+	return string(s[offset:end]), nil
+}
+
+// interpolationFuncTimestamp implements the "timestamp" function that
+// returns the current time in UTC, formatted as RFC 3339. The time is
+// taken each time the configuration is interpolated, so it changes
+// between runs, and between planning and applying.
+func interpolationFuncTimestamp(
+	vs map[string]string, args ...string) (string, error) {
+	if len(args) != 0 {
+		return "", fmt.Errorf(
+			"timestamp expects 0 arguments, got %d", len(args))
+	}
+
+	return time.Now().UTC().Format(time.RFC3339), nil
+}
+
+// interpolationFuncUpper implements the "upper" function that converts
+// a string to upper case.
+func interpolationFuncUpper(
+	vs map[string]string, args ...string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf(
+			"upper expects 1 arguments, got %d", len(args))
+	}
+
+	return strings.ToUpper(args[0]), nil
+}
+
+// interpolationFuncUUID implements the "uuid" function that returns a
+// new random (version 4) UUID. Like "timestamp", a new one is generated
+// each time the configuration is interpolated.
+func interpolationFuncUUID(
+	vs map[string]string, args ...string) (string, error) {
+	if len(args) != 0 {
+		return "", fmt.Errorf(
+			"uuid expects 0 arguments, got %d", len(args))
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("uuid: %s", err)
+	}
+
+	// Set the version (4) and the variant (RFC 4122)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf(
+		"%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// ipToInt returns the IP address as an integer for the CIDR math.
+func ipToInt(ip net.IP) *big.Int {
+	return new(big.Int).SetBytes(ip)
+}
+
+// intToIP returns the integer as an IP address of the given length in
+// bytes, which is 4 for IPv4 and 16 for IPv6.
+func intToIP(n *big.Int, size int) net.IP {
+	b := n.Bytes()
+	ip := make(net.IP, size)
+	copy(ip[size-len(b):], b)
+	return ip
+}
@@ -2,10 +2,310 @@ package config
 
 import (
 	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/mail"
 	"os"
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 )
 
+func TestInterpolateFuncBase64Decode(t *testing.T) {
+	cases := []struct {
+		Args   []string
+		Result string
+		Error  bool
+	}{
+		{
+			[]string{"Zm9vYmFy"},
+			"foobar",
+			false,
+		},
+
+		// Invalid base64
+		{
+			[]string{"foo!"},
+			"",
+			true,
+		},
+
+		// Too many args
+		{
+			[]string{"foo", "bar"},
+			"",
+			true,
+		},
+	}
+
+	for i, tc := range cases {
+		actual, err := interpolationFuncBase64Decode(nil, tc.Args...)
+		if (err != nil) != tc.Error {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+
+		if actual != tc.Result {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
+
+func TestInterpolateFuncBase64Encode(t *testing.T) {
+	cases := []struct {
+		Args   []string
+		Result string
+		Error  bool
+	}{
+		{
+			[]string{"foobar"},
+			"Zm9vYmFy",
+			false,
+		},
+
+		{
+			[]string{""},
+			"",
+			false,
+		},
+
+		// Too many args
+		{
+			[]string{"foo", "bar"},
+			"",
+			true,
+		},
+	}
+
+	for i, tc := range cases {
+		actual, err := interpolationFuncBase64Encode(nil, tc.Args...)
+		if (err != nil) != tc.Error {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+
+		if actual != tc.Result {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
+
+func TestInterpolateFuncCidrHost(t *testing.T) {
+	cases := []struct {
+		Args   []string
+		Result string
+		Error  bool
+	}{
+		{
+			[]string{"10.0.0.0/24", "5"},
+			"10.0.0.5",
+			false,
+		},
+
+		// The prefix doesn't have to be a network address
+		{
+			[]string{"10.1.2.3/16", "258"},
+			"10.1.1.2",
+			false,
+		},
+
+		{
+			[]string{"fd00:fd12:3456:7890::/56", "16"},
+			"fd00:fd12:3456:7800::10",
+			false,
+		},
+
+		// Host number out of range
+		{
+			[]string{"10.0.0.0/24", "256"},
+			"",
+			true,
+		},
+
+		// Invalid prefix
+		{
+			[]string{"10.0.0.0", "1"},
+			"",
+			true,
+		},
+
+		// Invalid host number
+		{
+			[]string{"10.0.0.0/24", "foo"},
+			"",
+			true,
+		},
+	}
+
+	for i, tc := range cases {
+		actual, err := interpolationFuncCidrHost(nil, tc.Args...)
+		if (err != nil) != tc.Error {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+
+		if actual != tc.Result {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
+
+func TestInterpolateFuncCidrSubnet(t *testing.T) {
+	cases := []struct {
+		Args   []string
+		Result string
+		Error  bool
+	}{
+		{
+			[]string{"10.0.0.0/16", "8", "2"},
+			"10.0.2.0/24",
+			false,
+		},
+
+		{
+			[]string{"10.0.0.0/8", "12", "17"},
+			"10.1.16.0/20",
+			false,
+		},
+
+		{
+			[]string{"10.0.0.0/16", "0", "0"},
+			"10.0.0.0/16",
+			false,
+		},
+
+		{
+			[]string{"fd00:fd12:3456:7890::/56", "16", "162"},
+			"fd00:fd12:3456:7800:a200::/72",
+			false,
+		},
+
+		// Subnet number doesn't fit in the new bits
+		{
+			[]string{"10.0.0.0/16", "2", "4"},
+			"",
+			true,
+		},
+
+		// Prefix too long
+		{
+			[]string{"10.0.0.0/30", "3", "0"},
+			"",
+			true,
+		},
+
+		// Invalid prefix
+		{
+			[]string{"foo", "8", "2"},
+			"",
+			true,
+		},
+
+		// Invalid new bits
+		{
+			[]string{"10.0.0.0/16", "-1", "2"},
+			"",
+			true,
+		},
+
+		// Too few args
+		{
+			[]string{"10.0.0.0/16", "8"},
+			"",
+			true,
+		},
+	}
+
+	for i, tc := range cases {
+		actual, err := interpolationFuncCidrSubnet(nil, tc.Args...)
+		if (err != nil) != tc.Error {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+
+		if actual != tc.Result {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
+
+func TestInterpolateFuncCloudInit(t *testing.T) {
+	args := []string{
+		"#!/bin/sh\necho hello\n",
+		"#cloud-config\npackages:\n  - nginx\n",
+		"just text",
+	}
+
+	actual, err := interpolationFuncCloudInit(nil, args...)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(actual))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if mediaType != "multipart/mixed" {
+		t.Fatalf("bad: %s", mediaType)
+	}
+
+	var types, parts []string
+	r := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		p, err := r.NextPart()
+		if err != nil {
+			break
+		}
+
+		data, err := ioutil.ReadAll(p)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		types = append(types, p.Header.Get("Content-Type"))
+		parts = append(parts, string(data))
+	}
+
+	expected := []string{
+		`text/x-shellscript; charset="utf-8"`,
+		`text/cloud-config; charset="utf-8"`,
+		`text/plain; charset="utf-8"`,
+	}
+	if !reflect.DeepEqual(types, expected) {
+		t.Fatalf("bad: %#v", types)
+	}
+	if !reflect.DeepEqual(parts, args) {
+		t.Fatalf("bad: %#v", parts)
+	}
+
+	// The same parts always give the same payload
+	again, err := interpolationFuncCloudInit(nil, args...)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if again != actual {
+		t.Fatalf("bad: %s", again)
+	}
+}
+
+func TestInterpolateFuncCloudInit_error(t *testing.T) {
+	cases := [][]string{
+		// No parts
+		nil,
+
+		// A part containing the boundary
+		[]string{"foo " + cloudInitBoundary},
+	}
+
+	for i, args := range cases {
+		if _, err := interpolationFuncCloudInit(nil, args...); err == nil {
+			t.Fatalf("%d: should error", i)
+		}
+	}
+}
+
 func TestInterpolateFuncConcat(t *testing.T) {
 	cases := []struct {
 		Args   []string
@@ -91,6 +391,97 @@ func TestInterpolateFuncFile(t *testing.T) {
 	}
 }
 
+func TestInterpolateFuncFormatList(t *testing.T) {
+	cases := []struct {
+		Args   []string
+		Result string
+		Error  bool
+	}{
+		{
+			[]string{"%s:80", "10.0.0.1,10.0.0.2"},
+			"10.0.0.1:80,10.0.0.2:80",
+			false,
+		},
+
+		// Lists are zipped, and other arguments are repeated
+		{
+			[]string{"%s=%s:%s", "a,b", "1.1.1.1,2.2.2.2", "80"},
+			"a=1.1.1.1:80,b=2.2.2.2:80",
+			false,
+		},
+
+		// No lists
+		{
+			[]string{"%s-%s", "foo", "bar"},
+			"foo-bar",
+			false,
+		},
+
+		// Lists of different lengths
+		{
+			[]string{"%s:%s", "a,b", "1,2,3"},
+			"",
+			true,
+		},
+
+		// No format
+		{
+			nil,
+			"",
+			true,
+		},
+	}
+
+	for i, tc := range cases {
+		actual, err := interpolationFuncFormatList(nil, tc.Args...)
+		if (err != nil) != tc.Error {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+
+		if actual != tc.Result {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
+
+func TestInterpolateFuncJoin(t *testing.T) {
+	cases := []struct {
+		Args   []string
+		Result string
+		Error  bool
+	}{
+		{
+			[]string{" ", "foo,bar,baz"},
+			"foo bar baz",
+			false,
+		},
+
+		{
+			[]string{"\n", "foo"},
+			"foo",
+			false,
+		},
+
+		// Too few args
+		{
+			[]string{"foo,bar"},
+			"",
+			true,
+		},
+	}
+
+	for i, tc := range cases {
+		actual, err := interpolationFuncJoin(nil, tc.Args...)
+		if (err != nil) != tc.Error {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+
+		if actual != tc.Result {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
+
 func TestInterpolateFuncLookup(t *testing.T) {
 	cases := []struct {
 		M      map[string]string
@@ -139,3 +530,208 @@ func TestInterpolateFuncLookup(t *testing.T) {
 		}
 	}
 }
+
+func TestInterpolateFuncLower(t *testing.T) {
+	cases := []struct {
+		Args   []string
+		Result string
+		Error  bool
+	}{
+		{
+			[]string{"FooBAR"},
+			"foobar",
+			false,
+		},
+
+		// Too many args
+		{
+			[]string{"foo", "bar"},
+			"",
+			true,
+		},
+	}
+
+	for i, tc := range cases {
+		actual, err := interpolationFuncLower(nil, tc.Args...)
+		if (err != nil) != tc.Error {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+
+		if actual != tc.Result {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
+
+func TestInterpolateFuncReplace(t *testing.T) {
+	cases := []struct {
+		Args   []string
+		Result string
+		Error  bool
+	}{
+		{
+			[]string{"foo-bar-baz", "-", "_"},
+			"foo_bar_baz",
+			false,
+		},
+
+		{
+			[]string{"foobar", "baz", "qux"},
+			"foobar",
+			false,
+		},
+
+		// Too few args
+		{
+			[]string{"foo", "bar"},
+			"",
+			true,
+		},
+	}
+
+	for i, tc := range cases {
+		actual, err := interpolationFuncReplace(nil, tc.Args...)
+		if (err != nil) != tc.Error {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+
+		if actual != tc.Result {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
+
+func TestInterpolateFuncSubstr(t *testing.T) {
+	cases := []struct {
+		Args   []string
+		Result string
+		Error  bool
+	}{
+		{
+			[]string{"foobar", "0", "3"},
+			"foo",
+			false,
+		},
+
+		// Negative length is up to the end
+		{
+			[]string{"foobar", "3", "-1"},
+			"bar",
+			false,
+		},
+
+		// Length past the end
+		{
+			[]string{"foobar", "4", "10"},
+			"ar",
+			false,
+		},
+
+		// Offset out of range
+		{
+			[]string{"foobar", "7", "1"},
+			"",
+			true,
+		},
+
+		// Invalid length
+		{
+			[]string{"foobar", "0", "foo"},
+			"",
+			true,
+		},
+
+		// Too few args
+		{
+			[]string{"foobar", "0"},
+			"",
+			true,
+		},
+	}
+
+	for i, tc := range cases {
+		actual, err := interpolationFuncSubstr(nil, tc.Args...)
+		if (err != nil) != tc.Error {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+
+		if actual != tc.Result {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
+
+func TestInterpolateFuncTimestamp(t *testing.T) {
+	actual, err := interpolationFuncTimestamp(nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	ts, err := time.Parse(time.RFC3339, actual)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d := time.Now().Sub(ts); d < 0 || d > time.Minute {
+		t.Fatalf("bad: %s", actual)
+	}
+
+	if _, err := interpolationFuncTimestamp(nil, "foo"); err == nil {
+		t.Fatal("should error")
+	}
+}
+
+func TestInterpolateFuncUpper(t *testing.T) {
+	cases := []struct {
+		Args   []string
+		Result string
+		Error  bool
+	}{
+		{
+			[]string{"FooBar"},
+			"FOOBAR",
+			false,
+		},
+
+		// Too many args
+		{
+			[]string{"foo", "bar"},
+			"",
+			true,
+		},
+	}
+
+	for i, tc := range cases {
+		actual, err := interpolationFuncUpper(nil, tc.Args...)
+		if (err != nil) != tc.Error {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+
+		if actual != tc.Result {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
+
+func TestInterpolateFuncUUID(t *testing.T) {
+	re := regexp.MustCompile(
+		`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	seen := make(map[string]struct{})
+	for i := 0; i < 10; i++ {
+		actual, err := interpolationFuncUUID(nil)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if !re.MatchString(actual) {
+			t.Fatalf("bad: %s", actual)
+		}
+		if _, ok := seen[actual]; ok {
+			t.Fatalf("duplicate: %s", actual)
+		}
+		seen[actual] = struct{}{}
+	}
+
+	if _, err := interpolationFuncUUID(nil, "foo"); err == nil {
+		t.Fatal("should error")
+	}
+}
@@ -20,6 +20,33 @@ func TestNewInterpolatedVariable(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"secret.vault.secret/db.password",
+			&SecretVariable{
+				Backend: "vault",
+				Key:     "secret/db.password",
+				key:     "secret.vault.secret/db.password",
+			},
+			false,
+		},
+		{
+			"secret.env",
+			nil,
+			true,
+		},
+		{
+			"git.commit",
+			&GitVariable{
+				Field: "commit",
+				key:   "git.commit",
+			},
+			false,
+		},
+		{
+			"git.author",
+			nil,
+			true,
+		},
 	}
 
 	for i, tc := range cases {
@@ -27,6 +54,9 @@ func TestNewInterpolatedVariable(t *testing.T) {
 		if (err != nil) != tc.Error {
 			t.Fatalf("%d. Error: %s", i, err)
 		}
+		if tc.Error {
+			continue
+		}
 		if !reflect.DeepEqual(actual, tc.Result) {
 			t.Fatalf("%d bad: %#v", i, actual)
 		}
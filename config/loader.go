@@ -76,18 +76,12 @@ func LoadDir(root string) (*Config, error) {
 
 			// Only care about files that are valid to load
 			name := fi.Name()
-			extValue := ext(name)
-			if extValue == "" {
+			if ext(name) == "" {
 				continue
 			}
 
-			// Determine if we're dealing with an override
-			nameNoExt := name[:len(name)-len(extValue)]
-			override := nameNoExt == "override" ||
-				strings.HasSuffix(nameNoExt, "_override")
-
 			path := filepath.Join(root, name)
-			if override {
+			if isOverride(name) {
 				overrides = append(overrides, path)
 			} else {
 				files = append(files, path)
@@ -104,6 +98,63 @@ func LoadDir(root string) (*Config, error) {
 			root)
 	}
 
+	return loadAll(files, overrides, Load)
+}
+
+// LoadString loads the Terraform configuration in src as if it were the
+// contents of a file with the given name, whose extension determines the
+// format. Nothing is read from disk, which makes it useful for tests and
+// for tools that generate configuration.
+func LoadString(name, src string) (*Config, error) {
+	if ext(name) == "" {
+		return nil, fmt.Errorf(
+			"%s: unknown configuration format. Use '.tf' or '.tf.json' extension",
+			name)
+	}
+
+	c, _, err := loadHcl(name, []byte(src))
+	if err != nil {
+		return nil, err
+	}
+
+	importTree := &importTree{Path: name, Raw: c}
+	configTree, err := importTree.ConfigTree()
+	importTree.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return configTree.Flatten()
+}
+
+// LoadFiles loads the Terraform configuration from files, a map of file
+// names to their contents, in the same way that LoadDir loads the files
+// of a directory: override files are merged into the others, and files
+// are loaded in lexical order.
+func LoadFiles(files map[string]string) (*Config, error) {
+	var names, overrides []string
+	for name, _ := range files {
+		if isOverride(name) {
+			overrides = append(overrides, name)
+		} else {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("No Terraform configuration files given")
+	}
+
+	return loadAll(names, overrides, func(name string) (*Config, error) {
+		return LoadString(name, files[name])
+	})
+}
+
+// loadAll loads files and overrides with load, appending the files to
+// each other and then merging the overrides into the result.
+func loadAll(
+	files, overrides []string,
+	load func(string) (*Config, error)) (*Config, error) {
 	var result *Config
 
 	// Sort the files and overrides so we have a deterministic order
@@ -112,7 +163,7 @@ func LoadDir(root string) (*Config, error) {
 
 	// Load all the regular files, append them to each other.
 	for _, f := range files {
-		c, err := Load(f)
+		c, err := load(f)
 		if err != nil {
 			return nil, err
 		}
@@ -129,7 +180,7 @@ func LoadDir(root string) (*Config, error) {
 
 	// Load all the overrides, and merge them into the config
 	for _, f := range overrides {
-		c, err := Load(f)
+		c, err := load(f)
 		if err != nil {
 			return nil, err
 		}
@@ -143,6 +194,15 @@ func LoadDir(root string) (*Config, error) {
 	return result, nil
 }
 
+// isOverride returns whether the file with the given name is an override
+// file, such as "override.tf" or "foo_override.tf".
+func isOverride(name string) bool {
+	extValue := ext(name)
+	nameNoExt := filepath.Base(name[:len(name)-len(extValue)])
+	return nameNoExt == "override" ||
+		strings.HasSuffix(nameNoExt, "_override")
+}
+
 // Ext returns the Terraform configuration extension of the given
 // path, or a blank string if it is an invalid function.
 func ext(path string) string {
@@ -1,31 +1,37 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"regexp"
+	"time"
 
 	"github.com/hashicorp/hcl"
 	hclobj "github.com/hashicorp/hcl/hcl"
+	"github.com/hashicorp/terraform/helper/didyoumean"
+	"github.com/mitchellh/mapstructure"
 )
 
 // hclConfigurable is an implementation of configurable that knows
 // how to turn HCL configuration into a *Config object.
 type hclConfigurable struct {
 	File   string
+	Source []byte
 	Object *hclobj.Object
 }
 
 func (t *hclConfigurable) Config() (*Config, error) {
-	validKeys := map[string]struct{}{
-		"output":   struct{}{},
-		"provider": struct{}{},
-		"resource": struct{}{},
-		"variable": struct{}{},
+	validKeys := make(map[string]struct{})
+	for _, k := range rootKeys {
+		validKeys[k] = struct{}{}
 	}
 
 	type hclVariable struct {
 		Default     interface{}
 		Description string
+		Sensitive   bool
 		Fields      []string `hcl:",decodedFields"`
 	}
 
@@ -37,6 +43,13 @@ func (t *hclConfigurable) Config() (*Config, error) {
 		return nil, err
 	}
 
+	// The validation blocks of the variables are read from the objects,
+	// since decoding them all at once doesn't keep the blocks apart.
+	validations, err := loadValidationsHcl(t.Object.Get("variable", false))
+	if err != nil {
+		return nil, err
+	}
+
 	// Start building up the actual configuration. We start with
 	// variables.
 	// TODO(mitchellh): Make function like loadVariablesHcl so that
@@ -63,12 +76,23 @@ func (t *hclConfigurable) Config() (*Config, error) {
 				Name:        k,
 				Default:     v.Default,
 				Description: v.Description,
+				Sensitive:   v.Sensitive,
+				Validations: validations[k],
 			}
 
 			config.Variables = append(config.Variables, newVar)
 		}
 	}
 
+	// Build the configuration of Terraform itself
+	if tf := t.Object.Get("terraform", false); tf != nil {
+		var err error
+		config.Terraform, err = loadTerraformHcl(tf)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Build the provider configs
 	if providers := t.Object.Get("provider", false); providers != nil {
 		var err error
@@ -85,6 +109,13 @@ func (t *hclConfigurable) Config() (*Config, error) {
 		if err != nil {
 			return nil, err
 		}
+
+		for _, r := range config.Resources {
+			r.Pos = Pos{
+				Filename: t.File,
+				Line:     resourceLine(t.Source, r.Type, r.Name),
+			}
+		}
 	}
 
 	// Build the outputs
@@ -112,8 +143,6 @@ func (t *hclConfigurable) Config() (*Config, error) {
 // loadFileHcl is a fileLoaderFunc that knows how to read HCL
 // files and turn them into hclConfigurables.
 func loadFileHcl(root string) (configurable, []string, error) {
-	var obj *hclobj.Object = nil
-
 	// Read the HCL file and prepare for parsing
 	d, err := ioutil.ReadFile(root)
 	if err != nil {
@@ -121,16 +150,21 @@ func loadFileHcl(root string) (configurable, []string, error) {
 			"Error reading %s: %s", root, err)
 	}
 
+	return loadHcl(root, d)
+}
+
+// loadHcl parses the contents d of the HCL file at root.
+func loadHcl(root string, d []byte) (configurable, []string, error) {
 	// Parse it
-	obj, err = hcl.Parse(string(d))
+	obj, err := hcl.Parse(string(d))
 	if err != nil {
-		return nil, nil, fmt.Errorf(
-			"Error parsing %s: %s", root, err)
+		return nil, nil, parseDiagnostic(root, err)
 	}
 
 	// Start building the result
 	result := &hclConfigurable{
 		File:   root,
+		Source: d,
 		Object: obj,
 	}
 
@@ -177,6 +211,29 @@ func loadFileHcl(root string) (configurable, []string, error) {
 	return result, nil, nil
 }
 
+// loadTerraformHcl turns the "terraform" blocks of the given HCL object
+// into the configuration of Terraform itself.
+func loadTerraformHcl(os *hclobj.Object) (*Terraform, error) {
+	result := new(Terraform)
+	for _, o := range os.Elem(false) {
+		for _, elem := range o.Elem(true) {
+			if elem.Key != "required_version" {
+				return nil, fmt.Errorf(
+					"Unknown key in terraform block: %s", elem.Key)
+			}
+		}
+
+		if ro := o.Get("required_version", false); ro != nil {
+			if err := hcl.DecodeObject(&result.RequiredVersion, ro); err != nil {
+				return nil, fmt.Errorf(
+					"Error parsing required_version: %s", err)
+			}
+		}
+	}
+
+	return result, nil
+}
+
 // LoadOutputsHcl recurses into the given HCL object and turns
 // it into a mapping of outputs.
 func loadOutputsHcl(os *hclobj.Object) ([]*Output, error) {
@@ -203,6 +260,19 @@ func loadOutputsHcl(os *hclobj.Object) ([]*Output, error) {
 			return nil, err
 		}
 
+		// Whether the output is sensitive isn't part of its value
+		delete(config, "sensitive")
+
+		var sensitive bool
+		if so := o.Get("sensitive", false); so != nil {
+			if err := hcl.DecodeObject(&sensitive, so); err != nil {
+				return nil, fmt.Errorf(
+					"Error parsing sensitive for output %s: %s",
+					n,
+					err)
+			}
+		}
+
 		rawConfig, err := NewRawConfig(config)
 		if err != nil {
 			return nil, fmt.Errorf(
@@ -214,6 +284,7 @@ func loadOutputsHcl(os *hclobj.Object) ([]*Output, error) {
 		result = append(result, &Output{
 			Name:      n,
 			RawConfig: rawConfig,
+			Sensitive: sensitive,
 		})
 	}
 
@@ -246,6 +317,9 @@ func loadProvidersHcl(os *hclobj.Object) ([]*ProviderConfig, error) {
 			return nil, err
 		}
 
+		// The default tags aren't part of the provider's configuration
+		delete(config, "default_tags")
+
 		rawConfig, err := NewRawConfig(config)
 		if err != nil {
 			return nil, fmt.Errorf(
@@ -254,9 +328,29 @@ func loadProvidersHcl(os *hclobj.Object) ([]*ProviderConfig, error) {
 				err)
 		}
 
+		var defaultTags *RawConfig
+		if to := o.Get("default_tags", false); to != nil {
+			var tags map[string]interface{}
+			if err := hcl.DecodeObject(&tags, to); err != nil {
+				return nil, fmt.Errorf(
+					"Error reading default_tags for provider config %s: %s",
+					n,
+					err)
+			}
+
+			defaultTags, err = NewRawConfig(tags)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"Error reading default_tags for provider config %s: %s",
+					n,
+					err)
+			}
+		}
+
 		result = append(result, &ProviderConfig{
-			Name:      n,
-			RawConfig: rawConfig,
+			Name:        n,
+			RawConfig:   rawConfig,
+			DefaultTags: defaultTags,
 		})
 	}
 
@@ -314,7 +408,10 @@ func loadResourcesHcl(os *hclobj.Object) ([]*Resource, error) {
 			delete(config, "connection")
 			delete(config, "count")
 			delete(config, "depends_on")
+			delete(config, "enabled")
+			delete(config, "lifecycle")
 			delete(config, "provisioner")
+			delete(config, "timeouts")
 
 			rawConfig, err := NewRawConfig(config)
 			if err != nil {
@@ -338,6 +435,31 @@ func loadResourcesHcl(os *hclobj.Object) ([]*Resource, error) {
 				}
 			}
 
+			// If we have an enabled meta-argument, then it's kept to be
+			// interpolated with the variables when the graph is built
+			var enabled *RawConfig
+			if o := obj.Get("enabled", false); o != nil {
+				var v interface{}
+				if err := hcl.DecodeObject(&v, o); err != nil {
+					return nil, fmt.Errorf(
+						"Error parsing enabled for %s[%s]: %s",
+						t.Key,
+						k,
+						err)
+				}
+
+				enabled, err = NewRawConfig(map[string]interface{}{
+					"enabled": v,
+				})
+				if err != nil {
+					return nil, fmt.Errorf(
+						"Error parsing enabled for %s[%s]: %s",
+						t.Key,
+						k,
+						err)
+				}
+			}
+
 			// If we have depends fields, then add those in
 			var dependsOn []string
 			if o := obj.Get("depends_on", false); o != nil {
@@ -364,6 +486,34 @@ func loadResourcesHcl(os *hclobj.Object) ([]*Resource, error) {
 				}
 			}
 
+			// If we have timeouts, then parse those out
+			var timeouts map[string]time.Duration
+			if o := obj.Get("timeouts", false); o != nil {
+				var err error
+				timeouts, err = loadTimeoutsHcl(o)
+				if err != nil {
+					return nil, fmt.Errorf(
+						"Error reading timeouts for %s[%s]: %s",
+						t.Key,
+						k,
+						err)
+				}
+			}
+
+			// If we have a lifecycle block, then parse that out
+			var lifecycle ResourceLifecycle
+			if o := obj.Get("lifecycle", false); o != nil {
+				var err error
+				lifecycle, err = loadLifecycleHcl(o)
+				if err != nil {
+					return nil, fmt.Errorf(
+						"Error reading lifecycle for %s[%s]: %s",
+						t.Key,
+						k,
+						err)
+				}
+			}
+
 			// If we have provisioners, then parse those out
 			var provisioners []*Provisioner
 			if os := obj.Get("provisioner", false); os != nil {
@@ -385,6 +535,9 @@ func loadResourcesHcl(os *hclobj.Object) ([]*Resource, error) {
 				RawConfig:    rawConfig,
 				Provisioners: provisioners,
 				DependsOn:    dependsOn,
+				Enabled:      enabled,
+				Timeouts:     timeouts,
+				Lifecycle:    lifecycle,
 			})
 		}
 	}
@@ -392,6 +545,189 @@ func loadResourcesHcl(os *hclobj.Object) ([]*Resource, error) {
 	return result, nil
 }
 
+// loadValidationsHcl reads the validation blocks of the variables, such
+// as `validation { values = ["dev", "prod"] }`, by variable name.
+func loadValidationsHcl(
+	os *hclobj.Object) (map[string][]*VariableValidation, error) {
+	result := make(map[string][]*VariableValidation)
+	if os == nil {
+		return result, nil
+	}
+
+	for _, o1 := range os.Elem(false) {
+		for _, v := range o1.Elem(true) {
+			vos := v.Get("validation", false)
+			if vos == nil {
+				continue
+			}
+
+			for _, vo := range vos.Elem(false) {
+				var raw map[string]interface{}
+				if err := hcl.DecodeObject(&raw, vo); err != nil {
+					return nil, err
+				}
+
+				vv, err := loadValidationHcl(raw)
+				if err != nil {
+					return nil, fmt.Errorf(
+						"Error reading validation for variable %s: %s",
+						v.Key, err)
+				}
+
+				result[v.Key] = append(result[v.Key], vv)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// loadValidationHcl reads a single validation block of a variable.
+func loadValidationHcl(raw map[string]interface{}) (*VariableValidation, error) {
+	var vv VariableValidation
+	for k, v := range raw {
+		var err error
+		switch k {
+		case "error_message":
+			err = mapstructure.WeakDecode(v, &vv.ErrorMessage)
+		case "max":
+			vv.Max, err = loadValidationNumber(v)
+		case "min":
+			vv.Min, err = loadValidationNumber(v)
+		case "regex":
+			if err = mapstructure.WeakDecode(v, &vv.Regex); err == nil {
+				_, err = regexp.Compile(vv.Regex)
+			}
+		case "values":
+			err = mapstructure.WeakDecode(v, &vv.Values)
+		default:
+			msg := fmt.Sprintf("unknown key %q", k)
+			if s := didyoumean.NameSuggestion(k, validationKeys); s != "" {
+				msg += fmt.Sprintf(" (did you mean %q?)", s)
+			}
+
+			return nil, errors.New(msg)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", k, err)
+		}
+	}
+
+	if vv.Min != nil && vv.Max != nil && *vv.Min > *vv.Max {
+		return nil, fmt.Errorf("min can't be greater than max")
+	}
+
+	return &vv, nil
+}
+
+// loadValidationNumber reads the min or max of a validation block, which
+// can be a number or a string.
+func loadValidationNumber(v interface{}) (*float64, error) {
+	var result float64
+	if err := mapstructure.WeakDecode(v, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// loadLifecycleHcl reads the lifecycle block of a resource, such as
+// `lifecycle { ignore_changes = ["name"] }`.
+func loadLifecycleHcl(o *hclobj.Object) (ResourceLifecycle, error) {
+	var result ResourceLifecycle
+
+	var raw map[string]interface{}
+	if err := hcl.DecodeObject(&raw, o); err != nil {
+		return result, err
+	}
+	for k, _ := range raw {
+		valid := false
+		for _, vk := range lifecycleKeys {
+			if k == vk {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			msg := fmt.Sprintf("unknown key %q", k)
+			if s := didyoumean.NameSuggestion(k, lifecycleKeys); s != "" {
+				msg += fmt.Sprintf(" (did you mean %q?)", s)
+			}
+
+			return result, errors.New(msg)
+		}
+	}
+
+	if err := hcl.DecodeObject(&result, o); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// loadTimeoutsHcl reads the timeouts block of a resource, such as
+// `timeouts { create = "30m" }`, into the timeout of each action.
+func loadTimeoutsHcl(o *hclobj.Object) (map[string]time.Duration, error) {
+	var raw map[string]string
+	if err := hcl.DecodeObject(&raw, o); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]time.Duration, len(raw))
+	for k, v := range raw {
+		valid := false
+		for _, vk := range timeoutKeys {
+			if k == vk {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			msg := fmt.Sprintf("unknown timeout %q", k)
+			if s := didyoumean.NameSuggestion(k, timeoutKeys); s != "" {
+				msg += fmt.Sprintf(" (did you mean %q?)", s)
+			}
+
+			return nil, errors.New(msg)
+		}
+
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s timeout: %s", k, err)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("%s timeout must be positive", k)
+		}
+
+		result[k] = d
+	}
+
+	return result, nil
+}
+
+// resourceLine returns the line number where the resource with the given
+// type and name is declared within src, or zero if it can't be found.
+//
+// The HCL parser doesn't track positions, so this is a best-effort scan
+// of the source for the resource header. It will not find resources
+// declared in JSON or in unusual formatting.
+func resourceLine(src []byte, t, n string) int {
+	re, err := regexp.Compile(fmt.Sprintf(
+		`(?m)^[ \t]*resource\s+"?%s"?\s+"?%s"?\s*{`,
+		regexp.QuoteMeta(t),
+		regexp.QuoteMeta(n)))
+	if err != nil {
+		return 0
+	}
+
+	loc := re.FindIndex(src)
+	if loc == nil {
+		return 0
+	}
+
+	return bytes.Count(src[:loc[0]], []byte("\n")) + 1
+}
+
 func loadProvisionersHcl(os *hclobj.Object, connInfo map[string]interface{}) ([]*Provisioner, error) {
 	pos := make([]*hclobj.Object, 0, int(os.Len()))
 
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"path/filepath"
+	"reflect"
 	"sort"
 	"strings"
 	"testing"
@@ -46,6 +47,56 @@ func TestLoadBasic(t *testing.T) {
 	}
 }
 
+func TestLoad_sensitive(t *testing.T) {
+	c, err := Load(filepath.Join(fixtureDir, "sensitive.tf"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := c.Validate(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	vs := make(map[string]bool)
+	for _, v := range c.Variables {
+		vs[v.Name] = v.Sensitive
+	}
+	if !vs["password"] || vs["user"] {
+		t.Fatalf("bad: %#v", vs)
+	}
+
+	os := make(map[string]bool)
+	for _, o := range c.Outputs {
+		os[o.Name] = o.Sensitive
+
+		if _, ok := o.RawConfig.Raw["sensitive"]; ok {
+			t.Fatalf("sensitive should not be in config: %#v", o.RawConfig.Raw)
+		}
+	}
+	if !os["password"] || os["user"] {
+		t.Fatalf("bad: %#v", os)
+	}
+}
+
+func TestLoadBasic_pos(t *testing.T) {
+	path := filepath.Join(fixtureDir, "basic.tf")
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]Pos{
+		"aws_security_group.firewall": Pos{Filename: path, Line: 15},
+		"aws_instance.web":            Pos{Filename: path, Line: 19},
+		"aws_instance.db":             Pos{Filename: path, Line: 32},
+	}
+	for _, r := range c.Resources {
+		if r.Pos != expected[r.Id()] {
+			t.Fatalf("bad: %s %#v", r.Id(), r.Pos)
+		}
+	}
+}
+
 func TestLoadBasic_import(t *testing.T) {
 	// Skip because we disabled importing
 	t.Skip()
@@ -212,6 +263,278 @@ func TestLoadDir_override(t *testing.T) {
 	}
 }
 
+func TestLoadString(t *testing.T) {
+	c, err := LoadString("main.tf", `
+variable "foo" {
+    default = "bar"
+}
+
+resource "aws_instance" "web" {
+    ami = "${var.foo}"
+    count = 2
+}
+`)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := resourcesStr(c.Resources)
+	expected := strings.TrimSpace(`
+aws_instance[web] (x2)
+  ami
+  vars
+    user: var.foo
+`)
+	if actual != expected {
+		t.Fatalf("bad:\n%s", actual)
+	}
+}
+
+func TestLoadString_badType(t *testing.T) {
+	_, err := LoadString("main.nope", `resource "aws_instance" "web" {}`)
+	if err == nil {
+		t.Fatal("should have error")
+	}
+}
+
+func TestLoadString_json(t *testing.T) {
+	c, err := LoadString("main.tf.json", `
+{
+    "resource": {
+        "aws_instance": {
+            "web": { "ami": "foo" }
+        }
+    }
+}
+`)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := resourcesStr(c.Resources)
+	expected := strings.TrimSpace(`
+aws_instance[web] (x1)
+  ami
+`)
+	if actual != expected {
+		t.Fatalf("bad:\n%s", actual)
+	}
+}
+
+func TestLoadString_terraform(t *testing.T) {
+	cases := map[string]string{
+		"main.tf": `
+terraform {
+    required_version = ">= 0.2.0"
+}
+`,
+		"main.tf.json": `{"terraform": {"required_version": ">= 0.2.0"}}`,
+	}
+
+	for name, src := range cases {
+		c, err := LoadString(name, src)
+		if err != nil {
+			t.Fatalf("%s: err: %s", name, err)
+		}
+		if c.Terraform == nil || c.Terraform.RequiredVersion != ">= 0.2.0" {
+			t.Fatalf("%s: bad: %#v", name, c.Terraform)
+		}
+		if err := c.Validate(); err != nil {
+			t.Fatalf("%s: err: %s", name, err)
+		}
+	}
+}
+
+func TestLoadString_terraformUnknownKey(t *testing.T) {
+	_, err := LoadString("main.tf", `
+terraform {
+    required_versions = ">= 0.2.0"
+}
+`)
+	if err == nil {
+		t.Fatal("should have error")
+	}
+}
+
+func TestLoadFiles_terraformOverride(t *testing.T) {
+	c, err := LoadFiles(map[string]string{
+		"main.tf":     `terraform { required_version = ">= 0.2.0" }`,
+		"other.tf":    `variable "foo" {}`,
+		"override.tf": `terraform { required_version = ">= 0.3.0" }`,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if c.Terraform == nil || c.Terraform.RequiredVersion != ">= 0.3.0" {
+		t.Fatalf("bad: %#v", c.Terraform)
+	}
+}
+
+func TestLoadFiles(t *testing.T) {
+	cases := []struct {
+		Name  string
+		Files map[string]string
+		Err   bool
+		Raw   map[string]map[string]interface{}
+		Count map[string]int
+	}{
+		{
+			"single file",
+			map[string]string{
+				"main.tf": `resource "aws_instance" "web" { ami = "foo" }`,
+			},
+			false,
+			map[string]map[string]interface{}{
+				"aws_instance.web": {"ami": "foo"},
+			},
+			nil,
+		},
+
+		{
+			"files are appended",
+			map[string]string{
+				"one.tf": `resource "aws_instance" "web" { ami = "foo" }`,
+				"two.tf": `resource "aws_instance" "db" { ami = "bar" }`,
+			},
+			false,
+			map[string]map[string]interface{}{
+				"aws_instance.web": {"ami": "foo"},
+				"aws_instance.db":  {"ami": "bar"},
+			},
+			nil,
+		},
+
+		{
+			"override replaces and adds attributes",
+			map[string]string{
+				"main.tf": `
+resource "aws_instance" "web" {
+    ami = "foo"
+    instance_type = "m1.small"
+}
+`,
+				"override.tf": `
+resource "aws_instance" "web" {
+    ami = "bar"
+    key_name = "deploy"
+}
+`,
+			},
+			false,
+			map[string]map[string]interface{}{
+				"aws_instance.web": {
+					"ami":           "bar",
+					"instance_type": "m1.small",
+					"key_name":      "deploy",
+				},
+			},
+			nil,
+		},
+
+		{
+			"override sets count",
+			map[string]string{
+				"main.tf":         `resource "aws_instance" "web" { ami = "foo" }`,
+				"web_override.tf": `resource "aws_instance" "web" { count = 3 }`,
+			},
+			false,
+			map[string]map[string]interface{}{
+				"aws_instance.web": {"ami": "foo"},
+			},
+			map[string]int{"aws_instance.web": 3},
+		},
+
+		{
+			"override adds resources",
+			map[string]string{
+				"main.tf":     `resource "aws_instance" "web" { ami = "foo" }`,
+				"override.tf": `resource "aws_instance" "db" { ami = "bar" }`,
+			},
+			false,
+			map[string]map[string]interface{}{
+				"aws_instance.web": {"ami": "foo"},
+				"aws_instance.db":  {"ami": "bar"},
+			},
+			nil,
+		},
+
+		{
+			"overrides are merged in lexical order",
+			map[string]string{
+				"main.tf":       `resource "aws_instance" "web" { ami = "foo" }`,
+				"a_override.tf": `resource "aws_instance" "web" { ami = "bar" }`,
+				"override.tf":   `resource "aws_instance" "web" { ami = "baz" }`,
+			},
+			false,
+			map[string]map[string]interface{}{
+				"aws_instance.web": {"ami": "baz"},
+			},
+			nil,
+		},
+
+		{
+			"override in JSON",
+			map[string]string{
+				"main.tf": `resource "aws_instance" "web" { ami = "foo" }`,
+				"override.tf.json": `
+{ "resource": { "aws_instance": { "web": { "ami": "bar" } } } }
+`,
+			},
+			false,
+			map[string]map[string]interface{}{
+				"aws_instance.web": {"ami": "bar"},
+			},
+			nil,
+		},
+
+		{
+			"only overrides",
+			map[string]string{
+				"override.tf": `resource "aws_instance" "web" { ami = "foo" }`,
+			},
+			true,
+			nil,
+			nil,
+		},
+
+		{
+			"syntax error",
+			map[string]string{
+				"main.tf": `resource "aws_instance" "web" {`,
+			},
+			true,
+			nil,
+			nil,
+		},
+	}
+
+	for _, tc := range cases {
+		c, err := LoadFiles(tc.Files)
+		if (err != nil) != tc.Err {
+			t.Fatalf("%s: err: %s", tc.Name, err)
+		}
+		if err != nil {
+			continue
+		}
+
+		raw := make(map[string]map[string]interface{})
+		for _, r := range c.Resources {
+			raw[r.Id()] = r.RawConfig.Raw
+
+			expected := 1
+			if n, ok := tc.Count[r.Id()]; ok {
+				expected = n
+			}
+			if r.Count != expected {
+				t.Fatalf("%s: bad count for %s: %d", tc.Name, r.Id(), r.Count)
+			}
+		}
+		if !reflect.DeepEqual(raw, tc.Raw) {
+			t.Fatalf("%s: bad: %#v", tc.Name, raw)
+		}
+	}
+}
+
 func outputsStr(os []*Output) string {
 	ns := make([]string, 0, len(os))
 	m := make(map[string]*Output)
@@ -264,6 +587,147 @@ func TestLoad_provisioners(t *testing.T) {
 	}
 }
 
+func TestLoad_timeouts(t *testing.T) {
+	c, err := Load(filepath.Join(fixtureDir, "timeouts.tf"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := resourcesStr(c.Resources)
+	if actual != strings.TrimSpace(timeoutsResourcesStr) {
+		t.Fatalf("bad:\n%s", actual)
+	}
+}
+
+func TestLoad_timeoutsBad(t *testing.T) {
+	_, err := Load(filepath.Join(fixtureDir, "timeouts-bad.tf"))
+	if err == nil {
+		t.Fatal("should have error")
+	}
+	if !strings.Contains(err.Error(), `did you mean "create"?`) {
+		t.Fatalf("bad: %s", err)
+	}
+}
+
+func TestLoad_lifecycle(t *testing.T) {
+	c, err := Load(filepath.Join(fixtureDir, "lifecycle.tf"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := resourcesStr(c.Resources)
+	if actual != strings.TrimSpace(lifecycleResourcesStr) {
+		t.Fatalf("bad:\n%s", actual)
+	}
+}
+
+func TestLoad_lifecycleBad(t *testing.T) {
+	_, err := Load(filepath.Join(fixtureDir, "lifecycle-bad.tf"))
+	if err == nil {
+		t.Fatal("should have error")
+	}
+	if !strings.Contains(err.Error(), `did you mean "ignore_changes"?`) {
+		t.Fatalf("bad: %s", err)
+	}
+}
+
+func TestLoad_validation(t *testing.T) {
+	c, err := Load(filepath.Join(fixtureDir, "validation.tf"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	vars := make(map[string]*Variable)
+	for _, v := range c.Variables {
+		vars[v.Name] = v
+	}
+
+	if n := len(vars["instances"].Validations); n != 2 {
+		t.Fatalf("bad: %d", n)
+	}
+
+	cases := []struct {
+		Name  string
+		Value string
+		Errs  int
+	}{
+		{"environment", "prod", 0},
+		{"environment", "test", 1},
+		{"name", "web", 0},
+		{"name", "Web", 1},
+		{"instances", "10", 0},
+		{"instances", "11", 1},
+		{"instances", "-1", 2},
+	}
+
+	for i, tc := range cases {
+		errs := vars[tc.Name].ValidateValue(tc.Value)
+		if len(errs) != tc.Errs {
+			t.Fatalf("%d: bad: %#v", i, errs)
+		}
+	}
+
+	errs := vars["environment"].ValidateValue("test")
+	expected := "var.environment: environment must be one of dev/stage/prod"
+	if errs[0].Error() != expected {
+		t.Fatalf("bad: %s", errs[0])
+	}
+}
+
+func TestLoad_validationBad(t *testing.T) {
+	_, err := Load(filepath.Join(fixtureDir, "validation-bad.tf"))
+	if err == nil {
+		t.Fatal("should have error")
+	}
+	if !strings.Contains(err.Error(), `did you mean "values"?`) {
+		t.Fatalf("bad: %s", err)
+	}
+}
+
+func TestLoad_defaultTags(t *testing.T) {
+	c, err := Load(filepath.Join(fixtureDir, "default-tags.tf"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := providerConfigsStr(c.ProviderConfigs)
+	if actual != strings.TrimSpace(defaultTagsProvidersStr) {
+		t.Fatalf("bad:\n%s", actual)
+	}
+
+	tags := c.ProviderConfigs[0].DefaultTags
+	if len(tags.Variables) != 1 {
+		t.Fatalf("bad: %#v", tags.Variables)
+	}
+}
+
+func TestLoad_enabled(t *testing.T) {
+	c, err := Load(filepath.Join(fixtureDir, "enabled.tf"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	vs := map[string]string{"var.create_elb": "false"}
+	expected := map[string]bool{
+		"aws_elb.lb":       false,
+		"aws_instance.web": true,
+		"aws_instance.db":  true,
+	}
+	for _, r := range c.Resources {
+		if _, ok := r.RawConfig.Raw["enabled"]; ok {
+			t.Fatalf("%s: bad: %#v", r.Id(), r.RawConfig.Raw)
+		}
+
+		enabled, err := r.IsEnabled(vs)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if enabled != expected[r.Id()] {
+			t.Fatalf("%s: bad: %#v", r.Id(), enabled)
+		}
+	}
+}
+
 func TestLoad_connections(t *testing.T) {
 	c, err := Load(filepath.Join(fixtureDir, "connection.tf"))
 	if err != nil {
@@ -330,6 +794,20 @@ func providerConfigsStr(pcs []*ProviderConfig) string {
 			result += fmt.Sprintf("  %s\n", k)
 		}
 
+		if pc.DefaultTags != nil {
+			result += fmt.Sprintf("  default_tags\n")
+
+			keys := make([]string, 0, len(pc.DefaultTags.Raw))
+			for k, _ := range pc.DefaultTags.Raw {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+
+			for _, k := range keys {
+				result += fmt.Sprintf("    %s\n", k)
+			}
+		}
+
 		if len(pc.RawConfig.Variables) > 0 {
 			result += fmt.Sprintf("  vars\n")
 			for _, rawV := range pc.RawConfig.Variables {
@@ -410,6 +888,27 @@ func resourcesStr(rs []*Resource) string {
 			}
 		}
 
+		if len(r.Timeouts) > 0 {
+			result += fmt.Sprintf("  timeouts\n")
+
+			ks := make([]string, 0, len(r.Timeouts))
+			for k, _ := range r.Timeouts {
+				ks = append(ks, k)
+			}
+			sort.Strings(ks)
+
+			for _, k := range ks {
+				result += fmt.Sprintf("    %s: %s\n", k, r.Timeouts[k])
+			}
+		}
+
+		if len(r.Lifecycle.IgnoreChanges) > 0 {
+			result += fmt.Sprintf("  ignore changes\n")
+			for _, k := range r.Lifecycle.IgnoreChanges {
+				result += fmt.Sprintf("    %s\n", k)
+			}
+		}
+
 		if len(r.RawConfig.Variables) > 0 {
 			result += fmt.Sprintf("  vars\n")
 
@@ -623,6 +1122,33 @@ aws_instance[web] (x1)
     user: var.foo
 `
 
+const defaultTagsProvidersStr = `
+aws
+  region
+  default_tags
+    cost_center
+    environment
+`
+
+const timeoutsResourcesStr = `
+aws_db_instance[db] (x1)
+  engine
+  timeouts
+    create: 30m0s
+    delete: 10m0s
+`
+
+const lifecycleResourcesStr = `
+aws_instance[web] (x1)
+  ami
+  name
+  ignore changes
+    name
+    tags
+  vars
+    user: var.suffix
+`
+
 const connectionResourcesStr = `
 aws_instance[web] (x1)
   ami
@@ -25,6 +25,12 @@ func Merge(c1, c2 *Config) (*Config, error) {
 		}
 	}
 
+	// The configuration of Terraform itself is overridden as a whole
+	c.Terraform = c1.Terraform
+	if c2.Terraform != nil {
+		c.Terraform = c2.Terraform
+	}
+
 	// NOTE: Everything below is pretty gross. Due to the lack of generics
 	// in Go, there is some hoop-jumping involved to make this merging a
 	// little more test-friendly and less repetitive. Ironically, making it
@@ -2,6 +2,7 @@ package config
 
 import (
 	"encoding/gob"
+	"fmt"
 	"reflect"
 	"testing"
 )
@@ -129,3 +130,104 @@ func TestRawConfig_implGob(t *testing.T) {
 	var _ gob.GobDecoder = new(RawConfig)
 	var _ gob.GobEncoder = new(RawConfig)
 }
+
+func TestRawConfigInterpolate_loaded(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Config   string
+		Vars     map[string]string
+		Err      bool
+		Expected map[string]interface{}
+	}{
+		{
+			"user variable",
+			`ami = "${var.ami}"`,
+			map[string]string{"var.ami": "ami-123"},
+			false,
+			map[string]interface{}{"ami": "ami-123"},
+		},
+
+		{
+			"embedded in a string",
+			`name = "${var.role}-${var.env}"`,
+			map[string]string{"var.role": "web", "var.env": "prod"},
+			false,
+			map[string]interface{}{"name": "web-prod"},
+		},
+
+		{
+			"resource attribute",
+			`ip = "${aws_instance.db.private_ip}"`,
+			map[string]string{"aws_instance.db.private_ip": "10.0.0.1"},
+			false,
+			map[string]interface{}{"ip": "10.0.0.1"},
+		},
+
+		{
+			"multi resource attribute",
+			`ids = "${aws_instance.db.*.id}"`,
+			map[string]string{"aws_instance.db.*.id": "i-1,i-2"},
+			false,
+			map[string]interface{}{"ids": "i-1,i-2"},
+		},
+
+		{
+			"function",
+			`name = "${concat("web-", var.env)}"`,
+			map[string]string{"var.env": "prod"},
+			false,
+			map[string]interface{}{"name": "web-prod"},
+		},
+
+		{
+			"lookup",
+			`ami = "${lookup(var.amis, var.region)}"`,
+			map[string]string{
+				"var.region":         "us-east-1",
+				"var.amis":           "amis",
+				"var.amis.us-east-1": "ami-123",
+			},
+			false,
+			map[string]interface{}{"ami": "ami-123"},
+		},
+
+		{
+			"list",
+			`security_groups = ["default", "${var.sg}"]`,
+			map[string]string{"var.sg": "web"},
+			false,
+			map[string]interface{}{
+				"security_groups": []interface{}{"default", "web"},
+			},
+		},
+
+		{
+			"missing variable",
+			`ami = "${var.ami}"`,
+			map[string]string{},
+			true,
+			nil,
+		},
+	}
+
+	for _, tc := range cases {
+		c, err := LoadString("main.tf", fmt.Sprintf(
+			"resource \"aws_instance\" \"web\" {\n%s\n}", tc.Config))
+		if err != nil {
+			t.Fatalf("%s: err: %s", tc.Name, err)
+		}
+
+		rc := c.Resources[0].RawConfig
+		err = rc.Interpolate(tc.Vars)
+		if (err != nil) != tc.Err {
+			t.Fatalf("%s: err: %s", tc.Name, err)
+		}
+		if err != nil {
+			continue
+		}
+
+		if !reflect.DeepEqual(rc.Config(), tc.Expected) {
+			t.Fatalf("%s: bad: %#v", tc.Name, rc.Config())
+		}
+	}
+}
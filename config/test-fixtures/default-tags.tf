@@ -0,0 +1,10 @@
+variable "environment" {}
+
+provider "aws" {
+    region = "us-east-1"
+
+    default_tags {
+        cost_center = "1234"
+        environment = "${var.environment}"
+    }
+}
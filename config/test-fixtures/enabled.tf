@@ -0,0 +1,15 @@
+variable "create_elb" {
+    default = "false"
+}
+
+resource "aws_elb" "lb" {
+    enabled = "${var.create_elb}"
+    name = "foo"
+}
+
+resource "aws_instance" "web" {
+    enabled = true
+}
+
+resource "aws_instance" "db" {
+}
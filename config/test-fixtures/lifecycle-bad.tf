@@ -0,0 +1,7 @@
+resource "aws_instance" "web" {
+    ami = "foo"
+
+    lifecycle {
+        ignore_change = ["name"]
+    }
+}
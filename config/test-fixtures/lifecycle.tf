@@ -0,0 +1,8 @@
+resource "aws_instance" "web" {
+    ami = "foo"
+    name = "web-${var.suffix}"
+
+    lifecycle {
+        ignore_changes = ["name", "tags"]
+    }
+}
@@ -0,0 +1,20 @@
+variable "password" {
+    sensitive = true
+}
+
+variable "user" {
+    default = "admin"
+}
+
+resource "aws_db_instance" "db" {
+    password = "${var.password}"
+}
+
+output "password" {
+    value = "${aws_db_instance.db.password}"
+    sensitive = true
+}
+
+output "user" {
+    value = "${var.user}"
+}
@@ -0,0 +1,7 @@
+resource "aws_db_instance" "db" {
+    engine = "postgres"
+
+    timeouts {
+        craete = "30m"
+    }
+}
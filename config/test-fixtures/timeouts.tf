@@ -0,0 +1,8 @@
+resource "aws_db_instance" "db" {
+    engine = "postgres"
+
+    timeouts {
+        create = "30m"
+        delete = "10m"
+    }
+}
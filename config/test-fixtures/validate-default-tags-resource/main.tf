@@ -0,0 +1,7 @@
+provider "aws" {
+    default_tags {
+        vpc = "${aws_vpc.main.id}"
+    }
+}
+
+resource "aws_vpc" "main" {}
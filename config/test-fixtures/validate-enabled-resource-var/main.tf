@@ -0,0 +1,6 @@
+resource "aws_instance" "web" {
+}
+
+resource "aws_elb" "lb" {
+    enabled = "${aws_instance.web.id}"
+}
@@ -0,0 +1 @@
+resourc "aws_instance" "web" {}
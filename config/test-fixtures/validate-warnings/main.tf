@@ -0,0 +1,22 @@
+variable "used" {
+    default = "foo"
+}
+
+variable "unused" {
+    default = "bar"
+}
+
+provider "aws" {
+    access_key = "${var.used}"
+    secret_key = "${secret.env.AWS_SECRET_KEY}"
+}
+
+resource "aws_instance" "web" {
+    user_data = "${secret.env.USER_DATA}"
+}
+
+resource "do_droplet" "db" {}
+
+output "token" {
+    value = "${secret.env.TOKEN}"
+}
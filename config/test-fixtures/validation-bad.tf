@@ -0,0 +1,5 @@
+variable "environment" {
+    validation {
+        value = ["dev", "prod"]
+    }
+}
@@ -0,0 +1,27 @@
+variable "environment" {
+    default = "dev"
+
+    validation {
+        values = ["dev", "stage", "prod"]
+        error_message = "environment must be one of dev/stage/prod"
+    }
+}
+
+variable "name" {
+    validation {
+        regex = "^[a-z]+$"
+    }
+}
+
+variable "instances" {
+    default = "1"
+
+    validation {
+        min = 1
+        max = "10"
+    }
+
+    validation {
+        regex = "^[0-9]+$"
+    }
+}
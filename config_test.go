@@ -1,6 +1,8 @@
 package main
 
 import (
+	"io/ioutil"
+	"os"
 	"path/filepath"
 	"reflect"
 	"testing"
@@ -20,6 +22,47 @@ func TestLoadConfig(t *testing.T) {
 			"aws": "foo",
 			"do":  "bar",
 		},
+		AuditLog:           "/var/log/terraform-audit.log",
+		UpdateAddress:      "https://releases.example.com/terraform.json",
+		UpdateKey:          "/etc/terraform/release.pub",
+		CostBudget:         500,
+		MaxDestroy:         20,
+		MaxDestroyPercent:  50,
+		StateKeepLast:      10,
+		StateKeepDailyDays: 30,
+		Webhooks: map[string]*WebhookConfig{
+			"slack": &WebhookConfig{
+				URL:    "https://hooks.slack.com/services/T0/B0/X",
+				Format: "slack",
+			},
+		},
+		Hooks: map[string]*HookConfig{
+			"metrics": &HookConfig{
+				Command: "/usr/local/bin/terraform-metrics",
+				Events:  []string{"post_apply", "post_apply_all"},
+			},
+		},
+		EventSinks: map[string]*EventSinkConfig{
+			"metrics": &EventSinkConfig{
+				Type:    "statsd",
+				Address: "127.0.0.1:8125",
+			},
+		},
+		Policies: map[string]*PolicyConfig{
+			"security-groups": &PolicyConfig{
+				Command: "/usr/local/bin/check-security-groups",
+			},
+			"naming": &PolicyConfig{
+				Resource:     "aws_instance",
+				NamePattern:  "^[a-z][a-z0-9_]*$",
+				RequiredTags: []string{"Name", "Owner"},
+			},
+		},
+		Approvers: map[string]*ApproverConfig{
+			"alice": &ApproverConfig{
+				PublicKey: "/etc/terraform/approvers/alice.pub",
+			},
+		},
 	}
 
 	if !reflect.DeepEqual(c, expected) {
@@ -37,6 +80,27 @@ func TestConfig_Merge(t *testing.T) {
 			"local":  "local",
 			"remote": "bad",
 		},
+		Webhooks: map[string]*WebhookConfig{
+			"ci":    &WebhookConfig{URL: "https://ci.example.com/a"},
+			"slack": &WebhookConfig{URL: "https://hooks.slack.com/a"},
+		},
+		Hooks: map[string]*HookConfig{
+			"notify": &HookConfig{Command: "notify"},
+		},
+		EventSinks: map[string]*EventSinkConfig{
+			"log": &EventSinkConfig{Type: "syslog"},
+		},
+		Policies: map[string]*PolicyConfig{
+			"tags": &PolicyConfig{Command: "check-tags"},
+		},
+		Approvers: map[string]*ApproverConfig{
+			"alice": &ApproverConfig{PublicKey: "alice.pub"},
+		},
+		ReadOnly:      true,
+		UpdateAddress: "https://releases.example.com/terraform.json",
+		UpdateKey:     "old.pub",
+		MaxDestroy:    10,
+		StateKeepLast: 5,
 	}
 
 	c2 := &Config{
@@ -47,6 +111,22 @@ func TestConfig_Merge(t *testing.T) {
 		Provisioners: map[string]string{
 			"remote": "remote",
 		},
+		Webhooks: map[string]*WebhookConfig{
+			"ci": &WebhookConfig{URL: "https://ci.example.com/b"},
+		},
+		EventSinks: map[string]*EventSinkConfig{
+			"log": &EventSinkConfig{Type: "file", Path: "events.log"},
+		},
+		Policies: map[string]*PolicyConfig{
+			"tags": &PolicyConfig{Command: "/opt/check-tags"},
+		},
+		AuditLog:           "audit.log",
+		PushAddress:        "https://runs.example.com",
+		UpdateKey:          "release.pub",
+		CostEstimator:      "estimate-cost",
+		CostBudget:         1000,
+		MaxDestroyPercent:  50,
+		StateKeepDailyDays: 7,
 	}
 
 	expected := &Config{
@@ -59,6 +139,33 @@ func TestConfig_Merge(t *testing.T) {
 			"local":  "local",
 			"remote": "remote",
 		},
+		Webhooks: map[string]*WebhookConfig{
+			"ci":    &WebhookConfig{URL: "https://ci.example.com/b"},
+			"slack": &WebhookConfig{URL: "https://hooks.slack.com/a"},
+		},
+		Hooks: map[string]*HookConfig{
+			"notify": &HookConfig{Command: "notify"},
+		},
+		EventSinks: map[string]*EventSinkConfig{
+			"log": &EventSinkConfig{Type: "file", Path: "events.log"},
+		},
+		Policies: map[string]*PolicyConfig{
+			"tags": &PolicyConfig{Command: "/opt/check-tags"},
+		},
+		Approvers: map[string]*ApproverConfig{
+			"alice": &ApproverConfig{PublicKey: "alice.pub"},
+		},
+		AuditLog:           "audit.log",
+		ReadOnly:           true,
+		PushAddress:        "https://runs.example.com",
+		UpdateAddress:      "https://releases.example.com/terraform.json",
+		UpdateKey:          "release.pub",
+		CostEstimator:      "estimate-cost",
+		CostBudget:         1000,
+		MaxDestroy:         10,
+		MaxDestroyPercent:  50,
+		StateKeepLast:      5,
+		StateKeepDailyDays: 7,
 	}
 
 	actual := c1.Merge(c2)
@@ -66,3 +173,98 @@ func TestConfig_Merge(t *testing.T) {
 		t.Fatalf("bad: %#v", actual)
 	}
 }
+
+func TestEventSinkConfigSink(t *testing.T) {
+	cases := []struct {
+		Config *EventSinkConfig
+		Err    bool
+	}{
+		{&EventSinkConfig{Type: "statsd", Address: "127.0.0.1:8125"}, false},
+		{&EventSinkConfig{Type: "statsd"}, true},
+		{&EventSinkConfig{Type: "syslog"}, false},
+		{&EventSinkConfig{Type: "file", Path: "events.log"}, false},
+		{&EventSinkConfig{Type: "file"}, true},
+		{&EventSinkConfig{Type: "nope"}, true},
+	}
+
+	for i, tc := range cases {
+		sink, err := tc.Config.Sink()
+		if (err != nil) != tc.Err {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+		if err == nil && sink == nil {
+			t.Fatalf("%d: no sink", i)
+		}
+	}
+}
+
+func TestPolicyConfigRule(t *testing.T) {
+	p := &PolicyConfig{Command: "check-tags"}
+	rule, err := p.Rule("tags")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if rule != nil {
+		t.Fatalf("bad: %#v", rule)
+	}
+
+	p = &PolicyConfig{NamePattern: "^[a-z]+$"}
+	rule, err = p.Rule("naming")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if rule == nil || rule.Name != "naming" {
+		t.Fatalf("bad: %#v", rule)
+	}
+
+	p = &PolicyConfig{NamePattern: "[a-z"}
+	if _, err := p.Rule("naming"); err == nil {
+		t.Fatal("should error")
+	}
+}
+
+func TestExecutablePath(t *testing.T) {
+	actual, err := executablePath()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !filepath.IsAbs(actual) {
+		t.Fatalf("bad: %s", actual)
+	}
+	if _, err := os.Stat(actual); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestPluginPath(t *testing.T) {
+	td, err := ioutil.TempDir("", "tf")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(td)
+
+	name := "terraform-provider-test"
+	exe := filepath.Join(td, name+pluginExeSuffix)
+	if err := ioutil.WriteFile(exe, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Plugins are looked up on the PATH
+	defer os.Setenv("PATH", os.Getenv("PATH"))
+	os.Setenv("PATH", td)
+	if actual := pluginPath(name); actual != exe {
+		t.Fatalf("bad: %s", actual)
+	}
+
+	// Paths can use forward slashes on every platform, and don't need
+	// the extension of executables
+	if actual := pluginPath(filepath.ToSlash(filepath.Join(td, name))); actual != exe {
+		t.Fatalf("bad: %s", actual)
+	}
+
+	// Unknown plugins are left as they are
+	expected := "terraform-provider-nope" + pluginExeSuffix
+	if actual := pluginPath("terraform-provider-nope"); actual != expected {
+		t.Fatalf("bad: %s", actual)
+	}
+}
@@ -12,19 +12,78 @@ import (
 	"strings"
 )
 
+// pluginExeSuffix is the extension of plugin executables.
+const pluginExeSuffix = ""
+
+// systemPluginDirs are the directories that packages of Terraform and
+// its plugins, such as Homebrew and distribution packages, install
+// plugins into for every user.
+var systemPluginDirs = []string{
+	"/usr/local/libexec/terraform",
+	"/usr/libexec/terraform",
+}
+
+// configFile returns the ".terraformrc" file in the home directory. If it
+// doesn't exist, the "terraform/terraformrc" file in the XDG configuration
+// directory is used instead if it exists.
 func configFile() (string, error) {
 	dir, err := configDir()
 	if err != nil {
 		return "", err
 	}
 
-	return filepath.Join(dir, ".terraformrc"), nil
+	path := filepath.Join(dir, ".terraformrc")
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+
+	xdgPath := filepath.Join(
+		xdgDir("XDG_CONFIG_HOME", dir, ".config"), "terraform", "terraformrc")
+	if _, err := os.Stat(xdgPath); err == nil {
+		return xdgPath, nil
+	}
+
+	return path, nil
+}
+
+// pluginDirs returns the directories that plugins are looked for in,
+// in order: the "terraform/plugins" directories of the XDG data
+// directories, then the system plugin directories.
+func pluginDirs() []string {
+	var result []string
+	if home, err := configDir(); err == nil {
+		result = append(result, filepath.Join(
+			xdgDir("XDG_DATA_HOME", home, ".local/share"), "terraform", "plugins"))
+	}
+
+	dataDirs := os.Getenv("XDG_DATA_DIRS")
+	if dataDirs == "" {
+		dataDirs = "/usr/local/share:/usr/share"
+	}
+	for _, dir := range filepath.SplitList(dataDirs) {
+		if dir != "" {
+			result = append(result, filepath.Join(dir, "terraform", "plugins"))
+		}
+	}
+
+	return append(result, systemPluginDirs...)
+}
+
+// xdgDir returns the XDG base directory in the given environmental
+// variable, or the default directory relative to the home directory if
+// it isn't set.
+func xdgDir(env, home, def string) string {
+	if dir := os.Getenv(env); dir != "" {
+		return dir
+	}
+
+	return filepath.Join(home, def)
 }
 
 func configDir() (string, error) {
 	// First prefer the HOME environmental variable
 	if home := os.Getenv("HOME"); home != "" {
-		log.Printf("Detected home directory from env var: %s", home)
+		log.Printf("[DEBUG] Detected home directory from env var: %s", home)
 		return home, nil
 	}
 
@@ -43,3 +102,9 @@ func configDir() (string, error) {
 
 	return result, nil
 }
+
+// consoleColor returns true if colors can be written to the given
+// terminal. Unix-like terminals always understand the color codes.
+func consoleColor(f *os.File) bool {
+	return true
+}
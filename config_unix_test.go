@@ -0,0 +1,136 @@
+// +build darwin freebsd linux netbsd openbsd
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConfigFile_xdg(t *testing.T) {
+	home := testTempDir(t)
+	defer os.RemoveAll(home)
+	xdg := testTempDir(t)
+	defer os.RemoveAll(xdg)
+
+	defer os.Setenv("HOME", os.Getenv("HOME"))
+	defer os.Setenv("XDG_CONFIG_HOME", os.Getenv("XDG_CONFIG_HOME"))
+	os.Setenv("HOME", home)
+	os.Setenv("XDG_CONFIG_HOME", xdg)
+
+	// Without any file, the file in the home directory is the default
+	path, err := configFile()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if path != filepath.Join(home, ".terraformrc") {
+		t.Fatalf("bad: %s", path)
+	}
+
+	xdgPath := filepath.Join(xdg, "terraform", "terraformrc")
+	testWriteFile(t, xdgPath, 0644)
+	path, err = configFile()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if path != xdgPath {
+		t.Fatalf("bad: %s", path)
+	}
+
+	// The file in the home directory comes first
+	testWriteFile(t, filepath.Join(home, ".terraformrc"), 0644)
+	path, err = configFile()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if path != filepath.Join(home, ".terraformrc") {
+		t.Fatalf("bad: %s", path)
+	}
+}
+
+func TestPluginDirs(t *testing.T) {
+	defer os.Setenv("XDG_DATA_HOME", os.Getenv("XDG_DATA_HOME"))
+	defer os.Setenv("XDG_DATA_DIRS", os.Getenv("XDG_DATA_DIRS"))
+	os.Setenv("XDG_DATA_HOME", "/home/foo/data")
+	os.Setenv("XDG_DATA_DIRS", "/opt/share:/usr/share")
+
+	expected := []string{
+		"/home/foo/data/terraform/plugins",
+		"/opt/share/terraform/plugins",
+		"/usr/share/terraform/plugins",
+		"/usr/local/libexec/terraform",
+		"/usr/libexec/terraform",
+	}
+	if actual := pluginDirs(); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestPluginPath_pluginDirs(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	defer os.Setenv("XDG_DATA_HOME", os.Getenv("XDG_DATA_HOME"))
+	defer os.Setenv("PATH", os.Getenv("PATH"))
+	os.Setenv("XDG_DATA_HOME", td)
+	os.Setenv("PATH", "")
+
+	exe := filepath.Join(td, "terraform", "plugins", "terraform-provider-test")
+	testWriteFile(t, exe, 0755)
+	if actual := pluginPath("terraform-provider-test"); actual != exe {
+		t.Fatalf("bad: %s", actual)
+	}
+}
+
+func TestConfigResolveProvider(t *testing.T) {
+	td := testTempDir(t)
+	defer os.RemoveAll(td)
+
+	defer os.Setenv("XDG_DATA_HOME", os.Getenv("XDG_DATA_HOME"))
+	defer os.Setenv("PATH", os.Getenv("PATH"))
+	os.Setenv("XDG_DATA_HOME", td)
+	os.Setenv("PATH", "")
+
+	exe := filepath.Join(td, "terraform", "plugins", "terraform-provider-test")
+	testWriteFile(t, exe, 0755)
+
+	var c Config
+	f, where, err := c.ResolveProvider("test")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if f == nil {
+		t.Fatal("should have a factory")
+	}
+	if where != "found at "+exe {
+		t.Fatalf("bad: %s", where)
+	}
+
+	// Without a release index, missing providers can't be downloaded
+	_, _, err = c.ResolveProvider("nope")
+	if err == nil || !strings.Contains(err.Error(), "terraform-provider-nope") {
+		t.Fatalf("bad: %v", err)
+	}
+}
+
+func testTempDir(t *testing.T) string {
+	td, err := ioutil.TempDir("", "tf")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return td
+}
+
+func testWriteFile(t *testing.T, path string, mode os.FileMode) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := ioutil.WriteFile(path, []byte("#!/bin/sh\n"), mode); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
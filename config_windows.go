@@ -3,6 +3,8 @@
 package main
 
 import (
+	"log"
+	"os"
 	"path/filepath"
 	"syscall"
 	"unsafe"
@@ -11,10 +13,20 @@ import (
 var (
 	shell         = syscall.MustLoadDLL("Shell32.dll")
 	getFolderPath = shell.MustFindProc("SHGetFolderPathW")
+
+	kernel32       = syscall.MustLoadDLL("kernel32.dll")
+	setConsoleMode = kernel32.MustFindProc("SetConsoleMode")
 )
 
 const CSIDL_APPDATA = 26
 
+// enableVirtualTerminalProcessing is the console mode that makes the
+// console interpret color codes, supported since Windows 10.
+const enableVirtualTerminalProcessing = 0x4
+
+// pluginExeSuffix is the extension of plugin executables.
+const pluginExeSuffix = ".exe"
+
 func configFile() (string, error) {
 	dir, err := configDir()
 	if err != nil {
@@ -24,7 +36,25 @@ func configFile() (string, error) {
 	return filepath.Join(dir, "terraform.rc"), nil
 }
 
+// pluginDirs returns the directories that plugins are looked for in: the
+// "terraform.d/plugins" directory in the application data directory.
+func pluginDirs() []string {
+	dir, err := configDir()
+	if err != nil {
+		return nil
+	}
+
+	return []string{filepath.Join(dir, "terraform.d", "plugins")}
+}
+
 func configDir() (string, error) {
+	// First prefer the APPDATA environmental variable, which is what
+	// the documentation refers to as %APPDATA%
+	if dir := os.Getenv("APPDATA"); dir != "" {
+		log.Printf("[DEBUG] Detected application data directory from env var: %s", dir)
+		return dir, nil
+	}
+
 	b := make([]uint16, syscall.MAX_PATH)
 
 	// See: http://msdn.microsoft.com/en-us/library/windows/desktop/bb762181(v=vs.85).aspx
@@ -35,3 +65,29 @@ func configDir() (string, error) {
 
 	return syscall.UTF16ToString(b), nil
 }
+
+// consoleColor returns true if colors can be written to the given
+// terminal. Terminals that emulate colors themselves, such as ConEmu and
+// the terminals of Cygwin and MSYS, are detected from the environment.
+// Otherwise, the console is asked to interpret color codes, which fails
+// on versions of Windows before Windows 10.
+func consoleColor(f *os.File) bool {
+	if os.Getenv("ANSICON") != "" ||
+		os.Getenv("ConEmuANSI") == "ON" ||
+		os.Getenv("TERM") != "" {
+		return true
+	}
+
+	var mode uint32
+	h := syscall.Handle(f.Fd())
+	if err := syscall.GetConsoleMode(h, &mode); err != nil {
+		return false
+	}
+	if mode&enableVirtualTerminalProcessing != 0 {
+		return true
+	}
+
+	r, _, _ := setConsoleMode.Call(
+		uintptr(h), uintptr(mode|enableVirtualTerminalProcessing))
+	return r != 0
+}
@@ -1,11 +1,14 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform/flatmap"
+	"github.com/hashicorp/terraform/helper/didyoumean"
 	"github.com/hashicorp/terraform/terraform"
 )
 
@@ -37,6 +40,23 @@ type Validator struct {
 	Optional []string
 }
 
+// NewConfigSchemaValidator returns a Validator for the attributes of a
+// provider configuration. Terraform sets the attributes it reads from
+// their environment variables in the configuration before validating
+// it, so required attributes can be given either way.
+func NewConfigSchemaValidator(attrs []terraform.ConfigAttribute) *Validator {
+	v := new(Validator)
+	for _, a := range attrs {
+		if a.Required {
+			v.Required = append(v.Required, a.Name)
+		} else {
+			v.Optional = append(v.Optional, a.Name)
+		}
+	}
+
+	return v
+}
+
 func (v *Validator) Validate(
 	c *terraform.ResourceConfig) (ws []string, es []error) {
 	// Flatten the configuration so it is easier to reason about
@@ -75,9 +95,22 @@ func (v *Validator) Validate(
 		delete(flat, p)
 	}
 
-	// The rest are unknown
+	// The rest are unknown. Suggest a close match from the basic keys
+	// in case the key is just a typo.
+	names := make([]string, 0, len(keySet))
+	for k, _ := range keySet {
+		if !strings.Contains(k, ".") {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
 	for k, _ := range flat {
-		es = append(es, fmt.Errorf("Unknown configuration: %s", k))
+		msg := fmt.Sprintf("Unknown configuration: %s", k)
+		if s := didyoumean.NameSuggestion(k, names); s != "" {
+			msg += fmt.Sprintf(" (did you mean %q?)", s)
+		}
+
+		es = append(es, errors.New(msg))
 	}
 
 	return
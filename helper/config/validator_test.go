@@ -2,6 +2,8 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"reflect"
 	"testing"
 
 	"github.com/hashicorp/terraform/config"
@@ -43,6 +45,58 @@ func TestValidator(t *testing.T) {
 	testInvalid(v, c)
 }
 
+func TestNewConfigSchemaValidator(t *testing.T) {
+	attrs := []terraform.ConfigAttribute{
+		terraform.ConfigAttribute{
+			Name:     "token",
+			Required: true,
+			EnvVars:  []string{"TF_TEST_TOKEN"},
+		},
+		terraform.ConfigAttribute{
+			Name:     "email",
+			Required: true,
+		},
+		terraform.ConfigAttribute{
+			Name:    "region",
+			EnvVars: []string{"TF_TEST_REGION"},
+		},
+	}
+
+	// The environment is read by Terraform, not the validator
+	defer os.Setenv("TF_TEST_TOKEN", os.Getenv("TF_TEST_TOKEN"))
+	os.Setenv("TF_TEST_TOKEN", "foo")
+
+	v := NewConfigSchemaValidator(attrs)
+	if !reflect.DeepEqual(v.Required, []string{"token", "email"}) {
+		t.Fatalf("bad: %#v", v.Required)
+	}
+	if !reflect.DeepEqual(v.Optional, []string{"region"}) {
+		t.Fatalf("bad: %#v", v.Optional)
+	}
+}
+
+func TestValidator_didYouMean(t *testing.T) {
+	v := &Validator{
+		Required: []string{"instance_type"},
+		Optional: []string{"ami"},
+	}
+
+	c := testConfig(t, map[string]interface{}{
+		"instance_type": "m1.small",
+		"amj":           "foo",
+	})
+
+	_, es := v.Validate(c)
+	if len(es) != 1 {
+		t.Fatalf("bad: %#v", es)
+	}
+
+	expected := `Unknown configuration: amj (did you mean "ami"?)`
+	if es[0].Error() != expected {
+		t.Fatalf("bad: %s", es[0])
+	}
+}
+
 func TestValidator_array(t *testing.T) {
 	v := &Validator{
 		Required: []string{
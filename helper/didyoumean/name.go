@@ -0,0 +1,61 @@
+package didyoumean
+
+// NameSuggestion tries to find a name from the given slice of suggested
+// names that is close to the given name and returns it if found. If no
+// suggestion is close enough, returns the empty string.
+//
+// This is intended for suggesting corrections for typos in keys and
+// names, such as "instnce_type" instead of "instance_type".
+func NameSuggestion(given string, suggestions []string) string {
+	best := ""
+	bestDist := 3
+	for _, s := range suggestions {
+		dist := distance(given, s)
+		if dist < bestDist {
+			best = s
+			bestDist = dist
+		}
+	}
+
+	return best
+}
+
+// distance is the Levenshtein distance between two strings: the
+// number of single character edits needed to turn one into the other.
+func distance(a, b string) int {
+	ra := []rune(a)
+	rb := []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, cur = cur, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func min(vs ...int) int {
+	result := vs[0]
+	for _, v := range vs[1:] {
+		if v < result {
+			result = v
+		}
+	}
+
+	return result
+}
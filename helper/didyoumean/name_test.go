@@ -0,0 +1,46 @@
+package didyoumean
+
+import (
+	"testing"
+)
+
+func TestNameSuggestion(t *testing.T) {
+	keys := []string{"instance_type", "ami", "availability_zone"}
+
+	cases := []struct {
+		Input  string
+		Output string
+	}{
+		{"instnce_type", "instance_type"},
+		{"instance_typ", "instance_type"},
+		{"ammi", "ami"},
+		{"ami", "ami"},
+		{"tags", ""},
+		{"", ""},
+	}
+
+	for _, tc := range cases {
+		actual := NameSuggestion(tc.Input, keys)
+		if actual != tc.Output {
+			t.Fatalf("Input: %q\n\n%q", tc.Input, actual)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	cases := []struct {
+		A, B   string
+		Output int
+	}{
+		{"", "", 0},
+		{"foo", "", 3},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+	}
+
+	for _, tc := range cases {
+		if actual := distance(tc.A, tc.B); actual != tc.Output {
+			t.Fatalf("%q, %q: %d", tc.A, tc.B, actual)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package resource
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+)
+
+// UniqueIdPrefix is the prefix of the IDs returned by UniqueId.
+const UniqueIdPrefix = "terraform-"
+
+// UniqueId returns a new unique ID, for resources that need a unique
+// name but don't have one configured. Since the name isn't reused, a
+// replacement of the resource can exist at the same time as it, such as
+// a launch configuration that is still in use.
+func UniqueId() string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+
+	return fmt.Sprintf("%s%s-%x",
+		UniqueIdPrefix, time.Now().UTC().Format("20060102150405"), b)
+}
@@ -0,0 +1,20 @@
+package resource
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUniqueId(t *testing.T) {
+	seen := make(map[string]struct{})
+	for i := 0; i < 10; i++ {
+		id := UniqueId()
+		if !strings.HasPrefix(id, UniqueIdPrefix) {
+			t.Fatalf("bad: %s", id)
+		}
+		if _, ok := seen[id]; ok {
+			t.Fatalf("duplicate: %s", id)
+		}
+		seen[id] = struct{}{}
+	}
+}
@@ -94,6 +94,51 @@ func (m *Map) Diff(
 	return r.Diff(s, c, meta)
 }
 
+// List lists the existing resources of the given type, and can be used to
+// satisfy the ListResources method of a ResourceProvider.
+//
+// terraform.ErrListNotSupported is returned if the resource has no List.
+func (m *Map) List(
+	t string,
+	filters map[string]string,
+	meta interface{}) ([]*terraform.ResourceState, error) {
+	r, ok := m.Mapping[t]
+	if !ok {
+		return nil, fmt.Errorf("Unknown resource type: %s", t)
+	}
+	if r.List == nil {
+		return nil, terraform.ErrListNotSupported
+	}
+
+	result, err := r.List(filters, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range result {
+		if s.Attributes == nil {
+			s.Attributes = make(map[string]string)
+		}
+
+		s.Type = t
+		s.Attributes["id"] = s.ID
+	}
+
+	return result, nil
+}
+
+// Listable returns true if resources of at least one type can be listed,
+// which is reported in the capabilities of a ResourceProvider.
+func (m *Map) Listable() bool {
+	for _, r := range m.Mapping {
+		if r.List != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Refresh performs a Refresh on the proper resource type.
 //
 // Refresh on the Resource won't be called if the state represents a
@@ -9,6 +9,73 @@ import (
 	"github.com/hashicorp/terraform/terraform"
 )
 
+func TestMapList(t *testing.T) {
+	var filters map[string]string
+	m := &Map{
+		Mapping: map[string]Resource{
+			"aws_elb": Resource{},
+			"aws_instance": Resource{
+				List: func(
+					f map[string]string,
+					meta interface{}) ([]*terraform.ResourceState, error) {
+					filters = f
+					return []*terraform.ResourceState{
+						&terraform.ResourceState{ID: "i-1"},
+					}, nil
+				},
+			},
+		},
+	}
+
+	actual, err := m.List("aws_instance", map[string]string{"foo": "bar"}, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := []*terraform.ResourceState{
+		&terraform.ResourceState{
+			ID:         "i-1",
+			Type:       "aws_instance",
+			Attributes: map[string]string{"id": "i-1"},
+		},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+	if filters["foo"] != "bar" {
+		t.Fatalf("bad: %#v", filters)
+	}
+
+	if _, err := m.List("aws_elb", nil, nil); err != terraform.ErrListNotSupported {
+		t.Fatalf("bad: %s", err)
+	}
+	if _, err := m.List("aws_foo", nil, nil); err == nil {
+		t.Fatal("should error")
+	}
+}
+
+func TestMapListable(t *testing.T) {
+	m := &Map{
+		Mapping: map[string]Resource{
+			"aws_elb": Resource{},
+		},
+	}
+	if m.Listable() {
+		t.Fatal("should not be listable")
+	}
+
+	m.Mapping["aws_instance"] = Resource{
+		List: func(
+			map[string]string,
+			interface{}) ([]*terraform.ResourceState, error) {
+			return nil, nil
+		},
+	}
+	if !m.Listable() {
+		t.Fatal("should be listable")
+	}
+}
+
 func TestMapResources(t *testing.T) {
 	m := &Map{
 		Mapping: map[string]Resource{
@@ -10,6 +10,7 @@ type Resource struct {
 	Create          CreateFunc
 	Destroy         DestroyFunc
 	Diff            DiffFunc
+	List            ListFunc
 	Refresh         RefreshFunc
 	Update          UpdateFunc
 }
@@ -33,6 +34,13 @@ type DiffFunc func(
 	*terraform.ResourceConfig,
 	interface{}) (*terraform.ResourceDiff, error)
 
+// ListFunc is a function that returns the existing resources of a type
+// that match the given filters, with their state filled in as a refresh
+// would. It's optional, and resources without one can't be listed.
+type ListFunc func(
+	map[string]string,
+	interface{}) ([]*terraform.ResourceState, error)
+
 // RefreshFunc is a function that performs a refresh of a specific type
 // of resource.
 type RefreshFunc func(
@@ -6,10 +6,13 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"os/exec"
 	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/plugin"
+	"github.com/hashicorp/terraform/rpc"
 	"github.com/hashicorp/terraform/terraform"
 )
 
@@ -25,7 +28,7 @@ type TestCheckFunc func(*terraform.State) error
 // lifecycle of a resource in a specific configuration.
 //
 // When the destroy plan is executed, the config from the last TestStep
-// is used to plan it.
+// that could be loaded is used to plan it.
 type TestCase struct {
 	// PreCheck, if non-nil, will be called before any test steps are
 	// executed. It will only be executed in the case that the steps
@@ -36,6 +39,12 @@ type TestCase struct {
 	// Provider is the ResourceProvider that will be under test.
 	Providers map[string]terraform.ResourceProvider
 
+	// ProviderPlugins are the paths of provider plugin binaries that
+	// will be under test, by provider name. They are started and used
+	// over RPC the same way Terraform uses them, so this tests the
+	// provider as it is shipped.
+	ProviderPlugins map[string]string
+
 	// CheckDestroy is called after the resource is finally destroyed
 	// to allow the tester to test that the resource is truly gone.
 	CheckDestroy TestCheckFunc
@@ -105,33 +114,45 @@ func Test(t TestT, c TestCase) {
 	for k, p := range c.Providers {
 		ctxProviders[k] = terraform.ResourceProviderFactoryFixed(p)
 	}
+	for k, path := range c.ProviderPlugins {
+		p, client, err := testProviderPlugin(path)
+		if client != nil {
+			defer client.Kill()
+		}
+		if err != nil {
+			t.Fatal(fmt.Sprintf(
+				"Error starting provider plugin '%s': %s", k, err))
+			return
+		}
+
+		ctxProviders[k] = terraform.ResourceProviderFactoryFixed(p)
+	}
 	opts := terraform.ContextOpts{Providers: ctxProviders}
 
-	// A single state variable to track the lifecycle, starting with no state
+	// A single state variable to track the lifecycle, starting with no
+	// state, and the configuration to destroy it with.
 	var state *terraform.State
-
-	// Go through each step and run it
-	for i, step := range c.Steps {
-		var err error
-		log.Printf("[WARN] Test: Executing step %d", i)
-		state, err = testStep(opts, state, step)
-		if err != nil {
-			t.Error(fmt.Sprintf(
-				"Step %d error: %s", i, err))
-			break
+	var lastConfig *config.Config
+
+	// Whatever happens to the steps, including a panic or a check that
+	// calls t.Fatal, destroy what was created so that nothing is left
+	// behind.
+	defer func() {
+		if state == nil || lastConfig == nil {
+			log.Printf("[WARN] Skipping destroy test since there is no state.")
+			return
 		}
-	}
 
-	// If we have a state, then run the destroy
-	if state != nil {
 		destroyStep := TestStep{
-			Config:  c.Steps[len(c.Steps)-1].Config,
 			Check:   c.CheckDestroy,
 			Destroy: true,
 		}
 
 		log.Printf("[WARN] Test: Executing destroy step")
-		state, err := testStep(opts, state, destroyStep)
+		state, err := testStep(opts, state, lastConfig, destroyStep)
+		if err == nil {
+			err = testCheck(state, destroyStep)
+		}
 		if err != nil {
 			t.Error(fmt.Sprintf(
 				"Error destroying resource! WARNING: Dangling resources\n"+
@@ -140,19 +161,62 @@ func Test(t TestT, c TestCase) {
 				err,
 				state))
 		}
-	} else {
-		log.Printf("[WARN] Skipping destroy test since there is no state.")
+	}()
+
+	// Go through each step and run it
+	for i, step := range c.Steps {
+		log.Printf("[WARN] Test: Executing step %d", i)
+		cfg, err := testStepConfig(step.Config)
+		if err != nil {
+			t.Error(fmt.Sprintf(
+				"Step %d error: %s", i, err))
+			break
+		}
+		lastConfig = cfg
+
+		state, err = testStep(opts, state, cfg, step)
+		if err == nil {
+			err = testCheck(state, step)
+		}
+		if err != nil {
+			t.Error(fmt.Sprintf(
+				"Step %d error: %s", i, err))
+			break
+		}
 	}
 }
 
-func testStep(
-	opts terraform.ContextOpts,
-	state *terraform.State,
-	step TestStep) (*terraform.State, error) {
+// testProviderPlugin starts the provider plugin at the given path. The
+// client is returned whenever the plugin was started, even if there is
+// an error, so that it can be killed.
+func testProviderPlugin(
+	path string) (terraform.ResourceProvider, *plugin.Client, error) {
+	client := plugin.NewClient(&plugin.ClientConfig{
+		Cmd: exec.Command(path),
+	})
+
+	rpcClient, err := client.Client()
+	if err != nil {
+		return nil, client, err
+	}
+
+	service, err := client.Service()
+	if err != nil {
+		return nil, client, err
+	}
+
+	return &rpc.ResourceProvider{
+		Client: rpcClient,
+		Name:   service,
+	}, client, nil
+}
+
+// testStepConfig loads the configuration of a step.
+func testStepConfig(raw string) (*config.Config, error) {
 	// Write the configuration
 	cfgF, err := ioutil.TempFile("", "tf-test")
 	if err != nil {
-		return state, fmt.Errorf(
+		return nil, fmt.Errorf(
 			"Error creating temporary file for config: %s", err)
 	}
 	cfgPath := cfgF.Name() + ".tf"
@@ -161,25 +225,33 @@ func testStep(
 
 	cfgF, err = os.Create(cfgPath)
 	if err != nil {
-		return state, fmt.Errorf(
+		return nil, fmt.Errorf(
 			"Error creating temporary file for config: %s", err)
 	}
 	defer os.Remove(cfgPath)
 
-	_, err = io.Copy(cfgF, strings.NewReader(step.Config))
+	_, err = io.Copy(cfgF, strings.NewReader(raw))
 	cfgF.Close()
 	if err != nil {
-		return state, fmt.Errorf(
+		return nil, fmt.Errorf(
 			"Error creating temporary file for config: %s", err)
 	}
 
 	// Parse the configuration
 	config, err := config.Load(cfgPath)
 	if err != nil {
-		return state, fmt.Errorf(
+		return nil, fmt.Errorf(
 			"Error parsing configuration: %s", err)
 	}
 
+	return config, nil
+}
+
+func testStep(
+	opts terraform.ContextOpts,
+	state *terraform.State,
+	config *config.Config,
+	step TestStep) (*terraform.State, error) {
 	// Build the context
 	opts.Config = config
 	opts.State = state
@@ -195,7 +267,7 @@ func testStep(
 	}
 
 	// Refresh!
-	state, err = ctx.Refresh()
+	state, err := ctx.Refresh()
 	if err != nil {
 		return state, fmt.Errorf(
 			"Error refreshing: %s", err)
@@ -215,14 +287,22 @@ func testStep(
 		return state, fmt.Errorf("Error applying: %s", err)
 	}
 
+	return state, nil
+}
+
+// testCheck runs the check of a step, if it has one. It is separate from
+// testStep so that the state is known even if the check panics.
+func testCheck(state *terraform.State, step TestStep) error {
+	if step.Check == nil {
+		return nil
+	}
+
 	// Check! Excitement!
-	if step.Check != nil {
-		if err = step.Check(state); err != nil {
-			err = fmt.Errorf("Check failed: %s", err)
-		}
+	if err := step.Check(state); err != nil {
+		return fmt.Errorf("Check failed: %s", err)
 	}
 
-	return state, err
+	return nil
 }
 
 // ComposeTestCheckFunc lets you compose multiple TestCheckFuncs into
@@ -3,6 +3,7 @@ package resource
 import (
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform/terraform"
@@ -159,6 +160,111 @@ func TestTest_stepError(t *testing.T) {
 	}
 }
 
+func TestTest_stepConfigError(t *testing.T) {
+	mp := testProvider()
+	mp.ApplyReturn = &terraform.ResourceState{
+		ID: "foo",
+	}
+
+	checkDestroy := false
+	checkDestroyFn := func(*terraform.State) error {
+		checkDestroy = true
+		return nil
+	}
+
+	mt := new(mockT)
+	Test(mt, TestCase{
+		Providers: map[string]terraform.ResourceProvider{
+			"test": mp,
+		},
+		CheckDestroy: checkDestroyFn,
+		Steps: []TestStep{
+			TestStep{
+				Config: testConfigStr,
+			},
+			TestStep{
+				Config: testConfigStrInvalid,
+			},
+		},
+	})
+
+	if !mt.failed() {
+		t.Fatal("test should've failed")
+	}
+	if !strings.Contains(mt.failMessage(), "Step 1 error") {
+		t.Fatalf("bad: %s", mt.failMessage())
+	}
+
+	// The config of the first step is used to destroy
+	if !checkDestroy {
+		t.Fatal("didn't call check for destroy")
+	}
+}
+
+func TestTest_stepPanic(t *testing.T) {
+	mp := testProvider()
+	mp.ApplyReturn = &terraform.ResourceState{
+		ID: "foo",
+	}
+
+	checkDestroy := false
+	checkDestroyFn := func(*terraform.State) error {
+		checkDestroy = true
+		return nil
+	}
+
+	checkStepFn := func(*terraform.State) error {
+		panic("check")
+	}
+
+	mt := new(mockT)
+	func() {
+		defer func() {
+			if r := recover(); r != "check" {
+				t.Fatalf("bad: %#v", r)
+			}
+		}()
+
+		Test(mt, TestCase{
+			Providers: map[string]terraform.ResourceProvider{
+				"test": mp,
+			},
+			CheckDestroy: checkDestroyFn,
+			Steps: []TestStep{
+				TestStep{
+					Config: testConfigStr,
+					Check:  checkStepFn,
+				},
+			},
+		})
+	}()
+
+	if !checkDestroy {
+		t.Fatal("didn't call check for destroy")
+	}
+}
+
+func TestTest_providerPluginError(t *testing.T) {
+	mt := new(mockT)
+	Test(mt, TestCase{
+		ProviderPlugins: map[string]string{
+			"test": "/nonexistent/terraform-provider-test",
+		},
+		Steps: []TestStep{
+			TestStep{
+				Config: testConfigStr,
+			},
+		},
+	})
+
+	if !mt.FatalCalled {
+		t.Fatal("fatal should be called")
+	}
+	if !strings.Contains(mt.failMessage(), "provider plugin 'test'") {
+		t.Fatalf("bad: %s", mt.failMessage())
+	}
+}
+
 func TestComposeTestCheckFunc(t *testing.T) {
 	cases := []struct {
 		F      []TestCheckFunc
@@ -277,3 +383,7 @@ func testProvider() *terraform.MockResourceProvider {
 const testConfigStr = `
 resource "test_instance" "foo" {}
 `
+
+const testConfigStrInvalid = `
+resource "test_instance" "foo" {
+`
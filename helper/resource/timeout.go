@@ -0,0 +1,21 @@
+package resource
+
+import (
+	"time"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// Timeout returns how long the given action, "create", "update" or
+// "delete", may take according to the diff, or def if the configuration
+// doesn't limit it. Resources should wait for long operations at most
+// this long, since Terraform stops waiting for them once it's up.
+func Timeout(d *terraform.ResourceDiff, action string, def time.Duration) time.Duration {
+	if d != nil {
+		if v := d.Timeouts[action]; v > 0 {
+			return v
+		}
+	}
+
+	return def
+}
@@ -0,0 +1,34 @@
+package resource
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestTimeout(t *testing.T) {
+	d := &terraform.ResourceDiff{
+		Timeouts: map[string]time.Duration{
+			"create": 30 * time.Minute,
+		},
+	}
+
+	cases := []struct {
+		Diff   *terraform.ResourceDiff
+		Action string
+		Result time.Duration
+	}{
+		{d, "create", 30 * time.Minute},
+		{d, "update", 5 * time.Minute},
+		{new(terraform.ResourceDiff), "create", 5 * time.Minute},
+		{nil, "create", 5 * time.Minute},
+	}
+
+	for i, tc := range cases {
+		actual := Timeout(tc.Diff, tc.Action, 5*time.Minute)
+		if actual != tc.Result {
+			t.Fatalf("%d: bad: %s", i, actual)
+		}
+	}
+}
@@ -28,6 +28,10 @@ type StateChangeConf struct {
 	Target     string           // Target state
 	Timeout    time.Duration    // The amount of time to wait before timeout
 	MinTimeout time.Duration    // Smallest time to wait before refreshes
+
+	// StopCh stops the wait with an error when it's closed, such as when
+	// the provider is stopped.
+	StopCh <-chan struct{}
 }
 
 // WaitForState watches an object and waits for it to achieve the state
@@ -112,6 +116,10 @@ func (conf *StateChangeConf) WaitForState() (interface{}, error) {
 	select {
 	case <-doneCh:
 		return result, resulterr
+	case <-conf.StopCh:
+		return nil, fmt.Errorf(
+			"stopped while waiting for state to become '%s'",
+			conf.Target)
 	case <-time.After(conf.Timeout):
 		return nil, fmt.Errorf(
 			"timeout while waiting for state to become '%s'",
@@ -45,6 +45,27 @@ func TestWaitForState_timeout(t *testing.T) {
 
 }
 
+func TestWaitForState_stop(t *testing.T) {
+	stopCh := make(chan struct{})
+	close(stopCh)
+
+	conf := &StateChangeConf{
+		Pending: []string{"pending", "incomplete"},
+		Target:  "running",
+		Refresh: TimeoutStateRefreshFunc(),
+		Timeout: 200 * time.Second,
+		StopCh:  stopCh,
+	}
+
+	obj, err := conf.WaitForState()
+	if err == nil || err.Error() != "stopped while waiting for state to become 'running'" {
+		t.Fatalf("err: %s", err)
+	}
+	if obj != nil {
+		t.Fatalf("should not return obj")
+	}
+}
+
 func TestWaitForState_success(t *testing.T) {
 	conf := &StateChangeConf{
 		Pending: []string{"pending", "incomplete"},
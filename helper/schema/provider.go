@@ -39,6 +39,20 @@ type Provider struct {
 	// See the ConfigureFunc documentation for more information.
 	ConfigureFunc ConfigureFunc
 
+	// ValidateCredentialsFunc is a function for checking that the
+	// credentials the provider was configured with work. If it is
+	// omitted, the credentials aren't checked.
+	//
+	// See the ValidateCredentialsFunc documentation for more information.
+	ValidateCredentialsFunc ValidateCredentialsFunc
+
+	// StopFunc is a function for canceling the calls of the provider in
+	// progress when the operation is stopped. If it is omitted, the calls
+	// are left to finish.
+	//
+	// See the StopFunc documentation for more information.
+	StopFunc StopFunc
+
 	meta interface{}
 }
 
@@ -50,6 +64,18 @@ type Provider struct {
 // structure, etc.
 type ConfigureFunc func(*ResourceData) (interface{}, error)
 
+// ValidateCredentialsFunc is the function used to check the credentials
+// of a Provider after it is configured. It is given the value returned
+// by the ConfigureFunc, and should return an error describing what is
+// wrong with the credentials if they don't work.
+type ValidateCredentialsFunc func(interface{}) error
+
+// StopFunc is the function used to cancel the calls of a Provider in
+// progress, such as requests or waits for resources to become available.
+// It is given the value returned by the ConfigureFunc, and is called
+// concurrently with the calls it cancels.
+type StopFunc func(interface{}) error
+
 // InternalValidate should be called to validate the structure
 // of the provider.
 //
@@ -69,6 +95,17 @@ func (p *Provider) InternalValidate() error {
 		if err := r.InternalValidate(); err != nil {
 			return fmt.Errorf("%s: %s", k, err)
 		}
+
+		for sk, s := range r.Schema {
+			if len(s.EnvVars) > 0 {
+				return fmt.Errorf(
+					"%s: %s: EnvVars can only be set in the provider schema", k, sk)
+			}
+		}
+
+		if err := r.validateOperations(); err != nil {
+			return fmt.Errorf("%s: %s", k, err)
+		}
 	}
 
 	return nil
@@ -134,6 +171,76 @@ func (p *Provider) Configure(c *terraform.ResourceConfig) error {
 	return nil
 }
 
+// ValidateCredentials implementation of terraform.ResourceProvider interface.
+func (p *Provider) ValidateCredentials() error {
+	if p.ValidateCredentialsFunc == nil {
+		return nil
+	}
+
+	return p.ValidateCredentialsFunc(p.meta)
+}
+
+// Stop implementation of terraform.ResourceProvider interface.
+func (p *Provider) Stop() error {
+	// If the provider isn't configured, nothing can be in progress
+	if p.StopFunc == nil || p.meta == nil {
+		return nil
+	}
+
+	return p.StopFunc(p.meta)
+}
+
+// Capabilities implementation of terraform.ResourceProvider interface.
+func (p *Provider) Capabilities() terraform.ResourceProviderCapabilities {
+	result := terraform.ResourceProviderCapabilities{
+		ValidateCredentials: p.ValidateCredentialsFunc != nil,
+		Stop:                p.StopFunc != nil,
+	}
+	for _, r := range p.ResourcesMap {
+		if r.List != nil {
+			result.ListResources = true
+			break
+		}
+	}
+
+	return result
+}
+
+// WriteOnlyConfig implementation of terraform.ResourceProvider interface.
+func (p *Provider) WriteOnlyConfig() []string {
+	var result []string
+	for k, v := range p.Schema {
+		if v.WriteOnly {
+			result = append(result, k)
+		}
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// ConfigSchema implementation of terraform.ResourceProvider interface.
+func (p *Provider) ConfigSchema() []terraform.ConfigAttribute {
+	keys := make([]string, 0, len(p.Schema))
+	for k, _ := range p.Schema {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	result := make([]terraform.ConfigAttribute, 0, len(keys))
+	for _, k := range keys {
+		s := p.Schema[k]
+		result = append(result, terraform.ConfigAttribute{
+			Name:      k,
+			Required:  s.Required,
+			WriteOnly: s.WriteOnly,
+			EnvVars:   s.EnvVars,
+		})
+	}
+
+	return result
+}
+
 // Apply implementation of terraform.ResourceProvider interface.
 func (p *Provider) Apply(
 	s *terraform.ResourceState,
@@ -169,6 +276,44 @@ func (p *Provider) Refresh(
 	return r.Refresh(s, p.meta)
 }
 
+// ListResources implementation of terraform.ResourceProvider interface.
+func (p *Provider) ListResources(
+	t string, filters map[string]string) ([]*terraform.ResourceState, error) {
+	r, ok := p.ResourcesMap[t]
+	if !ok {
+		return nil, fmt.Errorf("unknown resource type: %s", t)
+	}
+	if r.List == nil {
+		return nil, terraform.ErrListNotSupported
+	}
+
+	ids, err := r.List(filters, p.meta)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*terraform.ResourceState, 0, len(ids))
+	for _, id := range ids {
+		state, err := r.Refresh(&terraform.ResourceState{
+			ID:   id,
+			Type: t,
+		}, p.meta)
+		if err != nil {
+			return nil, err
+		}
+
+		// The resource may have been deleted since it was listed
+		if state == nil || state.ID == "" {
+			continue
+		}
+
+		state.Type = t
+		result = append(result, state)
+	}
+
+	return result, nil
+}
+
 // Resources implementation of terraform.ResourceProvider interface.
 func (p *Provider) Resources() []terraform.ResourceType {
 	keys := make([]string, 0, len(p.ResourcesMap))
@@ -179,8 +324,21 @@ func (p *Provider) Resources() []terraform.ResourceType {
 
 	result := make([]terraform.ResourceType, 0, len(keys))
 	for _, k := range keys {
+		// Resources with a "tags" mapping get the default tags of the
+		// provider configuration
+		var taggable bool
+		var attrs []terraform.ResourceAttribute
+		if r := p.ResourcesMap[k]; r != nil {
+			if s, ok := r.Schema["tags"]; ok {
+				taggable = s.Type == TypeMap
+			}
+			attrs = schemaMap(r.Schema).Attributes()
+		}
+
 		result = append(result, terraform.ResourceType{
-			Name: k,
+			Name:       k,
+			Taggable:   taggable,
+			Attributes: attrs,
 		})
 	}
 
@@ -85,6 +85,126 @@ func TestProviderConfigure(t *testing.T) {
 	}
 }
 
+func TestProviderInternalValidate(t *testing.T) {
+	noop := func(*ResourceData, interface{}) error { return nil }
+
+	cases := []struct {
+		In  *Provider
+		Err bool
+	}{
+		{
+			&Provider{
+				ResourcesMap: map[string]*Resource{
+					"foo": &Resource{
+						Schema: map[string]*Schema{
+							"foo": &Schema{
+								Type:     TypeString,
+								Required: true,
+							},
+						},
+						Create: noop,
+						Read:   noop,
+						Update: noop,
+						Delete: noop,
+					},
+				},
+			},
+			false,
+		},
+
+		// No Read
+		{
+			&Provider{
+				ResourcesMap: map[string]*Resource{
+					"foo": &Resource{
+						Create: noop,
+						Delete: noop,
+					},
+				},
+			},
+			true,
+		},
+
+		// No Update, and a field that isn't ForceNew
+		{
+			&Provider{
+				ResourcesMap: map[string]*Resource{
+					"foo": &Resource{
+						Schema: map[string]*Schema{
+							"foo": &Schema{
+								Type:     TypeString,
+								Optional: true,
+							},
+						},
+						Create: noop,
+						Read:   noop,
+						Delete: noop,
+					},
+				},
+			},
+			true,
+		},
+
+		// No Update, with only ForceNew, computed and write-only fields
+		{
+			&Provider{
+				ResourcesMap: map[string]*Resource{
+					"foo": &Resource{
+						Schema: map[string]*Schema{
+							"foo": &Schema{
+								Type:     TypeString,
+								Required: true,
+								ForceNew: true,
+							},
+							"bar": &Schema{
+								Type:     TypeString,
+								Computed: true,
+							},
+							"password": &Schema{
+								Type:      TypeString,
+								Required:  true,
+								WriteOnly: true,
+							},
+						},
+						Create: noop,
+						Read:   noop,
+						Delete: noop,
+					},
+				},
+			},
+			false,
+		},
+		// Environment variables in the schema of a resource
+		{
+			&Provider{
+				ResourcesMap: map[string]*Resource{
+					"foo": &Resource{
+						Schema: map[string]*Schema{
+							"foo": &Schema{
+								Type:     TypeString,
+								Required: true,
+								ForceNew: true,
+								EnvVars:  []string{"FOO"},
+							},
+						},
+						Create: noop,
+						Read:   noop,
+						Delete: noop,
+					},
+				},
+			},
+			true,
+		},
+	}
+
+	for i, tc := range cases {
+		err := tc.In.InternalValidate()
+		if (err != nil) != tc.Err {
+			t.Fatalf("%d: bad: %s", i, err)
+		}
+	}
+}
+
 func TestProviderResources(t *testing.T) {
 	cases := []struct {
 		P      *Provider
@@ -107,6 +227,123 @@ func TestProviderResources(t *testing.T) {
 				terraform.ResourceType{Name: "foo"},
 			},
 		},
+
+		{
+			P: &Provider{
+				ResourcesMap: map[string]*Resource{
+					"foo": &Resource{
+						Schema: map[string]*Schema{
+							"tags": &Schema{Type: TypeMap},
+						},
+					},
+					"bar": &Resource{
+						Schema: map[string]*Schema{
+							"tags": &Schema{Type: TypeString},
+						},
+					},
+				},
+			},
+			Result: []terraform.ResourceType{
+				terraform.ResourceType{
+					Name: "bar",
+					Attributes: []terraform.ResourceAttribute{
+						terraform.ResourceAttribute{
+							Name: "tags",
+							Type: "string",
+						},
+					},
+				},
+				terraform.ResourceType{
+					Name:     "foo",
+					Taggable: true,
+					Attributes: []terraform.ResourceAttribute{
+						terraform.ResourceAttribute{
+							Name: "tags",
+							Type: "map",
+						},
+					},
+				},
+			},
+		},
+
+		{
+			P: &Provider{
+				ResourcesMap: map[string]*Resource{
+					"foo": &Resource{
+						Schema: map[string]*Schema{
+							"name": &Schema{
+								Type:     TypeString,
+								Required: true,
+								ForceNew: true,
+							},
+							"ports": &Schema{
+								Type:     TypeList,
+								Optional: true,
+								Elem:     &Schema{Type: TypeInt},
+							},
+							"rule": &Schema{
+								Type:     TypeSet,
+								Optional: true,
+								Computed: true,
+								Elem: &Resource{
+									Schema: map[string]*Schema{
+										"port": &Schema{
+											Type:     TypeInt,
+											Required: true,
+										},
+									},
+								},
+							},
+							"token": &Schema{
+								Type:       TypeString,
+								Optional:   true,
+								WriteOnly:  true,
+								Deprecated: "use name",
+							},
+						},
+					},
+				},
+			},
+			Result: []terraform.ResourceType{
+				terraform.ResourceType{
+					Name: "foo",
+					Attributes: []terraform.ResourceAttribute{
+						terraform.ResourceAttribute{
+							Name:     "name",
+							Type:     "string",
+							Required: true,
+							ForceNew: true,
+						},
+						terraform.ResourceAttribute{
+							Name:     "ports",
+							Type:     "list",
+							Optional: true,
+							ElemType: "int",
+						},
+						terraform.ResourceAttribute{
+							Name:     "rule",
+							Type:     "set",
+							Optional: true,
+							Computed: true,
+							Attributes: []terraform.ResourceAttribute{
+								terraform.ResourceAttribute{
+									Name:     "port",
+									Type:     "int",
+									Required: true,
+								},
+							},
+						},
+						terraform.ResourceAttribute{
+							Name:       "token",
+							Type:       "string",
+							Optional:   true,
+							WriteOnly:  true,
+							Deprecated: "use name",
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for i, tc := range cases {
@@ -156,6 +393,198 @@ func TestProviderValidateResource(t *testing.T) {
 	}
 }
 
+func TestProviderWriteOnlyConfig(t *testing.T) {
+	p := &Provider{
+		Schema: map[string]*Schema{
+			"region": &Schema{
+				Type:     TypeString,
+				Optional: true,
+			},
+			"token": &Schema{
+				Type:      TypeString,
+				Required:  true,
+				WriteOnly: true,
+			},
+			"key": &Schema{
+				Type:      TypeString,
+				Required:  true,
+				WriteOnly: true,
+			},
+		},
+	}
+
+	actual := p.WriteOnlyConfig()
+	expected := []string{"key", "token"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestProviderConfigSchema(t *testing.T) {
+	p := &Provider{
+		Schema: map[string]*Schema{
+			"token": &Schema{
+				Type:      TypeString,
+				Required:  true,
+				WriteOnly: true,
+				EnvVars:   []string{"FOO_TOKEN"},
+			},
+			"region": &Schema{
+				Type:     TypeString,
+				Optional: true,
+			},
+		},
+	}
+
+	actual := p.ConfigSchema()
+	expected := []terraform.ConfigAttribute{
+		terraform.ConfigAttribute{
+			Name: "region",
+		},
+		terraform.ConfigAttribute{
+			Name:      "token",
+			Required:  true,
+			WriteOnly: true,
+			EnvVars:   []string{"FOO_TOKEN"},
+		},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestProviderValidateCredentials(t *testing.T) {
+	p := new(Provider)
+	if err := p.ValidateCredentials(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	p.SetMeta(42)
+	p.ValidateCredentialsFunc = func(meta interface{}) error {
+		if meta.(int) != 42 {
+			return fmt.Errorf("bad meta: %#v", meta)
+		}
+
+		return fmt.Errorf("expired")
+	}
+	if err := p.ValidateCredentials(); err == nil || err.Error() != "expired" {
+		t.Fatalf("bad: %#v", err)
+	}
+}
+
+func TestProviderStop(t *testing.T) {
+	called := false
+	p := &Provider{
+		StopFunc: func(meta interface{}) error {
+			if meta.(int) != 42 {
+				return fmt.Errorf("bad meta: %#v", meta)
+			}
+
+			called = true
+			return nil
+		},
+	}
+
+	// Nothing is in progress before the provider is configured
+	if err := p.Stop(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if called {
+		t.Fatal("stop should not be called")
+	}
+
+	p.SetMeta(42)
+	if err := p.Stop(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !called {
+		t.Fatal("stop should be called")
+	}
+}
+
+func TestProviderListResources(t *testing.T) {
+	p := &Provider{
+		ResourcesMap: map[string]*Resource{
+			"foo": &Resource{
+				Schema: map[string]*Schema{
+					"name": &Schema{
+						Type:     TypeString,
+						Computed: true,
+					},
+				},
+				List: func(filters map[string]string, meta interface{}) ([]string, error) {
+					if filters["name"] != "bar" {
+						return nil, fmt.Errorf("bad filters: %#v", filters)
+					}
+
+					return []string{"1", "2"}, nil
+				},
+				Read: func(d *ResourceData, meta interface{}) error {
+					// Resource 2 was deleted after it was listed
+					if d.Id() == "2" {
+						d.SetId("")
+						return nil
+					}
+
+					return d.Set("name", "bar")
+				},
+			},
+			"bar": &Resource{},
+		},
+	}
+
+	result, err := p.ListResources("foo", map[string]string{"name": "bar"})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(result) != 1 {
+		t.Fatalf("bad: %#v", result)
+	}
+	if result[0].ID != "1" || result[0].Type != "foo" {
+		t.Fatalf("bad: %#v", result[0])
+	}
+	if result[0].Attributes["name"] != "bar" {
+		t.Fatalf("bad: %#v", result[0].Attributes)
+	}
+
+	if _, err := p.ListResources("bar", nil); err != terraform.ErrListNotSupported {
+		t.Fatalf("bad: %#v", err)
+	}
+	if _, err := p.ListResources("baz", nil); err == nil {
+		t.Fatal("should error")
+	}
+}
+
+func TestProviderCapabilities(t *testing.T) {
+	p := &Provider{
+		ResourcesMap: map[string]*Resource{
+			"foo": &Resource{},
+		},
+	}
+
+	expected := terraform.ResourceProviderCapabilities{}
+	if actual := p.Capabilities(); actual != expected {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	p.ResourcesMap["bar"] = &Resource{
+		List: func(map[string]string, interface{}) ([]string, error) {
+			return nil, nil
+		},
+	}
+	p.ValidateCredentialsFunc = func(interface{}) error { return nil }
+	p.StopFunc = func(interface{}) error { return nil }
+
+	expected = terraform.ResourceProviderCapabilities{
+		ListResources:       true,
+		ValidateCredentials: true,
+		Stop:                true,
+	}
+	if actual := p.Capabilities(); actual != expected {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
 func TestProviderMeta(t *testing.T) {
 	p := new(Provider)
 	if v := p.Meta(); v != nil {
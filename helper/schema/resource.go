@@ -45,6 +45,14 @@ type Resource struct {
 	Read   ReadFunc
 	Update UpdateFunc
 	Delete DeleteFunc
+
+	// List returns the IDs of the existing resources of this type that
+	// match the given filters, so that they can be brought under the
+	// management of Terraform. Each one is then refreshed with Read.
+	//
+	// List is optional. If it is not implemented, resources of this type
+	// can't be listed.
+	List ListFunc
 }
 
 // See Resource documentation.
@@ -59,6 +67,9 @@ type UpdateFunc func(*ResourceData, interface{}) error
 // See Resource documentation.
 type DeleteFunc func(*ResourceData, interface{}) error
 
+// See Resource documentation.
+type ListFunc func(map[string]string, interface{}) ([]string, error)
+
 // Apply creates, updates, and/or deletes a resource.
 func (r *Resource) Apply(
 	s *terraform.ResourceState,
@@ -156,3 +167,35 @@ func (r *Resource) InternalValidate() error {
 
 	return schemaMap(r.Schema).InternalValidate()
 }
+
+// validateOperations validates that the CRUD operations of a resource
+// that is managed by a provider are set, so that mistakes are caught by
+// InternalValidate instead of when the operation is first used.
+//
+// Resources that are only the element of a list or set don't have
+// operations, which is why this isn't part of InternalValidate.
+func (r *Resource) validateOperations() error {
+	if r.Create == nil {
+		return errors.New("Create must be set")
+	}
+	if r.Read == nil {
+		return errors.New("Read must be set")
+	}
+	if r.Delete == nil {
+		return errors.New("Delete must be set")
+	}
+
+	// Without Update, changing any field that can be configured must
+	// create a new resource. WriteOnly fields are only in the diff when
+	// the resource is created, so they can never be changed.
+	if r.Update == nil {
+		for k, v := range r.Schema {
+			if (v.Optional || v.Required) && !v.ForceNew && !v.WriteOnly {
+				return fmt.Errorf(
+					"%s: ForceNew must be set since Update isn't", k)
+			}
+		}
+	}
+
+	return nil
+}
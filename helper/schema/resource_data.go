@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/mitchellh/mapstructure"
@@ -191,6 +192,17 @@ func (d *ResourceData) Dependencies() []terraform.ResourceDependency {
 	return nil
 }
 
+// Timeout returns how long the given action, "create", "update" or
+// "delete", may take, as set in the timeouts block of the resource. It's
+// zero if no timeout was set, and the resource should use its own.
+func (d *ResourceData) Timeout(key string) time.Duration {
+	if d.diff == nil {
+		return 0
+	}
+
+	return d.diff.Timeouts[key]
+}
+
 // SetId sets the ID of the resource. If the value is blank, then the
 // resource is destroyed.
 func (d *ResourceData) SetId(v string) {
@@ -904,6 +916,10 @@ func (d *ResourceData) stateObject(
 	schema map[string]*Schema) map[string]string {
 	result := make(map[string]string)
 	for k, v := range schema {
+		if v.WriteOnly {
+			continue
+		}
+
 		key := k
 		if prefix != "" {
 			key = prefix + "." + key
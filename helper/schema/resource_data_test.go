@@ -3,6 +3,7 @@ package schema
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform/terraform"
 )
@@ -1391,6 +1392,26 @@ func TestResourceDataSet(t *testing.T) {
 	}
 }
 
+func TestResourceDataTimeout(t *testing.T) {
+	d := &ResourceData{
+		diff: &terraform.ResourceDiff{
+			Timeouts: map[string]time.Duration{"create": time.Minute},
+		},
+	}
+
+	if actual := d.Timeout("create"); actual != time.Minute {
+		t.Fatalf("bad: %s", actual)
+	}
+	if actual := d.Timeout("delete"); actual != 0 {
+		t.Fatalf("bad: %s", actual)
+	}
+
+	d = new(ResourceData)
+	if actual := d.Timeout("create"); actual != 0 {
+		t.Fatalf("bad: %s", actual)
+	}
+}
+
 func TestResourceDataState(t *testing.T) {
 	cases := []struct {
 		Schema  map[string]*Schema
@@ -1974,6 +1995,41 @@ func TestResourceDataState(t *testing.T) {
 				},
 			},
 		},
+
+		// Write-only
+		{
+			Schema: map[string]*Schema{
+				"name": &Schema{
+					Type:     TypeString,
+					Optional: true,
+				},
+
+				"password": &Schema{
+					Type:      TypeString,
+					Required:  true,
+					WriteOnly: true,
+				},
+			},
+
+			State: nil,
+
+			Diff: &terraform.ResourceDiff{
+				Attributes: map[string]*terraform.ResourceAttrDiff{
+					"name": &terraform.ResourceAttrDiff{
+						New: "foo",
+					},
+					"password": &terraform.ResourceAttrDiff{
+						New: "hunter2",
+					},
+				},
+			},
+
+			Result: &terraform.ResourceState{
+				Attributes: map[string]string{
+					"name": "foo",
+				},
+			},
+		},
 	}
 
 	for i, tc := range cases {
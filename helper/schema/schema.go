@@ -12,11 +12,14 @@
 package schema
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/hashicorp/terraform/helper/didyoumean"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/mitchellh/mapstructure"
 )
@@ -34,6 +37,17 @@ const (
 	TypeSet
 )
 
+// valueTypeNames are the names of the value types as resource providers
+// describe them to Terraform.
+var valueTypeNames = map[ValueType]string{
+	TypeBool:   "bool",
+	TypeInt:    "int",
+	TypeString: "string",
+	TypeList:   "list",
+	TypeMap:    "map",
+	TypeSet:    "set",
+}
+
 // Schema is used to describe the structure of a value.
 //
 // Read the documentation of the struct elements for important details.
@@ -98,6 +112,35 @@ type Schema struct {
 	//
 	// NOTE: This currently does not work.
 	ComputedWhen []string
+
+	// Deprecated, if set, marks this field as deprecated. Setting the
+	// field in the configuration is still allowed, but results in a
+	// warning with this message, which should say what to use instead.
+	Deprecated string
+
+	// Removed, if set, marks this field as removed. Setting the field in
+	// the configuration is an error with this message, which should say
+	// how to migrate the configuration. Removed fields are never part of
+	// a diff, so a value left in the state from before isn't removed.
+	Removed string
+
+	// WriteOnly, if true, means the value is only sent to the provider
+	// and is never stored in the state, such as for passwords. Since
+	// there is no old value to compare with, it is only part of the diff
+	// when the resource is created. In the schema of a provider, it
+	// means the value is never stored in plans.
+	//
+	// This can only be set for primitive types that aren't Computed.
+	WriteOnly bool
+
+	// EnvVars are environment variables the value is read from, in
+	// order, when it isn't set in the configuration. A Required value
+	// that is set in the environment doesn't have to be configured.
+	// Terraform reads them and passes the value in the configuration.
+	//
+	// This can only be set in the schema of a provider, for primitive
+	// types that aren't Computed.
+	EnvVars []string
 }
 
 // SchemaSetFunc is a function that must return a unique ID for the given
@@ -155,6 +198,42 @@ func (m schemaMap) Data(
 	}, nil
 }
 
+// Attributes returns the schema as the attributes of a resource, sorted
+// by name.
+func (m schemaMap) Attributes() []terraform.ResourceAttribute {
+	keys := make([]string, 0, len(m))
+	for k, _ := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	result := make([]terraform.ResourceAttribute, 0, len(keys))
+	for _, k := range keys {
+		s := m[k]
+		attr := terraform.ResourceAttribute{
+			Name:       k,
+			Type:       valueTypeNames[s.Type],
+			Required:   s.Required,
+			Optional:   s.Optional,
+			Computed:   s.Computed,
+			ForceNew:   s.ForceNew,
+			WriteOnly:  s.WriteOnly,
+			Deprecated: s.Deprecated,
+		}
+
+		switch t := s.Elem.(type) {
+		case *Schema:
+			attr.ElemType = valueTypeNames[t.Type]
+		case *Resource:
+			attr.Attributes = schemaMap(t.Schema).Attributes()
+		}
+
+		result = append(result, attr)
+	}
+
+	return result
+}
+
 // Diff returns the diff for a resource given the schema map,
 // state, and configuration.
 func (m schemaMap) Diff(
@@ -280,6 +359,41 @@ func (m schemaMap) InternalValidate() error {
 			return fmt.Errorf("%s: One of optional, required, or computed must be set", k)
 		}
 
+		if v.WriteOnly {
+			if v.Computed {
+				return fmt.Errorf("%s: WriteOnly can't be set with Computed", k)
+			}
+
+			switch v.Type {
+			case TypeBool, TypeInt, TypeString:
+			default:
+				return fmt.Errorf("%s: WriteOnly can only be set for primitives", k)
+			}
+		}
+
+		if len(v.EnvVars) > 0 {
+			if v.Computed {
+				return fmt.Errorf("%s: EnvVars can't be set with Computed", k)
+			}
+
+			switch v.Type {
+			case TypeBool, TypeInt, TypeString:
+			default:
+				return fmt.Errorf("%s: EnvVars can only be set for primitives", k)
+			}
+		}
+
+		if v.Removed != "" {
+			if v.Required || v.Computed {
+				return fmt.Errorf(
+					"%s: Removed fields can't be Required or Computed", k)
+			}
+			if v.Deprecated != "" {
+				return fmt.Errorf(
+					"%s: Removed and Deprecated can't both be set", k)
+			}
+		}
+
 		if len(v.ComputedWhen) > 0 && !v.Computed {
 			return fmt.Errorf("%s: ComputedWhen can only be set with Computed", k)
 		}
@@ -318,6 +432,10 @@ func (m schemaMap) diff(
 	schema *Schema,
 	diff *terraform.ResourceDiff,
 	d *ResourceData) error {
+	if schema.Removed != "" {
+		return nil
+	}
+
 	var err error
 	switch schema.Type {
 	case TypeBool:
@@ -489,6 +607,12 @@ func (m schemaMap) diffString(
 	schema *Schema,
 	diff *terraform.ResourceDiff,
 	d *ResourceData) error {
+	// Write-only values aren't in the state to compare with, so they are
+	// only diffed when the resource is created.
+	if schema.WriteOnly && d.Id() != "" {
+		return nil
+	}
+
 	var originalN interface{}
 	var os, ns string
 	o, n, _ := d.diffChange(k)
@@ -545,13 +669,24 @@ func (m schemaMap) validate(
 		return nil, nil
 	}
 
+	if schema.Removed != "" {
+		return nil, []error{fmt.Errorf(
+			"%s: removed: %s", k, schema.Removed)}
+	}
+
 	if !schema.Required && !schema.Optional {
 		// This is a computed-only field
 		return nil, []error{fmt.Errorf(
 			"%s: this field cannot be set", k)}
 	}
 
-	return m.validatePrimitive(k, raw, schema, c)
+	ws, es := m.validatePrimitive(k, raw, schema, c)
+	if schema.Deprecated != "" {
+		ws = append(ws, fmt.Sprintf(
+			"%s: deprecated: %s", k, schema.Deprecated))
+	}
+
+	return ws, es
 }
 
 func (m schemaMap) validateList(
@@ -633,8 +768,20 @@ func (m schemaMap) validateObject(
 		}
 
 		if _, ok := schema[configK]; !ok {
-			es = append(es, fmt.Errorf(
-				"%s: invalid or unknown key: %s", k, configK))
+			msg := fmt.Sprintf("invalid or unknown key: %s", configK)
+			if k != "" {
+				msg = fmt.Sprintf("%s: %s", k, msg)
+			}
+
+			keys := make([]string, 0, len(schema))
+			for schemaK, _ := range schema {
+				keys = append(keys, schemaK)
+			}
+			if s := didyoumean.NameSuggestion(configK, keys); s != "" {
+				msg = fmt.Sprintf("%s (did you mean %q?)", msg, s)
+			}
+
+			es = append(es, errors.New(msg))
 		}
 	}
 
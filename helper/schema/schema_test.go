@@ -862,6 +862,86 @@ func TestSchemaMap_Diff(t *testing.T) {
 
 			Err: false,
 		},
+
+		/*
+		 * Write-only
+		 */
+
+		{
+			Schema: map[string]*Schema{
+				"password": &Schema{
+					Type:      TypeString,
+					Required:  true,
+					WriteOnly: true,
+				},
+			},
+
+			State: nil,
+
+			Config: map[string]interface{}{
+				"password": "hunter2",
+			},
+
+			Diff: &terraform.ResourceDiff{
+				Attributes: map[string]*terraform.ResourceAttrDiff{
+					"password": &terraform.ResourceAttrDiff{
+						Old: "",
+						New: "hunter2",
+					},
+				},
+			},
+
+			Err: false,
+		},
+
+		{
+			Schema: map[string]*Schema{
+				"password": &Schema{
+					Type:      TypeString,
+					Required:  true,
+					WriteOnly: true,
+				},
+			},
+
+			State: &terraform.ResourceState{
+				ID: "foo",
+			},
+
+			Config: map[string]interface{}{
+				"password": "hunter2",
+			},
+
+			Diff: nil,
+
+			Err: false,
+		},
+
+		/*
+		 * Removed
+		 */
+
+		{
+			Schema: map[string]*Schema{
+				"availability_zone": &Schema{
+					Type:     TypeString,
+					Optional: true,
+					Removed:  "use availability_zones instead",
+				},
+			},
+
+			State: &terraform.ResourceState{
+				ID: "foo",
+				Attributes: map[string]string{
+					"availability_zone": "foo",
+				},
+			},
+
+			Config: map[string]interface{}{},
+
+			Diff: nil,
+
+			Err: false,
+		},
 	}
 
 	for i, tc := range cases {
@@ -914,6 +994,31 @@ func TestSchemaMap_InternalValidate(t *testing.T) {
 			true,
 		},
 
+		// Removed and Required
+		{
+			map[string]*Schema{
+				"foo": &Schema{
+					Type:     TypeInt,
+					Required: true,
+					Removed:  "foo",
+				},
+			},
+			true,
+		},
+
+		// Removed and Deprecated
+		{
+			map[string]*Schema{
+				"foo": &Schema{
+					Type:       TypeInt,
+					Optional:   true,
+					Deprecated: "foo",
+					Removed:    "foo",
+				},
+			},
+			true,
+		},
+
 		// Missing Type
 		{
 			map[string]*Schema{
@@ -1009,6 +1114,57 @@ func TestSchemaMap_InternalValidate(t *testing.T) {
 			true,
 		},
 
+		// Write-only and computed
+		{
+			map[string]*Schema{
+				"foo": &Schema{
+					Type:      TypeString,
+					Optional:  true,
+					Computed:  true,
+					WriteOnly: true,
+				},
+			},
+			true,
+		},
+
+		// Write-only list
+		{
+			map[string]*Schema{
+				"foo": &Schema{
+					Type:      TypeList,
+					Optional:  true,
+					Elem:      &Schema{Type: TypeInt},
+					WriteOnly: true,
+				},
+			},
+			true,
+		},
+
+		// Environment variables of a computed value
+		{
+			map[string]*Schema{
+				"foo": &Schema{
+					Type:     TypeString,
+					Optional: true,
+					Computed: true,
+					EnvVars:  []string{"FOO"},
+				},
+			},
+			true,
+		},
+
+		// Environment variables of a map
+		{
+			map[string]*Schema{
+				"foo": &Schema{
+					Type:     TypeMap,
+					Optional: true,
+					EnvVars:  []string{"FOO"},
+				},
+			},
+			true,
+		},
+
 		// Sub-resource invalid
 		{
 			map[string]*Schema{
@@ -1237,6 +1393,66 @@ func TestSchemaMap_Validate(t *testing.T) {
 
 			Err: true,
 		},
+
+		// Deprecated field set
+		{
+			Schema: map[string]*Schema{
+				"availability_zone": &Schema{
+					Type:       TypeString,
+					Optional:   true,
+					Deprecated: "use availability_zones instead",
+				},
+			},
+
+			Config: map[string]interface{}{
+				"availability_zone": "bar",
+			},
+
+			Warn: true,
+		},
+
+		// Deprecated field not set
+		{
+			Schema: map[string]*Schema{
+				"availability_zone": &Schema{
+					Type:       TypeString,
+					Optional:   true,
+					Deprecated: "use availability_zones instead",
+				},
+			},
+
+			Config: map[string]interface{}{},
+		},
+
+		// Removed field set
+		{
+			Schema: map[string]*Schema{
+				"availability_zone": &Schema{
+					Type:     TypeString,
+					Optional: true,
+					Removed:  "use availability_zones instead",
+				},
+			},
+
+			Config: map[string]interface{}{
+				"availability_zone": "bar",
+			},
+
+			Err: true,
+		},
+
+		// Removed field not set
+		{
+			Schema: map[string]*Schema{
+				"availability_zone": &Schema{
+					Type:     TypeString,
+					Optional: true,
+					Removed:  "use availability_zones instead",
+				},
+			},
+
+			Config: map[string]interface{}{},
+		},
 	}
 
 	for i, tc := range cases {
@@ -1263,3 +1479,29 @@ func TestSchemaMap_Validate(t *testing.T) {
 		}
 	}
 }
+
+func TestSchemaMap_Validate_didYouMean(t *testing.T) {
+	schema := map[string]*Schema{
+		"instance_type": &Schema{
+			Type:     TypeString,
+			Optional: true,
+		},
+	}
+
+	c, err := config.NewRawConfig(map[string]interface{}{
+		"instnce_type": "m1.small",
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	_, es := schemaMap(schema).Validate(terraform.NewResourceConfig(c))
+	if len(es) != 1 {
+		t.Fatalf("bad: %#v", es)
+	}
+
+	expected := `invalid or unknown key: instnce_type (did you mean "instance_type"?)`
+	if es[0].Error() != expected {
+		t.Fatalf("bad: %s", es[0])
+	}
+}
@@ -86,6 +86,24 @@ func ParseSSHConfig(s *terraform.ResourceState) (*SSHConfig, error) {
 	return sshConf, nil
 }
 
+// Target returns where the connection described by the ConnInfo of the
+// resource goes, as "user@host:port", without connecting. The host is
+// "<computed>" if it isn't known yet, which is the case when planning
+// resources that haven't been created.
+func Target(s *terraform.ResourceState) (string, error) {
+	conf, err := ParseSSHConfig(s)
+	if err != nil {
+		return "", err
+	}
+
+	host := conf.Host
+	if host == "" {
+		host = "<computed>"
+	}
+
+	return fmt.Sprintf("%s@%s:%d", conf.User, host, conf.Port), nil
+}
+
 // safeDuration returns either the parsed duration or a default value
 func safeDuration(dur string, defaultDur time.Duration) time.Duration {
 	d, err := time.ParseDuration(dur)
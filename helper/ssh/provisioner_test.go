@@ -61,3 +61,29 @@ func TestResourceProvider_sshConfig(t *testing.T) {
 		t.Fatalf("bad: %v", conf)
 	}
 }
+
+func TestTarget(t *testing.T) {
+	r := &terraform.ResourceState{
+		ConnInfo: map[string]string{
+			"user": "ubuntu",
+			"host": "127.0.0.1",
+			"port": "2222",
+		},
+	}
+
+	actual, err := Target(r)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if actual != "ubuntu@127.0.0.1:2222" {
+		t.Fatalf("bad: %s", actual)
+	}
+
+	actual, err = Target(&terraform.ResourceState{})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if actual != "root@<computed>:22" {
+		t.Fatalf("bad: %s", actual)
+	}
+}
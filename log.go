@@ -1,15 +1,29 @@
 package main
 
 import (
+	"bytes"
 	"io"
 	"os"
+	"strings"
+	"sync"
 )
 
 // These are the environmental variables that determine if we log, and if
 // we log whether or not the log should go to a file.
+//
+// TF_LOG can be set to one of the levels in validLevels to only log
+// messages at that level or above. Any other value logs everything.
 const EnvLog = "TF_LOG"
 const EnvLogFile = "TF_LOG_PATH"
 
+// validLevels are the log levels, from the most to the least verbose.
+var validLevels = []string{"TRACE", "DEBUG", "INFO", "WARN", "ERROR"}
+
+// levelAliases are other names used for levels in log messages.
+var levelAliases = map[string]string{
+	"ERR": "ERROR",
+}
+
 // logOutput determines where we should send logs (if anywhere).
 func logOutput() (logOutput io.Writer, err error) {
 	logOutput = nil
@@ -23,7 +37,98 @@ func logOutput() (logOutput io.Writer, err error) {
 				return nil, err
 			}
 		}
+
+		logOutput = &levelFilter{
+			Level:  logLevel(),
+			Writer: logOutput,
+		}
 	}
 
 	return
 }
+
+// logLevel returns the minimum level to log, from TF_LOG.
+func logLevel() string {
+	v := strings.ToUpper(strings.TrimSpace(os.Getenv(EnvLog)))
+	for _, l := range validLevels {
+		if v == l {
+			return l
+		}
+	}
+
+	return validLevels[0]
+}
+
+// levelFilter is an io.Writer that only writes the lines that are at or
+// above a log level. The level of a line is the first "[LEVEL]" in it.
+// Lines without a level are always written.
+//
+// Writes don't need to be whole lines, since output from the child
+// process is copied in arbitrary chunks. Partial lines are held until the
+// rest of the line is written.
+type levelFilter struct {
+	Level  string
+	Writer io.Writer
+
+	buf bytes.Buffer
+	l   sync.Mutex
+}
+
+func (f *levelFilter) Write(p []byte) (int, error) {
+	f.l.Lock()
+	defer f.l.Unlock()
+
+	f.buf.Write(p)
+	for {
+		idx := bytes.IndexByte(f.buf.Bytes(), '\n')
+		if idx < 0 {
+			break
+		}
+
+		line := f.buf.Next(idx + 1)
+		if !f.check(line) {
+			continue
+		}
+
+		if _, err := f.Writer.Write(line); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// check returns true if the line should be written.
+func (f *levelFilter) check(line []byte) bool {
+	x := bytes.IndexByte(line, '[')
+	if x < 0 {
+		return true
+	}
+	y := bytes.IndexByte(line[x:], ']')
+	if y < 0 {
+		return true
+	}
+
+	level := string(line[x+1 : x+y])
+	if alias, ok := levelAliases[level]; ok {
+		level = alias
+	}
+
+	min := -1
+	actual := -1
+	for i, l := range validLevels {
+		if l == f.Level {
+			min = i
+		}
+		if l == level {
+			actual = i
+		}
+	}
+
+	// Brackets that aren't a level, like "[reset]", don't filter.
+	if actual < 0 {
+		return true
+	}
+
+	return actual >= min
+}
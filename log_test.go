@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestLevelFilter(t *testing.T) {
+	buf := new(bytes.Buffer)
+	f := &levelFilter{Level: "INFO", Writer: buf}
+
+	f.Write([]byte("2014/09/01 [TRACE] trace\n"))
+	f.Write([]byte("2014/09/01 [DEBUG] debug\n"))
+	f.Write([]byte("2014/09/01 [INFO] info\n"))
+	f.Write([]byte("2014/09/01 [ERR] err\n"))
+	f.Write([]byte("no level\n"))
+	f.Write([]byte("[reset] not a level\n"))
+
+	expected := "2014/09/01 [INFO] info\n" +
+		"2014/09/01 [ERR] err\n" +
+		"no level\n" +
+		"[reset] not a level\n"
+	if buf.String() != expected {
+		t.Fatalf("bad: %q", buf.String())
+	}
+}
+
+func TestLevelFilter_partial(t *testing.T) {
+	buf := new(bytes.Buffer)
+	f := &levelFilter{Level: "WARN", Writer: buf}
+
+	f.Write([]byte("[DEBUG] fo"))
+	f.Write([]byte("o\n[WA"))
+	if buf.String() != "" {
+		t.Fatalf("bad: %q", buf.String())
+	}
+
+	f.Write([]byte("RN] bar\n[WARN] baz"))
+	if buf.String() != "[WARN] bar\n" {
+		t.Fatalf("bad: %q", buf.String())
+	}
+}
+
+func TestLogLevel(t *testing.T) {
+	old := os.Getenv(EnvLog)
+	defer os.Setenv(EnvLog, old)
+
+	cases := map[string]string{
+		"1":     "TRACE",
+		"trace": "TRACE",
+		"debug": "DEBUG",
+		"WARN":  "WARN",
+		"ERROR": "ERROR",
+	}
+	for input, expected := range cases {
+		os.Setenv(EnvLog, input)
+		if actual := logLevel(); actual != expected {
+			t.Fatalf("bad: %s %s", input, actual)
+		}
+	}
+}
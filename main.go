@@ -1,13 +1,19 @@
 package main
 
 import (
+	"crypto/ecdsa"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
+	"github.com/hashicorp/terraform/command"
 	"github.com/hashicorp/terraform/plugin"
+	"github.com/hashicorp/terraform/state"
 	"github.com/mitchellh/cli"
 	"github.com/mitchellh/panicwrap"
 	"github.com/mitchellh/prefixedio"
@@ -18,6 +24,12 @@ func main() {
 }
 
 func realMain() int {
+	// If the shell is running us to complete a command line, do only
+	// that. This has to be quick and quiet, so it skips everything else.
+	if os.Getenv(EnvCompLine) != "" {
+		return autocompleteMain()
+	}
+
 	var wrapConfig panicwrap.WrapConfig
 
 	if !panicwrap.Wrapped(&wrapConfig) {
@@ -45,6 +57,13 @@ func realMain() int {
 		os.Setenv(EnvLog, "")
 		os.Setenv(EnvLogFile, "")
 
+		// The wrapped process writes its output through us, so it can't
+		// tell whether the output is going to a terminal that supports
+		// colors. If it isn't, disable colors.
+		if !isTerminal(os.Stdout) || !consoleColor(os.Stdout) {
+			os.Setenv(EnvNoColor, "1")
+		}
+
 		// Setup the prefixed readers that send data properly to
 		// stdout/stderr.
 		outR, outW := io.Pipe()
@@ -86,6 +105,7 @@ func wrappedMain() int {
 	// Initialize the TFConfig settings for the commands...
 	ContextOpts.Providers = config.ProviderFactories()
 	ContextOpts.Provisioners = config.ProvisionerFactories()
+	ContextOpts.Secrets = SecretBackends()
 
 	// Get the command line args. We shortcut "--version" and "-v" to
 	// just show the version.
@@ -100,11 +120,70 @@ func wrappedMain() int {
 		}
 	}
 
-	cli := &cli.CLI{
-		Args:       args,
-		Commands:   Commands,
-		HelpFunc:   cli.BasicHelpFunc("terraform"),
-		HelpWriter: os.Stdout,
+	// A -no-color flag given before the command applies to the command.
+	// Commands remove the flag from wherever it is in their arguments.
+	for i, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			break
+		}
+
+		if arg == "-no-color" {
+			args = append(args[:i], args[i+1:]...)
+			args = append(args, "-no-color")
+			break
+		}
+	}
+
+	// Installing shell completion isn't a command, since it is about
+	// Terraform itself and not infrastructure.
+	if len(args) > 0 {
+		switch args[0] {
+		case "-autocomplete-install", "-autocomplete-uninstall":
+			uninstall := args[0] == "-autocomplete-uninstall"
+			if err := autocompleteInstall(uninstall); err != nil {
+				fmt.Fprintf(os.Stderr, "Error installing completion: %s\n", err)
+				return 1
+			}
+
+			return 0
+		}
+	}
+
+	// A -chdir flag before the command sets the directory the command
+	// works in. Rather than changing the working directory of the process,
+	// the directory is given to the commands, which resolve relative paths
+	// against it.
+	var workingDir string
+	for i, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			break
+		}
+
+		if strings.HasPrefix(arg, "-chdir=") {
+			dir := strings.TrimPrefix(arg, "-chdir=")
+			if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+				fmt.Fprintf(os.Stderr, "Error: -chdir directory %q does not exist\n", dir)
+				return 1
+			}
+
+			args = append(args[:i], args[i+1:]...)
+			workingDir = dir
+			pluginHostFile = filepath.Join(dir, pluginHostFile)
+			break
+		}
+	}
+
+	// A -read-only flag before the command runs it in read-only mode.
+	for i, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			break
+		}
+
+		if arg == "-read-only" {
+			args = append(args[:i], args[i+1:]...)
+			ReadOnly = true
+			break
+		}
 	}
 
 	// Load the configuration file if we have one, that can be used to
@@ -128,6 +207,115 @@ func wrappedMain() int {
 	// Initialize the TFConfig settings for the commands...
 	ContextOpts.Providers = config.ProviderFactories()
 	ContextOpts.Provisioners = config.ProvisionerFactories()
+	ResolveProvider = config.ResolveProvider
+	AuditLog.Sink = config.AuditLog
+	PushAddress = config.PushAddress
+	UpdateAddress = config.UpdateAddress
+	UpdateKey = config.UpdateKey
+	for _, w := range config.Webhooks {
+		Webhooks = append(Webhooks, &command.Webhook{
+			URL:    w.URL,
+			Format: w.Format,
+		})
+	}
+
+	// Hooks are run in the order of their names
+	hookNames := make([]string, 0, len(config.Hooks))
+	for n, _ := range config.Hooks {
+		hookNames = append(hookNames, n)
+	}
+	sort.Strings(hookNames)
+	for _, n := range hookNames {
+		h := config.Hooks[n]
+		ExecHooks = append(ExecHooks, &command.ExecHook{
+			Name:    n,
+			Command: h.Command,
+			Events:  h.Events,
+		})
+	}
+
+	// Events are sent to the sinks in the order of their names
+	sinkNames := make([]string, 0, len(config.EventSinks))
+	for n, _ := range config.EventSinks {
+		sinkNames = append(sinkNames, n)
+	}
+	sort.Strings(sinkNames)
+	for _, n := range sinkNames {
+		sink, err := config.EventSinks[n].Sink()
+		if err != nil {
+			fmt.Fprintf(os.Stderr,
+				"Error loading CLI configuration: \n\nevent_sink %s: %s\n", n, err)
+			return 1
+		}
+
+		Events.Sinks = append(Events.Sinks, sink)
+	}
+
+	// Policies are run in the order of their names
+	policyNames := make([]string, 0, len(config.Policies))
+	for n, _ := range config.Policies {
+		policyNames = append(policyNames, n)
+	}
+	sort.Strings(policyNames)
+	for _, n := range policyNames {
+		p := config.Policies[n]
+		if p.Command != "" {
+			Policies = append(Policies, &command.Policy{
+				Name:    n,
+				Command: p.Command,
+			})
+		}
+
+		// The configuration was validated, so the rule is valid
+		if rule, _ := p.Rule(n); rule != nil {
+			Rules = append(Rules, rule)
+		}
+	}
+
+	if len(config.Approvers) > 0 {
+		Approvers = make(map[string]*ecdsa.PublicKey)
+		for n, a := range config.Approvers {
+			key, err := command.LoadApprovalKey(a.PublicKey)
+			if err != nil {
+				fmt.Fprintf(os.Stderr,
+					"Error loading CLI configuration: \n\napprover %s: %s\n", n, err)
+				return 1
+			}
+
+			Approvers[n] = key
+		}
+	}
+
+	// An external estimator takes precedence over the built-in prices
+	if config.CostEstimator != "" {
+		CostEstimators = append([]command.CostEstimator{
+			&command.ExecCostEstimator{Command: config.CostEstimator},
+		}, CostEstimators...)
+	}
+	CostBudget = float64(config.CostBudget)
+	MaxDestroy = config.MaxDestroy
+	MaxDestroyPercent = float64(config.MaxDestroyPercent)
+	if config.StateKeepLast > 0 || config.StateKeepDailyDays > 0 {
+		StateRetention = &state.Retention{
+			KeepLast:      config.StateKeepLast,
+			KeepDailyDays: config.StateKeepDailyDays,
+		}
+	}
+
+	if config.ReadOnly {
+		ReadOnly = true
+	}
+
+	// The commands are set up again now that the working directory and
+	// read-only mode are known.
+	initCommands(workingDir)
+
+	cli := &cli.CLI{
+		Args:       args,
+		Commands:   Commands,
+		HelpFunc:   cli.BasicHelpFunc("terraform"),
+		HelpWriter: os.Stdout,
+	}
 
 	exitCode, err := cli.Run()
 	if err != nil {
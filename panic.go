@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"runtime"
+	"sort"
 	"strings"
 
+	"github.com/hashicorp/terraform/config"
 	"github.com/mitchellh/panicwrap"
 )
 
@@ -19,11 +22,21 @@ A crash log has been placed at "crash.log" relative to your current
 working directory. It would be immensely helpful if you could please
 report the crash with Terraform[1] so that we can fix this.
 
+When reporting bugs, please include your terraform version, the crash
+log, and a description of what you were doing when it happened. The
+summary of your configuration at the top of the crash log only has the
+names of resources and providers, but the log below it can include
+values that providers logged, so please check it before sharing it.
+
 [1]: https://github.com/hashicorp/terraform/issues
 
 !!!!!!!!!!!!!!!!!!!!!!!!!!! TERRAFORM CRASH !!!!!!!!!!!!!!!!!!!!!!!!!!!!
 `
 
+// crashLogMaxBytes is the most of the end of the log that is put in the
+// crash log. The end of the log is what led up to the crash.
+const crashLogMaxBytes = 1 << 20
+
 // panicHandler is what is called by panicwrap when a panic is encountered
 // within Terraform. It is guaranteed to run after the resulting process has
 // exited so we can take the log file, add in the panic, and store it
@@ -42,15 +55,7 @@ func panicHandler(logF *os.File) panicwrap.HandlerFunc {
 		}
 		defer f.Close()
 
-		// Seek the log file back to the beginning
-		if _, err = logF.Seek(0, 0); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to seek log file for crash: %s", err)
-			return
-		}
-
-		// Copy the contents to the crash file. This will include
-		// the panic that just happened.
-		if _, err = io.Copy(f, logF); err != nil {
+		if err := writeCrashLog(f, m, os.Args[1:], logF); err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to write crash log: %s", err)
 			return
 		}
@@ -61,3 +66,113 @@ func panicHandler(logF *os.File) panicwrap.HandlerFunc {
 		fmt.Println(strings.TrimSpace(panicOutput))
 	}
 }
+
+// writeCrashLog writes the report of a crash to w: information about
+// the environment, the panic itself, and the end of the log.
+func writeCrashLog(w io.Writer, m string, args []string, logF io.ReadSeeker) error {
+	version := Version
+	if VersionPrerelease != "" {
+		version += "-" + VersionPrerelease
+	}
+	if GitCommit != "" {
+		version += fmt.Sprintf(" (%s)", GitCommit)
+	}
+
+	fmt.Fprintf(w, "Terraform Version: %s\n", version)
+	fmt.Fprintf(w, "Go Version: %s\n", runtime.Version())
+	fmt.Fprintf(w, "OS/Arch: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(w, "Command: terraform %s\n\n",
+		strings.Join(crashArgs(args), " "))
+
+	fmt.Fprintf(w, "Configuration:\n\n%s\n", crashConfig(args))
+	fmt.Fprintf(w, "Panic:\n\n%s\n\n", strings.TrimSpace(m))
+
+	// Seek to the part of the log we want. The log will include the
+	// panic that just happened.
+	size, err := logF.Seek(0, 2)
+	if err != nil {
+		return err
+	}
+	offset := int64(0)
+	if size > crashLogMaxBytes {
+		offset = size - crashLogMaxBytes
+	}
+	if _, err := logF.Seek(offset, 0); err != nil {
+		return err
+	}
+
+	if offset > 0 {
+		fmt.Fprintf(w, "Log (last %d bytes):\n\n", crashLogMaxBytes)
+	} else {
+		fmt.Fprintf(w, "Log:\n\n")
+	}
+
+	_, err = io.Copy(w, logF)
+	return err
+}
+
+// crashArgs returns the arguments with the values of variables removed,
+// since they're often secrets.
+func crashArgs(args []string) []string {
+	result := make([]string, len(args))
+	copy(result, args)
+
+	for i, arg := range result {
+		switch {
+		case arg == "-var" && i+1 < len(result):
+			result[i+1] = "<redacted>"
+		case strings.HasPrefix(arg, "-var="):
+			result[i] = "-var=<redacted>"
+		}
+	}
+
+	return result
+}
+
+// crashConfig summarizes the configuration in the working directory
+// by the names of things in it, without any of their settings.
+func crashConfig(args []string) string {
+	dir := "."
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			break
+		}
+		if strings.HasPrefix(arg, "-chdir=") {
+			dir = strings.TrimPrefix(arg, "-chdir=")
+		}
+	}
+
+	c, err := config.LoadDir(dir)
+	if err != nil {
+		return fmt.Sprintf("  Error loading configuration: %s\n", err)
+	}
+
+	var providers, resources, variables []string
+	for _, p := range c.ProviderConfigs {
+		providers = append(providers, p.Name)
+	}
+	for _, r := range c.Resources {
+		resources = append(resources, fmt.Sprintf("%s (count: %d)", r.Id(), r.Count))
+	}
+	for _, v := range c.Variables {
+		variables = append(variables, v.Name)
+	}
+
+	var result string
+	for _, s := range []struct {
+		Name   string
+		Values []string
+	}{
+		{"Providers", providers},
+		{"Resources", resources},
+		{"Variables", variables},
+	} {
+		sort.Strings(s.Values)
+		result += fmt.Sprintf("  %s:\n", s.Name)
+		for _, v := range s.Values {
+			result += fmt.Sprintf("    %s\n", v)
+		}
+	}
+
+	return result
+}
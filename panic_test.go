@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCrashArgs(t *testing.T) {
+	args := []string{
+		"apply",
+		"-var", "foo=bar",
+		"-var=baz=qux",
+		"-state=foo.tfstate",
+	}
+	expected := []string{
+		"apply",
+		"-var", "<redacted>",
+		"-var=<redacted>",
+		"-state=foo.tfstate",
+	}
+
+	if actual := crashArgs(args); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+	if args[2] != "foo=bar" {
+		t.Fatal("args should not be modified")
+	}
+}
+
+func TestWriteCrashLog(t *testing.T) {
+	td, err := ioutil.TempDir("", "tf")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(td)
+
+	err = ioutil.WriteFile(filepath.Join(td, "main.tf"), []byte(`
+variable "secret" {
+    default = "hunter2"
+}
+
+resource "aws_instance" "web" {
+    ami = "${var.secret}"
+}
+`), 0644)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	logF := bytes.NewReader([]byte("[DEBUG] something happened\n"))
+	args := []string{"-chdir=" + td, "plan", "-var", "secret=hunter2"}
+
+	buf := new(bytes.Buffer)
+	if err := writeCrashLog(buf, "panic: boom", args, logF); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := buf.String()
+	for _, s := range []string{
+		"Terraform Version: " + Version,
+		"-var <redacted>",
+		"aws_instance.web (count: 1)",
+		"    secret\n",
+		"panic: boom",
+		"[DEBUG] something happened",
+	} {
+		if !strings.Contains(actual, s) {
+			t.Fatalf("missing %q:\n\n%s", s, actual)
+		}
+	}
+
+	if strings.Contains(actual, "hunter2") {
+		t.Fatalf("secret in crash log:\n\n%s", actual)
+	}
+}
@@ -2,6 +2,8 @@ package plugin
 
 import (
 	"bufio"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -36,6 +38,7 @@ type Client struct {
 	l           sync.Mutex
 	address     net.Addr
 	service     string
+	token       string
 	client      *rpc.Client
 }
 
@@ -65,6 +68,28 @@ type ClientConfig struct {
 	// If non-nil, then the stderr of the client will be written to here
 	// (as well as the log).
 	Stderr io.Writer
+
+	// PersistTimeout, if non-zero, leaves the plugin process running when
+	// the client is killed so that later clients can reattach to it. The
+	// plugin exits on its own once it has had no connections for this
+	// long.
+	PersistTimeout time.Duration
+
+	// Reattach, if non-nil, connects to an already running plugin
+	// instead of starting Cmd.
+	Reattach *ReattachConfig
+}
+
+// ReattachConfig is the information needed to connect to a plugin
+// process that is already running.
+type ReattachConfig struct {
+	Network string
+	Address string
+	Service string
+
+	// Token is the secret that connections to the plugin start with. It
+	// is empty for plugins from before authentication existed.
+	Token string
 }
 
 // This makes sure all the managed subprocesses are killed and properly
@@ -88,7 +113,7 @@ func CleanupClients() {
 		}(client)
 	}
 
-	log.Println("waiting for all plugin processes to complete...")
+	log.Println("[DEBUG] waiting for all plugin processes to complete...")
 	wg.Wait()
 }
 
@@ -147,6 +172,14 @@ func (c *Client) Client() (*rpc.Client, error) {
 		tcpConn.SetKeepAlive(true)
 	}
 
+	// Authenticate before anything else is sent
+	if c.token != "" {
+		if _, err := io.WriteString(conn, c.token+"\n"); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
 	c.client = rpc.NewClient(conn)
 	return c.client, nil
 }
@@ -165,6 +198,19 @@ func (c *Client) Exited() bool {
 //
 // This method can safely be called multiple times.
 func (c *Client) Kill() {
+	// If the plugin is meant to outlive us, just disconnect from it.
+	if c.config.Reattach != nil || c.config.PersistTimeout > 0 {
+		c.l.Lock()
+		defer c.l.Unlock()
+
+		if c.client != nil {
+			c.client.Close()
+			c.client = nil
+		}
+
+		return
+	}
+
 	cmd := c.config.Cmd
 
 	if cmd.Process == nil {
@@ -177,6 +223,22 @@ func (c *Client) Kill() {
 	<-c.doneLogging
 }
 
+// ReattachConfig returns the information needed for a later client
+// to reattach to this plugin, if it was started with a PersistTimeout.
+func (c *Client) ReattachConfig() (*ReattachConfig, error) {
+	addr, err := c.Start()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReattachConfig{
+		Network: addr.Network(),
+		Address: addr.String(),
+		Service: c.service,
+		Token:   c.token,
+	}, nil
+}
+
 // Service returns the name of the service to use.
 func (c *Client) Service() (string, error) {
 	if _, err := c.Start(); err != nil {
@@ -200,13 +262,44 @@ func (c *Client) Start() (addr net.Addr, err error) {
 		return c.address, nil
 	}
 
+	// If we're reattaching, there is no process to start
+	if r := c.config.Reattach; r != nil {
+		switch r.Network {
+		case "tcp":
+			addr, err = net.ResolveTCPAddr("tcp", r.Address)
+		case "unix":
+			addr, err = net.ResolveUnixAddr("unix", r.Address)
+		default:
+			err = fmt.Errorf("Unknown address type: %s", r.Network)
+		}
+		if err != nil {
+			return
+		}
+
+		c.address = addr
+		c.service = r.Service
+		c.token = r.Token
+		return
+	}
+
+	// Generate the secret that connections to the plugin start with
+	token, err := authToken()
+	if err != nil {
+		return
+	}
+
 	c.doneLogging = make(chan struct{})
 
 	env := []string{
 		fmt.Sprintf("%s=%s", MagicCookieKey, MagicCookieValue),
+		fmt.Sprintf("%s=%s", AuthTokenKey, token),
 		fmt.Sprintf("TF_PLUGIN_MIN_PORT=%d", c.config.MinPort),
 		fmt.Sprintf("TF_PLUGIN_MAX_PORT=%d", c.config.MaxPort),
 	}
+	if c.config.PersistTimeout > 0 {
+		env = append(env, fmt.Sprintf(
+			"%s=%s", PersistTimeoutKey, c.config.PersistTimeout))
+	}
 
 	stdout_r, stdout_w := io.Pipe()
 	stderr_r, stderr_w := io.Pipe()
@@ -306,7 +399,7 @@ func (c *Client) Start() (addr net.Addr, err error) {
 		// Trim the line and split by "|" in order to get the parts of
 		// the output.
 		line := strings.TrimSpace(string(lineBytes))
-		parts := strings.SplitN(line, "|", 4)
+		parts := strings.SplitN(line, "|", 5)
 		if len(parts) < 4 {
 			err = fmt.Errorf("Unrecognized remote plugin message: %s", line)
 			return
@@ -330,12 +423,33 @@ func (c *Client) Start() (addr net.Addr, err error) {
 
 		// Grab the services
 		c.service = parts[3]
+
+		// Plugins that support authentication say so. Older plugins
+		// ignore the token, and must not be sent it.
+		if len(parts) > 4 && parts[4] == "auth" {
+			c.token = token
+		} else {
+			log.Printf(
+				"[WARN] %s: plugin doesn't support authentication. "+
+					"Rebuild it with this version of Terraform.", cmd.Path)
+		}
 	}
 
 	c.address = addr
 	return
 }
 
+// authToken returns a new random secret for authenticating connections
+// to a plugin.
+func authToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
+
 func (c *Client) logStderr(r io.Reader) {
 	bufR := bufio.NewReader(r)
 	for {
@@ -3,10 +3,14 @@ package plugin
 import (
 	"bytes"
 	"io/ioutil"
+	"net"
+	"net/rpc"
 	"os"
 	"strings"
 	"testing"
 	"time"
+
+	tfrpc "github.com/hashicorp/terraform/rpc"
 )
 
 func TestClient(t *testing.T) {
@@ -151,3 +155,126 @@ func TestClient_Stdin(t *testing.T) {
 		t.Fatal("process didn't exit cleanly")
 	}
 }
+
+func TestClient_auth(t *testing.T) {
+	// The plugin is persistent so that it accepts more connections
+	process := helperProcess("resource-provider")
+	c := NewClient(&ClientConfig{
+		Cmd:            process,
+		PersistTimeout: 250 * time.Millisecond,
+	})
+	defer func() { process.Process.Kill() }()
+
+	if _, err := c.Client(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	reattach, err := c.ReattachConfig()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if reattach.Token == "" {
+		t.Fatal("should have a token")
+	}
+
+	// Connecting without the token doesn't work
+	conn, err := net.Dial(reattach.Network, reattach.Address)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	client := rpc.NewClient(conn)
+	defer client.Close()
+
+	provider := &tfrpc.ResourceProvider{
+		Client: client,
+		Name:   reattach.Service,
+	}
+	if _, err := provider.Diff(nil, nil); err == nil {
+		t.Fatal("should error")
+	}
+}
+
+func TestClient_authLegacy(t *testing.T) {
+	c := NewClient(&ClientConfig{Cmd: helperProcess("mock")})
+	defer c.Kill()
+
+	reattach, err := c.ReattachConfig()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if reattach.Token != "" {
+		t.Fatalf("bad: %#v", reattach)
+	}
+}
+
+func TestClient_persist(t *testing.T) {
+	process := helperProcess("resource-provider")
+	c := NewClient(&ClientConfig{
+		Cmd:            process,
+		PersistTimeout: 250 * time.Millisecond,
+	})
+
+	if _, err := c.Client(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	reattach, err := c.ReattachConfig()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if reattach.Service == "" {
+		t.Fatalf("bad: %#v", reattach)
+	}
+
+	// Killing a persistent client only disconnects from it
+	c.Kill()
+	if c.Exited() {
+		t.Fatal("should not have exited")
+	}
+
+	// Reattach and use the plugin
+	c2 := NewClient(&ClientConfig{Reattach: reattach})
+	client, err := c2.Client()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	provider := &tfrpc.ResourceProvider{
+		Client: client,
+		Name:   reattach.Service,
+	}
+	if _, err := provider.Diff(nil, nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	c2.Kill()
+
+	// Once idle, the plugin should exit on its own
+	for i := 0; i < 50 && !c.Exited(); i++ {
+		time.Sleep(100 * time.Millisecond)
+	}
+	if !c.Exited() {
+		process.Process.Kill()
+		t.Fatal("should have exited after being idle")
+	}
+}
+
+func TestClient_persistSingle(t *testing.T) {
+	// Without a factory, the plugin can't give each connection its own
+	// provider, so it exits after the first one.
+	process := helperProcess("resource-provider-single")
+	c := NewClient(&ClientConfig{
+		Cmd:            process,
+		PersistTimeout: 1 * time.Minute,
+	})
+
+	if _, err := c.Client(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	c.Kill()
+
+	for i := 0; i < 50 && !c.Exited(); i++ {
+		time.Sleep(100 * time.Millisecond)
+	}
+	if !c.Exited() {
+		process.Process.Kill()
+		t.Fatal("should have exited after the connection")
+	}
+}
@@ -55,6 +55,14 @@ func TestHelperProcess(*testing.T) {
 		fmt.Printf("%s1|tcp|:1234|foo\n", APIVersion)
 		<-make(chan int)
 	case "resource-provider":
+		err := ServeFactory(func() interface{} {
+			return new(terraform.MockResourceProvider)
+		})
+		if err != nil {
+			log.Printf("[ERR] %s", err)
+			os.Exit(1)
+		}
+	case "resource-provider-single":
 		err := Serve(new(terraform.MockResourceProvider))
 		if err != nil {
 			log.Printf("[ERR] %s", err)
@@ -1,8 +1,10 @@
 package plugin
 
 import (
+	"crypto/subtle"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"net"
@@ -11,7 +13,9 @@ import (
 	"os/signal"
 	"runtime"
 	"strconv"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	tfrpc "github.com/hashicorp/terraform/rpc"
 )
@@ -27,7 +31,37 @@ const APIVersion = "1"
 const MagicCookieKey = "TF_PLUGIN_MAGIC_COOKIE"
 const MagicCookieValue = "d602bf8f470bc67ca7faa0386276bbdd4330efaf76d1a219cb4d6991ca9872b2"
 
+// PersistTimeoutKey is the environmental variable that, if set to a
+// duration, makes the plugin keep serving connections after the first
+// one closes, until it has been idle for that duration.
+const PersistTimeoutKey = "TF_PLUGIN_PERSIST_TIMEOUT"
+
+// AuthTokenKey is the environmental variable that the client uses to
+// give the plugin a secret generated for it. Every connection must start
+// with the secret, so that other local users can't connect to the plugin
+// and use the credentials it was configured with.
+const AuthTokenKey = "TF_PLUGIN_AUTH_TOKEN"
+
+// authTimeout is how long a new connection has to send the auth token.
+const authTimeout = 10 * time.Second
+
+// Serve serves the given provider or provisioner to Terraform. A plugin
+// served this way can't be kept running between runs of Terraform, since
+// the runs would share the one provider, so it always serves a single
+// connection.
 func Serve(svc interface{}) error {
+	return serve(func() interface{} { return svc }, false)
+}
+
+// ServeFactory is like Serve, but the provider or provisioner is created
+// by the given function. If the plugin is kept running between runs of
+// Terraform, every connection gets a new one, so that one run can't
+// reconfigure or stop the provider of another.
+func ServeFactory(f func() interface{}) error {
+	return serve(f, true)
+}
+
+func serve(f func() interface{}, persist bool) error {
 	// First check the cookie
 	if os.Getenv(MagicCookieKey) != MagicCookieValue {
 		fmt.Fprintf(os.Stderr,
@@ -37,11 +71,16 @@ func Serve(svc interface{}) error {
 		os.Exit(1)
 	}
 
+	// Processes we start, such as provisioner commands, must not be able
+	// to connect to us.
+	token := os.Getenv(AuthTokenKey)
+	os.Unsetenv(AuthTokenKey)
+
 	// Create the server to serve our interface
 	server := rpc.NewServer()
 
 	// Register the service
-	name, err := tfrpc.Register(server, svc)
+	name, err := tfrpc.Register(server, f())
 	if err != nil {
 		return err
 	}
@@ -53,23 +92,21 @@ func Serve(svc interface{}) error {
 	}
 	defer listener.Close()
 
-	// Output the address and service name to stdout
-	log.Printf("Plugin address: %s %s\n",
+	// Output the address and service name to stdout, and whether
+	// connections must be authenticated. Clients from before
+	// authentication existed don't give us a token.
+	log.Printf("[DEBUG] Plugin address: %s %s\n",
 		listener.Addr().Network(), listener.Addr().String())
-	fmt.Printf("%s|%s|%s|%s\n",
+	line := fmt.Sprintf("%s|%s|%s|%s",
 		APIVersion,
 		listener.Addr().Network(),
 		listener.Addr().String(),
 		name)
-	os.Stdout.Sync()
-
-	// Accept a connection
-	log.Println("Waiting for connection...")
-	conn, err := listener.Accept()
-	if err != nil {
-		log.Printf("Error accepting connection: %s\n", err.Error())
-		return err
+	if token != "" {
+		line += "|auth"
 	}
+	fmt.Println(line)
+	os.Stdout.Sync()
 
 	// Eat the interrupts
 	ch := make(chan os.Signal, 1)
@@ -80,23 +117,151 @@ func Serve(svc interface{}) error {
 			<-ch
 			newCount := atomic.AddInt32(&count, 1)
 			log.Printf(
-				"Received interrupt signal (count: %d). Ignoring.",
+				"[DEBUG] Received interrupt signal (count: %d). Ignoring.",
 				newCount)
 		}
 	}()
 
+	if v := os.Getenv(PersistTimeoutKey); v != "" && !persist {
+		log.Printf("[INFO] Plugin has no factory, serving a single connection")
+	} else if v != "" {
+		timeout, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("Invalid %s: %s", PersistTimeoutKey, err)
+		}
+
+		// The process that started us will exit while we're still
+		// running, so we can't keep writing to the pipes it gave us.
+		devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+		if err != nil {
+			return err
+		}
+		defer devNull.Close()
+		log.SetOutput(devNull)
+		os.Stdout = devNull
+		os.Stderr = devNull
+
+		newServer := func() (*rpc.Server, error) {
+			server := rpc.NewServer()
+			return server, tfrpc.RegisterName(server, name, f())
+		}
+		return servePersistent(newServer, listener, timeout, token)
+	}
+
+	// Accept a connection
+	log.Println("[DEBUG] Waiting for connection...")
+	var conn net.Conn
+	for {
+		conn, err = listener.Accept()
+		if err != nil {
+			log.Printf("[ERROR] Error accepting connection: %s\n", err.Error())
+			return err
+		}
+
+		if err := authenticate(conn, token); err != nil {
+			log.Printf("[ERROR] Rejected plugin connection: %s", err)
+			conn.Close()
+			continue
+		}
+
+		break
+	}
+
 	// Serve a single connection
-	log.Println("Serving a plugin connection...")
+	log.Println("[DEBUG] Serving a plugin connection...")
 	server.ServeConn(conn)
 	return nil
 }
 
+// servePersistent serves connections as they come, so that the plugin
+// can be reused by multiple runs of Terraform. Every connection is
+// served by a new server from newServer. Once there have been no
+// connections for the given timeout, it returns.
+func servePersistent(
+	newServer func() (*rpc.Server, error),
+	listener net.Listener,
+	timeout time.Duration,
+	token string) error {
+	var l sync.Mutex
+	var wg sync.WaitGroup
+	active := 0
+	idle := time.AfterFunc(timeout, func() { listener.Close() })
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			// The listener is closed when we've been idle for too long.
+			// Finish serving anyone who connected just before that.
+			wg.Wait()
+			return nil
+		}
+
+		l.Lock()
+		active++
+		idle.Stop()
+		l.Unlock()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := authenticate(conn, token); err != nil {
+				log.Printf("[ERROR] Rejected plugin connection: %s", err)
+				conn.Close()
+			} else if server, err := newServer(); err != nil {
+				log.Printf("[ERROR] Error creating plugin server: %s", err)
+				conn.Close()
+			} else {
+				server.ServeConn(conn)
+			}
+
+			l.Lock()
+			defer l.Unlock()
+			active--
+			if active == 0 {
+				idle.Reset(timeout)
+			}
+		}()
+	}
+}
+
+// authenticate checks that a new connection starts with the auth token.
+// If the token is empty, every connection is allowed.
+func authenticate(conn net.Conn, token string) error {
+	if token == "" {
+		return nil
+	}
+
+	// Exactly the token is read, since anything after it is for RPC
+	buf := make([]byte, len(token)+1)
+	conn.SetReadDeadline(time.Now().Add(authTimeout))
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		return err
+	}
+	conn.SetReadDeadline(time.Time{})
+
+	if subtle.ConstantTimeCompare(buf, []byte(token+"\n")) != 1 {
+		return errors.New("invalid auth token")
+	}
+
+	return nil
+}
+
 func serverListener() (net.Listener, error) {
 	if runtime.GOOS == "windows" {
 		return serverListener_tcp()
 	}
 
-	return serverListener_unix()
+	// Socket paths are limited to around 100 bytes, less on the BSDs
+	// than on Linux, so a long temporary directory can make the socket
+	// impossible to create. TCP works everywhere.
+	listener, err := serverListener_unix()
+	if err != nil {
+		log.Printf(
+			"[WARN] Error creating unix socket, using TCP instead: %s", err)
+		return serverListener_tcp()
+	}
+
+	return listener, nil
 }
 
 func serverListener_tcp() (net.Listener, error) {
@@ -137,5 +302,16 @@ func serverListener_unix() (net.Listener, error) {
 		return nil, err
 	}
 
-	return net.Listen("unix", path)
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only our user can connect to the socket
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	return listener, nil
 }
@@ -0,0 +1,120 @@
+package plugin
+
+import (
+	"io/ioutil"
+	"net"
+	"net/rpc"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tfrpc "github.com/hashicorp/terraform/rpc"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestServerListener_longTempDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins always use TCP on Windows")
+	}
+
+	td, err := ioutil.TempDir("", "tf")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(td)
+
+	// The path is too long for a socket on every platform
+	dir := filepath.Join(td, strings.Repeat("d", 200))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	for k, v := range map[string]string{
+		"TMPDIR":             dir,
+		"TF_PLUGIN_MIN_PORT": "10000",
+		"TF_PLUGIN_MAX_PORT": "25000",
+	} {
+		defer os.Setenv(k, os.Getenv(k))
+		os.Setenv(k, v)
+	}
+
+	listener, err := serverListener()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer listener.Close()
+
+	if listener.Addr().Network() != "tcp" {
+		t.Fatalf("bad: %s", listener.Addr())
+	}
+}
+
+func TestServePersistent(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var l sync.Mutex
+	var providers []*terraform.MockResourceProvider
+	newServer := func() (*rpc.Server, error) {
+		p := new(terraform.MockResourceProvider)
+		l.Lock()
+		providers = append(providers, p)
+		l.Unlock()
+
+		server := rpc.NewServer()
+		return server, tfrpc.RegisterName(server, "Terraform", p)
+	}
+
+	doneCh := make(chan error)
+	go func() {
+		doneCh <- servePersistent(newServer, listener, 100*time.Millisecond, "")
+	}()
+
+	// Every connection gets its own provider, so configuring one
+	// doesn't configure the other.
+	var clients []*tfrpc.ResourceProvider
+	for i := 0; i < 2; i++ {
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		clients = append(clients, &tfrpc.ResourceProvider{
+			Client: rpc.NewClient(conn),
+			Name:   "Terraform",
+		})
+		defer clients[i].Client.Close()
+	}
+
+	if err := clients[0].Configure(new(terraform.ResourceConfig)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	clients[1].Resources()
+
+	l.Lock()
+	if len(providers) != 2 {
+		t.Fatalf("bad: %#v", providers)
+	}
+	if !providers[0].ConfigureCalled || providers[1].ConfigureCalled {
+		t.Fatalf("bad: %#v", providers)
+	}
+	l.Unlock()
+
+	// Once the connections close, it stops after being idle
+	for _, c := range clients {
+		c.Client.Close()
+	}
+	select {
+	case err := <-doneCh:
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("should stop after being idle")
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform/command"
+	"github.com/hashicorp/terraform/plugin"
+	"github.com/hashicorp/terraform/rpc"
+)
+
+// EnvPluginDaemon is the environmental variable that enables keeping
+// plugins running between invocations of Terraform in the same
+// directory. If it is set to a duration, the plugins exit after being
+// idle for that long. Otherwise, DefaultPluginIdleTimeout is used.
+const EnvPluginDaemon = "TF_PLUGIN_DAEMON"
+
+// DefaultPluginIdleTimeout is how long kept-alive plugins wait for the
+// next run of Terraform before exiting.
+const DefaultPluginIdleTimeout = 10 * time.Minute
+
+// pluginHostFile is the file, relative to the working directory, that
+// records the plugins that are being kept alive.
+var pluginHostFile = filepath.Join(command.DefaultDataDir, "plugins.json")
+
+var pluginHostLock sync.Mutex
+
+// pluginHostEntry is a plugin that is being kept alive. The fingerprint
+// is of the executable and the environment it was started with, since a
+// running plugin doesn't see either of them change.
+type pluginHostEntry struct {
+	Fingerprint string
+	Reattach    *plugin.ReattachConfig
+}
+
+// pluginClient returns the plugin client for the plugin at the given
+// path. If the plugin daemon mode is enabled, this reattaches to a
+// plugin that was kept alive by an earlier run if there is one with the
+// same executable and environment, or starts a plugin that will be kept
+// alive otherwise.
+func pluginClient(path string) (*plugin.Client, error) {
+	var config plugin.ClientConfig
+	config.Cmd = pluginCmd(path)
+	config.Managed = true
+
+	v := os.Getenv(EnvPluginDaemon)
+	if v == "" {
+		return plugin.NewClient(&config), nil
+	}
+
+	timeout, err := time.ParseDuration(v)
+	if err != nil {
+		timeout = DefaultPluginIdleTimeout
+	}
+
+	fingerprint, err := pluginFingerprint(config.Cmd.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	pluginHostLock.Lock()
+	defer pluginHostLock.Unlock()
+
+	plugins := readPluginHostFile()
+	if e, ok := plugins[config.Cmd.Path]; ok && e.Reattach != nil {
+		// Make sure the plugin is still alive before reusing it. If it
+		// isn't, or it was started from another executable or
+		// environment, we'll just start a new one. The old one exits
+		// once it's idle.
+		r := e.Reattach
+		conn, err := net.Dial(r.Network, r.Address)
+		if err == nil {
+			conn.Close()
+		}
+
+		if err == nil && e.Fingerprint == fingerprint {
+			log.Printf("[DEBUG] Reattaching to plugin: %s", config.Cmd.Path)
+			return plugin.NewClient(&plugin.ClientConfig{
+				Managed:  true,
+				Reattach: r,
+			}), nil
+		}
+	}
+
+	config.PersistTimeout = timeout
+	client := plugin.NewClient(&config)
+	r, err := client.ReattachConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	plugins[config.Cmd.Path] = &pluginHostEntry{
+		Fingerprint: fingerprint,
+		Reattach:    r,
+	}
+	if err := writePluginHostFile(plugins); err != nil {
+		// Not fatal, the plugin just won't be reused next time
+		log.Printf("[WARN] Error writing %s: %s", pluginHostFile, err)
+	}
+
+	return client, nil
+}
+
+// pluginFingerprint returns the fingerprint of the plugin executable at
+// the given path, together with our environment. The executable is
+// identified by its size and modification time, which change when it is
+// replaced, such as by self-update.
+func pluginFingerprint(path string) (string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+
+	// Variables that the shell changes between commands are left out,
+	// so that they don't keep plugins from being reused.
+	env := make([]string, 0, len(os.Environ()))
+	for _, v := range os.Environ() {
+		if strings.HasPrefix(v, "_=") || strings.HasPrefix(v, "OLDPWD=") {
+			continue
+		}
+
+		env = append(env, v)
+	}
+	sort.Strings(env)
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%d\n%d\n", fi.Size(), fi.ModTime().UnixNano())
+	for _, v := range env {
+		fmt.Fprintf(h, "%s\x00", v)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func readPluginHostFile() map[string]*pluginHostEntry {
+	result := make(map[string]*pluginHostEntry)
+
+	f, err := os.Open(pluginHostFile)
+	if err != nil {
+		return result
+	}
+	defer f.Close()
+
+	if err := json.NewDecoder(f).Decode(&result); err != nil {
+		log.Printf("[WARN] Error reading %s: %s", pluginHostFile, err)
+		return make(map[string]*pluginHostEntry)
+	}
+
+	return result
+}
+
+func writePluginHostFile(plugins map[string]*pluginHostEntry) error {
+	if err := os.MkdirAll(filepath.Dir(pluginHostFile), 0755); err != nil {
+		return err
+	}
+
+	// The file has the secrets for connecting to the plugins, so only
+	// our user can read it.
+	f, err := os.OpenFile(
+		pluginHostFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := f.Chmod(0600); err != nil {
+		return err
+	}
+
+	return json.NewEncoder(f).Encode(plugins)
+}
+
+// pluginResourceProvider is a provider served by a plugin. Closing it
+// kills the plugin, or disconnects from it if it's being kept alive.
+type pluginResourceProvider struct {
+	*rpc.ResourceProvider
+	Plugin *plugin.Client
+}
+
+func (p *pluginResourceProvider) Close() error {
+	p.Plugin.Kill()
+	return nil
+}
+
+// pluginResourceProvisioner is a provisioner served by a plugin, which
+// is closed like a pluginResourceProvider.
+type pluginResourceProvisioner struct {
+	*rpc.ResourceProvisioner
+	Plugin *plugin.Client
+}
+
+func (p *pluginResourceProvisioner) Close() error {
+	p.Plugin.Kill()
+	return nil
+}
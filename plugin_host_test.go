@@ -0,0 +1,98 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/plugin"
+)
+
+func TestPluginHostFile(t *testing.T) {
+	td, err := ioutil.TempDir("", "tf")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(td)
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := os.Chdir(td); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.Chdir(cwd)
+
+	// No file yet
+	if actual := readPluginHostFile(); len(actual) != 0 {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	expected := map[string]*pluginHostEntry{
+		"/bin/terraform-provider-foo": &pluginHostEntry{
+			Fingerprint: "abc",
+			Reattach: &plugin.ReattachConfig{
+				Network: "unix",
+				Address: "/tmp/tf-plugin1234",
+				Service: "Terraform1",
+				Token:   "secret",
+			},
+		},
+	}
+	if err := writePluginHostFile(expected); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := readPluginHostFile()
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	fi, err := os.Stat(pluginHostFile)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if fi.Mode().Perm() != 0600 {
+		t.Fatalf("bad: %s", fi.Mode())
+	}
+}
+
+func TestPluginFingerprint(t *testing.T) {
+	tf, err := ioutil.TempFile("", "tf")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	tf.Close()
+	defer os.Remove(tf.Name())
+
+	fp, err := pluginFingerprint(tf.Name())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// The environment is part of the fingerprint
+	defer os.Setenv("TF_PLUGIN_TEST", os.Getenv("TF_PLUGIN_TEST"))
+	os.Setenv("TF_PLUGIN_TEST", "foo")
+	actual, err := pluginFingerprint(tf.Name())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if actual == fp {
+		t.Fatal("should change with the environment")
+	}
+	fp = actual
+
+	// So is the executable
+	if err := ioutil.WriteFile(tf.Name(), []byte("foo"), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	actual, err = pluginFingerprint(tf.Name())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if actual == fp {
+		t.Fatal("should change with the executable")
+	}
+}
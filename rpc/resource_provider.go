@@ -1,7 +1,10 @@
 package rpc
 
 import (
+	"log"
 	"net/rpc"
+	"strings"
+	"sync"
 
 	"github.com/hashicorp/terraform/terraform"
 )
@@ -11,6 +14,9 @@ import (
 type ResourceProvider struct {
 	Client *rpc.Client
 	Name   string
+
+	capsOnce sync.Once
+	caps     terraform.ResourceProviderCapabilities
 }
 
 func (p *ResourceProvider) Validate(c *terraform.ResourceConfig) ([]string, []error) {
@@ -19,7 +25,7 @@ func (p *ResourceProvider) Validate(c *terraform.ResourceConfig) ([]string, []er
 		Config: c,
 	}
 
-	err := p.Client.Call(p.Name+".Validate", &args, &resp)
+	err := call(p.Client, p.Name+".Validate", &args, &resp)
 	if err != nil {
 		return nil, []error{err}
 	}
@@ -43,7 +49,7 @@ func (p *ResourceProvider) ValidateResource(
 		Type:   t,
 	}
 
-	err := p.Client.Call(p.Name+".ValidateResource", &args, &resp)
+	err := call(p.Client, p.Name+".ValidateResource", &args, &resp)
 	if err != nil {
 		return nil, []error{err}
 	}
@@ -61,8 +67,36 @@ func (p *ResourceProvider) ValidateResource(
 
 func (p *ResourceProvider) Configure(c *terraform.ResourceConfig) error {
 	var resp ResourceProviderConfigureResponse
-	err := p.Client.Call(p.Name+".Configure", c, &resp)
+	err := call(p.Client, p.Name+".Configure", c, &resp)
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		err = resp.Error
+	}
+
+	return err
+}
+
+func (p *ResourceProvider) ValidateCredentials() error {
+	if !p.supports(func(c terraform.ResourceProviderCapabilities) bool {
+		return c.ValidateCredentials
+	}) {
+		return nil
+	}
+
+	// A real value is sent rather than nil, since plugins that don't know
+	// ValidateCredentials have to be able to read and discard it before
+	// replying with an error.
+	var resp ResourceProviderValidateCredentialsResponse
+	err := call(p.Client, p.Name+".ValidateCredentials", true, &resp)
 	if err != nil {
+		// Plugins built before ValidateCredentials existed can't check
+		// their credentials, which isn't an error.
+		if strings.HasPrefix(err.Error(), "rpc: can't find method") {
+			return nil
+		}
+
 		return err
 	}
 	if resp.Error != nil {
@@ -72,6 +106,32 @@ func (p *ResourceProvider) Configure(c *terraform.ResourceConfig) error {
 	return err
 }
 
+func (p *ResourceProvider) WriteOnlyConfig() []string {
+	// A real value is sent for the same reason as in ValidateCredentials.
+	// Plugins built before WriteOnlyConfig existed have no write-only
+	// configuration.
+	var result []string
+	err := call(p.Client, p.Name+".WriteOnlyConfig", true, &result)
+	if err != nil {
+		return nil
+	}
+
+	return result
+}
+
+func (p *ResourceProvider) ConfigSchema() []terraform.ConfigAttribute {
+	// A real value is sent for the same reason as in ValidateCredentials.
+	// Plugins built before ConfigSchema existed can't describe their
+	// configuration.
+	var result []terraform.ConfigAttribute
+	err := call(p.Client, p.Name+".ConfigSchema", true, &result)
+	if err != nil {
+		return nil
+	}
+
+	return result
+}
+
 func (p *ResourceProvider) Apply(
 	s *terraform.ResourceState,
 	d *terraform.ResourceDiff) (*terraform.ResourceState, error) {
@@ -81,7 +141,7 @@ func (p *ResourceProvider) Apply(
 		Diff:  d,
 	}
 
-	err := p.Client.Call(p.Name+".Apply", args, &resp)
+	err := call(p.Client, p.Name+".Apply", args, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -100,7 +160,8 @@ func (p *ResourceProvider) Diff(
 		State:  s,
 		Config: c,
 	}
-	err := p.Client.Call(p.Name+".Diff", args, &resp)
+
+	err := call(p.Client, p.Name+".Diff", args, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -114,7 +175,7 @@ func (p *ResourceProvider) Diff(
 func (p *ResourceProvider) Refresh(
 	s *terraform.ResourceState) (*terraform.ResourceState, error) {
 	var resp ResourceProviderRefreshResponse
-	err := p.Client.Call(p.Name+".Refresh", s, &resp)
+	err := call(p.Client, p.Name+".Refresh", s, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -125,10 +186,104 @@ func (p *ResourceProvider) Refresh(
 	return resp.State, err
 }
 
+func (p *ResourceProvider) ListResources(
+	t string, filters map[string]string) ([]*terraform.ResourceState, error) {
+	if !p.supports(func(c terraform.ResourceProviderCapabilities) bool {
+		return c.ListResources
+	}) {
+		return nil, terraform.ErrListNotSupported
+	}
+
+	var resp ResourceProviderListResourcesResponse
+	args := &ResourceProviderListResourcesArgs{
+		Type:    t,
+		Filters: filters,
+	}
+
+	err := call(p.Client, p.Name+".ListResources", args, &resp)
+	if err != nil {
+		// Plugins built before ListResources existed can't list anything.
+		if strings.HasPrefix(err.Error(), "rpc: can't find method") {
+			return nil, terraform.ErrListNotSupported
+		}
+
+		return nil, err
+	}
+	if resp.Error != nil {
+		// The error is sent as a BasicError, so the sentinel has to be
+		// restored for callers to be able to compare against it.
+		if resp.Error.Error() == terraform.ErrListNotSupported.Error() {
+			return nil, terraform.ErrListNotSupported
+		}
+
+		err = resp.Error
+	}
+
+	return resp.States, err
+}
+
+func (p *ResourceProvider) Stop() error {
+	if !p.supports(func(c terraform.ResourceProviderCapabilities) bool {
+		return c.Stop
+	}) {
+		return nil
+	}
+
+	// The call is sent over the same connection as the calls in progress,
+	// which net/rpc allows. A real value is sent for the same reason as
+	// in ValidateCredentials.
+	var resp ResourceProviderStopResponse
+	err := call(p.Client, p.Name+".Stop", true, &resp)
+	if err != nil {
+		// Plugins built before Stop existed leave their calls to finish.
+		if strings.HasPrefix(err.Error(), "rpc: can't find method") {
+			return nil
+		}
+
+		return err
+	}
+	if resp.Error != nil {
+		err = resp.Error
+	}
+
+	return err
+}
+
+// Capabilities returns the capabilities of the plugin. They're asked for
+// once, since they can't change while the plugin runs.
+func (p *ResourceProvider) Capabilities() terraform.ResourceProviderCapabilities {
+	p.capsOnce.Do(func() {
+		// A real value is sent for the same reason as in
+		// ValidateCredentials.
+		var result terraform.ResourceProviderCapabilities
+		err := call(p.Client, p.Name+".Capabilities", true, &result)
+		if err != nil {
+			// Plugins built before Capabilities existed can't report
+			// them. Any other error is left to the calls that follow.
+			log.Printf(
+				"[WARN] rpc: %s can't report its capabilities: %s", p.Name, err)
+			result = terraform.ResourceProviderCapabilities{Unknown: true}
+		}
+
+		p.caps = result
+	})
+
+	return p.caps
+}
+
+// supports returns false if the plugin reported that it doesn't support
+// a feature, in which case the call for the feature is skipped. Plugins
+// that can't report their capabilities are called anyway.
+func (p *ResourceProvider) supports(
+	f func(terraform.ResourceProviderCapabilities) bool) bool {
+	caps := p.Capabilities()
+	return caps.Unknown || f(caps)
+}
+
 func (p *ResourceProvider) Resources() []terraform.ResourceType {
 	var result []terraform.ResourceType
 
-	err := p.Client.Call(p.Name+".Resources", new(interface{}), &result)
+	err := call(p.Client, p.Name+".Resources", new(interface{}), &result)
 	if err != nil {
 		// TODO: panic, log, what?
 		return nil
@@ -147,6 +302,10 @@ type ResourceProviderConfigureResponse struct {
 	Error *BasicError
 }
 
+type ResourceProviderValidateCredentialsResponse struct {
+	Error *BasicError
+}
+
 type ResourceProviderApplyArgs struct {
 	State *terraform.ResourceState
 	Diff  *terraform.ResourceDiff
@@ -172,6 +331,20 @@ type ResourceProviderRefreshResponse struct {
 	Error *BasicError
 }
 
+type ResourceProviderListResourcesArgs struct {
+	Type    string
+	Filters map[string]string
+}
+
+type ResourceProviderListResourcesResponse struct {
+	States []*terraform.ResourceState
+	Error  *BasicError
+}
+
+type ResourceProviderStopResponse struct {
+	Error *BasicError
+}
+
 type ResourceProviderValidateArgs struct {
 	Config *terraform.ResourceConfig
 }
@@ -231,6 +404,37 @@ func (s *ResourceProviderServer) Configure(
 	return nil
 }
 
+func (s *ResourceProviderServer) ValidateCredentials(
+	nothing bool,
+	reply *ResourceProviderValidateCredentialsResponse) error {
+	err := s.Provider.ValidateCredentials()
+	*reply = ResourceProviderValidateCredentialsResponse{
+		Error: NewBasicError(err),
+	}
+	return nil
+}
+
+func (s *ResourceProviderServer) WriteOnlyConfig(
+	nothing bool,
+	result *[]string) error {
+	*result = s.Provider.WriteOnlyConfig()
+	return nil
+}
+
+func (s *ResourceProviderServer) ConfigSchema(
+	nothing bool,
+	result *[]terraform.ConfigAttribute) error {
+	*result = s.Provider.ConfigSchema()
+	return nil
+}
+
+func (s *ResourceProviderServer) Capabilities(
+	nothing bool,
+	result *terraform.ResourceProviderCapabilities) error {
+	*result = s.Provider.Capabilities()
+	return nil
+}
+
 func (s *ResourceProviderServer) Apply(
 	args *ResourceProviderApplyArgs,
 	result *ResourceProviderApplyResponse) error {
@@ -264,6 +468,27 @@ func (s *ResourceProviderServer) Refresh(
 	return nil
 }
 
+func (s *ResourceProviderServer) ListResources(
+	args *ResourceProviderListResourcesArgs,
+	result *ResourceProviderListResourcesResponse) error {
+	states, err := s.Provider.ListResources(args.Type, args.Filters)
+	*result = ResourceProviderListResourcesResponse{
+		States: states,
+		Error:  NewBasicError(err),
+	}
+	return nil
+}
+
+func (s *ResourceProviderServer) Stop(
+	nothing bool,
+	reply *ResourceProviderStopResponse) error {
+	err := s.Provider.Stop()
+	*reply = ResourceProviderStopResponse{
+		Error: NewBasicError(err),
+	}
+	return nil
+}
+
 func (s *ResourceProviderServer) Resources(
 	nothing interface{},
 	result *[]terraform.ResourceType) error {
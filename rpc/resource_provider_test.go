@@ -2,8 +2,11 @@ package rpc
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform/terraform"
 )
@@ -37,6 +40,33 @@ func TestResourceProvider_configure(t *testing.T) {
 	}
 }
 
+func TestResourceProvider_configureAgain(t *testing.T) {
+	p := new(terraform.MockResourceProvider)
+	client, server := testClientServer(t)
+	name, err := Register(server, p)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	provider := &ResourceProvider{Client: client, Name: name}
+
+	config := &terraform.ResourceConfig{
+		Raw: map[string]interface{}{"foo": "bar"},
+	}
+	if err := provider.Configure(config); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Configuring again with the same config still configures, since
+	// the config can come from the environment of another run
+	p.ConfigureCalled = false
+	if err := provider.Configure(config); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !p.ConfigureCalled {
+		t.Fatal("configure should be called")
+	}
+}
+
 func TestResourceProvider_configure_errors(t *testing.T) {
 	p := new(terraform.MockResourceProvider)
 	client, server := testClientServer(t)
@@ -92,6 +122,239 @@ func TestResourceProvider_configure_warnings(t *testing.T) {
 	}
 }
 
+func TestResourceProvider_validateCredentials(t *testing.T) {
+	p := new(terraform.MockResourceProvider)
+	p.CapabilitiesReturn.ValidateCredentials = true
+	client, server := testClientServer(t)
+	name, err := Register(server, p)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	provider := &ResourceProvider{Client: client, Name: name}
+
+	if err := provider.ValidateCredentials(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !p.ValidateCredentialsCalled {
+		t.Fatal("validate credentials should be called")
+	}
+
+	p.ValidateCredentialsReturnError = errors.New("expired")
+	err = provider.ValidateCredentials()
+	if err == nil {
+		t.Fatal("should have error")
+	}
+	if err.Error() != "expired" {
+		t.Fatalf("bad: %s", err)
+	}
+}
+
+func TestResourceProvider_validateCredentialsNotSupported(t *testing.T) {
+	p := new(terraform.MockResourceProvider)
+	p.ValidateCredentialsReturnError = errors.New("expired")
+
+	client, server := testClientServer(t)
+	name, err := Register(server, p)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	provider := &ResourceProvider{Client: client, Name: name}
+
+	if err := provider.ValidateCredentials(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if p.ValidateCredentialsCalled {
+		t.Fatal("validate credentials should not be called")
+	}
+}
+
+func TestResourceProvider_validateCredentialsLegacy(t *testing.T) {
+	p := new(terraform.MockResourceProvider)
+	client, server := testClientServer(t)
+	err := server.RegisterName("Legacy", &legacyResourceProviderServer{
+		Server: &ResourceProviderServer{Provider: p},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	provider := &ResourceProvider{Client: client, Name: "Legacy"}
+
+	// Plugins from before ValidateCredentials can't check credentials
+	if err := provider.ValidateCredentials(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestResourceProvider_stop(t *testing.T) {
+	p := new(terraform.MockResourceProvider)
+	p.CapabilitiesReturn.Stop = true
+
+	// Apply blocks until the provider is stopped
+	stopCh := make(chan struct{})
+	p.StopFn = func() error {
+		close(stopCh)
+		return nil
+	}
+	p.ApplyFn = func(
+		*terraform.ResourceState,
+		*terraform.ResourceDiff) (*terraform.ResourceState, error) {
+		<-stopCh
+		return nil, errors.New("stopped")
+	}
+
+	client, server := testClientServer(t)
+	name, err := Register(server, p)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	provider := &ResourceProvider{Client: client, Name: name}
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := provider.Apply(
+			new(terraform.ResourceState), new(terraform.ResourceDiff))
+		errCh <- err
+	}()
+
+	if err := provider.Stop(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !p.Stopped() {
+		t.Fatal("stop should be called")
+	}
+
+	select {
+	case err := <-errCh:
+		if err == nil || err.Error() != "stopped" {
+			t.Fatalf("bad: %#v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("apply should return once stopped")
+	}
+}
+
+func TestResourceProvider_stopNotSupported(t *testing.T) {
+	p := new(terraform.MockResourceProvider)
+	client, server := testClientServer(t)
+	name, err := Register(server, p)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	provider := &ResourceProvider{Client: client, Name: name}
+
+	if err := provider.Stop(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if p.Stopped() {
+		t.Fatal("stop should not be called")
+	}
+}
+
+func TestResourceProvider_stopLegacy(t *testing.T) {
+	p := new(terraform.MockResourceProvider)
+	client, server := testClientServer(t)
+	err := server.RegisterName("Legacy", &legacyResourceProviderServer{
+		Server: &ResourceProviderServer{Provider: p},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	provider := &ResourceProvider{Client: client, Name: "Legacy"}
+
+	// Plugins from before Stop leave their calls to finish
+	if err := provider.Stop(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestResourceProvider_writeOnlyConfig(t *testing.T) {
+	p := new(terraform.MockResourceProvider)
+	p.WriteOnlyConfigReturn = []string{"access_key", "secret_key"}
+
+	client, server := testClientServer(t)
+	name, err := Register(server, p)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	provider := &ResourceProvider{Client: client, Name: name}
+
+	result := provider.WriteOnlyConfig()
+	if !p.WriteOnlyConfigCalled {
+		t.Fatal("write only config should be called")
+	}
+	if !reflect.DeepEqual(result, p.WriteOnlyConfigReturn) {
+		t.Fatalf("bad: %#v", result)
+	}
+}
+
+func TestResourceProvider_writeOnlyConfigLegacy(t *testing.T) {
+	p := new(terraform.MockResourceProvider)
+	p.WriteOnlyConfigReturn = []string{"access_key"}
+
+	client, server := testClientServer(t)
+	err := server.RegisterName("Legacy", &legacyResourceProviderServer{
+		Server: &ResourceProviderServer{Provider: p},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	provider := &ResourceProvider{Client: client, Name: "Legacy"}
+
+	if result := provider.WriteOnlyConfig(); result != nil {
+		t.Fatalf("bad: %#v", result)
+	}
+}
+
+func TestResourceProvider_configSchema(t *testing.T) {
+	p := new(terraform.MockResourceProvider)
+	p.ConfigSchemaReturn = []terraform.ConfigAttribute{
+		terraform.ConfigAttribute{
+			Name:      "access_key",
+			Required:  true,
+			WriteOnly: true,
+			EnvVars:   []string{"AWS_ACCESS_KEY_ID", "AWS_ACCESS_KEY"},
+		},
+		terraform.ConfigAttribute{
+			Name: "region",
+		},
+	}
+
+	client, server := testClientServer(t)
+	name, err := Register(server, p)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	provider := &ResourceProvider{Client: client, Name: name}
+
+	result := provider.ConfigSchema()
+	if !p.ConfigSchemaCalled {
+		t.Fatal("config schema should be called")
+	}
+	if !reflect.DeepEqual(result, p.ConfigSchemaReturn) {
+		t.Fatalf("bad: %#v", result)
+	}
+}
+
+func TestResourceProvider_configSchemaLegacy(t *testing.T) {
+	p := new(terraform.MockResourceProvider)
+	p.ConfigSchemaReturn = []terraform.ConfigAttribute{
+		terraform.ConfigAttribute{Name: "region"},
+	}
+
+	client, server := testClientServer(t)
+	err := server.RegisterName("Legacy", &legacyResourceProviderServer{
+		Server: &ResourceProviderServer{Provider: p},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	provider := &ResourceProvider{Client: client, Name: "Legacy"}
+
+	if result := provider.ConfigSchema(); result != nil {
+		t.Fatalf("bad: %#v", result)
+	}
+}
+
 func TestResourceProvider_apply(t *testing.T) {
 	p := new(terraform.MockResourceProvider)
 	client, server := testClientServer(t)
@@ -192,6 +455,59 @@ func TestResourceProvider_diff_error(t *testing.T) {
 	}
 }
 
+func TestResourceProvider_diffConcurrent(t *testing.T) {
+	p := new(terraform.MockResourceProvider)
+	client, server := testClientServer(t)
+	name, err := Register(server, p)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	provider := &ResourceProvider{Client: client, Name: name}
+
+	p.DiffFn = func(
+		s *terraform.ResourceState,
+		c *terraform.ResourceConfig) (*terraform.ResourceDiff, error) {
+		return &terraform.ResourceDiff{
+			Attributes: map[string]*terraform.ResourceAttrDiff{
+				"id": &terraform.ResourceAttrDiff{
+					Old: s.ID,
+				},
+			},
+		}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+
+			state := &terraform.ResourceState{ID: id}
+			diff, err := provider.Diff(state, new(terraform.ResourceConfig))
+			if err != nil {
+				t.Errorf("err: %s", err)
+				return
+			}
+			if diff.Attributes["id"].Old != id {
+				t.Errorf("bad: %s %#v", id, diff)
+			}
+		}(fmt.Sprintf("foo%d", i))
+	}
+	wg.Wait()
+}
+
+// legacyResourceProviderServer is a ResourceProviderServer from
+// before Capabilities existed.
+type legacyResourceProviderServer struct {
+	Server *ResourceProviderServer
+}
+
+func (s *legacyResourceProviderServer) Diff(
+	args *ResourceProviderDiffArgs,
+	result *ResourceProviderDiffResponse) error {
+	return s.Server.Diff(args, result)
+}
+
 func TestResourceProvider_refresh(t *testing.T) {
 	p := new(terraform.MockResourceProvider)
 	client, server := testClientServer(t)
@@ -222,6 +538,136 @@ func TestResourceProvider_refresh(t *testing.T) {
 	}
 }
 
+func TestResourceProvider_listResources(t *testing.T) {
+	p := new(terraform.MockResourceProvider)
+	p.CapabilitiesReturn.ListResources = true
+	client, server := testClientServer(t)
+	name, err := Register(server, p)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	provider := &ResourceProvider{Client: client, Name: name}
+
+	p.ListResourcesReturn = []*terraform.ResourceState{
+		&terraform.ResourceState{ID: "i-abc123"},
+	}
+
+	filters := map[string]string{"tag:Name": "web"}
+	result, err := provider.ListResources("aws_instance", filters)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !p.ListResourcesCalled {
+		t.Fatal("list resources should be called")
+	}
+	if p.ListResourcesType != "aws_instance" {
+		t.Fatalf("bad: %s", p.ListResourcesType)
+	}
+	if !reflect.DeepEqual(p.ListResourcesFilters, filters) {
+		t.Fatalf("bad: %#v", p.ListResourcesFilters)
+	}
+	if !reflect.DeepEqual(result, p.ListResourcesReturn) {
+		t.Fatalf("bad: %#v", result)
+	}
+}
+
+func TestResourceProvider_listResourcesNotSupported(t *testing.T) {
+	p := new(terraform.MockResourceProvider)
+	p.CapabilitiesReturn.ListResources = true
+	p.ListResourcesReturnError = terraform.ErrListNotSupported
+
+	client, server := testClientServer(t)
+	name, err := Register(server, p)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	provider := &ResourceProvider{Client: client, Name: name}
+
+	_, err = provider.ListResources("aws_instance", nil)
+	if err != terraform.ErrListNotSupported {
+		t.Fatalf("bad: %#v", err)
+	}
+}
+
+func TestResourceProvider_listResourcesNoCapability(t *testing.T) {
+	p := new(terraform.MockResourceProvider)
+	client, server := testClientServer(t)
+	name, err := Register(server, p)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	provider := &ResourceProvider{Client: client, Name: name}
+
+	_, err = provider.ListResources("aws_instance", nil)
+	if err != terraform.ErrListNotSupported {
+		t.Fatalf("bad: %#v", err)
+	}
+	if p.ListResourcesCalled {
+		t.Fatal("list resources should not be called")
+	}
+}
+
+func TestResourceProvider_listResourcesLegacy(t *testing.T) {
+	p := new(terraform.MockResourceProvider)
+	client, server := testClientServer(t)
+	err := server.RegisterName("Legacy", &legacyResourceProviderServer{
+		Server: &ResourceProviderServer{Provider: p},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	provider := &ResourceProvider{Client: client, Name: "Legacy"}
+
+	_, err = provider.ListResources("aws_instance", nil)
+	if err != terraform.ErrListNotSupported {
+		t.Fatalf("bad: %#v", err)
+	}
+}
+
+func TestResourceProvider_capabilities(t *testing.T) {
+	p := new(terraform.MockResourceProvider)
+	p.CapabilitiesReturn = terraform.ResourceProviderCapabilities{
+		ListResources: true,
+		Stop:          true,
+	}
+
+	client, server := testClientServer(t)
+	name, err := Register(server, p)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	provider := &ResourceProvider{Client: client, Name: name}
+
+	expected := terraform.ResourceProviderCapabilities{
+		ListResources: true,
+		Stop:          true,
+	}
+	if actual := provider.Capabilities(); actual != expected {
+		t.Fatalf("bad: %#v", actual)
+	}
+	if !p.CapabilitiesCalled {
+		t.Fatal("capabilities should be called")
+	}
+}
+
+func TestResourceProvider_capabilitiesLegacy(t *testing.T) {
+	p := new(terraform.MockResourceProvider)
+	client, server := testClientServer(t)
+	err := server.RegisterName("Legacy", &legacyResourceProviderServer{
+		Server: &ResourceProviderServer{Provider: p},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	provider := &ResourceProvider{Client: client, Name: "Legacy"}
+
+	// Plugins from before Capabilities can't report them
+	expected := terraform.ResourceProviderCapabilities{Unknown: true}
+	if actual := provider.Capabilities(); actual != expected {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
 func TestResourceProvider_resources(t *testing.T) {
 	p := new(terraform.MockResourceProvider)
 	client, server := testClientServer(t)
@@ -232,8 +678,22 @@ func TestResourceProvider_resources(t *testing.T) {
 	provider := &ResourceProvider{Client: client, Name: name}
 
 	expected := []terraform.ResourceType{
-		{"foo"},
-		{"bar"},
+		{Name: "foo"},
+		{Name: "bar", Taggable: true},
+		{
+			Name: "baz",
+			Attributes: []terraform.ResourceAttribute{
+				{Name: "name", Type: "string", Required: true},
+				{
+					Name:     "rule",
+					Type:     "set",
+					Optional: true,
+					Attributes: []terraform.ResourceAttribute{
+						{Name: "port", Type: "int", Required: true},
+					},
+				},
+			},
+		},
 	}
 
 	p.ResourcesReturn = expected
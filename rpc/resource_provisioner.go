@@ -2,6 +2,7 @@ package rpc
 
 import (
 	"net/rpc"
+	"strings"
 
 	"github.com/hashicorp/terraform/terraform"
 )
@@ -19,7 +20,7 @@ func (p *ResourceProvisioner) Validate(c *terraform.ResourceConfig) ([]string, [
 		Config: c,
 	}
 
-	err := p.Client.Call(p.Name+".Validate", &args, &resp)
+	err := call(p.Client, p.Name+".Validate", &args, &resp)
 	if err != nil {
 		return nil, []error{err}
 	}
@@ -44,7 +45,7 @@ func (p *ResourceProvisioner) Apply(
 		Config: c,
 	}
 
-	err := p.Client.Call(p.Name+".Apply", args, &resp)
+	err := call(p.Client, p.Name+".Apply", args, &resp)
 	if err != nil {
 		return err
 	}
@@ -55,6 +56,52 @@ func (p *ResourceProvisioner) Apply(
 	return err
 }
 
+func (p *ResourceProvisioner) Describe(
+	s *terraform.ResourceState,
+	c *terraform.ResourceConfig) ([]string, error) {
+	var resp ResourceProvisionerDescribeResponse
+	args := &ResourceProvisionerDescribeArgs{
+		State:  s,
+		Config: c,
+	}
+
+	err := call(p.Client, p.Name+".Describe", args, &resp)
+	if err != nil {
+		// Plugins built before Describe existed can't say what they'd
+		// do, so there's nothing to show for them.
+		if strings.HasPrefix(err.Error(), "rpc: can't find method") {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+	if resp.Error != nil {
+		err = resp.Error
+	}
+
+	return resp.Actions, err
+}
+
+func (p *ResourceProvisioner) Stop() error {
+	// A real value is sent so that plugins built before Stop existed can
+	// read and discard it before replying with an error, and leave Apply
+	// to finish.
+	var resp ResourceProvisionerStopResponse
+	err := call(p.Client, p.Name+".Stop", true, &resp)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "rpc: can't find method") {
+			return nil
+		}
+
+		return err
+	}
+	if resp.Error != nil {
+		err = resp.Error
+	}
+
+	return err
+}
+
 type ResourceProvisionerValidateArgs struct {
 	Config *terraform.ResourceConfig
 }
@@ -73,6 +120,20 @@ type ResourceProvisionerApplyResponse struct {
 	Error *BasicError
 }
 
+type ResourceProvisionerDescribeArgs struct {
+	State  *terraform.ResourceState
+	Config *terraform.ResourceConfig
+}
+
+type ResourceProvisionerDescribeResponse struct {
+	Actions []string
+	Error   *BasicError
+}
+
+type ResourceProvisionerStopResponse struct {
+	Error *BasicError
+}
+
 // ResourceProvisionerServer is a net/rpc compatible structure for serving
 // a ResourceProvisioner. This should not be used directly.
 type ResourceProvisionerServer struct {
@@ -89,6 +150,17 @@ func (s *ResourceProvisionerServer) Apply(
 	return nil
 }
 
+func (s *ResourceProvisionerServer) Describe(
+	args *ResourceProvisionerDescribeArgs,
+	result *ResourceProvisionerDescribeResponse) error {
+	actions, err := s.Provisioner.Describe(args.State, args.Config)
+	*result = ResourceProvisionerDescribeResponse{
+		Actions: actions,
+		Error:   NewBasicError(err),
+	}
+	return nil
+}
+
 func (s *ResourceProvisionerServer) Validate(
 	args *ResourceProvisionerValidateArgs,
 	reply *ResourceProvisionerValidateResponse) error {
@@ -103,3 +175,13 @@ func (s *ResourceProvisionerServer) Validate(
 	}
 	return nil
 }
+
+func (s *ResourceProvisionerServer) Stop(
+	nothing bool,
+	reply *ResourceProvisionerStopResponse) error {
+	err := s.Provisioner.Stop()
+	*reply = ResourceProvisionerStopResponse{
+		Error: NewBasicError(err),
+	}
+	return nil
+}
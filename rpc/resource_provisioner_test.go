@@ -36,6 +36,86 @@ func TestResourceProvisioner_apply(t *testing.T) {
 	}
 }
 
+func TestResourceProvisioner_describe(t *testing.T) {
+	p := new(terraform.MockResourceProvisioner)
+	p.DescribeReturn = []string{"run foo"}
+
+	client, server := testClientServer(t)
+	name, err := Register(server, p)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	provisioner := &ResourceProvisioner{Client: client, Name: name}
+
+	// Describe
+	state := &terraform.ResourceState{}
+	conf := &terraform.ResourceConfig{}
+	actions, err := provisioner.Describe(state, conf)
+	if !p.DescribeCalled {
+		t.Fatal("describe should be called")
+	}
+	if !reflect.DeepEqual(p.DescribeConfig, conf) {
+		t.Fatalf("bad: %#v", p.DescribeConfig)
+	}
+	if err != nil {
+		t.Fatalf("bad: %#v", err)
+	}
+	if !reflect.DeepEqual(actions, p.DescribeReturn) {
+		t.Fatalf("bad: %#v", actions)
+	}
+}
+
+func TestResourceProvisioner_describe_error(t *testing.T) {
+	p := new(terraform.MockResourceProvisioner)
+	p.DescribeReturnError = errors.New("foo")
+
+	client, server := testClientServer(t)
+	name, err := Register(server, p)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	provisioner := &ResourceProvisioner{Client: client, Name: name}
+
+	_, err = provisioner.Describe(
+		&terraform.ResourceState{}, &terraform.ResourceConfig{})
+	if err == nil {
+		t.Fatal("should have error")
+	}
+}
+
+// legacyResourceProvisionerServer is a ResourceProvisionerServer from
+// before Describe existed.
+type legacyResourceProvisionerServer struct {
+	Server *ResourceProvisionerServer
+}
+
+func (s *legacyResourceProvisionerServer) Apply(
+	args *ResourceProvisionerApplyArgs,
+	result *ResourceProvisionerApplyResponse) error {
+	return s.Server.Apply(args, result)
+}
+
+func TestResourceProvisioner_describeLegacy(t *testing.T) {
+	p := new(terraform.MockResourceProvisioner)
+	client, server := testClientServer(t)
+	err := server.RegisterName("Legacy", &legacyResourceProvisionerServer{
+		Server: &ResourceProvisionerServer{Provisioner: p},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	provisioner := &ResourceProvisioner{Client: client, Name: "Legacy"}
+
+	actions, err := provisioner.Describe(
+		new(terraform.ResourceState), new(terraform.ResourceConfig))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(actions) > 0 {
+		t.Fatalf("bad: %#v", actions)
+	}
+}
+
 func TestResourceProvisioner_validate(t *testing.T) {
 	p := new(terraform.MockResourceProvisioner)
 	client, server := testClientServer(t)
@@ -129,3 +209,23 @@ func TestResourceProvisioner_validate_warns(t *testing.T) {
 		t.Fatalf("bad: %#v", w)
 	}
 }
+
+func TestResourceProvisioner_stop(t *testing.T) {
+	p := new(terraform.MockResourceProvisioner)
+	p.StopReturnError = errors.New("foo")
+
+	client, server := testClientServer(t)
+	name, err := Register(server, p)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	provisioner := &ResourceProvisioner{Client: client, Name: name}
+
+	err = provisioner.Stop()
+	if !p.StopCalled {
+		t.Fatal("stop should be called")
+	}
+	if err == nil || err.Error() != "foo" {
+		t.Fatalf("bad: %#v", err)
+	}
+}
@@ -3,8 +3,10 @@ package rpc
 import (
 	"errors"
 	"fmt"
+	"log"
 	"net/rpc"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/terraform/terraform"
 )
@@ -19,17 +21,44 @@ func Register(server *rpc.Server, thing interface{}) (name string, err error) {
 	nextLock.Lock()
 	defer nextLock.Unlock()
 
+	name = fmt.Sprintf("Terraform%d", nextId)
+	if err = RegisterName(server, name, thing); err != nil {
+		return "", err
+	}
+
+	nextId += 1
+	return
+}
+
+// RegisterName registers a Terraform thing with the RPC server under the
+// given name, such as one returned by an earlier Register.
+func RegisterName(server *rpc.Server, name string, thing interface{}) error {
 	switch t := thing.(type) {
 	case terraform.ResourceProvider:
-		name = fmt.Sprintf("Terraform%d", nextId)
-		err = server.RegisterName(name, &ResourceProviderServer{Provider: t})
+		return server.RegisterName(name, &ResourceProviderServer{Provider: t})
 	case terraform.ResourceProvisioner:
-		name = fmt.Sprintf("Terraform%d", nextId)
-		err = server.RegisterName(name, &ResourceProvisionerServer{Provisioner: t})
+		return server.RegisterName(name, &ResourceProvisionerServer{Provisioner: t})
 	default:
-		return "", errors.New("Unknown type to register for RPC server.")
+		return errors.New("Unknown type to register for RPC server.")
 	}
+}
 
-	nextId += 1
-	return
+// call calls a method on the client, logging the method and how long it
+// took at the TRACE level so that the calls between Terraform and its
+// plugins can be seen with TF_LOG=TRACE. The arguments and replies
+// aren't logged, since they carry credentials and the values of
+// attributes, and the log ends up in crash.log.
+func call(c *rpc.Client, method string, args, reply interface{}) error {
+	log.Printf("[TRACE] rpc: calling %s", method)
+
+	start := time.Now()
+	err := c.Call(method, args, reply)
+	if err != nil {
+		log.Printf("[TRACE] rpc: %s failed after %s: %s",
+			method, time.Since(start), err)
+		return err
+	}
+
+	log.Printf("[TRACE] rpc: %s returned after %s", method, time.Since(start))
+	return nil
 }
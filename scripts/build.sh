@@ -19,6 +19,10 @@ GIT_DIRTY=$(test -n "`git status --porcelain`" && echo "+CHANGES" || true)
 XC_ARCH=${XC_ARCH:-"386 amd64 arm"}
 XC_OS=${XC_OS:-linux darwin windows freebsd openbsd}
 
+# Build for ARMv6 so that the ARM builds run on the smallest boards, such
+# as the first Raspberry Pi, as well as on newer ones.
+export GOARM=${GOARM:-6}
+
 # Install dependencies
 echo "==> Getting dependencies..."
 go get ./...
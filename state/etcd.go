@@ -0,0 +1,205 @@
+package state
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// EtcdState stores the state in a key of etcd, using the v2 API. The
+// state is locked by creating a key next to it, with the LockExtension,
+// that only one run of Terraform can create.
+type EtcdState struct {
+	// Address is the URL of an etcd member, such as
+	// "http://127.0.0.1:2379". Path is the key the state is stored in.
+	Address string
+	Path    string
+
+	// Client is the HTTP client to use. Defaults to http.DefaultClient.
+	Client *http.Client
+
+	state *terraform.State
+}
+
+// etcdResponse is the response of the etcd v2 keys API.
+type etcdResponse struct {
+	Node struct {
+		Value string `json:"value"`
+	} `json:"node"`
+
+	ErrorCode int    `json:"errorCode"`
+	Message   string `json:"message"`
+}
+
+func (s *EtcdState) State() *terraform.State {
+	return s.state
+}
+
+// RefreshState reads the state from the key. If the key doesn't exist,
+// the state is nil, since there is no state yet.
+func (s *EtcdState) RefreshState() error {
+	value, ok, err := s.get(s.Path)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		s.state = nil
+		return nil
+	}
+
+	state, err := terraform.ReadState(strings.NewReader(value))
+	if err != nil {
+		return err
+	}
+
+	s.state = state
+	return nil
+}
+
+func (s *EtcdState) WriteState(state *terraform.State) error {
+	s.state = state
+	return nil
+}
+
+// PersistState sets the key to the state, incrementing its serial and
+// giving it a lineage like the other storages do.
+func (s *EtcdState) PersistState() error {
+	if s.state == nil {
+		s.state = new(terraform.State)
+	}
+	s.state.Serial++
+	if err := s.state.InitLineage(); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := terraform.WriteState(s.state, &buf); err != nil {
+		return err
+	}
+
+	_, err := s.put(s.Path, buf.String(), false)
+	return err
+}
+
+// Lock creates the lock key with the info as its value. If the key
+// already exists, the state is locked by whoever's info it holds.
+func (s *EtcdState) Lock(info string) error {
+	lockPath := s.Path + LockExtension
+	created, err := s.put(lockPath, info, true)
+	if err != nil {
+		return err
+	}
+	if !created {
+		holder, _, _ := s.get(lockPath)
+		return fmt.Errorf(
+			"the state %s is locked by: %s\n\n"+
+				"If no other run of Terraform is using it, delete the key %s.",
+			s.Path, holder, lockPath)
+	}
+
+	return nil
+}
+
+// Unlock deletes the lock key.
+func (s *EtcdState) Unlock() error {
+	resp, err := s.request("DELETE", s.Path+LockExtension, "", nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.response(resp)
+	return err
+}
+
+// get returns the value of a key, and false if the key doesn't exist.
+func (s *EtcdState) get(key string) (string, bool, error) {
+	resp, err := s.request("GET", key, "", nil)
+	if err != nil {
+		return "", false, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return "", false, nil
+	}
+
+	result, err := s.response(resp)
+	if err != nil {
+		return "", false, err
+	}
+
+	return result.Node.Value, true, nil
+}
+
+// put sets the value of a key. If create is true, the key is only set
+// if it doesn't exist yet, and false is returned if it does.
+func (s *EtcdState) put(key, value string, create bool) (bool, error) {
+	query := ""
+	if create {
+		query = "prevExist=false"
+	}
+
+	body := url.Values{"value": []string{value}}.Encode()
+	resp, err := s.request("PUT", key, query, strings.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	if create && resp.StatusCode == http.StatusPreconditionFailed {
+		resp.Body.Close()
+		return false, nil
+	}
+
+	_, err = s.response(resp)
+	return err == nil, err
+}
+
+func (s *EtcdState) request(
+	method, key, query string, body io.Reader) (*http.Response, error) {
+	u := fmt.Sprintf("%s/v2/keys/%s",
+		strings.TrimRight(s.Address, "/"), strings.TrimLeft(key, "/"))
+	if query != "" {
+		u += "?" + query
+	}
+
+	req, err := http.NewRequest(method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	return s.client().Do(req)
+}
+
+// response decodes and closes the response of a request, returning the
+// error etcd responded with, if any.
+func (s *EtcdState) response(resp *http.Response) (*etcdResponse, error) {
+	defer resp.Body.Close()
+
+	var result etcdResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf(
+			"unexpected response from etcd at %s: %s", s.Address, resp.Status)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf(
+			"error from etcd at %s: %s (%d)",
+			s.Address, result.Message, result.ErrorCode)
+	}
+
+	return &result, nil
+}
+
+func (s *EtcdState) client() *http.Client {
+	if s.Client != nil {
+		return s.Client
+	}
+
+	return http.DefaultClient
+}
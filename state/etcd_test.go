@@ -0,0 +1,136 @@
+package state
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestEtcdState(t *testing.T) {
+	var buf bytes.Buffer
+	if err := terraform.WriteState(TestStateInitial(), &buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	ts := httptest.NewServer(testEtcdHandler(map[string]string{
+		"/terraform/state": buf.String(),
+	}))
+	defer ts.Close()
+
+	TestState(t, &EtcdState{Address: ts.URL, Path: "terraform/state"})
+}
+
+func TestEtcdState_notFound(t *testing.T) {
+	ts := httptest.NewServer(testEtcdHandler(nil))
+	defer ts.Close()
+
+	s := &EtcdState{Address: ts.URL, Path: "/terraform/state"}
+	if err := s.RefreshState(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if s.State() != nil {
+		t.Fatalf("bad: %#v", s.State())
+	}
+}
+
+func TestEtcdState_lock(t *testing.T) {
+	ts := httptest.NewServer(testEtcdHandler(nil))
+	defer ts.Close()
+
+	s := &EtcdState{Address: ts.URL, Path: "/terraform/state"}
+	if err := s.Lock("alice@host"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// The state can't be locked twice
+	other := &EtcdState{Address: ts.URL, Path: "/terraform/state"}
+	err := other.Lock("bob@host")
+	if err == nil {
+		t.Fatal("should error")
+	}
+	if !strings.Contains(err.Error(), "alice@host") {
+		t.Fatalf("bad: %s", err)
+	}
+
+	if err := s.Unlock(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := other.Lock("bob@host"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestEtcdState_impl(t *testing.T) {
+	var _ State = new(EtcdState)
+	var _ StateLocker = new(EtcdState)
+}
+
+// testEtcdHandler returns a handler that implements the parts of the
+// etcd v2 keys API that EtcdState uses, with the given keys set.
+func testEtcdHandler(keys map[string]string) http.Handler {
+	var lock sync.Mutex
+	if keys == nil {
+		keys = make(map[string]string)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		key := strings.TrimPrefix(r.URL.Path, "/v2/keys")
+		respond := func(code int, v map[string]interface{}) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(code)
+			json.NewEncoder(w).Encode(v)
+		}
+		notFound := func() {
+			respond(http.StatusNotFound, map[string]interface{}{
+				"errorCode": 100,
+				"message":   "Key not found",
+			})
+		}
+		node := func(value string) map[string]interface{} {
+			return map[string]interface{}{
+				"node": map[string]interface{}{"key": key, "value": value},
+			}
+		}
+
+		switch r.Method {
+		case "GET":
+			value, ok := keys[key]
+			if !ok {
+				notFound()
+				return
+			}
+
+			respond(http.StatusOK, node(value))
+		case "PUT":
+			if _, ok := keys[key]; ok && r.URL.Query().Get("prevExist") == "false" {
+				respond(http.StatusPreconditionFailed, map[string]interface{}{
+					"errorCode": 105,
+					"message":   "Key already exists",
+				})
+				return
+			}
+
+			keys[key] = r.FormValue("value")
+			respond(http.StatusOK, node(keys[key]))
+		case "DELETE":
+			if _, ok := keys[key]; !ok {
+				notFound()
+				return
+			}
+
+			delete(keys, key)
+			respond(http.StatusOK, node(""))
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+}
@@ -0,0 +1,208 @@
+package state
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// HistoryExtension is added to the path of a local state file to form
+// the path of the directory its old versions are kept in.
+const HistoryExtension = ".history"
+
+// historyTimeFormat is the format of the time in the IDs of versions.
+const historyTimeFormat = "20060102T150405Z"
+
+// StateHistory is implemented by storages that keep old versions of the
+// state.
+type StateHistory interface {
+	// Versions returns the versions of the state that are kept, newest
+	// first.
+	Versions() ([]*StateVersion, error)
+
+	// Version returns the version of the state with the given ID.
+	Version(id string) (*terraform.State, error)
+}
+
+// StateVersion is a version of the state kept by a StateHistory.
+type StateVersion struct {
+	ID     string
+	Serial int64
+	Time   time.Time
+}
+
+// Retention is how many old versions of the state are kept. A version
+// is kept if either rule keeps it.
+type Retention struct {
+	// KeepLast is how many of the latest versions are kept.
+	KeepLast int
+
+	// KeepDailyDays is for how many days the latest version of each day
+	// is kept.
+	KeepDailyDays int
+}
+
+// Keep returns the versions to keep out of the given versions, which
+// are sorted newest first, at the given time.
+func (r *Retention) Keep(versions []*StateVersion, now time.Time) []*StateVersion {
+	cutoff := now.AddDate(0, 0, -r.KeepDailyDays)
+	days := make(map[string]struct{})
+
+	var result []*StateVersion
+	for i, v := range versions {
+		keep := i < r.KeepLast
+
+		day := v.Time.UTC().Format("2006-01-02")
+		if _, ok := days[day]; !ok && r.KeepDailyDays > 0 && v.Time.After(cutoff) {
+			days[day] = struct{}{}
+			keep = true
+		}
+
+		if keep {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+// Versions returns the versions of the state kept in the history
+// directory next to the state file.
+func (s *LocalState) Versions() ([]*StateVersion, error) {
+	entries, err := ioutil.ReadDir(s.historyDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var result []*StateVersion
+	for _, e := range entries {
+		v, ok := parseStateVersion(e.Name())
+		if ok {
+			result = append(result, v)
+		}
+	}
+
+	sort.Sort(stateVersionsByTime(result))
+	return result, nil
+}
+
+func (s *LocalState) Version(id string) (*terraform.State, error) {
+	if _, ok := parseStateVersion(id + ".tfstate"); !ok {
+		return nil, fmt.Errorf("invalid version: %s", id)
+	}
+
+	f, err := os.Open(filepath.Join(s.historyDir(), id+".tfstate"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no version %s of the state", id)
+		}
+
+		return nil, err
+	}
+	defer f.Close()
+
+	return terraform.ReadState(f)
+}
+
+// saveVersion keeps the state that was just persisted as a version in
+// the history, and removes the versions that the Retention doesn't
+// keep.
+func (s *LocalState) saveVersion() error {
+	now := time.Now()
+	if s.now != nil {
+		now = s.now()
+	}
+
+	dir := s.historyDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	id := fmt.Sprintf("%s-%d", now.UTC().Format(historyTimeFormat), s.state.Serial)
+	f, err := os.Create(filepath.Join(dir, id+".tfstate"))
+	if err != nil {
+		return err
+	}
+	err = terraform.WriteState(s.state, f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	versions, err := s.Versions()
+	if err != nil {
+		return err
+	}
+
+	keep := make(map[string]struct{})
+	for _, v := range s.Retention.Keep(versions, now) {
+		keep[v.ID] = struct{}{}
+	}
+	for _, v := range versions {
+		if _, ok := keep[v.ID]; ok {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(dir, v.ID+".tfstate")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *LocalState) historyDir() string {
+	path := strings.TrimSuffix(s.pathOut(), CompressedExtension)
+	return path + HistoryExtension
+}
+
+// parseStateVersion parses the name of a file in the history directory.
+func parseStateVersion(name string) (*StateVersion, bool) {
+	if !strings.HasSuffix(name, ".tfstate") {
+		return nil, false
+	}
+	id := strings.TrimSuffix(name, ".tfstate")
+
+	parts := strings.SplitN(id, "-", 2)
+	if len(parts) != 2 {
+		return nil, false
+	}
+
+	t, err := time.Parse(historyTimeFormat, parts[0])
+	if err != nil {
+		return nil, false
+	}
+	serial, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, false
+	}
+
+	return &StateVersion{ID: id, Serial: serial, Time: t}, true
+}
+
+// stateVersionsByTime sorts versions newest first. Versions written in
+// the same second are sorted by their serial.
+type stateVersionsByTime []*StateVersion
+
+func (s stateVersionsByTime) Len() int      { return len(s) }
+func (s stateVersionsByTime) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s stateVersionsByTime) Less(i, j int) bool {
+	if !s[i].Time.Equal(s[j].Time) {
+		return s[i].Time.After(s[j].Time)
+	}
+
+	return s[i].Serial > s[j].Serial
+}
@@ -0,0 +1,130 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRetentionKeep(t *testing.T) {
+	now := time.Date(2014, 7, 20, 12, 0, 0, 0, time.UTC)
+	versions := []*StateVersion{
+		&StateVersion{ID: "a", Time: now.Add(-1 * time.Hour)},
+		&StateVersion{ID: "b", Time: now.Add(-2 * time.Hour)},
+		&StateVersion{ID: "c", Time: now.Add(-25 * time.Hour)},
+		&StateVersion{ID: "d", Time: now.Add(-26 * time.Hour)},
+		&StateVersion{ID: "e", Time: now.Add(-49 * time.Hour)},
+		&StateVersion{ID: "f", Time: now.Add(-97 * time.Hour)},
+	}
+
+	cases := []struct {
+		Retention Retention
+		Expected  []string
+	}{
+		{Retention{KeepLast: 2}, []string{"a", "b"}},
+		{Retention{KeepDailyDays: 3}, []string{"a", "c", "e"}},
+		{Retention{KeepLast: 2, KeepDailyDays: 2}, []string{"a", "b", "c"}},
+		{Retention{KeepLast: 10}, []string{"a", "b", "c", "d", "e", "f"}},
+		{Retention{}, nil},
+	}
+
+	for i, tc := range cases {
+		var actual []string
+		for _, v := range tc.Retention.Keep(versions, now) {
+			actual = append(actual, v.ID)
+		}
+
+		if !reflect.DeepEqual(actual, tc.Expected) {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
+
+func TestLocalState_history(t *testing.T) {
+	ls := testLocalState(t, "terraform.tfstate.gz")
+	defer os.RemoveAll(filepath.Dir(ls.Path))
+
+	now := time.Date(2014, 7, 20, 12, 0, 0, 0, time.UTC)
+	ls.Retention = &Retention{KeepLast: 2}
+	ls.now = func() time.Time { return now }
+
+	if err := ls.RefreshState(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for i := 0; i < 3; i++ {
+		now = now.Add(time.Minute)
+		ls.State().Outputs = map[string]string{"i": []string{"a", "b", "c"}[i]}
+		if err := ls.PersistState(); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	// Only the last two versions are kept, next to the state without
+	// its compressed extension
+	if _, err := os.Stat(filepath.Join(
+		filepath.Dir(ls.Path), "terraform.tfstate"+HistoryExtension)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	versions, err := ls.Versions()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := []*StateVersion{
+		&StateVersion{
+			ID:     "20140720T120300Z-4",
+			Serial: 4,
+			Time:   time.Date(2014, 7, 20, 12, 3, 0, 0, time.UTC),
+		},
+		&StateVersion{
+			ID:     "20140720T120200Z-3",
+			Serial: 3,
+			Time:   time.Date(2014, 7, 20, 12, 2, 0, 0, time.UTC),
+		},
+	}
+	if !reflect.DeepEqual(versions, expected) {
+		t.Fatalf("bad: %#v", versions)
+	}
+
+	s, err := ls.Version("20140720T120200Z-3")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if s.Serial != 3 || s.Outputs["i"] != "b" {
+		t.Fatalf("bad: %#v", s)
+	}
+
+	for _, id := range []string{"20140720T120100Z-2", "../terraform", "nope"} {
+		if _, err := ls.Version(id); err == nil {
+			t.Fatalf("%s: should error", id)
+		}
+	}
+}
+
+func TestLocalState_noHistory(t *testing.T) {
+	ls := testLocalState(t, "terraform.tfstate")
+	defer os.RemoveAll(filepath.Dir(ls.Path))
+
+	if err := ls.RefreshState(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := ls.PersistState(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	versions, err := ls.Versions()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(versions) != 0 {
+		t.Fatalf("bad: %#v", versions)
+	}
+	if _, err := os.Stat(ls.Path + HistoryExtension); !os.IsNotExist(err) {
+		t.Fatalf("history shouldn't be kept: %s", err)
+	}
+}
+
+func TestLocalState_historyImpl(t *testing.T) {
+	var _ StateHistory = new(LocalState)
+}
@@ -0,0 +1,152 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// HTTPState stores the state at an HTTP URL. The state is read with a
+// GET of Address and written with a POST of the state to Address.
+//
+// The state is locked with a LOCK request to Address, and unlocked with
+// an UNLOCK request. Servers that don't implement locking respond with
+// 405 or 501.
+type HTTPState struct {
+	Address string
+
+	// Client is the HTTP client to use. Defaults to http.DefaultClient.
+	Client *http.Client
+
+	state *terraform.State
+}
+
+func (s *HTTPState) State() *terraform.State {
+	return s.state
+}
+
+// RefreshState reads the state from Address. If the server responds
+// with 404 or 204, the state is nil, since there is no state yet.
+func (s *HTTPState) RefreshState() error {
+	resp, err := s.client().Get(s.Address)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNoContent, http.StatusNotFound:
+		s.state = nil
+		return nil
+	default:
+		return fmt.Errorf(
+			"unexpected status reading state from %s: %s", s.Address, resp.Status)
+	}
+
+	state, err := terraform.ReadState(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	s.state = state
+	return nil
+}
+
+func (s *HTTPState) WriteState(state *terraform.State) error {
+	s.state = state
+	return nil
+}
+
+// PersistState posts the state to Address, incrementing its serial and
+// giving it a lineage like the other storages do.
+func (s *HTTPState) PersistState() error {
+	if s.state == nil {
+		s.state = new(terraform.State)
+	}
+	s.state.Serial++
+	if err := s.state.InitLineage(); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := terraform.WriteState(s.state, &buf); err != nil {
+		return err
+	}
+
+	resp, err := s.client().Post(s.Address, "application/json", &buf)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf(
+			"unexpected status writing state to %s: %s", s.Address, resp.Status)
+	}
+
+	return nil
+}
+
+// Lock sends a LOCK request with the info as its body. The server
+// responds with 423 or 409 if the state is already locked, with who
+// locked it in the body.
+func (s *HTTPState) Lock(info string) error {
+	resp, err := s.lockRequest("LOCK", info)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return nil
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return ErrLockNotSupported
+	case http.StatusLocked, http.StatusConflict:
+		holder, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf(
+			"the state %s is locked by: %s",
+			s.Address, strings.TrimSpace(string(holder)))
+	default:
+		return fmt.Errorf(
+			"unexpected status locking state at %s: %s", s.Address, resp.Status)
+	}
+}
+
+// Unlock sends an UNLOCK request.
+func (s *HTTPState) Unlock() error {
+	resp, err := s.lockRequest("UNLOCK", "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf(
+			"unexpected status unlocking state at %s: %s", s.Address, resp.Status)
+	}
+
+	return nil
+}
+
+func (s *HTTPState) lockRequest(method, body string) (*http.Response, error) {
+	req, err := http.NewRequest(method, s.Address, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client().Do(req)
+}
+
+func (s *HTTPState) client() *http.Client {
+	if s.Client != nil {
+		return s.Client
+	}
+
+	return http.DefaultClient
+}
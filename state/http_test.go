@@ -0,0 +1,145 @@
+package state
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestHTTPState(t *testing.T) {
+	var buf bytes.Buffer
+	if err := terraform.WriteState(TestStateInitial(), &buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	ts := httptest.NewServer(testHTTPStateHandler(buf.Bytes()))
+	defer ts.Close()
+
+	TestState(t, &HTTPState{Address: ts.URL})
+}
+
+func TestHTTPState_notFound(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+
+	s := &HTTPState{Address: ts.URL}
+	if err := s.RefreshState(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if s.State() != nil {
+		t.Fatalf("bad: %#v", s.State())
+	}
+}
+
+func TestHTTPState_error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+	defer ts.Close()
+
+	s := &HTTPState{Address: ts.URL}
+	if err := s.RefreshState(); err == nil {
+		t.Fatal("should error")
+	}
+	if err := s.PersistState(); err == nil {
+		t.Fatal("should error")
+	}
+}
+
+func TestHTTPState_impl(t *testing.T) {
+	var _ State = new(HTTPState)
+	var _ StateLocker = new(HTTPState)
+}
+
+func TestHTTPState_lock(t *testing.T) {
+	ts := httptest.NewServer(testHTTPStateHandler(nil))
+	defer ts.Close()
+
+	s := &HTTPState{Address: ts.URL}
+	if err := s.Lock("alice@host"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// The state can't be locked twice
+	other := &HTTPState{Address: ts.URL}
+	err := other.Lock("bob@host")
+	if err == nil {
+		t.Fatal("should error")
+	}
+	if !strings.Contains(err.Error(), "alice@host") {
+		t.Fatalf("bad: %s", err)
+	}
+
+	if err := s.Unlock(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := other.Lock("bob@host"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestHTTPState_lockNotSupported(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}))
+	defer ts.Close()
+
+	s := &HTTPState{Address: ts.URL}
+	if err := s.Lock("alice@host"); err != ErrLockNotSupported {
+		t.Fatalf("bad: %v", err)
+	}
+}
+
+// testHTTPStateHandler returns a handler that serves the given state for
+// GET requests, and replaces it with the body of POST requests. LOCK and
+// UNLOCK requests lock the state, with the body of the LOCK request as
+// who holds the lock.
+func testHTTPStateHandler(initial []byte) http.Handler {
+	var lock sync.Mutex
+	data := initial
+	var holder []byte
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		switch r.Method {
+		case "GET":
+			w.Write(data)
+		case "POST":
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			data = body
+		case "LOCK":
+			if holder != nil {
+				w.WriteHeader(http.StatusLocked)
+				w.Write(holder)
+				return
+			}
+
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			holder = body
+		case "UNLOCK":
+			holder = nil
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+}
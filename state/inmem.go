@@ -0,0 +1,34 @@
+package state
+
+import (
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// InmemState is an in-memory state storage. Persisting the state only
+// increments its serial and gives it a lineage, like persisting to any
+// other storage does.
+type InmemState struct {
+	state *terraform.State
+}
+
+func (s *InmemState) State() *terraform.State {
+	return s.state
+}
+
+func (s *InmemState) RefreshState() error {
+	return nil
+}
+
+func (s *InmemState) WriteState(state *terraform.State) error {
+	s.state = state
+	return nil
+}
+
+func (s *InmemState) PersistState() error {
+	if s.state == nil {
+		return nil
+	}
+
+	s.state.Serial++
+	return s.state.InitLineage()
+}
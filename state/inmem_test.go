@@ -0,0 +1,13 @@
+package state
+
+import (
+	"testing"
+)
+
+func TestInmemState(t *testing.T) {
+	TestState(t, &InmemState{state: TestStateInitial()})
+}
+
+func TestInmemState_impl(t *testing.T) {
+	var _ State = new(InmemState)
+}
@@ -0,0 +1,139 @@
+package state
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// CompressedExtension is the extension of state files that are
+// gzip-compressed at rest.
+const CompressedExtension = ".gz"
+
+// LocalState manipulates the state in a local file.
+type LocalState struct {
+	// Path is the path to read the state from. PathOut is the path to
+	// write the state to. If PathOut isn't set, Path is used for both.
+	Path    string
+	PathOut string
+
+	// Retention, if set, keeps the versions of the state that are
+	// persisted in the directory next to the state file with the
+	// HistoryExtension, and is how many of them are kept.
+	Retention *Retention
+
+	state *terraform.State
+	lock  *LocalLock
+
+	// now returns the current time, for the history. Defaults to
+	// time.Now.
+	now func() time.Time
+}
+
+func (s *LocalState) State() *terraform.State {
+	return s.state
+}
+
+// RefreshState reads the state from Path. If the file doesn't exist,
+// the state is nil, since there is no infrastructure yet.
+func (s *LocalState) RefreshState() error {
+	f, err := os.Open(s.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			s.state = nil
+			return nil
+		}
+
+		return err
+	}
+	defer f.Close()
+
+	state, err := terraform.ReadState(f)
+	if err != nil {
+		return err
+	}
+
+	s.state = state
+	return nil
+}
+
+func (s *LocalState) WriteState(state *terraform.State) error {
+	s.state = state
+	return nil
+}
+
+// Lock locks the state file that the state is written to with
+// LockLocal.
+func (s *LocalState) Lock(info string) error {
+	l, err := LockLocal(s.pathOut(), info)
+	if err != nil {
+		return err
+	}
+
+	s.lock = l
+	return nil
+}
+
+// Unlock removes the lock taken by Lock, if any.
+func (s *LocalState) Unlock() error {
+	if s.lock == nil {
+		return nil
+	}
+
+	err := s.lock.Unlock()
+	s.lock = nil
+	return err
+}
+
+// PersistState writes the state to PathOut, compressing it if the path
+// has the CompressedExtension.
+//
+// Every write increments the serial of the state, so that a given
+// version of the state can be identified, such as in the audit log. A
+// state persisted for the first time is also given its lineage.
+//
+// If Retention is set, the state is also kept in the history.
+func (s *LocalState) PersistState() error {
+	path := s.pathOut()
+
+	if s.state == nil {
+		s.state = new(terraform.State)
+	}
+	s.state.Serial++
+	if err := s.state.InitLineage(); err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if strings.HasSuffix(path, CompressedExtension) {
+		err = terraform.WriteStateCompressed(s.state, f)
+	} else {
+		err = terraform.WriteState(s.state, f)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	if s.Retention != nil {
+		return s.saveVersion()
+	}
+
+	return nil
+}
+
+func (s *LocalState) pathOut() string {
+	if s.PathOut != "" {
+		return s.PathOut
+	}
+
+	return s.Path
+}
@@ -0,0 +1,134 @@
+package state
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestLocalState(t *testing.T) {
+	ls := testLocalState(t, "terraform.tfstate")
+	defer os.RemoveAll(filepath.Dir(ls.Path))
+	TestState(t, ls)
+}
+
+func TestLocalState_compressed(t *testing.T) {
+	ls := testLocalState(t, "terraform.tfstate.gz")
+	defer os.RemoveAll(filepath.Dir(ls.Path))
+	TestState(t, ls)
+
+	// The state is compressed on disk
+	data, err := ioutil.ReadFile(ls.Path)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(data) < 2 || data[0] != 0x1f || data[1] != 0x8b {
+		t.Fatalf("should be compressed: %q", data)
+	}
+}
+
+func TestLocalState_lock(t *testing.T) {
+	ls := testLocalState(t, "terraform.tfstate")
+	defer os.RemoveAll(filepath.Dir(ls.Path))
+
+	if err := ls.Lock("alice@host"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// The lock is the same as LockLocal takes
+	if _, err := LockLocal(ls.Path, "bob@host"); err == nil {
+		t.Fatal("should error")
+	}
+
+	if err := ls.Unlock(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := os.Stat(ls.Path + LockExtension); !os.IsNotExist(err) {
+		t.Fatalf("lock should be removed: %s", err)
+	}
+
+	// Unlocking without a lock does nothing
+	if err := ls.Unlock(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestLocalState_pathOut(t *testing.T) {
+	ls := testLocalState(t, "terraform.tfstate")
+	defer os.RemoveAll(filepath.Dir(ls.Path))
+	ls.PathOut = ls.Path + ".out"
+
+	if err := ls.RefreshState(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := ls.PersistState(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// The original is untouched
+	if s := testReadState(t, ls.Path); s.Serial != 1 {
+		t.Fatalf("bad: %#v", s)
+	}
+	if s := testReadState(t, ls.PathOut); s.Serial != 2 {
+		t.Fatalf("bad: %#v", s)
+	}
+}
+
+func TestLocalState_noFile(t *testing.T) {
+	td, err := ioutil.TempDir("", "tf")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(td)
+
+	ls := &LocalState{Path: filepath.Join(td, "terraform.tfstate")}
+	if err := ls.RefreshState(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if ls.State() != nil {
+		t.Fatalf("bad: %#v", ls.State())
+	}
+}
+
+func TestLocalState_impl(t *testing.T) {
+	var _ State = new(LocalState)
+	var _ StateLocker = new(LocalState)
+}
+
+// testLocalState returns a LocalState whose file, in a new temporary
+// directory, holds TestStateInitial.
+func testLocalState(t *testing.T, name string) *LocalState {
+	td, err := ioutil.TempDir("", "tf")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	ls := &LocalState{Path: filepath.Join(td, name)}
+	if err := ls.WriteState(TestStateInitial()); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := ls.PersistState(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Start over with what is on disk
+	return &LocalState{Path: ls.Path}
+}
+
+func testReadState(t *testing.T, path string) *terraform.State {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer f.Close()
+
+	s, err := terraform.ReadState(f)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return s
+}
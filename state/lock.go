@@ -0,0 +1,62 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// ErrLockNotSupported is returned by StateLocker.Lock if the state
+// can't be locked, such as by an HTTP server that doesn't implement
+// locking.
+var ErrLockNotSupported = errors.New("locking the state isn't supported")
+
+// LockExtension is added to the path of a local state file to form the
+// path of its lock file.
+const LockExtension = ".lock"
+
+// LocalLock is a lock on a local state file, so that only one run of
+// Terraform changes the state at a time. The lock is a file next to the
+// state that holds who locked it, and is removed by Unlock. If Terraform
+// is killed while it holds the lock, the lock file must be removed by
+// hand.
+type LocalLock struct {
+	Path string
+}
+
+// LockLocal locks the state file at the given path. Info describes who
+// is locking it, and is shown to anyone else who tries to lock it. An
+// error is returned if the state is already locked.
+func LockLocal(path, info string) (*LocalLock, error) {
+	lockPath := path + LockExtension
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		if !os.IsExist(err) {
+			return nil, err
+		}
+
+		holder, _ := ioutil.ReadFile(lockPath)
+		return nil, fmt.Errorf(
+			"the state %s is locked by: %s\n\n"+
+				"If no other run of Terraform is using it, remove %s.",
+			path, strings.TrimSpace(string(holder)), lockPath)
+	}
+
+	_, err = f.WriteString(info + "\n")
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(lockPath)
+		return nil, err
+	}
+
+	return &LocalLock{Path: lockPath}, nil
+}
+
+// Unlock removes the lock.
+func (l *LocalLock) Unlock() error {
+	return os.Remove(l.Path)
+}
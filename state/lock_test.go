@@ -0,0 +1,46 @@
+package state
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLockLocal(t *testing.T) {
+	td, err := ioutil.TempDir("", "tf")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(td)
+
+	path := filepath.Join(td, "terraform.tfstate")
+	l, err := LockLocal(path, "alice@host")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// The state can't be locked twice
+	_, err = LockLocal(path, "bob@host")
+	if err == nil {
+		t.Fatal("should error")
+	}
+	if !strings.Contains(err.Error(), "alice@host") {
+		t.Fatalf("bad: %s", err)
+	}
+
+	if err := l.Unlock(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := os.Stat(path + LockExtension); !os.IsNotExist(err) {
+		t.Fatalf("lock should be removed: %s", err)
+	}
+
+	// It can be locked again once unlocked
+	l, err = LockLocal(path, "bob@host")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	l.Unlock()
+}
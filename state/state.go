@@ -0,0 +1,68 @@
+// The state package provides ways to store and retrieve the Terraform
+// state, such as in a local file, at an HTTP URL, or in memory.
+//
+// Commands work with any implementation of State, so tests and other
+// tooling can run Terraform workflows without touching the filesystem
+// by using InmemState.
+package state
+
+import (
+	"os"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// State is the collection of all the state interfaces.
+type State interface {
+	StateReader
+	StateWriter
+	StateRefresher
+	StatePersister
+}
+
+// StateReader is the interface for things that can return the state.
+// The state may be nil if there is no state yet.
+type StateReader interface {
+	State() *terraform.State
+}
+
+// StateWriter is the interface that must be implemented by something
+// that can write the state. Writing the state only changes the state in
+// memory, and PersistState must be called to store it.
+type StateWriter interface {
+	WriteState(*terraform.State) error
+}
+
+// StateRefresher is the interface that is implemented by something that
+// can load the state. Loading the state replaces the state in memory.
+type StateRefresher interface {
+	RefreshState() error
+}
+
+// StatePersister is implemented to store the state in memory somewhere
+// durable.
+type StatePersister interface {
+	PersistState() error
+}
+
+// StateLocker is implemented by storages that can be locked, so that
+// only one run of Terraform changes the state at a time. Info describes
+// who is locking the state, and is shown to anyone else who tries to
+// lock it.
+//
+// ErrLockNotSupported is returned by Lock if the storage turns out not
+// to support locking.
+type StateLocker interface {
+	Lock(info string) error
+	Unlock() error
+}
+
+// envDefault returns v, or the value of the environment variable if v
+// is empty.
+func envDefault(v, env string) string {
+	if v != "" {
+		return v
+	}
+
+	return os.Getenv(env)
+}
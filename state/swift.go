@@ -0,0 +1,307 @@
+package state
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// SwiftState stores the state as an object in OpenStack Swift. It
+// authenticates with the v2 identity API (Keystone) and uses the
+// object-store endpoint of the region from the service catalog.
+//
+// The state is locked by creating an object next to it, with the
+// LockExtension, only if it doesn't exist yet.
+type SwiftState struct {
+	// Container and Object are where the state is stored. The container
+	// is created when the state is first persisted or locked.
+	Container string
+	Object    string
+
+	// The credentials to authenticate with. Each defaults to the
+	// environment variable that the OpenStack clients use: OS_AUTH_URL,
+	// OS_USERNAME, OS_PASSWORD, OS_TENANT_NAME and OS_REGION_NAME. If
+	// Region is empty, the first object-store endpoint is used.
+	AuthURL    string
+	Username   string
+	Password   string
+	TenantName string
+	Region     string
+
+	// Client is the HTTP client to use. Defaults to http.DefaultClient.
+	Client *http.Client
+
+	state      *terraform.State
+	token      string
+	storageURL string
+}
+
+func (s *SwiftState) State() *terraform.State {
+	return s.state
+}
+
+// RefreshState reads the state from the object. If the object doesn't
+// exist, the state is nil, since there is no state yet.
+func (s *SwiftState) RefreshState() error {
+	resp, err := s.request("GET", s.Object, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNoContent, http.StatusNotFound:
+		s.state = nil
+		return nil
+	default:
+		return fmt.Errorf(
+			"unexpected status reading state from %s: %s", s.location(), resp.Status)
+	}
+
+	state, err := terraform.ReadState(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	s.state = state
+	return nil
+}
+
+func (s *SwiftState) WriteState(state *terraform.State) error {
+	s.state = state
+	return nil
+}
+
+// PersistState uploads the state to the object, creating the container
+// if needed. It increments its serial and gives it a lineage like the
+// other storages do.
+func (s *SwiftState) PersistState() error {
+	if s.state == nil {
+		s.state = new(terraform.State)
+	}
+	s.state.Serial++
+	if err := s.state.InitLineage(); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := terraform.WriteState(s.state, &buf); err != nil {
+		return err
+	}
+
+	if err := s.createContainer(); err != nil {
+		return err
+	}
+
+	resp, err := s.request("PUT", s.Object, &buf, map[string]string{
+		"Content-Type": "application/json",
+	})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf(
+			"unexpected status writing state to %s: %s", s.location(), resp.Status)
+	}
+
+	return nil
+}
+
+// Lock creates the lock object with the info as its content. If the
+// object already exists, the state is locked by whoever's info it
+// holds.
+func (s *SwiftState) Lock(info string) error {
+	if err := s.createContainer(); err != nil {
+		return err
+	}
+
+	lockObject := s.Object + LockExtension
+	resp, err := s.request("PUT", lockObject, strings.NewReader(info),
+		map[string]string{"If-None-Match": "*"})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusPreconditionFailed:
+		var holder []byte
+		resp, err := s.request("GET", lockObject, nil, nil)
+		if err == nil {
+			holder, _ = ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+		}
+
+		return fmt.Errorf(
+			"the state %s is locked by: %s\n\n"+
+				"If no other run of Terraform is using it, delete the object %s.",
+			s.location(), strings.TrimSpace(string(holder)), lockObject)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return fmt.Errorf(
+			"unexpected status locking state at %s: %s", s.location(), resp.Status)
+	}
+
+	return nil
+}
+
+// Unlock deletes the lock object.
+func (s *SwiftState) Unlock() error {
+	resp, err := s.request("DELETE", s.Object+LockExtension, nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf(
+			"unexpected status unlocking state at %s: %s", s.location(), resp.Status)
+	}
+
+	return nil
+}
+
+// createContainer creates the container. Creating a container that
+// exists already does nothing.
+func (s *SwiftState) createContainer() error {
+	resp, err := s.request("PUT", "", nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf(
+			"unexpected status creating container %s: %s", s.Container, resp.Status)
+	}
+
+	return nil
+}
+
+// request sends a request for an object of the container, or for the
+// container itself if object is empty, authenticating first if needed.
+func (s *SwiftState) request(
+	method, object string,
+	body io.Reader,
+	headers map[string]string) (*http.Response, error) {
+	if s.token == "" {
+		if err := s.authenticate(); err != nil {
+			return nil, err
+		}
+	}
+
+	u := strings.TrimRight(s.storageURL, "/") + "/" + s.Container
+	if object != "" {
+		u += "/" + object
+	}
+
+	req, err := http.NewRequest(method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Auth-Token", s.token)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	return s.client().Do(req)
+}
+
+// authenticate gets a token from the identity API, along with the URL
+// of the object store.
+func (s *SwiftState) authenticate() error {
+	authURL := envDefault(s.AuthURL, "OS_AUTH_URL")
+	if authURL == "" {
+		return fmt.Errorf("the Swift state requires an auth URL, such as in OS_AUTH_URL")
+	}
+
+	var auth struct {
+		Auth struct {
+			PasswordCredentials struct {
+				Username string `json:"username"`
+				Password string `json:"password"`
+			} `json:"passwordCredentials"`
+			TenantName string `json:"tenantName,omitempty"`
+		} `json:"auth"`
+	}
+	auth.Auth.PasswordCredentials.Username = envDefault(s.Username, "OS_USERNAME")
+	auth.Auth.PasswordCredentials.Password = envDefault(s.Password, "OS_PASSWORD")
+	auth.Auth.TenantName = envDefault(s.TenantName, "OS_TENANT_NAME")
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(&auth); err != nil {
+		return err
+	}
+
+	resp, err := s.client().Post(
+		strings.TrimRight(authURL, "/")+"/tokens", "application/json", &buf)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf(
+			"error authenticating with %s: %s", authURL, resp.Status)
+	}
+
+	var result struct {
+		Access struct {
+			Token struct {
+				ID string `json:"id"`
+			} `json:"token"`
+			ServiceCatalog []struct {
+				Type      string `json:"type"`
+				Endpoints []struct {
+					Region    string `json:"region"`
+					PublicURL string `json:"publicURL"`
+				} `json:"endpoints"`
+			} `json:"serviceCatalog"`
+		} `json:"access"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("error reading authentication response: %s", err)
+	}
+
+	region := envDefault(s.Region, "OS_REGION_NAME")
+	for _, service := range result.Access.ServiceCatalog {
+		if service.Type != "object-store" {
+			continue
+		}
+
+		for _, e := range service.Endpoints {
+			if region == "" || e.Region == region {
+				s.token = result.Access.Token.ID
+				s.storageURL = e.PublicURL
+				return nil
+			}
+		}
+	}
+
+	if region != "" {
+		return fmt.Errorf("no object-store endpoint in region %s", region)
+	}
+	return fmt.Errorf("no object-store endpoint in the service catalog")
+}
+
+func (s *SwiftState) location() string {
+	return s.Container + "/" + s.Object
+}
+
+func (s *SwiftState) client() *http.Client {
+	if s.Client != nil {
+		return s.Client
+	}
+
+	return http.DefaultClient
+}
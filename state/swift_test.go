@@ -0,0 +1,220 @@
+package state
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestSwiftState(t *testing.T) {
+	var buf bytes.Buffer
+	if err := terraform.WriteState(TestStateInitial(), &buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	ts := testSwiftServer(t, map[string][]byte{
+		"/terraform/terraform.tfstate": buf.Bytes(),
+	})
+	defer ts.Close()
+
+	TestState(t, testSwiftState(ts))
+}
+
+func TestSwiftState_notFound(t *testing.T) {
+	ts := testSwiftServer(t, nil)
+	defer ts.Close()
+
+	s := testSwiftState(ts)
+	if err := s.RefreshState(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if s.State() != nil {
+		t.Fatalf("bad: %#v", s.State())
+	}
+}
+
+func TestSwiftState_badCredentials(t *testing.T) {
+	ts := testSwiftServer(t, nil)
+	defer ts.Close()
+
+	s := testSwiftState(ts)
+	s.Password = "wrong"
+	if err := s.RefreshState(); err == nil {
+		t.Fatal("should error")
+	}
+}
+
+func TestSwiftState_region(t *testing.T) {
+	ts := testSwiftServer(t, nil)
+	defer ts.Close()
+
+	s := testSwiftState(ts)
+	s.Region = "nowhere"
+	err := s.RefreshState()
+	if err == nil || !strings.Contains(err.Error(), "nowhere") {
+		t.Fatalf("bad: %v", err)
+	}
+}
+
+func TestSwiftState_lock(t *testing.T) {
+	ts := testSwiftServer(t, nil)
+	defer ts.Close()
+
+	s := testSwiftState(ts)
+	if err := s.Lock("alice@host"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// The state can't be locked twice
+	other := testSwiftState(ts)
+	err := other.Lock("bob@host")
+	if err == nil {
+		t.Fatal("should error")
+	}
+	if !strings.Contains(err.Error(), "alice@host") {
+		t.Fatalf("bad: %s", err)
+	}
+
+	if err := s.Unlock(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := other.Lock("bob@host"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestSwiftState_impl(t *testing.T) {
+	var _ State = new(SwiftState)
+	var _ StateLocker = new(SwiftState)
+}
+
+func testSwiftState(ts *httptest.Server) *SwiftState {
+	return &SwiftState{
+		Container:  "terraform",
+		Object:     "terraform.tfstate",
+		AuthURL:    ts.URL + "/v2.0",
+		Username:   "alice",
+		Password:   "secret",
+		TenantName: "infra",
+		Region:     "RegionOne",
+	}
+}
+
+// testSwiftServer starts a server that implements the parts of the
+// identity and object storage APIs that SwiftState uses, with the given
+// objects stored by their path.
+func testSwiftServer(t *testing.T, objects map[string][]byte) *httptest.Server {
+	var lock sync.Mutex
+	if objects == nil {
+		objects = make(map[string][]byte)
+	}
+	containers := make(map[string]bool)
+	for k, _ := range objects {
+		containers[strings.SplitN(k[1:], "/", 2)[0]] = true
+	}
+
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			if r.URL.Path == "/v2.0/tokens" {
+				var auth struct {
+					Auth struct {
+						PasswordCredentials struct {
+							Username string `json:"username"`
+							Password string `json:"password"`
+						} `json:"passwordCredentials"`
+						TenantName string `json:"tenantName"`
+					} `json:"auth"`
+				}
+				json.NewDecoder(r.Body).Decode(&auth)
+				creds := auth.Auth.PasswordCredentials
+				if creds.Username != "alice" || creds.Password != "secret" ||
+					auth.Auth.TenantName != "infra" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+
+				fmt.Fprintf(w, `{"access": {
+					"token": {"id": "token"},
+					"serviceCatalog": [
+						{"type": "compute", "endpoints": [
+							{"region": "RegionOne", "publicURL": "%s/compute"}]},
+						{"type": "object-store", "endpoints": [
+							{"region": "RegionOne", "publicURL": "%s/swift"}]}
+					]}}`, ts.URL, ts.URL)
+				return
+			}
+
+			if r.Header.Get("X-Auth-Token") != "token" ||
+				!strings.HasPrefix(r.URL.Path, "/swift/") {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			path := strings.TrimPrefix(r.URL.Path, "/swift")
+			parts := strings.SplitN(path[1:], "/", 2)
+			if len(parts) == 1 {
+				// The container
+				if r.Method != "PUT" {
+					w.WriteHeader(http.StatusMethodNotAllowed)
+					return
+				}
+
+				containers[parts[0]] = true
+				w.WriteHeader(http.StatusCreated)
+				return
+			}
+
+			switch r.Method {
+			case "GET":
+				data, ok := objects[path]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				w.Write(data)
+			case "PUT":
+				if !containers[parts[0]] {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				if _, ok := objects[path]; ok && r.Header.Get("If-None-Match") == "*" {
+					w.WriteHeader(http.StatusPreconditionFailed)
+					return
+				}
+
+				body, err := ioutil.ReadAll(r.Body)
+				if err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+
+				objects[path] = body
+				w.WriteHeader(http.StatusCreated)
+			case "DELETE":
+				if _, ok := objects[path]; !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				delete(objects, path)
+				w.WriteHeader(http.StatusNoContent)
+			default:
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		}))
+
+	return ts
+}
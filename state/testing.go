@@ -0,0 +1,92 @@
+package state
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// TestState is a helper for testing state implementations. It is
+// expected that the given implementation is pre-loaded with the
+// TestStateInitial state.
+func TestState(t *testing.T, s interface{}) {
+	reader, ok := s.(StateReader)
+	if !ok {
+		t.Fatalf("must at least be a StateReader")
+	}
+
+	// If it implements refresh, refresh
+	if rs, ok := s.(StateRefresher); ok {
+		if err := rs.RefreshState(); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	// current will track our current state
+	current := TestStateInitial()
+
+	// Check that the initial state is correct
+	if state := reader.State(); !reflect.DeepEqual(state.Resources, current.Resources) {
+		t.Fatalf("not initial: %#v\n\n%#v", state, current)
+	}
+
+	// Write a new state and verify that we have it
+	if ws, ok := s.(StateWriter); ok {
+		current.Outputs = map[string]string{"bar": "baz"}
+		if err := ws.WriteState(current); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		if actual := reader.State(); !reflect.DeepEqual(actual.Outputs, current.Outputs) {
+			t.Fatalf("bad: %#v\n\n%#v", actual, current)
+		}
+	}
+
+	// Test persistence
+	if ps, ok := s.(StatePersister); ok {
+		serial := current.Serial
+		if err := ps.PersistState(); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		if actual := reader.State(); actual.Serial != serial+1 {
+			t.Fatalf("serial should be incremented: %d", actual.Serial)
+		}
+		lineage := reader.State().Lineage
+		if lineage == "" {
+			t.Fatal("lineage should be set")
+		}
+
+		// Refresh if we got it
+		if rs, ok := s.(StateRefresher); ok {
+			if err := rs.RefreshState(); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+		}
+
+		actual := reader.State()
+		if !reflect.DeepEqual(actual.Outputs, current.Outputs) {
+			t.Fatalf("bad: %#v\n\n%#v", actual, current)
+		}
+		if actual.Serial != serial+1 {
+			t.Fatalf("bad serial: %d", actual.Serial)
+		}
+		if actual.Lineage != lineage {
+			t.Fatalf("bad lineage: %s", actual.Lineage)
+		}
+	}
+}
+
+// TestStateInitial is the initial state that a State should have
+// for TestState.
+func TestStateInitial() *terraform.State {
+	return &terraform.State{
+		Resources: map[string]*terraform.ResourceState{
+			"test_instance.foo": &terraform.ResourceState{
+				ID:   "bar",
+				Type: "test_instance",
+			},
+		},
+	}
+}
@@ -0,0 +1,202 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// WebDAVState stores the state at a URL of a generic artifact store or
+// WebDAV server, such as Artifactory or Apache with mod_dav. The state
+// is read with a GET of Address and written with a PUT to it.
+//
+// If the server returns an ETag with the state, the state is written
+// with If-Match, so that it isn't written over a state that was changed
+// since it was read.
+//
+// The state is locked by creating the file at Address with the
+// LockExtension with If-None-Match, and unlocked by deleting it.
+type WebDAVState struct {
+	Address string
+
+	// The credentials to authenticate with: a username and password for
+	// basic authentication, or a bearer token. Each defaults to an
+	// environment variable: TF_WEBDAV_USERNAME, TF_WEBDAV_PASSWORD and
+	// TF_WEBDAV_TOKEN.
+	Username string
+	Password string
+	Token    string
+
+	// Client is the HTTP client to use. Defaults to http.DefaultClient.
+	Client *http.Client
+
+	state *terraform.State
+	etag  string
+}
+
+func (s *WebDAVState) State() *terraform.State {
+	return s.state
+}
+
+// RefreshState reads the state from Address. If the server responds
+// with 404 or 204, the state is nil, since there is no state yet.
+func (s *WebDAVState) RefreshState() error {
+	resp, err := s.request("GET", s.Address, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNoContent, http.StatusNotFound:
+		s.state = nil
+		s.etag = ""
+		return nil
+	default:
+		return fmt.Errorf(
+			"unexpected status reading state from %s: %s", s.Address, resp.Status)
+	}
+
+	state, err := terraform.ReadState(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	s.state = state
+	s.etag = resp.Header.Get("ETag")
+	return nil
+}
+
+func (s *WebDAVState) WriteState(state *terraform.State) error {
+	s.state = state
+	return nil
+}
+
+// PersistState puts the state at Address, incrementing its serial and
+// giving it a lineage like the other storages do.
+func (s *WebDAVState) PersistState() error {
+	if s.state == nil {
+		s.state = new(terraform.State)
+	}
+	s.state.Serial++
+	if err := s.state.InitLineage(); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := terraform.WriteState(s.state, &buf); err != nil {
+		return err
+	}
+
+	headers := map[string]string{"Content-Type": "application/json"}
+	if s.etag != "" {
+		headers["If-Match"] = s.etag
+	}
+
+	resp, err := s.request("PUT", s.Address, &buf, headers)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode == http.StatusPreconditionFailed {
+		return fmt.Errorf(
+			"the state at %s was changed since it was read. Refresh\n"+
+				"the state and try again.", s.Address)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf(
+			"unexpected status writing state to %s: %s", s.Address, resp.Status)
+	}
+
+	// The next write can only be checked against the new ETag, which
+	// not all servers return with a PUT.
+	s.etag = resp.Header.Get("ETag")
+	return nil
+}
+
+// Lock creates the lock file with the info as its content. If the file
+// already exists, the state is locked by whoever's info it holds.
+func (s *WebDAVState) Lock(info string) error {
+	lockAddress := s.Address + LockExtension
+	resp, err := s.request("PUT", lockAddress, strings.NewReader(info),
+		map[string]string{"If-None-Match": "*"})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusPreconditionFailed:
+		var holder []byte
+		resp, err := s.request("GET", lockAddress, nil, nil)
+		if err == nil {
+			holder, _ = ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+		}
+
+		return fmt.Errorf(
+			"the state %s is locked by: %s\n\n"+
+				"If no other run of Terraform is using it, delete %s.",
+			s.Address, strings.TrimSpace(string(holder)), lockAddress)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return fmt.Errorf(
+			"unexpected status locking state at %s: %s", s.Address, resp.Status)
+	}
+
+	return nil
+}
+
+// Unlock deletes the lock file.
+func (s *WebDAVState) Unlock() error {
+	resp, err := s.request("DELETE", s.Address+LockExtension, nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf(
+			"unexpected status unlocking state at %s: %s", s.Address, resp.Status)
+	}
+
+	return nil
+}
+
+func (s *WebDAVState) request(
+	method, u string,
+	body io.Reader,
+	headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequest(method, u, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if token := envDefault(s.Token, "TF_WEBDAV_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	} else if username := envDefault(s.Username, "TF_WEBDAV_USERNAME"); username != "" {
+		req.SetBasicAuth(username, envDefault(s.Password, "TF_WEBDAV_PASSWORD"))
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	return s.client().Do(req)
+}
+
+func (s *WebDAVState) client() *http.Client {
+	if s.Client != nil {
+		return s.Client
+	}
+
+	return http.DefaultClient
+}
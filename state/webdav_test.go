@@ -0,0 +1,197 @@
+package state
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestWebDAVState(t *testing.T) {
+	var buf bytes.Buffer
+	if err := terraform.WriteState(TestStateInitial(), &buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	ts := httptest.NewServer(testWebDAVHandler(map[string][]byte{
+		"/terraform.tfstate": buf.Bytes(),
+	}))
+	defer ts.Close()
+
+	TestState(t, &WebDAVState{
+		Address:  ts.URL + "/terraform.tfstate",
+		Username: "alice",
+		Password: "secret",
+	})
+}
+
+func TestWebDAVState_token(t *testing.T) {
+	ts := httptest.NewServer(testWebDAVHandler(nil))
+	defer ts.Close()
+
+	s := &WebDAVState{Address: ts.URL + "/terraform.tfstate", Token: "token"}
+	if err := s.RefreshState(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if s.State() != nil {
+		t.Fatalf("bad: %#v", s.State())
+	}
+	if err := s.PersistState(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestWebDAVState_unauthorized(t *testing.T) {
+	ts := httptest.NewServer(testWebDAVHandler(nil))
+	defer ts.Close()
+
+	s := &WebDAVState{
+		Address:  ts.URL + "/terraform.tfstate",
+		Username: "alice",
+		Password: "wrong",
+	}
+	if err := s.RefreshState(); err == nil {
+		t.Fatal("should error")
+	}
+}
+
+func TestWebDAVState_ifMatch(t *testing.T) {
+	var buf bytes.Buffer
+	if err := terraform.WriteState(TestStateInitial(), &buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	ts := httptest.NewServer(testWebDAVHandler(map[string][]byte{
+		"/terraform.tfstate": buf.Bytes(),
+	}))
+	defer ts.Close()
+
+	address := ts.URL + "/terraform.tfstate"
+	a := &WebDAVState{Address: address, Token: "token"}
+	b := &WebDAVState{Address: address, Token: "token"}
+	if err := a.RefreshState(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := b.RefreshState(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// The first write changes the state that the second was read from
+	if err := a.PersistState(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	err := b.PersistState()
+	if err == nil || !strings.Contains(err.Error(), "was changed") {
+		t.Fatalf("bad: %v", err)
+	}
+
+	// Writing again after a write uses the new ETag
+	if err := a.PersistState(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestWebDAVState_lock(t *testing.T) {
+	ts := httptest.NewServer(testWebDAVHandler(nil))
+	defer ts.Close()
+
+	s := &WebDAVState{Address: ts.URL + "/terraform.tfstate", Token: "token"}
+	if err := s.Lock("alice@host"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// The state can't be locked twice
+	other := &WebDAVState{Address: ts.URL + "/terraform.tfstate", Token: "token"}
+	err := other.Lock("bob@host")
+	if err == nil {
+		t.Fatal("should error")
+	}
+	if !strings.Contains(err.Error(), "alice@host") {
+		t.Fatalf("bad: %s", err)
+	}
+
+	if err := s.Unlock(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := other.Lock("bob@host"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestWebDAVState_impl(t *testing.T) {
+	var _ State = new(WebDAVState)
+	var _ StateLocker = new(WebDAVState)
+}
+
+// testWebDAVHandler returns a handler that stores files by their path
+// like a WebDAV server, with ETags and conditional requests. Requests
+// must authenticate as alice, or with the bearer token "token".
+func testWebDAVHandler(files map[string][]byte) http.Handler {
+	var lock sync.Mutex
+	if files == nil {
+		files = make(map[string][]byte)
+	}
+	etag := func(data []byte) string {
+		return fmt.Sprintf(`"%x"`, sha1.Sum(data))
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		username, password, ok := r.BasicAuth()
+		if !(ok && username == "alice" && password == "secret") &&
+			r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		data, exists := files[r.URL.Path]
+		switch r.Method {
+		case "GET":
+			if !exists {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			w.Header().Set("ETag", etag(data))
+			w.Write(data)
+		case "PUT":
+			if exists && r.Header.Get("If-None-Match") == "*" {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+			if m := r.Header.Get("If-Match"); m != "" && (!exists || m != etag(data)) {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			files[r.URL.Path] = body
+			w.Header().Set("ETag", etag(body))
+			w.WriteHeader(http.StatusCreated)
+		case "DELETE":
+			if !exists {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			delete(files, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+}
@@ -3,10 +3,12 @@ package terraform
 import (
 	"fmt"
 	"log"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/depgraph"
@@ -29,14 +31,22 @@ type Context struct {
 	state        *State
 	providers    map[string]ResourceProviderFactory
 	provisioners map[string]ResourceProvisionerFactory
+	secrets      map[string]SecretBackend
 	variables    map[string]string
 	defaultVars  map[string]string
+	git          map[string]string
 
 	l     sync.Mutex    // Lock acquired during any task
 	parCh chan struct{} // Semaphore used to limit parallelism
 	sl    sync.RWMutex  // Lock acquired to R/W internal data
 	runCh <-chan struct{}
 	sh    *stopHook
+
+	stoppers  map[stopper]struct{} // Providers and provisioners in use
+	stoppersL sync.Mutex           // Lock acquired to R/W stoppers
+
+	secretVals map[string]string // Secrets read so far, by key
+	secretL    sync.Mutex        // Lock acquired to R/W secretVals
 }
 
 // ContextOpts are the user-creatable configuration structure to create
@@ -49,7 +59,13 @@ type ContextOpts struct {
 	State        *State
 	Providers    map[string]ResourceProviderFactory
 	Provisioners map[string]ResourceProvisionerFactory
+	Secrets      map[string]SecretBackend
 	Variables    map[string]string
+
+	// Git is the git revision of the configuration, keyed by the fields
+	// in config.GitFields. It is nil if the configuration isn't in a git
+	// repository.
+	Git map[string]string
 }
 
 // NewContext creates a new context.
@@ -90,8 +106,10 @@ func NewContext(opts *ContextOpts) *Context {
 		state:        opts.State,
 		providers:    opts.Providers,
 		provisioners: opts.Provisioners,
+		secrets:      opts.Secrets,
 		variables:    opts.Variables,
 		defaultVars:  defaultVars,
+		git:          opts.Git,
 
 		parCh: parCh,
 		sh:    sh,
@@ -113,11 +131,25 @@ func (c *Context) Apply() (*State, error) {
 		Providers:    c.providers,
 		Provisioners: c.provisioners,
 		State:        c.state,
+		Variables:    c.userVars(),
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	// Let the hooks know that the apply is starting. If one of them
+	// halts, nothing is applied.
+	for _, h := range c.hooks {
+		a, err := h.PreApplyAll(c.state, c.diff)
+		if err != nil {
+			return nil, err
+		}
+		if a == HookActionHalt {
+			log.Printf("[INFO] Apply halted by a hook")
+			return c.state, nil
+		}
+	}
+
 	// Set our state right away. No matter what, this IS our new state,
 	// even if there is an error below.
 	c.state = c.state.deepcopy()
@@ -130,6 +162,20 @@ func (c *Context) Apply() (*State, error) {
 	// Prune the state so that we have as clean a state as possible
 	c.state.prune()
 
+	// Missing resources that were created again or forgotten are no
+	// longer missing. If creating one failed, it's still missing.
+	if c.diff != nil {
+		for k, rd := range c.diff.Resources {
+			if !rd.Missing {
+				continue
+			}
+
+			if _, ok := c.state.Resources[k]; ok || rd.Empty() {
+				delete(c.state.Missing, k)
+			}
+		}
+	}
+
 	// If we have no errors, then calculate the outputs if we have any
 	if err == nil && len(c.config.Outputs) > 0 && len(c.state.Resources) > 0 {
 		c.state.Outputs = make(map[string]string)
@@ -139,12 +185,38 @@ func (c *Context) Apply() (*State, error) {
 			}
 
 			c.state.Outputs[o.Name] = o.RawConfig.Config()["value"].(string)
+			if o.Sensitive {
+				if c.state.SensitiveOutputs == nil {
+					c.state.SensitiveOutputs = make(map[string]struct{})
+				}
+
+				c.state.SensitiveOutputs[o.Name] = struct{}{}
+			}
 		}
 	}
 
+	for _, h := range c.hooks {
+		h.PostApplyAll(c.state, err)
+	}
+
 	return c.state, err
 }
 
+// SensitiveValues returns the values of the variables that are marked
+// sensitive, which should be hidden from output and logs.
+//
+// Sensitive outputs aren't included since they're only known after an
+// apply. They are marked in State.SensitiveOutputs instead.
+func (c *Context) SensitiveValues() []string {
+	return sensitiveValues(c.config, c.variables)
+}
+
+// Git returns the git revision of the configuration, or nil if the
+// configuration isn't in a git repository.
+func (c *Context) Git() map[string]string {
+	return c.git
+}
+
 // Graph returns the graph for this context.
 func (c *Context) Graph() (*depgraph.Graph, error) {
 	return c.graph()
@@ -166,6 +238,7 @@ func (c *Context) Plan(opts *PlanOpts) (*Plan, error) {
 		Providers:    c.providers,
 		Provisioners: c.provisioners,
 		State:        c.state,
+		Variables:    c.userVars(),
 	})
 	if err != nil {
 		return nil, err
@@ -174,9 +247,25 @@ func (c *Context) Plan(opts *PlanOpts) (*Plan, error) {
 	p := &Plan{
 		Config: c.config,
 		Vars:   c.variables,
+		Git:    c.git,
 		State:  c.state,
 	}
 
+	var missing map[string]string
+	if c.state != nil {
+		missing = c.state.Missing
+	}
+
+	// Only resources that are missing can be forgotten
+	if opts != nil {
+		for _, k := range opts.Forget {
+			if _, ok := missing[k]; !ok {
+				return nil, fmt.Errorf(
+					"Can't forget %s: it wasn't deleted outside of Terraform", k)
+			}
+		}
+	}
+
 	var walkFn depgraph.WalkFunc
 
 	if opts != nil && opts.Destroy {
@@ -193,15 +282,33 @@ func (c *Context) Plan(opts *PlanOpts) (*Plan, error) {
 			c.state = old
 		}()
 
-		walkFn = c.planWalkFn(p)
+		walkFn = c.planWalkFn(p, opts)
 	}
 
 	// Walk and run the plan
 	err = g.Walk(walkFn)
 
+	// Missing resources that aren't created again, because they're no
+	// longer in the configuration or everything is being destroyed,
+	// are removed from the state.
+	for k, _ := range missing {
+		if _, ok := p.Diff.Resources[k]; !ok {
+			p.Diff.Resources[k] = &ResourceDiff{Missing: true}
+		}
+	}
+
 	// Update the diff so that our context is up-to-date
 	c.diff = p.Diff
 
+	// Credentials are never stored in the plan
+	planConfig, planVars, cerr := writeOnlyConfig(
+		p.Config, p.Vars, graphWriteOnly(g))
+	if cerr != nil && err == nil {
+		err = cerr
+	}
+	p.Config = planConfig
+	p.Vars = planVars
+
 	return p, err
 }
 
@@ -220,6 +327,7 @@ func (c *Context) Refresh() (*State, error) {
 		Providers:    c.providers,
 		Provisioners: c.provisioners,
 		State:        c.state,
+		Variables:    c.userVars(),
 	})
 	if err != nil {
 		return c.state, err
@@ -232,7 +340,83 @@ func (c *Context) Refresh() (*State, error) {
 	return c.state, err
 }
 
-// Stop stops the running task.
+// ListResources lists the existing resources of the given type that match
+// the filters, so that they can be brought under the management of
+// Terraform. The provider is configured with the provider configuration
+// for the type, if there is one, just like it is for the other
+// operations.
+//
+// ErrListNotSupported is returned if the provider can't list resources
+// of the type.
+func (c *Context) ListResources(
+	t string, filters map[string]string) ([]*ResourceState, error) {
+	v := c.acquireRun()
+	defer c.releaseRun(v)
+
+	var provider ResourceProvider
+	for _, prefix := range matchingPrefixes(t, c.providers) {
+		p, err := c.providers[prefix]()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"Error instantiating resource provider for "+
+					"prefix %s: %s", prefix, err)
+		}
+
+		if ProviderSatisfies(p, t) {
+			provider = p
+			break
+		}
+	}
+	if provider == nil {
+		return nil, fmt.Errorf(
+			"Resource provider not found for resource type '%s'", t)
+	}
+
+	// A provider that can't list anything isn't configured, which could
+	// otherwise fail first with a less helpful error. Plugins that can't
+	// report their capabilities are asked anyway.
+	caps := provider.Capabilities()
+	if !caps.Unknown && !caps.ListResources {
+		return nil, ErrListNotSupported
+	}
+
+	var raw *config.RawConfig
+	if c.config != nil {
+		name := config.ProviderConfigName(t, c.config.ProviderConfigs)
+		for _, pc := range c.config.ProviderConfigs {
+			if pc.Name == name {
+				raw = pc.RawConfig
+				break
+			}
+		}
+	}
+	if err := c.readSecrets(raw); err != nil {
+		return nil, err
+	}
+
+	rc := NewResourceConfig(raw)
+	rc.interpolate(c)
+	if err := provider.Configure(rc.withEnv(provider.ConfigSchema())); err != nil {
+		return nil, err
+	}
+	if err := provider.ValidateCredentials(); err != nil {
+		return nil, fmt.Errorf("Provider credentials are invalid: %s", err)
+	}
+
+	result, err := provider.ListResources(t, filters)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range result {
+		s.Type = t
+	}
+
+	return result, nil
+}
+
+// Stop stops the running task. The providers and provisioners in use are
+// told to stop too, so that the calls they're making are canceled rather
+// than left to finish.
 //
 // Stop will block until the task completes.
 func (c *Context) Stop() {
@@ -247,6 +431,7 @@ func (c *Context) Stop() {
 
 	// Tell the hook we want to stop
 	c.sh.Stop()
+	c.stopAll()
 
 	// Wait for us to stop
 	c.l.Unlock()
@@ -255,45 +440,64 @@ func (c *Context) Stop() {
 
 // Validate validates the configuration and returns any warnings or errors.
 func (c *Context) Validate() ([]string, []error) {
-	var rerr *multierror.Error
+	var warns []string
+	var errs []error
+	for _, d := range c.Diagnostics() {
+		if d.Severity == config.DiagWarning {
+			warns = append(warns, d.Error())
+		} else {
+			errs = append(errs, d)
+		}
+	}
+
+	return warns, errs
+}
+
+// Diagnostics validates like Validate, but returns the errors and
+// warnings as diagnostics, with where in the configuration they are when
+// that is known. The errors come first.
+func (c *Context) Diagnostics() []*config.Diagnostic {
+	var errs []*config.Diagnostic
 
 	// Validate the configuration itself
-	if err := c.config.Validate(); err != nil {
-		rerr = multierror.ErrorAppend(rerr, err)
-	}
+	errs = append(errs, config.Diagnostics(c.config.Validate(), "config")...)
 
 	// Validate the user variables
-	if errs := smcUserVariables(c.config, c.variables); len(errs) > 0 {
-		rerr = multierror.ErrorAppend(rerr, errs...)
+	for _, err := range smcUserVariables(c.config, c.variables) {
+		errs = append(errs, config.Diagnostics(err, "variable_value")...)
+	}
+
+	// Validate the secrets
+	for _, err := range smcSecrets(c.config, c.secrets) {
+		errs = append(errs, config.Diagnostics(err, "secret")...)
 	}
 
 	// Validate the graph
 	g, err := c.graph()
 	if err != nil {
-		rerr = multierror.ErrorAppend(rerr, fmt.Errorf(
-			"Error creating graph: %s", err))
+		errs = append(errs, config.Diagnostics(fmt.Errorf(
+			"Error creating graph: %s", err), "graph")...)
 	}
 
-	// Walk the graph and validate all the configs
-	var warns []string
-	var errs []error
+	// Walk the graph and validate all the configs, starting with the
+	// warnings for the configuration itself.
+	warns := c.config.WarningDiagnostics()
 	if g != nil {
-		err = g.Walk(c.validateWalkFn(&warns, &errs))
+		var walkErrs []*config.Diagnostic
+		err = g.Walk(c.validateWalkFn(&warns, &walkErrs))
 		if err != nil {
-			rerr = multierror.ErrorAppend(rerr, fmt.Errorf(
-				"Error validating resources in graph: %s", err))
-		}
-		if len(errs) > 0 {
-			rerr = multierror.ErrorAppend(rerr, errs...)
+			errs = append(errs, config.Diagnostics(fmt.Errorf(
+				"Error validating resources in graph: %s", err), "graph")...)
 		}
-	}
 
-	errs = nil
-	if rerr != nil && len(rerr.Errors) > 0 {
-		errs = rerr.Errors
+		// Resources are validated concurrently, so sort what we found
+		// to always show it in the same order.
+		sort.Sort(diagnosticSort(warns))
+		sort.Sort(diagnosticSort(walkErrs))
+		errs = append(errs, walkErrs...)
 	}
 
-	return warns, errs
+	return append(errs, warns...)
 }
 
 // computeVars takes the State and given RawConfig and processes all
@@ -327,6 +531,22 @@ func (c *Context) computeVars(raw *config.RawConfig) error {
 			}
 
 			vs[n] = attr
+		case *config.SecretVariable:
+			val, err := c.computeSecretVariable(v)
+			if err != nil {
+				return err
+			}
+
+			vs[n] = val
+		case *config.GitVariable:
+			val, ok := c.git[v.Field]
+			if !ok {
+				return fmt.Errorf(
+					"%s: the configuration isn't in a git repository",
+					v.FullKey())
+			}
+
+			vs[n] = val
 		case *config.UserVariable:
 			val, ok := c.variables[v.Name]
 			if ok {
@@ -348,6 +568,86 @@ func (c *Context) computeVars(raw *config.RawConfig) error {
 	return raw.Interpolate(vs)
 }
 
+// userVars returns the values of the variables, including defaults,
+// keyed like "var.foo".
+func (c *Context) userVars() map[string]string {
+	result := make(map[string]string)
+	for k, v := range c.defaultVars {
+		result[k] = v
+	}
+	for k, v := range c.variables {
+		result["var."+k] = v
+	}
+
+	return result
+}
+
+// readSecrets reads all the secrets referenced in the given RawConfig.
+func (c *Context) readSecrets(raw *config.RawConfig) error {
+	if raw == nil {
+		return nil
+	}
+
+	for _, v := range raw.Variables {
+		if sv, ok := v.(*config.SecretVariable); ok {
+			if _, err := c.computeSecretVariable(sv); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// defaultTags interpolates the default tags of a provider configuration.
+func (c *Context) defaultTags(raw *config.RawConfig) (map[string]string, error) {
+	if err := c.computeVars(raw); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string)
+	for k, v := range raw.Config() {
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("default tag '%s' must be a string", k)
+		}
+
+		result[k] = s
+	}
+
+	return result, nil
+}
+
+// computeSecretVariable reads a secret from its backend. Each secret is
+// only read once for the lifetime of the context.
+func (c *Context) computeSecretVariable(
+	v *config.SecretVariable) (string, error) {
+	c.secretL.Lock()
+	defer c.secretL.Unlock()
+
+	if val, ok := c.secretVals[v.FullKey()]; ok {
+		return val, nil
+	}
+
+	b, ok := c.secrets[v.Backend]
+	if !ok {
+		return "", fmt.Errorf(
+			"%s: unknown secret backend '%s'", v.FullKey(), v.Backend)
+	}
+
+	val, err := b.Secret(v.Key)
+	if err != nil {
+		return "", fmt.Errorf("%s: error reading secret: %s", v.FullKey(), err)
+	}
+
+	if c.secretVals == nil {
+		c.secretVals = make(map[string]string)
+	}
+	c.secretVals[v.FullKey()] = val
+
+	return val, nil
+}
+
 func (c *Context) computeResourceVariable(
 	v *config.ResourceVariable) (string, error) {
 	id := v.ResourceId()
@@ -432,7 +732,7 @@ func (c *Context) computeResourceMultiVariable(
 			v.FullKey())
 	}
 
-	return strings.Join(values, ","), nil
+	return strings.Join(values, config.InterpSplitDelim), nil
 }
 
 func (c *Context) graph() (*depgraph.Graph, error) {
@@ -442,6 +742,7 @@ func (c *Context) graph() (*depgraph.Graph, error) {
 		Providers:    c.providers,
 		Provisioners: c.provisioners,
 		State:        c.state,
+		Variables:    c.userVars(),
 	})
 }
 
@@ -469,6 +770,86 @@ func (c *Context) releaseRun(ch chan<- struct{}) {
 	close(ch)
 	c.runCh = nil
 	c.sh.Reset()
+
+	c.stoppersL.Lock()
+	c.stoppers = nil
+	c.stoppersL.Unlock()
+}
+
+// stopper is a provider or a provisioner, which can be told to stop.
+type stopper interface {
+	Stop() error
+}
+
+// track records that the provider or provisioner is in use by the running
+// task, so that Stop stops it. If the task is already being stopped, it's
+// stopped right away.
+func (c *Context) track(s stopper) {
+	c.stoppersL.Lock()
+	defer c.stoppersL.Unlock()
+
+	if _, ok := c.stoppers[s]; ok {
+		return
+	}
+	if c.stoppers == nil {
+		c.stoppers = make(map[stopper]struct{})
+	}
+	c.stoppers[s] = struct{}{}
+
+	if c.sh.Stopped() {
+		go stop(s)
+	}
+}
+
+// stopAll stops the providers and provisioners in use. Each is stopped
+// in the background, since Stop can block, such as on a plugin, and the
+// task is waited on anyway.
+func (c *Context) stopAll() {
+	c.stoppersL.Lock()
+	defer c.stoppersL.Unlock()
+
+	for s := range c.stoppers {
+		go stop(s)
+	}
+}
+
+func stop(s stopper) {
+	if err := s.Stop(); err != nil {
+		log.Printf("[WARN] Error stopping %T: %s", s, err)
+	}
+}
+
+// applyResource applies the diff of the resource with its provider. If
+// the apply takes longer than the timeout of the resource, an error is
+// returned without waiting for the provider, and the resource is left in
+// the state as it was.
+func (c *Context) applyResource(
+	r *Resource, d *ResourceDiff) (*ResourceState, error) {
+	timeout := r.Timeout(d)
+	if timeout == 0 {
+		return r.Provider.Apply(r.State, d)
+	}
+
+	type applyResult struct {
+		State *ResourceState
+		Err   error
+	}
+
+	resultCh := make(chan applyResult, 1)
+	go func() {
+		rs, err := r.Provider.Apply(r.State, d)
+		resultCh <- applyResult{State: rs, Err: err}
+	}()
+
+	select {
+	case result := <-resultCh:
+		return result.State, result.Err
+	case <-time.After(timeout):
+		log.Printf("[ERROR] %s: Apply timed out after %s", r.Id, timeout)
+		return r.State, fmt.Errorf(
+			"%s: apply didn't finish within the timeout of %s",
+			r.Id, timeout)
+	}
 }
 
 func (c *Context) applyWalkFn() depgraph.WalkFunc {
@@ -491,6 +872,7 @@ func (c *Context) applyWalkFn() depgraph.WalkFunc {
 			if err != nil {
 				return err
 			}
+			r.ignoreChanges(diff)
 
 			// This should never happen because we check if Diff.Empty above.
 			// If this happened, then the diff above returned a bad diff.
@@ -535,9 +917,13 @@ func (c *Context) applyWalkFn() depgraph.WalkFunc {
 			handleHook(h.PreApply(r.Id, r.State, diff))
 		}
 
+		// Let the provider know how long it may take, since we stop
+		// waiting for it once the timeout is up
+		diff.Timeouts = r.Timeouts
+
 		// With the completed diff, apply!
 		log.Printf("[DEBUG] %s: Executing Apply", r.Id)
-		rs, applyerr := r.Provider.Apply(r.State, diff)
+		rs, applyerr := c.applyResource(r, diff)
 
 		var errs []error
 		if applyerr != nil {
@@ -561,7 +947,15 @@ func (c *Context) applyWalkFn() depgraph.WalkFunc {
 			rs.Attributes["id"] = rs.ID
 		}
 
-		for ak, av := range rs.Attributes {
+		attrKeys := make([]string, 0, len(rs.Attributes))
+		for ak, _ := range rs.Attributes {
+			attrKeys = append(attrKeys, ak)
+		}
+		sort.Strings(attrKeys)
+
+		for _, ak := range attrKeys {
+			av := rs.Attributes[ak]
+
 			// If the value is the unknown variable value, then it is an error.
 			// In this case we record the error and remove it from the state
 			if av == config.UnknownVariableValue {
@@ -653,46 +1047,18 @@ func (c *Context) applyProvisioners(r *Resource, rs *ResourceState) error {
 			return err
 		}
 
-		// Interpolate the conn info, since it may contain variables
-		connInfo := NewResourceConfig(prov.ConnInfo)
-		if err := connInfo.interpolate(c); err != nil {
+		connInfo, err := c.provisionerConnInfo(prov, origConnInfo)
+		if err != nil {
 			return err
 		}
-
-		// Merge the connection information
-		overlay := make(map[string]string)
-		if origConnInfo != nil {
-			for k, v := range origConnInfo {
-				overlay[k] = v
-			}
-		}
-		for k, v := range connInfo.Config {
-			switch vt := v.(type) {
-			case string:
-				overlay[k] = vt
-			case int64:
-				overlay[k] = strconv.FormatInt(vt, 10)
-			case int32:
-				overlay[k] = strconv.FormatInt(int64(vt), 10)
-			case int:
-				overlay[k] = strconv.FormatInt(int64(vt), 10)
-			case float32:
-				overlay[k] = strconv.FormatFloat(float64(vt), 'f', 3, 32)
-			case float64:
-				overlay[k] = strconv.FormatFloat(vt, 'f', 3, 64)
-			case bool:
-				overlay[k] = strconv.FormatBool(vt)
-			default:
-				overlay[k] = fmt.Sprintf("%v", vt)
-			}
-		}
-		rs.ConnInfo = overlay
+		rs.ConnInfo = connInfo
 
 		// Invoke the Provisioner
 		for _, h := range c.hooks {
 			handleHook(h.PreProvision(r.Id, prov.Type))
 		}
 
+		c.track(prov.Provisioner)
 		if err := prov.Provisioner.Apply(rs, prov.Config); err != nil {
 			return err
 		}
@@ -705,20 +1071,122 @@ func (c *Context) applyProvisioners(r *Resource, rs *ResourceState) error {
 	return nil
 }
 
-func (c *Context) planWalkFn(result *Plan) depgraph.WalkFunc {
+// describeProvisioners returns what the provisioners of a resource that
+// is being created would do, without running them.
+func (c *Context) describeProvisioners(r *Resource) ([]*ProvisionerDiff, error) {
+	result := make([]*ProvisionerDiff, 0, len(r.Provisioners))
+	for _, prov := range r.Provisioners {
+		if err := prov.Config.interpolate(c); err != nil {
+			return nil, err
+		}
+
+		connInfo, err := c.provisionerConnInfo(prov, r.State.ConnInfo)
+		if err != nil {
+			return nil, err
+		}
+
+		// Describe against a copy of the state so that the connection
+		// info of the real state isn't touched.
+		rs := new(ResourceState)
+		*rs = *r.State
+		rs.ConnInfo = connInfo
+
+		actions, err := prov.Provisioner.Describe(rs, prov.Config)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%s: error describing provisioner %s: %s",
+				r.Id, prov.Type, err)
+		}
+
+		result = append(result, &ProvisionerDiff{
+			Type:    prov.Type,
+			Actions: actions,
+		})
+	}
+
+	return result, nil
+}
+
+// provisionerConnInfo returns the connection info for a provisioner: the
+// connection info of the resource overlaid with the interpolated
+// connection settings of the provisioner.
+func (c *Context) provisionerConnInfo(
+	prov *ResourceProvisionerConfig,
+	orig map[string]string) (map[string]string, error) {
+	// Interpolate the conn info, since it may contain variables
+	connInfo := NewResourceConfig(prov.ConnInfo)
+	if err := connInfo.interpolate(c); err != nil {
+		return nil, err
+	}
+
+	// Merge the connection information
+	overlay := make(map[string]string)
+	for k, v := range orig {
+		overlay[k] = v
+	}
+	for k, v := range connInfo.Config {
+		switch vt := v.(type) {
+		case string:
+			overlay[k] = vt
+		case int64:
+			overlay[k] = strconv.FormatInt(vt, 10)
+		case int32:
+			overlay[k] = strconv.FormatInt(int64(vt), 10)
+		case int:
+			overlay[k] = strconv.FormatInt(int64(vt), 10)
+		case float32:
+			overlay[k] = strconv.FormatFloat(float64(vt), 'f', 3, 32)
+		case float64:
+			overlay[k] = strconv.FormatFloat(vt, 'f', 3, 64)
+		case bool:
+			overlay[k] = strconv.FormatBool(vt)
+		default:
+			overlay[k] = fmt.Sprintf("%v", vt)
+		}
+	}
+
+	return overlay, nil
+}
+
+func (c *Context) planWalkFn(result *Plan, opts *PlanOpts) depgraph.WalkFunc {
 	var l sync.Mutex
 
 	// Initialize the result
 	result.init()
 
+	forget := make(map[string]struct{})
+	var resume *planResume
+	if opts != nil {
+		for _, k := range opts.Forget {
+			forget[k] = struct{}{}
+		}
+		resume = newPlanResume(opts.Resume)
+	}
+
 	cb := func(r *Resource) error {
 		var diff *ResourceDiff
 
+		c.sl.RLock()
+		_, missing := c.state.Missing[r.Id]
+		_, tainted := c.state.Tainted[r.Id]
+		c.sl.RUnlock()
+		if _, ok := forget[r.Id]; ok && missing {
+			log.Printf("[DEBUG] %s: Missing, removing from state", r.Id)
+
+			l.Lock()
+			result.Diff.Resources[r.Id] = &ResourceDiff{Missing: true}
+			l.Unlock()
+			return nil
+		}
+
 		for _, h := range c.hooks {
 			handleHook(h.PreDiff(r.Id, r.State))
 		}
 
-		if r.Config == nil {
+		if rd, ok := resume.Diff(r.Id, r.State, tainted); ok {
+			log.Printf("[DEBUG] %s: Unchanged since the resumed plan, reusing its diff", r.Id)
+			diff = rd
+		} else if r.Config == nil {
 			log.Printf("[DEBUG] %s: Orphan, marking for destroy", r.Id)
 
 			// This is an orphan (no config), so we mark it to be destroyed
@@ -742,6 +1210,9 @@ func (c *Context) planWalkFn(result *Plan) depgraph.WalkFunc {
 			if err != nil {
 				return err
 			}
+			if !r.Tainted {
+				r.ignoreChanges(diff)
+			}
 		}
 
 		if diff == nil {
@@ -760,6 +1231,11 @@ func (c *Context) planWalkFn(result *Plan) depgraph.WalkFunc {
 		}
 
 		if diff.RequiresNew() || r.State.ID == "" {
+			if missing {
+				log.Printf("[DEBUG] %s: Missing, creating again", r.Id)
+				diff.Missing = true
+			}
+
 			// Add diff to compute new ID
 			diff.init()
 			diff.Attributes["id"] = &ResourceAttrDiff{
@@ -768,6 +1244,18 @@ func (c *Context) planWalkFn(result *Plan) depgraph.WalkFunc {
 				RequiresNew: true,
 				Type:        DiffAttrOutput,
 			}
+
+			// Provisioners only run when a resource is created, so
+			// that's the only time we describe them.
+			if opts != nil && opts.Provisioners &&
+				r.Config != nil && len(r.Provisioners) > 0 {
+				provs, err := c.describeProvisioners(r)
+				if err != nil {
+					return err
+				}
+
+				diff.Provisioners = provs
+			}
 		}
 
 		l.Lock()
@@ -847,6 +1335,11 @@ func (c *Context) refreshWalkFn() depgraph.WalkFunc {
 
 		c.sl.Lock()
 		if rs.ID == "" {
+			// The resource was deleted outside of Terraform. This is
+			// recorded so that plans can explain why it's created again.
+			log.Printf("[INFO] %s: Not found, deleted outside of Terraform", r.Id)
+			c.state.init()
+			c.state.Missing[r.Id] = r.State.ID
 			delete(c.state.Resources, r.Id)
 		} else {
 			c.state.Resources[r.Id] = rs
@@ -863,8 +1356,21 @@ func (c *Context) refreshWalkFn() depgraph.WalkFunc {
 	return c.genericWalkFn(cb)
 }
 
-func (c *Context) validateWalkFn(rws *[]string, res *[]error) depgraph.WalkFunc {
+func (c *Context) validateWalkFn(rws, res *[]*config.Diagnostic) depgraph.WalkFunc {
 	var l sync.Mutex
+	add := func(
+		ws []string, es []error,
+		f func(config.DiagnosticSeverity, string) *config.Diagnostic) {
+		l.Lock()
+		defer l.Unlock()
+
+		for _, w := range ws {
+			*rws = append(*rws, f(config.DiagWarning, w))
+		}
+		for _, e := range es {
+			*res = append(*res, f(config.DiagError, e.Error()))
+		}
+	}
 
 	return func(n *depgraph.Noun) error {
 		// If it is the root node, ignore
@@ -888,34 +1394,38 @@ func (c *Context) validateWalkFn(rws *[]string, res *[]error) depgraph.WalkFunc
 				return nil
 			}
 
+			var pos config.Pos
+			if rn.Config != nil {
+				pos = rn.Config.Pos
+			}
+
 			log.Printf("[INFO] Validating resource: %s", rn.Resource.Id)
 			ws, es := rn.Resource.Provider.ValidateResource(
 				rn.Type, rn.Resource.Config)
-			for i, w := range ws {
-				ws[i] = fmt.Sprintf("'%s' warning: %s", rn.Resource.Id, w)
-			}
-			for i, e := range es {
-				es[i] = fmt.Errorf("'%s' error: %s", rn.Resource.Id, e)
-			}
-
-			l.Lock()
-			*rws = append(*rws, ws...)
-			*res = append(*res, es...)
-			l.Unlock()
+			add(ws, es, func(
+				sev config.DiagnosticSeverity, msg string) *config.Diagnostic {
+				return &config.Diagnostic{
+					Severity: sev,
+					Code:     "invalid_resource",
+					Pos:      pos,
+					Resource: rn.Resource.Id,
+					Summary:  msg,
+				}
+			})
 
 			for idx, p := range rn.Resource.Provisioners {
+				id := fmt.Sprintf("%s.provisioner.%d", rn.Resource.Id, idx)
 				ws, es := p.Provisioner.Validate(p.Config)
-				for i, w := range ws {
-					ws[i] = fmt.Sprintf("'%s.provisioner.%d' warning: %s", rn.Resource.Id, idx, w)
-				}
-				for i, e := range es {
-					es[i] = fmt.Errorf("'%s.provisioner.%d' error: %s", rn.Resource.Id, idx, e)
-				}
-
-				l.Lock()
-				*rws = append(*rws, ws...)
-				*res = append(*res, es...)
-				l.Unlock()
+				add(ws, es, func(
+					sev config.DiagnosticSeverity, msg string) *config.Diagnostic {
+					return &config.Diagnostic{
+						Severity: sev,
+						Code:     "invalid_provisioner",
+						Pos:      pos,
+						Resource: id,
+						Summary:  msg,
+					}
+				})
 			}
 
 		case *GraphNodeResourceProvider:
@@ -927,18 +1437,15 @@ func (c *Context) validateWalkFn(rws *[]string, res *[]error) depgraph.WalkFunc
 
 			for k, p := range rn.Providers {
 				log.Printf("[INFO] Validating provider: %s", k)
-				ws, es := p.Validate(rc)
-				for i, w := range ws {
-					ws[i] = fmt.Sprintf("Provider '%s' warning: %s", k, w)
-				}
-				for i, e := range es {
-					es[i] = fmt.Errorf("Provider '%s' error: %s", k, e)
-				}
-
-				l.Lock()
-				*rws = append(*rws, ws...)
-				*res = append(*res, es...)
-				l.Unlock()
+				ws, es := p.Validate(rc.withEnv(p.ConfigSchema()))
+				add(ws, es, func(
+					sev config.DiagnosticSeverity, msg string) *config.Diagnostic {
+					return &config.Diagnostic{
+						Severity: sev,
+						Code:     "invalid_provider",
+						Summary:  fmt.Sprintf("Provider '%s' %s: %s", k, sev, msg),
+					}
+				})
 			}
 		}
 
@@ -950,7 +1457,7 @@ func (c *Context) genericWalkFn(cb genericWalkFunc) depgraph.WalkFunc {
 	// This will keep track of whether we're stopped or not
 	var stop uint32 = 0
 
-	return func(n *depgraph.Noun) error {
+	return func(n *depgraph.Noun) (rerr error) {
 		// If it is the root node, ignore
 		if n.Name == GraphRootNode {
 			return nil
@@ -980,15 +1487,41 @@ func (c *Context) genericWalkFn(cb genericWalkFunc) depgraph.WalkFunc {
 				raw = m.Config.RawConfig
 			}
 
+			// Errors interpolating the provider configuration are ignored
+			// since resources may not be known yet, but a secret that
+			// can't be read is always an error.
+			if err := c.readSecrets(raw); err != nil {
+				return err
+			}
+
 			rc := NewResourceConfig(raw)
 			rc.interpolate(c)
 
+			// The default tags only reference variables, so they're known
+			// now and merged into the tags of the resources below
+			if m.Config != nil && m.Config.DefaultTags != nil {
+				tags, err := c.defaultTags(m.Config.DefaultTags)
+				if err != nil {
+					return err
+				}
+
+				m.DefaultTags = tags
+			}
+
 			for k, p := range m.Providers {
+				c.track(p)
+
 				log.Printf("[INFO] Configuring provider: %s", k)
-				err := p.Configure(rc)
+				err := p.Configure(rc.withEnv(p.ConfigSchema()))
 				if err != nil {
 					return err
 				}
+
+				log.Printf("[INFO] Validating credentials for provider: %s", k)
+				if err := p.ValidateCredentials(); err != nil {
+					return fmt.Errorf(
+						"Provider '%s' credentials are invalid: %s", k, err)
+				}
 			}
 
 			return nil
@@ -1005,6 +1538,10 @@ func (c *Context) genericWalkFn(cb genericWalkFunc) depgraph.WalkFunc {
 			} else {
 				rn.Resource.Config = NewResourceConfig(rn.Config.RawConfig)
 			}
+
+			if rn.Taggable && rn.ResourceProvider != nil {
+				rn.Resource.Config.defaultTags = rn.ResourceProvider.DefaultTags
+			}
 		} else {
 			rn.Resource.Config = nil
 		}
@@ -1014,6 +1551,8 @@ func (c *Context) genericWalkFn(cb genericWalkFunc) depgraph.WalkFunc {
 			if v := recover(); v != nil {
 				if v == HookActionHalt {
 					atomic.StoreUint32(&stop, 1)
+				} else if he, ok := v.(*hookError); ok {
+					rerr = fmt.Errorf("%s: %s", rn.Resource.Id, he.Err)
 				} else {
 					panic(v)
 				}
@@ -1033,3 +1572,19 @@ func (c *Context) genericWalkFn(cb genericWalkFunc) depgraph.WalkFunc {
 		return nil
 	}
 }
+
+// diagnosticSort implements sort.Interface and sorts diagnostics by their
+// message.
+type diagnosticSort []*config.Diagnostic
+
+func (s diagnosticSort) Len() int {
+	return len(s)
+}
+
+func (s diagnosticSort) Less(i, j int) bool {
+	return s[i].Error() < s[j].Error()
+}
+
+func (s diagnosticSort) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
@@ -1,10 +1,17 @@
 package terraform
 
 import (
+	"bytes"
 	"fmt"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/config"
 )
 
 func TestContextGraph(t *testing.T) {
@@ -161,6 +168,98 @@ func TestContextValidate_resourceConfig_bad(t *testing.T) {
 	}
 }
 
+func TestContextValidate_resourceConfig_badPos(t *testing.T) {
+	config := testConfig(t, "validate-bad-rc")
+	p := testProvider("aws")
+	c := testContext(t, &ContextOpts{
+		Config: config,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+	})
+
+	p.ValidateResourceReturnErrors = []error{fmt.Errorf("bad")}
+
+	_, e := c.Validate()
+	if len(e) != 1 {
+		t.Fatalf("bad: %#v", e)
+	}
+
+	expected := fmt.Sprintf(
+		"%s:1: resource 'aws_instance.test': bad",
+		filepath.Join(fixtureDir, "validate-bad-rc", "main.tf"))
+	if e[0].Error() != expected {
+		t.Fatalf("bad: %s", e[0])
+	}
+}
+
+func TestContextDiagnostics(t *testing.T) {
+	c := testConfig(t, "validate-bad-rc")
+	p := testProvider("aws")
+	ctx := testContext(t, &ContextOpts{
+		Config: c,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+	})
+
+	p.ValidateResourceReturnErrors = []error{fmt.Errorf("bad")}
+	p.ValidateResourceReturnWarns = []string{"meh"}
+
+	ds := ctx.Diagnostics()
+	pos := config.Pos{
+		Filename: filepath.Join(fixtureDir, "validate-bad-rc", "main.tf"),
+		Line:     1,
+	}
+	expected := []*config.Diagnostic{
+		&config.Diagnostic{
+			Severity: config.DiagError,
+			Code:     "invalid_resource",
+			Pos:      pos,
+			Resource: "aws_instance.test",
+			Summary:  "bad",
+		},
+		&config.Diagnostic{
+			Severity: config.DiagWarning,
+			Code:     "invalid_resource",
+			Pos:      pos,
+			Resource: "aws_instance.test",
+			Summary:  "meh",
+		},
+	}
+	if !reflect.DeepEqual(ds, expected) {
+		t.Fatalf("bad: %#v", ds)
+	}
+}
+
+func TestContextValidate_configWarnings(t *testing.T) {
+	config := testConfig(t, "validate-warnings")
+	p := testProvider("aws")
+	c := testContext(t, &ContextOpts{
+		Config: config,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+	})
+
+	p.ValidateResourceReturnWarns = []string{"bad"}
+
+	w, e := c.Validate()
+	if len(e) > 0 {
+		t.Fatalf("bad: %#v", e)
+	}
+
+	expected := []string{
+		"Variable 'unused': declared but never used",
+		fmt.Sprintf(
+			"%s:5: resource 'aws_instance.foo': warning: bad",
+			filepath.Join(fixtureDir, "validate-warnings", "main.tf")),
+	}
+	if !reflect.DeepEqual(w, expected) {
+		t.Fatalf("bad: %#v", w)
+	}
+}
+
 func TestContextValidate_resourceConfig_good(t *testing.T) {
 	config := testConfig(t, "validate-bad-rc")
 	p := testProvider("aws")
@@ -195,6 +294,31 @@ func TestContextValidate_requiredVar(t *testing.T) {
 	}
 }
 
+func TestContextValidate_secretBackend_bad(t *testing.T) {
+	config := testConfig(t, "validate-bad-secret")
+	p := testProvider("aws")
+	c := testContext(t, &ContextOpts{
+		Config: config,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+		Secrets: map[string]SecretBackend{
+			"mock": new(MockSecretBackend),
+		},
+	})
+
+	w, e := c.Validate()
+	if len(w) > 0 {
+		t.Fatalf("bad: %#v", w)
+	}
+	if len(e) != 1 {
+		t.Fatalf("bad: %#v", e)
+	}
+	if !strings.Contains(e[0].Error(), "unknown secret backend 'nope'") {
+		t.Fatalf("bad: %s", e[0])
+	}
+}
+
 func TestContextValidate_provisionerConfig_bad(t *testing.T) {
 	config := testConfig(t, "validate-bad-prov-conf")
 	p := testProvider("aws")
@@ -338,6 +462,40 @@ func TestContextApply(t *testing.T) {
 	}
 }
 
+func TestContextApply_missing(t *testing.T) {
+	c := testConfig(t, "plan-orphan")
+	p := testProvider("aws")
+	p.ApplyFn = testApplyFn
+	p.DiffFn = testDiffFn
+	ctx := testContext(t, &ContextOpts{
+		Config: c,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+		State: &State{
+			Missing: map[string]string{
+				"aws_instance.foo": "i-1",
+				"aws_instance.baz": "i-2",
+			},
+		},
+	})
+
+	if _, err := ctx.Plan(nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	state, err := ctx.Apply()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if state.Resources["aws_instance.foo"] == nil {
+		t.Fatalf("bad: %#v", state.Resources)
+	}
+	if len(state.Missing) > 0 {
+		t.Fatalf("bad: %#v", state.Missing)
+	}
+}
+
 func TestContextApply_Minimal(t *testing.T) {
 	c := testConfig(t, "apply-minimal")
 	p := testProvider("aws")
@@ -395,6 +553,50 @@ func TestContextApply_badDiff(t *testing.T) {
 	}
 }
 
+func TestContextApply_timeouts(t *testing.T) {
+	c := testConfig(t, "apply-timeouts")
+	p := testProvider("aws")
+	p.DiffFn = testDiffFn
+	ctx := testContext(t, &ContextOpts{
+		Config: c,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+	})
+
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	var timeouts map[string]time.Duration
+	p.ApplyFn = func(s *ResourceState, d *ResourceDiff) (*ResourceState, error) {
+		timeouts = d.Timeouts
+
+		// Apply for longer than the timeout
+		<-doneCh
+		return testApplyFn(s, d)
+	}
+
+	if _, err := ctx.Plan(nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	state, err := ctx.Apply()
+	if err == nil {
+		t.Fatal("should error")
+	}
+	if !strings.Contains(err.Error(), "timeout of 50ms") {
+		t.Fatalf("bad: %s", err)
+	}
+	if len(state.Resources) != 0 {
+		t.Fatalf("bad: %#v", state.Resources)
+	}
+
+	expected := map[string]time.Duration{"create": 50 * time.Millisecond}
+	if !reflect.DeepEqual(timeouts, expected) {
+		t.Fatalf("bad: %#v", timeouts)
+	}
+}
+
 func TestContextApply_cancel(t *testing.T) {
 	stopped := false
 
@@ -464,6 +666,109 @@ func TestContextApply_cancel(t *testing.T) {
 	}
 }
 
+func TestContextApply_cancelProvider(t *testing.T) {
+	c := testConfig(t, "apply-cancel")
+	p := testProvider("aws")
+	ctx := testContext(t, &ContextOpts{
+		Config: c,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+	})
+
+	// Apply blocks until the provider is stopped, like an API call that
+	// is canceled
+	var once sync.Once
+	stopCh := make(chan struct{})
+	p.StopFn = func() error {
+		once.Do(func() { close(stopCh) })
+		return nil
+	}
+	p.ApplyFn = func(*ResourceState, *ResourceDiff) (*ResourceState, error) {
+		go ctx.Stop()
+
+		<-stopCh
+		return nil, fmt.Errorf("canceled")
+	}
+	p.DiffFn = testDiffFn
+
+	if _, err := ctx.Plan(nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := ctx.Apply()
+		errCh <- err
+	}()
+
+	select {
+	case <-errCh:
+		// The errors of the stopped calls aren't reported, since the
+		// apply was stopped
+	case <-time.After(5 * time.Second):
+		t.Fatal("apply should return once the provider is stopped")
+	}
+	if !p.Stopped() {
+		t.Fatal("stop should be called")
+	}
+}
+
+func TestContextApply_cancelProvisioner(t *testing.T) {
+	c := testConfig(t, "apply-provisioner-fail")
+	p := testProvider("aws")
+	pr := testProvisioner()
+	p.ApplyFn = testApplyFn
+	p.DiffFn = testDiffFn
+
+	ctx := testContext(t, &ContextOpts{
+		Config: c,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+		Provisioners: map[string]ResourceProvisionerFactory{
+			"shell": testProvisionerFuncFixed(pr),
+		},
+		Variables: map[string]string{
+			"value": "1",
+		},
+	})
+
+	var once sync.Once
+	stopCh := make(chan struct{})
+	pr.StopFn = func() error {
+		once.Do(func() { close(stopCh) })
+		return nil
+	}
+	pr.ApplyFn = func(*ResourceState, *ResourceConfig) error {
+		go ctx.Stop()
+
+		<-stopCh
+		return fmt.Errorf("canceled")
+	}
+
+	if _, err := ctx.Plan(nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := ctx.Apply()
+		errCh <- err
+	}()
+
+	select {
+	case <-errCh:
+		// The errors of the stopped calls aren't reported, since the
+		// apply was stopped
+	case <-time.After(5 * time.Second):
+		t.Fatal("apply should return once the provisioner is stopped")
+	}
+	if !pr.StopCalled {
+		t.Fatal("stop should be called")
+	}
+}
+
 func TestContextApply_compute(t *testing.T) {
 	c := testConfig(t, "apply-compute")
 	p := testProvider("aws")
@@ -1069,6 +1374,78 @@ func TestContextApply_hook(t *testing.T) {
 	if !h.PostApplyCalled {
 		t.Fatal("should be called")
 	}
+	if !h.PreApplyAllCalled || h.PreApplyAllDiff == nil {
+		t.Fatal("should be called")
+	}
+	if !h.PostApplyAllCalled || h.PostApplyAllState == nil {
+		t.Fatal("should be called")
+	}
+}
+
+func TestContextApply_hookError(t *testing.T) {
+	c := testConfig(t, "apply-good")
+	h := new(MockHook)
+	h.PreApplyError = fmt.Errorf("not allowed")
+	p := testProvider("aws")
+	p.ApplyFn = testApplyFn
+	p.DiffFn = testDiffFn
+	ctx := testContext(t, &ContextOpts{
+		Config: c,
+		Hooks:  []Hook{h},
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+	})
+
+	if _, err := ctx.Plan(nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	_, err := ctx.Apply()
+	if err == nil {
+		t.Fatal("should error")
+	}
+	if !strings.Contains(err.Error(), "not allowed") {
+		t.Fatalf("bad: %s", err)
+	}
+	if p.ApplyCalled {
+		t.Fatal("apply should not be called")
+	}
+}
+
+func TestContextApply_hookHalt(t *testing.T) {
+	c := testConfig(t, "apply-good")
+	h := new(MockHook)
+	h.PreApplyAllReturn = HookActionHalt
+	p := testProvider("aws")
+	p.ApplyFn = testApplyFn
+	p.DiffFn = testDiffFn
+	ctx := testContext(t, &ContextOpts{
+		Config: c,
+		Hooks:  []Hook{h},
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+	})
+
+	if _, err := ctx.Plan(nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	state, err := ctx.Apply()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if p.ApplyCalled {
+		t.Fatal("apply should not be called")
+	}
+	if h.PostApplyAllCalled {
+		t.Fatal("should not be called")
+	}
+	if state != nil && len(state.Resources) > 0 {
+		t.Fatalf("bad: %#v", state)
+	}
 }
 
 func TestContextApply_idAttr(t *testing.T) {
@@ -1149,8 +1526,8 @@ func TestContextApply_output(t *testing.T) {
 	}
 }
 
-func TestContextApply_outputMulti(t *testing.T) {
-	c := testConfig(t, "apply-output-multi")
+func TestContextApply_outputSensitive(t *testing.T) {
+	c := testConfig(t, "apply-output-sensitive")
 	p := testProvider("aws")
 	p.ApplyFn = testApplyFn
 	p.DiffFn = testDiffFn
@@ -1159,8 +1536,16 @@ func TestContextApply_outputMulti(t *testing.T) {
 		Providers: map[string]ResourceProviderFactory{
 			"aws": testProviderFuncFixed(p),
 		},
+		Variables: map[string]string{
+			"password": "hunter2",
+		},
 	})
 
+	expected := []string{"hunter2"}
+	if actual := ctx.SensitiveValues(); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+
 	if _, err := ctx.Plan(nil); err != nil {
 		t.Fatalf("err: %s", err)
 	}
@@ -1170,9 +1555,43 @@ func TestContextApply_outputMulti(t *testing.T) {
 		t.Fatalf("err: %s", err)
 	}
 
-	actual := strings.TrimSpace(state.String())
-	expected := strings.TrimSpace(testTerraformApplyOutputMultiStr)
-	if actual != expected {
+	// The output is still in the state
+	if state.Outputs["foo_num"] != "2" {
+		t.Fatalf("bad: %#v", state.Outputs)
+	}
+	if _, ok := state.SensitiveOutputs["foo_num"]; !ok {
+		t.Fatalf("bad: %#v", state.SensitiveOutputs)
+	}
+	if _, ok := state.SensitiveOutputs["user"]; ok {
+		t.Fatalf("bad: %#v", state.SensitiveOutputs)
+	}
+
+}
+
+func TestContextApply_outputMulti(t *testing.T) {
+	c := testConfig(t, "apply-output-multi")
+	p := testProvider("aws")
+	p.ApplyFn = testApplyFn
+	p.DiffFn = testDiffFn
+	ctx := testContext(t, &ContextOpts{
+		Config: c,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+	})
+
+	if _, err := ctx.Plan(nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	state, err := ctx.Apply()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := strings.TrimSpace(state.String())
+	expected := strings.TrimSpace(testTerraformApplyOutputMultiStr)
+	if actual != expected {
 		t.Fatalf("bad: \n%s", actual)
 	}
 }
@@ -1293,39 +1712,567 @@ func TestContextApply_vars(t *testing.T) {
 		},
 	})
 
-	w, e := ctx.Validate()
-	if len(w) > 0 {
-		t.Fatalf("bad: %#v", w)
+	w, e := ctx.Validate()
+	if len(w) > 0 {
+		t.Fatalf("bad: %#v", w)
+	}
+	if len(e) > 0 {
+		t.Fatalf("bad: %s", e)
+	}
+
+	if _, err := ctx.Plan(nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	state, err := ctx.Apply()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := strings.TrimSpace(state.String())
+	expected := strings.TrimSpace(testTerraformApplyVarsStr)
+	if actual != expected {
+		t.Fatalf("bad: \n%s", actual)
+	}
+}
+
+func TestContextPlan(t *testing.T) {
+	c := testConfig(t, "plan-good")
+	p := testProvider("aws")
+	p.DiffFn = testDiffFn
+	ctx := testContext(t, &ContextOpts{
+		Config: c,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+	})
+
+	plan, err := ctx.Plan(nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if len(plan.Diff.Resources) < 2 {
+		t.Fatalf("bad: %#v", plan.Diff.Resources)
+	}
+
+	actual := strings.TrimSpace(plan.String())
+	expected := strings.TrimSpace(testTerraformPlanStr)
+	if actual != expected {
+		t.Fatalf("bad:\n%s", actual)
+	}
+}
+
+func TestContextPlan_minimal(t *testing.T) {
+	c := testConfig(t, "plan-empty")
+	p := testProvider("aws")
+	p.DiffFn = testDiffFn
+	ctx := testContext(t, &ContextOpts{
+		Config: c,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+	})
+
+	plan, err := ctx.Plan(nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := strings.TrimSpace(plan.String())
+	expected := strings.TrimSpace(testTerraformPlanEmptyStr)
+	if actual != expected {
+		t.Fatalf("bad:\n%s", actual)
+	}
+}
+
+func TestContextPlan_nil(t *testing.T) {
+	c := testConfig(t, "plan-nil")
+	p := testProvider("aws")
+	p.DiffFn = testDiffFn
+	ctx := testContext(t, &ContextOpts{
+		Config: c,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+		State: &State{
+			Resources: map[string]*ResourceState{
+				"aws_instance.foo": &ResourceState{
+					ID:   "bar",
+					Type: "aws_instance",
+				},
+			},
+		},
+	})
+
+	plan, err := ctx.Plan(nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(plan.Diff.Resources) != 0 {
+		t.Fatalf("bad: %#v", plan.Diff.Resources)
+	}
+}
+
+func TestContextPlan_provisioners(t *testing.T) {
+	c := testConfig(t, "apply-provisioner-conninfo")
+	p := testProvider("aws")
+	p.DiffFn = testDiffFn
+	pr := testProvisioner()
+	pr.DescribeFn = func(rs *ResourceState, c *ResourceConfig) ([]string, error) {
+		if rs.ConnInfo["user"] != "superuser" {
+			t.Fatalf("bad: %#v", rs.ConnInfo)
+		}
+		if rs.ConnInfo["port"] != "2222" {
+			t.Fatalf("bad: %#v", rs.ConnInfo)
+		}
+		if !c.IsSet("foo") {
+			t.Fatalf("bad: %#v", c)
+		}
+
+		return []string{"run something"}, nil
+	}
+
+	ctx := testContext(t, &ContextOpts{
+		Config: c,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+		Provisioners: map[string]ResourceProvisionerFactory{
+			"shell": testProvisionerFuncFixed(pr),
+		},
+		Variables: map[string]string{
+			"value": "1",
+			"pass":  "test",
+		},
+	})
+
+	// By default, provisioners aren't described
+	if _, err := ctx.Plan(nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if pr.DescribeCalled {
+		t.Fatal("describe should not be called")
+	}
+
+	plan, err := ctx.Plan(&PlanOpts{Provisioners: true})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !pr.DescribeCalled {
+		t.Fatal("describe should be called")
+	}
+	if pr.ApplyCalled {
+		t.Fatal("apply should not be called")
+	}
+
+	expected := []*ProvisionerDiff{
+		&ProvisionerDiff{
+			Type:    "shell",
+			Actions: []string{"run something"},
+		},
+	}
+	actual := plan.Diff.Resources["aws_instance.bar"].Provisioners
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestContextPlan_defaultTags(t *testing.T) {
+	m := testConfig(t, "plan-default-tags")
+	p := testProvider("aws")
+	p.ResourcesReturn[0].Taggable = true
+
+	tags := make(map[string]interface{})
+	p.DiffFn = func(s *ResourceState, c *ResourceConfig) (*ResourceDiff, error) {
+		if _, ok := c.Get("num"); ok {
+			tags["foo"] = c.Config["tags"]
+		} else {
+			tags["bar"] = c.Config["tags"]
+		}
+
+		return testDiffFn(s, c)
+	}
+
+	ctx := testContext(t, &ContextOpts{
+		Config: m,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+	})
+
+	if _, err := ctx.Plan(nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"foo": map[string]interface{}{
+			"cost_center": "1234",
+			"env":         "dev",
+		},
+		"bar": map[string]interface{}{
+			"cost_center": "1234",
+			"env":         "prod",
+		},
+	}
+	if !reflect.DeepEqual(tags, expected) {
+		t.Fatalf("bad: %#v", tags)
+	}
+
+	// The configuration itself isn't changed
+	for _, r := range m.Resources {
+		if _, ok := r.RawConfig.Raw["tags"]; ok && r.Name == "bar" {
+			t.Fatalf("bad: %#v", r.RawConfig.Raw)
+		}
+	}
+}
+
+func TestContextPlan_defaultTagsNotTaggable(t *testing.T) {
+	m := testConfig(t, "plan-default-tags")
+	p := testProvider("aws")
+
+	var tags interface{}
+	p.DiffFn = func(s *ResourceState, c *ResourceConfig) (*ResourceDiff, error) {
+		if _, ok := c.Get("num"); !ok {
+			tags = c.Config["tags"]
+		}
+
+		return testDiffFn(s, c)
+	}
+
+	ctx := testContext(t, &ContextOpts{
+		Config: m,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+	})
+
+	if _, err := ctx.Plan(nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if tags != nil {
+		t.Fatalf("bad: %#v", tags)
+	}
+}
+
+func TestContextPlan_enabled(t *testing.T) {
+	m := testConfig(t, "plan-enabled")
+	p := testProvider("aws")
+	p.DiffFn = testDiffFn
+	ctx := testContext(t, &ContextOpts{
+		Config: m,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+	})
+
+	plan, err := ctx.Plan(nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if len(plan.Diff.Resources) != 1 {
+		t.Fatalf("bad: %#v", plan.Diff.Resources)
+	}
+	if _, ok := plan.Diff.Resources["aws_instance.bar"]; !ok {
+		t.Fatalf("bad: %#v", plan.Diff.Resources)
+	}
+
+	ctx = testContext(t, &ContextOpts{
+		Config: m,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+		Variables: map[string]string{"create_elb": "true"},
+	})
+
+	plan, err = ctx.Plan(nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if len(plan.Diff.Resources) != 2 {
+		t.Fatalf("bad: %#v", plan.Diff.Resources)
+	}
+}
+
+func TestContextPlan_enabledDestroy(t *testing.T) {
+	m := testConfig(t, "plan-enabled")
+	p := testProvider("aws")
+	p.DiffFn = testDiffFn
+	s := &State{
+		Resources: map[string]*ResourceState{
+			"aws_instance.foo": &ResourceState{
+				ID:   "foo",
+				Type: "aws_instance",
+			},
+		},
+	}
+	ctx := testContext(t, &ContextOpts{
+		Config: m,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+		State: s,
+	})
+
+	plan, err := ctx.Plan(nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	rd, ok := plan.Diff.Resources["aws_instance.foo"]
+	if !ok || !rd.Destroy {
+		t.Fatalf("bad: %#v", plan.Diff.Resources)
+	}
+}
+
+func TestContextPlan_enabledRef(t *testing.T) {
+	m := testConfig(t, "plan-enabled-ref")
+	p := testProvider("aws")
+	p.DiffFn = testDiffFn
+	ctx := testContext(t, &ContextOpts{
+		Config: m,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+	})
+
+	_, err := ctx.Plan(nil)
+	if err == nil {
+		t.Fatal("should error")
+	}
+	if !strings.Contains(err.Error(), "which isn't enabled") {
+		t.Fatalf("bad: %s", err)
+	}
+}
+
+func TestContextPlan_ignoreChanges(t *testing.T) {
+	m := testConfig(t, "plan-ignore-changes")
+	p := testProvider("aws")
+	p.DiffFn = testDiffFn
+	s := &State{
+		Resources: map[string]*ResourceState{
+			"aws_instance.foo": &ResourceState{
+				ID:   "foo",
+				Type: "aws_instance",
+				Attributes: map[string]string{
+					"num":  "2",
+					"name": "foo-old",
+				},
+			},
+			"aws_instance.bar": &ResourceState{
+				ID:   "bar",
+				Type: "aws_instance",
+				Attributes: map[string]string{
+					"num":  "2",
+					"name": "bar-old",
+				},
+			},
+		},
+	}
+	ctx := testContext(t, &ContextOpts{
+		Config: m,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+		State: s,
+	})
+
+	plan, err := ctx.Plan(nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// The change to the name of an existing resource is ignored
+	if rd, ok := plan.Diff.Resources["aws_instance.foo"]; ok {
+		if _, ok := rd.Attributes["name"]; ok {
+			t.Fatalf("bad: %#v", rd)
+		}
+	}
+
+	// ...but not if it isn't ignored, or the resource is created
+	for _, id := range []string{"aws_instance.bar", "aws_instance.baz"} {
+		rd, ok := plan.Diff.Resources[id]
+		if !ok {
+			t.Fatalf("%s: no diff", id)
+		}
+		if _, ok := rd.Attributes["name"]; !ok {
+			t.Fatalf("%s: bad: %#v", id, rd)
+		}
+	}
+}
+
+func TestContextPlan_computed(t *testing.T) {
+	c := testConfig(t, "plan-computed")
+	p := testProvider("aws")
+	p.DiffFn = testDiffFn
+	ctx := testContext(t, &ContextOpts{
+		Config: c,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+	})
+
+	plan, err := ctx.Plan(nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := strings.TrimSpace(plan.String())
+	expected := strings.TrimSpace(testTerraformPlanComputedStr)
+	if actual != expected {
+		t.Fatalf("bad:\n%s", actual)
+	}
+}
+
+func TestContextPlan_badCredentials(t *testing.T) {
+	c := testConfig(t, "plan-good")
+	p := testProvider("aws")
+	p.DiffFn = testDiffFn
+	p.ValidateCredentialsReturnError = fmt.Errorf("token expired")
+	ctx := testContext(t, &ContextOpts{
+		Config: c,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+	})
+
+	_, err := ctx.Plan(nil)
+	if err == nil {
+		t.Fatal("should error")
+	}
+	if !strings.Contains(err.Error(), "Provider 'aws' credentials are invalid: token expired") {
+		t.Fatalf("bad: %s", err)
+	}
+	if p.DiffCalled {
+		t.Fatal("diff should not be called")
+	}
+}
+
+func TestContextPlan_writeOnly(t *testing.T) {
+	c := testConfig(t, "plan-write-only")
+	p := testProvider("aws")
+	p.DiffFn = testDiffFn
+	p.WriteOnlyConfigReturn = []string{"access_key", "secret_key"}
+	ctx := testContext(t, &ContextOpts{
+		Config: c,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+		Variables: map[string]string{
+			"access_key": "AKIAEXAMPLE",
+			"region":     "us-east-1",
+		},
+	})
+
+	plan, err := ctx.Plan(nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// The provider is still configured with the credentials
+	if v := p.ConfigureConfig.Config["secret_key"]; v != "hunter2" {
+		t.Fatalf("bad: %#v", v)
+	}
+
+	raw := plan.Config.ProviderConfigs[0].RawConfig.Raw
+	expected := map[string]interface{}{"region": "${var.region}"}
+	if !reflect.DeepEqual(raw, expected) {
+		t.Fatalf("bad: %#v", raw)
+	}
+	expectedVars := map[string]string{"region": "us-east-1"}
+	if !reflect.DeepEqual(plan.Vars, expectedVars) {
+		t.Fatalf("bad: %#v", plan.Vars)
+	}
+
+	// The configuration of the context itself is unchanged
+	if len(c.ProviderConfigs[0].RawConfig.Raw) != 3 {
+		t.Fatalf("bad: %#v", c.ProviderConfigs[0].RawConfig.Raw)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := WritePlan(plan, buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, v := range []string{"hunter2", "AKIAEXAMPLE"} {
+		if strings.Contains(buf.String(), v) {
+			t.Fatalf("plan should not contain %s", v)
+		}
+	}
+}
+
+func TestContextPlan_providerEnv(t *testing.T) {
+	c := testConfig(t, "plan-write-only")
+	p := testProvider("aws")
+	p.DiffFn = testDiffFn
+	p.ConfigSchemaReturn = []ConfigAttribute{
+		ConfigAttribute{
+			Name:    "secret_key",
+			EnvVars: []string{"TF_TEST_SECRET_KEY"},
+		},
+		ConfigAttribute{
+			Name:    "token",
+			EnvVars: []string{"TF_TEST_TOKEN", "TF_TEST_TOKEN_OLD"},
+		},
+	}
+	ctx := testContext(t, &ContextOpts{
+		Config: c,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+		Variables: map[string]string{
+			"access_key": "AKIAEXAMPLE",
+			"region":     "us-east-1",
+		},
+	})
+
+	for k, v := range map[string]string{
+		"TF_TEST_SECRET_KEY": "env",
+		"TF_TEST_TOKEN":      "",
+		"TF_TEST_TOKEN_OLD":  "token",
+	} {
+		defer os.Setenv(k, os.Getenv(k))
+		os.Setenv(k, v)
+	}
+
+	if _, e := ctx.Validate(); len(e) > 0 {
+		t.Fatalf("bad: %#v", e)
 	}
-	if len(e) > 0 {
-		t.Fatalf("bad: %s", e)
+	if v := p.ValidateConfig.Config["token"]; v != "token" {
+		t.Fatalf("bad: %#v", v)
 	}
 
 	if _, err := ctx.Plan(nil); err != nil {
 		t.Fatalf("err: %s", err)
 	}
 
-	state, err := ctx.Apply()
-	if err != nil {
-		t.Fatalf("err: %s", err)
+	// The environment is read for the provider, but the configuration
+	// wins over it
+	if v := p.ConfigureConfig.Config["token"]; v != "token" {
+		t.Fatalf("bad: %#v", v)
 	}
-
-	actual := strings.TrimSpace(state.String())
-	expected := strings.TrimSpace(testTerraformApplyVarsStr)
-	if actual != expected {
-		t.Fatalf("bad: \n%s", actual)
+	if v := p.ConfigureConfig.Config["secret_key"]; v != "hunter2" {
+		t.Fatalf("bad: %#v", v)
 	}
 }
 
-func TestContextPlan(t *testing.T) {
-	c := testConfig(t, "plan-good")
+func TestContextPlan_secret(t *testing.T) {
+	c := testConfig(t, "plan-secret")
 	p := testProvider("aws")
 	p.DiffFn = testDiffFn
+	b := &MockSecretBackend{
+		Values: map[string]string{"token": "hunter2"},
+	}
 	ctx := testContext(t, &ContextOpts{
 		Config: c,
 		Providers: map[string]ResourceProviderFactory{
 			"aws": testProviderFuncFixed(p),
 		},
+		Secrets: map[string]SecretBackend{
+			"mock": b,
+		},
 	})
 
 	plan, err := ctx.Plan(nil)
@@ -1333,19 +2280,28 @@ func TestContextPlan(t *testing.T) {
 		t.Fatalf("err: %s", err)
 	}
 
-	if len(plan.Diff.Resources) < 2 {
-		t.Fatalf("bad: %#v", plan.Diff.Resources)
+	if !b.SecretCalled {
+		t.Fatal("secret should be read")
+	}
+	if !reflect.DeepEqual(b.SecretKeys, []string{"token"}) {
+		t.Fatalf("bad: %#v", b.SecretKeys)
+	}
+	if v := p.ConfigureConfig.Config["token"]; v != "hunter2" {
+		t.Fatalf("bad: %#v", v)
 	}
 
-	actual := strings.TrimSpace(plan.String())
-	expected := strings.TrimSpace(testTerraformPlanStr)
-	if actual != expected {
-		t.Fatalf("bad:\n%s", actual)
+	// The secret must not be stored in the plan
+	buf := new(bytes.Buffer)
+	if err := WritePlan(plan, buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if strings.Contains(buf.String(), "hunter2") {
+		t.Fatal("plan should not contain the secret")
 	}
 }
 
-func TestContextPlan_minimal(t *testing.T) {
-	c := testConfig(t, "plan-empty")
+func TestContextPlan_secretError(t *testing.T) {
+	c := testConfig(t, "plan-secret")
 	p := testProvider("aws")
 	p.DiffFn = testDiffFn
 	ctx := testContext(t, &ContextOpts{
@@ -1353,50 +2309,49 @@ func TestContextPlan_minimal(t *testing.T) {
 		Providers: map[string]ResourceProviderFactory{
 			"aws": testProviderFuncFixed(p),
 		},
+		Secrets: map[string]SecretBackend{
+			"mock": new(MockSecretBackend),
+		},
 	})
 
-	plan, err := ctx.Plan(nil)
-	if err != nil {
-		t.Fatalf("err: %s", err)
-	}
-
-	actual := strings.TrimSpace(plan.String())
-	expected := strings.TrimSpace(testTerraformPlanEmptyStr)
-	if actual != expected {
-		t.Fatalf("bad:\n%s", actual)
+	if _, err := ctx.Plan(nil); err == nil {
+		t.Fatal("should error")
 	}
 }
 
-func TestContextPlan_nil(t *testing.T) {
-	c := testConfig(t, "plan-nil")
+func TestContextPlan_git(t *testing.T) {
+	c := testConfig(t, "plan-git")
 	p := testProvider("aws")
 	p.DiffFn = testDiffFn
+	git := map[string]string{
+		"commit":       "0123456789abcdef",
+		"short_commit": "0123456",
+		"branch":       "master",
+	}
 	ctx := testContext(t, &ContextOpts{
 		Config: c,
 		Providers: map[string]ResourceProviderFactory{
 			"aws": testProviderFuncFixed(p),
 		},
-		State: &State{
-			Resources: map[string]*ResourceState{
-				"aws_instance.foo": &ResourceState{
-					ID:   "bar",
-					Type: "aws_instance",
-				},
-			},
-		},
+		Git: git,
 	})
 
 	plan, err := ctx.Plan(nil)
 	if err != nil {
 		t.Fatalf("err: %s", err)
 	}
-	if len(plan.Diff.Resources) != 0 {
-		t.Fatalf("bad: %#v", plan.Diff.Resources)
+
+	rd := plan.Diff.Resources["aws_instance.foo"]
+	if v := rd.Attributes["revision"].New; v != "master@0123456" {
+		t.Fatalf("bad: %#v", v)
+	}
+	if !reflect.DeepEqual(plan.Git, git) {
+		t.Fatalf("bad: %#v", plan.Git)
 	}
 }
 
-func TestContextPlan_computed(t *testing.T) {
-	c := testConfig(t, "plan-computed")
+func TestContextPlan_gitNone(t *testing.T) {
+	c := testConfig(t, "plan-git")
 	p := testProvider("aws")
 	p.DiffFn = testDiffFn
 	ctx := testContext(t, &ContextOpts{
@@ -1406,15 +2361,12 @@ func TestContextPlan_computed(t *testing.T) {
 		},
 	})
 
-	plan, err := ctx.Plan(nil)
-	if err != nil {
-		t.Fatalf("err: %s", err)
+	_, err := ctx.Plan(nil)
+	if err == nil {
+		t.Fatal("should error")
 	}
-
-	actual := strings.TrimSpace(plan.String())
-	expected := strings.TrimSpace(testTerraformPlanComputedStr)
-	if actual != expected {
-		t.Fatalf("bad:\n%s", actual)
+	if !strings.Contains(err.Error(), "git repository") {
+		t.Fatalf("bad: %s", err)
 	}
 }
 
@@ -1708,6 +2660,78 @@ func TestContextPlan_orphan(t *testing.T) {
 	}
 }
 
+func TestContextPlan_missing(t *testing.T) {
+	c := testConfig(t, "plan-orphan")
+	p := testProvider("aws")
+	p.DiffFn = testDiffFn
+	ctx := testContext(t, &ContextOpts{
+		Config: c,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+		State: &State{
+			Missing: map[string]string{
+				"aws_instance.foo": "i-1",
+				"aws_instance.baz": "i-2",
+			},
+		},
+	})
+
+	plan, err := ctx.Plan(nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// The resource in the configuration is created again, and the other
+	// one is removed from the state.
+	rd := plan.Diff.Resources["aws_instance.foo"]
+	if rd == nil || !rd.Missing || !rd.RequiresNew() {
+		t.Fatalf("bad: %#v", rd)
+	}
+	rd = plan.Diff.Resources["aws_instance.baz"]
+	if rd == nil || !rd.Missing || !rd.Empty() {
+		t.Fatalf("bad: %#v", rd)
+	}
+}
+
+func TestContextPlan_missingForget(t *testing.T) {
+	c := testConfig(t, "plan-orphan")
+	p := testProvider("aws")
+	p.DiffFn = testDiffFn
+	ctx := testContext(t, &ContextOpts{
+		Config: c,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+		State: &State{
+			Missing: map[string]string{
+				"aws_instance.foo": "i-1",
+			},
+		},
+	})
+
+	_, err := ctx.Plan(&PlanOpts{Forget: []string{"aws_instance.bar"}})
+	if err == nil {
+		t.Fatal("should error")
+	}
+
+	plan, err := ctx.Plan(&PlanOpts{Forget: []string{"aws_instance.foo"}})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if p.DiffCalled {
+		t.Fatal("diff should not be called")
+	}
+
+	rd := plan.Diff.Resources["aws_instance.foo"]
+	if rd == nil || !rd.Missing || !rd.Empty() {
+		t.Fatalf("bad: %#v", rd)
+	}
+	if plan.Diff.Empty() {
+		t.Fatal("diff should not be empty")
+	}
+}
+
 func TestContextPlan_state(t *testing.T) {
 	c := testConfig(t, "plan-good")
 	p := testProvider("aws")
@@ -1781,6 +2805,126 @@ func TestContextPlan_taint(t *testing.T) {
 	}
 }
 
+func TestContextPlan_resume(t *testing.T) {
+	c := testConfig(t, "plan-good")
+	p := testProvider("aws")
+	p.DiffFn = testDiffFn
+
+	// The apply of the plan created foo, and failed to create bar
+	resume := &Plan{
+		State: &State{},
+		Diff: &Diff{
+			Resources: map[string]*ResourceDiff{
+				"aws_instance.foo": &ResourceDiff{
+					Attributes: map[string]*ResourceAttrDiff{
+						"num": &ResourceAttrDiff{New: "2"},
+					},
+				},
+				"aws_instance.bar": &ResourceDiff{
+					Attributes: map[string]*ResourceAttrDiff{
+						"foo":    &ResourceAttrDiff{New: "2"},
+						"reused": &ResourceAttrDiff{New: "yes"},
+					},
+				},
+			},
+		},
+		Completed: []string{"aws_instance.foo"},
+	}
+	s := &State{
+		Resources: map[string]*ResourceState{
+			"aws_instance.foo": &ResourceState{
+				ID:         "foo",
+				Type:       "aws_instance",
+				Attributes: map[string]string{"num": "2"},
+			},
+		},
+	}
+	ctx := testContext(t, &ContextOpts{
+		Config: c,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+		State: s,
+	})
+
+	plan, err := ctx.Plan(&PlanOpts{Resume: resume})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if _, ok := plan.Diff.Resources["aws_instance.foo"]; ok {
+		t.Fatalf("bad: %#v", plan.Diff.Resources)
+	}
+
+	rd := plan.Diff.Resources["aws_instance.bar"]
+	if rd == nil || rd.Attributes["reused"] == nil {
+		t.Fatalf("bad: %#v", plan.Diff.Resources)
+	}
+
+	// The resumed plan isn't changed
+	if _, ok := resume.Diff.Resources["aws_instance.bar"].Attributes["id"]; ok {
+		t.Fatal("resumed diff shouldn't be changed")
+	}
+}
+
+func TestContextPlan_resumeChanged(t *testing.T) {
+	c := testConfig(t, "plan-good")
+	p := testProvider("aws")
+	p.DiffFn = testDiffFn
+
+	// Creating bar failed after it was created, so it's tainted now
+	resume := &Plan{
+		State: &State{},
+		Diff: &Diff{
+			Resources: map[string]*ResourceDiff{
+				"aws_instance.foo": &ResourceDiff{
+					Attributes: map[string]*ResourceAttrDiff{
+						"num":    &ResourceAttrDiff{New: "2"},
+						"reused": &ResourceAttrDiff{New: "yes"},
+					},
+				},
+				"aws_instance.bar": &ResourceDiff{
+					Attributes: map[string]*ResourceAttrDiff{
+						"foo":    &ResourceAttrDiff{New: "2"},
+						"reused": &ResourceAttrDiff{New: "yes"},
+					},
+				},
+			},
+		},
+	}
+	s := &State{
+		Resources: map[string]*ResourceState{
+			"aws_instance.bar": &ResourceState{
+				ID:   "bar",
+				Type: "aws_instance",
+			},
+		},
+		Tainted: map[string]struct{}{"aws_instance.bar": struct{}{}},
+	}
+	ctx := testContext(t, &ContextOpts{
+		Config: c,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+		State: s,
+	})
+
+	plan, err := ctx.Plan(&PlanOpts{Resume: resume})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	foo := plan.Diff.Resources["aws_instance.foo"]
+	if foo == nil || foo.Attributes["reused"] == nil {
+		t.Fatalf("bad: %#v", plan.Diff.Resources)
+	}
+
+	bar := plan.Diff.Resources["aws_instance.bar"]
+	if bar == nil || bar.Attributes["reused"] != nil || !bar.Destroy {
+		t.Fatalf("bad: %#v", plan.Diff.Resources)
+	}
+}
+
 func TestContextPlan_varMultiCountOne(t *testing.T) {
 	c := testConfig(t, "plan-var-multi-count-one")
 	p := testProvider("aws")
@@ -1804,6 +2948,110 @@ func TestContextPlan_varMultiCountOne(t *testing.T) {
 	}
 }
 
+func TestContextListResources(t *testing.T) {
+	p := testProvider("aws")
+	c := testConfig(t, "list-resources")
+	ctx := testContext(t, &ContextOpts{
+		Config: c,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+	})
+
+	p.CapabilitiesReturn = ResourceProviderCapabilities{ListResources: true}
+	p.ListResourcesReturn = []*ResourceState{
+		&ResourceState{ID: "i-abc123"},
+	}
+
+	filters := map[string]string{"tag:Name": "web"}
+	result, err := ctx.ListResources("aws_instance", filters)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !p.ConfigureCalled {
+		t.Fatal("configure should be called")
+	}
+	if v, ok := p.ConfigureConfig.Get("region"); !ok || v != "us-east-1" {
+		t.Fatalf("bad: %#v", p.ConfigureConfig)
+	}
+	if p.ListResourcesType != "aws_instance" {
+		t.Fatalf("bad: %s", p.ListResourcesType)
+	}
+	if !reflect.DeepEqual(p.ListResourcesFilters, filters) {
+		t.Fatalf("bad: %#v", p.ListResourcesFilters)
+	}
+	if len(result) != 1 || result[0].ID != "i-abc123" {
+		t.Fatalf("bad: %#v", result)
+	}
+	if result[0].Type != "aws_instance" {
+		t.Fatalf("bad: %#v", result[0])
+	}
+}
+
+func TestContextListResources_unknownType(t *testing.T) {
+	p := testProvider("aws")
+	c := testConfig(t, "list-resources")
+	ctx := testContext(t, &ContextOpts{
+		Config: c,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+	})
+
+	if _, err := ctx.ListResources("aws_vpc", nil); err == nil {
+		t.Fatal("should error")
+	}
+	if p.ListResourcesCalled {
+		t.Fatal("list resources should not be called")
+	}
+}
+
+func TestContextListResources_notSupported(t *testing.T) {
+	p := testProvider("aws")
+	c := testConfig(t, "list-resources")
+	ctx := testContext(t, &ContextOpts{
+		Config: c,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+	})
+
+	_, err := ctx.ListResources("aws_instance", nil)
+	if err != ErrListNotSupported {
+		t.Fatalf("bad: %#v", err)
+	}
+	if p.ConfigureCalled {
+		t.Fatal("configure should not be called")
+	}
+	if p.ListResourcesCalled {
+		t.Fatal("list resources should not be called")
+	}
+}
+
+func TestContextListResources_capabilitiesUnknown(t *testing.T) {
+	p := testProvider("aws")
+	c := testConfig(t, "list-resources")
+	ctx := testContext(t, &ContextOpts{
+		Config: c,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+	})
+
+	p.CapabilitiesReturn = ResourceProviderCapabilities{Unknown: true}
+	p.ListResourcesReturn = []*ResourceState{
+		&ResourceState{ID: "i-abc123"},
+	}
+
+	result, err := ctx.ListResources("aws_instance", nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(result) != 1 {
+		t.Fatalf("bad: %#v", result)
+	}
+}
+
 func TestContextRefresh(t *testing.T) {
 	p := testProvider("aws")
 	c := testConfig(t, "refresh-basic")
@@ -1877,6 +3125,9 @@ func TestContextRefresh_delete(t *testing.T) {
 	if len(s.Resources) > 0 {
 		t.Fatal("resources should be empty")
 	}
+	if s.Missing["aws_instance.web"] != "foo" {
+		t.Fatalf("bad: %#v", s.Missing)
+	}
 }
 
 func TestContextRefresh_ignoreUncreated(t *testing.T) {
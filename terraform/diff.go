@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 )
 
 // The format byte is prefixed into the diff file format so that we have
@@ -76,7 +77,7 @@ func (d *Diff) Empty() bool {
 	}
 
 	for _, rd := range d.Resources {
-		if !rd.Empty() {
+		if !rd.Empty() || rd.Missing {
 			return false
 		}
 	}
@@ -99,7 +100,9 @@ func (d *Diff) String() string {
 		rdiff := d.Resources[name]
 
 		crud := "UPDATE"
-		if rdiff.RequiresNew() && rdiff.Destroy {
+		if rdiff.Missing && rdiff.Empty() {
+			crud = "FORGET"
+		} else if rdiff.RequiresNew() && rdiff.Destroy {
 			crud = "DESTROY/CREATE"
 		} else if rdiff.Destroy {
 			crud = "DESTROY"
@@ -157,9 +160,31 @@ type ResourceDiff struct {
 	Attributes map[string]*ResourceAttrDiff
 	Destroy    bool
 
+	// Provisioners describes what the provisioners of the resource will
+	// do when it is created. This is only set if the plan was made with
+	// PlanOpts.Provisioners, and is only informational.
+	Provisioners []*ProvisionerDiff
+
+	// Missing is true if the resource was deleted outside of Terraform.
+	// The diff then either creates the resource again, or is otherwise
+	// empty and only removes the resource from the state.
+	Missing bool
+
+	// Timeouts are the timeouts of the resource for each action, keyed by
+	// "create", "update" and "delete", so that providers can wait as
+	// long as they're allowed to. They're only set when the diff is
+	// applied.
+	Timeouts map[string]time.Duration
+
 	once sync.Once
 }
 
+// ProvisionerDiff describes what a provisioner will do when it is run.
+type ProvisionerDiff struct {
+	Type    string
+	Actions []string
+}
+
 // ResourceAttrDiff is the diff of a single attribute of a resource.
 type ResourceAttrDiff struct {
 	Old         string      // Old Value
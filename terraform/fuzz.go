@@ -0,0 +1,27 @@
+// +build gofuzz
+
+package terraform
+
+import (
+	"bytes"
+)
+
+// Fuzz is the entry point for go-fuzz (github.com/dvyukov/go-fuzz). It
+// reads data as a state file and writes it back out, which should only
+// ever return errors for malformed states, never panic:
+//
+//	go-fuzz-build github.com/hashicorp/terraform/terraform
+//	go-fuzz -bin=terraform-fuzz.zip -workdir=fuzz/state
+func Fuzz(data []byte) int {
+	s, err := ReadState(bytes.NewReader(data))
+	if err != nil {
+		return 0
+	}
+
+	s.String()
+	if err := WriteState(s, new(bytes.Buffer)); err != nil {
+		panic(err)
+	}
+
+	return 1
+}
@@ -44,6 +44,11 @@ type GraphOpts struct {
 	// Provisioners is a mapping of names to a resource provisioner.
 	// These must be provided to support resource provisioners.
 	Provisioners map[string]ResourceProvisionerFactory
+
+	// Variables are the values of the variables, keyed like "var.foo".
+	// Resources whose enabled meta-argument is false with these values
+	// are left out of the graph, so they're treated as orphans.
+	Variables map[string]string
 }
 
 // GraphRootNode is the name of the root node in the Terraform resource
@@ -61,6 +66,12 @@ type GraphNodeResource struct {
 	Orphan             bool
 	Resource           *Resource
 	ResourceProviderID string
+
+	// ResourceProvider is the provider node of the resource, and Taggable
+	// is true if the resource has tags that the default tags of the
+	// provider are merged into.
+	ResourceProvider *GraphNodeResourceProvider
+	Taggable         bool
 }
 
 // GraphNodeResourceMeta is a node type in the graph that represents the
@@ -80,6 +91,10 @@ type GraphNodeResourceProvider struct {
 	Providers    map[string]ResourceProvider
 	ProviderKeys []string
 	Config       *config.ProviderConfig
+
+	// DefaultTags are the interpolated default tags of the provider
+	// configuration. They're set when the provider is configured.
+	DefaultTags map[string]string
 }
 
 // Graph builds a dependency graph of all the resources for infrastructure
@@ -103,22 +118,28 @@ func Graph(opts *GraphOpts) (*depgraph.Graph, error) {
 
 	log.Printf("[DEBUG] Creating graph...")
 
+	// Only the resources that are enabled are in the graph
+	c, err := graphEnabledConfig(opts.Config, opts.Variables)
+	if err != nil {
+		return nil, err
+	}
+
 	g := new(depgraph.Graph)
 
 	// First, build the initial resource graph. This only has the resources
 	// and no dependencies.
-	graphAddConfigResources(g, opts.Config, opts.State)
+	graphAddConfigResources(g, c, opts.State)
 
 	// Add explicit dependsOn dependencies to the graph
 	graphAddExplicitDeps(g)
 
 	// Next, add the state orphans if we have any
 	if opts.State != nil {
-		graphAddOrphans(g, opts.Config, opts.State)
+		graphAddOrphans(g, c, opts.State)
 	}
 
 	// Map the provider configurations to all of the resources
-	graphAddProviderConfigs(g, opts.Config)
+	graphAddProviderConfigs(g, c)
 
 	// Setup the provisioners. These may have variable dependencies,
 	// and must be done before dependency setup
@@ -170,6 +191,78 @@ func Graph(opts *GraphOpts) (*depgraph.Graph, error) {
 	return g, nil
 }
 
+// graphEnabledConfig returns the configuration without the resources
+// that aren't enabled with the given variables. It is an error for the
+// resources that are enabled or the outputs to reference them.
+func graphEnabledConfig(
+	c *config.Config, vs map[string]string) (*config.Config, error) {
+	disabled := make(map[string]struct{})
+	for _, r := range c.Resources {
+		enabled, err := r.IsEnabled(vs)
+		if err != nil {
+			return nil, err
+		}
+		if !enabled {
+			log.Printf("[DEBUG] %s isn't enabled", r.Id())
+			disabled[r.Id()] = struct{}{}
+		}
+	}
+	if len(disabled) == 0 {
+		return c, nil
+	}
+
+	var errs []error
+	checkRefs := func(source string, raw *config.RawConfig) {
+		if raw == nil {
+			return
+		}
+
+		for _, v := range raw.Variables {
+			rv, ok := v.(*config.ResourceVariable)
+			if !ok {
+				continue
+			}
+			if _, ok := disabled[rv.ResourceId()]; ok {
+				errs = append(errs, fmt.Errorf(
+					"%s: references %s, which isn't enabled",
+					source, rv.ResourceId()))
+			}
+		}
+	}
+
+	resources := make([]*config.Resource, 0, len(c.Resources))
+	for _, r := range c.Resources {
+		if _, ok := disabled[r.Id()]; ok {
+			continue
+		}
+
+		checkRefs(r.Id(), r.RawConfig)
+		for _, p := range r.Provisioners {
+			checkRefs(r.Id(), p.RawConfig)
+			checkRefs(r.Id(), p.ConnInfo)
+		}
+		for _, d := range r.DependsOn {
+			if _, ok := disabled[d]; ok {
+				errs = append(errs, fmt.Errorf(
+					"%s: depends on %s, which isn't enabled", r.Id(), d))
+			}
+		}
+
+		resources = append(resources, r)
+	}
+	for _, o := range c.Outputs {
+		checkRefs(fmt.Sprintf("output '%s'", o.Name), o.RawConfig)
+	}
+
+	if len(errs) > 0 {
+		return nil, &multierror.Error{Errors: errs}
+	}
+
+	result := *c
+	result.Resources = resources
+	return &result, nil
+}
+
 // configGraph turns a configuration structure into a dependency graph.
 func graphAddConfigResources(
 	g *depgraph.Graph, c *config.Config, s *State) {
@@ -225,10 +318,12 @@ func graphAddConfigResources(
 					Type:   r.Type,
 					Config: r,
 					Resource: &Resource{
-						Id:      name,
-						State:   state,
-						Config:  NewResourceConfig(r.RawConfig),
-						Tainted: tainted,
+						Id:            name,
+						State:         state,
+						Config:        NewResourceConfig(r.RawConfig),
+						Tainted:       tainted,
+						Timeouts:      r.Timeouts,
+						IgnoreChanges: r.Lifecycle.IgnoreChanges,
 					},
 				},
 			}
@@ -653,7 +748,16 @@ func nounAddVariableDeps(
 	n *depgraph.Noun,
 	vars map[string]config.InterpolatedVariable,
 	removeSelf bool) {
-	for _, v := range vars {
+	// Go through the variables in order so that the dependencies are
+	// always in the same order.
+	keys := make([]string, 0, len(vars))
+	for k, _ := range vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v := vars[k]
 		// Only resource variables impose dependencies
 		rv, ok := v.(*config.ResourceVariable)
 		if !ok {
@@ -808,6 +912,8 @@ func graphMapResourceProviders(g *depgraph.Graph) error {
 		}
 
 		rn.Resource.Provider = provider
+		rn.ResourceProvider = rpn
+		rn.Taggable = ProviderTaggable(provider, rn.Type)
 	}
 
 	if len(errs) > 0 {
@@ -66,6 +66,33 @@ func TestGraph_dependsOn(t *testing.T) {
 	}
 }
 
+func TestGraph_depsOrder(t *testing.T) {
+	config := testConfig(t, "graph-deps-order")
+
+	// The dependencies come from a map, so build the graph a few times
+	// to make sure they're always in the same order.
+	for i := 0; i < 10; i++ {
+		g, err := Graph(&GraphOpts{Config: config})
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		var actual []string
+		for _, d := range g.Noun("aws_instance.web").Deps {
+			actual = append(actual, d.Target.Name)
+		}
+
+		expected := []string{
+			"aws_instance.a",
+			"aws_instance.b",
+			"aws_instance.c",
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Fatalf("bad: %#v", actual)
+		}
+	}
+}
+
 func TestGraph_state(t *testing.T) {
 	config := testConfig(t, "graph-basic")
 	state := &State{
@@ -20,6 +20,10 @@ const (
 // some hook points, but not all (which is the likely case), then embed the
 // NilHook into your struct, which implements all of the interface but does
 // nothing. Then, override only the functions you want to implement.
+//
+// Hooks are registered with ContextOpts.Hooks. If a hook returns an
+// error, the resource it was called for fails with the error, as if the
+// provider had returned it, and PreApplyAll errors cancel the apply.
 type Hook interface {
 	// PreApply and PostApply are called before and after a single
 	// resource is applied. The error argument in PostApply is the
@@ -27,6 +31,14 @@ type Hook interface {
 	PreApply(string, *ResourceState, *ResourceDiff) (HookAction, error)
 	PostApply(string, *ResourceState, error) (HookAction, error)
 
+	// PreApplyAll and PostApplyAll are called before and after a whole
+	// apply. PreApplyAll is given the state and the diff that is about
+	// to be applied; halting in it cancels the apply. PostApplyAll is
+	// given the resulting state and the error, if any, that the apply
+	// ended with.
+	PreApplyAll(*State, *Diff) (HookAction, error)
+	PostApplyAll(*State, error) (HookAction, error)
+
 	// PreDiff and PostDiff are called before and after a single resource
 	// resource is diffed.
 	PreDiff(string, *ResourceState) (HookAction, error)
@@ -57,6 +69,14 @@ func (*NilHook) PostApply(string, *ResourceState, error) (HookAction, error) {
 	return HookActionContinue, nil
 }
 
+func (*NilHook) PreApplyAll(*State, *Diff) (HookAction, error) {
+	return HookActionContinue, nil
+}
+
+func (*NilHook) PostApplyAll(*State, error) (HookAction, error) {
+	return HookActionContinue, nil
+}
+
 func (*NilHook) PreDiff(string, *ResourceState) (HookAction, error) {
 	return HookActionContinue, nil
 }
@@ -89,12 +109,18 @@ func (*NilHook) PostRefresh(string, *ResourceState) (HookAction, error) {
 	return HookActionContinue, nil
 }
 
-// handleHook turns hook actions into panics. This lets you use the
-// panic/recover mechanism in Go as a flow control mechanism for hook
+// hookError is the panic value of handleHook for an error returned by
+// a hook, which genericWalkFn turns back into an error.
+type hookError struct {
+	Err error
+}
+
+// handleHook turns hook actions and errors into panics. This lets you use
+// the panic/recover mechanism in Go as a flow control mechanism for hook
 // actions.
 func handleHook(a HookAction, err error) {
 	if err != nil {
-		// TODO: handle errors
+		panic(&hookError{Err: err})
 	}
 
 	switch a {
@@ -17,6 +17,18 @@ type MockHook struct {
 	PostApplyReturn      HookAction
 	PostApplyReturnError error
 
+	PreApplyAllCalled bool
+	PreApplyAllState  *State
+	PreApplyAllDiff   *Diff
+	PreApplyAllReturn HookAction
+	PreApplyAllError  error
+
+	PostApplyAllCalled      bool
+	PostApplyAllState       *State
+	PostApplyAllError       error
+	PostApplyAllReturn      HookAction
+	PostApplyAllReturnError error
+
 	PreDiffCalled bool
 	PreDiffId     string
 	PreDiffState  *ResourceState
@@ -82,6 +94,20 @@ func (h *MockHook) PostApply(n string, s *ResourceState, e error) (HookAction, e
 	return h.PostApplyReturn, h.PostApplyReturnError
 }
 
+func (h *MockHook) PreApplyAll(s *State, d *Diff) (HookAction, error) {
+	h.PreApplyAllCalled = true
+	h.PreApplyAllState = s
+	h.PreApplyAllDiff = d
+	return h.PreApplyAllReturn, h.PreApplyAllError
+}
+
+func (h *MockHook) PostApplyAll(s *State, e error) (HookAction, error) {
+	h.PostApplyAllCalled = true
+	h.PostApplyAllState = s
+	h.PostApplyAllError = e
+	return h.PostApplyAllReturn, h.PostApplyAllReturnError
+}
+
 func (h *MockHook) PreDiff(n string, s *ResourceState) (HookAction, error) {
 	h.PreDiffCalled = true
 	h.PreDiffId = n
@@ -18,6 +18,14 @@ func (h *stopHook) PostApply(string, *ResourceState, error) (HookAction, error)
 	return h.hook()
 }
 
+func (h *stopHook) PreApplyAll(*State, *Diff) (HookAction, error) {
+	return h.hook()
+}
+
+func (h *stopHook) PostApplyAll(*State, error) (HookAction, error) {
+	return h.hook()
+}
+
 func (h *stopHook) PreDiff(string, *ResourceState) (HookAction, error) {
 	return h.hook()
 }
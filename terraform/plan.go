@@ -25,6 +25,23 @@ type PlanOpts struct {
 	// that are created. Otherwise, it will move towards the desired state
 	// specified in the configuration.
 	Destroy bool
+
+	// If set to true, the provisioners of resources that will be created
+	// are asked to describe what they would do, such as the commands
+	// they'd run, without doing it. The descriptions are in the
+	// Provisioners field of the resource diffs.
+	Provisioners bool
+
+	// Forget are the names of resources that were deleted outside of
+	// Terraform that are removed from the state instead of being created
+	// again.
+	Forget []string
+
+	// Resume is the plan of an apply that failed partway, which is
+	// continued by this plan. The diffs of the resources that weren't
+	// applied and whose state hasn't changed since are reused instead of
+	// being diffed again. See Plan.Completed.
+	Resume *Plan
 }
 
 // Plan represents a single Terraform execution plan, which contains
@@ -35,21 +52,42 @@ type Plan struct {
 	State  *State
 	Vars   map[string]string
 
+	// Git is the git revision of the configuration the plan was made
+	// from, so that applying the plan uses the same revision.
+	Git map[string]string
+
+	// TFVersion is the version of Terraform that wrote the plan. It's
+	// set by WritePlan, and empty for plans written before versions were
+	// recorded.
+	TFVersion string
+
+	// Completed are the resources that were applied when an apply of
+	// the plan failed partway. It's set on the plans that are saved to
+	// resume such applies.
+	Completed []string
+
 	once sync.Once
 }
 
 // Context returns a Context with the data encapsulated in this plan.
 //
 // The following fields in opts are overridden by the plan: Config,
-// Diff, State, Variables.
+// Diff, State, Variables, Git.
 func (p *Plan) Context(opts *ContextOpts) *Context {
 	opts.Config = p.Config
 	opts.Diff = p.Diff
 	opts.State = p.State
 	opts.Variables = p.Vars
+	opts.Git = p.Git
 	return NewContext(opts)
 }
 
+// SensitiveValues returns the values of the variables in the plan that
+// are marked sensitive. See Context.SensitiveValues.
+func (p *Plan) SensitiveValues() []string {
+	return sensitiveValues(p.Config, p.Vars)
+}
+
 func (p *Plan) String() string {
 	buf := new(bytes.Buffer)
 	buf.WriteString("DIFF:\n\n")
@@ -81,6 +119,92 @@ func (p *Plan) init() {
 	})
 }
 
+// planResume is the plan of an apply that failed partway, as it's used
+// when planning to continue the apply.
+type planResume struct {
+	plan      *Plan
+	completed map[string]struct{}
+}
+
+func newPlanResume(p *Plan) *planResume {
+	if p == nil {
+		return nil
+	}
+
+	completed := make(map[string]struct{}, len(p.Completed))
+	for _, k := range p.Completed {
+		completed[k] = struct{}{}
+	}
+
+	return &planResume{plan: p, completed: completed}
+}
+
+// Diff returns a copy of the diff of the resource in the plan being
+// resumed, and whether it can be reused: the resource wasn't applied, and
+// its state, given with whether it's tainted, is the same as when the
+// plan was made. Resources without a diff in the plan have a nil one.
+func (p *planResume) Diff(
+	id string, s *ResourceState, tainted bool) (*ResourceDiff, bool) {
+	if p == nil {
+		return nil, false
+	}
+	if _, ok := p.completed[id]; ok {
+		return nil, false
+	}
+
+	var old *ResourceState
+	var oldTainted bool
+	if p.plan.State != nil {
+		old = p.plan.State.Resources[id]
+		_, oldTainted = p.plan.State.Tainted[id]
+	}
+	if tainted != oldTainted || !sameResourceState(old, s) {
+		return nil, false
+	}
+
+	var rd *ResourceDiff
+	if p.plan.Diff != nil {
+		rd = p.plan.Diff.Resources[id]
+	}
+	if rd == nil {
+		return nil, true
+	}
+
+	// The diff is changed while planning, so it's copied
+	result := &ResourceDiff{
+		Attributes:   make(map[string]*ResourceAttrDiff, len(rd.Attributes)),
+		Destroy:      rd.Destroy,
+		Provisioners: rd.Provisioners,
+		Missing:      rd.Missing,
+		Timeouts:     rd.Timeouts,
+	}
+	for k, v := range rd.Attributes {
+		ad := *v
+		result.Attributes[k] = &ad
+	}
+
+	return result, true
+}
+
+// sameResourceState returns whether two states of a resource have the
+// same ID and attributes. Resources without an ID don't exist.
+func sameResourceState(a, b *ResourceState) bool {
+	if a == nil || a.ID == "" {
+		return b == nil || b.ID == ""
+	}
+	if b == nil || a.ID != b.ID || len(a.Attributes) != len(b.Attributes) {
+		return false
+	}
+
+	for k, v := range a.Attributes {
+		if v2, ok := b.Attributes[k]; !ok || v != v2 {
+			return false
+		}
+	}
+
+	return true
+}
+
 // The format byte is prefixed into the plan file format so that we have
 // the ability in the future to change the file format if we want for any
 // reason.
@@ -128,7 +252,8 @@ func ReadPlan(src io.Reader) (*Plan, error) {
 	return result, nil
 }
 
-// WritePlan writes a plan somewhere in a binary format.
+// WritePlan writes a plan somewhere in a binary format. Like in the state
+// file, the connection info of resources is never written.
 func WritePlan(d *Plan, dst io.Writer) error {
 	// Write the magic bytes so we can determine the file format later
 	n, err := dst.Write([]byte(planFormatMagic))
@@ -148,5 +273,14 @@ func WritePlan(d *Plan, dst io.Writer) error {
 		return errors.New("failed to write plan version byte")
 	}
 
-	return gob.NewEncoder(dst).Encode(d)
+	return gob.NewEncoder(dst).Encode(&Plan{
+		Config: d.Config,
+		Diff:   d.Diff,
+		State:  d.State.withoutConnInfo(),
+		Vars:   d.Vars,
+		Git:    d.Git,
+
+		TFVersion: VersionString(),
+		Completed: d.Completed,
+	})
 }
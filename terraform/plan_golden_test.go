@@ -0,0 +1,156 @@
+package terraform
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// updateGolden rewrites the golden files with the current plans instead
+// of comparing against them, with "go test ./terraform -update".
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+// goldenResourceDiff is how a ResourceDiff is written in golden files.
+// Only what the diff engine decides is kept, so that golden files don't
+// change along with unrelated internals.
+type goldenResourceDiff struct {
+	Destroy    bool                       `json:"destroy,omitempty"`
+	Attributes map[string]*goldenAttrDiff `json:"attributes,omitempty"`
+}
+
+type goldenAttrDiff struct {
+	Old         string `json:"old"`
+	New         string `json:"new"`
+	Computed    bool   `json:"computed,omitempty"`
+	Removed     bool   `json:"removed,omitempty"`
+	RequiresNew bool   `json:"requires_new,omitempty"`
+}
+
+func TestContextPlan_golden(t *testing.T) {
+	cases := []struct {
+		Fixture string
+		State   *State
+	}{
+		{"plan-good", nil},
+		{"plan-computed", nil},
+		{"plan-count", nil},
+		{"plan-empty", nil},
+		{
+			"plan-orphan",
+			&State{
+				Resources: map[string]*ResourceState{
+					"aws_instance.baz": &ResourceState{
+						ID:   "bar",
+						Type: "aws_instance",
+					},
+				},
+			},
+		},
+		{
+			"plan-taint",
+			&State{
+				Resources: map[string]*ResourceState{
+					"aws_instance.foo": &ResourceState{
+						ID:         "bar",
+						Type:       "aws_instance",
+						Attributes: map[string]string{"num": "2"},
+					},
+					"aws_instance.bar": &ResourceState{
+						ID:   "baz",
+						Type: "aws_instance",
+					},
+				},
+				Tainted: map[string]struct{}{"aws_instance.bar": struct{}{}},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		p := testProvider("aws")
+		p.DiffFn = testDiffFn
+		ctx := testContext(t, &ContextOpts{
+			Config: testConfig(t, tc.Fixture),
+			Providers: map[string]ResourceProviderFactory{
+				"aws": testProviderFuncFixed(p),
+			},
+			State: tc.State,
+		})
+
+		plan, err := ctx.Plan(nil)
+		if err != nil {
+			t.Fatalf("%s: err: %s", tc.Fixture, err)
+		}
+
+		testPlanGolden(t, tc.Fixture, plan)
+	}
+}
+
+// testPlanGolden compares the plan to the "plan.golden" file in the
+// fixture directory, or writes it there if -update is given.
+func testPlanGolden(t *testing.T, fixture string, plan *Plan) {
+	actual, err := goldenPlan(plan)
+	if err != nil {
+		t.Fatalf("%s: err: %s", fixture, err)
+	}
+
+	path := filepath.Join(fixtureDir, fixture, "plan.golden")
+	if *updateGolden {
+		if err := ioutil.WriteFile(path, actual, 0644); err != nil {
+			t.Fatalf("%s: err: %s", fixture, err)
+		}
+
+		return
+	}
+
+	expected, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%s: err: %s\n\nRun the tests with -update to create it.",
+			fixture, err)
+	}
+
+	if !bytes.Equal(actual, expected) {
+		t.Fatalf(
+			"%s: plan doesn't match %s. If the change is intentional, "+
+				"run the tests with -update.\n\n%s",
+			fixture, path, actual)
+	}
+}
+
+// goldenPlan returns the normalized JSON of the diff of the plan.
+// Resources without changes are left out.
+func goldenPlan(plan *Plan) ([]byte, error) {
+	result := make(map[string]*goldenResourceDiff)
+	if plan.Diff != nil {
+		for k, rd := range plan.Diff.Resources {
+			if rd.Empty() {
+				continue
+			}
+
+			gd := &goldenResourceDiff{Destroy: rd.Destroy}
+			if len(rd.Attributes) > 0 {
+				gd.Attributes = make(map[string]*goldenAttrDiff)
+				for ak, ad := range rd.Attributes {
+					gd.Attributes[ak] = &goldenAttrDiff{
+						Old:         ad.Old,
+						New:         ad.New,
+						Computed:    ad.NewComputed,
+						Removed:     ad.NewRemoved,
+						RequiresNew: ad.RequiresNew,
+					}
+				}
+			}
+
+			result[k] = gd
+		}
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(data, '\n'), nil
+}
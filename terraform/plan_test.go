@@ -7,6 +7,41 @@ import (
 	"testing"
 )
 
+func TestWritePlan_connInfo(t *testing.T) {
+	r := &ResourceState{
+		ID: "bar",
+		ConnInfo: map[string]string{
+			"password": "hunter2",
+		},
+	}
+	plan := &Plan{
+		State: &State{
+			Resources: map[string]*ResourceState{"foo": r},
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	if err := WritePlan(plan, buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte("hunter2")) {
+		t.Fatal("plan should not contain the connection info")
+	}
+
+	// The plan itself is unchanged
+	if r.ConnInfo["password"] != "hunter2" {
+		t.Fatalf("bad: %#v", r)
+	}
+
+	actual, err := ReadPlan(buf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if actual.State.Resources["foo"].ID != "bar" {
+		t.Fatalf("bad: %#v", actual.State)
+	}
+}
+
 func TestReadWritePlan(t *testing.T) {
 	plan := &Plan{
 		Config: testConfig(t, "new-good"),
@@ -41,6 +76,10 @@ func TestReadWritePlan(t *testing.T) {
 		Vars: map[string]string{
 			"foo": "bar",
 		},
+		Git: map[string]string{
+			"commit": "abc123",
+		},
+		Completed: []string{"nodeB"},
 	}
 
 	buf := new(bytes.Buffer)
@@ -55,6 +94,8 @@ func TestReadWritePlan(t *testing.T) {
 
 	println(reflect.DeepEqual(actual.Config.Resources, plan.Config.Resources))
 
+	// The version that wrote the plan is recorded
+	plan.TFVersion = VersionString()
 	if !reflect.DeepEqual(actual, plan) {
 		t.Fatalf("bad: %#v", actual)
 	}
@@ -2,9 +2,12 @@ package terraform
 
 import (
 	"fmt"
+	"log"
+	"os"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform/config"
 )
@@ -33,6 +36,56 @@ type Resource struct {
 	State        *ResourceState
 	Provisioners []*ResourceProvisionerConfig
 	Tainted      bool
+
+	// Timeouts are how long applying the resource may take for each
+	// action, from the timeouts block of its configuration.
+	Timeouts map[string]time.Duration
+
+	// IgnoreChanges are the attributes whose changes are ignored once
+	// the resource exists, from the lifecycle block of its configuration.
+	IgnoreChanges []string
+}
+
+// ignoreChanges removes the changes to the ignored attributes of the
+// resource from the diff. A change to a list or map attribute is
+// ignored as a whole, such as "tags.foo" and "tags.#" for "tags".
+// Nothing is ignored when the resource is created.
+func (r *Resource) ignoreChanges(d *ResourceDiff) {
+	if d == nil || len(r.IgnoreChanges) == 0 ||
+		r.State == nil || r.State.ID == "" {
+		return
+	}
+
+	for k, _ := range d.Attributes {
+		for _, ignored := range r.IgnoreChanges {
+			if k == ignored || strings.HasPrefix(k, ignored+".") {
+				log.Printf("[DEBUG] %s: Ignoring change to %s", r.Id, k)
+				delete(d.Attributes, k)
+				break
+			}
+		}
+	}
+}
+
+// Timeout returns how long the given diff of the resource may take to
+// apply, or zero if it isn't limited. Replacing a resource is limited
+// only if both deleting and creating it are.
+func (r *Resource) Timeout(d *ResourceDiff) time.Duration {
+	switch {
+	case d.Destroy:
+		return r.Timeouts["delete"]
+	case r.State == nil || r.State.ID == "":
+		return r.Timeouts["create"]
+	case d.RequiresNew():
+		del, create := r.Timeouts["delete"], r.Timeouts["create"]
+		if del == 0 || create == 0 {
+			return 0
+		}
+
+		return del + create
+	default:
+		return r.Timeouts["update"]
+	}
 }
 
 // Vars returns the mapping of variables that should be replaced in
@@ -58,7 +111,8 @@ type ResourceConfig struct {
 	Raw          map[string]interface{}
 	Config       map[string]interface{}
 
-	raw *config.RawConfig
+	raw         *config.RawConfig
+	defaultTags map[string]string
 }
 
 // NewResourceConfig creates a new ResourceConfig from a config.RawConfig.
@@ -179,5 +233,111 @@ func (c *ResourceConfig) interpolate(ctx *Context) error {
 		c.Config = c.raw.Config()
 	}
 
+	c.mergeDefaultTags()
 	return nil
 }
+
+// withEnv returns the configuration of a provider with the attributes
+// that aren't set read from the EnvVars declared in its ConfigSchema.
+// They're read here rather than by the provider, so that a plugin that
+// is kept running between runs gets the environment of each run. The
+// configuration isn't changed.
+func (c *ResourceConfig) withEnv(attrs []ConfigAttribute) *ResourceConfig {
+	if c == nil {
+		c = new(ResourceConfig)
+	}
+
+	result := c
+	for _, a := range attrs {
+		if len(a.EnvVars) == 0 || c.IsSet(a.Name) {
+			continue
+		}
+
+		for _, name := range a.EnvVars {
+			v := os.Getenv(name)
+			if v == "" {
+				continue
+			}
+
+			if result == c {
+				result = c.copyConfig()
+			}
+
+			log.Printf("[DEBUG] Reading %s from %s", a.Name, name)
+			result.Raw[a.Name] = v
+			result.Config[a.Name] = v
+			break
+		}
+	}
+
+	return result
+}
+
+// copyConfig returns a copy of the configuration whose Raw and Config
+// can be changed.
+func (c *ResourceConfig) copyConfig() *ResourceConfig {
+	result := *c
+	result.Raw = make(map[string]interface{}, len(c.Raw)+1)
+	for k, v := range c.Raw {
+		result.Raw[k] = v
+	}
+	result.Config = make(map[string]interface{}, len(c.Config)+1)
+	for k, v := range c.Config {
+		result.Config[k] = v
+	}
+
+	return &result
+}
+
+// mergeDefaultTags merges the default tags of the provider into the
+// "tags" of the configuration, with the tags set on the resource itself
+// taking precedence.
+func (c *ResourceConfig) mergeDefaultTags() {
+	if len(c.defaultTags) == 0 {
+		return
+	}
+
+	// If the tags of the resource aren't known yet, they can't be merged
+	// until they are
+	for _, k := range c.ComputedKeys {
+		if k == "tags" {
+			return
+		}
+	}
+
+	tags := make(map[string]interface{})
+	for k, v := range c.defaultTags {
+		tags[k] = v
+	}
+
+	// A tags block is decoded as a list of maps
+	switch v := c.Config["tags"].(type) {
+	case map[string]interface{}:
+		for k, v := range v {
+			tags[k] = v
+		}
+	case []map[string]interface{}:
+		for _, m := range v {
+			for k, v := range m {
+				tags[k] = v
+			}
+		}
+	}
+
+	// The maps belong to the raw configuration, which is shared with
+	// the other walks, so the tags are set on copies
+	c.Config = withKey(c.Config, "tags", tags)
+	c.Raw = withKey(c.Raw, "tags", tags)
+}
+
+// withKey returns a copy of the map with the key set to v.
+func withKey(
+	m map[string]interface{}, k string, v interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(m)+1)
+	for k, v := range m {
+		result[k] = v
+	}
+	result[k] = v
+
+	return result
+}
@@ -1,5 +1,13 @@
 package terraform
 
+import (
+	"errors"
+)
+
+// ErrListNotSupported is returned by ListResources for the types of
+// resources that the provider can't list.
+var ErrListNotSupported = errors.New("listing resources of this type isn't supported")
+
 // ResourceProvider is an interface that must be implemented by any
 // resource provider: the thing that creates and manages the resources in
 // a Terraform configuration.
@@ -36,6 +44,25 @@ type ResourceProvider interface {
 	// Configure returns an error if it occurred.
 	Configure(*ResourceConfig) error
 
+	// ValidateCredentials is called after Configure and checks that the
+	// credentials the provider was configured with work, usually with a
+	// single cheap API call. This lets bad or expired credentials be
+	// reported before any resources are planned or applied.
+	//
+	// Providers that can't check their credentials return nil.
+	ValidateCredentials() error
+
+	// WriteOnlyConfig returns the keys of the provider configuration that
+	// must never be persisted, such as access keys and tokens. They are
+	// removed from plans, so a saved plan is applied with the values
+	// the provider reads from the environment instead.
+	WriteOnlyConfig() []string
+
+	// ConfigSchema returns the attributes of the provider configuration,
+	// with the environment variables each of them can be set with. It
+	// describes the configuration to users and isn't used to validate it.
+	ConfigSchema() []ConfigAttribute
+
 	// Resources returns all the available resource types that this provider
 	// knows how to manage.
 	Resources() []ResourceType
@@ -58,11 +85,99 @@ type ResourceProvider interface {
 	// Refresh refreshes a resource and updates all of its attributes
 	// with the latest information.
 	Refresh(*ResourceState) (*ResourceState, error)
+
+	// ListResources returns the existing resources of the given type,
+	// refreshed, so that resources that were created outside of Terraform
+	// can be brought under its management. The filters narrow down the
+	// resources, and their meaning depends on the type of resource.
+	//
+	// ErrListNotSupported is returned if resources of the type can't be
+	// listed.
+	ListResources(string, map[string]string) ([]*ResourceState, error)
+
+	// Stop is called when the running operation is stopped, such as on an
+	// interrupt, while other calls may still be in progress. The provider
+	// should cancel what it's waiting on, such as API calls or resources
+	// becoming available, so that the calls in progress return promptly.
+	//
+	// Providers that can't cancel anything return nil, and their calls
+	// in progress are left to finish.
+	Stop() error
+
+	// Capabilities returns which of the optional features of this
+	// interface the provider supports, so that a feature a provider
+	// doesn't support can be reported clearly before it's used.
+	Capabilities() ResourceProviderCapabilities
+}
+
+// ResourceProviderCapabilities are the optional features of the
+// ResourceProvider interface that a provider supports.
+type ResourceProviderCapabilities struct {
+	// Unknown is true if the provider can't report its capabilities,
+	// which is the case for plugins built before Capabilities existed.
+	// The other fields are false then, although the provider may support
+	// some of the features.
+	Unknown bool
+
+	// ListResources is true if resources of at least one type can be
+	// listed, such as by "terraform scan".
+	ListResources bool
+
+	// ValidateCredentials is true if the provider checks its credentials,
+	// and Stop is true if it can cancel the calls in progress.
+	ValidateCredentials bool
+	Stop                bool
 }
 
 // ResourceType is a type of resource that a resource provider can manage.
 type ResourceType struct {
 	Name string
+
+	// Taggable is true if the resource has a "tags" mapping, which the
+	// default tags of its provider's configuration are merged into.
+	Taggable bool
+
+	// Attributes are the attributes of the resource, sorted by name.
+	// Providers that can't describe their resources leave them empty.
+	Attributes []ResourceAttribute
+}
+
+// ResourceAttribute is an attribute of a type of resource.
+type ResourceAttribute struct {
+	Name string
+
+	// Type is the type of the value: "bool", "int", "string", "list",
+	// "map" or "set".
+	Type string
+
+	Required  bool
+	Optional  bool
+	Computed  bool
+	ForceNew  bool
+	WriteOnly bool
+
+	// Deprecated is the message shown when the attribute is used, if it
+	// is deprecated.
+	Deprecated string
+
+	// ElemType is the type of the elements of lists and sets of
+	// primitive values, and Attributes the attributes of the elements of
+	// lists and sets of nested resources.
+	ElemType   string
+	Attributes []ResourceAttribute
+}
+
+// ConfigAttribute is an attribute of the configuration of a provider.
+type ConfigAttribute struct {
+	Name      string
+	Required  bool
+	WriteOnly bool
+
+	// EnvVars are the environment variables the value is read from, in
+	// order, if it isn't set in the configuration. The Context reads
+	// them before validating and configuring the provider, so that the
+	// provider doesn't read the environment itself.
+	EnvVars []string
 }
 
 // ResourceProviderFactory is a function type that creates a new instance
@@ -86,3 +201,15 @@ func ProviderSatisfies(p ResourceProvider, n string) bool {
 
 	return false
 }
+
+// ProviderTaggable returns true if the resource type n of the provider
+// has tags.
+func ProviderTaggable(p ResourceProvider, n string) bool {
+	for _, rt := range p.Resources() {
+		if rt.Name == n {
+			return rt.Taggable
+		}
+	}
+
+	return false
+}
@@ -2,6 +2,7 @@ package terraform
 
 import (
 	"sync"
+	"time"
 )
 
 // MockResourceProvider implements ResourceProvider but mocks out all the
@@ -12,38 +13,64 @@ type MockResourceProvider struct {
 	// Anything you want, in case you need to store extra data with the mock.
 	Meta interface{}
 
-	ApplyCalled                  bool
-	ApplyState                   *ResourceState
-	ApplyDiff                    *ResourceDiff
-	ApplyFn                      func(*ResourceState, *ResourceDiff) (*ResourceState, error)
-	ApplyReturn                  *ResourceState
-	ApplyReturnError             error
-	ConfigureCalled              bool
-	ConfigureConfig              *ResourceConfig
-	ConfigureReturnError         error
-	DiffCalled                   bool
-	DiffState                    *ResourceState
-	DiffDesired                  *ResourceConfig
-	DiffFn                       func(*ResourceState, *ResourceConfig) (*ResourceDiff, error)
-	DiffReturn                   *ResourceDiff
-	DiffReturnError              error
-	RefreshCalled                bool
-	RefreshState                 *ResourceState
-	RefreshFn                    func(*ResourceState) (*ResourceState, error)
-	RefreshReturn                *ResourceState
-	RefreshReturnError           error
-	ResourcesCalled              bool
-	ResourcesReturn              []ResourceType
-	ValidateCalled               bool
-	ValidateConfig               *ResourceConfig
-	ValidateReturnWarns          []string
-	ValidateReturnErrors         []error
-	ValidateResourceFn           func(string, *ResourceConfig) ([]string, []error)
-	ValidateResourceCalled       bool
-	ValidateResourceType         string
-	ValidateResourceConfig       *ResourceConfig
-	ValidateResourceReturnWarns  []string
-	ValidateResourceReturnErrors []error
+	// Latency is how long Apply, Diff and Refresh take. The mock isn't
+	// locked while waiting, so calls overlap like they would with a
+	// remote API.
+	Latency time.Duration
+
+	ApplyCalled                    bool
+	ApplyState                     *ResourceState
+	ApplyDiff                      *ResourceDiff
+	ApplyFn                        func(*ResourceState, *ResourceDiff) (*ResourceState, error)
+	ApplyReturn                    *ResourceState
+	ApplyReturnError               error
+	ConfigureCalled                bool
+	ConfigureConfig                *ResourceConfig
+	ConfigureReturnError           error
+	DiffCalled                     bool
+	DiffState                      *ResourceState
+	DiffDesired                    *ResourceConfig
+	DiffFn                         func(*ResourceState, *ResourceConfig) (*ResourceDiff, error)
+	DiffReturn                     *ResourceDiff
+	DiffReturnError                error
+	ListResourcesCalled            bool
+	ListResourcesType              string
+	ListResourcesFilters           map[string]string
+	ListResourcesReturn            []*ResourceState
+	ListResourcesReturnError       error
+	RefreshCalled                  bool
+	RefreshState                   *ResourceState
+	RefreshFn                      func(*ResourceState) (*ResourceState, error)
+	RefreshReturn                  *ResourceState
+	RefreshReturnError             error
+	ResourcesCalled                bool
+	ResourcesReturn                []ResourceType
+	ValidateCalled                 bool
+	ValidateConfig                 *ResourceConfig
+	ValidateReturnWarns            []string
+	ValidateReturnErrors           []error
+	ValidateResourceFn             func(string, *ResourceConfig) ([]string, []error)
+	ValidateResourceCalled         bool
+	ValidateResourceType           string
+	ValidateResourceConfig         *ResourceConfig
+	ValidateResourceReturnWarns    []string
+	ValidateResourceReturnErrors   []error
+	ValidateCredentialsCalled      bool
+	ValidateCredentialsReturnError error
+	WriteOnlyConfigCalled          bool
+	WriteOnlyConfigReturn          []string
+	ConfigSchemaCalled             bool
+	ConfigSchemaReturn             []ConfigAttribute
+
+	// Stop is called while other calls are in progress, which hold the
+	// lock of the mock, so it has its own. So is Capabilities, which is
+	// checked before stopping.
+	stopLock           sync.Mutex
+	StopCalled         bool
+	StopFn             func() error
+	StopReturnError    error
+	CapabilitiesCalled bool
+	CapabilitiesReturn ResourceProviderCapabilities
 }
 
 func (p *MockResourceProvider) Validate(c *ResourceConfig) ([]string, []error) {
@@ -79,9 +106,64 @@ func (p *MockResourceProvider) Configure(c *ResourceConfig) error {
 	return p.ConfigureReturnError
 }
 
+func (p *MockResourceProvider) ValidateCredentials() error {
+	p.Lock()
+	defer p.Unlock()
+
+	p.ValidateCredentialsCalled = true
+	return p.ValidateCredentialsReturnError
+}
+
+func (p *MockResourceProvider) WriteOnlyConfig() []string {
+	p.Lock()
+	defer p.Unlock()
+
+	p.WriteOnlyConfigCalled = true
+	return p.WriteOnlyConfigReturn
+}
+
+func (p *MockResourceProvider) ConfigSchema() []ConfigAttribute {
+	p.Lock()
+	defer p.Unlock()
+
+	p.ConfigSchemaCalled = true
+	return p.ConfigSchemaReturn
+}
+
+func (p *MockResourceProvider) Capabilities() ResourceProviderCapabilities {
+	p.stopLock.Lock()
+	defer p.stopLock.Unlock()
+
+	p.CapabilitiesCalled = true
+	return p.CapabilitiesReturn
+}
+
+func (p *MockResourceProvider) Stop() error {
+	p.stopLock.Lock()
+	defer p.stopLock.Unlock()
+
+	p.StopCalled = true
+	if p.StopFn != nil {
+		return p.StopFn()
+	}
+
+	return p.StopReturnError
+}
+
+// Stopped returns whether Stop was called, without the lock of the mock,
+// so that it can be used from the functions of the mock.
+func (p *MockResourceProvider) Stopped() bool {
+	p.stopLock.Lock()
+	defer p.stopLock.Unlock()
+
+	return p.StopCalled
+}
+
 func (p *MockResourceProvider) Apply(
 	state *ResourceState,
 	diff *ResourceDiff) (*ResourceState, error) {
+	p.wait()
+
 	p.Lock()
 	defer p.Unlock()
 
@@ -98,6 +180,8 @@ func (p *MockResourceProvider) Apply(
 func (p *MockResourceProvider) Diff(
 	state *ResourceState,
 	desired *ResourceConfig) (*ResourceDiff, error) {
+	p.wait()
+
 	p.Lock()
 	defer p.Unlock()
 
@@ -113,6 +197,8 @@ func (p *MockResourceProvider) Diff(
 
 func (p *MockResourceProvider) Refresh(
 	s *ResourceState) (*ResourceState, error) {
+	p.wait()
+
 	p.Lock()
 	defer p.Unlock()
 
@@ -126,6 +212,17 @@ func (p *MockResourceProvider) Refresh(
 	return p.RefreshReturn, p.RefreshReturnError
 }
 
+func (p *MockResourceProvider) ListResources(
+	t string, filters map[string]string) ([]*ResourceState, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.ListResourcesCalled = true
+	p.ListResourcesType = t
+	p.ListResourcesFilters = filters
+	return p.ListResourcesReturn, p.ListResourcesReturnError
+}
+
 func (p *MockResourceProvider) Resources() []ResourceType {
 	p.Lock()
 	defer p.Unlock()
@@ -133,3 +230,12 @@ func (p *MockResourceProvider) Resources() []ResourceType {
 	p.ResourcesCalled = true
 	return p.ResourcesReturn
 }
+
+// wait sleeps for the configured Latency.
+func (p *MockResourceProvider) wait() {
+	p.Lock()
+	d := p.Latency
+	p.Unlock()
+
+	time.Sleep(d)
+}
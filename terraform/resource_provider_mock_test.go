@@ -1,9 +1,35 @@
 package terraform
 
 import (
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestMockResourceProvider_impl(t *testing.T) {
 	var _ ResourceProvider = new(MockResourceProvider)
 }
+
+func TestMockResourceProvider_latency(t *testing.T) {
+	p := &MockResourceProvider{Latency: 50 * time.Millisecond}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.Refresh(nil)
+		}()
+	}
+	wg.Wait()
+
+	// The calls should overlap rather than wait on each other
+	d := time.Since(start)
+	if d < p.Latency || d >= 4*p.Latency {
+		t.Fatalf("bad: %s", d)
+	}
+	if !p.RefreshCalled {
+		t.Fatal("refresh should be called")
+	}
+}
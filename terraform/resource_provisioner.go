@@ -21,6 +21,21 @@ type ResourceProvisioner interface {
 	// is provided since provisioners only run after a resource has been
 	// newly created.
 	Apply(*ResourceState, *ResourceConfig) error
+
+	// Describe returns what Apply would do with the same arguments, such
+	// as the commands it would run and where, without doing any of it.
+	// This is used to show provisioning in an execution plan, so it must
+	// not connect to the resource.
+	//
+	// Values that are computed are not known yet, so they'll be missing
+	// from the configuration and the connection info.
+	Describe(*ResourceState, *ResourceConfig) ([]string, error)
+
+	// Stop is called when the running operation is stopped, such as on an
+	// interrupt, while Apply may still be in progress. The provisioner
+	// should cancel what it's running, such as commands or connections
+	// it's retrying, so that Apply returns promptly with an error.
+	Stop() error
 }
 
 // ResourceProvisionerFactory is a function type that creates a new instance
@@ -12,11 +12,22 @@ type MockResourceProvisioner struct {
 	ApplyFn          func(*ResourceState, *ResourceConfig) error
 	ApplyReturnError error
 
+	DescribeCalled      bool
+	DescribeState       *ResourceState
+	DescribeConfig      *ResourceConfig
+	DescribeFn          func(*ResourceState, *ResourceConfig) ([]string, error)
+	DescribeReturn      []string
+	DescribeReturnError error
+
 	ValidateCalled       bool
 	ValidateConfig       *ResourceConfig
 	ValidateFn           func(c *ResourceConfig) ([]string, []error)
 	ValidateReturnWarns  []string
 	ValidateReturnErrors []error
+
+	StopCalled      bool
+	StopFn          func() error
+	StopReturnError error
 }
 
 func (p *MockResourceProvisioner) Validate(c *ResourceConfig) ([]string, []error) {
@@ -37,3 +48,22 @@ func (p *MockResourceProvisioner) Apply(state *ResourceState, c *ResourceConfig)
 	}
 	return p.ApplyReturnError
 }
+
+func (p *MockResourceProvisioner) Describe(
+	state *ResourceState, c *ResourceConfig) ([]string, error) {
+	p.DescribeCalled = true
+	p.DescribeState = state
+	p.DescribeConfig = c
+	if p.DescribeFn != nil {
+		return p.DescribeFn(state, c)
+	}
+	return p.DescribeReturn, p.DescribeReturnError
+}
+
+func (p *MockResourceProvisioner) Stop() error {
+	p.StopCalled = true
+	if p.StopFn != nil {
+		return p.StopFn()
+	}
+	return p.StopReturnError
+}
@@ -1,8 +1,10 @@
 package terraform
 
 import (
+	"os"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform/config"
 )
@@ -32,6 +34,49 @@ func TestResource_Vars(t *testing.T) {
 	}
 }
 
+func TestResource_Timeout(t *testing.T) {
+	timeouts := map[string]time.Duration{
+		"create": 2 * time.Minute,
+		"delete": 1 * time.Minute,
+	}
+	replace := &ResourceDiff{
+		Attributes: map[string]*ResourceAttrDiff{
+			"ami": &ResourceAttrDiff{Old: "a", New: "b", RequiresNew: true},
+		},
+	}
+	update := &ResourceDiff{
+		Attributes: map[string]*ResourceAttrDiff{
+			"ami": &ResourceAttrDiff{Old: "a", New: "b"},
+		},
+	}
+
+	cases := []struct {
+		Timeouts map[string]time.Duration
+		State    *ResourceState
+		Diff     *ResourceDiff
+		Result   time.Duration
+	}{
+		{timeouts, nil, update, 2 * time.Minute},
+		{timeouts, &ResourceState{ID: "foo"}, &ResourceDiff{Destroy: true}, time.Minute},
+		{timeouts, &ResourceState{ID: "foo"}, replace, 3 * time.Minute},
+		{timeouts, &ResourceState{ID: "foo"}, update, 0},
+		{
+			map[string]time.Duration{"create": time.Minute},
+			&ResourceState{ID: "foo"},
+			replace,
+			0,
+		},
+		{nil, nil, update, 0},
+	}
+
+	for i, tc := range cases {
+		r := &Resource{State: tc.State, Timeouts: tc.Timeouts}
+		if actual := r.Timeout(tc.Diff); actual != tc.Result {
+			t.Fatalf("%d: bad: %s", i, actual)
+		}
+	}
+}
+
 func TestResourceConfigGet(t *testing.T) {
 	cases := []struct {
 		Config map[string]interface{}
@@ -93,3 +138,40 @@ func TestResourceConfigGet(t *testing.T) {
 		}
 	}
 }
+
+func TestResourceConfig_withEnv(t *testing.T) {
+	defer os.Setenv("TF_TEST_TOKEN", os.Getenv("TF_TEST_TOKEN"))
+	os.Setenv("TF_TEST_TOKEN", "")
+
+	attrs := []ConfigAttribute{
+		ConfigAttribute{
+			Name:    "token",
+			EnvVars: []string{"TF_TEST_TOKEN"},
+		},
+		ConfigAttribute{Name: "address"},
+	}
+
+	// Nothing is set in the environment
+	c := &ResourceConfig{
+		Raw:    map[string]interface{}{"address": "foo"},
+		Config: map[string]interface{}{"address": "foo"},
+	}
+	if actual := c.withEnv(attrs); actual != c {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	os.Setenv("TF_TEST_TOKEN", "env")
+	actual := c.withEnv(attrs)
+	if actual.Config["token"] != "env" || actual.Raw["token"] != "env" {
+		t.Fatalf("bad: %#v", actual)
+	}
+	if _, ok := c.Config["token"]; ok {
+		t.Fatalf("config shouldn't be changed: %#v", c.Config)
+	}
+
+	// Without any configuration at all
+	var nilConfig *ResourceConfig
+	if v, ok := nilConfig.withEnv(attrs).Get("token"); !ok || v != "env" {
+		t.Fatalf("bad: %#v", v)
+	}
+}
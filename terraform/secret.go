@@ -0,0 +1,65 @@
+package terraform
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/config"
+)
+
+// SecretBackend is a source of secrets, such as Vault or the environment,
+// that the configuration can reference with "${secret.BACKEND.KEY}".
+//
+// Secrets are read whenever they're interpolated during a plan or apply.
+// They're never stored in a plan, so a plan that uses them reads them
+// again when it is applied.
+type SecretBackend interface {
+	// Secret returns the value of the secret with the given key.
+	Secret(key string) (string, error)
+}
+
+// smcSecrets does the semantic checks to verify that all the secrets
+// referenced in the configuration come from a known backend.
+func smcSecrets(c *config.Config, backends map[string]SecretBackend) []error {
+	var errs []error
+
+	seen := make(map[string]struct{})
+	check := func(raw *config.RawConfig) {
+		if raw == nil {
+			return
+		}
+
+		for _, v := range raw.Variables {
+			sv, ok := v.(*config.SecretVariable)
+			if !ok {
+				continue
+			}
+			if _, ok := backends[sv.Backend]; ok {
+				continue
+			}
+			if _, ok := seen[sv.Backend]; ok {
+				continue
+			}
+
+			seen[sv.Backend] = struct{}{}
+			errs = append(errs, fmt.Errorf(
+				"%s: unknown secret backend '%s'",
+				sv.FullKey(), sv.Backend))
+		}
+	}
+
+	for _, pc := range c.ProviderConfigs {
+		check(pc.RawConfig)
+	}
+	for _, r := range c.Resources {
+		check(r.RawConfig)
+		for _, p := range r.Provisioners {
+			check(p.RawConfig)
+			check(p.ConnInfo)
+		}
+	}
+	for _, o := range c.Outputs {
+		check(o.RawConfig)
+	}
+
+	return errs
+}
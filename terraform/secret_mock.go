@@ -0,0 +1,32 @@
+package terraform
+
+import (
+	"fmt"
+	"sync"
+)
+
+// MockSecretBackend is an implementation of SecretBackend that can be
+// used for tests. It returns the secrets in Values.
+type MockSecretBackend struct {
+	sync.Mutex
+
+	Values map[string]string
+
+	SecretCalled bool
+	SecretKeys   []string
+}
+
+func (b *MockSecretBackend) Secret(key string) (string, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.SecretCalled = true
+	b.SecretKeys = append(b.SecretKeys, key)
+
+	v, ok := b.Values[key]
+	if !ok {
+		return "", fmt.Errorf("secret not found: %s", key)
+	}
+
+	return v, nil
+}
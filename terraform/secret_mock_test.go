@@ -0,0 +1,9 @@
+package terraform
+
+import (
+	"testing"
+)
+
+func TestMockSecretBackend_impl(t *testing.T) {
+	var _ SecretBackend = new(MockSecretBackend)
+}
@@ -47,6 +47,24 @@ func smcUserVariables(c *config.Config, vs map[string]string) []error {
 		}
 	}
 
+	// Check the values against the validations of the variables,
+	// using the default if a value isn't set
+	for _, v := range c.Variables {
+		if len(v.Validations) == 0 || v.Type() != config.VariableTypeString {
+			continue
+		}
+
+		value, ok := vs[v.Name]
+		if !ok {
+			if v.Default == nil {
+				continue
+			}
+			value = v.Default.(string)
+		}
+
+		errs = append(errs, v.ValidateValue(value)...)
+	}
+
 	// TODO(mitchellh): variables that are unknown
 
 	return errs
@@ -38,3 +38,31 @@ func TestSMCUserVariables(t *testing.T) {
 	}
 
 }
+
+func TestSMCUserVariables_validation(t *testing.T) {
+	c := testConfig(t, "smc-uservars-validation")
+
+	// Defaults are valid
+	errs := smcUserVariables(c, nil)
+	if len(errs) != 0 {
+		t.Fatalf("err: %#v", errs)
+	}
+
+	// Valid values
+	errs = smcUserVariables(c, map[string]string{
+		"environment": "prod",
+		"instances":   "5",
+	})
+	if len(errs) != 0 {
+		t.Fatalf("err: %#v", errs)
+	}
+
+	// Invalid values
+	errs = smcUserVariables(c, map[string]string{
+		"environment": "test",
+		"instances":   "20",
+	})
+	if len(errs) != 2 {
+		t.Fatalf("bad: %#v", errs)
+	}
+}
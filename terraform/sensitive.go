@@ -0,0 +1,47 @@
+package terraform
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/config"
+)
+
+// sensitiveValues returns the values of the variables that are marked
+// sensitive in the configuration: the values that were set, falling back
+// to the defaults in the configuration.
+func sensitiveValues(c *config.Config, vars map[string]string) []string {
+	values := make(map[string]struct{})
+
+	if c != nil {
+		for _, v := range c.Variables {
+			if !v.Sensitive {
+				continue
+			}
+
+			// The defaults map of a map variable includes the name of the
+			// variable itself, which isn't sensitive.
+			for k, val := range v.DefaultsMap() {
+				if k != "var."+v.Name || v.Type() == config.VariableTypeString {
+					values[val] = struct{}{}
+				}
+			}
+
+			for k, val := range vars {
+				if k == v.Name || strings.HasPrefix(k, v.Name+".") {
+					values[val] = struct{}{}
+				}
+			}
+		}
+	}
+
+	result := make([]string, 0, len(values))
+	for v, _ := range values {
+		if v != "" {
+			result = append(result, v)
+		}
+	}
+	sort.Strings(result)
+
+	return result
+}
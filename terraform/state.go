@@ -1,12 +1,18 @@
 package terraform
 
 import (
+	"bufio"
 	"bytes"
+	"compress/gzip"
+	"crypto/rand"
 	"encoding/gob"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/hashicorp/terraform/config"
@@ -16,9 +22,38 @@ import (
 // can use to keep track of what real world resources it is actually
 // managing.
 type State struct {
-	Outputs   map[string]string
-	Resources map[string]*ResourceState
-	Tainted   map[string]struct{}
+	Outputs   map[string]string         `json:"outputs,omitempty"`
+	Resources map[string]*ResourceState `json:"resources,omitempty"`
+	Tainted   map[string]struct{}       `json:"tainted,omitempty"`
+
+	// Missing are the resources that a refresh found were deleted outside
+	// of Terraform, mapped to the ID they had. They're removed from
+	// Resources, and recorded here until they're recreated or removed
+	// from the state by an apply, so that plans can explain what happened.
+	Missing map[string]string `json:"missing,omitempty"`
+
+	// Serial is incremented every time the state is written, so that
+	// each version of the state can be told apart.
+	Serial int64 `json:"serial,omitempty"`
+
+	// Lineage is a unique ID given to the state when it's first
+	// persisted, and kept by every later version of it. Together with
+	// Serial, it tells whether one state is a newer version of another
+	// or an unrelated state.
+	Lineage string `json:"lineage,omitempty"`
+
+	// SensitiveOutputs are the names of the outputs that are marked
+	// sensitive, whose values shouldn't be shown.
+	SensitiveOutputs map[string]struct{} `json:"sensitive_outputs,omitempty"`
+
+	// Metadata is information about the run that last wrote the state,
+	// such as the git revision of the configuration.
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// TFVersion is the version of Terraform that wrote the state, as read
+	// by ReadState. It's empty for states written before versions were
+	// recorded. WriteState always records the running version.
+	TFVersion string `json:"-"`
 
 	once sync.Once
 }
@@ -32,6 +67,10 @@ func (s *State) init() {
 		if s.Tainted == nil {
 			s.Tainted = make(map[string]struct{})
 		}
+
+		if s.Missing == nil {
+			s.Missing = make(map[string]string)
+		}
 	})
 }
 
@@ -39,17 +78,76 @@ func (s *State) deepcopy() *State {
 	result := new(State)
 	result.init()
 	if s != nil {
+		result.Serial = s.Serial
+		result.Lineage = s.Lineage
+		result.Metadata = s.Metadata
+		result.TFVersion = s.TFVersion
 		for k, v := range s.Resources {
 			result.Resources[k] = v
 		}
 		for k, v := range s.Tainted {
 			result.Tainted[k] = v
 		}
+		for k, v := range s.Missing {
+			result.Missing[k] = v
+		}
 	}
 
 	return result
 }
 
+// withoutConnInfo returns a copy of the state with the connection info
+// of every resource removed, since it is sensitive.
+func (s *State) withoutConnInfo() *State {
+	if s == nil {
+		return nil
+	}
+
+	result := &State{
+		Outputs:          s.Outputs,
+		Resources:        make(map[string]*ResourceState, len(s.Resources)),
+		Tainted:          s.Tainted,
+		Missing:          s.Missing,
+		Serial:           s.Serial,
+		Lineage:          s.Lineage,
+		SensitiveOutputs: s.SensitiveOutputs,
+		Metadata:         s.Metadata,
+		TFVersion:        s.TFVersion,
+	}
+	for k, r := range s.Resources {
+		if r != nil && r.ConnInfo != nil {
+			rs := *r
+			rs.ConnInfo = nil
+			r = &rs
+		}
+
+		result.Resources[k] = r
+	}
+
+	return result
+}
+
+// InitLineage gives the state a new, random lineage if it doesn't have
+// one yet. It's called whenever the state is persisted.
+func (s *State) InitLineage() error {
+	if s.Lineage != "" {
+		return nil
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Errorf("error generating state lineage: %s", err)
+	}
+
+	// Set the version (4) and the variant (RFC 4122) of a UUID
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	s.Lineage = fmt.Sprintf(
+		"%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+	return nil
+}
+
 // prune is a helper that removes any empty IDs from the state
 // and cleans it up in general.
 func (s *State) prune() {
@@ -60,9 +158,166 @@ func (s *State) prune() {
 	}
 }
 
+// Compact prunes the state of anything that doesn't need to be stored:
+// resources that were never created, taint markers for resources that
+// no longer exist, missing markers for resources that exist again, and
+// empty maps and lists.
+func (s *State) Compact() {
+	s.prune()
+
+	for k, _ := range s.Tainted {
+		if _, ok := s.Resources[k]; !ok {
+			delete(s.Tainted, k)
+		}
+	}
+	if len(s.Tainted) == 0 {
+		s.Tainted = nil
+	}
+	for k, _ := range s.Missing {
+		if _, ok := s.Resources[k]; ok {
+			delete(s.Missing, k)
+		}
+	}
+	if len(s.Missing) == 0 {
+		s.Missing = nil
+	}
+	if len(s.Outputs) == 0 {
+		s.Outputs = nil
+	}
+	for k, _ := range s.SensitiveOutputs {
+		if _, ok := s.Outputs[k]; !ok {
+			delete(s.SensitiveOutputs, k)
+		}
+	}
+	if len(s.SensitiveOutputs) == 0 {
+		s.SensitiveOutputs = nil
+	}
+
+	for _, r := range s.Resources {
+		if len(r.Attributes) == 0 {
+			r.Attributes = nil
+		}
+		if len(r.Extra) == 0 {
+			r.Extra = nil
+		}
+		if len(r.Dependencies) == 0 {
+			r.Dependencies = nil
+		}
+	}
+}
+
+// Move renames a resource in the state, so that renaming it in the
+// configuration doesn't destroy and create it again. The names are in
+// the form of "type.name", optionally followed by the index of one
+// instance of a resource with a count.
+//
+// If the source is the name of a resource with a count, all its
+// instances are moved. Taint and missing markers are moved along with
+// the resources. The names that were moved are returned, mapped to
+// their new names.
+func (s *State) Move(from, to string) (map[string]string, error) {
+	fromType, _, fromIndex, err := parseStateName(from)
+	if err != nil {
+		return nil, err
+	}
+	toType, _, toIndex, err := parseStateName(to)
+	if err != nil {
+		return nil, err
+	}
+	if fromType != toType {
+		return nil, fmt.Errorf(
+			"can't move %s to %s: the resource type can't change", from, to)
+	}
+
+	result := make(map[string]string)
+	if _, ok := s.Resources[from]; ok {
+		result[from] = to
+	} else if fromIndex == -1 {
+		for k, _ := range s.Resources {
+			if !strings.HasPrefix(k, from+".") {
+				continue
+			}
+
+			idx := k[len(from)+1:]
+			if _, err := strconv.Atoi(idx); err != nil {
+				continue
+			}
+
+			result[k] = to + "." + idx
+		}
+
+		if len(result) > 0 && toIndex != -1 {
+			return nil, fmt.Errorf(
+				"can't move all instances of %s to the single instance %s",
+				from, to)
+		}
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("%s is not in the state", from)
+	}
+
+	for _, v := range result {
+		if _, ok := s.Resources[v]; ok {
+			if _, moved := result[v]; !moved {
+				return nil, fmt.Errorf("%s is already in the state", v)
+			}
+		}
+	}
+
+	// All the resources are removed before any are added, so that
+	// instances can be moved to names that are also being moved.
+	resources := make(map[string]*ResourceState)
+	tainted := make(map[string]bool)
+	for k, _ := range result {
+		resources[k] = s.Resources[k]
+		_, tainted[k] = s.Tainted[k]
+		delete(s.Resources, k)
+		delete(s.Tainted, k)
+	}
+	for k, v := range result {
+		s.Resources[v] = resources[k]
+		if tainted[k] {
+			if s.Tainted == nil {
+				s.Tainted = make(map[string]struct{})
+			}
+
+			s.Tainted[v] = struct{}{}
+		}
+		if id, ok := s.Missing[k]; ok {
+			delete(s.Missing, k)
+			s.Missing[v] = id
+		}
+	}
+
+	return result, nil
+}
+
+// parseStateName parses the name of a resource in the state into its
+// type, name and index, which is -1 if there is no index.
+func parseStateName(n string) (string, string, int, error) {
+	parts := strings.Split(n, ".")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return "", "", 0, fmt.Errorf(
+			"invalid resource name %q: must be TYPE.NAME or TYPE.NAME.INDEX", n)
+	}
+
+	index := -1
+	if len(parts) == 3 {
+		var err error
+		index, err = strconv.Atoi(parts[2])
+		if err != nil || index < 0 {
+			return "", "", 0, fmt.Errorf(
+				"invalid resource name %q: the index must be a number", n)
+		}
+	}
+
+	return parts[0], parts[1], index, nil
+}
+
 // Orphans returns a list of keys of resources that are in the State
 // but aren't present in the configuration itself. Hence, these keys
-// represent the state of resources that are orphans.
+// represent the state of resources that are orphans. The keys are
+// sorted.
 func (s *State) Orphans(c *config.Config) []string {
 	keys := make(map[string]struct{})
 	for k, _ := range s.Resources {
@@ -82,6 +337,7 @@ func (s *State) Orphans(c *config.Config) []string {
 	for k, _ := range keys {
 		result = append(result, k)
 	}
+	sort.Strings(result)
 
 	return result
 }
@@ -152,33 +408,110 @@ func (s *State) String() string {
 		}
 	}
 
-	return buf.String()
-}
+	if len(s.Missing) > 0 {
+		buf.WriteString("\nMissing:\n\n")
+
+		ks := make([]string, 0, len(s.Missing))
+		for k, _ := range s.Missing {
+			ks = append(ks, k)
+		}
+		sort.Strings(ks)
 
-// sensitiveState is used to store sensitive state information
-// that should not be serialized. This is only used temporarily
-// and is restored into the state.
-type sensitiveState struct {
-	ConnInfo map[string]map[string]string
+		for _, k := range ks {
+			buf.WriteString(fmt.Sprintf("%s (ID: %s)\n", k, s.Missing[k]))
+		}
+	}
 
-	once sync.Once
+	return buf.String()
 }
 
-func (s *sensitiveState) init() {
-	s.once.Do(func() {
-		s.ConnInfo = make(map[string]map[string]string)
-	})
-}
+// StateVersion is the version of the JSON state format written by
+// WriteState. Older state files written in the binary format can still
+// be read by ReadState, but are always rewritten as JSON.
+const StateVersion = 2
 
-// The format byte is prefixed into the state file format so that we have
-// the ability in the future to change the file format if we want for any
-// reason.
+// The magic bytes and version of the original binary state format.
+// These are only used to detect and read older state files.
 const stateFormatMagic = "tfstate"
 const stateFormatVersion byte = 1
 
+// gzipMagic are the first bytes of any gzip stream, used to detect
+// state files that were compressed at rest.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// jsonState is the structure that is actually serialized for a state
+// file, so that the format version is recorded alongside the state.
+type jsonState struct {
+	Version          int    `json:"version"`
+	TerraformVersion string `json:"terraform_version,omitempty"`
+	*State
+}
+
 // ReadState reads a state structure out of a reader in the format that
-// was written by WriteState.
+// was written by WriteState or WriteStateCompressed. State files in the
+// older binary format are also supported.
 func ReadState(src io.Reader) (*State, error) {
+	r := bufio.NewReader(src)
+
+	// If the state was compressed, transparently decompress it
+	if magic, err := r.Peek(len(gzipMagic)); err == nil &&
+		bytes.Equal(magic, gzipMagic) {
+		gzr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("error decompressing state: %s", err)
+		}
+		defer gzr.Close()
+
+		r = bufio.NewReader(gzr)
+	}
+
+	var state *State
+	magic, err := r.Peek(len(stateFormatMagic))
+	if err == nil && string(magic) == stateFormatMagic {
+		state, err = readStateV1(r)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		result := &jsonState{State: new(State)}
+		if err := json.NewDecoder(r).Decode(result); err != nil {
+			return nil, fmt.Errorf("not a valid state file: %s", err)
+		}
+		if result.Version > StateVersion {
+			return nil, fmt.Errorf(
+				"unknown state file version: %d", result.Version)
+		}
+
+		state = result.State
+		state.TFVersion = result.TerraformVersion
+	}
+
+	if err := state.validate(); err != nil {
+		return nil, fmt.Errorf("not a valid state file: %s", err)
+	}
+
+	return state, nil
+}
+
+// validate checks for what a state can't contain if it was written by
+// WriteState, so that a corrupted state file is an error rather than
+// a crash later on.
+func (s *State) validate() error {
+	if s == nil {
+		return errors.New("state is empty")
+	}
+
+	for k, r := range s.Resources {
+		if r == nil {
+			return fmt.Errorf("resource %s has no state", k)
+		}
+	}
+
+	return nil
+}
+
+// readStateV1 reads a state in the original binary format.
+func readStateV1(src io.Reader) (*State, error) {
 	var result *State
 	var err error
 	n := 0
@@ -218,45 +551,28 @@ func ReadState(src io.Reader) (*State, error) {
 	return result, nil
 }
 
-// WriteState writes a state somewhere in a binary format.
+// WriteState writes a state somewhere in JSON format. The state is
+// streamed directly to the writer. Sensitive information such as the
+// connection info of resources is never written. The running version of
+// Terraform is recorded, so that older versions can refuse to read it.
 func WriteState(d *State, dst io.Writer) error {
-	// Write the magic bytes so we can determine the file format later
-	n, err := dst.Write([]byte(stateFormatMagic))
-	if err != nil {
-		return err
-	}
-	if n != len(stateFormatMagic) {
-		return errors.New("failed to write state format magic bytes")
-	}
+	return json.NewEncoder(dst).Encode(&jsonState{
+		Version:          StateVersion,
+		TerraformVersion: VersionString(),
+		State:            d,
+	})
+}
 
-	// Write a version byte so we can iterate on version at some point
-	n, err = dst.Write([]byte{stateFormatVersion})
-	if err != nil {
+// WriteStateCompressed writes a state in the same format as WriteState,
+// but gzip-compressed. ReadState detects and reads compressed state.
+func WriteStateCompressed(d *State, dst io.Writer) error {
+	gzw := gzip.NewWriter(dst)
+	if err := WriteState(d, gzw); err != nil {
+		gzw.Close()
 		return err
 	}
-	if n != 1 {
-		return errors.New("failed to write state version byte")
-	}
-
-	// Prevent sensitive information from being serialized
-	sensitive := &sensitiveState{}
-	sensitive.init()
-	for name, r := range d.Resources {
-		if r.ConnInfo != nil {
-			sensitive.ConnInfo[name] = r.ConnInfo
-			r.ConnInfo = nil
-		}
-	}
-
-	// Serialize the state
-	err = gob.NewEncoder(dst).Encode(d)
-
-	// Restore the state
-	for name, info := range sensitive.ConnInfo {
-		d.Resources[name].ConnInfo = info
-	}
 
-	return err
+	return gzw.Close()
 }
 
 // ResourceState holds the state of a resource that is used so that
@@ -273,7 +589,7 @@ type ResourceState struct {
 	// This is filled in and managed by Terraform, and is the resource
 	// type itself such as "mycloud_instance". If a resource provider sets
 	// this value, it won't be persisted.
-	Type string
+	Type string `json:"type"`
 
 	// The attributes below are all meant to be filled in by the
 	// resource providers themselves. Documentation for each are above
@@ -281,22 +597,23 @@ type ResourceState struct {
 
 	// A unique ID for this resource. This is opaque to Terraform
 	// and is only meant as a lookup mechanism for the providers.
-	ID string
+	ID string `json:"id"`
 
 	// Attributes are basic information about the resource. Any keys here
 	// are accessible in variable format within Terraform configurations:
 	// ${resourcetype.name.attribute}.
-	Attributes map[string]string
+	Attributes map[string]string `json:"attributes,omitempty"`
 
 	// ConnInfo is used for the providers to export information which is
 	// used to connect to the resource for provisioning. For example,
-	// this could contain SSH or WinRM credentials.
-	ConnInfo map[string]string
+	// this could contain SSH or WinRM credentials. Since it is sensitive,
+	// it is never persisted in the state file.
+	ConnInfo map[string]string `json:"-"`
 
 	// Extra information that the provider can store about a resource.
 	// This data is opaque, never shown to the user, and is sent back to
 	// the provider as-is for whatever purpose appropriate.
-	Extra map[string]interface{}
+	Extra map[string]interface{} `json:"extra,omitempty"`
 
 	// Dependencies are a list of things that this resource relies on
 	// existing to remain intact. For example: an AWS instance might
@@ -311,7 +628,7 @@ type ResourceState struct {
 	// Terraform. If Terraform doesn't find a matching ID in the
 	// overall state, then it assumes it isn't managed and doesn't
 	// worry about it.
-	Dependencies []ResourceDependency
+	Dependencies []ResourceDependency `json:"depends_on,omitempty"`
 }
 
 // MergeDiff takes a ResourceDiff and merges the attributes into
@@ -361,5 +678,5 @@ func (s *ResourceState) GoString() string {
 type ResourceDependency struct {
 	// ID of the resource that we depend on. This ID should map
 	// directly to another ResourceState's ID.
-	ID string
+	ID string `json:"id"`
 }
@@ -2,7 +2,10 @@ package terraform
 
 import (
 	"bytes"
+	"encoding/gob"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform/config"
@@ -129,7 +132,332 @@ func TestReadWriteState(t *testing.T) {
 	// ReadState should not restore sensitive information!
 	state.Resources["foo"].ConnInfo = nil
 
+	// The version that wrote the state is read back
+	state.TFVersion = VersionString()
+
+	if !reflect.DeepEqual(actual, state) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestReadWriteState_compressed(t *testing.T) {
+	state := &State{
+		Outputs: map[string]string{
+			"foo": "bar",
+		},
+		Resources: map[string]*ResourceState{
+			"foo": &ResourceState{
+				ID: "bar",
+				Attributes: map[string]string{
+					"id": "bar",
+				},
+			},
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	if err := WriteStateCompressed(state, buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), gzipMagic) {
+		t.Fatalf("state is not compressed: %q", buf.String())
+	}
+
+	actual, err := ReadState(buf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	state.TFVersion = VersionString()
+	if !reflect.DeepEqual(actual, state) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestReadState_binary(t *testing.T) {
+	state := &State{
+		Resources: map[string]*ResourceState{
+			"foo": &ResourceState{
+				ID: "bar",
+			},
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteString(stateFormatMagic)
+	buf.WriteByte(stateFormatVersion)
+	if err := gob.NewEncoder(buf).Encode(state); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual, err := ReadState(buf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
 	if !reflect.DeepEqual(actual, state) {
 		t.Fatalf("bad: %#v", actual)
 	}
 }
+
+func TestReadState_futureVersion(t *testing.T) {
+	buf := bytes.NewBufferString(`{"version": 99}`)
+	if _, err := ReadState(buf); err == nil {
+		t.Fatal("should error")
+	}
+}
+
+func TestReadState_terraformVersion(t *testing.T) {
+	buf := bytes.NewBufferString(
+		`{"version": 1, "terraform_version": "9.1.0", "serial": 2}`)
+	actual, err := ReadState(buf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if actual.TFVersion != "9.1.0" {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	// States from before versions were recorded have no version
+	buf = bytes.NewBufferString(`{"version": 1, "serial": 2}`)
+	actual, err = ReadState(buf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if actual.TFVersion != "" {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestReadState_invalid(t *testing.T) {
+	cases := []string{
+		``,
+		`{`,
+		`{"resources": {"aws_instance.foo": null}}`,
+		`{"resources": ["aws_instance.foo"]}`,
+	}
+
+	for _, tc := range cases {
+		if _, err := ReadState(bytes.NewBufferString(tc)); err == nil {
+			t.Fatalf("should error: %s", tc)
+		}
+	}
+}
+
+func TestWriteState_omitEmpty(t *testing.T) {
+	state := &State{
+		Resources: map[string]*ResourceState{
+			"foo": &ResourceState{
+				ID: "bar",
+			},
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	if err := WriteState(state, buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	for _, k := range []string{"outputs", "tainted", "attributes", "extra"} {
+		if strings.Contains(buf.String(), k) {
+			t.Fatalf("should not contain %q: %s", k, buf.String())
+		}
+	}
+}
+
+func TestStateInitLineage(t *testing.T) {
+	state := new(State)
+	if err := state.InitLineage(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	lineage := state.Lineage
+	if len(lineage) != 36 {
+		t.Fatalf("bad: %q", lineage)
+	}
+
+	// An existing lineage is kept
+	if err := state.InitLineage(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if state.Lineage != lineage {
+		t.Fatalf("bad: %q", state.Lineage)
+	}
+
+	other := new(State)
+	if err := other.InitLineage(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if other.Lineage == lineage {
+		t.Fatal("lineages should be unique")
+	}
+}
+
+func TestStateCompact(t *testing.T) {
+	state := &State{
+		Outputs: map[string]string{},
+		Resources: map[string]*ResourceState{
+			"foo": &ResourceState{
+				ID:           "bar",
+				Attributes:   map[string]string{},
+				Dependencies: []ResourceDependency{},
+			},
+			"bar": &ResourceState{},
+		},
+		Tainted: map[string]struct{}{
+			"foo": struct{}{},
+			"baz": struct{}{},
+		},
+		SensitiveOutputs: map[string]struct{}{
+			"password": struct{}{},
+		},
+	}
+	state.Compact()
+
+	expected := &State{
+		Resources: map[string]*ResourceState{
+			"foo": &ResourceState{
+				ID: "bar",
+			},
+		},
+		Tainted: map[string]struct{}{
+			"foo": struct{}{},
+		},
+	}
+	if !reflect.DeepEqual(state, expected) {
+		t.Fatalf("bad: %#v", state)
+	}
+}
+
+func TestStateCompact_missing(t *testing.T) {
+	state := &State{
+		Resources: map[string]*ResourceState{
+			"foo": &ResourceState{ID: "bar"},
+		},
+		Missing: map[string]string{
+			"foo": "baz",
+			"bar": "baz",
+		},
+	}
+	state.Compact()
+
+	expected := map[string]string{"bar": "baz"}
+	if !reflect.DeepEqual(state.Missing, expected) {
+		t.Fatalf("bad: %#v", state.Missing)
+	}
+
+	delete(state.Missing, "bar")
+	state.Compact()
+	if state.Missing != nil {
+		t.Fatalf("bad: %#v", state.Missing)
+	}
+}
+
+func TestStateMove(t *testing.T) {
+	cases := []struct {
+		From     string
+		To       string
+		Moved    map[string]string
+		Expected []string
+		Err      bool
+	}{
+		{
+			"aws_instance.foo",
+			"aws_instance.baz",
+			map[string]string{"aws_instance.foo": "aws_instance.baz"},
+			[]string{"aws_instance.bar.0", "aws_instance.bar.1", "aws_instance.baz"},
+			false,
+		},
+		{
+			"aws_instance.bar",
+			"aws_instance.baz",
+			map[string]string{
+				"aws_instance.bar.0": "aws_instance.baz.0",
+				"aws_instance.bar.1": "aws_instance.baz.1",
+			},
+			[]string{"aws_instance.baz.0", "aws_instance.baz.1", "aws_instance.foo"},
+			false,
+		},
+		{
+			"aws_instance.bar.1",
+			"aws_instance.foo.1",
+			map[string]string{"aws_instance.bar.1": "aws_instance.foo.1"},
+			[]string{"aws_instance.bar.0", "aws_instance.foo", "aws_instance.foo.1"},
+			false,
+		},
+
+		// Errors
+		{"aws_instance.foo", "aws_instance.bar.0", nil, nil, true},
+		{"aws_instance.bar", "aws_instance.baz.0", nil, nil, true},
+		{"aws_instance.nope", "aws_instance.baz", nil, nil, true},
+		{"aws_instance.foo", "aws_eip.foo", nil, nil, true},
+		{"aws_instance", "aws_instance.baz", nil, nil, true},
+		{"aws_instance.foo", "aws_instance.baz.x", nil, nil, true},
+	}
+
+	for i, tc := range cases {
+		state := &State{
+			Resources: map[string]*ResourceState{
+				"aws_instance.foo":   &ResourceState{ID: "1"},
+				"aws_instance.bar.0": &ResourceState{ID: "2"},
+				"aws_instance.bar.1": &ResourceState{ID: "3"},
+			},
+			Tainted: map[string]struct{}{
+				"aws_instance.foo": struct{}{},
+			},
+		}
+
+		moved, err := state.Move(tc.From, tc.To)
+		if (err != nil) != tc.Err {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+		if err != nil {
+			continue
+		}
+		if !reflect.DeepEqual(moved, tc.Moved) {
+			t.Fatalf("%d: bad: %#v", i, moved)
+		}
+
+		var actual []string
+		for k, _ := range state.Resources {
+			actual = append(actual, k)
+		}
+		sort.Strings(actual)
+		if !reflect.DeepEqual(actual, tc.Expected) {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+
+		// The taint marker moves with the resource
+		if to, ok := tc.Moved["aws_instance.foo"]; ok {
+			if _, ok := state.Tainted[to]; !ok {
+				t.Fatalf("%d: bad: %#v", i, state.Tainted)
+			}
+			if _, ok := state.Tainted["aws_instance.foo"]; ok {
+				t.Fatalf("%d: bad: %#v", i, state.Tainted)
+			}
+		}
+	}
+}
+
+func TestStateOrphans(t *testing.T) {
+	c := &config.Config{
+		Resources: []*config.Resource{
+			&config.Resource{Name: "foo", Type: "aws_instance", Count: 1},
+		},
+	}
+	s := &State{
+		Resources: map[string]*ResourceState{
+			"aws_instance.foo": &ResourceState{ID: "foo"},
+			"aws_instance.zed": &ResourceState{ID: "zed"},
+			"aws_instance.bar": &ResourceState{ID: "bar"},
+			"aws_instance.baz": &ResourceState{ID: "baz"},
+		},
+	}
+
+	expected := []string{
+		"aws_instance.bar",
+		"aws_instance.baz",
+		"aws_instance.zed",
+	}
+	if actual := s.Orphans(c); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
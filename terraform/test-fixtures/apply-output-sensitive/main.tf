@@ -0,0 +1,21 @@
+variable "password" {
+    sensitive = true
+}
+
+variable "user" {
+    default = "admin"
+}
+
+resource "aws_instance" "foo" {
+    num = "2"
+    foo = "${var.password}"
+}
+
+output "foo_num" {
+    value = "${aws_instance.foo.num}"
+    sensitive = true
+}
+
+output "user" {
+    value = "${var.user}"
+}
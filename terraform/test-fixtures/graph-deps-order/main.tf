@@ -0,0 +1,9 @@
+resource "aws_instance" "c" {}
+resource "aws_instance" "a" {}
+resource "aws_instance" "b" {}
+
+resource "aws_instance" "web" {
+    foo = "${aws_instance.c.id}"
+    bar = "${aws_instance.a.id}"
+    baz = "${aws_instance.b.id}"
+}
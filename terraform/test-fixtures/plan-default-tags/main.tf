@@ -0,0 +1,22 @@
+variable "env" {
+    default = "prod"
+}
+
+provider "aws" {
+    default_tags {
+        cost_center = "1234"
+        env = "${var.env}"
+    }
+}
+
+resource "aws_instance" "foo" {
+    num = "2"
+
+    tags {
+        env = "dev"
+    }
+}
+
+resource "aws_instance" "bar" {
+    foo = "2"
+}
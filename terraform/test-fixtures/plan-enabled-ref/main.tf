@@ -0,0 +1,8 @@
+resource "aws_instance" "foo" {
+    num = "2"
+    enabled = false
+}
+
+resource "aws_instance" "bar" {
+    foo = "${aws_instance.foo.num}"
+}
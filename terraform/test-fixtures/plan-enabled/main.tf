@@ -0,0 +1,12 @@
+variable "create_elb" {
+    default = "false"
+}
+
+resource "aws_instance" "foo" {
+    num = "2"
+    enabled = "${var.create_elb}"
+}
+
+resource "aws_instance" "bar" {
+    foo = "2"
+}
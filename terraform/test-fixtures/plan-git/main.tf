@@ -0,0 +1,3 @@
+resource "aws_instance" "foo" {
+    revision = "${git.branch}@${git.short_commit}"
+}
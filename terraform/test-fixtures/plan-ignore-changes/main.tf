@@ -0,0 +1,22 @@
+resource "aws_instance" "foo" {
+    num = "2"
+    name = "foo-${uuid()}"
+
+    lifecycle {
+        ignore_changes = ["name"]
+    }
+}
+
+resource "aws_instance" "bar" {
+    num = "2"
+    name = "bar-${uuid()}"
+}
+
+resource "aws_instance" "baz" {
+    num = "2"
+    name = "baz-${uuid()}"
+
+    lifecycle {
+        ignore_changes = ["name"]
+    }
+}
@@ -0,0 +1,7 @@
+provider "aws" {
+    token = "${secret.mock.token}"
+}
+
+resource "aws_instance" "foo" {
+    num = "2"
+}
@@ -0,0 +1,12 @@
+variable "access_key" {}
+variable "region" {}
+
+provider "aws" {
+    access_key = "${var.access_key}"
+    secret_key = "hunter2"
+    region = "${var.region}"
+}
+
+resource "aws_instance" "foo" {
+    num = "2"
+}
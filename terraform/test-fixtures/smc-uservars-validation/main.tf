@@ -0,0 +1,17 @@
+variable "environment" {
+    default = "dev"
+
+    validation {
+        values = ["dev", "stage", "prod"]
+        error_message = "environment must be one of dev/stage/prod"
+    }
+}
+
+variable "instances" {
+    default = "1"
+
+    validation {
+        min = 1
+        max = 10
+    }
+}
@@ -0,0 +1,5 @@
+provider "aws" {
+    token = "${secret.nope.token}"
+}
+
+resource "aws_instance" "foo" {}
@@ -0,0 +1,5 @@
+variable "unused" {
+    default = "foo"
+}
+
+resource "aws_instance" "foo" {}
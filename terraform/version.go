@@ -0,0 +1,177 @@
+package terraform
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/config"
+)
+
+// The main version number that is being run at the moment.
+const Version = "0.2.0"
+
+// A pre-release marker for the version. If this is "" (empty string)
+// then it means that it is a final release. Otherwise, this is a pre-release
+// such as "dev" (in development), "beta", "rc1", etc.
+const VersionPrerelease = ""
+
+// VersionString returns the version of Terraform with its pre-release
+// marker, as it is recorded in states and plans.
+func VersionString() string {
+	if VersionPrerelease != "" {
+		return fmt.Sprintf("%s.%s", Version, VersionPrerelease)
+	}
+
+	return Version
+}
+
+// CheckVersion returns an error if the given version of Terraform, which
+// wrote a state or plan, is newer than this version. Newer versions can
+// write what this version doesn't understand, which would be silently
+// dropped. States and plans that don't record a version were written
+// before versions were recorded, so they're always older.
+func CheckVersion(v string) error {
+	if v == "" || CompareVersions(v, Version) <= 0 {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"it was written by Terraform v%s, which is newer than this "+
+			"version (v%s)", v, VersionString())
+}
+
+// CheckRequiredVersion returns an error if this version of Terraform
+// doesn't meet the required_version constraint of the configuration, so
+// that configurations fail fast with versions they aren't tested with.
+func CheckRequiredVersion(c *config.Config) error {
+	if c == nil || c.Terraform == nil || c.Terraform.RequiredVersion == "" {
+		return nil
+	}
+
+	required := c.Terraform.RequiredVersion
+	ok, err := versionMeetsConstraint(Version, required)
+	if err != nil {
+		return fmt.Errorf("Invalid required_version %q: %s", required, err)
+	}
+	if !ok {
+		return fmt.Errorf(
+			"The configuration requires Terraform %s, but this is Terraform\n"+
+				"v%s. Use a version of Terraform that meets the required_version\n"+
+				"of the configuration.", required, VersionString())
+	}
+
+	return nil
+}
+
+// versionMeetsConstraint returns whether the version meets the constraint,
+// a comma-separated list of conditions that must all be met, such as
+// ">= 0.2.0, < 0.4.0". Each condition is an operator and a version. The
+// operators are "=" (the default), "!=", ">", ">=", "<", "<=" and "~>",
+// which allows only the last part of the version to increase, so that
+// "~> 0.2.1" is ">= 0.2.1, < 0.3.0".
+func versionMeetsConstraint(v, constraint string) (bool, error) {
+	for _, c := range strings.Split(constraint, ",") {
+		c = strings.TrimSpace(c)
+
+		op := ""
+		for _, prefix := range []string{"~>", ">=", "<=", "!=", "=", ">", "<"} {
+			if strings.HasPrefix(c, prefix) {
+				op = prefix
+				break
+			}
+		}
+
+		target := strings.TrimSpace(c[len(op):])
+		parts := versionParts(target)
+		if len(parts) == 0 {
+			return false, fmt.Errorf("invalid version: %q", target)
+		}
+
+		cmp := CompareVersions(v, target)
+		var ok bool
+		switch op {
+		case "", "=":
+			ok = cmp == 0
+		case "!=":
+			ok = cmp != 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		case "~>":
+			if len(parts) == 1 {
+				ok = cmp >= 0
+				break
+			}
+
+			// The version must be below the next release of the part
+			// before the last one
+			upper := parts[:len(parts)-1]
+			upper[len(upper)-1]++
+			ok = cmp >= 0 && CompareVersions(v, joinVersion(upper)) < 0
+		}
+
+		if !ok {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// joinVersion is the opposite of versionParts.
+func joinVersion(parts []int) string {
+	strs := make([]string, len(parts))
+	for i, p := range parts {
+		strs[i] = strconv.Itoa(p)
+	}
+
+	return strings.Join(strs, ".")
+}
+
+// CompareVersions compares the release numbers of two versions such as
+// "0.3.1", ignoring pre-release markers. It returns -1, 0 or 1 if a is
+// older than, the same as, or newer than b.
+func CompareVersions(a, b string) int {
+	as, bs := versionParts(a), versionParts(b)
+	for len(as) < len(bs) {
+		as = append(as, 0)
+	}
+	for len(bs) < len(as) {
+		bs = append(bs, 0)
+	}
+
+	for i := range as {
+		switch {
+		case as[i] < bs[i]:
+			return -1
+		case as[i] > bs[i]:
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// versionParts returns the numbers of a version, up to the first part
+// that isn't a number, such as a pre-release marker.
+func versionParts(v string) []int {
+	var result []int
+	for _, part := range strings.FieldsFunc(v, func(r rune) bool {
+		return r == '.' || r == '-'
+	}) {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			break
+		}
+
+		result = append(result, n)
+	}
+
+	return result
+}
@@ -0,0 +1,112 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+)
+
+func TestCheckVersion(t *testing.T) {
+	cases := []struct {
+		Version string
+		Err     bool
+	}{
+		{"", false},
+		{Version, false},
+		{VersionString(), false},
+		{"0.1.0", false},
+		{"0.1.9", false},
+		{"99.0.0", true},
+		{Version + ".1", true},
+	}
+
+	for _, tc := range cases {
+		err := CheckVersion(tc.Version)
+		if (err != nil) != tc.Err {
+			t.Fatalf("%s: bad: %s", tc.Version, err)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	cases := []struct {
+		A, B   string
+		Result int
+	}{
+		{"0.2.0", "0.2.0", 0},
+		{"0.2", "0.2.0", 0},
+		{"0.2.0.dev", "0.2.0", 0},
+		{"0.2.0-rc1", "0.2.0", 0},
+		{"0.2.0", "0.3.0", -1},
+		{"0.10.0", "0.9.1", 1},
+		{"1.0.0", "0.99.99", 1},
+	}
+
+	for _, tc := range cases {
+		if actual := CompareVersions(tc.A, tc.B); actual != tc.Result {
+			t.Fatalf("%s, %s: bad: %d", tc.A, tc.B, actual)
+		}
+	}
+}
+
+func TestCheckRequiredVersion(t *testing.T) {
+	cases := []struct {
+		Constraint string
+		Err        bool
+	}{
+		{"", false},
+		{">= " + Version, false},
+		{"> " + Version, true},
+		{"< 99.0.0", false},
+		{">= 0.1.0, < 99.0.0", false},
+		{">= 0.1.0, < 0.1.5", true},
+		{"nope", true},
+	}
+
+	for _, tc := range cases {
+		c := &config.Config{
+			Terraform: &config.Terraform{RequiredVersion: tc.Constraint},
+		}
+		err := CheckRequiredVersion(c)
+		if (err != nil) != tc.Err {
+			t.Fatalf("%q: bad: %s", tc.Constraint, err)
+		}
+	}
+
+	if err := CheckRequiredVersion(new(config.Config)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestVersionMeetsConstraint(t *testing.T) {
+	cases := []struct {
+		Version    string
+		Constraint string
+		Result     bool
+		Err        bool
+	}{
+		{"0.2.0", "0.2.0", true, false},
+		{"0.2.0", "= 0.2.1", false, false},
+		{"0.2.0", "!= 0.2.1", true, false},
+		{"0.2.0", ">0.1.9", true, false},
+		{"0.2.0", "<= 0.2", true, false},
+		{"0.2.5", "~> 0.2.1", true, false},
+		{"0.3.0", "~> 0.2.1", false, false},
+		{"0.2.0", "~> 0.2.1", false, false},
+		{"0.9.0", "~> 0.2", true, false},
+		{"1.0.0", "~> 0.2", false, false},
+		{"0.2.0", ">= 0.1, != 0.2.0", false, false},
+		{"0.2.0", ">= foo", false, true},
+		{"0.2.0", ">= 0.1,", false, true},
+	}
+
+	for _, tc := range cases {
+		actual, err := versionMeetsConstraint(tc.Version, tc.Constraint)
+		if (err != nil) != tc.Err {
+			t.Fatalf("%s, %q: err: %s", tc.Version, tc.Constraint, err)
+		}
+		if actual != tc.Result {
+			t.Fatalf("%s, %q: bad: %v", tc.Version, tc.Constraint, actual)
+		}
+	}
+}
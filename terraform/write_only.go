@@ -0,0 +1,138 @@
+package terraform
+
+import (
+	"strings"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/depgraph"
+)
+
+// graphWriteOnly returns the write-only keys of the configuration of each
+// provider in the graph, by the name of the provider configuration.
+func graphWriteOnly(g *depgraph.Graph) map[string][]string {
+	result := make(map[string][]string)
+	for _, n := range g.Nouns {
+		m, ok := n.Meta.(*GraphNodeResourceProvider)
+		if !ok || m.Config == nil {
+			continue
+		}
+
+		for _, p := range m.Providers {
+			result[m.Config.Name] = append(
+				result[m.Config.Name], p.WriteOnlyConfig()...)
+		}
+	}
+
+	return result
+}
+
+// writeOnlyConfig returns the configuration and variables to store in a
+// plan, with the write-only keys of the provider configurations removed.
+// writeOnly maps the names of provider configurations to their write-only
+// keys. The values of variables that are only used in the removed keys
+// are removed as well, so that credentials given as variables aren't
+// stored either.
+//
+// The given configuration and variables aren't modified.
+func writeOnlyConfig(
+	c *config.Config,
+	vars map[string]string,
+	writeOnly map[string][]string) (*config.Config, map[string]string, error) {
+	if c == nil || len(writeOnly) == 0 {
+		return c, vars, nil
+	}
+
+	result := *c
+	result.ProviderConfigs = make([]*config.ProviderConfig, len(c.ProviderConfigs))
+
+	// The variables that are used in the removed keys
+	removedVars := make(map[string]struct{})
+
+	for i, pc := range c.ProviderConfigs {
+		result.ProviderConfigs[i] = pc
+
+		keys := writeOnly[pc.Name]
+		if len(keys) == 0 || pc.RawConfig == nil {
+			continue
+		}
+
+		raw := make(map[string]interface{})
+		removed := make(map[string]interface{})
+		for k, v := range pc.RawConfig.Raw {
+			raw[k] = v
+		}
+		for _, k := range keys {
+			if v, ok := raw[k]; ok {
+				removed[k] = v
+				delete(raw, k)
+			}
+		}
+		if len(removed) == 0 {
+			continue
+		}
+
+		rc, err := config.NewRawConfig(raw)
+		if err != nil {
+			return nil, nil, err
+		}
+		result.ProviderConfigs[i] = &config.ProviderConfig{
+			Name:      pc.Name,
+			RawConfig: rc,
+		}
+
+		removedRC, err := config.NewRawConfig(removed)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, v := range removedRC.Variables {
+			if uv, ok := v.(*config.UserVariable); ok {
+				removedVars[uv.Name] = struct{}{}
+			}
+		}
+	}
+
+	if len(removedVars) == 0 {
+		return &result, vars, nil
+	}
+
+	// Variables that are still used elsewhere in the configuration
+	// have to be kept.
+	var raws []*config.RawConfig
+	for _, pc := range result.ProviderConfigs {
+		raws = append(raws, pc.RawConfig)
+	}
+	for _, r := range result.Resources {
+		raws = append(raws, r.RawConfig)
+		for _, p := range r.Provisioners {
+			raws = append(raws, p.RawConfig, p.ConnInfo)
+		}
+	}
+	for _, o := range result.Outputs {
+		raws = append(raws, o.RawConfig)
+	}
+	for _, raw := range raws {
+		if raw == nil {
+			continue
+		}
+
+		for _, v := range raw.Variables {
+			if uv, ok := v.(*config.UserVariable); ok {
+				delete(removedVars, uv.Name)
+			}
+		}
+	}
+
+	resultVars := make(map[string]string)
+	for k, v := range vars {
+		name := k
+		if idx := strings.Index(k, "."); idx >= 0 {
+			name = k[:idx]
+		}
+
+		if _, ok := removedVars[name]; !ok {
+			resultVars[k] = v
+		}
+	}
+
+	return &result, resultVars, nil
+}